@@ -0,0 +1,264 @@
+// Package bytesize provides ByteSize, a number of bytes parsed from, and
+// formatted to, human-readable strings, e.g.: "10GiB", or "512MB".
+package bytesize
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+//////
+// Const, vars, and types.
+//////
+
+// ByteSize is a number of bytes. It's an integer, so the usual arithmetic,
+// and comparison operators apply, e.g.: 2*bytesize.GiB < size. It's encoded
+// as a string, e.g.: "10GiB", in JSON, text, and flags.
+type ByteSize int64
+
+// Decimal units, powers of 1000.
+const (
+	B  ByteSize = 1
+	KB ByteSize = 1000 * B
+	MB ByteSize = 1000 * KB
+	GB ByteSize = 1000 * MB
+	TB ByteSize = 1000 * GB
+	PB ByteSize = 1000 * TB
+	EB ByteSize = 1000 * PB
+)
+
+// Binary units, powers of 1024.
+const (
+	KiB ByteSize = 1 << (10 * (iota + 1))
+	MiB
+	GiB
+	TiB
+	PiB
+	EiB
+)
+
+// unit is a named unit.
+type unit struct {
+	name string
+	size ByteSize
+}
+
+var (
+	// binaryUnits are the binary units, from the largest.
+	binaryUnits = []unit{{"EiB", EiB}, {"PiB", PiB}, {"TiB", TiB}, {"GiB", GiB}, {"MiB", MiB}, {"KiB", KiB}}
+
+	// decimalUnits are the decimal units, from the largest.
+	decimalUnits = []unit{{"EB", EB}, {"PB", PB}, {"TB", TB}, {"GB", GB}, {"MB", MB}, {"KB", KB}}
+
+	// units are the units accepted by Parse, lowercase. Single letters are
+	// binary, as in most command line tools.
+	units = map[string]ByteSize{
+		"": B, "b": B,
+		"k": KiB, "kb": KB, "kib": KiB,
+		"m": MiB, "mb": MB, "mib": MiB,
+		"g": GiB, "gb": GB, "gib": GiB,
+		"t": TiB, "tb": TB, "tib": TiB,
+		"p": PiB, "pb": PB, "pib": PiB,
+		"e": EiB, "eb": EB, "eib": EiB,
+	}
+)
+
+//////
+// Methods.
+//////
+
+// String is the stringer implementation, returning the exact size with the
+// largest unit dividing it, binary, or decimal, e.g.: "10GiB", "512MB", or
+// "100B", so it can be parsed back.
+func (b ByteSize) String() string {
+	best := unit{"B", B}
+
+	if b != 0 {
+		for _, units := range [][]unit{binaryUnits, decimalUnits} {
+			for _, u := range units {
+				if b%u.size == 0 && u.size > best.size {
+					best = u
+				}
+			}
+		}
+	}
+
+	return strconv.FormatInt(int64(b/best.size), 10) + best.name
+}
+
+// Human returns the size with the largest binary unit not greater than it,
+// rounded to at most two decimals, e.g.: "1.5 GiB", for display.
+func (b ByteSize) Human() string {
+	return human(b, binaryUnits)
+}
+
+// HumanSI is like Human, with decimal units, e.g.: "1.61 GB".
+func (b ByteSize) HumanSI() string {
+	return human(b, decimalUnits)
+}
+
+// Bytes returns the number of bytes.
+func (b ByteSize) Bytes() int64 {
+	return int64(b)
+}
+
+// In returns the size in the unit, e.g.: size.In(bytesize.MiB).
+func (b ByteSize) In(unit ByteSize) float64 {
+	return float64(b) / float64(unit)
+}
+
+// MarshalText implements the encoding.TextMarshaler interface, see String.
+func (b ByteSize) MarshalText() ([]byte, error) {
+	return []byte(b.String()), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface, see
+// Parse.
+func (b *ByteSize) UnmarshalText(text []byte) error {
+	parsed, err := Parse(string(text))
+	if err != nil {
+		return err
+	}
+
+	*b = parsed
+
+	return nil
+}
+
+// MarshalJSON implements the json.Marshaler interface, encoding the size as a
+// string, see String.
+func (b ByteSize) MarshalJSON() ([]byte, error) {
+	return json.Marshal(b.String())
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface. It accepts
+// strings, see Parse, and numbers, as bytes.
+func (b *ByteSize) UnmarshalJSON(data []byte) error {
+	if !bytes.HasPrefix(bytes.TrimSpace(data), []byte(`"`)) {
+		var n int64
+
+		if err := json.Unmarshal(data, &n); err != nil {
+			return err
+		}
+
+		*b = ByteSize(n)
+
+		return nil
+	}
+
+	var s string
+
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+
+	return b.UnmarshalText([]byte(s))
+}
+
+// Set implements flag.Value, see Parse.
+func (b *ByteSize) Set(s string) error {
+	return b.UnmarshalText([]byte(s))
+}
+
+// Value implements the driver.Valuer interface, storing the number of bytes.
+func (b ByteSize) Value() (driver.Value, error) {
+	return int64(b), nil
+}
+
+// Scan implements the sql.Scanner interface. It accepts integers, as bytes,
+// and strings, as []byte, or string, see Parse.
+func (b *ByteSize) Scan(src any) error {
+	switch v := src.(type) {
+	case int64:
+		*b = ByteSize(v)
+
+		return nil
+	case []byte:
+		return b.UnmarshalText(v)
+	case string:
+		return b.UnmarshalText([]byte(v))
+	default:
+		return fmt.Errorf("cannot scan %T, expected int64, []byte, or string", src)
+	}
+}
+
+//////
+// Helpers.
+//////
+
+// human formats the size with the largest of the units not greater than it.
+func human(b ByteSize, units []unit) string {
+	abs := b
+	if abs < 0 {
+		abs = -abs
+	}
+
+	for _, u := range units {
+		if abs >= u.size {
+			return strconv.FormatFloat(math.Round(b.In(u.size)*100)/100, 'f', -1, 64) + " " + u.name
+		}
+	}
+
+	return strconv.FormatInt(int64(b), 10) + " B"
+}
+
+//////
+// Factory.
+//////
+
+// Parse parses a size: a number, with an optional fraction, and an optional
+// unit, case insensitive, e.g.: "10GiB", "512 MB", "1.5g", or "100". Units
+// ending in "iB", and single letters, are binary, e.g.: "1k" is 1024 bytes,
+// others are decimal. Fractions of bytes are truncated.
+func Parse(s string) (ByteSize, error) {
+	s = strings.TrimSpace(s)
+
+	i := strings.IndexFunc(s, func(r rune) bool {
+		return (r < '0' || r > '9') && r != '.' && r != '-' && r != '+'
+	})
+	if i < 0 {
+		i = len(s)
+	}
+
+	number, name := s[:i], strings.ToLower(strings.TrimSpace(s[i:]))
+
+	size, ok := units[name]
+	if !ok || number == "" {
+		return 0, fmt.Errorf("invalid size %q", s)
+	}
+
+	if n, err := strconv.ParseInt(number, 10, 64); err == nil {
+		if n > math.MaxInt64/int64(size) || n < math.MinInt64/int64(size) {
+			return 0, fmt.Errorf("size %q overflows", s)
+		}
+
+		return ByteSize(n) * size, nil
+	}
+
+	f, err := strconv.ParseFloat(number, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid size %q", s)
+	}
+
+	total := f * float64(size)
+	if math.Abs(total) >= math.MaxInt64 {
+		return 0, fmt.Errorf("size %q overflows", s)
+	}
+
+	return ByteSize(total), nil
+}
+
+// MustParse is like Parse, panicking on error, e.g.: for defaults.
+func MustParse(s string) ByteSize {
+	b, err := Parse(s)
+	if err != nil {
+		panic(err)
+	}
+
+	return b
+}
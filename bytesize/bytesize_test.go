@@ -0,0 +1,128 @@
+package bytesize
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"flag"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/thalesfsp/go-common-types/safeorderedmap"
+)
+
+func TestParse(t *testing.T) {
+	tests := map[string]ByteSize{
+		"10GiB":   10 * GiB,
+		"512MB":   512 * MB,
+		"512 mb":  512 * MB,
+		"1.5g":    GiB + 512*MiB,
+		"1k":      1024,
+		"1KB":     1000,
+		"100":     100,
+		"100B":    100,
+		" 2 TiB ": 2 * TiB,
+		"0.5KiB":  512,
+		"-1MiB":   -MiB,
+	}
+
+	for s, want := range tests {
+		got, err := Parse(s)
+
+		assert.NoError(t, err, s)
+		assert.Equal(t, want, got, s)
+	}
+
+	for _, s := range []string{"", "GiB", "10XB", "1.2.3MB", "abc", "9EiB", "100000000000EB"} {
+		_, err := Parse(s)
+
+		assert.Error(t, err, s)
+	}
+}
+
+func TestFormat(t *testing.T) {
+	assert.Equal(t, "10GiB", (10 * GiB).String())
+	assert.Equal(t, "512MB", (512 * MB).String())
+	assert.Equal(t, "1001B", ByteSize(1001).String())
+	assert.Equal(t, "0B", ByteSize(0).String())
+	assert.Equal(t, "-1KiB", (-KiB).String())
+
+	assert.Equal(t, "1.5 GiB", (GiB + 512*MiB).Human())
+	assert.Equal(t, "1.61 GB", (GiB + 512*MiB).HumanSI())
+	assert.Equal(t, "999 B", ByteSize(999).HumanSI())
+	assert.Equal(t, "-2 KiB", (-2 * KiB).Human())
+
+	assert.Equal(t, 1.5, (GiB + 512*MiB).In(GiB))
+	assert.Equal(t, int64(1024), KiB.Bytes())
+
+	// Arithmetic, and comparison.
+	assert.Less(t, 2*GiB-MiB, 2*GiB)
+	assert.Equal(t, GiB, 1024*MiB)
+}
+
+func TestJSON(t *testing.T) {
+	type config struct {
+		Limit ByteSize `json:"limit"`
+	}
+
+	var c config
+
+	assert.NoError(t, json.Unmarshal([]byte(`{"limit":"10GiB"}`), &c))
+	assert.Equal(t, 10*GiB, c.Limit)
+
+	data, err := json.Marshal(c)
+
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"limit":"10GiB"}`, string(data))
+
+	assert.NoError(t, json.Unmarshal([]byte(`{"limit":2048}`), &c))
+	assert.Equal(t, 2*KiB, c.Limit)
+
+	assert.Error(t, json.Unmarshal([]byte(`{"limit":"big"}`), &c))
+	assert.Error(t, json.Unmarshal([]byte(`{"limit":true}`), &c))
+
+	// Settings stored in a collection.
+	limits := safeorderedmap.New[ByteSize]()
+
+	assert.NoError(t, limits.UnmarshalText([]byte("upload=10MiB,cache=1GB")))
+
+	upload, _ := limits.Get("upload")
+
+	assert.Equal(t, 10*MiB, upload)
+}
+
+func TestFlag(t *testing.T) {
+	var size ByteSize
+
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	fs.Var(&size, "size", "maximum size")
+
+	assert.NoError(t, fs.Parse([]string{"-size", "512MB"}))
+	assert.Equal(t, 512*MB, size)
+
+	assert.Error(t, fs.Parse([]string{"-size", "lots"}))
+}
+
+func TestSQL(t *testing.T) {
+	var (
+		_ driver.Valuer = ByteSize(0)
+		_ sql.Scanner   = new(ByteSize)
+	)
+
+	value, err := KiB.Value()
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1024), value)
+
+	var size ByteSize
+
+	assert.NoError(t, size.Scan(int64(10)))
+	assert.Equal(t, ByteSize(10), size)
+
+	assert.NoError(t, size.Scan("1MiB"))
+	assert.Equal(t, MiB, size)
+
+	assert.Error(t, size.Scan(1.5))
+}
@@ -0,0 +1,97 @@
+// Command soak load tests the collections of go-common-types, exercising each
+// one with concurrent goroutines running a mix of reads, writes, and deletes,
+// for a duration, and reporting the throughput, the latency percentiles, and
+// the allocations, e.g.: to catch performance regressions between releases.
+//
+// Usage:
+//
+//	go run ./cmd/soak -duration 30s -goroutines 16 -mix read=90,write=10 -collection safemap
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"runtime"
+	"time"
+
+	"github.com/thalesfsp/go-common-types/flagutil"
+	"github.com/thalesfsp/go-common-types/safeorderedmap"
+	"github.com/thalesfsp/go-common-types/safeset"
+)
+
+//////
+// Helpers.
+//////
+
+// soak parses the arguments, runs the collections, and writes the report.
+func soak(args []string, w io.Writer) error {
+	flags := flag.NewFlagSet("soak", flag.ContinueOnError)
+
+	collections := safeset.New[string]()
+	weights := safeorderedmap.New[int]()
+
+	cfg := config{}
+
+	flags.Var(flagutil.Set(collections), "collection", fmt.Sprintf("Collections to exercise, repeatable, all by default: %v", targets.Keys()))
+	flags.Var(flagutil.Map(weights), "mix", "Operation weights, e.g.: read=80,write=15,delete=5 (default)")
+	flags.IntVar(&cfg.goroutines, "goroutines", runtime.GOMAXPROCS(0), "Concurrent goroutines")
+	flags.DurationVar(&cfg.duration, "duration", 10*time.Second, "Duration, per collection")
+	flags.IntVar(&cfg.keys, "keys", 10_000, "Number of distinct keys")
+	flags.Int64Var(&cfg.seed, "seed", 1, "Seed of the random operations, and keys")
+
+	asJSON := flags.Bool("json", false, "Writes the report as JSON")
+
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if weights.Empty() {
+		weights.Add(opRead, 80).Add(opWrite, 15).Add(opDelete, 5)
+	}
+
+	m, err := parseMix(weights)
+	if err != nil {
+		return err
+	}
+
+	cfg.mix = m
+
+	if cfg.goroutines <= 0 || cfg.keys <= 0 || cfg.duration <= 0 {
+		return fmt.Errorf("goroutines, keys, and duration must be positive")
+	}
+
+	names := targets.Keys()
+
+	if !collections.Empty() {
+		names = collections.Values()
+	}
+
+	results := make([]result, 0, len(names))
+
+	for _, name := range names {
+		newT, ok := targets.Get(name)
+		if !ok {
+			return fmt.Errorf("unknown collection %q, expected one of: %v", name, targets.Keys())
+		}
+
+		results = append(results, run(name, newT, cfg))
+	}
+
+	if *asJSON {
+		return writeJSON(w, results)
+	}
+
+	return writeTable(w, results)
+}
+
+func main() {
+	if err := soak(os.Args[1:], os.Stdout); err != nil {
+		if err != flag.ErrHelp {
+			fmt.Fprintln(os.Stderr, err)
+		}
+
+		os.Exit(2)
+	}
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSoak(t *testing.T) {
+	var buf bytes.Buffer
+
+	err := soak([]string{"-duration", "10ms", "-goroutines", "2", "-collection", "safemap,safeset", "-json"}, &buf)
+	assert.NoError(t, err)
+
+	var results []result
+
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &results))
+	assert.Len(t, results, 2)
+	assert.Equal(t, "safemap", results[0].Collection)
+	assert.Equal(t, "safeset", results[1].Collection)
+
+	buf.Reset()
+
+	err = soak([]string{"-duration", "10ms", "-collection", "safelog", "-mix", "write=1"}, &buf)
+	assert.NoError(t, err)
+	assert.Contains(t, buf.String(), "safelog")
+	assert.True(t, strings.HasPrefix(strings.TrimSpace(buf.String()), "collection"))
+}
+
+func TestSoakInvalid(t *testing.T) {
+	var buf bytes.Buffer
+
+	assert.Error(t, soak([]string{"-collection", "nope"}, &buf))
+	assert.Error(t, soak([]string{"-mix", "scan=1"}, &buf))
+	assert.Error(t, soak([]string{"-keys", "0"}, &buf))
+	assert.Error(t, soak([]string{"-unknown"}, &buf))
+}
@@ -0,0 +1,35 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"text/tabwriter"
+)
+
+//////
+// Helpers.
+//////
+
+// writeTable writes the results as an aligned table, one row per collection.
+func writeTable(w io.Writer, results []result) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+
+	fmt.Fprintln(tw, "collection\tops\tops/s\tp50\tp99\tmax\tallocs/op\tB/op\tGCs\t")
+
+	for _, r := range results {
+		fmt.Fprintf(tw, "%s\t%d\t%.0f\t%s\t%s\t%s\t%.2f\t%.1f\t%d\t\n",
+			r.Collection, r.Ops, r.OpsPerSec, r.P50, r.P99, r.Max, r.AllocsPerOp, r.BytesPerOp, r.GCs)
+	}
+
+	return tw.Flush()
+}
+
+// writeJSON writes the results as JSON, e.g.: to compare releases.
+func writeJSON(w io.Writer, results []result) error {
+	enc := json.NewEncoder(w)
+
+	enc.SetIndent("", "  ")
+
+	return enc.Encode(results)
+}
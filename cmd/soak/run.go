@@ -0,0 +1,190 @@
+package main
+
+import (
+	"fmt"
+	"math/rand"
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/thalesfsp/go-common-types/safeorderedmap"
+	"github.com/thalesfsp/go-common-types/statistical"
+)
+
+//////
+// Const, vars, and types.
+//////
+
+// Operations of the mix.
+const (
+	opRead   = "read"
+	opWrite  = "write"
+	opDelete = "delete"
+)
+
+// config is the configuration of a run.
+type config struct {
+	goroutines int
+	duration   time.Duration
+	keys       int
+	mix        mix
+	seed       int64
+}
+
+// mix is the operation mix, as weights, e.g.: 80 reads, 15 writes, and 5
+// deletes.
+type mix struct {
+	read, write, delete int
+}
+
+// result is the outcome of a run, for a collection.
+type result struct {
+	Collection  string        `json:"collection"`
+	Ops         int           `json:"ops"`
+	OpsPerSec   float64       `json:"opsPerSec"`
+	P50         time.Duration `json:"p50"`
+	P99         time.Duration `json:"p99"`
+	Max         time.Duration `json:"max"`
+	AllocsPerOp float64       `json:"allocsPerOp"`
+	BytesPerOp  float64       `json:"bytesPerOp"`
+	GCs         uint32        `json:"gcs"`
+}
+
+//////
+// Methods.
+//////
+
+// total returns the sum of the weights.
+func (m mix) total() int {
+	return m.read + m.write + m.delete
+}
+
+// do runs the operation picked by n, in [0, total), on the key.
+func (m mix) do(t target, n, key int) {
+	switch {
+	case n < m.read:
+		t.read(key)
+	case n < m.read+m.write:
+		t.write(key)
+	default:
+		t.delete(key)
+	}
+}
+
+//////
+// Helpers.
+//////
+
+// parseMix returns the mix of the weights, by operation.
+func parseMix(weights *safeorderedmap.SafeOrderedMap[int]) (mix, error) {
+	m := mix{}
+
+	for _, entry := range weights.Entries() {
+		if entry.Value < 0 {
+			return mix{}, fmt.Errorf("negative weight for %q", entry.Key)
+		}
+
+		switch entry.Key {
+		case opRead:
+			m.read = entry.Value
+		case opWrite:
+			m.write = entry.Value
+		case opDelete:
+			m.delete = entry.Value
+		default:
+			return mix{}, fmt.Errorf("unknown operation %q, expected read, write, or delete", entry.Key)
+		}
+	}
+
+	if m.total() == 0 {
+		return mix{}, fmt.Errorf("the operation mix has no weight")
+	}
+
+	return m, nil
+}
+
+// run exercises the collection for the duration, with the goroutines, each
+// running operations picked from the mix, on random keys, measuring the
+// latency of each one. Half of the keys are written first. The allocations
+// include the ones of the harness, which are amortized to about zero.
+func run(name string, newT newTarget, cfg config) result {
+	t := newT(cfg.keys)
+
+	for key := 0; key < cfg.keys; key += 2 {
+		t.write(key)
+	}
+
+	digests := make([]*statistical.TDigest, cfg.goroutines)
+	counts := make([]int, cfg.goroutines)
+
+	var before, after runtime.MemStats
+
+	runtime.GC()
+	runtime.ReadMemStats(&before)
+
+	start := time.Now()
+	deadline := start.Add(cfg.duration)
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < cfg.goroutines; i++ {
+		wg.Add(1)
+
+		go func(i int) {
+			defer wg.Done()
+
+			rng := rand.New(rand.NewSource(cfg.seed + int64(i)))
+			digest := statistical.NewTDigest(statistical.DefaultCompression)
+			total := cfg.mix.total()
+
+			n := 0
+
+			for {
+				opStart := time.Now()
+				if opStart.After(deadline) {
+					break
+				}
+
+				cfg.mix.do(t, rng.Intn(total), rng.Intn(cfg.keys))
+
+				digest.Add(float64(time.Since(opStart)))
+
+				n++
+			}
+
+			digests[i], counts[i] = digest, n
+		}(i)
+	}
+
+	wg.Wait()
+
+	elapsed := time.Since(start)
+
+	runtime.ReadMemStats(&after)
+
+	latencies := statistical.NewTDigest(statistical.DefaultCompression)
+	ops := 0
+
+	for i, digest := range digests {
+		latencies.Merge(digest)
+
+		ops += counts[i]
+	}
+
+	r := result{
+		Collection: name,
+		Ops:        ops,
+		OpsPerSec:  float64(ops) / elapsed.Seconds(),
+		GCs:        after.NumGC - before.NumGC,
+	}
+
+	if ops > 0 {
+		r.P50 = time.Duration(latencies.Quantile(0.5))
+		r.P99 = time.Duration(latencies.Quantile(0.99))
+		r.Max = time.Duration(latencies.Max())
+		r.AllocsPerOp = float64(after.Mallocs-before.Mallocs) / float64(ops)
+		r.BytesPerOp = float64(after.TotalAlloc-before.TotalAlloc) / float64(ops)
+	}
+
+	return r
+}
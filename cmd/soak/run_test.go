@@ -0,0 +1,47 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/thalesfsp/go-common-types/safeorderedmap"
+)
+
+func TestParseMix(t *testing.T) {
+	m, err := parseMix(safeorderedmap.New[int]().Add("read", 8).Add("write", 2))
+	assert.NoError(t, err)
+	assert.Equal(t, mix{read: 8, write: 2}, m)
+	assert.Equal(t, 10, m.total())
+
+	_, err = parseMix(safeorderedmap.New[int]().Add("scan", 1))
+	assert.Error(t, err)
+
+	_, err = parseMix(safeorderedmap.New[int]().Add("read", -1))
+	assert.Error(t, err)
+
+	_, err = parseMix(safeorderedmap.New[int]().Add("read", 0))
+	assert.Error(t, err)
+}
+
+func TestRun(t *testing.T) {
+	cfg := config{
+		goroutines: 2,
+		duration:   10 * time.Millisecond,
+		keys:       100,
+		mix:        mix{read: 1, write: 1, delete: 1},
+		seed:       1,
+	}
+
+	for _, name := range targets.Keys() {
+		newT, _ := targets.Get(name)
+
+		r := run(name, newT, cfg)
+
+		assert.Equal(t, name, r.Collection)
+		assert.Greater(t, r.Ops, 0, name)
+		assert.Greater(t, r.OpsPerSec, 0.0, name)
+		assert.LessOrEqual(t, r.P50, r.P99, name)
+		assert.LessOrEqual(t, r.P99, r.Max, name)
+	}
+}
@@ -0,0 +1,150 @@
+package main
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/thalesfsp/go-common-types/crdt"
+	"github.com/thalesfsp/go-common-types/safebloom"
+	"github.com/thalesfsp/go-common-types/safeexpiringset"
+	"github.com/thalesfsp/go-common-types/safelog"
+	"github.com/thalesfsp/go-common-types/safemap"
+	"github.com/thalesfsp/go-common-types/safeorderedmap"
+	"github.com/thalesfsp/go-common-types/safeset"
+	"github.com/thalesfsp/go-common-types/safeslice"
+)
+
+//////
+// Const, vars, and types.
+//////
+
+// target is a collection under load. Keys are in [0, keys), see newTarget.
+type target interface {
+	read(key int)
+	write(key int)
+	delete(key int)
+}
+
+// newTarget creates a collection, for the number of keys.
+type newTarget func(keys int) target
+
+// targets are the collections which can be exercised, by name, in order.
+var targets = safeorderedmap.New[newTarget]().
+	Add("safemap", func(keys int) target { return &mapTarget{m: safemap.New[int, int]()} }).
+	Add("safeorderedmap", func(keys int) target { return &orderedMapTarget{m: safeorderedmap.New[int](), names: names(keys)} }).
+	Add("safeset", func(keys int) target { return &setTarget{s: safeset.New[int]()} }).
+	Add("safeslice", func(keys int) target { return &sliceTarget{s: safeslice.New[int](), keys: keys} }).
+	Add("safeexpiringset", func(keys int) target { return &expiringSetTarget{s: safeexpiringset.New[int](time.Minute)} }).
+	Add("safebloom", func(keys int) target { return &bloomTarget{b: safebloom.New[int](uint(keys), 0.01)} }).
+	Add("safelog", func(keys int) target { return &logTarget{l: safelog.New[int](), keys: keys} }).
+	Add("lwwmap", func(keys int) target { return &lwwMapTarget{m: crdt.NewLWWMap[int]("soak"), names: names(keys)} }).
+	Add("orset", func(keys int) target { return &orSetTarget{s: crdt.NewORSet[int]("soak")} })
+
+// mapTarget exercises a safemap.Map.
+type mapTarget struct{ m *safemap.Map[int, int] }
+
+// orderedMapTarget exercises a SafeOrderedMap.
+type orderedMapTarget struct {
+	m     *safeorderedmap.SafeOrderedMap[int]
+	names []string
+}
+
+// setTarget exercises a SafeSet.
+type setTarget struct{ s *safeset.SafeSet[int] }
+
+// sliceTarget exercises a SafeSlice, by index, growing up to keys elements.
+type sliceTarget struct {
+	s    *safeslice.SafeSlice[int]
+	keys int
+}
+
+// expiringSetTarget exercises a SafeExpiringSet.
+type expiringSetTarget struct {
+	s *safeexpiringset.SafeExpiringSet[int]
+}
+
+// bloomTarget exercises a SafeBloom, which can't delete.
+type bloomTarget struct{ b *safebloom.SafeBloom[int] }
+
+// logTarget exercises a SafeLog, by offset, retaining about keys values.
+type logTarget struct {
+	l    *safelog.SafeLog[int]
+	keys int
+}
+
+// lwwMapTarget exercises a crdt.LWWMap.
+type lwwMapTarget struct {
+	m     *crdt.LWWMap[int]
+	names []string
+}
+
+// orSetTarget exercises a crdt.ORSet.
+type orSetTarget struct{ s *crdt.ORSet[int] }
+
+//////
+// Methods.
+//////
+
+func (t *mapTarget) read(key int)   { t.m.Get(key) }
+func (t *mapTarget) write(key int)  { t.m.Set(key, key) }
+func (t *mapTarget) delete(key int) { t.m.Delete(key) }
+
+func (t *orderedMapTarget) read(key int)   { t.m.Get(t.names[key]) }
+func (t *orderedMapTarget) write(key int)  { t.m.Set(t.names[key], key) }
+func (t *orderedMapTarget) delete(key int) { t.m.Delete(t.names[key]) }
+
+func (t *setTarget) read(key int)   { t.s.Contains(key) }
+func (t *setTarget) write(key int)  { t.s.Add(key) }
+func (t *setTarget) delete(key int) { t.s.Remove(key) }
+
+func (t *sliceTarget) read(key int) { t.s.Get(key) }
+
+func (t *sliceTarget) write(key int) {
+	if t.s.Size() < t.keys {
+		t.s.Add(key)
+	}
+}
+
+func (t *sliceTarget) delete(key int) { t.s.Delete(key) }
+
+func (t *expiringSetTarget) read(key int)   { t.s.Contains(key) }
+func (t *expiringSetTarget) write(key int)  { t.s.Add(key) }
+func (t *expiringSetTarget) delete(key int) { t.s.Remove(key) }
+
+func (t *bloomTarget) read(key int)   { t.b.MayContain(key) }
+func (t *bloomTarget) write(key int)  { t.b.Add(key) }
+func (t *bloomTarget) delete(key int) { t.b.MayContain(key) }
+
+func (t *logTarget) read(key int) { t.l.Get(t.l.First() + uint64(key)) }
+
+func (t *logTarget) write(key int) { t.l.Append(key) }
+
+func (t *logTarget) delete(key int) {
+	if next := t.l.Next(); next > uint64(t.keys) {
+		t.l.TruncateBefore(next - uint64(t.keys))
+	}
+}
+
+func (t *lwwMapTarget) read(key int)   { t.m.Get(t.names[key]) }
+func (t *lwwMapTarget) write(key int)  { t.m.Set(t.names[key], key) }
+func (t *lwwMapTarget) delete(key int) { t.m.Delete(t.names[key]) }
+
+func (t *orSetTarget) read(key int)   { t.s.Contains(key) }
+func (t *orSetTarget) write(key int)  { t.s.Add(key) }
+func (t *orSetTarget) delete(key int) { t.s.Remove(key) }
+
+//////
+// Helpers.
+//////
+
+// names returns the string keys, formatted beforehand, so formatting doesn't
+// count in the allocations.
+func names(keys int) []string {
+	names := make([]string, keys)
+
+	for i := range names {
+		names[i] = strconv.Itoa(i)
+	}
+
+	return names
+}
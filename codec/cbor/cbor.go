@@ -0,0 +1,282 @@
+package cbor
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	fxcbor "github.com/fxamacker/cbor/v2"
+	"github.com/thalesfsp/go-common-types/shared"
+)
+
+//////
+// Const, vars, and types.
+//////
+
+// CBOR major types, see RFC 8949.
+const (
+	majorArray  byte = 4
+	majorMap    byte = 5
+	majorSimple byte = 7
+
+	additionalIndefinite byte = 31
+
+	breakByte byte = 0xff
+	nullByte  byte = 0xf6
+)
+
+// ErrUnexpectedEOF is returned when the data ends in the middle of an item.
+var ErrUnexpectedEOF = errors.New("cbor: unexpected end of data")
+
+// Encodable is implemented by all collections.
+type Encodable interface {
+	EncodeWith(enc shared.Encoder) error
+}
+
+// Decodable is implemented by all collections.
+type Decodable interface {
+	DecodeWith(dec shared.Decoder) error
+}
+
+// encoder is a shared.Encoder writing CBOR. Headers are written directly,
+// values are encoded with fxamacker/cbor.
+type encoder struct {
+	buf bytes.Buffer
+}
+
+// decoder is a shared.Decoder reading CBOR. Headers are read directly, values
+// are delimited, and decoded with fxamacker/cbor.
+type decoder struct {
+	data []byte
+	pos  int
+}
+
+//////
+// Encoder.
+//////
+
+// writeHeader writes the head of an item with the given major type and
+// argument.
+func (e *encoder) writeHeader(major byte, n uint64) {
+	switch {
+	case n < 24:
+		e.buf.WriteByte(major<<5 | byte(n))
+	case n <= 0xff:
+		e.buf.WriteByte(major<<5 | 24)
+		e.buf.WriteByte(byte(n))
+	case n <= 0xffff:
+		e.buf.WriteByte(major<<5 | 25)
+		e.buf.Write(binary.BigEndian.AppendUint16(nil, uint16(n)))
+	case n <= 0xffffffff:
+		e.buf.WriteByte(major<<5 | 26)
+		e.buf.Write(binary.BigEndian.AppendUint32(nil, uint32(n)))
+	default:
+		e.buf.WriteByte(major<<5 | 27)
+		e.buf.Write(binary.BigEndian.AppendUint64(nil, n))
+	}
+}
+
+// EncodeArrayLen implements shared.Encoder.
+func (e *encoder) EncodeArrayLen(n int) error {
+	e.writeHeader(majorArray, uint64(n))
+
+	return nil
+}
+
+// EncodeMapLen implements shared.Encoder.
+func (e *encoder) EncodeMapLen(n int) error {
+	e.writeHeader(majorMap, uint64(n))
+
+	return nil
+}
+
+// EncodeString implements shared.Encoder.
+func (e *encoder) EncodeString(s string) error {
+	return e.Encode(s)
+}
+
+// Encode implements shared.Encoder.
+func (e *encoder) Encode(v any) error {
+	b, err := fxcbor.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	e.buf.Write(b)
+
+	return nil
+}
+
+//////
+// Decoder.
+//////
+
+// readHeader reads the head of the item at pos, returning its major type,
+// additional information, argument, and the position right after the head.
+func (d *decoder) readHeader(pos int) (byte, byte, uint64, int, error) {
+	if pos >= len(d.data) {
+		return 0, 0, 0, 0, ErrUnexpectedEOF
+	}
+
+	major, additional := d.data[pos]>>5, d.data[pos]&0x1f
+	pos++
+
+	size := 0
+
+	switch {
+	case additional < 24, additional == additionalIndefinite:
+		return major, additional, uint64(additional), pos, nil
+	case additional == 24:
+		size = 1
+	case additional == 25:
+		size = 2
+	case additional == 26:
+		size = 4
+	case additional == 27:
+		size = 8
+	default:
+		return 0, 0, 0, 0, fmt.Errorf("cbor: invalid additional information %d", additional)
+	}
+
+	if pos+size > len(d.data) {
+		return 0, 0, 0, 0, ErrUnexpectedEOF
+	}
+
+	var n uint64
+
+	for _, b := range d.data[pos : pos+size] {
+		n = n<<8 | uint64(b)
+	}
+
+	return major, additional, n, pos + size, nil
+}
+
+// skip returns the position right after the item at pos.
+//
+//nolint:cyclop
+func (d *decoder) skip(pos int) (int, error) {
+	major, additional, n, pos, err := d.readHeader(pos)
+	if err != nil {
+		return 0, err
+	}
+
+	if additional == additionalIndefinite && major != majorSimple {
+		for {
+			if pos >= len(d.data) {
+				return 0, ErrUnexpectedEOF
+			}
+
+			if d.data[pos] == breakByte {
+				return pos + 1, nil
+			}
+
+			if pos, err = d.skip(pos); err != nil {
+				return 0, err
+			}
+		}
+	}
+
+	items := uint64(0)
+
+	switch major {
+	case 2, 3:
+		if uint64(len(d.data)-pos) < n {
+			return 0, ErrUnexpectedEOF
+		}
+
+		return pos + int(n), nil
+	case majorArray:
+		items = n
+	case majorMap:
+		items = 2 * n
+	case 6:
+		items = 1
+	}
+
+	for i := uint64(0); i < items; i++ {
+		if pos, err = d.skip(pos); err != nil {
+			return 0, err
+		}
+	}
+
+	return pos, nil
+}
+
+// decodeLen reads the head of an array or map, returning -1 for null.
+func (d *decoder) decodeLen(expected byte) (int, error) {
+	if d.pos < len(d.data) && d.data[d.pos] == nullByte {
+		d.pos++
+
+		return -1, nil
+	}
+
+	major, additional, n, pos, err := d.readHeader(d.pos)
+	if err != nil {
+		return 0, err
+	}
+
+	if major != expected || additional == additionalIndefinite {
+		return 0, fmt.Errorf("cbor: unexpected major type %d, expected definite length %d", major, expected)
+	}
+
+	d.pos = pos
+
+	return int(n), nil
+}
+
+// DecodeArrayLen implements shared.Decoder.
+func (d *decoder) DecodeArrayLen() (int, error) {
+	return d.decodeLen(majorArray)
+}
+
+// DecodeMapLen implements shared.Decoder.
+func (d *decoder) DecodeMapLen() (int, error) {
+	return d.decodeLen(majorMap)
+}
+
+// DecodeString implements shared.Decoder.
+func (d *decoder) DecodeString() (string, error) {
+	var s string
+
+	err := d.Decode(&s)
+
+	return s, err
+}
+
+// Decode implements shared.Decoder.
+func (d *decoder) Decode(v any) error {
+	end, err := d.skip(d.pos)
+	if err != nil {
+		return err
+	}
+
+	if err := fxcbor.Unmarshal(d.data[d.pos:end], v); err != nil {
+		return err
+	}
+
+	d.pos = end
+
+	return nil
+}
+
+//////
+// Exported functionalities.
+//////
+
+// Marshal encodes the collection as CBOR. SafeOrderedMap preserves the
+// insertion order of the keys.
+func Marshal(v Encodable) ([]byte, error) {
+	e := &encoder{}
+
+	if err := v.EncodeWith(e); err != nil {
+		return nil, err
+	}
+
+	return e.buf.Bytes(), nil
+}
+
+// Unmarshal decodes CBOR data into the collection, replacing its content.
+func Unmarshal(data []byte, v Decodable) error {
+	return v.DecodeWith(&decoder{data: data})
+}
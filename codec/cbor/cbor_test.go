@@ -0,0 +1,62 @@
+package cbor
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/thalesfsp/go-common-types/safeorderedmap"
+	"github.com/thalesfsp/go-common-types/safeset"
+	"github.com/thalesfsp/go-common-types/safeslice"
+)
+
+func TestSafeOrderedMap(t *testing.T) {
+	m := safeorderedmap.New[int]()
+
+	m.Add("z", 1).Add("a", 2).Add("m", 3)
+
+	data, err := Marshal(m)
+
+	assert.NoError(t, err)
+
+	decoded := safeorderedmap.New[int]()
+
+	decoded.Add("stale", 0)
+
+	assert.NoError(t, Unmarshal(data, decoded))
+	assert.Equal(t, []string{"z", "a", "m"}, decoded.Keys())
+	assert.Equal(t, []int{1, 2, 3}, decoded.Values())
+
+	data, err = Marshal(safeorderedmap.New[int]())
+
+	assert.NoError(t, err)
+	assert.NoError(t, Unmarshal(data, decoded))
+	assert.Equal(t, 0, decoded.Size())
+
+	assert.Error(t, Unmarshal([]byte{0x81}, decoded))
+}
+
+func TestSafeSlice(t *testing.T) {
+	s := safeslice.New(3, 1, 2)
+
+	data, err := Marshal(s)
+
+	assert.NoError(t, err)
+
+	decoded := safeslice.New[int]()
+
+	assert.NoError(t, Unmarshal(data, decoded))
+	assert.Equal(t, []int{3, 1, 2}, decoded.Values())
+}
+
+func TestSafeSet(t *testing.T) {
+	s := safeset.New("a", "b", "c")
+
+	data, err := Marshal(s)
+
+	assert.NoError(t, err)
+
+	decoded := safeset.New[string]()
+
+	assert.NoError(t, Unmarshal(data, decoded))
+	assert.ElementsMatch(t, []string{"a", "b", "c"}, decoded.Values())
+}
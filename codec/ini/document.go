@@ -0,0 +1,259 @@
+package ini
+
+import (
+	"bytes"
+
+	"github.com/thalesfsp/go-common-types/safeorderedmap"
+)
+
+//////
+// Const, vars, and types.
+//////
+
+// Document is an INI file: its File, and, if decoded, the source of its lines,
+// so it's written back as it was: with its comments, blank lines, repeated
+// sections, and values as written, e.g.: quoted. Editing the File only
+// changes the lines of the edited keys:
+//
+//   - Changed values are rewritten in place, keeping the key, and the
+//     separator. For repeated keys, the last one, which holds the value, is
+//     rewritten.
+//   - Removed keys, and sections, are dropped, with the comments preceding
+//     them.
+//   - New keys are appended to the last occurrence of their section, and new
+//     sections are appended to the file.
+//
+// Keys, and sections keep their original order.
+type Document struct {
+	*File
+
+	// blocks are the lines of the file, grouped by section header, the first
+	// one being the global section. Nil for a new document.
+	blocks []*block
+
+	// trailing are the lines after the last statement, e.g.: comments.
+	trailing string
+}
+
+// block is a section header, and the entries following it.
+type block struct {
+	// leading are the lines preceding the header, e.g.: comments.
+	leading string
+
+	// header is the line of the header, empty for the global section.
+	header string
+
+	name    string
+	section *Section
+	items   []*item
+}
+
+// item is a key = value line.
+type item struct {
+	// leading are the lines preceding the entry.
+	leading string
+
+	key   string
+	value string
+
+	// prefix is the source of the line up to the value, e.g.: `key = `, raw
+	// the value, as written, e.g.: quoted, and suffix the rest of the line.
+	prefix string
+	raw    string
+	suffix string
+}
+
+//////
+// Methods.
+//////
+
+// encode writes the document to buf, as it was decoded, or as a new one.
+func (d *Document) encode(buf *bytes.Buffer) error {
+	if d.blocks == nil {
+		return encodeFile(buf, d.File)
+	}
+
+	// last is the index of the last item of each key, per section, which
+	// holds its value, and of the last block of each section, which gets new
+	// keys.
+	last := map[*Section]map[string]*item{}
+	lastBlock := map[*Section]*block{}
+
+	for _, b := range d.blocks {
+		section := d.section(b)
+		if section == nil {
+			continue
+		}
+
+		if last[section] == nil {
+			last[section] = map[string]*item{}
+		}
+
+		for _, it := range b.items {
+			last[section][it.key] = it
+		}
+
+		lastBlock[section] = b
+	}
+
+	written := map[*Section]bool{}
+
+	for _, b := range d.blocks {
+		section := d.section(b)
+		if section == nil && b.header != "" {
+			continue
+		}
+
+		buf.WriteString(b.leading)
+		buf.WriteString(b.header)
+
+		if section == nil {
+			continue
+		}
+
+		written[section] = true
+
+		for _, it := range b.items {
+			value, ok := section.Get(it.key)
+			if !ok {
+				continue
+			}
+
+			buf.WriteString(it.leading)
+			buf.WriteString(it.prefix)
+
+			switch {
+			case last[section][it.key] != it, value == it.value:
+				buf.WriteString(it.raw)
+			default:
+				if err := check(it.key, value); err != nil {
+					return err
+				}
+
+				if it.raw == "" {
+					buf.WriteByte(' ')
+				}
+
+				buf.WriteString(quote(value))
+			}
+
+			buf.WriteString(it.suffix)
+		}
+
+		if lastBlock[section] != b {
+			continue
+		}
+
+		for _, kv := range section.Entries() {
+			if last[section][kv.Key] != nil {
+				continue
+			}
+
+			line, err := entry(kv.Key, kv.Value)
+			if err != nil {
+				return err
+			}
+
+			newline(buf)
+
+			buf.WriteString(line)
+		}
+	}
+
+	buf.WriteString(d.trailing)
+
+	// New sections.
+	for _, s := range d.Entries() {
+		if written[s.Value] {
+			continue
+		}
+
+		newline(buf)
+
+		if buf.Len() > 0 {
+			buf.WriteByte('\n')
+		}
+
+		if err := encodeSection(buf, s.Key, s.Value); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// section returns the section of the block, nil if it was removed. The global
+// section is looked up by name, as it may have been added.
+func (d *Document) section(b *block) *Section {
+	section, ok := d.Get(b.name)
+	if !ok || (b.header != "" && section != b.section) {
+		return nil
+	}
+
+	return section
+}
+
+//////
+// Helpers.
+//////
+
+// newline ends the last line, if not ended, e.g.: at the end of a file without
+// a final newline.
+func newline(buf *bytes.Buffer) {
+	if b := buf.Bytes(); len(b) > 0 && b[len(b)-1] != '\n' {
+		buf.WriteByte('\n')
+	}
+}
+
+// encodeSection writes the header, unless global, and the entries of the
+// section.
+func encodeSection(buf *bytes.Buffer, name string, section *Section) error {
+	if name != "" {
+		line, err := header(name)
+		if err != nil {
+			return err
+		}
+
+		buf.WriteString(line)
+	}
+
+	for _, kv := range section.Entries() {
+		line, err := entry(kv.Key, kv.Value)
+		if err != nil {
+			return err
+		}
+
+		buf.WriteString(line)
+	}
+
+	return nil
+}
+
+// encodeFile writes the file, keeping the order of the sections, and keys.
+func encodeFile(buf *bytes.Buffer, file *File) error {
+	for i, s := range file.Entries() {
+		if s.Key != "" && i > 0 {
+			buf.WriteByte('\n')
+		}
+
+		if err := encodeSection(buf, s.Key, s.Value); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+//////
+// Factory.
+//////
+
+// NewDocument creates a document of the file, written as a new one, e.g.: to
+// encode a file built in code. If file is nil, an empty one is created.
+func NewDocument(file *File) *Document {
+	if file == nil {
+		file = safeorderedmap.New[*Section]()
+	}
+
+	return &Document{File: file}
+}
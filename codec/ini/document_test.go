@@ -0,0 +1,175 @@
+package ini
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/thalesfsp/go-common-types/safeorderedmap"
+)
+
+// load decodes the document of testdata, returning it, and its source.
+func load(t *testing.T, name string) (*Document, string) {
+	t.Helper()
+
+	data, err := os.ReadFile(filepath.Join("testdata", name))
+	assert.NoError(t, err)
+
+	doc, err := Unmarshal(data)
+	assert.NoError(t, err)
+
+	return doc, string(data)
+}
+
+// marshal encodes the document.
+func marshal(t *testing.T, doc *Document) string {
+	t.Helper()
+
+	data, err := Marshal(doc)
+	assert.NoError(t, err)
+
+	return string(data)
+}
+
+// section returns the section of the document.
+func section(t *testing.T, doc *Document, name string) *Section {
+	t.Helper()
+
+	s, ok := doc.Get(name)
+	assert.True(t, ok, name)
+
+	return s
+}
+
+func TestGolden(t *testing.T) {
+	files, err := filepath.Glob(filepath.Join("testdata", "*.ini"))
+	assert.NoError(t, err)
+	assert.NotEmpty(t, files)
+
+	for _, file := range files {
+		t.Run(filepath.Base(file), func(t *testing.T) {
+			doc, source := load(t, filepath.Base(file))
+
+			assert.Equal(t, source, marshal(t, doc))
+		})
+	}
+}
+
+func TestDocumentValues(t *testing.T) {
+	doc, _ := load(t, "config.ini")
+
+	assert.Equal(t, []string{"", "server", "database"}, doc.Keys())
+
+	server := section(t, doc, "server")
+	assert.Equal(t, []string{"port", "host", "timeout"}, server.Keys())
+	assert.Equal(t, []string{"8081", "0.0.0.0", "30s"}, server.Values())
+
+	global := section(t, doc, "")
+	assert.Equal(t, []string{"app", "true"}, global.Values())
+}
+
+func TestDocumentEdit(t *testing.T) {
+	for name, tc := range map[string]struct {
+		edit     func(t *testing.T, doc *Document)
+		old, new string
+	}{
+		"value": {
+			edit: func(t *testing.T, doc *Document) {
+				section(t, doc, "").Set("name", "api")
+			},
+			old: "name = app\n",
+			new: "name = api\n",
+		},
+		"quoted value": {
+			edit: func(t *testing.T, doc *Document) {
+				section(t, doc, "database").Set("url", " sqlite://")
+			},
+			old: `url = "  postgres://db ; primary"`,
+			new: `url = " sqlite://"`,
+		},
+		"empty value": {
+			edit: func(t *testing.T, doc *Document) {
+				section(t, doc, "database").Set("empty", "full")
+			},
+			old: "empty =\n",
+			new: "empty = full\n",
+		},
+		"repeated key": {
+			edit: func(t *testing.T, doc *Document) {
+				section(t, doc, "server").Set("port", "9090")
+			},
+			old: "port = 8081\n",
+			new: "port = 9090\n",
+		},
+		"new key": {
+			edit: func(t *testing.T, doc *Document) {
+				section(t, doc, "server").Set("tls", "on")
+			},
+			old: "timeout = 30s\n",
+			new: "timeout = 30s\ntls = on\n",
+		},
+		"removed key": {
+			edit: func(t *testing.T, doc *Document) {
+				section(t, doc, "database").Delete("url")
+			},
+			old: "; Quoted to keep the spaces.\nurl = \"  postgres://db ; primary\"\n",
+			new: "",
+		},
+		"removed section": {
+			edit: func(t *testing.T, doc *Document) {
+				doc.Delete("database")
+			},
+			old: "\n[database]\n; Quoted to keep the spaces.\nurl = \"  postgres://db ; primary\"\nempty =\n",
+			new: "",
+		},
+		"new section": {
+			edit: func(t *testing.T, doc *Document) {
+				doc.Set("cache", safeorderedmap.New[string]().Add("size", "10"))
+			},
+			old: "# Trailing comment of the file.\n",
+			new: "# Trailing comment of the file.\n\n[cache]\nsize = 10\n",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			doc, source := load(t, "config.ini")
+
+			assert.Contains(t, source, tc.old)
+
+			tc.edit(t, doc)
+
+			data := marshal(t, doc)
+
+			assert.Equal(t, strings.Replace(source, tc.old, tc.new, 1), data)
+
+			// The result is valid, with the edit.
+			decoded, err := Unmarshal([]byte(data))
+			assert.NoError(t, err)
+			assert.Equal(t, marshal(t, NewDocument(doc.File)), marshal(t, NewDocument(decoded.File)))
+		})
+	}
+}
+
+func TestDocumentNoNewline(t *testing.T) {
+	doc, source := load(t, "nonewline.ini")
+
+	section(t, doc, "a").Set("y", "2")
+	doc.Set("b", safeorderedmap.New[string]().Add("z", "3"))
+
+	assert.Equal(t, source+"\ny = 2\n\n[b]\nz = 3\n", marshal(t, doc))
+}
+
+func TestDocumentGlobal(t *testing.T) {
+	doc, source := load(t, "comment.ini")
+
+	// A new global section goes first, before the comments.
+	doc.Set("", safeorderedmap.New[string]().Add("a", "1"))
+
+	assert.Equal(t, "a = 1\n"+source, marshal(t, doc))
+}
+
+func TestNewDocument(t *testing.T) {
+	assert.Equal(t, "", marshal(t, NewDocument(nil)))
+	assert.Equal(t, "[a]\nb = 1\n", marshal(t, NewDocument(safeorderedmap.New[*Section]().Add("a", safeorderedmap.New[string]().Add("b", "1")))))
+}
@@ -0,0 +1,224 @@
+package ini
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/thalesfsp/go-common-types/safeorderedmap"
+)
+
+//////
+// Const, vars, and types.
+//////
+
+// ErrSyntax is returned when a document isn't valid INI.
+var ErrSyntax = errors.New("ini: syntax error")
+
+// Section is an INI section, keeping the order of its keys.
+type Section = safeorderedmap.SafeOrderedMap[string]
+
+// File is an INI file, keeping the order of its sections. Keys before the
+// first section are in the section named "".
+type File = safeorderedmap.SafeOrderedMap[*Section]
+
+//////
+// Helpers.
+//////
+
+// unquote removes the double quotes around a value, if any.
+func unquote(value string) string {
+	if len(value) >= 2 && value[0] == '"' && value[len(value)-1] == '"' {
+		return value[1 : len(value)-1]
+	}
+
+	return value
+}
+
+// quote adds double quotes around a value which wouldn't be read back as is,
+// e.g.: with leading spaces, or a comment character.
+func quote(value string) string {
+	if value != strings.TrimSpace(value) || strings.ContainsAny(value, ";#") || unquote(value) != value {
+		return `"` + value + `"`
+	}
+
+	return value
+}
+
+// header returns the header of the section.
+func header(name string) (string, error) {
+	if strings.ContainsAny(name, "[]\n") {
+		return "", fmt.Errorf("ini: invalid section name %q", name)
+	}
+
+	return "[" + name + "]\n", nil
+}
+
+// entry returns the line of the entry.
+func entry(key, value string) (string, error) {
+	if key == "" || strings.ContainsAny(key, "=:\n") || key != strings.TrimSpace(key) {
+		return "", fmt.Errorf("ini: invalid key %q", key)
+	}
+
+	if err := check(key, value); err != nil {
+		return "", err
+	}
+
+	return key + " = " + quote(value) + "\n", nil
+}
+
+// check checks that the value of the key can be written.
+func check(key, value string) error {
+	if strings.Contains(value, "\n") {
+		return fmt.Errorf("ini: key %q: multi-line values aren't supported", key)
+	}
+
+	return nil
+}
+
+//////
+// Decoding.
+//////
+
+// Decode reads an INI file from r. Sections are [name] lines, entries are
+// key = value, or key: value lines, and lines starting with ; or # are
+// comments. Values may be double-quoted, to keep spaces, and comment
+// characters. In the File, repeated keys keep their first position, and
+// their last value, and repeated sections are merged, while the Document
+// keeps its lines as they were, see Document.
+func Decode(r io.Reader) (*Document, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	return Unmarshal(data)
+}
+
+// Unmarshal is like Decode, from a byte slice.
+func Unmarshal(data []byte) (*Document, error) {
+	file := safeorderedmap.New[*Section]()
+
+	current := &block{}
+
+	doc := &Document{File: file, blocks: []*block{current}}
+
+	// leading are the lines preceding the next statement.
+	var leading strings.Builder
+
+	source := string(data)
+
+	for n := 1; source != ""; n++ {
+		raw := source
+
+		if i := strings.IndexByte(source, '\n'); i >= 0 {
+			raw = source[:i+1]
+		}
+
+		source = source[len(raw):]
+
+		line := strings.TrimSpace(raw)
+
+		switch {
+		case line == "", line[0] == ';', line[0] == '#':
+			leading.WriteString(raw)
+
+			continue
+		case line[0] == '[':
+			if line[len(line)-1] != ']' {
+				return nil, fmt.Errorf("%w: line %d: unterminated section", ErrSyntax, n)
+			}
+
+			name := strings.TrimSpace(line[1 : len(line)-1])
+
+			section, ok := file.Get(name)
+			if !ok {
+				section = safeorderedmap.New[string]()
+
+				file.Add(name, section)
+			}
+
+			current = &block{leading: leading.String(), header: raw, name: name, section: section}
+
+			doc.blocks = append(doc.blocks, current)
+
+			leading.Reset()
+
+			continue
+		}
+
+		i := strings.IndexAny(line, "=:")
+		if i <= 0 {
+			return nil, fmt.Errorf("%w: line %d: expected key = value", ErrSyntax, n)
+		}
+
+		// The global section is only added if it has entries.
+		if current.section == nil {
+			current.section = safeorderedmap.New[string]()
+
+			file.Add("", current.section)
+		}
+
+		key := strings.TrimSpace(line[:i])
+		value := unquote(strings.TrimSpace(line[i+1:]))
+
+		// The value is what's left of the line, after the separator, and the
+		// spaces around it.
+		body := strings.TrimRight(raw, " \t\r\n")
+		start := strings.IndexAny(body, "=:") + 1
+
+		for start < len(body) && (body[start] == ' ' || body[start] == '\t') {
+			start++
+		}
+
+		current.section.Add(key, value)
+
+		current.items = append(current.items, &item{
+			leading: leading.String(),
+			key:     key,
+			value:   value,
+			prefix:  body[:start],
+			raw:     body[start:],
+			suffix:  raw[len(body):],
+		})
+
+		leading.Reset()
+	}
+
+	doc.trailing = leading.String()
+
+	return doc, nil
+}
+
+//////
+// Encoding.
+//////
+
+// Encode writes the document to w as INI. Lines of a decoded document are
+// written as they were, with their comments, and formatting, including
+// repeated sections, unless their value changed, see Document. A new
+// document is written keeping the order of the sections, and keys.
+func Encode(w io.Writer, doc *Document) error {
+	var buf bytes.Buffer
+
+	if err := doc.encode(&buf); err != nil {
+		return err
+	}
+
+	_, err := w.Write(buf.Bytes())
+
+	return err
+}
+
+// Marshal is like Encode, returning a byte slice.
+func Marshal(doc *Document) ([]byte, error) {
+	var buf bytes.Buffer
+
+	if err := Encode(&buf, doc); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
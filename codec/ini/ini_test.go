@@ -0,0 +1,98 @@
+package ini
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/thalesfsp/go-common-types/safeorderedmap"
+)
+
+const document = `name = app
+
+[server]
+port = 8080
+host = 0.0.0.0
+
+[database]
+url = "  postgres://db ; primary"
+pool = 10
+`
+
+func TestRoundTrip(t *testing.T) {
+	file, err := Unmarshal([]byte(document))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"", "server", "database"}, file.Keys())
+
+	// A new document is written as the decoded one.
+	data, err := Marshal(NewDocument(file.File))
+	assert.NoError(t, err)
+	assert.Equal(t, document, string(data))
+
+	server, _ := file.Get("server")
+	assert.Equal(t, []string{"port", "host"}, server.Keys())
+
+	database, _ := file.Get("database")
+	url, _ := database.Get("url")
+	assert.Equal(t, "  postgres://db ; primary", url)
+
+	data, err = Marshal(file)
+	assert.NoError(t, err)
+	assert.Equal(t, document, string(data))
+
+	// Editing a single key keeps the order.
+	server.Set("port", "9090")
+
+	data, err = Marshal(file)
+	assert.NoError(t, err)
+	assert.Equal(t, strings.Replace(document, "8080", "9090", 1), string(data))
+}
+
+func TestDecode(t *testing.T) {
+	file, err := Decode(strings.NewReader(`
+; Comment.
+# Comment.
+[b]
+x: 1
+y = a=b
+x = 2
+[a]
+[b]
+z = 3
+`))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"b", "a"}, file.Keys())
+
+	b, _ := file.Get("b")
+	assert.Equal(t, []string{"x", "y", "z"}, b.Keys())
+	assert.Equal(t, []string{"2", "a=b", "3"}, b.Values())
+
+	for _, doc := range []string{"[a", "novalue", "= 1"} {
+		_, err := Unmarshal([]byte(doc))
+		assert.ErrorIs(t, err, ErrSyntax, doc)
+	}
+}
+
+func TestEncodeErrors(t *testing.T) {
+	for _, section := range []*Section{
+		safeorderedmap.New[string]().Add("a=b", "1"),
+		safeorderedmap.New[string]().Add("", "1"),
+		safeorderedmap.New[string]().Add("a", "1\n2"),
+	} {
+		_, err := Marshal(NewDocument(safeorderedmap.New[*Section]().Add("s", section)))
+		assert.Error(t, err)
+	}
+
+	_, err := Marshal(NewDocument(safeorderedmap.New[*Section]().Add("[s]", safeorderedmap.New[string]())))
+	assert.Error(t, err)
+
+	// Changed values of a decoded document are checked too.
+	file, err := Unmarshal([]byte("[s]\na = 1\n"))
+	assert.NoError(t, err)
+
+	s, _ := file.Get("s")
+	s.Set("a", "1\n2")
+
+	_, err = Marshal(file)
+	assert.Error(t, err)
+}
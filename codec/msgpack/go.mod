@@ -0,0 +1,20 @@
+module github.com/thalesfsp/go-common-types/codec/msgpack
+
+go 1.20
+
+require (
+	github.com/stretchr/testify v1.8.4
+	github.com/thalesfsp/go-common-types v1.0.0
+	github.com/vmihailenco/msgpack/v5 v5.3.5
+)
+
+require (
+	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/kr/text v0.2.0 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+	github.com/vmihailenco/tagparser/v2 v2.0.0 // indirect
+	golang.org/x/exp v0.0.0-20230522175609-2e198f4a06a1 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
+
+replace github.com/thalesfsp/go-common-types => ../..
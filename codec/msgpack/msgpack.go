@@ -0,0 +1,44 @@
+package msgpack
+
+import (
+	"bytes"
+
+	"github.com/thalesfsp/go-common-types/shared"
+	vmsgpack "github.com/vmihailenco/msgpack/v5"
+)
+
+//////
+// Const, vars, and types.
+//////
+
+// Encodable is implemented by all collections.
+type Encodable interface {
+	EncodeWith(enc shared.Encoder) error
+}
+
+// Decodable is implemented by all collections.
+type Decodable interface {
+	DecodeWith(dec shared.Decoder) error
+}
+
+//////
+// Exported functionalities.
+//////
+
+// Marshal encodes the collection as MessagePack. SafeOrderedMap preserves the
+// insertion order of the keys.
+func Marshal(v Encodable) ([]byte, error) {
+	var buf bytes.Buffer
+
+	if err := v.EncodeWith(vmsgpack.NewEncoder(&buf)); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// Unmarshal decodes MessagePack data into the collection, replacing its
+// content.
+func Unmarshal(data []byte, v Decodable) error {
+	return v.DecodeWith(vmsgpack.NewDecoder(bytes.NewReader(data)))
+}
@@ -0,0 +1,89 @@
+package protostruct
+
+import (
+	"sort"
+
+	"github.com/thalesfsp/go-common-types/safeorderedmap"
+	"github.com/thalesfsp/go-common-types/safeslice"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+//////
+// Helpers.
+//////
+
+// normalize converts nested collections to the plain Go types understood by
+// structpb.
+func normalize(v any) any {
+	switch value := v.(type) {
+	case *safeorderedmap.SafeOrderedMap[any]:
+		m := make(map[string]any, value.Size())
+
+		value.Each(func(key string, v any) {
+			m[key] = normalize(v)
+		})
+
+		return m
+	case *safeslice.SafeSlice[any]:
+		items := value.ToSlice()
+
+		l := make([]any, 0, len(items))
+
+		for _, item := range items {
+			l = append(l, normalize(item))
+		}
+
+		return l
+	default:
+		return v
+	}
+}
+
+//////
+// Exported functionalities.
+//////
+
+// ToProtoStruct converts the map to a google.protobuf.Struct. Nested
+// SafeOrderedMap[any], and SafeSlice[any] are converted as well.
+//
+// NOTE: Protobuf maps are unordered, so the insertion order of the keys is
+// lost.
+func ToProtoStruct(m *safeorderedmap.SafeOrderedMap[any]) (*structpb.Struct, error) {
+	//nolint:forcetypeassert
+	return structpb.NewStruct(normalize(m).(map[string]any))
+}
+
+// FromProtoStruct converts a google.protobuf.Struct to a map. As protobuf
+// maps are unordered, keys are added sorted, so the result is deterministic.
+// Nested structs, and lists, are converted to map[string]any, and []any.
+func FromProtoStruct(s *structpb.Struct) *safeorderedmap.SafeOrderedMap[any] {
+	values := s.AsMap()
+
+	keys := make([]string, 0, len(values))
+
+	for key := range values {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	m := safeorderedmap.New[any]()
+
+	for _, key := range keys {
+		m.Add(key, values[key])
+	}
+
+	return m
+}
+
+// ToProtoList converts the slice to a google.protobuf.ListValue. Nested
+// SafeOrderedMap[any], and SafeSlice[any] are converted as well.
+func ToProtoList(s *safeslice.SafeSlice[any]) (*structpb.ListValue, error) {
+	//nolint:forcetypeassert
+	return structpb.NewList(normalize(s).([]any))
+}
+
+// FromProtoList converts a google.protobuf.ListValue to a slice.
+func FromProtoList(l *structpb.ListValue) *safeslice.SafeSlice[any] {
+	return safeslice.New(l.AsSlice()...)
+}
@@ -0,0 +1,109 @@
+package protostruct
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/thalesfsp/go-common-types/safeorderedmap"
+	"github.com/thalesfsp/go-common-types/safeslice"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+func TestToProtoStruct(t *testing.T) {
+	nested := safeorderedmap.New[any]()
+
+	nested.Add("z", "last").Add("a", true)
+
+	m := safeorderedmap.New[any]()
+
+	m.Add("name", "a").
+		Add("count", 2).
+		Add("ratio", 0.5).
+		Add("none", nil).
+		Add("nested", nested).
+		Add("list", safeslice.New[any](1, "b", nested)).
+		Add("plain", map[string]any{"k": []any{"v"}})
+
+	s, err := ToProtoStruct(m)
+
+	assert.NoError(t, err)
+
+	fields := s.GetFields()
+
+	assert.Equal(t, "a", fields["name"].GetStringValue())
+	assert.Equal(t, float64(2), fields["count"].GetNumberValue())
+	assert.Equal(t, 0.5, fields["ratio"].GetNumberValue())
+	assert.IsType(t, &structpb.Value_NullValue{}, fields["none"].GetKind())
+	assert.Equal(t, "last", fields["nested"].GetStructValue().GetFields()["z"].GetStringValue())
+	assert.True(t, fields["nested"].GetStructValue().GetFields()["a"].GetBoolValue())
+
+	list := fields["list"].GetListValue().GetValues()
+
+	assert.Len(t, list, 3)
+	assert.Equal(t, float64(1), list[0].GetNumberValue())
+	assert.Equal(t, "b", list[1].GetStringValue())
+	assert.Equal(t, "last", list[2].GetStructValue().GetFields()["z"].GetStringValue())
+	assert.Equal(t, "v", fields["plain"].GetStructValue().GetFields()["k"].GetListValue().GetValues()[0].GetStringValue())
+
+	// Survives the wire.
+	data, err := proto.Marshal(s)
+
+	assert.NoError(t, err)
+
+	decoded := &structpb.Struct{}
+
+	assert.NoError(t, proto.Unmarshal(data, decoded))
+	assert.True(t, proto.Equal(s, decoded))
+
+	// Unsupported values are reported.
+	_, err = ToProtoStruct(safeorderedmap.New[any]().Add("ch", make(chan int)))
+
+	assert.Error(t, err)
+}
+
+func TestFromProtoStruct(t *testing.T) {
+	s, err := structpb.NewStruct(map[string]any{
+		"b":      1,
+		"a":      "x",
+		"c":      nil,
+		"nested": map[string]any{"k": []any{true, 2}},
+	})
+
+	assert.NoError(t, err)
+
+	m := FromProtoStruct(s)
+
+	assert.Equal(t, []string{"a", "b", "c", "nested"}, m.Keys())
+	assert.Equal(t, []any{"x", float64(1), nil, map[string]any{"k": []any{true, float64(2)}}}, m.Values())
+
+	// Round-trip.
+	back, err := ToProtoStruct(m)
+
+	assert.NoError(t, err)
+	assert.True(t, proto.Equal(s, back))
+
+	assert.Equal(t, 0, FromProtoStruct(&structpb.Struct{}).Size())
+}
+
+func TestProtoList(t *testing.T) {
+	nested := safeorderedmap.New[any]()
+
+	nested.Add("k", "v")
+
+	s := safeslice.New[any]("a", 1, nil, nested, safeslice.New[any](true))
+
+	l, err := ToProtoList(s)
+
+	assert.NoError(t, err)
+
+	values := l.GetValues()
+
+	assert.Len(t, values, 5)
+	assert.Equal(t, "v", values[3].GetStructValue().GetFields()["k"].GetStringValue())
+	assert.True(t, values[4].GetListValue().GetValues()[0].GetBoolValue())
+
+	back := FromProtoList(l)
+
+	assert.Equal(t, []any{"a", float64(1), nil, map[string]any{"k": "v"}, []any{true}}, back.ToSlice())
+}
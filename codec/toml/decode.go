@@ -0,0 +1,787 @@
+package toml
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/thalesfsp/go-common-types/safeorderedmap"
+)
+
+//////
+// Const, vars, and types.
+//////
+
+// parser is a recursive descent TOML parser.
+type parser struct {
+	data string
+	pos  int
+	line int
+
+	// defined tracks the tables defined by a header, or a key, which can't be
+	// defined again.
+	defined map[*Table]bool
+}
+
+// pair is a parsed key = value pair, and the position of the value.
+type pair struct {
+	key        []string
+	value      any
+	start, end int
+}
+
+//////
+// Helpers.
+//////
+
+// errorf returns a syntax error at the current line.
+func (p *parser) errorf(format string, args ...any) error {
+	return fmt.Errorf("%w: line %d: %s", ErrSyntax, p.line, fmt.Sprintf(format, args...))
+}
+
+// eof checks if all the data was read.
+func (p *parser) eof() bool {
+	return p.pos >= len(p.data)
+}
+
+// peek returns the current byte, 0 at the end.
+func (p *parser) peek() byte {
+	if p.eof() {
+		return 0
+	}
+
+	return p.data[p.pos]
+}
+
+// has checks if the data continues with s.
+func (p *parser) has(s string) bool {
+	return strings.HasPrefix(p.data[p.pos:], s)
+}
+
+// skipSpace skips spaces, and tabs.
+func (p *parser) skipSpace() {
+	for p.peek() == ' ' || p.peek() == '\t' {
+		p.pos++
+	}
+}
+
+// skipComment skips a comment, up to the end of the line.
+func (p *parser) skipComment() {
+	if p.peek() != '#' {
+		return
+	}
+
+	for !p.eof() && p.peek() != '\n' {
+		p.pos++
+	}
+}
+
+// skipBlank skips whitespace, newlines, and comments.
+func (p *parser) skipBlank() {
+	for {
+		p.skipSpace()
+		p.skipComment()
+
+		switch {
+		case p.peek() == '\n':
+			p.pos++
+			p.line++
+		case p.has("\r\n"):
+			p.pos += 2
+			p.line++
+		default:
+			return
+		}
+	}
+}
+
+// endOfLine expects the end of a statement: an optional comment, and a
+// newline, or the end of the data.
+func (p *parser) endOfLine() error {
+	p.skipSpace()
+	p.skipComment()
+
+	switch {
+	case p.eof():
+		return nil
+	case p.peek() == '\n':
+		p.pos++
+		p.line++
+
+		return nil
+	case p.has("\r\n"):
+		p.pos += 2
+		p.line++
+
+		return nil
+	default:
+		return p.errorf("unexpected %q after the statement", p.peek())
+	}
+}
+
+// isBare checks if c can be in a bare key.
+func isBare(c byte) bool {
+	return c >= 'A' && c <= 'Z' || c >= 'a' && c <= 'z' || c >= '0' && c <= '9' || c == '_' || c == '-'
+}
+
+//////
+// Keys.
+//////
+
+// key parses a dotted key into its parts.
+func (p *parser) key() ([]string, error) {
+	parts := []string{}
+
+	for {
+		p.skipSpace()
+
+		var (
+			part string
+			err  error
+		)
+
+		switch c := p.peek(); {
+		case c == '"':
+			part, err = p.basicString()
+		case c == '\'':
+			part, err = p.literalString()
+		case isBare(c):
+			start := p.pos
+
+			for isBare(p.peek()) {
+				p.pos++
+			}
+
+			part = p.data[start:p.pos]
+		default:
+			return nil, p.errorf("invalid key")
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		parts = append(parts, part)
+
+		p.skipSpace()
+
+		if p.peek() != '.' {
+			return parts, nil
+		}
+
+		p.pos++
+	}
+}
+
+// descend walks the parts from the table, creating missing tables, and
+// returns the last one. Arrays of tables resolve to their last table.
+func (p *parser) descend(table *Table, parts []string) (*Table, error) {
+	for _, part := range parts {
+		value, ok := table.Get(part)
+		if !ok {
+			child := safeorderedmap.New[any]()
+
+			table.Add(part, child)
+
+			table = child
+
+			continue
+		}
+
+		switch v := value.(type) {
+		case *Table:
+			table = v
+		case []any:
+			last, ok := lastTable(v)
+			if !ok {
+				return nil, p.errorf("key %q isn't a table", part)
+			}
+
+			table = last
+		default:
+			return nil, p.errorf("key %q isn't a table", part)
+		}
+	}
+
+	return table, nil
+}
+
+// lastTable returns the last table of an array of tables.
+func lastTable(array []any) (*Table, bool) {
+	if len(array) == 0 {
+		return nil, false
+	}
+
+	table, ok := array[len(array)-1].(*Table)
+
+	return table, ok
+}
+
+//////
+// Statements.
+//////
+
+// document parses the whole document, recording its statements, see
+// Document.
+func (p *parser) document() (*Document, error) {
+	root := safeorderedmap.New[any]()
+
+	current := &block{table: root}
+
+	doc := &Document{Table: root, blocks: []*block{current}}
+
+	// end is the position right after the last statement.
+	end := 0
+
+	for {
+		p.skipBlank()
+
+		if p.eof() {
+			doc.trailing = p.data[end:]
+
+			return doc, nil
+		}
+
+		start := p.pos
+
+		var (
+			kv    pair
+			table *Table
+			parts []string
+			err   error
+		)
+
+		switch {
+		case p.has("[["):
+			table, parts, err = p.arrayTableHeader(root)
+		case p.peek() == '[':
+			table, parts, err = p.tableHeader(root)
+		default:
+			kv, err = p.keyValue(current.table)
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		if err := p.endOfLine(); err != nil {
+			return nil, err
+		}
+
+		if table != nil {
+			current = &block{
+				leading: p.data[end:start],
+				header:  p.data[start:p.pos],
+				path:    formatPath(parts),
+				table:   table,
+			}
+
+			doc.blocks = append(doc.blocks, current)
+		} else {
+			norm, err := format(kv.value)
+			if err != nil {
+				return nil, p.errorf("%v", err)
+			}
+
+			current.items = append(current.items, &item{
+				leading: p.data[end:start],
+				key:     kv.key,
+				prefix:  p.data[start:kv.start],
+				raw:     p.data[kv.start:kv.end],
+				suffix:  p.data[kv.end:p.pos],
+				norm:    norm,
+			})
+		}
+
+		end = p.pos
+	}
+}
+
+// tableHeader parses a [table] header, returning the table, and its name.
+func (p *parser) tableHeader(root *Table) (*Table, []string, error) {
+	p.pos++
+
+	parts, err := p.key()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if p.peek() != ']' {
+		return nil, nil, p.errorf("expected ] after the table name")
+	}
+
+	p.pos++
+
+	table, err := p.descend(root, parts)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if p.defined[table] {
+		return nil, nil, p.errorf("table %q defined twice", strings.Join(parts, "."))
+	}
+
+	p.defined[table] = true
+
+	return table, parts, nil
+}
+
+// arrayTableHeader parses a [[table]] header, appending a table to the array,
+// and returning it, and the name of the array.
+func (p *parser) arrayTableHeader(root *Table) (*Table, []string, error) {
+	p.pos += 2
+
+	parts, err := p.key()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if !p.has("]]") {
+		return nil, nil, p.errorf("expected ]] after the table name")
+	}
+
+	p.pos += 2
+
+	parent, err := p.descend(root, parts[:len(parts)-1])
+	if err != nil {
+		return nil, nil, err
+	}
+
+	name := parts[len(parts)-1]
+	table := safeorderedmap.New[any]()
+
+	value, ok := parent.Get(name)
+	if !ok {
+		parent.Add(name, []any{table})
+
+		return table, parts, nil
+	}
+
+	array, ok := value.([]any)
+	if _, isTable := lastTable(array); !ok || !isTable {
+		return nil, nil, p.errorf("key %q isn't an array of tables", name)
+	}
+
+	parent.Add(name, append(array, table))
+
+	return table, parts, nil
+}
+
+// keyValue parses a key = value pair into the table.
+func (p *parser) keyValue(table *Table) (pair, error) {
+	parts, err := p.key()
+	if err != nil {
+		return pair{}, err
+	}
+
+	if p.peek() != '=' {
+		return pair{}, p.errorf("expected = after the key")
+	}
+
+	p.pos++
+
+	p.skipSpace()
+
+	start := p.pos
+
+	value, err := p.value()
+	if err != nil {
+		return pair{}, err
+	}
+
+	parent, err := p.descend(table, parts[:len(parts)-1])
+	if err != nil {
+		return pair{}, err
+	}
+
+	name := parts[len(parts)-1]
+
+	if parent.Contains(name) {
+		return pair{}, p.errorf("key %q defined twice", strings.Join(parts, "."))
+	}
+
+	if t, ok := value.(*Table); ok {
+		p.defined[t] = true
+	}
+
+	parent.Add(name, value)
+
+	return pair{key: parts, value: value, start: start, end: p.pos}, nil
+}
+
+//////
+// Values.
+//////
+
+// value parses a value.
+func (p *parser) value() (any, error) {
+	switch c := p.peek(); {
+	case p.has(`"""`):
+		return p.multilineBasicString()
+	case c == '"':
+		return p.basicString()
+	case p.has("'''"):
+		return p.multilineLiteralString()
+	case c == '\'':
+		return p.literalString()
+	case c == '[':
+		return p.array()
+	case c == '{':
+		return p.inlineTable()
+	case p.has("true"):
+		p.pos += 4
+
+		return true, nil
+	case p.has("false"):
+		p.pos += 5
+
+		return false, nil
+	case c == 0:
+		return nil, p.errorf("missing value")
+	default:
+		return p.scalar()
+	}
+}
+
+// array parses an array, whose elements can span lines.
+func (p *parser) array() ([]any, error) {
+	p.pos++
+
+	array := []any{}
+
+	for {
+		p.skipBlank()
+
+		if p.peek() == ']' {
+			p.pos++
+
+			return array, nil
+		}
+
+		value, err := p.value()
+		if err != nil {
+			return nil, err
+		}
+
+		array = append(array, value)
+
+		p.skipBlank()
+
+		switch p.peek() {
+		case ',':
+			p.pos++
+		case ']':
+		default:
+			return nil, p.errorf("expected , or ] in the array")
+		}
+	}
+}
+
+// inlineTable parses an inline table.
+func (p *parser) inlineTable() (*Table, error) {
+	p.pos++
+
+	table := safeorderedmap.New[any]()
+
+	p.skipSpace()
+
+	if p.peek() == '}' {
+		p.pos++
+
+		return table, nil
+	}
+
+	for {
+		if _, err := p.keyValue(table); err != nil {
+			return nil, err
+		}
+
+		p.skipSpace()
+
+		switch p.peek() {
+		case ',':
+			p.pos++
+		case '}':
+			p.pos++
+
+			return table, nil
+		default:
+			return nil, p.errorf("expected , or } in the inline table")
+		}
+	}
+}
+
+// scalar parses a number, or a date-time.
+func (p *parser) scalar() (any, error) {
+	start := p.pos
+
+	for c := p.peek(); isBare(c) || c == '.' || c == ':' || c == '+'; c = p.peek() {
+		p.pos++
+	}
+
+	// A space may separate the date, and the time.
+	if p.pos-start == 10 && p.peek() == ' ' && p.pos+1 < len(p.data) && p.data[p.pos+1] >= '0' && p.data[p.pos+1] <= '9' {
+		p.pos++
+
+		for c := p.peek(); isBare(c) || c == '.' || c == ':' || c == '+'; c = p.peek() {
+			p.pos++
+		}
+	}
+
+	token := p.data[start:p.pos]
+
+	if value, ok := parseDateTime(token); ok {
+		return value, nil
+	}
+
+	switch strings.TrimLeft(token, "+-") {
+	case "inf":
+		if strings.HasPrefix(token, "-") {
+			return math.Inf(-1), nil
+		}
+
+		return math.Inf(1), nil
+	case "nan":
+		return math.NaN(), nil
+	}
+
+	if !strings.HasPrefix(token, "0x") && strings.ContainsAny(token, ".eE") {
+		f, err := strconv.ParseFloat(strings.ReplaceAll(token, "_", ""), 64)
+		if err != nil {
+			return nil, p.errorf("invalid float %q", token)
+		}
+
+		return f, nil
+	}
+
+	// Leading zeros aren't allowed, so base 0 doesn't read them as octal.
+	digits := strings.TrimLeft(token, "+-")
+	if len(digits) > 1 && digits[0] == '0' && isDigit(digits[1]) {
+		return nil, p.errorf("invalid integer %q", token)
+	}
+
+	i, err := strconv.ParseInt(token, 0, 64)
+	if err == nil {
+		return i, nil
+	}
+
+	// Positive integers beyond int64 are read as uint64, e.g.: IDs.
+	if !strings.HasPrefix(token, "-") {
+		if u, err := strconv.ParseUint(strings.TrimPrefix(token, "+"), 0, 64); err == nil {
+			return u, nil
+		}
+	}
+
+	return nil, p.errorf("invalid value %q", token)
+}
+
+// isDigit checks if c is a decimal digit.
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+// parseDateTime parses offset date-times as time.Time, and local ones as
+// LocalDateTime, LocalDate, or LocalTime.
+func parseDateTime(token string) (any, bool) {
+	normalized := strings.Replace(strings.ToUpper(token), " ", "T", 1)
+
+	if t, err := time.Parse(time.RFC3339Nano, normalized); err == nil {
+		return t, true
+	}
+
+	if _, err := time.Parse("2006-01-02T15:04:05.999999999", normalized); err == nil {
+		return LocalDateTime(token), true
+	}
+
+	if _, err := time.Parse("2006-01-02", token); err == nil {
+		return LocalDate(token), true
+	}
+
+	if _, err := time.Parse("15:04:05.999999999", token); err == nil {
+		return LocalTime(token), true
+	}
+
+	return nil, false
+}
+
+//////
+// Strings.
+//////
+
+// basicString parses a "basic string", with escapes.
+func (p *parser) basicString() (string, error) {
+	p.pos++
+
+	var sb strings.Builder
+
+	for {
+		switch c := p.peek(); c {
+		case 0, '\n':
+			return "", p.errorf("unterminated string")
+		case '"':
+			p.pos++
+
+			return sb.String(), nil
+		case '\\':
+			if err := p.escape(&sb); err != nil {
+				return "", err
+			}
+		default:
+			sb.WriteByte(c)
+
+			p.pos++
+		}
+	}
+}
+
+// multilineBasicString parses a """multi-line basic string""".
+func (p *parser) multilineBasicString() (string, error) {
+	p.pos += 3
+
+	// A newline right after the delimiter is trimmed.
+	p.trimNewline()
+
+	var sb strings.Builder
+
+	for {
+		switch c := p.peek(); {
+		case c == 0:
+			return "", p.errorf("unterminated string")
+		case p.has(`"""`) && !p.has(`""""`):
+			p.pos += 3
+
+			return sb.String(), nil
+		case p.has("\\\n"), p.has("\\\r\n"), p.has("\\ "), p.has("\\\t"):
+			// A line ending backslash trims the whitespace up to the next
+			// non-whitespace character.
+			p.pos++
+
+			for c := p.peek(); c == ' ' || c == '\t' || c == '\n' || c == '\r'; c = p.peek() {
+				if c == '\n' {
+					p.line++
+				}
+
+				p.pos++
+			}
+		case c == '\\':
+			if err := p.escape(&sb); err != nil {
+				return "", err
+			}
+		default:
+			if c == '\n' {
+				p.line++
+			}
+
+			sb.WriteByte(c)
+
+			p.pos++
+		}
+	}
+}
+
+// literalString parses a 'literal string', without escapes.
+func (p *parser) literalString() (string, error) {
+	p.pos++
+
+	end := strings.IndexAny(p.data[p.pos:], "'\n")
+	if end < 0 || p.data[p.pos+end] != '\'' {
+		return "", p.errorf("unterminated string")
+	}
+
+	s := p.data[p.pos : p.pos+end]
+
+	p.pos += end + 1
+
+	return s, nil
+}
+
+// multilineLiteralString parses a multi-line literal string, delimited by
+// three single quotes.
+func (p *parser) multilineLiteralString() (string, error) {
+	p.pos += 3
+
+	p.trimNewline()
+
+	end := strings.Index(p.data[p.pos:], "'''")
+	if end < 0 {
+		return "", p.errorf("unterminated string")
+	}
+
+	// Up to two quotes may precede the delimiter.
+	for i := 0; i < 2 && p.pos+end+3 < len(p.data) && p.data[p.pos+end+3] == '\''; i++ {
+		end++
+	}
+
+	s := p.data[p.pos : p.pos+end]
+
+	p.line += strings.Count(s, "\n")
+	p.pos += end + 3
+
+	return s, nil
+}
+
+// trimNewline skips a newline right after the opening delimiter of a
+// multi-line string.
+func (p *parser) trimNewline() {
+	switch {
+	case p.peek() == '\n':
+		p.pos++
+		p.line++
+	case p.has("\r\n"):
+		p.pos += 2
+		p.line++
+	}
+}
+
+// escape parses an escape sequence into sb.
+func (p *parser) escape(sb *strings.Builder) error {
+	p.pos++
+
+	c := p.peek()
+
+	p.pos++
+
+	switch c {
+	case 'b':
+		sb.WriteByte('\b')
+	case 't':
+		sb.WriteByte('\t')
+	case 'n':
+		sb.WriteByte('\n')
+	case 'f':
+		sb.WriteByte('\f')
+	case 'r':
+		sb.WriteByte('\r')
+	case 'e':
+		sb.WriteByte(0x1b)
+	case '"':
+		sb.WriteByte('"')
+	case '\\':
+		sb.WriteByte('\\')
+	case 'u', 'U':
+		n := 4
+		if c == 'U' {
+			n = 8
+		}
+
+		if p.pos+n > len(p.data) {
+			return p.errorf("invalid unicode escape")
+		}
+
+		code, err := strconv.ParseUint(p.data[p.pos:p.pos+n], 16, 32)
+		if err != nil || !utf8.ValidRune(rune(code)) {
+			return p.errorf("invalid unicode escape")
+		}
+
+		sb.WriteRune(rune(code))
+
+		p.pos += n
+	default:
+		return p.errorf("invalid escape \\%c", c)
+	}
+
+	return nil
+}
@@ -0,0 +1,469 @@
+package toml
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/thalesfsp/go-common-types/safeorderedmap"
+)
+
+//////
+// Const, vars, and types.
+//////
+
+// Document is a TOML document: its Table, and, if decoded, the source of its
+// statements, so it's written back as it was: with its comments, blank lines,
+// tables in their declaration order, inline tables, dotted keys, and values
+// as written, e.g.: literal strings, or hexadecimal integers. Editing the
+// Table, e.g.: with safeorderedmap.SetPath, only changes the statements of
+// the edited keys:
+//
+//   - Changed values are rewritten in place, keeping the key, and the comment
+//     of their statement.
+//   - Removed keys, and tables, are dropped, with the comments preceding them.
+//   - New values are appended to the table they belong to, and new tables are
+//     appended to the document.
+//
+// Inline tables, and arrays are values: a change to them rewrites the whole
+// value. Keys keep their original order. If arrays of tables are reordered,
+// which can't be kept, the whole document is written as a new one.
+type Document struct {
+	*Table
+
+	// blocks are the statements of the document, grouped by table header,
+	// the first one being the root table. Nil for a new document.
+	blocks []*block
+
+	// trailing is the source after the last statement, e.g.: comments.
+	trailing string
+}
+
+// block is a table header, and the key-value pairs following it.
+type block struct {
+	// leading is the source preceding the header, e.g.: blank lines, and
+	// comments.
+	leading string
+
+	// header is the source of the header, up to the end of its line, empty
+	// for the root table.
+	header string
+
+	// path is the formatted name of the table, to check it's still there.
+	path string
+
+	table *Table
+	items []*item
+}
+
+// item is a key = value statement.
+type item struct {
+	// leading is the source preceding the statement.
+	leading string
+
+	// key is the, possibly dotted, key, relative to the table of the block.
+	key []string
+
+	// prefix is the source of the key, up to the value, e.g.: `a.b = `.
+	prefix string
+
+	// raw is the source of the value.
+	raw string
+
+	// suffix is the source after the value, up to the end of its line, e.g.:
+	// a comment.
+	suffix string
+
+	// norm is the value, as written by the encoder, to detect changes.
+	norm string
+}
+
+// owned is a table whose values are written by a block: the table of the
+// block, or one defined by its dotted keys, under prefix.
+type owned struct {
+	table  *Table
+	prefix []string
+}
+
+// writer tracks what was written, while encoding a decoded document.
+type writer struct {
+	*encoder
+
+	// known are the tables written by a block.
+	known map[*Table]bool
+
+	// written are the keys written, per table.
+	written map[*Table]map[string]bool
+}
+
+//////
+// Methods.
+//////
+
+// encode writes the document, as it was decoded, or as a new one.
+func (d *Document) encode(e *encoder) error {
+	if d.blocks == nil {
+		return e.table(nil, d.Table, "")
+	}
+
+	paths := map[*Table]string{d.Table: ""}
+
+	walk(d.Table, nil, paths)
+
+	if !d.ordered() {
+		return e.table(nil, d.Table, "")
+	}
+
+	w := &writer{
+		encoder: e,
+		known:   map[*Table]bool{},
+		written: map[*Table]map[string]bool{},
+	}
+
+	for _, b := range d.blocks {
+		if path, ok := paths[b.table]; !ok || path != b.path {
+			continue
+		}
+
+		if err := w.block(b); err != nil {
+			return err
+		}
+	}
+
+	e.w.WriteString(d.trailing)
+
+	return w.rest(nil, d.Table)
+}
+
+// ordered checks if the tables of arrays of tables are in the order of their
+// headers, and new ones are last, so the headers can be written as they were.
+func (d *Document) ordered() bool {
+	index := map[*Table]int{}
+
+	for i, b := range d.blocks {
+		index[b.table] = i
+	}
+
+	ordered := true
+
+	each(d.Table, func(array []any) {
+		last, added := -1, false
+
+		for _, value := range array {
+			t, _ := value.(*Table)
+
+			i, ok := index[t]
+
+			switch {
+			case !ok:
+				added = true
+			case added || i < last:
+				ordered = false
+			default:
+				last = i
+			}
+		}
+	})
+
+	return ordered
+}
+
+// mark records the key as written.
+func (w *writer) mark(table *Table, key string) {
+	if w.written[table] == nil {
+		w.written[table] = map[string]bool{}
+	}
+
+	w.written[table][key] = true
+}
+
+// newline ends the last line, if not ended, e.g.: at the end of a document
+// without a final newline.
+func (w *writer) newline() {
+	if b := w.w.Bytes(); len(b) > 0 && b[len(b)-1] != '\n' {
+		w.w.WriteByte('\n')
+	}
+}
+
+// block writes the header, and the statements of the block, followed by the
+// new values of its tables.
+func (w *writer) block(b *block) error {
+	w.known[b.table] = true
+
+	w.w.WriteString(b.leading)
+	w.w.WriteString(b.header)
+
+	tables := []owned{{table: b.table}}
+
+	for _, it := range b.items {
+		parent, defined, ok := resolve(b.table, it.key[:len(it.key)-1])
+		if !ok {
+			continue
+		}
+
+		name := it.key[len(it.key)-1]
+
+		value, ok := parent.Get(name)
+		if !ok {
+			continue
+		}
+
+		for i, t := range defined {
+			if !w.known[t] {
+				w.known[t] = true
+
+				tables = append(tables, owned{table: t, prefix: it.key[:i+1]})
+			}
+		}
+
+		w.mark(parent, name)
+
+		norm, err := format(value)
+		if err != nil {
+			return keyError(it.key, err)
+		}
+
+		w.w.WriteString(it.leading)
+		w.w.WriteString(it.prefix)
+
+		if norm == it.norm {
+			w.w.WriteString(it.raw)
+		} else {
+			w.w.WriteString(norm)
+		}
+
+		w.w.WriteString(it.suffix)
+	}
+
+	for _, o := range tables {
+		if err := w.values(o.table, o.prefix); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// values writes the values of the table not written yet, as dotted keys under
+// prefix. Tables, and arrays of tables are left to rest.
+func (w *writer) values(table *Table, prefix []string) error {
+	for _, entry := range table.Entries() {
+		if w.written[table][entry.Key] || isTable(entry.Value) || isArrayOfTables(entry.Value) {
+			continue
+		}
+
+		w.mark(table, entry.Key)
+
+		key := append(append([]string{}, prefix...), entry.Key)
+
+		w.newline()
+		w.w.WriteString(formatPath(key))
+		w.w.WriteString(" = ")
+
+		if err := w.value(entry.Value); err != nil {
+			return keyError(key, err)
+		}
+
+		w.w.WriteByte('\n')
+	}
+
+	return nil
+}
+
+// rest writes what wasn't written by the blocks: new tables, and new values
+// of tables only defined implicitly by the headers of their sub-tables.
+func (w *writer) rest(path []string, table *Table) error {
+	for _, entry := range table.Entries() {
+		if w.written[table][entry.Key] {
+			continue
+		}
+
+		childPath := append(append([]string{}, path...), entry.Key)
+
+		switch {
+		case isTable(entry.Value):
+			if err := w.child(childPath, entry.Value, "["+formatPath(childPath)+"]"); err != nil {
+				return err
+			}
+		case isArrayOfTables(entry.Value):
+			//nolint:forcetypeassert
+			for _, t := range entry.Value.([]any) {
+				if err := w.child(childPath, t, "[["+formatPath(childPath)+"]]"); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// child writes the table, at path, under the header if it's new, otherwise
+// what's left of it.
+func (w *writer) child(path []string, value any, header string) error {
+	t, ok := value.(*Table)
+	if !ok || !w.reaches(t) {
+		w.newline()
+
+		w.started = w.w.Len() > 0
+
+		return w.table(path, value, header)
+	}
+
+	// Tables only defined by their sub-tables get a header for new values.
+	if !w.known[t] && hasValues(t, w.written[t]) {
+		w.known[t] = true
+
+		w.newline()
+
+		if w.w.Len() > 0 {
+			w.w.WriteByte('\n')
+		}
+
+		w.w.WriteString(header)
+		w.w.WriteByte('\n')
+
+		if err := w.values(t, nil); err != nil {
+			return err
+		}
+	}
+
+	return w.rest(path, t)
+}
+
+// reaches checks if the table, or one of its sub-tables was written by a
+// block.
+func (w *writer) reaches(table *Table) bool {
+	if w.known[table] {
+		return true
+	}
+
+	for _, value := range table.Values() {
+		switch v := value.(type) {
+		case *Table:
+			if w.reaches(v) {
+				return true
+			}
+		case []any:
+			for _, item := range v {
+				if t, ok := item.(*Table); ok && w.reaches(t) {
+					return true
+				}
+			}
+		}
+	}
+
+	return false
+}
+
+//////
+// Helpers.
+//////
+
+// format returns the value as written by the encoder.
+func format(value any) (string, error) {
+	e := &encoder{w: &bytes.Buffer{}}
+
+	if err := e.value(value); err != nil {
+		return "", err
+	}
+
+	return e.w.String(), nil
+}
+
+// keyError wraps the error of the value of the key.
+func keyError(key []string, err error) error {
+	return fmt.Errorf("toml: key %q: %w", strings.Join(key, "."), err)
+}
+
+// walk records the path of the tables under the table, arrays of tables
+// included.
+func walk(table *Table, path []string, paths map[*Table]string) {
+	for _, entry := range table.Entries() {
+		childPath := append(append([]string{}, path...), entry.Key)
+
+		switch v := entry.Value.(type) {
+		case *Table:
+			paths[v] = formatPath(childPath)
+
+			walk(v, childPath, paths)
+		case []any:
+			for _, item := range v {
+				if t, ok := item.(*Table); ok {
+					paths[t] = formatPath(childPath)
+
+					walk(t, childPath, paths)
+				}
+			}
+		}
+	}
+}
+
+// each calls f with the arrays of tables under the table.
+func each(table *Table, f func(array []any)) {
+	for _, value := range table.Values() {
+		switch v := value.(type) {
+		case *Table:
+			each(v, f)
+		case []any:
+			if !isArrayOfTables(v) {
+				continue
+			}
+
+			f(v)
+
+			for _, item := range v {
+				if t, ok := item.(*Table); ok {
+					each(t, f)
+				}
+			}
+		}
+	}
+}
+
+// resolve walks the parts of a dotted key from the table, returning the last
+// table, and the ones walked, false if one isn't there anymore.
+func resolve(table *Table, parts []string) (*Table, []*Table, bool) {
+	walked := make([]*Table, 0, len(parts))
+
+	for _, part := range parts {
+		value, ok := table.Get(part)
+		if !ok {
+			return nil, nil, false
+		}
+
+		if table, ok = value.(*Table); !ok {
+			return nil, nil, false
+		}
+
+		walked = append(walked, table)
+	}
+
+	return table, walked, true
+}
+
+// hasValues checks if the table has values, other than tables, not written
+// yet.
+func hasValues(table *Table, written map[string]bool) bool {
+	for _, entry := range table.Entries() {
+		if !written[entry.Key] && !isTable(entry.Value) && !isArrayOfTables(entry.Value) {
+			return true
+		}
+	}
+
+	return false
+}
+
+//////
+// Factory.
+//////
+
+// NewDocument creates a document of the table, written as a new one, e.g.:
+// to encode a table built in code. If table is nil, an empty one is created.
+func NewDocument(table *Table) *Document {
+	if table == nil {
+		table = safeorderedmap.New[any]()
+	}
+
+	return &Document{Table: table}
+}
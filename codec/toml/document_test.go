@@ -0,0 +1,219 @@
+package toml
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/thalesfsp/go-common-types/safeorderedmap"
+)
+
+// load decodes the document of testdata, returning it, and its source.
+func load(t *testing.T, name string) (*Document, string) {
+	t.Helper()
+
+	data, err := os.ReadFile(filepath.Join("testdata", name))
+	assert.NoError(t, err)
+
+	doc, err := Unmarshal(data)
+	assert.NoError(t, err)
+
+	return doc, string(data)
+}
+
+// marshal encodes the document.
+func marshal(t *testing.T, doc *Document) string {
+	t.Helper()
+
+	data, err := Marshal(doc)
+	assert.NoError(t, err)
+
+	return string(data)
+}
+
+func TestGolden(t *testing.T) {
+	files, err := filepath.Glob(filepath.Join("testdata", "*.toml"))
+	assert.NoError(t, err)
+	assert.NotEmpty(t, files)
+
+	for _, file := range files {
+		t.Run(filepath.Base(file), func(t *testing.T) {
+			doc, source := load(t, filepath.Base(file))
+
+			assert.Equal(t, source, marshal(t, doc))
+		})
+	}
+}
+
+func TestDocumentValues(t *testing.T) {
+	doc, _ := load(t, "config.toml")
+
+	assert.Equal(t, []string{"title", "id", "mask", "big", "float", "date", "inline", "fruit", "multi", "array", "a", "b", "products"}, doc.Keys())
+
+	id, _ := doc.Get("id")
+	assert.Equal(t, uint64(9223372036854775808), id)
+
+	mask, _ := doc.Get("mask")
+	assert.Equal(t, int64(0xdeadbeef), mask)
+
+	smooth, err := safeorderedmap.GetPath(doc.Table, "fruit.apple.texture.smooth")
+	assert.NoError(t, err)
+	assert.Equal(t, true, smooth)
+
+	// Beyond uint64, or negative beyond int64, are errors.
+	for _, source := range []string{"a = 18446744073709551616", "a = -9223372036854775809"} {
+		_, err := Unmarshal([]byte(source))
+		assert.ErrorIs(t, err, ErrSyntax, source)
+	}
+}
+
+func TestDocumentEdit(t *testing.T) {
+	for name, tc := range map[string]struct {
+		edit     func(t *testing.T, table *Table)
+		old, new string
+	}{
+		"value": {
+			edit: func(t *testing.T, table *Table) {
+				assert.NoError(t, safeorderedmap.SetPath(table, "a.x", 2))
+			},
+			old: "[a]\nx = 1\n",
+			new: "[a]\nx = 2\n",
+		},
+		"value with comment": {
+			edit: func(t *testing.T, table *Table) {
+				table.Set("title", "New")
+			},
+			old: `title = 'Literal "title"'   # Trailing comment.`,
+			new: `title = "New"   # Trailing comment.`,
+		},
+		"inline table": {
+			edit: func(t *testing.T, table *Table) {
+				assert.NoError(t, safeorderedmap.SetPath(table, "inline.nested.y", 3))
+			},
+			old: `inline = { name = "x", nested = { y = 2 } }`,
+			new: `inline = { name = "x", nested = { y = 3 } }`,
+		},
+		"dotted key": {
+			edit: func(t *testing.T, table *Table) {
+				assert.NoError(t, safeorderedmap.SetPath(table, "fruit.apple.color", "green"))
+			},
+			old: `fruit.apple.color = "red"`,
+			new: `fruit.apple.color = "green"`,
+		},
+		"array of tables": {
+			edit: func(t *testing.T, table *Table) {
+				products, _ := table.Get("products")
+
+				products.([]any)[1].(*Table).Set("name", "Screw")
+			},
+			old: "[[products]] # Second.\nname = \"Nail\"\n",
+			new: "[[products]] # Second.\nname = \"Screw\"\n",
+		},
+		"new value": {
+			edit: func(t *testing.T, table *Table) {
+				assert.NoError(t, safeorderedmap.SetPath(table, "a.y", "new"))
+			},
+			old: "[a]\nx = 1\n",
+			new: "[a]\nx = 1\ny = \"new\"\n",
+		},
+		"new dotted value": {
+			edit: func(t *testing.T, table *Table) {
+				assert.NoError(t, safeorderedmap.SetPath(table, "fruit.apple.size", 3))
+			},
+			old: "  2,\n]\n",
+			new: "  2,\n]\nfruit.apple.size = 3\n",
+		},
+		"removed value": {
+			edit: func(t *testing.T, table *Table) {
+				assert.NoError(t, safeorderedmap.DeletePath(table, "b.y"))
+			},
+			old: "[b]\ny = \"b\"\n",
+			new: "[b]\n",
+		},
+		"removed table": {
+			edit: func(t *testing.T, table *Table) {
+				assert.NoError(t, safeorderedmap.DeletePath(table, "a.c"))
+			},
+			old: "\n\t# Indented comment.\n[a.c]\nz = true\n",
+			new: "",
+		},
+		"new table": {
+			edit: func(t *testing.T, table *Table) {
+				assert.NoError(t, safeorderedmap.SetPath(table, "b.d.k", 1))
+			},
+			old: "# Trailing comment of the file.\n",
+			new: "# Trailing comment of the file.\n\n[b.d]\nk = 1\n",
+		},
+		"new table of array": {
+			edit: func(t *testing.T, table *Table) {
+				products, _ := table.Get("products")
+
+				table.Set("products", append(products.([]any), safeorderedmap.New[any]().Add("name", "Bolt")))
+			},
+			old: "# Trailing comment of the file.\n",
+			new: "# Trailing comment of the file.\n\n[[products]]\nname = \"Bolt\"\n",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			doc, source := load(t, "config.toml")
+
+			assert.Contains(t, source, tc.old)
+
+			tc.edit(t, doc.Table)
+
+			data := marshal(t, doc)
+
+			assert.Equal(t, strings.Replace(source, tc.old, tc.new, 1), data)
+
+			// The result is valid, with the edit.
+			decoded, err := Unmarshal([]byte(data))
+			assert.NoError(t, err)
+
+			want, err := json.Marshal(doc.Table)
+			assert.NoError(t, err)
+
+			got, err := json.Marshal(decoded.Table)
+			assert.NoError(t, err)
+
+			assert.JSONEq(t, string(want), string(got))
+		})
+	}
+}
+
+func TestDocumentImplicitTable(t *testing.T) {
+	doc, source := load(t, "nonewline.toml")
+
+	assert.NoError(t, safeorderedmap.SetPath(doc.Table, "servers.region", "eu"))
+	assert.NoError(t, safeorderedmap.SetPath(doc.Table, "servers.beta.port", 80))
+
+	assert.Equal(t, source+"\nport = 80\n\n[servers]\nregion = \"eu\"\n", marshal(t, doc))
+}
+
+func TestDocumentReordered(t *testing.T) {
+	doc, _ := load(t, "config.toml")
+
+	products, _ := doc.Get("products")
+	array := products.([]any)
+
+	doc.Set("products", []any{array[1], array[0]})
+
+	// Reordered arrays of tables are written as a new document.
+	data := marshal(t, doc)
+
+	assert.Equal(t, marshal(t, NewDocument(doc.Table)), data)
+
+	decoded, err := Unmarshal([]byte(data))
+	assert.NoError(t, err)
+
+	name, err := safeorderedmap.GetPath(decoded.Table, "products")
+	assert.NoError(t, err)
+	assert.Equal(t, "Nail", name.([]any)[0].(*Table).Values()[0])
+}
+
+func TestNewDocument(t *testing.T) {
+	assert.Equal(t, "", marshal(t, NewDocument(nil)))
+	assert.Equal(t, "a = 1\n", marshal(t, NewDocument(safeorderedmap.New[any]().Add("a", 1))))
+}
@@ -0,0 +1,392 @@
+package toml
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/thalesfsp/go-common-types/safeorderedmap"
+)
+
+//////
+// Const, vars, and types.
+//////
+
+// ErrSyntax is returned when a document isn't valid TOML.
+var ErrSyntax = errors.New("toml: syntax error")
+
+// Table is a TOML table, keeping the order of its keys.
+type Table = safeorderedmap.SafeOrderedMap[any]
+
+// LocalDateTime is a date-time without offset, e.g.: 1979-05-27T07:32:00.
+type LocalDateTime string
+
+// LocalDate is a date without time, e.g.: 1979-05-27.
+type LocalDate string
+
+// LocalTime is a time without date, e.g.: 07:32:00.
+type LocalTime string
+
+// encoder writes TOML.
+type encoder struct {
+	w *bytes.Buffer
+
+	// started is set once anything was written, to separate tables.
+	started bool
+}
+
+//////
+// Encoder.
+//////
+
+// isTable checks if the value is written as a table.
+func isTable(value any) bool {
+	switch value.(type) {
+	case *Table, map[string]any:
+		return true
+	default:
+		return false
+	}
+}
+
+// isArrayOfTables checks if the value is a non-empty array of tables.
+func isArrayOfTables(value any) bool {
+	array, ok := value.([]any)
+	if !ok || len(array) == 0 {
+		return false
+	}
+
+	for _, item := range array {
+		if !isTable(item) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// entries returns the entries of a table. Built-in maps are sorted by key.
+func entries(table any) []safeorderedmap.Entry[any] {
+	if t, ok := table.(*Table); ok {
+		return t.Entries()
+	}
+
+	//nolint:forcetypeassert
+	m := table.(map[string]any)
+
+	keys := make([]string, 0, len(m))
+
+	for key := range m {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	result := make([]safeorderedmap.Entry[any], 0, len(m))
+
+	for _, key := range keys {
+		result = append(result, safeorderedmap.Entry[any]{Key: key, Value: m[key]})
+	}
+
+	return result
+}
+
+// table writes the table, under the header, if not empty. TOML requires
+// values to precede sub-tables, so sub-tables, and arrays of tables followed
+// by a value are written inline, keeping the order of the keys.
+func (e *encoder) table(path []string, table any, header string) error {
+	items := entries(table)
+
+	split := 0
+
+	for i, item := range items {
+		if !isTable(item.Value) && !isArrayOfTables(item.Value) {
+			split = i + 1
+		}
+	}
+
+	values, children := items[:split], items[split:]
+
+	// Tables only holding tables are implicit, their header is omitted.
+	if header != "" && (len(values) > 0 || len(children) == 0 || strings.HasPrefix(header, "[[")) {
+		if e.started {
+			e.w.WriteByte('\n')
+		}
+
+		e.w.WriteString(header)
+		e.w.WriteByte('\n')
+
+		e.started = true
+	}
+
+	for _, item := range values {
+		e.w.WriteString(formatKey(item.Key))
+		e.w.WriteString(" = ")
+
+		if err := e.value(item.Value); err != nil {
+			return fmt.Errorf("toml: key %q: %w", item.Key, err)
+		}
+
+		e.w.WriteByte('\n')
+
+		e.started = true
+	}
+
+	for _, item := range children {
+		childPath := append(append([]string{}, path...), item.Key)
+		name := formatPath(childPath)
+
+		if isTable(item.Value) {
+			if err := e.table(childPath, item.Value, "["+name+"]"); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		//nolint:forcetypeassert
+		for _, t := range item.Value.([]any) {
+			if err := e.table(childPath, t, "[["+name+"]]"); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// value writes an inline value.
+func (e *encoder) value(value any) error {
+	switch v := value.(type) {
+	case nil:
+		return errors.New("null isn't supported")
+	case string:
+		e.w.WriteString(quote(v))
+	case bool:
+		e.w.WriteString(strconv.FormatBool(v))
+	case float64:
+		e.w.WriteString(formatFloat(v))
+	case float32:
+		e.w.WriteString(formatFloat(float64(v)))
+	case time.Time:
+		e.w.WriteString(v.Format(time.RFC3339Nano))
+	case LocalDateTime:
+		e.w.WriteString(string(v))
+	case LocalDate:
+		e.w.WriteString(string(v))
+	case LocalTime:
+		e.w.WriteString(string(v))
+	case *Table, map[string]any:
+		items := entries(v)
+
+		e.w.WriteByte('{')
+
+		for i, item := range items {
+			if i > 0 {
+				e.w.WriteByte(',')
+			}
+
+			e.w.WriteByte(' ')
+			e.w.WriteString(formatKey(item.Key))
+			e.w.WriteString(" = ")
+
+			if err := e.value(item.Value); err != nil {
+				return err
+			}
+		}
+
+		if len(items) > 0 {
+			e.w.WriteByte(' ')
+		}
+
+		e.w.WriteByte('}')
+	default:
+		return e.reflectValue(value)
+	}
+
+	return nil
+}
+
+// reflectValue writes integers, and arrays of any type.
+func (e *encoder) reflectValue(value any) error {
+	rv := reflect.ValueOf(value)
+
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		e.w.WriteString(strconv.FormatInt(rv.Int(), 10))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		e.w.WriteString(strconv.FormatUint(rv.Uint(), 10))
+	case reflect.Slice, reflect.Array:
+		e.w.WriteByte('[')
+
+		for i := 0; i < rv.Len(); i++ {
+			if i > 0 {
+				e.w.WriteString(", ")
+			}
+
+			if err := e.value(rv.Index(i).Interface()); err != nil {
+				return err
+			}
+		}
+
+		e.w.WriteByte(']')
+	default:
+		return fmt.Errorf("unsupported type %T", value)
+	}
+
+	return nil
+}
+
+// formatKey writes the key bare if possible, otherwise quoted.
+func formatKey(key string) string {
+	if key == "" {
+		return `""`
+	}
+
+	for i := 0; i < len(key); i++ {
+		if !isBare(key[i]) {
+			return quote(key)
+		}
+	}
+
+	return key
+}
+
+// formatPath formats a dotted table name.
+func formatPath(path []string) string {
+	parts := make([]string, len(path))
+
+	for i, part := range path {
+		parts[i] = formatKey(part)
+	}
+
+	return strings.Join(parts, ".")
+}
+
+// formatFloat formats a float, always with a fraction, or an exponent, so it
+// isn't read back as an integer.
+func formatFloat(f float64) string {
+	switch {
+	case math.IsNaN(f):
+		return "nan"
+	case math.IsInf(f, 1):
+		return "inf"
+	case math.IsInf(f, -1):
+		return "-inf"
+	}
+
+	s := strconv.FormatFloat(f, 'g', -1, 64)
+
+	if !strings.ContainsAny(s, ".eE") {
+		s += ".0"
+	}
+
+	return s
+}
+
+// quote writes a basic string, escaping quotes, backslashes, and control
+// characters.
+func quote(s string) string {
+	var sb strings.Builder
+
+	sb.WriteByte('"')
+
+	for _, r := range s {
+		switch r {
+		case '"':
+			sb.WriteString(`\"`)
+		case '\\':
+			sb.WriteString(`\\`)
+		case '\b':
+			sb.WriteString(`\b`)
+		case '\t':
+			sb.WriteString(`\t`)
+		case '\n':
+			sb.WriteString(`\n`)
+		case '\f':
+			sb.WriteString(`\f`)
+		case '\r':
+			sb.WriteString(`\r`)
+		default:
+			if r < 0x20 || r == 0x7f {
+				fmt.Fprintf(&sb, `\u%04X`, r)
+			} else {
+				sb.WriteRune(r)
+			}
+		}
+	}
+
+	sb.WriteByte('"')
+
+	return sb.String()
+}
+
+//////
+// Decoding.
+//////
+
+// Unmarshal decodes a TOML document, keeping the order of the keys, and the
+// source of its statements, so Marshal writes it back as it was, see
+// Document. Tables are decoded as *Table, arrays, including arrays of tables,
+// as []any, integers as int64, or uint64 if they only fit in it, floats as
+// float64, offset date-times as time.Time, and local ones as LocalDateTime,
+// LocalDate, and LocalTime.
+func Unmarshal(data []byte) (*Document, error) {
+	p := &parser{
+		data:    string(data),
+		line:    1,
+		defined: map[*Table]bool{},
+	}
+
+	return p.document()
+}
+
+// Decode is like Unmarshal, reading the document from r.
+func Decode(r io.Reader) (*Document, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	return Unmarshal(data)
+}
+
+//////
+// Encoding.
+//////
+
+// Encode writes the document to w as TOML. Statements of a decoded document
+// are written as they were, with their comments, and formatting, in their
+// original order, unless their value changed, see Document. Tables of a new
+// document are written as sections, unless followed by a value, e.g.: defined
+// by a dotted key, then they're written inline. Built-in maps are written
+// sorted by key.
+func Encode(w io.Writer, doc *Document) error {
+	e := &encoder{w: &bytes.Buffer{}}
+
+	if err := doc.encode(e); err != nil {
+		return err
+	}
+
+	_, err := w.Write(e.w.Bytes())
+
+	return err
+}
+
+// Marshal is like Encode, returning a byte slice.
+func Marshal(doc *Document) ([]byte, error) {
+	var buf bytes.Buffer
+
+	if err := Encode(&buf, doc); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,196 @@
+package toml
+
+import (
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/thalesfsp/go-common-types/safeorderedmap"
+)
+
+const document = `title = "TOML Example"
+version = 2
+ratio = 0.5
+
+[owner]
+name = "Tom"
+dob = 1979-05-27T07:32:00-08:00
+
+[servers.beta]
+ip = "10.0.0.2"
+ports = [8001, 8002]
+
+[servers.alpha]
+ip = "10.0.0.1"
+enabled = true
+
+[[products]]
+name = "Hammer"
+sku = 738594937
+
+[[products]]
+name = "Nail"
+color = "gray"
+`
+
+func TestRoundTrip(t *testing.T) {
+	table, err := Unmarshal([]byte(document))
+	assert.NoError(t, err)
+
+	assert.Equal(t, []string{"title", "version", "ratio", "owner", "servers", "products"}, table.Keys())
+
+	servers, _ := table.Get("servers")
+	assert.Equal(t, []string{"beta", "alpha"}, servers.(*Table).Keys())
+
+	data, err := Marshal(table)
+	assert.NoError(t, err)
+	assert.Equal(t, document, string(data))
+}
+
+func TestEditSingleKey(t *testing.T) {
+	table, err := Unmarshal([]byte(document))
+	assert.NoError(t, err)
+
+	assert.NoError(t, safeorderedmap.SetPath(table.Table, "servers.alpha.ip", "10.0.0.9"))
+
+	data, err := Marshal(table)
+	assert.NoError(t, err)
+	assert.Equal(t, strings.Replace(document, "10.0.0.1", "10.0.0.9", 1), string(data))
+}
+
+func TestValues(t *testing.T) {
+	table, err := Unmarshal([]byte(`
+# Comment.
+basic = "a\tb \"q\" \u00e9" # Trailing comment.
+literal = 'C:\path'
+multi = """
+one \
+  two"""
+raw = '''
+it's'''
+hex = 0xff
+under = 1_000
+neg = -3
+exp = 1e3
+inf = -inf
+nan = nan
+local = 1979-05-27T07:32:00
+date = 1979-05-27
+time = 07:32:00
+spaced = 1979-05-27 07:32:00Z
+nested = [[1, 2], ["a"]]
+inline = { x = 1, y.z = 2 }
+"quoted key" = 1
+dotted.a.b = true
+`))
+	assert.NoError(t, err)
+
+	for key, expected := range map[string]any{
+		"basic":   "a\tb \"q\" é",
+		"literal": `C:\path`,
+		"multi":   "one two",
+		"raw":     "it's",
+		"hex":     int64(255),
+		"under":   int64(1000),
+		"neg":     int64(-3),
+		"exp":     1000.0,
+		"local":   LocalDateTime("1979-05-27T07:32:00"),
+		"date":    LocalDate("1979-05-27"),
+		"time":    LocalTime("07:32:00"),
+		"spaced":  time.Date(1979, 5, 27, 7, 32, 0, 0, time.UTC),
+		"nested":  []any{[]any{int64(1), int64(2)}, []any{"a"}},
+	} {
+		value, ok := table.Get(key)
+		assert.True(t, ok, key)
+		assert.Equal(t, expected, value, key)
+	}
+
+	inf, _ := table.Get("inf")
+	assert.True(t, math.IsInf(inf.(float64), -1))
+
+	nan, _ := table.Get("nan")
+	assert.True(t, math.IsNaN(nan.(float64)))
+
+	z, err := safeorderedmap.GetPath(table.Table, "inline.y.z")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), z)
+
+	b, err := safeorderedmap.GetPath(table.Table, "dotted.a.b")
+	assert.NoError(t, err)
+	assert.Equal(t, true, b)
+
+	assert.True(t, table.Contains("quoted key"))
+
+	// Re-encoding, and decoding gives the same values.
+	data, err := Marshal(table)
+	assert.NoError(t, err)
+
+	decoded, err := Unmarshal(data)
+	assert.NoError(t, err)
+	assert.Equal(t, table.Keys(), decoded.Keys())
+
+	inline, _ := decoded.Get("inline")
+	assert.Equal(t, []string{"x", "y"}, inline.(*Table).Keys())
+
+	basic, _ := decoded.Get("basic")
+	assert.Equal(t, "a\tb \"q\" é", basic)
+}
+
+func TestEncode(t *testing.T) {
+	table := safeorderedmap.New[any]()
+	table.Add("name", "x").Add("n", 1).Add("f", 2.0).Add("tags", []string{"a", "b"})
+	table.Add("point", map[string]any{"y": 2, "x": 1})
+
+	data, err := Marshal(NewDocument(table))
+	assert.NoError(t, err)
+	assert.Equal(t, `name = "x"
+n = 1
+f = 2.0
+tags = ["a", "b"]
+
+[point]
+x = 1
+y = 2
+`, string(data))
+
+	// Tables followed by values are written inline to keep the order.
+	table.Add("last", true)
+
+	data, err = Marshal(NewDocument(table))
+	assert.NoError(t, err)
+	assert.Equal(t, `name = "x"
+n = 1
+f = 2.0
+tags = ["a", "b"]
+point = { x = 1, y = 2 }
+last = true
+`, string(data))
+
+	_, err = Marshal(NewDocument(safeorderedmap.New[any]().Add("a", nil)))
+	assert.Error(t, err)
+
+	_, err = Marshal(NewDocument(safeorderedmap.New[any]().Add("a", []any{map[string]any{"a": 1}, struct{}{}})))
+	assert.Error(t, err)
+}
+
+func TestErrors(t *testing.T) {
+	for _, doc := range []string{
+		`a = `,
+		`a = 1 b = 2`,
+		"a = 1\na = 2",
+		"[t]\n[t]",
+		`a = "unterminated`,
+		`a = 012`,
+		`a = [1, 2`,
+		`a = { b = 1`,
+		"a = 1\n[a]",
+		"a = 1\n[[a]]",
+		`a = "\q"`,
+		`= 1`,
+	} {
+		_, err := Unmarshal([]byte(doc))
+		assert.ErrorIs(t, err, ErrSyntax, doc)
+	}
+}
@@ -0,0 +1,77 @@
+// Package collection defines the interfaces shared by the collections of this
+// module, and by alternative implementations, e.g.: the Redis-backed ones in
+// the redisstore submodule. Code depending on these interfaces can swap local
+// for distributed storage without call-site changes.
+//
+// NOTE: Interfaces only include methods which don't return the concrete type,
+// so chaining methods (e.g.: Add, Delete) are replaced by their non-chaining
+// counterparts (e.g.: Set, Insert, Append, Remove).
+package collection
+
+//////
+// Const, vars, and types.
+//////
+
+// Collection is implemented by all collections.
+type Collection[T any] interface {
+	// Values returns all elements, in the order of the collection.
+	Values() []T
+
+	// Size returns the number of elements.
+	Size() int
+
+	// Empty checks if there are no elements.
+	Empty() bool
+}
+
+// OrderedMap is a map which preserves the insertion order of keys.
+type OrderedMap[T any] interface {
+	Collection[T]
+
+	// Set a value, keeping the position of existing keys.
+	Set(key string, value T)
+
+	// Get a value.
+	Get(key string) (T, bool)
+
+	// Remove a value, returning whether it was present.
+	Remove(key string) bool
+
+	// Contains checks if the key is present.
+	Contains(key string) bool
+
+	// Keys returns all keys, in insertion order.
+	Keys() []string
+}
+
+// Set is a collection of unique elements.
+type Set[T any] interface {
+	Collection[T]
+
+	// Insert an element, returning whether it wasn't present.
+	Insert(value T) bool
+
+	// Remove an element, returning whether it was present.
+	Remove(value T) bool
+
+	// Contains checks if the element is present.
+	Contains(value T) bool
+}
+
+// List is an indexed sequence of elements.
+type List[T comparable] interface {
+	Collection[T]
+
+	// Append elements to the end of the list.
+	Append(items ...T)
+
+	// Get the element at the given index, the zero value if out of range.
+	Get(index int) T
+
+	// Contains checks if the element is present.
+	Contains(item T) bool
+
+	// Index returns the index of the first occurrence of the element, -1 and
+	// false if not found.
+	Index(item T) (int, bool)
+}
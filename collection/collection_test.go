@@ -0,0 +1,61 @@
+package collection
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/thalesfsp/go-common-types/safeorderedmap"
+	"github.com/thalesfsp/go-common-types/safeset"
+	"github.com/thalesfsp/go-common-types/safeslice"
+)
+
+var (
+	_ OrderedMap[int] = (*safeorderedmap.SafeOrderedMap[int])(nil)
+	_ OrderedMap[int] = (*safeorderedmap.Namespace[int])(nil)
+	_ Set[int]        = (*safeset.SafeSet[int])(nil)
+	_ List[int]       = (*safeslice.SafeSlice[int])(nil)
+)
+
+func TestOrderedMap(t *testing.T) {
+	var m OrderedMap[int] = safeorderedmap.New[int]()
+
+	m.Set("b", 2)
+	m.Set("a", 1)
+	m.Set("b", 3)
+
+	assert.Equal(t, []string{"b", "a"}, m.Keys())
+	assert.Equal(t, []int{3, 1}, m.Values())
+	assert.True(t, m.Remove("b"))
+	assert.False(t, m.Remove("b"))
+	assert.False(t, m.Contains("b"))
+	assert.Equal(t, 1, m.Size())
+}
+
+func TestSet(t *testing.T) {
+	var s Set[string] = safeset.New[string]()
+
+	assert.True(t, s.Insert("a"))
+	assert.False(t, s.Insert("a"))
+	assert.True(t, s.Insert("b"))
+	assert.True(t, s.Remove("a"))
+	assert.False(t, s.Remove("a"))
+	assert.Equal(t, []string{"b"}, s.Values())
+	assert.False(t, s.Empty())
+}
+
+func TestList(t *testing.T) {
+	var l List[int] = safeslice.New[int]()
+
+	l.Append(1, 2, 3)
+
+	values := l.Values()
+	values[0] = 10
+
+	assert.Equal(t, []int{1, 2, 3}, l.Values())
+	assert.Equal(t, 2, l.Get(1))
+
+	i, ok := l.Index(3)
+	assert.True(t, ok)
+	assert.Equal(t, 2, i)
+	assert.Equal(t, 3, l.Size())
+}
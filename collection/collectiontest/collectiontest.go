@@ -0,0 +1,606 @@
+// Package collectiontest implements invariant checks of the collection
+// interfaces, for tests of their implementations, e.g.: new collection types,
+// or wrappers embedding the ones of this module. Suites, e.g.: TestOrderedMap,
+// run all the checks, which are also exported individually, like
+// testing/fstest.
+package collectiontest
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"reflect"
+	"runtime"
+	"sync"
+	"testing"
+
+	"github.com/thalesfsp/go-common-types/collection"
+)
+
+//////
+// Const, vars, and types.
+//////
+
+const (
+	// Workers is the number of goroutines of the concurrent checks.
+	Workers = 8
+
+	// OpsPerWorker is the number of operations per goroutine of the
+	// concurrent checks.
+	OpsPerWorker = 200
+
+	// keySpace is the number of distinct keys, or values, of the operation
+	// sequences, small so operations often hit existing elements.
+	keySpace = 16
+)
+
+// indexed is implemented by lists, whose order is kept by JSON.
+type indexed[T any] interface {
+	Get(index int) T
+}
+
+// Generator returns the i-th value used by the checks. Different indexes must
+// return different values.
+type Generator[T any] func(i int) T
+
+//////
+// Helpers.
+//////
+
+// randomOps returns a random sequence of operations, reproducible by seed.
+func randomOps(seed int64, n int) []byte {
+	ops := make([]byte, n)
+
+	rand.New(rand.NewSource(seed)).Read(ops)
+
+	return ops
+}
+
+// containsValue checks if the value is in values, compared with
+// reflect.DeepEqual.
+func containsValue[T any](values []T, value T) bool {
+	for _, v := range values {
+		if reflect.DeepEqual(v, value) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// sameElements checks if both slices have the same elements, regardless of
+// their order.
+func sameElements[T any](a, b []T) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	used := make([]bool, len(b))
+
+	for _, x := range a {
+		found := false
+
+		for i, y := range b {
+			if !used[i] && reflect.DeepEqual(x, y) {
+				used[i], found = true, true
+
+				break
+			}
+		}
+
+		if !found {
+			return false
+		}
+	}
+
+	return true
+}
+
+// pairs returns the values of the map by key.
+func pairs[T any](m collection.OrderedMap[T]) map[string]T {
+	result := map[string]T{}
+
+	for _, key := range m.Keys() {
+		result[key], _ = m.Get(key)
+	}
+
+	return result
+}
+
+//////
+// Checks.
+//////
+
+// CheckSize checks that Size, Empty, and Values agree, and, for ordered maps,
+// Keys.
+func CheckSize[T any](t testing.TB, c collection.Collection[T]) {
+	t.Helper()
+
+	size := c.Size()
+
+	if values := c.Values(); len(values) != size {
+		t.Errorf("Size is %d, but Values has %d elements", size, len(values))
+	}
+
+	if c.Empty() != (size == 0) {
+		t.Errorf("Empty is %v, but Size is %d", c.Empty(), size)
+	}
+
+	if m, ok := c.(collection.OrderedMap[T]); ok {
+		if keys := m.Keys(); len(keys) != size {
+			t.Errorf("Size is %d, but Keys has %d elements", size, len(keys))
+		}
+	}
+}
+
+// CheckJSONRoundTrip checks that c, marshalled to JSON, and unmarshalled into
+// empty, which must be a pointer, results in the same elements. Lists must
+// keep their order. Ordered maps must keep the value of each key, but not
+// their order, as JSON objects have none, and sets their elements.
+func CheckJSONRoundTrip[T any](t testing.TB, c, empty collection.Collection[T]) {
+	t.Helper()
+
+	b, err := json.Marshal(c)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+
+	if err := json.Unmarshal(b, empty); err != nil {
+		t.Fatalf("Unmarshal %s: %v", b, err)
+	}
+
+	want, got := c.Values(), empty.Values()
+
+	switch m := c.(type) {
+	case collection.OrderedMap[T]:
+		//nolint:forcetypeassert
+		if !reflect.DeepEqual(pairs(m), pairs(empty.(collection.OrderedMap[T]))) {
+			t.Errorf("round trip of %s: got keys %v, and values %v, want %v, and %v",
+				b, empty.(collection.OrderedMap[T]).Keys(), got, m.Keys(), want)
+		}
+	case indexed[T]:
+		if len(want) != len(got) || (len(want) > 0 && !reflect.DeepEqual(want, got)) {
+			t.Errorf("round trip of %s: got %v, want %v", b, got, want)
+		}
+	default:
+		if !sameElements(want, got) {
+			t.Errorf("round trip of %s: got %v, want %v, in any order", b, got, want)
+		}
+	}
+}
+
+// CheckOrderedMapOps applies the sequence of operations encoded in ops, two
+// bytes each, to the empty map m, and to a model, checking results, sizes,
+// and that keys keep their insertion order. Any byte sequence is valid, so
+// it's meant for fuzzing, e.g.: in the function passed to testing.F.Fuzz.
+func CheckOrderedMapOps[T any](t testing.TB, m collection.OrderedMap[T], gen Generator[T], ops []byte) {
+	t.Helper()
+
+	var keys []string
+
+	model := map[string]T{}
+
+	for i := 0; i+1 < len(ops); i += 2 {
+		key := fmt.Sprintf("k%d", ops[i+1]%keySpace)
+		want, present := model[key]
+
+		switch ops[i] % 4 {
+		case 0:
+			value := gen(i)
+
+			m.Set(key, value)
+
+			if !present {
+				keys = append(keys, key)
+			}
+
+			model[key] = value
+		case 1:
+			if got := m.Remove(key); got != present {
+				t.Fatalf("op %d: Remove(%q) = %v, want %v", i/2, key, got, present)
+			}
+
+			if present {
+				delete(model, key)
+
+				for j, k := range keys {
+					if k == key {
+						keys = append(keys[:j], keys[j+1:]...)
+
+						break
+					}
+				}
+			}
+		case 2:
+			got, ok := m.Get(key)
+			if ok != present || (present && !reflect.DeepEqual(got, want)) {
+				t.Fatalf("op %d: Get(%q) = %v, %v, want %v, %v", i/2, key, got, ok, want, present)
+			}
+		default:
+			if got := m.Contains(key); got != present {
+				t.Fatalf("op %d: Contains(%q) = %v, want %v", i/2, key, got, present)
+			}
+		}
+
+		CheckSize[T](t, m)
+	}
+
+	if got := m.Keys(); len(got) != len(keys) || (len(keys) > 0 && !reflect.DeepEqual(got, keys)) {
+		t.Fatalf("Keys = %v, want %v", got, keys)
+	}
+
+	for i, value := range m.Values() {
+		if !reflect.DeepEqual(value, model[keys[i]]) {
+			t.Fatalf("Values[%d] = %v, want %v", i, value, model[keys[i]])
+		}
+	}
+}
+
+// CheckSetOps applies the sequence of operations encoded in ops, two bytes
+// each, to the empty set s, and to a model, checking results, and sizes. The
+// order of the elements isn't checked. Any byte sequence is valid, so it's
+// meant for fuzzing.
+func CheckSetOps[T any](t testing.TB, s collection.Set[T], gen Generator[T], ops []byte) {
+	t.Helper()
+
+	var model []T
+
+	for i := 0; i+1 < len(ops); i += 2 {
+		value := gen(int(ops[i+1] % keySpace))
+		present := containsValue(model, value)
+
+		switch ops[i] % 3 {
+		case 0:
+			if got := s.Insert(value); got == present {
+				t.Fatalf("op %d: Insert(%v) = %v, want %v", i/2, value, got, !present)
+			}
+
+			if !present {
+				model = append(model, value)
+			}
+		case 1:
+			if got := s.Remove(value); got != present {
+				t.Fatalf("op %d: Remove(%v) = %v, want %v", i/2, value, got, present)
+			}
+
+			for j, v := range model {
+				if reflect.DeepEqual(v, value) {
+					model = append(model[:j], model[j+1:]...)
+
+					break
+				}
+			}
+		default:
+			if got := s.Contains(value); got != present {
+				t.Fatalf("op %d: Contains(%v) = %v, want %v", i/2, value, got, present)
+			}
+		}
+
+		CheckSize[T](t, s)
+	}
+
+	if got := s.Values(); !sameElements(got, model) {
+		t.Fatalf("Values = %v, want %v, in any order", got, model)
+	}
+}
+
+// CheckListOps applies the sequence of operations encoded in ops, two bytes
+// each, to the empty list l, and to a model, checking results, sizes, and
+// order. Any byte sequence is valid, so it's meant for fuzzing.
+func CheckListOps[T comparable](t testing.TB, l collection.List[T], gen Generator[T], ops []byte) {
+	t.Helper()
+
+	var model []T
+
+	for i := 0; i+1 < len(ops); i += 2 {
+		value := gen(int(ops[i+1] % keySpace))
+
+		switch ops[i] % 4 {
+		case 0:
+			l.Append(value)
+
+			model = append(model, value)
+		case 1:
+			// Includes out of range indexes, returning the zero value.
+			index := int(ops[i+1]) - 1
+
+			var want T
+
+			if index >= 0 && index < len(model) {
+				want = model[index]
+			}
+
+			if got := l.Get(index); got != want {
+				t.Fatalf("op %d: Get(%d) = %v, want %v", i/2, index, got, want)
+			}
+		case 2:
+			want, found := -1, false
+
+			for j, v := range model {
+				if v == value {
+					want, found = j, true
+
+					break
+				}
+			}
+
+			if got, ok := l.Index(value); got != want || ok != found {
+				t.Fatalf("op %d: Index(%v) = %d, %v, want %d, %v", i/2, value, got, ok, want, found)
+			}
+		default:
+			if got, want := l.Contains(value), containsValue(model, value); got != want {
+				t.Fatalf("op %d: Contains(%v) = %v, want %v", i/2, value, got, want)
+			}
+		}
+
+		CheckSize[T](t, l)
+	}
+
+	if got := l.Values(); len(got) != len(model) || (len(model) > 0 && !reflect.DeepEqual(got, model)) {
+		t.Fatalf("Values = %v, want %v", got, model)
+	}
+}
+
+//////
+// Concurrent checks.
+//
+// Workers operate on disjoint keys, or values, concurrently with readers, so
+// the result of each operation, and the final state, are deterministic. Run
+// them with the race detector.
+
+// readers calls Values, and Size, until done is closed.
+func readers[T any](c collection.Collection[T], done <-chan struct{}, wg *sync.WaitGroup) {
+	for r := 0; r < 2; r++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for {
+				select {
+				case <-done:
+					return
+				default:
+					_ = c.Values()
+					_ = c.Size()
+
+					runtime.Gosched()
+				}
+			}
+		}()
+	}
+}
+
+// concurrently runs f for each worker, with readers of c, and reports the
+// errors of the workers.
+func concurrently[T any](t testing.TB, c collection.Collection[T], f func(w int) error) {
+	t.Helper()
+
+	var (
+		wg     sync.WaitGroup
+		readWG sync.WaitGroup
+		errs   = make([]error, Workers)
+		done   = make(chan struct{})
+	)
+
+	readers(c, done, &readWG)
+
+	for w := 0; w < Workers; w++ {
+		wg.Add(1)
+
+		go func(w int) {
+			defer wg.Done()
+
+			errs[w] = f(w)
+		}(w)
+	}
+
+	wg.Wait()
+	close(done)
+	readWG.Wait()
+
+	for w, err := range errs {
+		if err != nil {
+			t.Errorf("worker %d: %v", w, err)
+		}
+	}
+}
+
+// CheckConcurrentOrderedMap checks that concurrent Set, Get, and Remove, of
+// disjoint keys, on the empty map m, observe their own writes, and leave the
+// map with every key written last by a Set, once.
+func CheckConcurrentOrderedMap[T any](t testing.TB, m collection.OrderedMap[T], gen Generator[T]) {
+	t.Helper()
+
+	concurrently[T](t, m, func(w int) error {
+		for i := 0; i < OpsPerWorker; i++ {
+			key := fmt.Sprintf("w%d-%d", w, i)
+			value := gen(w*OpsPerWorker + i)
+
+			m.Set(key, value)
+
+			if got, ok := m.Get(key); !ok || !reflect.DeepEqual(got, value) {
+				return fmt.Errorf("Get(%q) = %v, %v after Set(%q, %v)", key, got, ok, key, value)
+			}
+
+			// Removes every other key.
+			if i%2 == 1 && !m.Remove(key) {
+				return fmt.Errorf("Remove(%q) = false after Set", key)
+			}
+		}
+
+		return nil
+	})
+
+	CheckSize[T](t, m)
+
+	keys := m.Keys()
+	seen := make(map[string]bool, len(keys))
+
+	for _, key := range keys {
+		if seen[key] {
+			t.Errorf("duplicated key %q", key)
+		}
+
+		seen[key] = true
+	}
+
+	if want := Workers * OpsPerWorker / 2; len(keys) != want {
+		t.Errorf("got %d keys, want %d", len(keys), want)
+	}
+}
+
+// CheckConcurrentSet checks that concurrent Insert, Contains, and Remove, of
+// disjoint values, on the empty set s, observe their own writes, and leave
+// the set with every value inserted, and not removed.
+func CheckConcurrentSet[T any](t testing.TB, s collection.Set[T], gen Generator[T]) {
+	t.Helper()
+
+	concurrently[T](t, s, func(w int) error {
+		for i := 0; i < OpsPerWorker; i++ {
+			value := gen(w*OpsPerWorker + i)
+
+			if !s.Insert(value) {
+				return fmt.Errorf("Insert(%v) = false, the first time", value)
+			}
+
+			if !s.Contains(value) {
+				return fmt.Errorf("Contains(%v) = false after Insert", value)
+			}
+
+			if i%2 == 1 && !s.Remove(value) {
+				return fmt.Errorf("Remove(%v) = false after Insert", value)
+			}
+		}
+
+		return nil
+	})
+
+	CheckSize[T](t, s)
+
+	if got, want := s.Size(), Workers*OpsPerWorker/2; got != want {
+		t.Errorf("got %d elements, want %d", got, want)
+	}
+}
+
+// CheckConcurrentList checks that concurrent Append to the empty list l keeps
+// every element, and the order of the elements appended by each worker.
+func CheckConcurrentList[T comparable](t testing.TB, l collection.List[T], gen Generator[T]) {
+	t.Helper()
+
+	concurrently[T](t, l, func(w int) error {
+		for i := 0; i < OpsPerWorker; i++ {
+			l.Append(gen(w*OpsPerWorker + i))
+		}
+
+		return nil
+	})
+
+	CheckSize[T](t, l)
+
+	next := make([]int, Workers)
+
+	values := l.Values()
+
+	for w := 0; w < Workers; w++ {
+		for _, value := range values {
+			if next[w] < OpsPerWorker && value == gen(w*OpsPerWorker+next[w]) {
+				next[w]++
+			}
+		}
+
+		if next[w] != OpsPerWorker {
+			t.Errorf("worker %d: found %d of %d elements in order", w, next[w], OpsPerWorker)
+		}
+	}
+}
+
+//////
+// Suites.
+//////
+
+// TestOrderedMap runs all the checks against ordered maps created by newMap,
+// which must return an empty map. If it also implements json.Marshaler, and
+// json.Unmarshaler, the JSON round trip is checked.
+func TestOrderedMap[T any](t *testing.T, newMap func() collection.OrderedMap[T], gen Generator[T]) {
+	t.Run("Ops", func(t *testing.T) {
+		for seed := int64(0); seed < 8; seed++ {
+			CheckOrderedMapOps(t, newMap(), gen, randomOps(seed, 512))
+		}
+	})
+
+	t.Run("Concurrent", func(t *testing.T) {
+		CheckConcurrentOrderedMap(t, newMap(), gen)
+	})
+
+	checkJSON[T](t, func(t testing.TB) collection.Collection[T] {
+		m := newMap()
+
+		CheckOrderedMapOps(t, m, gen, randomOps(1, 64))
+
+		return m
+	}, func() collection.Collection[T] { return newMap() })
+}
+
+// TestSet runs all the checks against sets created by newSet, which must
+// return an empty set.
+func TestSet[T any](t *testing.T, newSet func() collection.Set[T], gen Generator[T]) {
+	t.Run("Ops", func(t *testing.T) {
+		for seed := int64(0); seed < 8; seed++ {
+			CheckSetOps(t, newSet(), gen, randomOps(seed, 512))
+		}
+	})
+
+	t.Run("Concurrent", func(t *testing.T) {
+		CheckConcurrentSet(t, newSet(), gen)
+	})
+
+	checkJSON[T](t, func(t testing.TB) collection.Collection[T] {
+		s := newSet()
+
+		CheckSetOps(t, s, gen, randomOps(1, 64))
+
+		return s
+	}, func() collection.Collection[T] { return newSet() })
+}
+
+// TestList runs all the checks against lists created by newList, which must
+// return an empty list.
+func TestList[T comparable](t *testing.T, newList func() collection.List[T], gen Generator[T]) {
+	t.Run("Ops", func(t *testing.T) {
+		for seed := int64(0); seed < 8; seed++ {
+			CheckListOps(t, newList(), gen, randomOps(seed, 512))
+		}
+	})
+
+	t.Run("Concurrent", func(t *testing.T) {
+		CheckConcurrentList(t, newList(), gen)
+	})
+
+	checkJSON[T](t, func(t testing.TB) collection.Collection[T] {
+		l := newList()
+
+		CheckListOps(t, l, gen, randomOps(1, 64))
+
+		return l
+	}, func() collection.Collection[T] { return newList() })
+}
+
+// checkJSON runs CheckJSONRoundTrip as a subtest, if the collection
+// implements json.Marshaler, and json.Unmarshaler.
+func checkJSON[T any](
+	t *testing.T,
+	filled func(t testing.TB) collection.Collection[T],
+	empty func() collection.Collection[T],
+) {
+	_, marshaler := empty().(json.Marshaler)
+	_, unmarshaler := empty().(json.Unmarshaler)
+
+	if !marshaler || !unmarshaler {
+		return
+	}
+
+	t.Run("JSON", func(t *testing.T) {
+		CheckJSONRoundTrip(t, filled(t), empty())
+	})
+}
@@ -0,0 +1,134 @@
+package collectiontest
+
+import (
+	"fmt"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/thalesfsp/go-common-types/collection"
+	"github.com/thalesfsp/go-common-types/safeexpiringset"
+	"github.com/thalesfsp/go-common-types/safeorderedmap"
+	"github.com/thalesfsp/go-common-types/safeset"
+	"github.com/thalesfsp/go-common-types/safeslice"
+)
+
+func intGen(i int) int { return i }
+
+func stringGen(i int) string { return fmt.Sprintf("v%d", i) }
+
+func newOrderedMap() collection.OrderedMap[string] {
+	return safeorderedmap.New[string]()
+}
+
+func newSet() collection.Set[int] {
+	return safeset.New[int]()
+}
+
+func newList() collection.List[int] {
+	return safeslice.New[int]()
+}
+
+func TestSafeOrderedMap(t *testing.T) {
+	TestOrderedMap(t, newOrderedMap, stringGen)
+}
+
+func TestSafeOrderedMapNamespace(t *testing.T) {
+	TestOrderedMap(t, func() collection.OrderedMap[string] {
+		m := safeorderedmap.New[string]()
+		m.Add("other", "v")
+
+		return m.Namespace("ns/")
+	}, stringGen)
+}
+
+func TestSafeSet(t *testing.T) {
+	TestSet(t, newSet, intGen)
+}
+
+func TestSafeExpiringSet(t *testing.T) {
+	TestSet(t, func() collection.Set[string] {
+		return safeexpiringset.New[string](time.Hour)
+	}, stringGen)
+}
+
+func TestSafeSlice(t *testing.T) {
+	TestList(t, newList, intGen)
+}
+
+func TestSafeSliceSlabs(t *testing.T) {
+	TestList(t, func() collection.List[int] {
+		return safeslice.NewWithOptions(safeslice.WithSlabs[int](4))
+	}, intGen)
+}
+
+// brokenList loses the elements appended after the fourth one.
+type brokenList struct {
+	collection.List[int]
+}
+
+func (l brokenList) Append(items ...int) {
+	for _, item := range items {
+		if l.Size() < 4 {
+			l.List.Append(item)
+		}
+	}
+}
+
+// recorder is a testing.TB recording failures.
+type recorder struct {
+	testing.TB
+
+	failed bool
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Errorf(string, ...any) { r.failed = true }
+
+func (r *recorder) Fatalf(string, ...any) {
+	r.failed = true
+
+	runtime.Goexit()
+}
+
+func TestCheckListOpsFails(t *testing.T) {
+	r := &recorder{TB: t}
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		CheckListOps[int](r, brokenList{safeslice.New[int]()}, intGen, randomOps(0, 512))
+	}()
+
+	<-done
+
+	if !r.failed {
+		t.Error("expected the check to fail")
+	}
+}
+
+func FuzzSafeOrderedMap(f *testing.F) {
+	f.Add(randomOps(0, 64))
+
+	f.Fuzz(func(t *testing.T, ops []byte) {
+		CheckOrderedMapOps(t, newOrderedMap(), stringGen, ops)
+	})
+}
+
+func FuzzSafeSet(f *testing.F) {
+	f.Add(randomOps(0, 64))
+
+	f.Fuzz(func(t *testing.T, ops []byte) {
+		CheckSetOps(t, newSet(), intGen, ops)
+	})
+}
+
+func FuzzSafeSlice(f *testing.F) {
+	f.Add(randomOps(0, 64))
+
+	f.Fuzz(func(t *testing.T, ops []byte) {
+		CheckListOps(t, newList(), intGen, ops)
+	})
+}
@@ -0,0 +1,132 @@
+// Package crdt provides conflict-free replicated data types: collections
+// replicated across nodes, which can be modified concurrently, and merged in
+// any order, converging to the same state, without coordination.
+package crdt
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+//////
+// Const, vars, and types.
+//////
+
+// Timestamp orders the writes of the replicas: by time, in nanoseconds since
+// the Unix epoch, then by node, so concurrent writes have a deterministic
+// winner. It's encoded as text, e.g.: "1697443200000000000@node-a".
+type Timestamp struct {
+	Time int64
+	Node string
+}
+
+// Option allows to configure a replica.
+type Option func(c *clock)
+
+// clock issues the timestamps of a replica. Timestamps are strictly
+// increasing, even if the wall clock goes backwards, and follow the ones
+// observed from other replicas, like a hybrid logical clock, so a write
+// happening after a merge wins over the merged ones.
+type clock struct {
+	node string
+	now  func() time.Time
+	last int64
+}
+
+//////
+// Methods.
+//////
+
+// Compare returns -1, 0, or 1, comparing the timestamps by time, then node.
+func (t Timestamp) Compare(other Timestamp) int {
+	switch {
+	case t.Time < other.Time:
+		return -1
+	case t.Time > other.Time:
+		return 1
+	default:
+		return strings.Compare(t.Node, other.Node)
+	}
+}
+
+// After checks if the timestamp follows the other.
+func (t Timestamp) After(other Timestamp) bool {
+	return t.Compare(other) > 0
+}
+
+// String is the stringer implementation, e.g.: "1697443200000000000@node-a".
+func (t Timestamp) String() string {
+	return strconv.FormatInt(t.Time, 10) + "@" + t.Node
+}
+
+// MarshalText implements the encoding.TextMarshaler interface, see String.
+func (t Timestamp) MarshalText() ([]byte, error) {
+	return []byte(t.String()), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface.
+func (t *Timestamp) UnmarshalText(text []byte) error {
+	s, node, ok := strings.Cut(string(text), "@")
+	if !ok {
+		return fmt.Errorf("invalid timestamp %q, expected time@node", text)
+	}
+
+	n, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid timestamp %q: %w", text, err)
+	}
+
+	*t = Timestamp{Time: n, Node: node}
+
+	return nil
+}
+
+// tick returns the timestamp of a new write. Caller must hold the write lock.
+func (c *clock) tick() Timestamp {
+	now := c.now
+	if now == nil {
+		now = time.Now
+	}
+
+	n := now().UnixNano()
+	if n <= c.last {
+		n = c.last + 1
+	}
+
+	c.last = n
+
+	return Timestamp{Time: n, Node: c.node}
+}
+
+// observe advances the clock past a timestamp of another replica. Caller must
+// hold the write lock.
+func (c *clock) observe(t Timestamp) {
+	if t.Time > c.last {
+		c.last = t.Time
+	}
+}
+
+//////
+// Factory.
+//////
+
+// WithClock sets the wall clock of the replica, time.Now by default, e.g.:
+// for tests.
+func WithClock(now func() time.Time) Option {
+	return func(c *clock) {
+		c.now = now
+	}
+}
+
+// newClock creates the clock of the node.
+func newClock(node string, opts ...Option) clock {
+	c := clock{node: node, now: time.Now}
+
+	for _, opt := range opts {
+		opt(&c)
+	}
+
+	return c
+}
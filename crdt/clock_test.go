@@ -0,0 +1,47 @@
+package crdt
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTimestamp(t *testing.T) {
+	a := Timestamp{Time: 1, Node: "a"}
+	b := Timestamp{Time: 1, Node: "b"}
+
+	assert.Equal(t, -1, a.Compare(b))
+	assert.True(t, Timestamp{Time: 2, Node: "a"}.After(b))
+	assert.Equal(t, 0, a.Compare(a))
+
+	data, err := json.Marshal(a)
+	assert.NoError(t, err)
+	assert.Equal(t, `"1@a"`, string(data))
+
+	var decoded Timestamp
+
+	assert.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, a, decoded)
+
+	assert.Error(t, decoded.UnmarshalText([]byte("1")))
+	assert.Error(t, decoded.UnmarshalText([]byte("x@a")))
+}
+
+func TestClockMonotonic(t *testing.T) {
+	now := time.Unix(0, 100)
+
+	c := newClock("a", WithClock(func() time.Time { return now }))
+
+	assert.Equal(t, int64(100), c.tick().Time)
+	assert.Equal(t, int64(101), c.tick().Time)
+
+	now = time.Unix(0, 50)
+
+	assert.Equal(t, int64(102), c.tick().Time)
+
+	c.observe(Timestamp{Time: 500, Node: "b"})
+
+	assert.Equal(t, int64(501), c.tick().Time)
+}
@@ -0,0 +1,290 @@
+package crdt
+
+import (
+	"encoding/json"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/thalesfsp/go-common-types/safeorderedmap"
+)
+
+//////
+// Const, vars, and types.
+//////
+
+// register is the state of a key of a LWWMap: its last write.
+type register[T any] struct {
+	Value T `json:"v,omitempty"`
+
+	// Stamp is the timestamp of the last write, deciding the winner.
+	Stamp Timestamp `json:"t"`
+
+	// Order is the timestamp of the write which added the key, deciding its
+	// position.
+	Order Timestamp `json:"o"`
+
+	// Deleted marks a tombstone, so the delete wins over older writes.
+	Deleted bool `json:"d,omitempty"`
+}
+
+// LWWMap is a last-writer-wins ordered map, safe for concurrent use: every
+// key holds the value of its write with the highest Timestamp, across
+// replicas. Deletes are kept as tombstones, so they win over older writes
+// being merged, see Prune.
+//
+// Keys are ordered by the timestamp of their addition, so all replicas agree
+// on the order. Updates keep the position, and keys deleted, then added
+// again, move to the end.
+//
+// Each replica must have a unique node name.
+type LWWMap[T any] struct {
+	sync.RWMutex
+
+	clock     clock
+	registers map[string]register[T]
+}
+
+//////
+// Methods.
+//////
+
+// Node returns the name of the replica.
+func (m *LWWMap[T]) Node() string {
+	return m.clock.node
+}
+
+// Set sets the value of the key.
+func (m *LWWMap[T]) Set(key string, value T) *LWWMap[T] {
+	m.Lock()
+	defer m.Unlock()
+
+	stamp := m.clock.tick()
+
+	order := stamp
+
+	if r, ok := m.registers[key]; ok && !r.Deleted {
+		order = r.Order
+	}
+
+	m.registers[key] = register[T]{Value: value, Stamp: stamp, Order: order}
+
+	return m
+}
+
+// Get returns the value of the key, and whether it's present.
+func (m *LWWMap[T]) Get(key string) (T, bool) {
+	m.RLock()
+	defer m.RUnlock()
+
+	r, ok := m.registers[key]
+	if !ok || r.Deleted {
+		var zero T
+
+		return zero, false
+	}
+
+	return r.Value, true
+}
+
+// Contains checks if the key is present.
+func (m *LWWMap[T]) Contains(key string) bool {
+	_, ok := m.Get(key)
+
+	return ok
+}
+
+// Delete deletes the key, returning whether it was present.
+func (m *LWWMap[T]) Delete(key string) bool {
+	m.Lock()
+	defer m.Unlock()
+
+	r, ok := m.registers[key]
+	if !ok || r.Deleted {
+		return false
+	}
+
+	m.registers[key] = register[T]{Stamp: m.clock.tick(), Order: r.Order, Deleted: true}
+
+	return true
+}
+
+// Size returns the number of keys present.
+func (m *LWWMap[T]) Size() int {
+	m.RLock()
+	defer m.RUnlock()
+
+	n := 0
+
+	for _, r := range m.registers {
+		if !r.Deleted {
+			n++
+		}
+	}
+
+	return n
+}
+
+// Entries returns the key-value pairs, in order.
+func (m *LWWMap[T]) Entries() []safeorderedmap.Entry[T] {
+	m.RLock()
+	defer m.RUnlock()
+
+	type ordered struct {
+		order Timestamp
+		entry safeorderedmap.Entry[T]
+	}
+
+	live := make([]ordered, 0, len(m.registers))
+
+	for key, r := range m.registers {
+		if !r.Deleted {
+			live = append(live, ordered{order: r.Order, entry: safeorderedmap.Entry[T]{Key: key, Value: r.Value}})
+		}
+	}
+
+	sort.Slice(live, func(i, j int) bool {
+		if c := live[i].order.Compare(live[j].order); c != 0 {
+			return c < 0
+		}
+
+		return live[i].entry.Key < live[j].entry.Key
+	})
+
+	entries := make([]safeorderedmap.Entry[T], len(live))
+
+	for i, o := range live {
+		entries[i] = o.entry
+	}
+
+	return entries
+}
+
+// Keys returns the keys, in order.
+func (m *LWWMap[T]) Keys() []string {
+	entries := m.Entries()
+
+	keys := make([]string, len(entries))
+
+	for i, entry := range entries {
+		keys[i] = entry.Key
+	}
+
+	return keys
+}
+
+// ToOrderedMap returns a copy of the entries, in order, as a SafeOrderedMap.
+func (m *LWWMap[T]) ToOrderedMap(opts ...safeorderedmap.Option[T]) *safeorderedmap.SafeOrderedMap[T] {
+	return safeorderedmap.FromEntries(m.Entries(), opts...)
+}
+
+// Merge merges the state of the remote replica: for every key, the write
+// with the highest timestamp wins. Merging is commutative, associative, and
+// idempotent, so replicas merging each other's state, in any order, converge.
+func (m *LWWMap[T]) Merge(remote *LWWMap[T]) *LWWMap[T] {
+	remote.RLock()
+
+	registers := make(map[string]register[T], len(remote.registers))
+
+	for key, r := range remote.registers {
+		registers[key] = r
+	}
+
+	remote.RUnlock()
+
+	m.merge(registers)
+
+	return m
+}
+
+// MergeJSON merges the state of a remote replica, encoded by MarshalJSON,
+// e.g.: received from another node.
+func (m *LWWMap[T]) MergeJSON(data []byte) error {
+	registers := map[string]register[T]{}
+
+	if err := json.Unmarshal(data, &registers); err != nil {
+		return err
+	}
+
+	m.merge(registers)
+
+	return nil
+}
+
+// merge merges the registers of a remote replica.
+func (m *LWWMap[T]) merge(registers map[string]register[T]) {
+	m.Lock()
+	defer m.Unlock()
+
+	for key, r := range registers {
+		m.clock.observe(r.Stamp)
+
+		if local, ok := m.registers[key]; !ok || r.Stamp.After(local.Stamp) {
+			m.registers[key] = r
+		}
+	}
+}
+
+// Prune removes the tombstones of the keys deleted before the time, returning
+// how many. It's only safe once all replicas have merged the deletes, and no
+// older write can be merged anymore, otherwise deleted keys can reappear.
+func (m *LWWMap[T]) Prune(before time.Time) int {
+	m.Lock()
+	defer m.Unlock()
+
+	pruned := 0
+
+	for key, r := range m.registers {
+		if r.Deleted && r.Stamp.Time < before.UnixNano() {
+			delete(m.registers, key)
+
+			pruned++
+		}
+	}
+
+	return pruned
+}
+
+// MarshalJSON implements the json.Marshaler interface, encoding the state of
+// the replica, tombstones included, to be merged by another one, see
+// MergeJSON, e.g.: {"a":{"v":1,"t":"1697443200000000000@node-a","o":...}}.
+func (m *LWWMap[T]) MarshalJSON() ([]byte, error) {
+	m.RLock()
+	defer m.RUnlock()
+
+	return json.Marshal(m.registers)
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface, replacing the
+// state of the replica, e.g.: to restore it. Use MergeJSON to merge the state
+// of another replica instead.
+func (m *LWWMap[T]) UnmarshalJSON(data []byte) error {
+	registers := map[string]register[T]{}
+
+	if err := json.Unmarshal(data, &registers); err != nil {
+		return err
+	}
+
+	m.Lock()
+	defer m.Unlock()
+
+	m.registers = registers
+
+	for _, r := range registers {
+		m.clock.observe(r.Stamp)
+	}
+
+	return nil
+}
+
+//////
+// Factory.
+//////
+
+// NewLWWMap creates the replica of a LWWMap, with a unique node name.
+func NewLWWMap[T any](node string, opts ...Option) *LWWMap[T] {
+	return &LWWMap[T]{
+		clock:     newClock(node, opts...),
+		registers: make(map[string]register[T]),
+	}
+}
@@ -0,0 +1,121 @@
+package crdt
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeClock returns a clock advancing by one nanosecond on every call.
+func fakeClock(start int64) Option {
+	n := start
+
+	return WithClock(func() time.Time {
+		n++
+
+		return time.Unix(0, n)
+	})
+}
+
+func TestLWWMap(t *testing.T) {
+	m := NewLWWMap[int]("a")
+
+	m.Set("x", 1).Set("y", 2).Set("x", 3)
+
+	v, ok := m.Get("x")
+	assert.True(t, ok)
+	assert.Equal(t, 3, v)
+	assert.Equal(t, []string{"x", "y"}, m.Keys())
+	assert.Equal(t, 2, m.Size())
+
+	assert.True(t, m.Delete("x"))
+	assert.False(t, m.Delete("x"))
+	assert.False(t, m.Contains("x"))
+
+	m.Set("x", 4)
+
+	assert.Equal(t, []string{"y", "x"}, m.Keys())
+	assert.Equal(t, []string{"y", "x"}, m.ToOrderedMap().Keys())
+	assert.Equal(t, "a", m.Node())
+}
+
+func TestLWWMapMerge(t *testing.T) {
+	a := NewLWWMap[string]("a", fakeClock(0))
+	b := NewLWWMap[string]("b", fakeClock(10))
+
+	a.Set("host", "a.local").Set("port", "80")
+	b.Set("host", "b.local").Set("debug", "true")
+
+	// Concurrent write of the same key: the later one wins.
+	a.Merge(b)
+	b.Merge(a)
+
+	assert.Equal(t, a.Entries(), b.Entries())
+
+	host, _ := a.Get("host")
+	assert.Equal(t, "b.local", host)
+
+	// A delete after the merge wins over the older write.
+	a.Delete("host")
+
+	b.Merge(a).Merge(a)
+
+	assert.False(t, b.Contains("host"))
+	assert.Equal(t, a.Entries(), b.Entries())
+	assert.Equal(t, []string{"port", "debug"}, b.Keys())
+}
+
+func TestLWWMapMergeAfterObserving(t *testing.T) {
+	// a's wall clock is behind, but a write following a merge still wins.
+	a := NewLWWMap[int]("a", fakeClock(0))
+	b := NewLWWMap[int]("b", fakeClock(1000))
+
+	b.Set("k", 1)
+	a.Merge(b).Set("k", 2)
+	b.Merge(a)
+
+	v, _ := b.Get("k")
+	assert.Equal(t, 2, v)
+}
+
+func TestLWWMapJSON(t *testing.T) {
+	a := NewLWWMap[int]("a", fakeClock(0))
+
+	a.Set("x", 1).Set("y", 2).Delete("y")
+
+	data, err := json.Marshal(a)
+	assert.NoError(t, err)
+
+	b := NewLWWMap[int]("b", fakeClock(100))
+	b.Set("z", 3)
+
+	assert.NoError(t, b.MergeJSON(data))
+	assert.Equal(t, []string{"x", "z"}, b.Keys())
+
+	// The tombstone is merged too.
+	b.MergeJSON([]byte(`{"y":{"v":5,"t":"1@c","o":"1@c"}}`))
+	assert.False(t, b.Contains("y"))
+
+	restored := NewLWWMap[int]("a")
+
+	assert.NoError(t, json.Unmarshal(data, restored))
+	assert.Equal(t, a.Entries(), restored.Entries())
+
+	restored.Set("w", 0)
+	assert.Equal(t, []string{"x", "w"}, restored.Keys())
+
+	assert.Error(t, b.MergeJSON([]byte(`[]`)))
+}
+
+func TestLWWMapPrune(t *testing.T) {
+	m := NewLWWMap[int]("a", fakeClock(0))
+
+	m.Set("x", 1).Set("y", 2)
+	m.Delete("x")
+
+	assert.Equal(t, 0, m.Prune(time.Unix(0, 1)))
+	assert.Equal(t, 1, m.Prune(time.Unix(0, 100)))
+	assert.Equal(t, []string{"y"}, m.Keys())
+}
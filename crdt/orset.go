@@ -0,0 +1,273 @@
+package crdt
+
+import (
+	"encoding/json"
+	"sync"
+)
+
+//////
+// Const, vars, and types.
+//////
+
+// dots are the additions of an element, as the counter of the addition by
+// node. An element has at most one per node, its latest.
+type dots map[string]uint64
+
+// orsetElement is the JSON representation of an element of an ORSet.
+type orsetElement[T comparable] struct {
+	Value T    `json:"v"`
+	Dots  dots `json:"d"`
+}
+
+// orsetState is the JSON representation of an ORSet.
+type orsetState[T comparable] struct {
+	Clock    map[string]uint64 `json:"c"`
+	Elements []orsetElement[T] `json:"e"`
+}
+
+// ORSet is an observed-remove set, safe for concurrent use: an element added
+// concurrently with its removal, on another replica, is kept, i.e.: adds win,
+// and a remove only affects the additions it has observed.
+//
+// Every addition is tagged with the node, and a per-node counter, and the
+// version vector of the replica, the latest counter seen per node, tells
+// apart additions removed, from the ones not seen yet, so no tombstones are
+// kept, and the state only grows with the elements, and nodes.
+//
+// Each replica must have a unique node name.
+type ORSet[T comparable] struct {
+	sync.RWMutex
+
+	node     string
+	clock    map[string]uint64
+	elements map[T]dots
+}
+
+//////
+// Methods.
+//////
+
+// Node returns the name of the replica.
+func (s *ORSet[T]) Node() string {
+	return s.node
+}
+
+// Add adds the elements.
+func (s *ORSet[T]) Add(values ...T) *ORSet[T] {
+	s.Lock()
+	defer s.Unlock()
+
+	for _, v := range values {
+		s.clock[s.node]++
+
+		// The new addition supersedes the ones observed.
+		s.elements[v] = dots{s.node: s.clock[s.node]}
+	}
+
+	return s
+}
+
+// Remove removes the element, returning whether it was present. Additions
+// of the element not observed yet, e.g.: concurrent ones, survive the
+// removal when merged.
+func (s *ORSet[T]) Remove(value T) bool {
+	s.Lock()
+	defer s.Unlock()
+
+	_, ok := s.elements[value]
+
+	delete(s.elements, value)
+
+	return ok
+}
+
+// Contains checks if the element is present.
+func (s *ORSet[T]) Contains(value T) bool {
+	s.RLock()
+	defer s.RUnlock()
+
+	_, ok := s.elements[value]
+
+	return ok
+}
+
+// Size returns the number of elements.
+func (s *ORSet[T]) Size() int {
+	s.RLock()
+	defer s.RUnlock()
+
+	return len(s.elements)
+}
+
+// Values returns the elements, in no particular order.
+func (s *ORSet[T]) Values() []T {
+	s.RLock()
+	defer s.RUnlock()
+
+	values := make([]T, 0, len(s.elements))
+
+	for v := range s.elements {
+		values = append(values, v)
+	}
+
+	return values
+}
+
+// Merge merges the state of the remote replica: an addition is kept if both
+// replicas have it, or if the other one hasn't observed it, otherwise it was
+// removed. Merging is commutative, associative, and idempotent, so replicas
+// merging each other's state, in any order, converge.
+func (s *ORSet[T]) Merge(remote *ORSet[T]) *ORSet[T] {
+	s.merge(remote.state())
+
+	return s
+}
+
+// MergeJSON merges the state of a remote replica, encoded by MarshalJSON,
+// e.g.: received from another node.
+func (s *ORSet[T]) MergeJSON(data []byte) error {
+	var state orsetState[T]
+
+	if err := json.Unmarshal(data, &state); err != nil {
+		return err
+	}
+
+	s.merge(state)
+
+	return nil
+}
+
+// state returns a copy of the state.
+func (s *ORSet[T]) state() orsetState[T] {
+	s.RLock()
+	defer s.RUnlock()
+
+	state := orsetState[T]{
+		Clock:    make(map[string]uint64, len(s.clock)),
+		Elements: make([]orsetElement[T], 0, len(s.elements)),
+	}
+
+	for node, n := range s.clock {
+		state.Clock[node] = n
+	}
+
+	for v, d := range s.elements {
+		copied := make(dots, len(d))
+
+		for node, n := range d {
+			copied[node] = n
+		}
+
+		state.Elements = append(state.Elements, orsetElement[T]{Value: v, Dots: copied})
+	}
+
+	return state
+}
+
+// merge merges the state of a remote replica.
+func (s *ORSet[T]) merge(remote orsetState[T]) {
+	s.Lock()
+	defer s.Unlock()
+
+	seen := make(map[T]bool, len(remote.Elements))
+
+	for _, e := range remote.Elements {
+		seen[e.Value] = true
+
+		merged := dots{}
+
+		for node, n := range s.elements[e.Value] {
+			// Kept by both, or not observed by the remote replica.
+			if e.Dots[node] == n || n > remote.Clock[node] {
+				merged[node] = n
+			}
+		}
+
+		for node, n := range e.Dots {
+			// Not observed by this replica.
+			if n > s.clock[node] && n > merged[node] {
+				merged[node] = n
+			}
+		}
+
+		if len(merged) == 0 {
+			delete(s.elements, e.Value)
+		} else {
+			s.elements[e.Value] = merged
+		}
+	}
+
+	// Elements missing from the remote replica, which it has observed, were
+	// removed there.
+	for v, d := range s.elements {
+		if seen[v] {
+			continue
+		}
+
+		for node, n := range d {
+			if n <= remote.Clock[node] {
+				delete(d, node)
+			}
+		}
+
+		if len(d) == 0 {
+			delete(s.elements, v)
+		}
+	}
+
+	for node, n := range remote.Clock {
+		if n > s.clock[node] {
+			s.clock[node] = n
+		}
+	}
+}
+
+// MarshalJSON implements the json.Marshaler interface, encoding the state of
+// the replica, to be merged by another one, see MergeJSON, e.g.:
+// {"c":{"node-a":2},"e":[{"v":"x","d":{"node-a":2}}]}.
+func (s *ORSet[T]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.state())
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface, replacing the
+// state of the replica, e.g.: to restore it. Use MergeJSON to merge the state
+// of another replica instead.
+func (s *ORSet[T]) UnmarshalJSON(data []byte) error {
+	var state orsetState[T]
+
+	if err := json.Unmarshal(data, &state); err != nil {
+		return err
+	}
+
+	s.Lock()
+	defer s.Unlock()
+
+	s.clock = state.Clock
+	if s.clock == nil {
+		s.clock = make(map[string]uint64)
+	}
+
+	s.elements = make(map[T]dots, len(state.Elements))
+
+	for _, e := range state.Elements {
+		s.elements[e.Value] = e.Dots
+	}
+
+	return nil
+}
+
+//////
+// Factory.
+//////
+
+// NewORSet creates the replica of an ORSet, with a unique node name, and the
+// elements.
+func NewORSet[T comparable](node string, values ...T) *ORSet[T] {
+	s := &ORSet[T]{
+		node:     node,
+		clock:    make(map[string]uint64),
+		elements: make(map[T]dots),
+	}
+
+	return s.Add(values...)
+}
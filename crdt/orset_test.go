@@ -0,0 +1,97 @@
+package crdt
+
+import (
+	"encoding/json"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// sorted returns the values of the set, sorted.
+func sorted(s *ORSet[string]) []string {
+	values := s.Values()
+
+	sort.Strings(values)
+
+	return values
+}
+
+func TestORSet(t *testing.T) {
+	s := NewORSet("a", "x", "y")
+
+	assert.True(t, s.Contains("x"))
+	assert.Equal(t, 2, s.Size())
+	assert.True(t, s.Remove("x"))
+	assert.False(t, s.Remove("x"))
+	assert.Equal(t, []string{"y"}, sorted(s))
+	assert.Equal(t, "a", s.Node())
+}
+
+func TestORSetMerge(t *testing.T) {
+	a := NewORSet("a", "x", "y")
+	b := NewORSet[string]("b")
+
+	b.Merge(a)
+	assert.Equal(t, []string{"x", "y"}, sorted(b))
+
+	// Concurrent: a removes x, b adds x again, and removes y.
+	a.Remove("x")
+	b.Add("x", "z")
+	b.Remove("y")
+
+	a.Merge(b)
+	b.Merge(a)
+
+	// The concurrent add wins over the remove, and the observed remove of y
+	// is kept.
+	assert.Equal(t, []string{"x", "z"}, sorted(a))
+	assert.Equal(t, sorted(a), sorted(b))
+
+	// Idempotent.
+	a.Merge(b).Merge(b)
+	assert.Equal(t, []string{"x", "z"}, sorted(a))
+
+	// A remove after observing all the additions wins.
+	a.Remove("x")
+	b.Merge(a)
+	assert.Equal(t, []string{"z"}, sorted(b))
+}
+
+func TestORSetMergeRemoveNotReappearing(t *testing.T) {
+	a := NewORSet("a", "x")
+	b := NewORSet[string]("b").Merge(a)
+
+	b.Remove("x")
+
+	// a still has x, but b's removal observed it.
+	a.Merge(b)
+	assert.False(t, a.Contains("x"))
+
+	b.Merge(a)
+	assert.False(t, b.Contains("x"))
+}
+
+func TestORSetJSON(t *testing.T) {
+	a := NewORSet("a", "x", "y")
+	a.Remove("y")
+
+	data, err := json.Marshal(a)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"c":{"a":2},"e":[{"v":"x","d":{"a":1}}]}`, string(data))
+
+	b := NewORSet("b", "y")
+
+	assert.NoError(t, b.MergeJSON(data))
+	assert.Equal(t, []string{"x", "y"}, sorted(b))
+
+	restored := NewORSet[string]("a")
+
+	assert.NoError(t, json.Unmarshal(data, restored))
+	assert.Equal(t, []string{"x"}, sorted(restored))
+
+	restored.Add("w")
+	assert.Equal(t, []string{"w", "x"}, sorted(restored))
+
+	assert.Error(t, b.MergeJSON([]byte(`[]`)))
+}
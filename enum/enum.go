@@ -0,0 +1,238 @@
+// Package enum provides string-backed enums: the valid values of a string
+// type, with validation, parsing, and marshaling helpers.
+package enum
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/thalesfsp/go-common-types/safeset"
+)
+
+//////
+// Const, vars, and types.
+//////
+
+// ErrInvalid is returned for values which aren't part of the enum.
+var ErrInvalid = errors.New("invalid enum value")
+
+// ErrUndefined is returned by the package-level functions for types without
+// an enum, see New.
+var ErrUndefined = errors.New("enum not defined")
+
+// registry holds the enums, by type.
+var registry sync.Map
+
+// Enum is the definition of a string-backed enum: the valid values of a
+// string type E, in declaration order, e.g.:
+//
+//	type Color string
+//
+//	var Colors = enum.New[Color]("red", "green", "blue")
+//
+// To validate when decoding, the type delegates to the enum, e.g.:
+//
+//	func (c *Color) UnmarshalText(text []byte) error {
+//		return Colors.UnmarshalText(c, text)
+//	}
+//
+// which is also used by encoding/json.
+type Enum[E ~string] struct {
+	name   string
+	values *safeset.SafeSet[E]
+}
+
+//////
+// Methods.
+//////
+
+// invalid returns the error of an invalid value.
+func (e *Enum[E]) invalid(value string) error {
+	valid := make([]string, 0, e.values.Size())
+
+	for _, v := range e.values.Values() {
+		valid = append(valid, string(v))
+	}
+
+	return fmt.Errorf("%w %q for %s, expected one of: %s", ErrInvalid, value, e.name, strings.Join(valid, ", "))
+}
+
+// Name returns the name of the type, e.g.: "Color".
+func (e *Enum[E]) Name() string {
+	return e.name
+}
+
+// Values returns the valid values, in declaration order.
+func (e *Enum[E]) Values() []E {
+	return e.values.Values()
+}
+
+// Set returns a read-only view of the valid values, in declaration order.
+func (e *Enum[E]) Set() safeset.ReadOnly[E] {
+	return e.values.ReadOnly()
+}
+
+// Valid checks if the value is part of the enum.
+func (e *Enum[E]) Valid(value E) bool {
+	return e.values.Contains(value)
+}
+
+// Validate returns ErrInvalid, listing the valid values, if the value isn't
+// part of the enum.
+func (e *Enum[E]) Validate(value E) error {
+	if !e.Valid(value) {
+		return e.invalid(string(value))
+	}
+
+	return nil
+}
+
+// Parse returns the value, if part of the enum.
+func (e *Enum[E]) Parse(s string) (E, error) {
+	if err := e.Validate(E(s)); err != nil {
+		return "", err
+	}
+
+	return E(s), nil
+}
+
+// MustParse is like Parse, panicking on error, e.g.: for constants.
+func (e *Enum[E]) MustParse(s string) E {
+	value, err := e.Parse(s)
+	if err != nil {
+		panic(err)
+	}
+
+	return value
+}
+
+// MarshalText returns the text of the value, if part of the enum, e.g.: to
+// implement encoding.TextMarshaler, preventing invalid values from being
+// encoded.
+func (e *Enum[E]) MarshalText(value E) ([]byte, error) {
+	if err := e.Validate(value); err != nil {
+		return nil, err
+	}
+
+	return []byte(value), nil
+}
+
+// UnmarshalText parses the text into dst, e.g.: to implement
+// encoding.TextUnmarshaler, also used by encoding/json.
+func (e *Enum[E]) UnmarshalText(dst *E, text []byte) error {
+	value, err := e.Parse(string(text))
+	if err != nil {
+		return err
+	}
+
+	*dst = value
+
+	return nil
+}
+
+// Value returns the value, if part of the enum, as a string, e.g.: to
+// implement driver.Valuer.
+func (e *Enum[E]) Value(value E) (driver.Value, error) {
+	if err := e.Validate(value); err != nil {
+		return nil, err
+	}
+
+	return string(value), nil
+}
+
+// Scan parses the string, or []byte, source into dst, e.g.: to implement
+// sql.Scanner.
+func (e *Enum[E]) Scan(dst *E, src any) error {
+	switch v := src.(type) {
+	case string:
+		return e.UnmarshalText(dst, []byte(v))
+	case []byte:
+		return e.UnmarshalText(dst, v)
+	default:
+		return fmt.Errorf("cannot scan %T, expected []byte or string", src)
+	}
+}
+
+//////
+// Exported functionalities.
+//////
+
+// Of returns the enum of the type, false if not defined.
+func Of[E ~string]() (*Enum[E], bool) {
+	e, ok := registry.Load(reflect.TypeOf(*new(E)))
+	if !ok {
+		return nil, false
+	}
+
+	//nolint:forcetypeassert
+	return e.(*Enum[E]), true
+}
+
+// Parse returns the value of the type, if part of its enum, found by
+// reflection, so it doesn't need to be referenced, e.g.:
+// enum.Parse[Color]("red"). It fails with ErrUndefined if the type has no
+// enum.
+func Parse[E ~string](s string) (E, error) {
+	e, ok := Of[E]()
+	if !ok {
+		return "", fmt.Errorf("%w for %s", ErrUndefined, reflect.TypeOf(*new(E)))
+	}
+
+	return e.Parse(s)
+}
+
+// MustParse is like Parse, panicking on error, e.g.: for constants.
+func MustParse[E ~string](s string) E {
+	value, err := Parse[E](s)
+	if err != nil {
+		panic(err)
+	}
+
+	return value
+}
+
+// Valid checks if the value is part of the enum of its type, false if the
+// type has no enum.
+func Valid[E ~string](value E) bool {
+	e, ok := Of[E]()
+
+	return ok && e.Valid(value)
+}
+
+// Values returns the valid values of the type, in declaration order, nil if
+// the type has no enum.
+func Values[E ~string]() []E {
+	e, ok := Of[E]()
+	if !ok {
+		return nil
+	}
+
+	return e.Values()
+}
+
+//////
+// Factory.
+//////
+
+// New defines the enum of the type E, with the valid values, in order, and
+// registers it, so the package-level functions find it. Defining the enum of
+// a type again replaces it. It panics if there are no values, or duplicates.
+func New[E ~string](values ...E) *Enum[E] {
+	t := reflect.TypeOf(*new(E))
+
+	set := safeset.New(values...)
+
+	if len(values) == 0 || set.Size() != len(values) {
+		panic(fmt.Sprintf("enum %s: values must be non-empty, and unique", t))
+	}
+
+	e := &Enum[E]{name: t.Name(), values: set}
+
+	registry.Store(t, e)
+
+	return e
+}
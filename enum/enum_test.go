@@ -0,0 +1,117 @@
+package enum
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type color string
+
+var colors = New[color]("red", "green", "blue")
+
+func (c color) MarshalText() ([]byte, error) {
+	return colors.MarshalText(c)
+}
+
+func (c *color) UnmarshalText(text []byte) error {
+	return colors.UnmarshalText(c, text)
+}
+
+func (c color) Value() (driver.Value, error) {
+	return colors.Value(c)
+}
+
+func (c *color) Scan(src any) error {
+	return colors.Scan(c, src)
+}
+
+type size string
+
+func TestEnum(t *testing.T) {
+	assert.Equal(t, "color", colors.Name())
+	assert.Equal(t, []color{"red", "green", "blue"}, colors.Values())
+	assert.True(t, colors.Set().Contains("green"))
+	assert.Equal(t, 3, colors.Set().Size())
+	assert.True(t, colors.Valid("red"))
+	assert.False(t, colors.Valid("Red"))
+
+	c, err := colors.Parse("blue")
+
+	assert.NoError(t, err)
+	assert.Equal(t, color("blue"), c)
+
+	_, err = colors.Parse("pink")
+
+	assert.ErrorIs(t, err, ErrInvalid)
+	assert.EqualError(t, err, `invalid enum value "pink" for color, expected one of: red, green, blue`)
+
+	assert.Panics(t, func() { colors.MustParse("pink") })
+	assert.Panics(t, func() { New[size]() })
+	assert.Panics(t, func() { New[size]("s", "s") })
+}
+
+func TestReflection(t *testing.T) {
+	assert.Equal(t, color("red"), MustParse[color]("red"))
+	assert.True(t, Valid(color("green")))
+	assert.False(t, Valid(color("pink")))
+	assert.Equal(t, colors.Values(), Values[color]())
+
+	_, err := Parse[size]("s")
+
+	assert.ErrorIs(t, err, ErrUndefined)
+	assert.False(t, Valid(size("s")))
+	assert.Nil(t, Values[size]())
+
+	e, ok := Of[color]()
+
+	assert.True(t, ok)
+	assert.Same(t, colors, e)
+}
+
+func TestJSON(t *testing.T) {
+	var palette struct {
+		Primary color   `json:"primary"`
+		Others  []color `json:"others"`
+	}
+
+	assert.NoError(t, json.Unmarshal([]byte(`{"primary":"red","others":["green"]}`), &palette))
+	assert.Equal(t, color("red"), palette.Primary)
+
+	err := json.Unmarshal([]byte(`{"primary":"pink"}`), &palette)
+
+	assert.ErrorIs(t, err, ErrInvalid)
+
+	palette.Primary = "pink"
+
+	_, err = json.Marshal(palette)
+
+	assert.ErrorIs(t, err, ErrInvalid)
+}
+
+func TestSQL(t *testing.T) {
+	var (
+		_ driver.Valuer = color("")
+		_ sql.Scanner   = new(color)
+	)
+
+	value, err := color("red").Value()
+
+	assert.NoError(t, err)
+	assert.Equal(t, "red", value)
+
+	_, err = color("pink").Value()
+
+	assert.ErrorIs(t, err, ErrInvalid)
+
+	var c color
+
+	assert.NoError(t, c.Scan([]byte("blue")))
+	assert.Equal(t, color("blue"), c)
+
+	assert.ErrorIs(t, c.Scan("pink"), ErrInvalid)
+	assert.Error(t, c.Scan(1))
+}
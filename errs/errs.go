@@ -0,0 +1,158 @@
+// Package errs provides Errors, a thread-safe aggregate error, compatible
+// with errors.Is, and errors.As, and JSON serializable.
+package errs
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"sync"
+)
+
+//////
+// Const, vars, and types.
+//////
+
+// Errors is an aggregate error, safe for concurrent use, collecting the
+// errors of operations which don't stop at the first failure, e.g.: workers
+// of a pool. It supports errors.Is, and errors.As, which match any of the
+// collected errors. The zero value is ready to use.
+type Errors struct {
+	mu sync.RWMutex
+
+	errs []error
+}
+
+//////
+// Methods.
+//////
+
+// Add collects the errors, in order, ignoring nil ones. Other Errors are
+// flattened.
+func (e *Errors) Add(errs ...error) *Errors {
+	var flat []error
+
+	for _, err := range errs {
+		flat = appendFlat(flat, err)
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.errs = append(e.errs, flat...)
+
+	return e
+}
+
+// HasErrors checks if any error was collected.
+func (e *Errors) HasErrors() bool {
+	return e.Len() > 0
+}
+
+// Len returns the number of collected errors.
+func (e *Errors) Len() int {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	return len(e.errs)
+}
+
+// Unwrap returns a copy of the collected errors, in order, so errors.Is, and
+// errors.As look into them.
+func (e *Errors) Unwrap() []error {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	return append([]error(nil), e.errs...)
+}
+
+// Error implements the error interface, joining the messages of the
+// collected errors with newlines, like errors.Join.
+func (e *Errors) Error() string {
+	return strings.Join(e.Messages(), "\n")
+}
+
+// Messages returns the messages of the collected errors, in order.
+func (e *Errors) Messages() []string {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	messages := make([]string, 0, len(e.errs))
+
+	for _, err := range e.errs {
+		messages = append(messages, err.Error())
+	}
+
+	return messages
+}
+
+// ErrorOrNil returns the aggregate, or nil if no error was collected, so it
+// can be returned as an error without the nil interface pitfall.
+func (e *Errors) ErrorOrNil() error {
+	if e == nil || !e.HasErrors() {
+		return nil
+	}
+
+	return e
+}
+
+// MarshalJSON implements json.Marshaler, encoding the messages of the
+// collected errors as an array of strings.
+func (e *Errors) MarshalJSON() ([]byte, error) {
+	return json.Marshal(e.Messages())
+}
+
+// UnmarshalJSON implements json.Unmarshaler, replacing the collected errors
+// with errors having the decoded messages. The original error types are lost,
+// so errors.Is, and errors.As don't match them anymore.
+func (e *Errors) UnmarshalJSON(data []byte) error {
+	var messages []string
+
+	if err := json.Unmarshal(data, &messages); err != nil {
+		return err
+	}
+
+	errs := make([]error, 0, len(messages))
+
+	for _, message := range messages {
+		errs = append(errs, errors.New(message))
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.errs = errs
+
+	return nil
+}
+
+//////
+// Helpers.
+//////
+
+// appendFlat appends the error to errs, unless nil, flattening Errors.
+func appendFlat(errs []error, err error) []error {
+	switch err := err.(type) {
+	case nil:
+		return errs
+	case *Errors:
+		return append(errs, err.Unwrap()...)
+	default:
+		return append(errs, err)
+	}
+}
+
+//////
+// Factory.
+//////
+
+// New creates a new Errors, collecting the given errors, see Add.
+func New(errs ...error) *Errors {
+	return (&Errors{}).Add(errs...)
+}
+
+// Join returns an Errors collecting the given errors, or nil if all are nil,
+// i.e.: a drop-in replacement of errors.Join.
+func Join(errs ...error) error {
+	return New(errs...).ErrorOrNil()
+}
@@ -0,0 +1,85 @@
+package errs
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestErrors(t *testing.T) {
+	var e Errors
+
+	assert.False(t, e.HasErrors())
+	assert.NoError(t, e.ErrorOrNil())
+
+	errA := errors.New("a")
+
+	e.Add(nil, errA).Add(New(errors.New("b"), nil))
+
+	assert.True(t, e.HasErrors())
+	assert.Equal(t, 2, e.Len())
+	assert.Equal(t, []string{"a", "b"}, e.Messages())
+	assert.EqualError(t, e.ErrorOrNil(), "a\nb")
+
+	// Unwrap returns a copy.
+	e.Unwrap()[0] = nil
+
+	assert.Equal(t, errA, e.Unwrap()[0])
+}
+
+func TestErrorsIsAs(t *testing.T) {
+	err := Join(errors.New("a"), &fs.PathError{Op: "open", Path: "x", Err: fs.ErrNotExist})
+
+	assert.ErrorIs(t, err, fs.ErrNotExist)
+
+	var pathErr *fs.PathError
+
+	assert.ErrorAs(t, err, &pathErr)
+	assert.Equal(t, "x", pathErr.Path)
+
+	assert.NoError(t, Join(nil, nil))
+}
+
+func TestErrorsConcurrency(t *testing.T) {
+	var (
+		e  Errors
+		wg sync.WaitGroup
+	)
+
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			e.Add(errors.New("failed"))
+		}()
+	}
+
+	wg.Wait()
+
+	assert.Equal(t, 100, e.Len())
+}
+
+func TestErrorsJSON(t *testing.T) {
+	e := New(errors.New("a"), errors.New("b"))
+
+	data, err := json.Marshal(e)
+
+	assert.NoError(t, err)
+	assert.JSONEq(t, `["a","b"]`, string(data))
+
+	var decoded Errors
+
+	assert.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, e.Messages(), decoded.Messages())
+
+	empty, err := json.Marshal(&Errors{})
+
+	assert.NoError(t, err)
+	assert.Equal(t, `[]`, string(empty))
+}
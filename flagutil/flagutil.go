@@ -0,0 +1,153 @@
+package flagutil
+
+import (
+	"flag"
+
+	"github.com/thalesfsp/go-common-types/safeorderedmap"
+	"github.com/thalesfsp/go-common-types/safeset"
+	"github.com/thalesfsp/go-common-types/safeslice"
+	"github.com/thalesfsp/go-common-types/shared"
+)
+
+//////
+// Const, vars, and types.
+//////
+
+// sliceValue is the flag.Value of a SafeSlice.
+type sliceValue[T comparable] struct {
+	s *safeslice.SafeSlice[T]
+}
+
+// setValue is the flag.Value of a SafeSet.
+type setValue[T any] struct {
+	s *safeset.SafeSet[T]
+}
+
+// mapValue is the flag.Value of a SafeOrderedMap.
+type mapValue[T any] struct {
+	m *safeorderedmap.SafeOrderedMap[T]
+}
+
+//////
+// Methods.
+//////
+
+// String is the stringer implementation, formatting the slice as text.
+func (v *sliceValue[T]) String() string {
+	// The flag package calls String on a zero value to detect defaults.
+	if v == nil || v.s == nil {
+		return ""
+	}
+
+	text, _ := v.s.MarshalText()
+
+	return string(text)
+}
+
+// Set implements flag.Value, appending the comma-separated elements.
+func (v *sliceValue[T]) Set(text string) error {
+	items, err := shared.ParseTextList[T]([]byte(text))
+	if err != nil {
+		return err
+	}
+
+	for _, item := range items {
+		if err := v.s.TryAdd(item); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Get implements flag.Getter, returning the slice.
+func (v *sliceValue[T]) Get() any {
+	return v.s
+}
+
+// String is the stringer implementation, formatting the set as text.
+func (v *setValue[T]) String() string {
+	if v == nil || v.s == nil {
+		return ""
+	}
+
+	text, _ := v.s.MarshalText()
+
+	return string(text)
+}
+
+// Set implements flag.Value, adding the comma-separated elements.
+func (v *setValue[T]) Set(text string) error {
+	items, err := shared.ParseTextList[T]([]byte(text))
+	if err != nil {
+		return err
+	}
+
+	for _, item := range items {
+		v.s.Add(item)
+	}
+
+	return nil
+}
+
+// Get implements flag.Getter, returning the set.
+func (v *setValue[T]) Get() any {
+	return v.s
+}
+
+// String is the stringer implementation, formatting the map as text.
+func (v *mapValue[T]) String() string {
+	if v == nil || v.m == nil {
+		return ""
+	}
+
+	text, _ := v.m.MarshalText()
+
+	return string(text)
+}
+
+// Set implements flag.Value, adding the comma-separated key=value pairs.
+// Existing keys are updated. Entries are added with AddE, so validators
+// apply.
+func (v *mapValue[T]) Set(text string) error {
+	entries := safeorderedmap.New[T]()
+
+	if err := entries.UnmarshalText([]byte(text)); err != nil {
+		return err
+	}
+
+	for _, entry := range entries.Entries() {
+		if err := v.m.AddE(entry.Key, entry.Value); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Get implements flag.Getter, returning the map.
+func (v *mapValue[T]) Get() any {
+	return v.m
+}
+
+//////
+// Factory.
+//////
+
+// Slice returns a flag.Value appending to the slice, e.g.: -tag a -tag b,c.
+// Elements are parsed like in SafeSlice.UnmarshalText.
+func Slice[T comparable](s *safeslice.SafeSlice[T]) flag.Value {
+	return &sliceValue[T]{s: s}
+}
+
+// Set returns a flag.Value adding to the set, e.g.: -tag a -tag b,c.
+// Elements are parsed like in SafeSet.UnmarshalText.
+func Set[T any](s *safeset.SafeSet[T]) flag.Value {
+	return &setValue[T]{s: s}
+}
+
+// Map returns a flag.Value adding to the map, e.g.: -label k=v -label x=y,z=w.
+// Entries are parsed like in SafeOrderedMap.UnmarshalText.
+func Map[T any](m *safeorderedmap.SafeOrderedMap[T]) flag.Value {
+	return &mapValue[T]{m: m}
+}
@@ -0,0 +1,63 @@
+package flagutil
+
+import (
+	"flag"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/thalesfsp/go-common-types/safeorderedmap"
+	"github.com/thalesfsp/go-common-types/safeset"
+	"github.com/thalesfsp/go-common-types/safeslice"
+)
+
+func TestFlags(t *testing.T) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+
+	tags := safeslice.New[string]()
+	ports := safeset.New[int]()
+	labels := safeorderedmap.New[string]()
+
+	fs.Var(Slice(tags), "tag", "tags")
+	fs.Var(Set(ports), "port", "ports")
+	fs.Var(Map(labels), "label", "labels")
+
+	assert.NoError(t, fs.Parse([]string{
+		"-tag", "a", "-tag", "b,c",
+		"-port", "80", "-port", "443,80",
+		"-label", "env=prod", "-label", "team=core,env=dev",
+	}))
+
+	assert.Equal(t, []string{"a", "b", "c"}, tags.Values())
+	assert.Equal(t, []int{80, 443}, ports.Values())
+	assert.Equal(t, []string{"env", "team"}, labels.Keys())
+	assert.Equal(t, []string{"dev", "core"}, labels.Values())
+
+	assert.Equal(t, "a,b,c", fs.Lookup("tag").Value.String())
+	assert.Equal(t, tags, fs.Lookup("tag").Value.(flag.Getter).Get())
+}
+
+func TestFlagsErrors(t *testing.T) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+
+	fs.Var(Set(safeset.New[int]()), "port", "ports")
+	fs.Var(Map(safeorderedmap.New[string]()), "label", "labels")
+	fs.Var(Slice(safeslice.NewBounded[string](1, safeslice.Reject)), "tag", "tags")
+
+	assert.Error(t, fs.Parse([]string{"-port", "x"}))
+	assert.Error(t, fs.Parse([]string{"-label", "novalue"}))
+	assert.Error(t, fs.Parse([]string{"-tag", "a,b"}))
+}
+
+func TestFlagsDefaults(t *testing.T) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+
+	fs.Var(Slice(safeslice.New("x")), "tag", "tags")
+
+	assert.Equal(t, "x", fs.Lookup("tag").DefValue)
+
+	// PrintDefaults calls String on zero values.
+	fs.SetOutput(io.Discard)
+	fs.PrintDefaults()
+}
@@ -0,0 +1,342 @@
+package httpexpose
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/thalesfsp/go-common-types/safemap"
+	"github.com/thalesfsp/go-common-types/safeorderedmap"
+	"github.com/thalesfsp/go-common-types/safeset"
+	"github.com/thalesfsp/go-common-types/safeslice"
+	"github.com/thalesfsp/go-common-types/shared"
+)
+
+//////
+// Const, vars, and types.
+//////
+
+// MaxBodySize is the maximum size of the body of a PUT request.
+const MaxBodySize = 1 << 20
+
+// errBadKey is returned when the key of the path can't be parsed.
+var errBadKey = errors.New("invalid key")
+
+// AuthFunc authorizes a request, returning an error to reject it with 403.
+type AuthFunc func(r *http.Request) error
+
+// Option allows to configure a handler.
+type Option func(h *handler)
+
+// store adapts a collection to the handler. Nil put, or remove means the
+// method isn't supported.
+type store struct {
+	encode func(w io.Writer) error
+	get    func(key string) (any, bool, error)
+	put    func(key string, body io.Reader) error
+	remove func(key string) (bool, error)
+}
+
+// handler exposes a collection over HTTP.
+type handler struct {
+	store store
+
+	writes bool
+	auth   map[string]AuthFunc
+}
+
+//////
+// Methods.
+//////
+
+// ServeHTTP implements http.Handler. The key is the path, without the leading
+// slash, so mount the handler with http.StripPrefix.
+func (h *handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if auth, ok := h.auth[r.Method]; ok {
+		if err := auth(r); err != nil {
+			http.Error(w, err.Error(), http.StatusForbidden)
+
+			return
+		}
+	}
+
+	key := strings.TrimPrefix(r.URL.Path, "/")
+
+	switch {
+	case r.Method == http.MethodGet && key == "":
+		w.Header().Set("Content-Type", "application/json")
+
+		// Headers are already sent, errors can only be logged by the caller.
+		_ = h.store.encode(w)
+	case r.Method == http.MethodGet:
+		h.get(w, key)
+	case r.Method == http.MethodPut && h.writes && h.store.put != nil && key != "":
+		h.put(w, r, key)
+	case r.Method == http.MethodDelete && h.writes && h.store.remove != nil && key != "":
+		h.remove(w, key)
+	default:
+		w.Header().Set("Allow", h.allow())
+
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+	}
+}
+
+// get writes the value of the key as JSON.
+func (h *handler) get(w http.ResponseWriter, key string) {
+	value, ok, err := h.store.get(key)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+
+		return
+	}
+
+	if !ok {
+		http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	_ = json.NewEncoder(w).Encode(value)
+}
+
+// put stores the body under the key.
+func (h *handler) put(w http.ResponseWriter, r *http.Request, key string) {
+	if err := h.store.put(key, http.MaxBytesReader(w, r.Body, MaxBodySize)); err != nil {
+		status := http.StatusUnprocessableEntity
+
+		var syntaxErr *json.SyntaxError
+
+		var typeErr *json.UnmarshalTypeError
+
+		if errors.Is(err, errBadKey) || errors.As(err, &syntaxErr) || errors.As(err, &typeErr) {
+			status = http.StatusBadRequest
+		}
+
+		http.Error(w, err.Error(), status)
+
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// remove deletes the key.
+func (h *handler) remove(w http.ResponseWriter, key string) {
+	ok, err := h.store.remove(key)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+
+		return
+	}
+
+	if !ok {
+		http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// allow lists the supported methods.
+func (h *handler) allow() string {
+	methods := []string{http.MethodGet}
+
+	if h.writes && h.store.put != nil {
+		methods = append(methods, http.MethodPut)
+	}
+
+	if h.writes && h.store.remove != nil {
+		methods = append(methods, http.MethodDelete)
+	}
+
+	return strings.Join(methods, ", ")
+}
+
+//////
+// Helpers.
+//////
+
+// parse parses the key of the path as T.
+func parse[T any](key string) (T, error) {
+	value, err := shared.ParseText[T](key)
+	if err != nil {
+		return value, fmt.Errorf("%w %q: %w", errBadKey, key, err)
+	}
+
+	return value, nil
+}
+
+// decode decodes the body as T.
+func decode[T any](body io.Reader) (T, error) {
+	var value T
+
+	err := json.NewDecoder(body).Decode(&value)
+
+	return value, err
+}
+
+// newHandler creates the handler of the store.
+func newHandler(s store, opts ...Option) http.Handler {
+	h := &handler{
+		store: s,
+		auth:  map[string]AuthFunc{},
+	}
+
+	for _, opt := range opts {
+		opt(h)
+	}
+
+	return h
+}
+
+//////
+// Factory.
+//////
+
+// WithWrites enables PUT, and DELETE, which are disabled by default.
+func WithWrites() Option {
+	return func(h *handler) {
+		h.writes = true
+	}
+}
+
+// WithAuth sets the auth hook of the method, e.g.: http.MethodPut, called
+// before serving the request.
+func WithAuth(method string, auth AuthFunc) Option {
+	return func(h *handler) {
+		h.auth[method] = auth
+	}
+}
+
+// OrderedMap exposes a SafeOrderedMap. GET / returns the map, GET /{key} the
+// value. With WithWrites, PUT /{key} stores the JSON body, validated like
+// AddE, and DELETE /{key} deletes the key.
+func OrderedMap[T any](m *safeorderedmap.SafeOrderedMap[T], opts ...Option) http.Handler {
+	return newHandler(store{
+		encode: m.Encode,
+		get: func(key string) (any, bool, error) {
+			value, ok := m.Get(key)
+
+			return value, ok, nil
+		},
+		put: func(key string, body io.Reader) error {
+			value, err := decode[T](body)
+			if err != nil {
+				return err
+			}
+
+			return m.AddE(key, value)
+		},
+		remove: func(key string) (bool, error) {
+			return m.Remove(key), nil
+		},
+	}, opts...)
+}
+
+// Map exposes a safemap.Map. Keys are parsed from the path like in
+// UnmarshalText, e.g.: GET /42 for an int key. Otherwise it behaves like
+// OrderedMap.
+func Map[K comparable, V any](m *safemap.Map[K, V], opts ...Option) http.Handler {
+	return newHandler(store{
+		encode: m.Encode,
+		get: func(key string) (any, bool, error) {
+			k, err := parse[K](key)
+			if err != nil {
+				return nil, false, err
+			}
+
+			value, ok := m.Get(k)
+
+			return value, ok, nil
+		},
+		put: func(key string, body io.Reader) error {
+			k, err := parse[K](key)
+			if err != nil {
+				return err
+			}
+
+			value, err := decode[V](body)
+			if err != nil {
+				return err
+			}
+
+			m.Add(k, value)
+
+			return nil
+		},
+		remove: func(key string) (bool, error) {
+			k, err := parse[K](key)
+			if err != nil {
+				return false, err
+			}
+
+			_, ok := m.GetAndDelete(k)
+
+			return ok, nil
+		},
+	}, opts...)
+}
+
+// Slice exposes a SafeSlice, read-only. GET / returns the slice, GET /{index}
+// the element.
+func Slice[T comparable](s *safeslice.SafeSlice[T], opts ...Option) http.Handler {
+	return newHandler(store{
+		encode: s.Encode,
+		get: func(key string) (any, bool, error) {
+			i, err := strconv.Atoi(key)
+			if err != nil {
+				return nil, false, fmt.Errorf("%w %q: %w", errBadKey, key, err)
+			}
+
+			values := s.Values()
+
+			if i < 0 || i >= len(values) {
+				return nil, false, nil
+			}
+
+			return values[i], true, nil
+		},
+	}, opts...)
+}
+
+// Set exposes a SafeSet. GET / returns the set, GET /{value} returns the
+// value if present. With WithWrites, PUT /{value} adds the value, and DELETE
+// /{value} removes it. Values are parsed from the path like in UnmarshalText.
+func Set[T any](s *safeset.SafeSet[T], opts ...Option) http.Handler {
+	return newHandler(store{
+		encode: s.Encode,
+		get: func(key string) (any, bool, error) {
+			value, err := parse[T](key)
+			if err != nil {
+				return nil, false, err
+			}
+
+			return value, s.Contains(value), nil
+		},
+		put: func(key string, _ io.Reader) error {
+			value, err := parse[T](key)
+			if err != nil {
+				return err
+			}
+
+			s.Add(value)
+
+			return nil
+		},
+		remove: func(key string) (bool, error) {
+			value, err := parse[T](key)
+			if err != nil {
+				return false, err
+			}
+
+			return s.Remove(value), nil
+		},
+	}, opts...)
+}
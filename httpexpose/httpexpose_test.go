@@ -0,0 +1,167 @@
+package httpexpose
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/thalesfsp/go-common-types/safemap"
+	"github.com/thalesfsp/go-common-types/safeorderedmap"
+	"github.com/thalesfsp/go-common-types/safeset"
+	"github.com/thalesfsp/go-common-types/safeslice"
+)
+
+// do serves the request, returning the status, and the body.
+func do(h http.Handler, method, path, body string) (int, string) {
+	rec := httptest.NewRecorder()
+
+	h.ServeHTTP(rec, httptest.NewRequest(method, path, strings.NewReader(body)))
+
+	return rec.Code, strings.TrimSpace(rec.Body.String())
+}
+
+func TestOrderedMap(t *testing.T) {
+	m := safeorderedmap.New[int]()
+	m.Add("b", 2).Add("a", 1)
+
+	h := OrderedMap(m, WithWrites())
+
+	code, body := do(h, http.MethodGet, "/", "")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, `{"b":2,"a":1}`, body)
+
+	code, body = do(h, http.MethodGet, "/a", "")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, `1`, body)
+
+	code, _ = do(h, http.MethodGet, "/missing", "")
+	assert.Equal(t, http.StatusNotFound, code)
+
+	code, _ = do(h, http.MethodPut, "/c", "3")
+	assert.Equal(t, http.StatusNoContent, code)
+	assert.Equal(t, []string{"b", "a", "c"}, m.Keys())
+
+	code, _ = do(h, http.MethodPut, "/c", `"x"`)
+	assert.Equal(t, http.StatusBadRequest, code)
+
+	code, _ = do(h, http.MethodDelete, "/b", "")
+	assert.Equal(t, http.StatusNoContent, code)
+	assert.Equal(t, []string{"a", "c"}, m.Keys())
+
+	code, _ = do(h, http.MethodDelete, "/b", "")
+	assert.Equal(t, http.StatusNotFound, code)
+}
+
+func TestOrderedMapValidation(t *testing.T) {
+	m := safeorderedmap.New(safeorderedmap.WithValidator(func(_ string, value int) error {
+		if value < 0 {
+			return errors.New("negative")
+		}
+
+		return nil
+	}))
+
+	code, _ := do(OrderedMap(m, WithWrites()), http.MethodPut, "/a", "-1")
+	assert.Equal(t, http.StatusUnprocessableEntity, code)
+	assert.True(t, m.Empty())
+}
+
+func TestReadOnly(t *testing.T) {
+	m := safeorderedmap.New[int]()
+
+	rec := httptest.NewRecorder()
+
+	OrderedMap(m).ServeHTTP(rec, httptest.NewRequest(http.MethodPut, "/a", strings.NewReader("1")))
+
+	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+	assert.Equal(t, "GET", rec.Header().Get("Allow"))
+	assert.True(t, m.Empty())
+
+	code, _ := do(Slice(safeslice.New(1), WithWrites()), http.MethodDelete, "/0", "")
+	assert.Equal(t, http.StatusMethodNotAllowed, code)
+}
+
+func TestAuth(t *testing.T) {
+	m := safeorderedmap.New[int]()
+
+	h := OrderedMap(m, WithWrites(), WithAuth(http.MethodPut, func(r *http.Request) error {
+		if r.Header.Get("Authorization") != "secret" {
+			return errors.New("unauthorized")
+		}
+
+		return nil
+	}))
+
+	code, _ := do(h, http.MethodPut, "/a", "1")
+	assert.Equal(t, http.StatusForbidden, code)
+
+	req := httptest.NewRequest(http.MethodPut, "/a", strings.NewReader("1"))
+	req.Header.Set("Authorization", "secret")
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+
+	code, _ = do(h, http.MethodGet, "/a", "")
+	assert.Equal(t, http.StatusOK, code)
+}
+
+func TestMap(t *testing.T) {
+	m := safemap.New[int, string]()
+	m.Add(1, "a")
+
+	h := Map(m, WithWrites())
+
+	code, body := do(h, http.MethodGet, "/1", "")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, `"a"`, body)
+
+	code, _ = do(h, http.MethodGet, "/x", "")
+	assert.Equal(t, http.StatusBadRequest, code)
+
+	code, _ = do(h, http.MethodPut, "/2", `"b"`)
+	assert.Equal(t, http.StatusNoContent, code)
+
+	code, body = do(h, http.MethodGet, "/", "")
+	assert.Equal(t, http.StatusOK, code)
+	assert.JSONEq(t, `{"1":"a","2":"b"}`, body)
+
+	code, _ = do(h, http.MethodDelete, "/1", "")
+	assert.Equal(t, http.StatusNoContent, code)
+	assert.False(t, m.Contains(1))
+}
+
+func TestSliceAndSet(t *testing.T) {
+	sl := Slice(safeslice.New("a", "b"))
+
+	code, body := do(sl, http.MethodGet, "/", "")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, `["a","b"]`, body)
+
+	code, body = do(sl, http.MethodGet, "/1", "")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, `"b"`, body)
+
+	code, _ = do(sl, http.MethodGet, "/2", "")
+	assert.Equal(t, http.StatusNotFound, code)
+
+	s := safeset.New(1, 2)
+	st := Set(s, WithWrites())
+
+	code, _ = do(st, http.MethodGet, "/2", "")
+	assert.Equal(t, http.StatusOK, code)
+
+	code, _ = do(st, http.MethodPut, "/3", "")
+	assert.Equal(t, http.StatusNoContent, code)
+
+	code, _ = do(st, http.MethodDelete, "/1", "")
+	assert.Equal(t, http.StatusNoContent, code)
+	assert.Equal(t, []int{2, 3}, s.Values())
+
+	code, _ = do(st, http.MethodGet, "/1", "")
+	assert.Equal(t, http.StatusNotFound, code)
+}
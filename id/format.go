@@ -0,0 +1,323 @@
+package id
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"math/big"
+	"strings"
+	"time"
+)
+
+//////
+// Const, vars, and types.
+//////
+
+const (
+	// crockford is the Crockford's base32 alphabet, used by ULID.
+	crockford = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+	// base62 is the alphabet of KSUID.
+	base62 = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+
+	// ksuidEpoch is the epoch of KSUID timestamps, in Unix seconds.
+	ksuidEpoch = 1400000000
+
+	// ksuidLength is the length of the text encoding of a KSUID.
+	ksuidLength = 27
+)
+
+// UUIDv4 is the format of random UUIDs, RFC 9562, e.g.:
+// "f47ac10b-58cc-4372-a567-0e02b2c3d479".
+type UUIDv4 struct{}
+
+// UUIDv7 is the format of time ordered UUIDs, RFC 9562: a millisecond Unix
+// timestamp, followed by random bits, e.g.: database keys.
+type UUIDv7 struct{}
+
+// ULID is the format of Universally Unique Lexicographically Sortable
+// Identifiers: a millisecond Unix timestamp, followed by random bits, encoded
+// in 26 characters of Crockford's base32, e.g.: "01ARZ3NDEKTSV4RRFFQ69G5FAV".
+type ULID struct{}
+
+// KSUID is the format of K-Sortable Unique IDentifiers: a second timestamp,
+// followed by 128 random bits, encoded in 27 characters of base62, e.g.:
+// "0ujtsYcgvSTl8PAuAdqWYSMnLOv".
+type KSUID struct{}
+
+//////
+// UUID.
+//////
+
+// Size implements Format.
+func (UUIDv4) Size() int { return 16 }
+
+// Generate implements Format.
+func (UUIDv4) Generate(b []byte) error {
+	if _, err := rand.Read(b); err != nil {
+		return err
+	}
+
+	setVersion(b, 4)
+
+	return nil
+}
+
+// Encode implements Format.
+func (UUIDv4) Encode(b []byte) string { return encodeUUID(b) }
+
+// Decode implements Format. It also accepts uppercase, and no hyphens.
+func (UUIDv4) Decode(s string, b []byte) error { return decodeUUID(s, b, 4) }
+
+// Size implements Format.
+func (UUIDv7) Size() int { return 16 }
+
+// Generate implements Format.
+func (UUIDv7) Generate(b []byte) error {
+	if _, err := rand.Read(b[6:]); err != nil {
+		return err
+	}
+
+	putMillis(b, time.Now())
+
+	setVersion(b, 7)
+
+	return nil
+}
+
+// Encode implements Format.
+func (UUIDv7) Encode(b []byte) string { return encodeUUID(b) }
+
+// Decode implements Format. It also accepts uppercase, and no hyphens.
+func (UUIDv7) Decode(s string, b []byte) error { return decodeUUID(s, b, 7) }
+
+// Time returns the creation time, with millisecond precision.
+func (UUIDv7) Time(b []byte) time.Time { return millis(b) }
+
+//////
+// ULID.
+//////
+
+// Size implements Format.
+func (ULID) Size() int { return 16 }
+
+// Generate implements Format.
+func (ULID) Generate(b []byte) error {
+	if _, err := rand.Read(b[6:]); err != nil {
+		return err
+	}
+
+	putMillis(b, time.Now())
+
+	return nil
+}
+
+// Encode implements Format: the 128 bits are encoded, 5 bits per character,
+// from the most significant, with 2 leading zero bits.
+func (ULID) Encode(b []byte) string {
+	n := new(big.Int).SetBytes(b)
+
+	out := make([]byte, 26)
+
+	for i := len(out) - 1; i >= 0; i-- {
+		out[i] = crockford[n.Uint64()&31]
+
+		n.Rsh(n, 5)
+	}
+
+	return string(out)
+}
+
+// Decode implements Format. It's case insensitive, and accepts the Crockford's
+// aliases: I, and L for 1, O for 0.
+func (ULID) Decode(s string, b []byte) error {
+	if len(s) != 26 {
+		return errors.New("expected 26 characters")
+	}
+
+	n := new(big.Int)
+
+	for _, c := range strings.ToUpper(s) {
+		switch c {
+		case 'I', 'L':
+			c = '1'
+		case 'O':
+			c = '0'
+		}
+
+		digit := strings.IndexRune(crockford, c)
+		if digit < 0 {
+			return errors.New("invalid character")
+		}
+
+		n.Lsh(n, 5).Or(n, big.NewInt(int64(digit)))
+	}
+
+	if n.BitLen() > 128 {
+		return errors.New("overflow")
+	}
+
+	n.FillBytes(b)
+
+	return nil
+}
+
+// Time returns the creation time, with millisecond precision.
+func (ULID) Time(b []byte) time.Time { return millis(b) }
+
+//////
+// KSUID.
+//////
+
+// Size implements Format.
+func (KSUID) Size() int { return 20 }
+
+// Generate implements Format.
+func (KSUID) Generate(b []byte) error {
+	if _, err := rand.Read(b[4:]); err != nil {
+		return err
+	}
+
+	binary.BigEndian.PutUint32(b, uint32(time.Now().Unix()-ksuidEpoch))
+
+	return nil
+}
+
+// Encode implements Format, left padding with zeros.
+func (KSUID) Encode(b []byte) string {
+	n := new(big.Int).SetBytes(b)
+
+	out := make([]byte, ksuidLength)
+
+	base, mod := big.NewInt(62), new(big.Int)
+
+	for i := len(out) - 1; i >= 0; i-- {
+		n.DivMod(n, base, mod)
+
+		out[i] = base62[mod.Int64()]
+	}
+
+	return string(out)
+}
+
+// Decode implements Format.
+func (KSUID) Decode(s string, b []byte) error {
+	if len(s) != ksuidLength {
+		return errors.New("expected 27 characters")
+	}
+
+	n, base := new(big.Int), big.NewInt(62)
+
+	for _, c := range s {
+		digit := strings.IndexRune(base62, c)
+		if digit < 0 {
+			return errors.New("invalid character")
+		}
+
+		n.Mul(n, base).Add(n, big.NewInt(int64(digit)))
+	}
+
+	if n.BitLen() > 160 {
+		return errors.New("overflow")
+	}
+
+	n.FillBytes(b)
+
+	return nil
+}
+
+// Time returns the creation time, with second precision.
+func (KSUID) Time(b []byte) time.Time {
+	return time.Unix(int64(binary.BigEndian.Uint32(b))+ksuidEpoch, 0)
+}
+
+//////
+// Helpers.
+//////
+
+// setVersion sets the version, and the RFC 9562 variant, of the UUID.
+func setVersion(b []byte, version byte) {
+	b[6] = b[6]&0x0f | version<<4
+	b[8] = b[8]&0x3f | 0x80
+}
+
+// putMillis writes the 48-bit millisecond Unix timestamp of t at the start of
+// b.
+func putMillis(b []byte, t time.Time) {
+	ms := uint64(t.UnixMilli())
+
+	for i := 5; i >= 0; i-- {
+		b[i] = byte(ms)
+
+		ms >>= 8
+	}
+}
+
+// millis returns the time of the 48-bit millisecond Unix timestamp at the
+// start of b.
+func millis(b []byte) time.Time {
+	var ms int64
+
+	for _, c := range b[:6] {
+		ms = ms<<8 | int64(c)
+	}
+
+	return time.UnixMilli(ms)
+}
+
+// isZero checks if all bytes are zero.
+func isZero(b []byte) bool {
+	for _, c := range b {
+		if c != 0 {
+			return false
+		}
+	}
+
+	return true
+}
+
+// encodeUUID returns the canonical text encoding of the UUID.
+func encodeUUID(b []byte) string {
+	out := make([]byte, 36)
+
+	hex.Encode(out[0:8], b[0:4])
+	hex.Encode(out[9:13], b[4:6])
+	hex.Encode(out[14:18], b[6:8])
+	hex.Encode(out[19:23], b[8:10])
+	hex.Encode(out[24:], b[10:])
+
+	out[8], out[13], out[18], out[23] = '-', '-', '-', '-'
+
+	return string(out)
+}
+
+// decodeUUID decodes the text encoding of the UUID, checking its version, and
+// variant, unless it's the nil UUID.
+func decodeUUID(s string, b []byte, version byte) error {
+	if len(s) == 36 {
+		if s[8] != '-' || s[13] != '-' || s[18] != '-' || s[23] != '-' {
+			return errors.New("invalid hyphens")
+		}
+
+		s = strings.ReplaceAll(s, "-", "")
+	}
+
+	if len(s) != 32 {
+		return errors.New("expected 32 hex digits")
+	}
+
+	if _, err := hex.Decode(b, []byte(s)); err != nil {
+		return err
+	}
+
+	if isZero(b) {
+		return nil
+	}
+
+	if b[6]>>4 != version || b[8]&0xc0 != 0x80 {
+		return errors.New("wrong version, or variant")
+	}
+
+	return nil
+}
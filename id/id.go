@@ -0,0 +1,211 @@
+// Package id provides a generic, comparable, ID type, with the UUIDv4,
+// UUIDv7, ULID, and KSUID formats.
+package id
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"time"
+)
+
+//////
+// Const, vars, and types.
+//////
+
+// maxSize is the size of the largest format, KSUID.
+const maxSize = 20
+
+// ErrInvalid is returned when parsing, or scanning, an invalid ID.
+var ErrInvalid = errors.New("invalid id")
+
+// Format is the format of an ID: its size, generation, and text encoding.
+// Implementations are stateless empty structs, used as type parameters of ID,
+// e.g.: ID[UUIDv7].
+type Format interface {
+	// Size returns the size of the ID, in bytes, at most 20.
+	Size() int
+
+	// Generate fills b, of length Size, with a new ID.
+	Generate(b []byte) error
+
+	// Encode returns the text encoding of b.
+	Encode(b []byte) string
+
+	// Decode decodes s into b, of length Size.
+	Decode(s string, b []byte) error
+}
+
+// timestamped is a Format embedding the creation time in its IDs.
+type timestamped interface {
+	Time(b []byte) time.Time
+}
+
+// ID is an identifier of the format F. It's comparable, so it can be used as
+// a map key, or an element of a SafeSet, and, for time ordered formats, i.e.:
+// UUIDv7, ULID, and KSUID, Compare sorts IDs by creation time. The zero value
+// is the nil ID, see IsZero.
+type ID[F Format] struct {
+	raw [maxSize]byte
+}
+
+//////
+// Methods.
+//////
+
+// String is the stringer implementation, returning the text encoding of the
+// format.
+func (id ID[F]) String() string {
+	return format[F]().Encode(id.Bytes())
+}
+
+// Bytes returns a copy of the binary representation of the ID.
+func (id ID[F]) Bytes() []byte {
+	return append([]byte(nil), id.raw[:format[F]().Size()]...)
+}
+
+// IsZero checks if it's the nil ID, e.g.: not set.
+func (id ID[F]) IsZero() bool {
+	return id == ID[F]{}
+}
+
+// Compare returns -1, 0, or 1, comparing the binary representations. For time
+// ordered formats, older IDs come first, IDs created within the same time
+// unit are ordered randomly.
+func (id ID[F]) Compare(other ID[F]) int {
+	return bytes.Compare(id.raw[:], other.raw[:])
+}
+
+// Time returns the creation time embedded in the ID, false if the format
+// doesn't embed it, e.g.: UUIDv4.
+func (id ID[F]) Time() (time.Time, bool) {
+	f, ok := any(format[F]()).(timestamped)
+	if !ok {
+		return time.Time{}, false
+	}
+
+	return f.Time(id.Bytes()), true
+}
+
+// MarshalText implements the encoding.TextMarshaler interface, so IDs are
+// encoded as strings in JSON, including as map keys.
+func (id ID[F]) MarshalText() ([]byte, error) {
+	return []byte(id.String()), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface, the
+// inverse of MarshalText. An empty text is decoded as the nil ID.
+func (id *ID[F]) UnmarshalText(text []byte) error {
+	parsed, err := Parse[F](string(text))
+	if err != nil {
+		return err
+	}
+
+	*id = parsed
+
+	return nil
+}
+
+// Value implements the driver.Valuer interface, storing the ID as text.
+func (id ID[F]) Value() (driver.Value, error) {
+	return id.String(), nil
+}
+
+// Scan implements the sql.Scanner interface. It accepts the text encoding, as
+// string, or []byte, the binary representation, and nil, as the nil ID.
+func (id *ID[F]) Scan(src any) error {
+	switch v := src.(type) {
+	case nil:
+		*id = ID[F]{}
+
+		return nil
+	case string:
+		return id.UnmarshalText([]byte(v))
+	case []byte:
+		if len(v) == format[F]().Size() {
+			*id = ID[F]{}
+
+			copy(id.raw[:], v)
+
+			return nil
+		}
+
+		return id.UnmarshalText(v)
+	default:
+		return fmt.Errorf("cannot scan %T, expected []byte or string", src)
+	}
+}
+
+//////
+// Helpers.
+//////
+
+// format returns the format of the type parameter.
+func format[F Format]() F {
+	return *new(F)
+}
+
+//////
+// Factory.
+//////
+
+// New generates a new ID.
+func New[F Format]() (ID[F], error) {
+	var id ID[F]
+
+	if err := format[F]().Generate(id.raw[:format[F]().Size()]); err != nil {
+		return ID[F]{}, err
+	}
+
+	return id, nil
+}
+
+// Must generates a new ID, panicking on error, i.e.: if the system random
+// number generator fails.
+func Must[F Format]() ID[F] {
+	id, err := New[F]()
+	if err != nil {
+		panic(err)
+	}
+
+	return id
+}
+
+// Parse decodes the text encoding of an ID. An empty string is the nil ID.
+func Parse[F Format](s string) (ID[F], error) {
+	var id ID[F]
+
+	if s == "" {
+		return id, nil
+	}
+
+	if err := format[F]().Decode(s, id.raw[:format[F]().Size()]); err != nil {
+		return ID[F]{}, fmt.Errorf("%w %q: %w", ErrInvalid, s, err)
+	}
+
+	return id, nil
+}
+
+// MustParse is like Parse, panicking on error, e.g.: for constants.
+func MustParse[F Format](s string) ID[F] {
+	id, err := Parse[F](s)
+	if err != nil {
+		panic(err)
+	}
+
+	return id
+}
+
+// FromBytes creates an ID from its binary representation.
+func FromBytes[F Format](b []byte) (ID[F], error) {
+	var id ID[F]
+
+	if len(b) != format[F]().Size() {
+		return id, fmt.Errorf("%w: %d bytes, expected %d", ErrInvalid, len(b), format[F]().Size())
+	}
+
+	copy(id.raw[:], b)
+
+	return id, nil
+}
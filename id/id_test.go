@@ -0,0 +1,196 @@
+package id
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/thalesfsp/go-common-types/safeset"
+)
+
+func TestUUIDv4(t *testing.T) {
+	id := Must[UUIDv4]()
+
+	assert.False(t, id.IsZero())
+	assert.Len(t, id.String(), 36)
+	assert.Equal(t, byte('4'), id.String()[14])
+	assert.NotEqual(t, id, Must[UUIDv4]())
+
+	parsed, err := Parse[UUIDv4](id.String())
+
+	assert.NoError(t, err)
+	assert.Equal(t, id, parsed)
+
+	_, ok := id.Time()
+
+	assert.False(t, ok)
+
+	// Uppercase, and no hyphens are accepted, the version is checked.
+	assert.Equal(t, MustParse[UUIDv4]("f47ac10b-58cc-4372-a567-0e02b2c3d479"), MustParse[UUIDv4]("F47AC10B58CC4372A5670E02B2C3D479"))
+
+	_, err = Parse[UUIDv4]("017f22e2-79b0-7cc3-98c4-dc0c0c07398f")
+
+	assert.ErrorIs(t, err, ErrInvalid)
+
+	_, err = Parse[UUIDv4]("f47ac10b-58cc-4372-a567")
+
+	assert.ErrorIs(t, err, ErrInvalid)
+}
+
+func TestUUIDv7(t *testing.T) {
+	before := time.Now().Truncate(time.Millisecond)
+
+	id := Must[UUIDv7]()
+
+	assert.Equal(t, byte('7'), id.String()[14])
+
+	created, ok := id.Time()
+
+	assert.True(t, ok)
+	assert.False(t, created.Before(before))
+	assert.False(t, created.After(time.Now()))
+
+	created, _ = MustParse[UUIDv7]("017f22e2-79b0-7cc3-98c4-dc0c0c07398f").Time()
+
+	assert.Equal(t, int64(0x017F22E279B0), created.UnixMilli())
+}
+
+func TestULID(t *testing.T) {
+	const s = "01ARZ3NDEKTSV4RRFFQ69G5FAV"
+
+	id := MustParse[ULID](s)
+
+	assert.Equal(t, s, id.String())
+	assert.Equal(t, id, MustParse[ULID]("01arz3ndektsv4rrffq69g5fav"))
+
+	created, ok := id.Time()
+
+	assert.True(t, ok)
+	assert.Equal(t, int64(1469922850259), created.UnixMilli())
+
+	// Overflow, and invalid characters.
+	_, err := Parse[ULID]("81ARZ3NDEKTSV4RRFFQ69G5FAV")
+
+	assert.ErrorIs(t, err, ErrInvalid)
+
+	_, err = Parse[ULID]("01ARZ3NDEKTSV4RRFFQ69G5FAU")
+
+	assert.ErrorIs(t, err, ErrInvalid)
+
+	generated := Must[ULID]()
+
+	assert.Equal(t, generated, MustParse[ULID](generated.String()))
+}
+
+func TestKSUID(t *testing.T) {
+	const s = "0ujtsYcgvSTl8PAuAdqWYSMnLOv"
+
+	id := MustParse[KSUID](s)
+
+	assert.Equal(t, s, id.String())
+	assert.Len(t, id.Bytes(), 20)
+
+	created, ok := id.Time()
+
+	assert.True(t, ok)
+	assert.Equal(t, int64(1507608047), created.Unix())
+
+	generated := Must[KSUID]()
+
+	assert.Equal(t, generated, MustParse[KSUID](generated.String()))
+	assert.Len(t, generated.String(), 27)
+}
+
+func TestIDCompare(t *testing.T) {
+	old := MustParse[ULID]("01ARZ3NDEKTSV4RRFFQ69G5FAV")
+	ids := []ID[ULID]{Must[ULID](), old}
+
+	sort.Slice(ids, func(i, j int) bool { return ids[i].Compare(ids[j]) < 0 })
+
+	assert.Equal(t, old, ids[0])
+	assert.Equal(t, 0, old.Compare(old))
+}
+
+func TestIDZero(t *testing.T) {
+	var id ID[UUIDv4]
+
+	assert.True(t, id.IsZero())
+	assert.Equal(t, "00000000-0000-0000-0000-000000000000", id.String())
+
+	parsed, err := Parse[UUIDv4](id.String())
+
+	assert.NoError(t, err)
+	assert.True(t, parsed.IsZero())
+
+	parsed, err = Parse[UUIDv4]("")
+
+	assert.NoError(t, err)
+	assert.True(t, parsed.IsZero())
+}
+
+func TestIDJSON(t *testing.T) {
+	id := MustParse[UUIDv4]("f47ac10b-58cc-4372-a567-0e02b2c3d479")
+
+	data, err := json.Marshal(map[ID[UUIDv4]]ID[UUIDv4]{id: id})
+
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"f47ac10b-58cc-4372-a567-0e02b2c3d479":"f47ac10b-58cc-4372-a567-0e02b2c3d479"}`, string(data))
+
+	var decoded map[ID[UUIDv4]]ID[UUIDv4]
+
+	assert.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, id, decoded[id])
+
+	var invalid ID[UUIDv4]
+
+	assert.Error(t, json.Unmarshal([]byte(`"nope"`), &invalid))
+}
+
+func TestIDSQL(t *testing.T) {
+	var (
+		_ driver.Valuer = ID[KSUID]{}
+		_ sql.Scanner   = &ID[KSUID]{}
+	)
+
+	id := Must[KSUID]()
+
+	value, err := id.Value()
+
+	assert.NoError(t, err)
+	assert.Equal(t, id.String(), value)
+
+	var scanned ID[KSUID]
+
+	assert.NoError(t, scanned.Scan(value))
+	assert.Equal(t, id, scanned)
+
+	assert.NoError(t, scanned.Scan(id.Bytes()))
+	assert.Equal(t, id, scanned)
+
+	assert.NoError(t, scanned.Scan(nil))
+	assert.True(t, scanned.IsZero())
+
+	assert.Error(t, scanned.Scan(1))
+
+	fromBytes, err := FromBytes[KSUID](id.Bytes())
+
+	assert.NoError(t, err)
+	assert.Equal(t, id, fromBytes)
+
+	_, err = FromBytes[KSUID]([]byte{1})
+
+	assert.ErrorIs(t, err, ErrInvalid)
+}
+
+func TestIDSafeSet(t *testing.T) {
+	a, b := Must[UUIDv7](), Must[UUIDv7]()
+
+	set := safeset.New(a, b, a)
+
+	assert.Equal(t, 2, set.Size())
+	assert.True(t, set.Contains(a))
+}
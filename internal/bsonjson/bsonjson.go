@@ -0,0 +1,449 @@
+// Package bsonjson encodes Go values as BSON documents, keeping their BSON
+// types, and converts BSON documents to JSON, preserving the order of the
+// keys. It allows the collections to implement bson.Marshaler, and
+// bson.Unmarshaler without depending on the MongoDB driver.
+package bsonjson
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+)
+
+//////
+// Const, vars, and types.
+//////
+
+// BSON element types, see https://bsonspec.org/spec.html.
+const (
+	typeDouble   byte = 0x01
+	typeString   byte = 0x02
+	typeDocument byte = 0x03
+	typeArray    byte = 0x04
+	typeBinary   byte = 0x05
+	typeObjectID byte = 0x07
+	typeBool     byte = 0x08
+	typeDateTime byte = 0x09
+	typeNull     byte = 0x0A
+	typeInt32    byte = 0x10
+	typeInt64    byte = 0x12
+)
+
+// ErrInvalidDocument is returned when the BSON document is malformed.
+var ErrInvalidDocument = errors.New("invalid BSON document")
+
+// Element is a top-level element of a BSON document, with its value converted
+// to JSON.
+type Element struct {
+	Key   string
+	Value json.RawMessage
+}
+
+//////
+// JSON to BSON.
+//////
+
+// FromJSON converts a JSON object to a BSON document. A JSON array is
+// converted to a document keyed by the index of the elements, which is how
+// BSON represents arrays.
+func FromJSON(data []byte) ([]byte, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+
+	token, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	delim, ok := token.(json.Delim)
+	if !ok || (delim != '{' && delim != '[') {
+		return nil, fmt.Errorf("cannot convert %v to a BSON document", token)
+	}
+
+	var buf bytes.Buffer
+
+	if err := writeDocument(dec, &buf, delim == '['); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// writeDocument writes the elements of an object, or array, whose opening
+// delimiter was already consumed.
+func writeDocument(dec *json.Decoder, buf *bytes.Buffer, isArray bool) error {
+	start := buf.Len()
+
+	buf.Write([]byte{0, 0, 0, 0})
+
+	for i := 0; dec.More(); i++ {
+		key := strconv.Itoa(i)
+
+		if !isArray {
+			token, err := dec.Token()
+			if err != nil {
+				return err
+			}
+
+			//nolint:forcetypeassert
+			key = token.(string)
+		}
+
+		if err := writeElement(dec, buf, key); err != nil {
+			return err
+		}
+	}
+
+	// Closing delimiter.
+	if _, err := dec.Token(); err != nil {
+		return err
+	}
+
+	buf.WriteByte(0)
+
+	binary.LittleEndian.PutUint32(buf.Bytes()[start:], uint32(buf.Len()-start))
+
+	return nil
+}
+
+// writeElement reads the next JSON value, and writes it as a BSON element.
+func writeElement(dec *json.Decoder, buf *bytes.Buffer, key string) error {
+	token, err := dec.Token()
+	if err != nil {
+		return err
+	}
+
+	switch v := token.(type) {
+	case json.Delim:
+		if v == '{' {
+			writeHeader(buf, typeDocument, key)
+		} else {
+			writeHeader(buf, typeArray, key)
+		}
+
+		return writeDocument(dec, buf, v == '[')
+	case string:
+		writeHeader(buf, typeString, key)
+
+		writeString(buf, v)
+	case json.Number:
+		if n, err := v.Int64(); err == nil {
+			writeHeader(buf, typeInt64, key)
+
+			buf.Write(binary.LittleEndian.AppendUint64(nil, uint64(n)))
+
+			return nil
+		}
+
+		f, err := v.Float64()
+		if err != nil {
+			return err
+		}
+
+		writeHeader(buf, typeDouble, key)
+
+		buf.Write(binary.LittleEndian.AppendUint64(nil, math.Float64bits(f)))
+	case bool:
+		writeHeader(buf, typeBool, key)
+
+		if v {
+			buf.WriteByte(1)
+		} else {
+			buf.WriteByte(0)
+		}
+	case nil:
+		writeHeader(buf, typeNull, key)
+	}
+
+	return nil
+}
+
+//////
+// BSON to JSON.
+//////
+
+// reader reads a BSON document.
+type reader struct {
+	data []byte
+	pos  int
+}
+
+// next returns the next n bytes.
+func (r *reader) next(n int) ([]byte, error) {
+	if n < 0 || r.pos+n > len(r.data) {
+		return nil, ErrInvalidDocument
+	}
+
+	b := r.data[r.pos : r.pos+n]
+
+	r.pos += n
+
+	return b, nil
+}
+
+// cstring reads a NUL terminated string.
+func (r *reader) cstring() (string, error) {
+	end := bytes.IndexByte(r.data[r.pos:], 0)
+	if end < 0 {
+		return "", ErrInvalidDocument
+	}
+
+	s := string(r.data[r.pos : r.pos+end])
+
+	r.pos += end + 1
+
+	return s, nil
+}
+
+// int32 reads a little-endian int32.
+func (r *reader) int32() (int32, error) {
+	b, err := r.next(4)
+	if err != nil {
+		return 0, err
+	}
+
+	return int32(binary.LittleEndian.Uint32(b)), nil
+}
+
+// uint64 reads a little-endian uint64.
+func (r *reader) uint64() (uint64, error) {
+	b, err := r.next(8)
+	if err != nil {
+		return 0, err
+	}
+
+	return binary.LittleEndian.Uint64(b), nil
+}
+
+// elements reads the elements of the document at the current position,
+// calling f for each one of them, in order.
+func (r *reader) elements(f func(key string, value []byte) error) error {
+	size, err := r.int32()
+	if err != nil {
+		return err
+	}
+
+	end := r.pos - 4 + int(size)
+	if size < 5 || end > len(r.data) || r.data[end-1] != 0 {
+		return ErrInvalidDocument
+	}
+
+	for r.pos < end-1 {
+		t, err := r.next(1)
+		if err != nil {
+			return err
+		}
+
+		key, err := r.cstring()
+		if err != nil {
+			return err
+		}
+
+		value, err := r.value(t[0])
+		if err != nil {
+			return err
+		}
+
+		if err := f(key, value); err != nil {
+			return err
+		}
+	}
+
+	r.pos = end
+
+	return nil
+}
+
+// document reads a document, or an array, as JSON.
+func (r *reader) document(isArray bool) ([]byte, error) {
+	var buf bytes.Buffer
+
+	open, closing := byte('{'), byte('}')
+	if isArray {
+		open, closing = '[', ']'
+	}
+
+	buf.WriteByte(open)
+
+	first := true
+
+	if err := r.elements(func(key string, value []byte) error {
+		if !first {
+			buf.WriteByte(',')
+		}
+
+		first = false
+
+		if !isArray {
+			k, err := json.Marshal(key)
+			if err != nil {
+				return err
+			}
+
+			buf.Write(k)
+			buf.WriteByte(':')
+		}
+
+		buf.Write(value)
+
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	buf.WriteByte(closing)
+
+	return buf.Bytes(), nil
+}
+
+// value reads a value of the given type as JSON.
+//
+//nolint:cyclop
+func (r *reader) value(t byte) ([]byte, error) {
+	switch t {
+	case typeDouble:
+		bits, err := r.uint64()
+		if err != nil {
+			return nil, err
+		}
+
+		return json.Marshal(math.Float64frombits(bits))
+	case typeString:
+		size, err := r.int32()
+		if err != nil {
+			return nil, err
+		}
+
+		b, err := r.next(int(size))
+		if err != nil || size < 1 {
+			return nil, ErrInvalidDocument
+		}
+
+		return json.Marshal(string(b[:size-1]))
+	case typeDocument, typeArray:
+		return r.document(t == typeArray)
+	case typeBinary:
+		size, err := r.int32()
+		if err != nil {
+			return nil, err
+		}
+
+		// Subtype.
+		if _, err := r.next(1); err != nil {
+			return nil, err
+		}
+
+		b, err := r.next(int(size))
+		if err != nil {
+			return nil, err
+		}
+
+		return json.Marshal(base64.StdEncoding.EncodeToString(b))
+	case typeObjectID:
+		b, err := r.next(12)
+		if err != nil {
+			return nil, err
+		}
+
+		return json.Marshal(hex.EncodeToString(b))
+	case typeBool:
+		b, err := r.next(1)
+		if err != nil {
+			return nil, err
+		}
+
+		return strconv.AppendBool(nil, b[0] != 0), nil
+	case typeDateTime:
+		ms, err := r.uint64()
+		if err != nil {
+			return nil, err
+		}
+
+		return json.Marshal(time.UnixMilli(int64(ms)).UTC())
+	case typeNull:
+		return []byte("null"), nil
+	case typeInt32:
+		n, err := r.int32()
+		if err != nil {
+			return nil, err
+		}
+
+		return strconv.AppendInt(nil, int64(n), 10), nil
+	case typeInt64:
+		n, err := r.uint64()
+		if err != nil {
+			return nil, err
+		}
+
+		return strconv.AppendInt(nil, int64(n), 10), nil
+	default:
+		return nil, fmt.Errorf("unsupported BSON type 0x%02x", t)
+	}
+}
+
+// Elements returns the top-level elements of the BSON document, in order,
+// with their values converted to JSON.
+func Elements(doc []byte) ([]Element, error) {
+	r := &reader{data: doc}
+
+	elements := []Element{}
+
+	if err := r.elements(func(key string, value []byte) error {
+		elements = append(elements, Element{Key: key, Value: value})
+
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	if r.pos != len(doc) {
+		return nil, ErrInvalidDocument
+	}
+
+	return elements, nil
+}
+
+//////
+// Lists.
+//////
+
+// MarshalList encodes the items as a BSON document keyed by their index, see
+// D.MarshalBSON.
+func MarshalList[T any](items []T) ([]byte, error) {
+	d := make(D, len(items))
+
+	for i, item := range items {
+		d[i] = E{Key: strconv.Itoa(i), Value: item}
+	}
+
+	return d.MarshalBSON()
+}
+
+// UnmarshalList converts the values of the BSON document to items, in order.
+func UnmarshalList[T any](doc []byte) ([]T, error) {
+	elements, err := Elements(doc)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]T, 0, len(elements))
+
+	for _, element := range elements {
+		var item T
+
+		if err := json.Unmarshal(element.Value, &item); err != nil {
+			return nil, err
+		}
+
+		items = append(items, item)
+	}
+
+	return items, nil
+}
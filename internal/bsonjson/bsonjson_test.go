@@ -0,0 +1,197 @@
+package bsonjson
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// helloWorld is {"hello": "world"}, from the BSON specification.
+var helloWorld = []byte("\x16\x00\x00\x00\x02hello\x00\x06\x00\x00\x00world\x00\x00")
+
+func TestFromJSON(t *testing.T) {
+	doc, err := FromJSON([]byte(`{"hello":"world"}`))
+	assert.NoError(t, err)
+	assert.Equal(t, helloWorld, doc)
+
+	_, err = FromJSON([]byte(`1`))
+	assert.Error(t, err)
+}
+
+func TestElements(t *testing.T) {
+	elements, err := Elements(helloWorld)
+	assert.NoError(t, err)
+	assert.Equal(t, []Element{{Key: "hello", Value: json.RawMessage(`"world"`)}}, elements)
+
+	_, err = Elements(helloWorld[:10])
+	assert.ErrorIs(t, err, ErrInvalidDocument)
+}
+
+func TestRoundTrip(t *testing.T) {
+	in := `{"z":1,"a":{"y":[1,2.5,"x",true,null],"b":false},"m":-9007199254740993}`
+
+	doc, err := FromJSON([]byte(in))
+	assert.NoError(t, err)
+
+	elements, err := Elements(doc)
+	assert.NoError(t, err)
+	assert.Equal(t, []Element{
+		{Key: "z", Value: json.RawMessage(`1`)},
+		{Key: "a", Value: json.RawMessage(`{"y":[1,2.5,"x",true,null],"b":false}`)},
+		{Key: "m", Value: json.RawMessage(`-9007199254740993`)},
+	}, elements)
+}
+
+func TestElementsTypes(t *testing.T) {
+	doc := []byte{
+		0, 0, 0, 0,
+		// int32 "i": 7
+		0x10, 'i', 0, 7, 0, 0, 0,
+		// datetime "d": 1000ms
+		0x09, 'd', 0, 0xe8, 0x03, 0, 0, 0, 0, 0, 0,
+		// ObjectID "o"
+		0x07, 'o', 0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12,
+		// binary "b": 0x01 0x02
+		0x05, 'b', 0, 2, 0, 0, 0, 0, 1, 2,
+		0,
+	}
+	doc[0] = byte(len(doc))
+
+	elements, err := Elements(doc)
+	assert.NoError(t, err)
+	assert.Equal(t, []Element{
+		{Key: "i", Value: json.RawMessage(`7`)},
+		{Key: "d", Value: json.RawMessage(`"1970-01-01T00:00:01Z"`)},
+		{Key: "o", Value: json.RawMessage(`"0102030405060708090a0b0c"`)},
+		{Key: "b", Value: json.RawMessage(`"AQI="`)},
+	}, elements)
+}
+
+func TestMarshalUnmarshalList(t *testing.T) {
+	doc, err := MarshalList([]string{"a", "b"})
+	assert.NoError(t, err)
+
+	elements, err := Elements(doc)
+	assert.NoError(t, err)
+	assert.Equal(t, "0", elements[0].Key)
+	assert.Equal(t, "1", elements[1].Key)
+
+	items, err := UnmarshalList[string](doc)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a", "b"}, items)
+
+	doc, err = MarshalList[int](nil)
+	assert.NoError(t, err)
+
+	ints, err := UnmarshalList[int](doc)
+	assert.NoError(t, err)
+	assert.Empty(t, ints)
+}
+
+// types returns the types of the top-level elements of the document.
+func types(t *testing.T, doc []byte) map[string]byte {
+	t.Helper()
+
+	r := &reader{data: doc}
+
+	_, err := r.int32()
+	assert.NoError(t, err)
+
+	types := map[string]byte{}
+
+	for r.pos < len(doc)-1 {
+		b, err := r.next(1)
+		assert.NoError(t, err)
+
+		key, err := r.cstring()
+		assert.NoError(t, err)
+
+		_, err = r.value(b[0])
+		assert.NoError(t, err)
+
+		types[key] = b[0]
+	}
+
+	return types
+}
+
+// ObjectID is like primitive.ObjectID.
+type ObjectID [12]byte
+
+// MarshalJSON is like the one of primitive.ObjectID.
+func (id ObjectID) MarshalJSON() ([]byte, error) {
+	return json.Marshal(hex.EncodeToString(id[:]))
+}
+
+func TestMarshalD(t *testing.T) {
+	type inner struct {
+		Name  string `json:"name"`
+		Skip  string `bson:"-"`
+		Empty string `bson:",omitempty"`
+		Count int
+	}
+
+	at := time.Date(2024, 1, 2, 3, 4, 5, 6_000_000, time.UTC)
+
+	doc, err := D{
+		{Key: "small", Value: 7},
+		{Key: "big", Value: int64(7)},
+		{Key: "huge", Value: 1 << 40},
+		{Key: "ratio", Value: 2.5},
+		{Key: "at", Value: at},
+		{Key: "id", Value: ObjectID{1, 2, 3}},
+		{Key: "raw", Value: []byte{1, 2}},
+		{Key: "number", Value: json.Number("9007199254740993")},
+		{Key: "json", Value: json.RawMessage(`{"a":[1]}`)},
+		{Key: "inner", Value: &inner{Name: "x", Skip: "y", Count: 2}},
+		{Key: "map", Value: map[string]any{"b": true, "a": nil}},
+		{Key: "list", Value: []string{"a"}},
+		{Key: "nested", Value: D{{Key: "z", Value: "z"}}},
+		{Key: "nil", Value: nil},
+	}.MarshalBSON()
+	assert.NoError(t, err)
+
+	assert.Equal(t, map[string]byte{
+		"small":  typeInt32,
+		"big":    typeInt64,
+		"huge":   typeInt64,
+		"ratio":  typeDouble,
+		"at":     typeDateTime,
+		"id":     typeObjectID,
+		"raw":    typeBinary,
+		"number": typeInt64,
+		"json":   typeDocument,
+		"inner":  typeDocument,
+		"map":    typeDocument,
+		"list":   typeArray,
+		"nested": typeDocument,
+		"nil":    typeNull,
+	}, types(t, doc))
+
+	elements, err := Elements(doc)
+	assert.NoError(t, err)
+
+	values := map[string]string{}
+
+	for _, e := range elements {
+		values[e.Key] = string(e.Value)
+	}
+
+	assert.Equal(t, `"2024-01-02T03:04:05.006Z"`, values["at"])
+	assert.Equal(t, `"010203000000000000000000"`, values["id"])
+	assert.Equal(t, `9007199254740993`, values["number"])
+	assert.Equal(t, `{"a":[1]}`, values["json"])
+	assert.Equal(t, `{"name":"x","count":2}`, values["inner"])
+	assert.Equal(t, `{"a":null,"b":true}`, values["map"])
+	assert.Equal(t, `{"z":"z"}`, values["nested"])
+
+	_, err = D{{Key: "overflow", Value: uint64(math.MaxUint64)}}.MarshalBSON()
+	assert.Error(t, err)
+
+	_, err = D{{Key: "func", Value: func() {}}}.MarshalBSON()
+	assert.Error(t, err)
+}
@@ -0,0 +1,432 @@
+package bsonjson
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+//////
+// Const, vars, and types.
+//////
+
+// E is an element of an ordered document, like bson.E.
+type E struct {
+	Key   string
+	Value any
+}
+
+// D is an ordered document, like bson.D.
+type D []E
+
+// marshaler is implemented by values encoding themselves as a BSON document,
+// like bson.Marshaler.
+type marshaler interface {
+	MarshalBSON() ([]byte, error)
+}
+
+//////
+// Go to BSON.
+//////
+
+// MarshalBSON encodes the document, preserving the order of its elements.
+// Values are encoded like the MongoDB driver does, keeping their BSON types:
+//   - bool, strings, and floats, as boolean, string, and double
+//   - int8, int16, int32, uint8, and uint16, as int32
+//   - int64, uint32, and uint64, as int64, failing if it overflows
+//   - int, and uint, as int32, if they fit, int64 otherwise
+//   - time.Time, as a UTC datetime, with millisecond precision
+//   - []byte, as binary, and arrays of 12 bytes named ObjectID, e.g.:
+//     primitive.ObjectID, as an ObjectID
+//   - values implementing MarshalBSON, or MarshalBSONValue, as they encode
+//     themselves
+//   - slices, and arrays, as arrays, maps, as documents, with their keys
+//     sorted, and structs, as documents with their exported fields, named by
+//     their bson, or json, tag, or lowercased, honoring omitempty
+//   - nil, as null
+//
+// Other values implementing json.Marshaler, or encoding.TextMarshaler, e.g.:
+// json.RawMessage, are converted through their JSON, or text, representation.
+func (d D) MarshalBSON() ([]byte, error) {
+	var buf bytes.Buffer
+
+	if err := writeDoc(&buf, len(d), func(i int) error {
+		return writeValue(&buf, d[i].Key, reflect.ValueOf(d[i].Value))
+	}); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// writeDoc writes a document, calling element for each of its n elements,
+// which may write nothing, e.g.: an omitted field.
+func writeDoc(buf *bytes.Buffer, n int, element func(i int) error) error {
+	start := buf.Len()
+
+	buf.Write([]byte{0, 0, 0, 0})
+
+	for i := 0; i < n; i++ {
+		if err := element(i); err != nil {
+			return err
+		}
+	}
+
+	buf.WriteByte(0)
+
+	binary.LittleEndian.PutUint32(buf.Bytes()[start:], uint32(buf.Len()-start))
+
+	return nil
+}
+
+// writeValue writes the value as a BSON element, see D.MarshalBSON.
+//
+//nolint:cyclop,gocognit
+func writeValue(buf *bytes.Buffer, key string, v reflect.Value) error {
+	for {
+		if !v.IsValid() || isNil(v) {
+			writeHeader(buf, typeNull, key)
+
+			return nil
+		}
+
+		if ok, err := writeMarshaled(buf, key, v); ok {
+			return err
+		}
+
+		if v.Kind() != reflect.Interface && v.Kind() != reflect.Pointer {
+			break
+		}
+
+		v = v.Elem()
+	}
+
+	switch v.Kind() {
+	case reflect.Bool:
+		writeHeader(buf, typeBool, key)
+
+		if v.Bool() {
+			buf.WriteByte(1)
+		} else {
+			buf.WriteByte(0)
+		}
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint8, reflect.Uint16:
+		writeHeader(buf, typeInt32, key)
+
+		buf.Write(binary.LittleEndian.AppendUint32(nil, uint32(toInt(v))))
+	case reflect.Int, reflect.Int64, reflect.Uint, reflect.Uint32, reflect.Uint64:
+		if v.Kind() != reflect.Int64 && v.Kind() != reflect.Int && v.Uint() > math.MaxInt64 {
+			return fmt.Errorf("%d overflows a BSON int64", v.Uint())
+		}
+
+		n := toInt(v)
+
+		if (v.Kind() == reflect.Int || v.Kind() == reflect.Uint) && n >= math.MinInt32 && n <= math.MaxInt32 {
+			writeHeader(buf, typeInt32, key)
+
+			buf.Write(binary.LittleEndian.AppendUint32(nil, uint32(n)))
+
+			return nil
+		}
+
+		writeHeader(buf, typeInt64, key)
+
+		buf.Write(binary.LittleEndian.AppendUint64(nil, uint64(n)))
+	case reflect.Float32, reflect.Float64:
+		writeHeader(buf, typeDouble, key)
+
+		buf.Write(binary.LittleEndian.AppendUint64(nil, math.Float64bits(v.Float())))
+	case reflect.String:
+		writeHeader(buf, typeString, key)
+
+		writeString(buf, v.String())
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && v.Type().Elem().Kind() == reflect.Uint8 {
+			writeHeader(buf, typeBinary, key)
+
+			buf.Write(binary.LittleEndian.AppendUint32(nil, uint32(v.Len())))
+			buf.WriteByte(0)
+			buf.Write(v.Bytes())
+
+			return nil
+		}
+
+		writeHeader(buf, typeArray, key)
+
+		return writeDoc(buf, v.Len(), func(i int) error {
+			return writeValue(buf, strconv.Itoa(i), v.Index(i))
+		})
+	case reflect.Map:
+		keys := make([]string, 0, v.Len())
+		values := make(map[string]reflect.Value, v.Len())
+
+		for iter := v.MapRange(); iter.Next(); {
+			k, err := mapKey(iter.Key())
+			if err != nil {
+				return err
+			}
+
+			keys = append(keys, k)
+			values[k] = iter.Value()
+		}
+
+		sort.Strings(keys)
+
+		writeHeader(buf, typeDocument, key)
+
+		return writeDoc(buf, len(keys), func(i int) error {
+			return writeValue(buf, keys[i], values[keys[i]])
+		})
+	case reflect.Struct:
+		writeHeader(buf, typeDocument, key)
+
+		return writeDoc(buf, 1, func(int) error {
+			return writeFields(buf, v)
+		})
+	default:
+		return fmt.Errorf("cannot encode %s as BSON", v.Type())
+	}
+
+	return nil
+}
+
+// writeMarshaled writes the values encoded by themselves, or having a BSON
+// type, e.g.: time.Time, returning false if it doesn't apply to the value.
+//
+//nolint:cyclop
+func writeMarshaled(buf *bytes.Buffer, key string, v reflect.Value) (bool, error) {
+	if isObjectID(v.Type()) {
+		writeHeader(buf, typeObjectID, key)
+
+		for i := 0; i < v.Len(); i++ {
+			buf.WriteByte(byte(v.Index(i).Uint()))
+		}
+
+		return true, nil
+	}
+
+	if !v.CanInterface() {
+		return false, nil
+	}
+
+	if method := v.MethodByName("MarshalBSONValue"); method.IsValid() && isValueMarshaler(method.Type()) {
+		out := method.Call(nil)
+
+		if err, _ := out[2].Interface().(error); err != nil {
+			return true, err
+		}
+
+		writeHeader(buf, byte(out[0].Uint()), key)
+
+		buf.Write(out[1].Bytes())
+
+		return true, nil
+	}
+
+	switch x := v.Interface().(type) {
+	case time.Time:
+		writeHeader(buf, typeDateTime, key)
+
+		buf.Write(binary.LittleEndian.AppendUint64(nil, uint64(x.UnixMilli())))
+	case json.Number:
+		if n, err := x.Int64(); err == nil {
+			writeHeader(buf, typeInt64, key)
+
+			buf.Write(binary.LittleEndian.AppendUint64(nil, uint64(n)))
+
+			return true, nil
+		}
+
+		f, err := x.Float64()
+		if err != nil {
+			return true, err
+		}
+
+		writeHeader(buf, typeDouble, key)
+
+		buf.Write(binary.LittleEndian.AppendUint64(nil, math.Float64bits(f)))
+	case marshaler:
+		b, err := x.MarshalBSON()
+		if err != nil {
+			return true, err
+		}
+
+		writeHeader(buf, typeDocument, key)
+
+		buf.Write(b)
+	case json.Marshaler:
+		b, err := x.MarshalJSON()
+		if err != nil {
+			return true, err
+		}
+
+		dec := json.NewDecoder(bytes.NewReader(b))
+		dec.UseNumber()
+
+		return true, writeElement(dec, buf, key)
+	case encoding.TextMarshaler:
+		b, err := x.MarshalText()
+		if err != nil {
+			return true, err
+		}
+
+		writeHeader(buf, typeString, key)
+
+		writeString(buf, string(b))
+	default:
+		return false, nil
+	}
+
+	return true, nil
+}
+
+// writeFields writes the exported fields of the struct, see D.MarshalBSON.
+// Embedded structs without a name, or with the inline option, are flattened.
+func writeFields(buf *bytes.Buffer, v reflect.Value) error {
+	t := v.Type()
+
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		fv := v.Field(i)
+
+		tag := f.Tag.Get("bson")
+		if tag == "" {
+			tag = f.Tag.Get("json")
+		}
+
+		name, opts, _ := strings.Cut(tag, ",")
+
+		if name == "-" {
+			continue
+		}
+
+		if (f.Anonymous && name == "") || strings.Contains(opts, "inline") {
+			inner := fv
+
+			if inner.Kind() == reflect.Pointer {
+				if inner.IsNil() {
+					continue
+				}
+
+				inner = inner.Elem()
+			}
+
+			if inner.Kind() == reflect.Struct {
+				if err := writeFields(buf, inner); err != nil {
+					return err
+				}
+
+				continue
+			}
+		}
+
+		if !f.IsExported() || (strings.Contains(opts, "omitempty") && isEmpty(fv)) {
+			continue
+		}
+
+		if name == "" {
+			name = strings.ToLower(f.Name)
+		}
+
+		if err := writeValue(buf, name, fv); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+//////
+// Helpers.
+//////
+
+// writeHeader writes the type, and key, of an element.
+func writeHeader(buf *bytes.Buffer, t byte, key string) {
+	buf.WriteByte(t)
+	buf.WriteString(key)
+	buf.WriteByte(0)
+}
+
+// writeString writes the value of a string element.
+func writeString(buf *bytes.Buffer, s string) {
+	buf.Write(binary.LittleEndian.AppendUint32(nil, uint32(len(s)+1)))
+	buf.WriteString(s)
+	buf.WriteByte(0)
+}
+
+// isNil checks if the value is a nil pointer, interface, map, or slice.
+func isNil(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface, reflect.Map, reflect.Slice:
+		return v.IsNil()
+	default:
+		return false
+	}
+}
+
+// isEmpty checks if the value is empty, for omitempty, like encoding/json.
+func isEmpty(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	default:
+		return v.IsZero()
+	}
+}
+
+// isObjectID checks if the type is an array of 12 bytes named ObjectID, e.g.:
+// primitive.ObjectID.
+func isObjectID(t reflect.Type) bool {
+	return t.Kind() == reflect.Array && t.Len() == 12 && t.Elem().Kind() == reflect.Uint8 && t.Name() == "ObjectID"
+}
+
+// isValueMarshaler checks if the type is the one of MarshalBSONValue, like
+// bson.ValueMarshaler: func() (bsontype.Type, []byte, error).
+func isValueMarshaler(t reflect.Type) bool {
+	return t.NumIn() == 0 &&
+		t.NumOut() == 3 &&
+		t.Out(0).Kind() == reflect.Uint8 &&
+		t.Out(1) == reflect.TypeOf([]byte(nil)) &&
+		t.Out(2) == reflect.TypeOf((*error)(nil)).Elem()
+}
+
+// toInt returns the value of a signed, or unsigned, integer.
+func toInt(v reflect.Value) int64 {
+	if v.CanInt() {
+		return v.Int()
+	}
+
+	return int64(v.Uint())
+}
+
+// mapKey returns the key of a map as a string, like encoding/json.
+func mapKey(k reflect.Value) (string, error) {
+	if k.Kind() == reflect.String {
+		return k.String(), nil
+	}
+
+	if k.CanInterface() {
+		if m, ok := k.Interface().(encoding.TextMarshaler); ok {
+			b, err := m.MarshalText()
+
+			return string(b), err
+		}
+	}
+
+	switch k.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(k.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return strconv.FormatUint(k.Uint(), 10), nil
+	default:
+		return "", fmt.Errorf("cannot encode map key %s as BSON", k.Type())
+	}
+}
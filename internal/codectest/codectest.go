@@ -0,0 +1,91 @@
+// Package codectest provides an in-memory shared.Encoder, and shared.Decoder,
+// for the tests of EncodeWith, and DecodeWith.
+package codectest
+
+import (
+	"io"
+	"reflect"
+)
+
+//////
+// Const, vars, and types.
+//////
+
+// TokenCodec is an in-memory shared.Encoder, and shared.Decoder. Encoded
+// values are appended to Tokens, and decoded values are taken from its head.
+type TokenCodec struct {
+	Tokens []any
+}
+
+//////
+// Methods.
+//////
+
+// EncodeArrayLen implements shared.Encoder.
+func (c *TokenCodec) EncodeArrayLen(n int) error { return c.Encode(n) }
+
+// EncodeMapLen implements shared.Encoder.
+func (c *TokenCodec) EncodeMapLen(n int) error { return c.Encode(n) }
+
+// EncodeString implements shared.Encoder.
+func (c *TokenCodec) EncodeString(s string) error { return c.Encode(s) }
+
+// Encode implements shared.Encoder.
+func (c *TokenCodec) Encode(v any) error {
+	c.Tokens = append(c.Tokens, v)
+
+	return nil
+}
+
+// DecodeArrayLen implements shared.Decoder.
+func (c *TokenCodec) DecodeArrayLen() (int, error) { return c.decodeInt() }
+
+// DecodeMapLen implements shared.Decoder.
+func (c *TokenCodec) DecodeMapLen() (int, error) { return c.decodeInt() }
+
+// DecodeString implements shared.Decoder.
+func (c *TokenCodec) DecodeString() (string, error) {
+	var s string
+
+	err := c.Decode(&s)
+
+	return s, err
+}
+
+// Decode implements shared.Decoder. It returns io.EOF if there are no more
+// tokens.
+func (c *TokenCodec) Decode(v any) error {
+	token, err := c.next()
+	if err != nil {
+		return err
+	}
+
+	reflect.ValueOf(v).Elem().Set(reflect.ValueOf(token))
+
+	return nil
+}
+
+//////
+// Helpers.
+//////
+
+// next takes the token at the head.
+func (c *TokenCodec) next() (any, error) {
+	if len(c.Tokens) == 0 {
+		return nil, io.EOF
+	}
+
+	token := c.Tokens[0]
+	c.Tokens = c.Tokens[1:]
+
+	return token, nil
+}
+
+// decodeInt decodes a length.
+func (c *TokenCodec) decodeInt() (int, error) {
+	var n int
+
+	err := c.Decode(&n)
+
+	return n, err
+}
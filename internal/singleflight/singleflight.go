@@ -0,0 +1,79 @@
+// Package singleflight de-duplicates concurrent calls computing the same key.
+package singleflight
+
+import (
+	"fmt"
+	"sync"
+)
+
+//////
+// Const, vars, and types.
+//////
+
+// call is an in-flight, or completed, call.
+type call[T any] struct {
+	wg sync.WaitGroup
+
+	value T
+	err   error
+}
+
+// Group runs at most one call per key at a time. The zero value is ready to
+// use.
+type Group[T any] struct {
+	mu    sync.Mutex
+	calls map[string]*call[T]
+}
+
+//////
+// Methods.
+//////
+
+// Do calls fn, unless a call for the same key is in flight, in which case it
+// waits for it, sharing its result. It reports whether the result was shared.
+func (g *Group[T]) Do(key string, fn func() (T, error)) (T, error, bool) {
+	g.mu.Lock()
+
+	if g.calls == nil {
+		g.calls = make(map[string]*call[T])
+	}
+
+	if c, ok := g.calls[key]; ok {
+		g.mu.Unlock()
+
+		c.wg.Wait()
+
+		return c.value, c.err, true
+	}
+
+	c := &call[T]{}
+	c.wg.Add(1)
+
+	g.calls[key] = c
+
+	g.mu.Unlock()
+
+	// Waiters must be released, even if fn panics, with an error, so they
+	// don't take the zero value for a result. The panic is propagated to the
+	// caller.
+	defer func() {
+		r := recover()
+		if r != nil {
+			c.err = fmt.Errorf("singleflight: panic: %v", r)
+		}
+
+		g.mu.Lock()
+		delete(g.calls, key)
+		g.mu.Unlock()
+
+		c.wg.Done()
+
+		if r != nil {
+			panic(r)
+		}
+	}()
+
+	c.value, c.err = fn()
+
+	return c.value, c.err, false
+}
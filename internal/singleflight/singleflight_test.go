@@ -0,0 +1,115 @@
+package singleflight
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGroupDo(t *testing.T) {
+	var (
+		g     Group[int]
+		calls int32
+		wg    sync.WaitGroup
+	)
+
+	release := make(chan struct{})
+	started := make(chan struct{})
+
+	go func() {
+		v, err, shared := g.Do("a", func() (int, error) {
+			atomic.AddInt32(&calls, 1)
+
+			close(started)
+
+			<-release
+
+			return 42, nil
+		})
+
+		assert.Equal(t, 42, v)
+		assert.NoError(t, err)
+		assert.False(t, shared)
+	}()
+
+	<-started
+
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			v, err, shared := g.Do("a", func() (int, error) {
+				atomic.AddInt32(&calls, 1)
+
+				return 0, nil
+			})
+
+			assert.Equal(t, 42, v)
+			assert.NoError(t, err)
+			assert.True(t, shared)
+		}()
+	}
+
+	// Gives the callers time to join the in-flight call.
+	time.Sleep(50 * time.Millisecond)
+
+	close(release)
+
+	wg.Wait()
+
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+
+	// Completed calls aren't cached.
+	_, err, shared := g.Do("a", func() (int, error) { return 0, errors.New("boom") })
+	assert.EqualError(t, err, "boom")
+	assert.False(t, shared)
+}
+
+func TestGroupDoPanic(t *testing.T) {
+	var g Group[int]
+
+	started := make(chan struct{})
+
+	var (
+		waiterErr    error
+		waiterShared bool
+		wg           sync.WaitGroup
+	)
+
+	wg.Add(1)
+
+	// A concurrent waiter gets an error, not the zero value.
+	go func() {
+		defer wg.Done()
+
+		<-started
+
+		_, waiterErr, waiterShared = g.Do("a", func() (int, error) { return 2, nil })
+	}()
+
+	assert.PanicsWithValue(t, "boom", func() {
+		_, _, _ = g.Do("a", func() (int, error) {
+			close(started)
+
+			// Lets the waiter join the call.
+			time.Sleep(50 * time.Millisecond)
+
+			panic("boom")
+		})
+	}, "the leader panics")
+
+	wg.Wait()
+
+	assert.True(t, waiterShared)
+	assert.EqualError(t, waiterErr, "singleflight: panic: boom")
+
+	v, err, _ := g.Do("a", func() (int, error) { return 1, nil })
+	assert.Equal(t, 1, v)
+	assert.NoError(t, err)
+}
@@ -0,0 +1,143 @@
+// Package watch implements the fan-out of collection change events to
+// watchers.
+package watch
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+)
+
+//////
+// Const, vars, and types.
+//////
+
+// watcher is a subscription. Events are queued, so publishing never blocks,
+// and delivered in order by a dedicated goroutine.
+type watcher[E any] struct {
+	mu    sync.Mutex
+	queue []E
+
+	notify chan struct{}
+	out    chan E
+}
+
+// Hub delivers published events to all watchers. The zero value is ready to
+// use.
+type Hub[E any] struct {
+	mu       sync.Mutex
+	watchers map[*watcher[E]]struct{}
+
+	active int32
+}
+
+//////
+// Methods.
+//////
+
+// push queues the event, waking up the delivery goroutine.
+func (w *watcher[E]) push(e E) {
+	w.mu.Lock()
+	w.queue = append(w.queue, e)
+	w.mu.Unlock()
+
+	select {
+	case w.notify <- struct{}{}:
+	default:
+	}
+}
+
+// pop dequeues the next event, if any.
+func (w *watcher[E]) pop() (E, bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if len(w.queue) == 0 {
+		return *new(E), false
+	}
+
+	e := w.queue[0]
+
+	w.queue[0] = *new(E)
+	w.queue = w.queue[1:]
+
+	return e, true
+}
+
+// run delivers queued events until the context is done.
+func (w *watcher[E]) run(ctx context.Context, h *Hub[E]) {
+	defer close(w.out)
+	defer h.remove(w)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-w.notify:
+		}
+
+		for e, ok := w.pop(); ok; e, ok = w.pop() {
+			select {
+			case w.out <- e:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}
+}
+
+// remove unsubscribes the watcher.
+func (h *Hub[E]) remove(w *watcher[E]) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	delete(h.watchers, w)
+
+	atomic.StoreInt32(&h.active, int32(len(h.watchers)))
+}
+
+// Active checks if there are watchers. It allows to skip building events
+// nobody will receive.
+func (h *Hub[E]) Active() bool {
+	return atomic.LoadInt32(&h.active) > 0
+}
+
+// Publish sends the event to all watchers. It never blocks, events are queued
+// until the watcher receives them.
+func (h *Hub[E]) Publish(e E) {
+	if !h.Active() {
+		return
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for w := range h.watchers {
+		w.push(e)
+	}
+}
+
+// Subscribe returns a channel receiving, in order, all events published after
+// the call. The channel is closed when the context is done.
+func (h *Hub[E]) Subscribe(ctx context.Context) <-chan E {
+	w := &watcher[E]{
+		notify: make(chan struct{}, 1),
+		out:    make(chan E),
+	}
+
+	h.mu.Lock()
+
+	if h.watchers == nil {
+		h.watchers = make(map[*watcher[E]]struct{})
+	}
+
+	h.watchers[w] = struct{}{}
+
+	atomic.StoreInt32(&h.active, int32(len(h.watchers)))
+
+	h.mu.Unlock()
+
+	go w.run(ctx, h)
+
+	return w.out
+}
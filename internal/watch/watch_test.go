@@ -0,0 +1,47 @@
+package watch
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHub(t *testing.T) {
+	var h Hub[int]
+
+	assert.False(t, h.Active())
+
+	h.Publish(0)
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	ch1 := h.Subscribe(ctx)
+	ch2 := h.Subscribe(ctx)
+
+	assert.True(t, h.Active())
+
+	for i := 1; i <= 100; i++ {
+		h.Publish(i)
+	}
+
+	for _, ch := range []<-chan int{ch1, ch2} {
+		for i := 1; i <= 100; i++ {
+			assert.Equal(t, i, <-ch)
+		}
+	}
+
+	cancel()
+
+	for _, ch := range []<-chan int{ch1, ch2} {
+		select {
+		case _, ok := <-ch:
+			assert.False(t, ok)
+		case <-time.After(time.Second):
+			t.Fatal("channel not closed")
+		}
+	}
+
+	assert.False(t, h.Active())
+}
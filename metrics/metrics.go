@@ -0,0 +1,262 @@
+package metrics
+
+import (
+	"encoding/json"
+	"expvar"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+//////
+// Const, vars, and types.
+//////
+
+// Snapshot is a point-in-time copy of the metrics of a collection.
+type Snapshot struct {
+	// Name of the collection.
+	Name string `json:"name"`
+
+	// Operations is the number of calls per operation.
+	Operations map[string]uint64 `json:"operations"`
+
+	// Size is the last observed number of elements.
+	Size int64 `json:"size"`
+
+	// Bytes is the last estimated memory usage, in bytes, 0 if never
+	// estimated.
+	Bytes int64 `json:"bytes"`
+
+	// Evictions is the number of elements evicted per reason, e.g.:
+	// "capacity", or "expired".
+	Evictions map[string]uint64 `json:"evictions"`
+
+	// Hits is the number of lookups which found the element.
+	Hits uint64 `json:"hits"`
+
+	// Misses is the number of lookups which didn't find the element.
+	Misses uint64 `json:"misses"`
+
+	// HitRatio is Hits / (Hits + Misses), 0 if there were no lookups.
+	HitRatio float64 `json:"hitRatio"`
+
+	// LockAcquisitions is the number of observed lock acquisitions.
+	LockAcquisitions uint64 `json:"lockAcquisitions"`
+
+	// LockWait is the total time spent waiting for locks.
+	LockWait time.Duration `json:"lockWait"`
+}
+
+// Metrics tracks operation counts, sizes, lock wait times, and hit/miss ratios
+// of a collection. All methods are safe for concurrent use, and safe to be
+// called on a nil receiver, in which case they are no-op.
+type Metrics struct {
+	name string
+
+	operations sync.Map
+	evictions  sync.Map
+
+	size  int64
+	bytes int64
+
+	hits   uint64
+	misses uint64
+
+	lockAcquisitions uint64
+	lockWaitNanos    int64
+}
+
+//////
+// Methods.
+//////
+
+// Name returns the name of the collection.
+func (m *Metrics) Name() string {
+	if m == nil {
+		return ""
+	}
+
+	return m.name
+}
+
+// String is the stringer implementation. It also satisfies the expvar.Var
+// interface, returning the snapshot as JSON.
+func (m *Metrics) String() string {
+	b, err := json.Marshal(m.Snapshot())
+	if err != nil {
+		return "{}"
+	}
+
+	return string(b)
+}
+
+// Operation records a call to the given operation.
+func (m *Metrics) Operation(op string) {
+	if m == nil {
+		return
+	}
+
+	increment(&m.operations, op)
+}
+
+// Evicted records an element evicted for the given reason, e.g.: "capacity".
+func (m *Metrics) Evicted(reason string) {
+	if m == nil {
+		return
+	}
+
+	increment(&m.evictions, reason)
+}
+
+// Hit records a lookup which found the element.
+func (m *Metrics) Hit() {
+	if m == nil {
+		return
+	}
+
+	atomic.AddUint64(&m.hits, 1)
+}
+
+// Miss records a lookup which didn't find the element.
+func (m *Metrics) Miss() {
+	if m == nil {
+		return
+	}
+
+	atomic.AddUint64(&m.misses, 1)
+}
+
+// Lookup records either a hit or a miss.
+func (m *Metrics) Lookup(found bool) {
+	if found {
+		m.Hit()
+
+		return
+	}
+
+	m.Miss()
+}
+
+// SetSize records the current number of elements.
+func (m *Metrics) SetSize(n int) {
+	if m == nil {
+		return
+	}
+
+	atomic.StoreInt64(&m.size, int64(n))
+}
+
+// SetBytes records the estimated memory usage, in bytes.
+func (m *Metrics) SetBytes(n int64) {
+	if m == nil {
+		return
+	}
+
+	atomic.StoreInt64(&m.bytes, n)
+}
+
+// ObserveLockWait records the time spent waiting for a lock.
+func (m *Metrics) ObserveLockWait(d time.Duration) {
+	if m == nil {
+		return
+	}
+
+	atomic.AddUint64(&m.lockAcquisitions, 1)
+	atomic.AddInt64(&m.lockWaitNanos, int64(d))
+}
+
+// Snapshot returns a point-in-time copy of the metrics.
+func (m *Metrics) Snapshot() Snapshot {
+	if m == nil {
+		return Snapshot{Operations: map[string]uint64{}, Evictions: map[string]uint64{}}
+	}
+
+	s := Snapshot{
+		Name:             m.name,
+		Operations:       map[string]uint64{},
+		Evictions:        map[string]uint64{},
+		Size:             atomic.LoadInt64(&m.size),
+		Bytes:            atomic.LoadInt64(&m.bytes),
+		Hits:             atomic.LoadUint64(&m.hits),
+		Misses:           atomic.LoadUint64(&m.misses),
+		LockAcquisitions: atomic.LoadUint64(&m.lockAcquisitions),
+		LockWait:         time.Duration(atomic.LoadInt64(&m.lockWaitNanos)),
+	}
+
+	load(&m.operations, s.Operations)
+	load(&m.evictions, s.Evictions)
+
+	if lookups := s.Hits + s.Misses; lookups > 0 {
+		s.HitRatio = float64(s.Hits) / float64(lookups)
+	}
+
+	return s
+}
+
+// OperationNames returns the name of all recorded operations, sorted.
+func (s Snapshot) OperationNames() []string {
+	return sortedKeys(s.Operations)
+}
+
+// EvictionReasons returns all recorded eviction reasons, sorted.
+func (s Snapshot) EvictionReasons() []string {
+	return sortedKeys(s.Evictions)
+}
+
+// Publish exposes the metrics via expvar under its name. Like expvar.Publish,
+// it panics if the name is already registered.
+func (m *Metrics) Publish() *Metrics {
+	expvar.Publish(m.name, m)
+
+	return m
+}
+
+//////
+// Helpers.
+//////
+
+// increment increments the counter of the key, creating it if needed.
+func increment(counters *sync.Map, key string) {
+	counter, ok := counters.Load(key)
+	if !ok {
+		counter, _ = counters.LoadOrStore(key, new(uint64))
+	}
+
+	//nolint:forcetypeassert
+	atomic.AddUint64(counter.(*uint64), 1)
+}
+
+// load copies the counters into dst.
+func load(counters *sync.Map, dst map[string]uint64) {
+	counters.Range(func(key, value any) bool {
+		//nolint:forcetypeassert
+		dst[key.(string)] = atomic.LoadUint64(value.(*uint64))
+
+		return true
+	})
+}
+
+// sortedKeys returns the keys of the counters, sorted.
+func sortedKeys(counters map[string]uint64) []string {
+	keys := make([]string, 0, len(counters))
+
+	for key := range counters {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
+
+//////
+// Factory.
+//////
+
+// New creates a new Metrics for the collection with the given name.
+func New(name string) *Metrics {
+	return &Metrics{
+		name: name,
+	}
+}
@@ -0,0 +1,71 @@
+package metrics
+
+import (
+	"encoding/json"
+	"expvar"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMetrics(t *testing.T) {
+	m := New("test")
+
+	m.Operation("add")
+	m.Operation("add")
+	m.Operation("get")
+	m.Evicted("capacity")
+	m.Evicted("expired")
+	m.Evicted("capacity")
+	m.Hit()
+	m.Lookup(true)
+	m.Lookup(false)
+	m.Miss()
+	m.SetSize(3)
+	m.SetBytes(1024)
+	m.ObserveLockWait(time.Millisecond)
+
+	s := m.Snapshot()
+
+	assert.Equal(t, "test", s.Name)
+	assert.Equal(t, map[string]uint64{"add": 2, "get": 1}, s.Operations)
+	assert.Equal(t, []string{"add", "get"}, s.OperationNames())
+	assert.Equal(t, map[string]uint64{"capacity": 2, "expired": 1}, s.Evictions)
+	assert.Equal(t, []string{"capacity", "expired"}, s.EvictionReasons())
+	assert.Equal(t, int64(3), s.Size)
+	assert.Equal(t, int64(1024), s.Bytes)
+	assert.Equal(t, uint64(2), s.Hits)
+	assert.Equal(t, uint64(2), s.Misses)
+	assert.Equal(t, 0.5, s.HitRatio)
+	assert.Equal(t, uint64(1), s.LockAcquisitions)
+	assert.Equal(t, time.Millisecond, s.LockWait)
+}
+
+func TestMetricsNil(t *testing.T) {
+	var m *Metrics
+
+	m.Operation("add")
+	m.Evicted("capacity")
+	m.Hit()
+	m.Miss()
+	m.SetSize(1)
+	m.SetBytes(1)
+	m.ObserveLockWait(time.Second)
+
+	assert.Equal(t, "", m.Name())
+	assert.Empty(t, m.Snapshot().Operations)
+	assert.Empty(t, m.Snapshot().Evictions)
+}
+
+func TestMetricsPublish(t *testing.T) {
+	m := New("metrics_test_publish").Publish()
+	m.Operation("add")
+
+	v := expvar.Get("metrics_test_publish")
+	assert.NotNil(t, v)
+
+	var s Snapshot
+	assert.NoError(t, json.Unmarshal([]byte(v.String()), &s))
+	assert.Equal(t, uint64(1), s.Operations["add"])
+}
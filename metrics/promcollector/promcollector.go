@@ -0,0 +1,117 @@
+package promcollector
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/thalesfsp/go-common-types/metrics"
+)
+
+//////
+// Const, vars, and types.
+//////
+
+// Collector is a prometheus.Collector adapter for collection metrics.
+type Collector struct {
+	metrics []*metrics.Metrics
+
+	operations       *prometheus.Desc
+	evictions        *prometheus.Desc
+	size             *prometheus.Desc
+	bytes            *prometheus.Desc
+	hits             *prometheus.Desc
+	misses           *prometheus.Desc
+	lockAcquisitions *prometheus.Desc
+	lockWait         *prometheus.Desc
+}
+
+//////
+// Methods.
+//////
+
+// Describe implements the prometheus.Collector interface.
+func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.operations
+	ch <- c.evictions
+	ch <- c.size
+	ch <- c.bytes
+	ch <- c.hits
+	ch <- c.misses
+	ch <- c.lockAcquisitions
+	ch <- c.lockWait
+}
+
+// Collect implements the prometheus.Collector interface.
+func (c *Collector) Collect(ch chan<- prometheus.Metric) {
+	for _, m := range c.metrics {
+		s := m.Snapshot()
+
+		for _, op := range s.OperationNames() {
+			ch <- prometheus.MustNewConstMetric(c.operations, prometheus.CounterValue, float64(s.Operations[op]), s.Name, op)
+		}
+
+		for _, reason := range s.EvictionReasons() {
+			ch <- prometheus.MustNewConstMetric(c.evictions, prometheus.CounterValue, float64(s.Evictions[reason]), s.Name, reason)
+		}
+
+		ch <- prometheus.MustNewConstMetric(c.size, prometheus.GaugeValue, float64(s.Size), s.Name)
+		ch <- prometheus.MustNewConstMetric(c.bytes, prometheus.GaugeValue, float64(s.Bytes), s.Name)
+		ch <- prometheus.MustNewConstMetric(c.hits, prometheus.CounterValue, float64(s.Hits), s.Name)
+		ch <- prometheus.MustNewConstMetric(c.misses, prometheus.CounterValue, float64(s.Misses), s.Name)
+		ch <- prometheus.MustNewConstMetric(c.lockAcquisitions, prometheus.CounterValue, float64(s.LockAcquisitions), s.Name)
+		ch <- prometheus.MustNewConstMetric(c.lockWait, prometheus.CounterValue, s.LockWait.Seconds(), s.Name)
+	}
+}
+
+//////
+// Factory.
+//////
+
+// New creates a new Collector exposing the given metrics. Metric names are
+// prefixed with the given namespace.
+func New(namespace string, m ...*metrics.Metrics) *Collector {
+	labels := []string{"collection"}
+
+	return &Collector{
+		metrics: m,
+
+		operations: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "collection", "operations_total"),
+			"Number of operations per collection and operation.",
+			[]string{"collection", "operation"}, nil,
+		),
+		evictions: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "collection", "evictions_total"),
+			"Number of evicted elements per collection and reason.",
+			[]string{"collection", "reason"}, nil,
+		),
+		size: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "collection", "size"),
+			"Number of elements in the collection.",
+			labels, nil,
+		),
+		bytes: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "collection", "bytes"),
+			"Estimated memory usage of the collection, in bytes.",
+			labels, nil,
+		),
+		hits: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "collection", "hits_total"),
+			"Number of lookups which found the element.",
+			labels, nil,
+		),
+		misses: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "collection", "misses_total"),
+			"Number of lookups which didn't find the element.",
+			labels, nil,
+		),
+		lockAcquisitions: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "collection", "lock_acquisitions_total"),
+			"Number of observed lock acquisitions.",
+			labels, nil,
+		),
+		lockWait: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "collection", "lock_wait_seconds_total"),
+			"Total time spent waiting for locks.",
+			labels, nil,
+		),
+	}
+}
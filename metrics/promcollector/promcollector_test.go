@@ -0,0 +1,88 @@
+package promcollector
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/thalesfsp/go-common-types/metrics"
+)
+
+func TestCollector(t *testing.T) {
+	m := metrics.New("users")
+
+	m.Operation("add")
+	m.Operation("add")
+	m.Operation("delete")
+	m.Evicted("ttl")
+	m.Lookup(true)
+	m.Lookup(false)
+	m.Lookup(false)
+	m.SetSize(3)
+	m.SetBytes(128)
+	m.ObserveLockWait(1500 * time.Millisecond)
+
+	registry := prometheus.NewPedanticRegistry()
+
+	c := New("app", m)
+
+	assert.NoError(t, registry.Register(c))
+
+	// Same descriptors can't be registered twice.
+	assert.Error(t, registry.Register(New("app", m)))
+
+	expected := `
+# HELP app_collection_bytes Estimated memory usage of the collection, in bytes.
+# TYPE app_collection_bytes gauge
+app_collection_bytes{collection="users"} 128
+# HELP app_collection_evictions_total Number of evicted elements per collection and reason.
+# TYPE app_collection_evictions_total counter
+app_collection_evictions_total{collection="users",reason="ttl"} 1
+# HELP app_collection_hits_total Number of lookups which found the element.
+# TYPE app_collection_hits_total counter
+app_collection_hits_total{collection="users"} 1
+# HELP app_collection_lock_wait_seconds_total Total time spent waiting for locks.
+# TYPE app_collection_lock_wait_seconds_total counter
+app_collection_lock_wait_seconds_total{collection="users"} 1.5
+# HELP app_collection_misses_total Number of lookups which didn't find the element.
+# TYPE app_collection_misses_total counter
+app_collection_misses_total{collection="users"} 2
+# HELP app_collection_operations_total Number of operations per collection and operation.
+# TYPE app_collection_operations_total counter
+app_collection_operations_total{collection="users",operation="add"} 2
+app_collection_operations_total{collection="users",operation="delete"} 1
+# HELP app_collection_size Number of elements in the collection.
+# TYPE app_collection_size gauge
+app_collection_size{collection="users"} 3
+`
+
+	assert.NoError(t, testutil.GatherAndCompare(registry, strings.NewReader(expected),
+		"app_collection_bytes",
+		"app_collection_evictions_total",
+		"app_collection_hits_total",
+		"app_collection_lock_wait_seconds_total",
+		"app_collection_misses_total",
+		"app_collection_operations_total",
+		"app_collection_size",
+	))
+
+	// Collections are exposed as separate series.
+	other := metrics.New("orders")
+
+	other.SetSize(7)
+
+	c = New("app", m, other)
+
+	// 2 operations, 1 eviction, and 6 gauges, or counters, for users, and the
+	// latter for orders.
+	assert.Equal(t, 2+1+6+6, testutil.CollectAndCount(c))
+	assert.Equal(t, 2, testutil.CollectAndCount(c, "app_collection_size"))
+
+	problems, err := testutil.CollectAndLint(c)
+
+	assert.NoError(t, err)
+	assert.Empty(t, problems)
+}
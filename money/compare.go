@@ -0,0 +1,92 @@
+package money
+
+import (
+	"strings"
+
+	"github.com/thalesfsp/go-common-types/shared"
+)
+
+//////
+// Const, vars, and types.
+//////
+
+// decimalEqualer is the shared.Equaler of decimals, see Decimal.Equal.
+type decimalEqualer struct{}
+
+// moneyEqualer is the shared.Equaler of amounts, see Money.Equal.
+type moneyEqualer struct{}
+
+//////
+// Methods.
+//////
+
+// Equal implements the shared.Equaler interface.
+func (decimalEqualer) Equal(a, b Decimal) bool {
+	return a.Equal(b)
+}
+
+// Hash implements the shared.Equaler interface.
+func (decimalEqualer) Hash(d Decimal) string {
+	return shared.GenerateHash(d.canonical())
+}
+
+// Equal implements the shared.Equaler interface.
+func (moneyEqualer) Equal(a, b Money) bool {
+	return a.Equal(b)
+}
+
+// Hash implements the shared.Equaler interface.
+func (moneyEqualer) Hash(m Money) string {
+	return shared.GenerateHash(m.amount.canonical() + " " + m.currency)
+}
+
+// canonical returns the string of the value without trailing decimal zeros,
+// the same for equal values, e.g.: "1" for 1.00.
+func (d Decimal) canonical() string {
+	s := d.String()
+
+	if d.scale > 0 {
+		s = strings.TrimSuffix(strings.TrimRight(s, "0"), ".")
+	}
+
+	return s
+}
+
+//////
+// Exported functionalities.
+//////
+
+// DecimalEqualer returns the shared.Equaler of decimals, by Equal, e.g.: for
+// a set of decimals, with safeset.WithEqualer. Decimals can't be compared with
+// ==, nor used as map keys.
+func DecimalEqualer() shared.Equaler[Decimal] {
+	return decimalEqualer{}
+}
+
+// DecimalComparer returns the shared.Comparer of decimals, by Cmp, e.g.: for
+// SafeSlice.SortWith, or SortedInsert.
+func DecimalComparer() shared.Comparer[Decimal] {
+	return shared.CompareFunc[Decimal](func(a, b Decimal) int {
+		return a.Cmp(b)
+	})
+}
+
+// Equaler returns the shared.Equaler of amounts, by Equal, e.g.: for a set of
+// amounts, with safeset.WithEqualer. Amounts can't be compared with ==, nor
+// used as map keys.
+func Equaler() shared.Equaler[Money] {
+	return moneyEqualer{}
+}
+
+// Comparer returns the shared.Comparer of amounts, by currency, then amount,
+// e.g.: for SafeSlice.SortWith, or SortedInsert. Unlike Cmp, it orders
+// amounts in different currencies.
+func Comparer() shared.Comparer[Money] {
+	return shared.CompareFunc[Money](func(a, b Money) int {
+		if c := strings.Compare(a.currency, b.currency); c != 0 {
+			return c
+		}
+
+		return a.amount.Cmp(b.amount)
+	})
+}
@@ -0,0 +1,52 @@
+package money
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/thalesfsp/go-common-types/safeset"
+	"github.com/thalesfsp/go-common-types/safeslice"
+)
+
+func TestDecimalEqualer(t *testing.T) {
+	one, oneZero := MustParseDecimal("1"), MustParseDecimal("1.0")
+
+	// The coefficients are different pointers.
+	assert.False(t, one == MustParseDecimal("1"))
+
+	e := DecimalEqualer()
+
+	assert.True(t, e.Equal(one, oneZero))
+	assert.False(t, e.Equal(one, MustParseDecimal("10")))
+	assert.Equal(t, e.Hash(one), e.Hash(oneZero))
+	assert.Equal(t, e.Hash(Decimal{}), e.Hash(MustParseDecimal("0.00")))
+	assert.Equal(t, e.Hash(MustParseDecimal("-1.50")), e.Hash(MustParseDecimal("-1.5")))
+	assert.NotEqual(t, e.Hash(one), e.Hash(MustParseDecimal("10")))
+
+	s := safeslice.New(MustParseDecimal("2"), oneZero, MustParseDecimal("-3"))
+	s.SortWith(DecimalComparer())
+
+	assert.Equal(t, "[-3 1.0 2]", fmt.Sprint(s.Values()))
+}
+
+func TestEqualer(t *testing.T) {
+	e := Equaler()
+
+	assert.True(t, e.Equal(Must("1.0", "USD"), Must("1", "USD")))
+	assert.False(t, e.Equal(Must("1", "USD"), Must("1", "EUR")))
+	assert.Equal(t, e.Hash(Must("1.0", "USD")), e.Hash(Must("1", "USD")))
+	assert.NotEqual(t, e.Hash(Must("1", "USD")), e.Hash(Must("1", "EUR")))
+
+	set := safeset.NewWithOptions(safeset.WithEqualer(e))
+	set.Add(Must("1", "USD"))
+	set.Add(Must("1.00", "USD"))
+	set.Add(Must("1", "EUR"))
+
+	assert.Equal(t, 2, set.Size())
+
+	s := safeslice.New(Must("2", "USD"), Must("1", "USD"), Must("5", "EUR"))
+	s.SortWith(Comparer())
+
+	assert.Equal(t, "[5.00 EUR 1.00 USD 2.00 USD]", fmt.Sprint(s.Values()))
+}
@@ -0,0 +1,336 @@
+package money
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+//////
+// Const, vars, and types.
+//////
+
+// RoundingMode is how a value is rounded to a number of decimal places.
+type RoundingMode int
+
+// Rounding modes.
+const (
+	// RoundHalfEven rounds to the nearest, ties to the even digit, i.e.:
+	// banker's rounding. It's the default, e.g.: of the zero value.
+	RoundHalfEven RoundingMode = iota
+
+	// RoundHalfUp rounds to the nearest, ties away from zero.
+	RoundHalfUp
+
+	// RoundHalfDown rounds to the nearest, ties toward zero.
+	RoundHalfDown
+
+	// RoundUp rounds away from zero.
+	RoundUp
+
+	// RoundDown rounds toward zero, i.e.: truncates.
+	RoundDown
+
+	// RoundCeiling rounds toward positive infinity.
+	RoundCeiling
+
+	// RoundFloor rounds toward negative infinity.
+	RoundFloor
+)
+
+// ErrInvalidDecimal is returned when parsing an invalid decimal.
+var ErrInvalidDecimal = errors.New("invalid decimal")
+
+// ErrDivisionByZero is returned when dividing by zero.
+var ErrDivisionByZero = errors.New("division by zero")
+
+// Decimal is an exact, arbitrary precision, decimal number: an integer
+// coefficient, and a number of decimal places. It's immutable, operations
+// return new values. The zero value is 0.
+//
+// The coefficient is a *big.Int, so == compares pointers: equal values, e.g.:
+// 1.0, and 1, or two parsed 1, aren't ==. Use Equal, or Cmp, and, e.g.: for
+// sets, DecimalEqualer, instead of map keys.
+type Decimal struct {
+	coef  *big.Int
+	scale int32
+}
+
+//////
+// Methods.
+//////
+
+// int returns the coefficient, never nil.
+func (d Decimal) int() *big.Int {
+	if d.coef == nil {
+		return new(big.Int)
+	}
+
+	return d.coef
+}
+
+// rescale returns the coefficient with the given, larger or equal, scale.
+func (d Decimal) rescale(scale int32) *big.Int {
+	return new(big.Int).Mul(d.int(), pow10(scale-d.scale))
+}
+
+// align returns the coefficients of both decimals with the same scale.
+func align(a, b Decimal) (x, y *big.Int, scale int32) {
+	scale = a.scale
+
+	if b.scale > scale {
+		scale = b.scale
+	}
+
+	return a.rescale(scale), b.rescale(scale), scale
+}
+
+// String is the stringer implementation, e.g.: "-12.340". Trailing zeros are
+// kept.
+func (d Decimal) String() string {
+	digits := new(big.Int).Abs(d.int()).String()
+
+	if d.scale > 0 {
+		if pad := int(d.scale) + 1 - len(digits); pad > 0 {
+			digits = strings.Repeat("0", pad) + digits
+		}
+
+		digits = digits[:len(digits)-int(d.scale)] + "." + digits[len(digits)-int(d.scale):]
+	}
+
+	if d.Sign() < 0 {
+		return "-" + digits
+	}
+
+	return digits
+}
+
+// StringFixed returns the string with at least the given decimal places,
+// padding with zeros, e.g.: "12.30" for 12.3, and 2.
+func (d Decimal) StringFixed(places int32) string {
+	if d.scale >= places {
+		return d.String()
+	}
+
+	return Decimal{coef: d.rescale(places), scale: places}.String()
+}
+
+// Scale returns the number of decimal places.
+func (d Decimal) Scale() int32 {
+	return d.scale
+}
+
+// Sign returns -1, 0, or 1.
+func (d Decimal) Sign() int {
+	return d.int().Sign()
+}
+
+// IsZero checks if the value is 0.
+func (d Decimal) IsZero() bool {
+	return d.Sign() == 0
+}
+
+// Cmp returns -1, 0, or 1, comparing the values, e.g.: 1.0 equals 1.
+func (d Decimal) Cmp(other Decimal) int {
+	x, y, _ := align(d, other)
+
+	return x.Cmp(y)
+}
+
+// Equal checks if the values are equal, regardless of the scale.
+func (d Decimal) Equal(other Decimal) bool {
+	return d.Cmp(other) == 0
+}
+
+// Add returns d + other.
+func (d Decimal) Add(other Decimal) Decimal {
+	x, y, scale := align(d, other)
+
+	return Decimal{coef: x.Add(x, y), scale: scale}
+}
+
+// Sub returns d - other.
+func (d Decimal) Sub(other Decimal) Decimal {
+	x, y, scale := align(d, other)
+
+	return Decimal{coef: x.Sub(x, y), scale: scale}
+}
+
+// Mul returns d * other, exactly.
+func (d Decimal) Mul(other Decimal) Decimal {
+	return Decimal{coef: new(big.Int).Mul(d.int(), other.int()), scale: d.scale + other.scale}
+}
+
+// Div returns d / other, rounded to the given decimal places, see Round. It
+// fails if other is 0.
+func (d Decimal) Div(other Decimal, places int32, mode RoundingMode) (Decimal, error) {
+	if other.IsZero() {
+		return Decimal{}, ErrDivisionByZero
+	}
+
+	// d / other = (cd / 10^sd) / (co / 10^so), multiplied by 10^places, so
+	// cd * 10^(places + so - sd) / co.
+	num, den := new(big.Int).Set(d.int()), new(big.Int).Set(other.int())
+
+	if exp := places + other.scale - d.scale; exp >= 0 {
+		num.Mul(num, pow10(exp))
+	} else {
+		den.Mul(den, pow10(-exp))
+	}
+
+	return normalize(quo(num, den, mode), places), nil
+}
+
+// Neg returns -d.
+func (d Decimal) Neg() Decimal {
+	return Decimal{coef: new(big.Int).Neg(d.int()), scale: d.scale}
+}
+
+// Abs returns the absolute value.
+func (d Decimal) Abs() Decimal {
+	return Decimal{coef: new(big.Int).Abs(d.int()), scale: d.scale}
+}
+
+// Round returns the value rounded to the given decimal places, or, if
+// negative, to tens, hundreds, etc., e.g.: -2 rounds 12345 to 12300. Values
+// with less decimal places are returned unchanged.
+func (d Decimal) Round(places int32, mode RoundingMode) Decimal {
+	if d.scale <= places {
+		return d
+	}
+
+	return normalize(quo(d.int(), pow10(d.scale-places), mode), places)
+}
+
+// Float64 returns the nearest float64, e.g.: for display, or statistics.
+func (d Decimal) Float64() float64 {
+	f, _ := new(big.Rat).SetFrac(d.int(), pow10(d.scale)).Float64()
+
+	return f
+}
+
+// MarshalText implements the encoding.TextMarshaler interface.
+func (d Decimal) MarshalText() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface, the
+// inverse of MarshalText.
+func (d *Decimal) UnmarshalText(text []byte) error {
+	parsed, err := ParseDecimal(string(text))
+	if err != nil {
+		return err
+	}
+
+	*d = parsed
+
+	return nil
+}
+
+//////
+// Helpers.
+//////
+
+// normalize returns the decimal coef * 10^-scale, with a scale of 0 if
+// negative, as decimals have no negative scale.
+func normalize(coef *big.Int, scale int32) Decimal {
+	if scale < 0 {
+		return Decimal{coef: coef.Mul(coef, pow10(-scale))}
+	}
+
+	return Decimal{coef: coef, scale: scale}
+}
+
+// pow10 returns 10^n, n not negative.
+func pow10(n int32) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(n)), nil)
+}
+
+// quo returns num / den, rounded to an integer with the mode.
+func quo(num, den *big.Int, mode RoundingMode) *big.Int {
+	if den.Sign() < 0 {
+		num, den = new(big.Int).Neg(num), new(big.Int).Neg(den)
+	}
+
+	q, r := new(big.Int).QuoRem(num, den, new(big.Int))
+	if r.Sign() == 0 {
+		return q
+	}
+
+	// The quotient is truncated toward zero, away moves it away from zero.
+	sign := num.Sign()
+
+	// half compares the remainder to half of the divisor.
+	twice := new(big.Int).Abs(r)
+	half := twice.Lsh(twice, 1).Cmp(den)
+
+	var away bool
+
+	switch mode {
+	case RoundHalfUp:
+		away = half >= 0
+	case RoundHalfDown:
+		away = half > 0
+	case RoundUp:
+		away = true
+	case RoundDown:
+		away = false
+	case RoundCeiling:
+		away = sign > 0
+	case RoundFloor:
+		away = sign < 0
+	default:
+		away = half > 0 || (half == 0 && q.Bit(0) == 1)
+	}
+
+	if away {
+		q.Add(q, big.NewInt(int64(sign)))
+	}
+
+	return q
+}
+
+//////
+// Factory.
+//////
+
+// ParseDecimal parses a decimal, e.g.: "-12.34". Exponents aren't supported.
+func ParseDecimal(s string) (Decimal, error) {
+	digits := s
+
+	if strings.HasPrefix(s, "-") || strings.HasPrefix(s, "+") {
+		digits = s[1:]
+	}
+
+	integer, fraction, _ := strings.Cut(digits, ".")
+
+	if integer+fraction == "" || strings.Trim(integer+fraction, "0123456789") != "" {
+		return Decimal{}, fmt.Errorf("%w: %q", ErrInvalidDecimal, s)
+	}
+
+	coef, _ := new(big.Int).SetString(integer+fraction, 10)
+
+	if strings.HasPrefix(s, "-") {
+		coef.Neg(coef)
+	}
+
+	return Decimal{coef: coef, scale: int32(len(fraction))}, nil
+}
+
+// MustParseDecimal is like ParseDecimal, panicking on error, e.g.: for
+// constants.
+func MustParseDecimal(s string) Decimal {
+	d, err := ParseDecimal(s)
+	if err != nil {
+		panic(err)
+	}
+
+	return d
+}
+
+// NewDecimal creates the decimal value * 10^-scale, e.g.: NewDecimal(1234, 2)
+// is 12.34, and NewDecimal(5, -1) is 50.
+func NewDecimal(value int64, scale int32) Decimal {
+	return normalize(big.NewInt(value), scale)
+}
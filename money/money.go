@@ -0,0 +1,414 @@
+// Package money provides Money, an exact decimal amount in an ISO 4217
+// currency, with arithmetic, rounding modes, and JSON, and SQL marshaling.
+package money
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+//////
+// Const, vars, and types.
+//////
+
+// ErrInvalidCurrency is returned for currency codes which aren't active ISO
+// 4217 codes, with a minor unit, e.g.: "usd", or "ABC".
+var ErrInvalidCurrency = errors.New("invalid currency")
+
+// ErrPrecision is returned for amounts with more decimal places than the
+// minor unit of their currency, e.g.: 1.005 USD.
+var ErrPrecision = errors.New("too many decimal places")
+
+// ErrCurrencyMismatch is returned when combining amounts in different
+// currencies.
+var ErrCurrencyMismatch = errors.New("currency mismatch")
+
+// currencies are the minor unit digits of the active ISO 4217 currencies.
+// Codes without a minor unit, e.g.: XAU for gold, aren't supported.
+var currencies = map[string]int32{
+	"AED": 2, "AFN": 2, "ALL": 2, "AMD": 2, "ANG": 2, "AOA": 2, "ARS": 2,
+	"AUD": 2, "AWG": 2, "AZN": 2, "BAM": 2, "BBD": 2, "BDT": 2, "BGN": 2,
+	"BHD": 3, "BIF": 0, "BMD": 2, "BND": 2, "BOB": 2, "BOV": 2, "BRL": 2,
+	"BSD": 2, "BTN": 2, "BWP": 2, "BYN": 2, "BZD": 2, "CAD": 2, "CDF": 2,
+	"CHE": 2, "CHF": 2, "CHW": 2, "CLF": 4, "CLP": 0, "CNY": 2, "COP": 2,
+	"COU": 2, "CRC": 2, "CUC": 2, "CUP": 2, "CVE": 2, "CZK": 2, "DJF": 0,
+	"DKK": 2, "DOP": 2, "DZD": 2, "EGP": 2, "ERN": 2, "ETB": 2, "EUR": 2,
+	"FJD": 2, "FKP": 2, "GBP": 2, "GEL": 2, "GHS": 2, "GIP": 2, "GMD": 2,
+	"GNF": 0, "GTQ": 2, "GYD": 2, "HKD": 2, "HNL": 2, "HTG": 2, "HUF": 2,
+	"IDR": 2, "ILS": 2, "INR": 2, "IQD": 3, "IRR": 2, "ISK": 0, "JMD": 2,
+	"JOD": 3, "JPY": 0, "KES": 2, "KGS": 2, "KHR": 2, "KMF": 0, "KPW": 2,
+	"KRW": 0, "KWD": 3, "KYD": 2, "KZT": 2, "LAK": 2, "LBP": 2, "LKR": 2,
+	"LRD": 2, "LSL": 2, "LYD": 3, "MAD": 2, "MDL": 2, "MGA": 2, "MKD": 2,
+	"MMK": 2, "MNT": 2, "MOP": 2, "MRU": 2, "MUR": 2, "MVR": 2, "MWK": 2,
+	"MXN": 2, "MXV": 2, "MYR": 2, "MZN": 2, "NAD": 2, "NGN": 2, "NIO": 2,
+	"NOK": 2, "NPR": 2, "NZD": 2, "OMR": 3, "PAB": 2, "PEN": 2, "PGK": 2,
+	"PHP": 2, "PKR": 2, "PLN": 2, "PYG": 0, "QAR": 2, "RON": 2, "RSD": 2,
+	"RUB": 2, "RWF": 0, "SAR": 2, "SBD": 2, "SCR": 2, "SDG": 2, "SEK": 2,
+	"SGD": 2, "SHP": 2, "SLE": 2, "SLL": 2, "SOS": 2, "SRD": 2, "SSP": 2,
+	"STN": 2, "SVC": 2, "SYP": 2, "SZL": 2, "THB": 2, "TJS": 2, "TMT": 2,
+	"TND": 3, "TOP": 2, "TRY": 2, "TTD": 2, "TWD": 2, "TZS": 2, "UAH": 2,
+	"UGX": 0, "USD": 2, "USN": 2, "UYI": 0, "UYU": 2, "UYW": 4, "UZS": 2,
+	"VED": 2, "VES": 2, "VND": 0, "VUV": 0, "WST": 2, "XAF": 0, "XCD": 2,
+	"XCG": 2, "XOF": 0, "XPF": 0, "YER": 2, "ZAR": 2, "ZMW": 2, "ZWG": 2,
+	"ZWL": 2,
+}
+
+// Money is an exact amount in a currency, e.g.: 12.34 USD. It's immutable,
+// operations return new values. Amounts are created with at most the minor
+// unit digits of the currency, see New, but keep their precision after a
+// multiplication, and are rounded to the minor unit only by Round, and Div.
+//
+// Like Decimal, amounts can't be compared with ==: use Equal, or Cmp, and,
+// e.g.: for sets, Equaler, instead of map keys.
+type Money struct {
+	amount   Decimal
+	currency string
+}
+
+// jsonMoney is the JSON representation of Money. The amount is a string, so
+// it isn't decoded as a float64 by other languages, and tools.
+type jsonMoney struct {
+	Amount   json.RawMessage `json:"amount"`
+	Currency string          `json:"currency"`
+}
+
+//////
+// Methods.
+//////
+
+// check returns ErrCurrencyMismatch if the currencies differ.
+func (m Money) check(other Money) error {
+	if m.currency != other.currency {
+		return fmt.Errorf("%w: %s, and %s", ErrCurrencyMismatch, m.currency, other.currency)
+	}
+
+	return nil
+}
+
+// String is the stringer implementation, e.g.: "12.30 USD". The amount has at
+// least the minor unit digits of the currency. Without a currency, e.g.: the
+// zero value, it's the amount alone, e.g.: "0".
+func (m Money) String() string {
+	if m.currency == "" {
+		return m.amount.String()
+	}
+
+	return m.amount.StringFixed(Digits(m.currency)) + " " + m.currency
+}
+
+// Amount returns the amount.
+func (m Money) Amount() Decimal {
+	return m.amount
+}
+
+// Currency returns the ISO 4217 code of the currency.
+func (m Money) Currency() string {
+	return m.currency
+}
+
+// MinorUnits returns the amount in minor units of the currency, e.g.: cents,
+// false if it has more decimal places, or doesn't fit in an int64.
+func (m Money) MinorUnits() (int64, bool) {
+	d := Digits(m.currency)
+
+	if m.amount.scale > d {
+		return 0, false
+	}
+
+	units := m.amount.rescale(d)
+
+	return units.Int64(), units.IsInt64()
+}
+
+// Sign returns -1, 0, or 1.
+func (m Money) Sign() int {
+	return m.amount.Sign()
+}
+
+// IsZero checks if the amount is 0.
+func (m Money) IsZero() bool {
+	return m.amount.IsZero()
+}
+
+// IsNegative checks if the amount is lower than 0.
+func (m Money) IsNegative() bool {
+	return m.amount.Sign() < 0
+}
+
+// Equal checks if both have the same currency, and amount, regardless of the
+// scale, e.g.: 1.0 USD equals 1 USD.
+func (m Money) Equal(other Money) bool {
+	return m.currency == other.currency && m.amount.Equal(other.amount)
+}
+
+// Cmp returns -1, 0, or 1, comparing the amounts. It fails if the currencies
+// differ.
+func (m Money) Cmp(other Money) (int, error) {
+	if err := m.check(other); err != nil {
+		return 0, err
+	}
+
+	return m.amount.Cmp(other.amount), nil
+}
+
+// Add returns m + other. It fails if the currencies differ.
+func (m Money) Add(other Money) (Money, error) {
+	if err := m.check(other); err != nil {
+		return Money{}, err
+	}
+
+	return Money{amount: m.amount.Add(other.amount), currency: m.currency}, nil
+}
+
+// Sub returns m - other. It fails if the currencies differ.
+func (m Money) Sub(other Money) (Money, error) {
+	if err := m.check(other); err != nil {
+		return Money{}, err
+	}
+
+	return Money{amount: m.amount.Sub(other.amount), currency: m.currency}, nil
+}
+
+// Mul returns m * factor, exactly, e.g.: a price by a quantity, or a rate.
+func (m Money) Mul(factor Decimal) Money {
+	return Money{amount: m.amount.Mul(factor), currency: m.currency}
+}
+
+// Div returns m / divisor, rounded to the minor unit of the currency. It fails
+// if divisor is 0.
+func (m Money) Div(divisor Decimal, mode RoundingMode) (Money, error) {
+	amount, err := m.amount.Div(divisor, Digits(m.currency), mode)
+	if err != nil {
+		return Money{}, err
+	}
+
+	return Money{amount: amount, currency: m.currency}, nil
+}
+
+// Round returns the amount rounded to the minor unit of the currency.
+func (m Money) Round(mode RoundingMode) Money {
+	return Money{amount: m.amount.Round(Digits(m.currency), mode), currency: m.currency}
+}
+
+// Neg returns -m.
+func (m Money) Neg() Money {
+	return Money{amount: m.amount.Neg(), currency: m.currency}
+}
+
+// Abs returns the absolute value.
+func (m Money) Abs() Money {
+	return Money{amount: m.amount.Abs(), currency: m.currency}
+}
+
+// MarshalJSON implements the json.Marshaler interface, e.g.:
+// {"amount":"12.34","currency":"USD"}.
+func (m Money) MarshalJSON() ([]byte, error) {
+	return json.Marshal(jsonMoney{
+		Amount:   json.RawMessage(strconv.Quote(m.amount.String())),
+		Currency: m.currency,
+	})
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface, the inverse of
+// MarshalJSON. The amount can also be a JSON number, decoded exactly. Unlike
+// New, amounts keep their precision, e.g.: as encoded after a Mul.
+func (m *Money) UnmarshalJSON(data []byte) error {
+	var raw jsonMoney
+
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	amount := strings.Trim(string(raw.Amount), `"`)
+
+	parsed, err := parse(amount, raw.Currency)
+	if err != nil {
+		return err
+	}
+
+	*m = parsed
+
+	return nil
+}
+
+// Value implements the driver.Valuer interface, storing the money as text,
+// e.g.: "12.34 USD".
+func (m Money) Value() (driver.Value, error) {
+	return m.amount.String() + " " + m.currency, nil
+}
+
+// Scan implements the sql.Scanner interface, the inverse of Value. It accepts
+// []byte, and string sources. Like UnmarshalJSON, amounts keep their
+// precision.
+func (m *Money) Scan(src any) error {
+	var s string
+
+	switch v := src.(type) {
+	case []byte:
+		s = string(v)
+	case string:
+		s = v
+	default:
+		return fmt.Errorf("cannot scan %T, expected []byte or string", src)
+	}
+
+	amount, currency, err := split(s)
+	if err != nil {
+		return err
+	}
+
+	parsed, err := parse(amount, currency)
+	if err != nil {
+		return err
+	}
+
+	*m = parsed
+
+	return nil
+}
+
+//////
+// Helpers.
+//////
+
+// digits returns the minor unit digits of the currency, or
+// ErrInvalidCurrency if it isn't supported.
+func digits(currency string) (int32, error) {
+	d, ok := currencies[currency]
+	if !ok {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidCurrency, currency)
+	}
+
+	return d, nil
+}
+
+// parse creates an amount from its decimal representation, and currency
+// code, keeping its precision.
+func parse(amount, currency string) (Money, error) {
+	if _, err := digits(currency); err != nil {
+		return Money{}, err
+	}
+
+	d, err := ParseDecimal(amount)
+	if err != nil {
+		return Money{}, err
+	}
+
+	return Money{amount: d, currency: currency}, nil
+}
+
+// split splits the text representation of an amount, e.g.: "12.34 USD", into
+// the amount, and the currency.
+func split(s string) (string, string, error) {
+	amount, currency, ok := strings.Cut(strings.TrimSpace(s), " ")
+	if !ok {
+		return "", "", fmt.Errorf("%w: %q, expected amount, and currency", ErrInvalidDecimal, s)
+	}
+
+	return amount, strings.TrimSpace(currency), nil
+}
+
+//////
+// Exported functionalities.
+//////
+
+// Digits returns the number of minor unit digits of the currency, e.g.: 2 for
+// USD, 0 for JPY. Unknown currencies have 2.
+func Digits(currency string) int32 {
+	if d, ok := currencies[currency]; ok {
+		return d
+	}
+
+	return 2
+}
+
+// Sum returns the sum of the amounts, in the given currency, e.g.: for
+// empty lists. It fails if the currency isn't valid, or any has a different
+// one.
+func Sum(currency string, amounts ...Money) (Money, error) {
+	if _, err := digits(currency); err != nil {
+		return Money{}, err
+	}
+
+	total := Money{currency: currency}
+
+	for _, amount := range amounts {
+		var err error
+
+		if total, err = total.Add(amount); err != nil {
+			return Money{}, err
+		}
+	}
+
+	return total, nil
+}
+
+//////
+// Factory.
+//////
+
+// New creates an amount from its decimal representation, and ISO 4217
+// currency code, e.g.: New("12.34", "USD"). It fails with ErrPrecision if the
+// amount has more decimal places than the minor unit of the currency, e.g.:
+// New("12.345", "USD"), unless they're zeros.
+func New(amount, currency string) (Money, error) {
+	d, err := ParseDecimal(amount)
+	if err != nil {
+		return Money{}, err
+	}
+
+	return FromDecimal(d, currency)
+}
+
+// Must is like New, panicking on error, e.g.: for constants.
+func Must(amount, currency string) Money {
+	m, err := New(amount, currency)
+	if err != nil {
+		panic(err)
+	}
+
+	return m
+}
+
+// FromDecimal creates an amount from a decimal, and currency code. Like New,
+// it fails with ErrPrecision if the amount has more decimal places than the
+// minor unit of the currency: Round it first, e.g.: with Decimal.Round.
+func FromDecimal(amount Decimal, currency string) (Money, error) {
+	d, err := digits(currency)
+	if err != nil {
+		return Money{}, err
+	}
+
+	if amount.scale > d {
+		rounded := amount.Round(d, RoundDown)
+
+		if !rounded.Equal(amount) {
+			return Money{}, fmt.Errorf("%w: %s %s, expected at most %d", ErrPrecision, amount, currency, d)
+		}
+
+		amount = rounded
+	}
+
+	return Money{amount: amount, currency: currency}, nil
+}
+
+// FromMinorUnits creates an amount from minor units of the currency, e.g.:
+// FromMinorUnits(1234, "USD") is 12.34 USD.
+func FromMinorUnits(units int64, currency string) (Money, error) {
+	return FromDecimal(NewDecimal(units, Digits(currency)), currency)
+}
+
+// Parse parses the text representation of an amount, e.g.: "12.34 USD", like
+// New.
+func Parse(s string) (Money, error) {
+	amount, currency, err := split(s)
+	if err != nil {
+		return Money{}, err
+	}
+
+	return New(amount, currency)
+}
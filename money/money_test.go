@@ -0,0 +1,308 @@
+package money
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/thalesfsp/go-common-types/safeorderedmap"
+)
+
+func TestDecimal(t *testing.T) {
+	a, b := MustParseDecimal("0.1"), MustParseDecimal("0.2")
+
+	// Unlike float64, 0.1 + 0.2 is 0.3.
+	assert.Equal(t, "0.3", a.Add(b).String())
+	assert.True(t, a.Add(b).Equal(MustParseDecimal("0.30")))
+	assert.Equal(t, "-0.1", a.Sub(b).String())
+	assert.Equal(t, "0.02", a.Mul(b).String())
+	assert.Equal(t, "12.30", MustParseDecimal("12.3").StringFixed(2))
+	assert.Equal(t, "0.05", NewDecimal(5, 2).String())
+	assert.Equal(t, "-1", MustParseDecimal("-1").String())
+	assert.Equal(t, -1, a.Cmp(b))
+	assert.InDelta(t, 0.1, a.Float64(), 1e-9)
+
+	var zero Decimal
+
+	assert.True(t, zero.IsZero())
+	assert.Equal(t, "0", zero.String())
+
+	q, err := MustParseDecimal("10").Div(MustParseDecimal("3"), 4, RoundHalfEven)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "3.3333", q.String())
+
+	_, err = a.Div(zero, 2, RoundHalfEven)
+
+	assert.ErrorIs(t, err, ErrDivisionByZero)
+
+	for _, s := range []string{"", "-", "1.2.3", "1e3", "abc", "+-1"} {
+		_, err := ParseDecimal(s)
+
+		assert.ErrorIs(t, err, ErrInvalidDecimal, s)
+	}
+}
+
+func TestDecimalRound(t *testing.T) {
+	tests := []struct {
+		mode  RoundingMode
+		value string
+		want  string
+	}{
+		{RoundHalfEven, "2.5", "2"},
+		{RoundHalfEven, "3.5", "4"},
+		{RoundHalfEven, "-2.5", "-2"},
+		{RoundHalfEven, "2.51", "3"},
+		{RoundHalfUp, "2.5", "3"},
+		{RoundHalfUp, "-2.5", "-3"},
+		{RoundHalfDown, "2.5", "2"},
+		{RoundHalfDown, "2.6", "3"},
+		{RoundUp, "2.1", "3"},
+		{RoundUp, "-2.1", "-3"},
+		{RoundDown, "2.9", "2"},
+		{RoundDown, "-2.9", "-2"},
+		{RoundCeiling, "-2.9", "-2"},
+		{RoundCeiling, "2.1", "3"},
+		{RoundFloor, "2.9", "2"},
+		{RoundFloor, "-2.1", "-3"},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, MustParseDecimal(tt.value).Round(0, tt.mode).String(), tt.value)
+	}
+
+	// Values with less decimal places are unchanged.
+	assert.Equal(t, "1.5", MustParseDecimal("1.5").Round(2, RoundHalfEven).String())
+}
+
+func TestDecimalNegativePlaces(t *testing.T) {
+	// Rounding to tens, and hundreds.
+	tens := NewDecimal(12345, 0).Round(-1, RoundHalfUp)
+
+	assert.Equal(t, "12350", tens.String())
+	assert.Equal(t, 12350.0, tens.Float64())
+
+	hundreds := NewDecimal(12345, 0).Round(-2, RoundHalfUp)
+
+	assert.Equal(t, "12300", hundreds.String())
+	assert.Equal(t, "12300.00", hundreds.StringFixed(2))
+	assert.Equal(t, 12300.0, hundreds.Float64())
+	assert.Equal(t, int32(0), hundreds.Scale())
+	assert.True(t, hundreds.Equal(NewDecimal(12300, 0)))
+
+	assert.Equal(t, "1200", MustParseDecimal("1249.99").Round(-2, RoundHalfEven).String())
+	assert.Equal(t, "-1300", MustParseDecimal("-1250.01").Round(-2, RoundHalfEven).String())
+	assert.Equal(t, "0", MustParseDecimal("12.5").Round(-2, RoundHalfEven).String())
+
+	// Negative scales are normalized.
+	assert.Equal(t, "50", NewDecimal(5, -1).String())
+	assert.Equal(t, 500.0, NewDecimal(5, -2).Float64())
+
+	quotient, err := NewDecimal(100000, 0).Div(NewDecimal(3, 0), -2, RoundHalfEven)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "33300", quotient.String())
+
+	quotient, err = MustParseDecimal("1.5").Div(MustParseDecimal("0.001"), -3, RoundHalfUp)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "2000", quotient.String())
+}
+
+func TestMoney(t *testing.T) {
+	price := Must("19.99", "USD")
+
+	total := price.Mul(NewDecimal(3, 0))
+
+	assert.Equal(t, "59.97 USD", total.String())
+
+	withTax := price.Mul(MustParseDecimal("1.0825"))
+
+	assert.Equal(t, "21.639175", withTax.Amount().String())
+	assert.Equal(t, "21.64 USD", withTax.Round(RoundHalfUp).String())
+	assert.Equal(t, "21.63 USD", withTax.Round(RoundDown).String())
+
+	share, err := Must("100", "USD").Div(NewDecimal(3, 0), RoundHalfEven)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "33.33 USD", share.String())
+
+	yen, err := Must("1000", "JPY").Div(NewDecimal(3, 0), RoundUp)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "334 JPY", yen.String())
+
+	units, ok := total.MinorUnits()
+
+	assert.True(t, ok)
+	assert.Equal(t, int64(5997), units)
+
+	_, ok = withTax.MinorUnits()
+
+	assert.False(t, ok)
+
+	fromUnits, err := FromMinorUnits(1234, "KWD")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "1.234 KWD", fromUnits.String())
+
+	assert.True(t, Must("1.0", "USD").Equal(Must("1", "USD")))
+	assert.False(t, Must("1", "USD").Equal(Must("1", "EUR")))
+	assert.True(t, Must("-1", "USD").IsNegative())
+	assert.Equal(t, "1.00 USD", Must("-1", "USD").Abs().String())
+	assert.Equal(t, "-1.00 USD", Must("1", "USD").Neg().String())
+	assert.Equal(t, "0", Money{}.String())
+}
+
+func TestMoneyCurrencies(t *testing.T) {
+	usd, eur := Must("1", "USD"), Must("1", "EUR")
+
+	_, err := usd.Add(eur)
+
+	assert.ErrorIs(t, err, ErrCurrencyMismatch)
+
+	_, err = usd.Sub(eur)
+
+	assert.ErrorIs(t, err, ErrCurrencyMismatch)
+
+	_, err = usd.Cmp(eur)
+
+	assert.ErrorIs(t, err, ErrCurrencyMismatch)
+
+	cmp, err := usd.Cmp(Must("2", "USD"))
+
+	assert.NoError(t, err)
+	assert.Equal(t, -1, cmp)
+
+	for _, currency := range []string{"", "usd", "US", "USDX", "ABC", "XAU"} {
+		_, err := New("1", currency)
+
+		assert.ErrorIs(t, err, ErrInvalidCurrency, currency)
+	}
+
+	sum, err := Sum("USD", usd, usd, Must("0.5", "USD"))
+
+	assert.NoError(t, err)
+	assert.Equal(t, "2.50 USD", sum.String())
+
+	_, err = Sum("USD", usd, eur)
+
+	assert.ErrorIs(t, err, ErrCurrencyMismatch)
+}
+
+func TestMoneyPrecision(t *testing.T) {
+	for amount, currency := range map[string]string{"12.345": "USD", "1.5": "JPY", "0.0001": "KWD"} {
+		_, err := New(amount, currency)
+
+		assert.ErrorIs(t, err, ErrPrecision, amount+" "+currency)
+
+		_, err = Parse(amount + " " + currency)
+
+		assert.ErrorIs(t, err, ErrPrecision, amount+" "+currency)
+	}
+
+	_, err := FromDecimal(MustParseDecimal("0.125"), "EUR")
+
+	assert.ErrorIs(t, err, ErrPrecision)
+
+	// Trailing zeros are dropped.
+	m := Must("12.3400", "USD")
+
+	assert.Equal(t, "12.34", m.Amount().String())
+
+	units, ok := m.MinorUnits()
+
+	assert.True(t, ok)
+	assert.Equal(t, int64(1234), units)
+
+	m = Must("1.234", "KWD")
+
+	assert.Equal(t, "1.234 KWD", m.String())
+
+	// Rounded first, it's accepted.
+	m, err = FromDecimal(MustParseDecimal("0.125").Round(Digits("EUR"), RoundHalfUp), "EUR")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "0.13 EUR", m.String())
+
+	// Decoding keeps the precision of encoded results, e.g.: of Mul.
+	product := Must("19.99", "USD").Mul(MustParseDecimal("1.0825"))
+
+	data, err := json.Marshal(product)
+	assert.NoError(t, err)
+
+	var decoded Money
+
+	assert.NoError(t, json.Unmarshal(data, &decoded))
+	assert.True(t, product.Equal(decoded))
+
+	value, err := product.Value()
+	assert.NoError(t, err)
+
+	var scanned Money
+
+	assert.NoError(t, scanned.Scan(value))
+	assert.True(t, product.Equal(scanned))
+
+	assert.ErrorIs(t, scanned.Scan("1 ABC"), ErrInvalidCurrency)
+}
+
+func TestMoneyJSON(t *testing.T) {
+	m := Must("12.34", "USD")
+
+	data, err := json.Marshal(m)
+
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"amount":"12.34","currency":"USD"}`, string(data))
+
+	var decoded Money
+
+	assert.NoError(t, json.Unmarshal(data, &decoded))
+	assert.True(t, m.Equal(decoded))
+
+	// Numbers are decoded exactly.
+	assert.NoError(t, json.Unmarshal([]byte(`{"amount":0.1,"currency":"EUR"}`), &decoded))
+	assert.Equal(t, "0.1", decoded.Amount().String())
+
+	assert.Error(t, json.Unmarshal([]byte(`{"amount":"x","currency":"EUR"}`), &decoded))
+
+	// Stored in a collection.
+	prices := safeorderedmap.New[Money]()
+	prices.Add("coffee", Must("3.50", "EUR"))
+
+	data, err = json.Marshal(prices)
+
+	assert.NoError(t, err)
+
+	restored := safeorderedmap.New[Money]()
+
+	assert.NoError(t, json.Unmarshal(data, restored))
+
+	coffee, _ := restored.Get("coffee")
+
+	assert.Equal(t, "3.50 EUR", coffee.String())
+}
+
+func TestMoneySQL(t *testing.T) {
+	var (
+		_ driver.Valuer = Money{}
+		_ sql.Scanner   = &Money{}
+	)
+
+	m := Must("-7.5", "GBP")
+
+	value, err := m.Value()
+
+	assert.NoError(t, err)
+	assert.Equal(t, "-7.5 GBP", value)
+
+	var scanned Money
+
+	assert.NoError(t, scanned.Scan([]byte("-7.5 GBP")))
+	assert.True(t, m.Equal(scanned))
+
+	assert.Error(t, scanned.Scan("7.5"))
+	assert.Error(t, scanned.Scan(1))
+}
@@ -0,0 +1,315 @@
+// Package null provides a generic Null type, a value which may be absent,
+// explicitly null, or present, e.g.: for PATCH-style APIs.
+package null
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	"github.com/thalesfsp/go-common-types/shared"
+)
+
+//////
+// Const, vars, and types.
+//////
+
+// state is the state of a Null.
+type state uint8
+
+// States of a Null. The zero value is absent.
+const (
+	absent state = iota
+	null
+	present
+)
+
+// nullJSON is the JSON representation of null.
+var nullJSON = []byte("null")
+
+// Null is a value with three states: absent, e.g.: a missing JSON key, null,
+// e.g.: an explicit JSON null, or a SQL NULL, and present. The zero value is
+// absent, so fields not in a JSON document stay absent.
+type Null[T any] struct {
+	value T
+	state state
+}
+
+//////
+// Methods.
+//////
+
+// String is the stringer implementation.
+func (n Null[T]) String() string {
+	switch n.state {
+	case null:
+		return "Null"
+	case present:
+		return fmt.Sprintf("%v", n.value)
+	default:
+		return "Absent"
+	}
+}
+
+// IsAbsent checks if the value is absent, i.e.: neither null, nor present.
+func (n Null[T]) IsAbsent() bool {
+	return n.state == absent
+}
+
+// IsNull checks if the value is explicitly null.
+func (n Null[T]) IsNull() bool {
+	return n.state == null
+}
+
+// IsPresent checks if there's a value.
+func (n Null[T]) IsPresent() bool {
+	return n.state == present
+}
+
+// IsSet checks if the value is null, or present, i.e.: not absent, e.g.: a
+// field to update in a PATCH request.
+func (n Null[T]) IsSet() bool {
+	return n.state != absent
+}
+
+// IsZero checks if the value is absent, e.g.: for encoders honoring it to
+// omit fields.
+func (n Null[T]) IsZero() bool {
+	return n.IsAbsent()
+}
+
+// Get returns the value, and whether it's present, like a two-value lookup.
+func (n Null[T]) Get() (T, bool) {
+	return n.value, n.state == present
+}
+
+// OrElse returns the value if present, otherwise the given fallback.
+func (n Null[T]) OrElse(fallback T) T {
+	if n.state != present {
+		return fallback
+	}
+
+	return n.value
+}
+
+// Ptr returns a pointer to a copy of the value, nil if it's not present.
+func (n Null[T]) Ptr() *T {
+	if n.state != present {
+		return nil
+	}
+
+	value := n.value
+
+	return &value
+}
+
+// MarshalJSON implements the json.Marshaler interface. Absent, and null are
+// encoded as null: encoding/json can't omit a struct field, use pointers, or
+// IsSet, to skip absent ones.
+func (n Null[T]) MarshalJSON() ([]byte, error) {
+	if n.state != present {
+		return nullJSON, nil
+	}
+
+	return json.Marshal(n.value)
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface. It's only called
+// for keys in the document, so missing keys stay absent, and null is decoded
+// as null.
+func (n *Null[T]) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(bytes.TrimSpace(data), nullJSON) {
+		*n = Nil[T]()
+
+		return nil
+	}
+
+	var value T
+
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+
+	*n = Of(value)
+
+	return nil
+}
+
+// MarshalText implements the encoding.TextMarshaler interface, see
+// shared.FormatText. Absent, and null are encoded as an empty text.
+func (n Null[T]) MarshalText() ([]byte, error) {
+	if n.state != present {
+		return []byte{}, nil
+	}
+
+	s, err := shared.FormatText(n.value)
+	if err != nil {
+		return nil, err
+	}
+
+	return []byte(s), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface, the
+// inverse of MarshalText: an empty text is decoded as null.
+func (n *Null[T]) UnmarshalText(text []byte) error {
+	if len(text) == 0 {
+		*n = Nil[T]()
+
+		return nil
+	}
+
+	value, err := shared.ParseText[T](string(text))
+	if err != nil {
+		return err
+	}
+
+	*n = Of(value)
+
+	return nil
+}
+
+// Value implements the driver.Valuer interface. Absent, and null are stored
+// as NULL.
+func (n Null[T]) Value() (driver.Value, error) {
+	if n.state != present {
+		return nil, nil
+	}
+
+	return driver.DefaultParameterConverter.ConvertValue(n.value)
+}
+
+// Scan implements the sql.Scanner interface. NULL is scanned as null, other
+// values are converted to T, e.g.: int64 to int, or []byte to string.
+func (n *Null[T]) Scan(src any) error {
+	if src == nil {
+		*n = Nil[T]()
+
+		return nil
+	}
+
+	var value T
+
+	if scanner, ok := any(&value).(interface{ Scan(src any) error }); ok {
+		if err := scanner.Scan(src); err != nil {
+			return err
+		}
+
+		*n = Of(value)
+
+		return nil
+	}
+
+	value, err := convert[T](src)
+	if err != nil {
+		return err
+	}
+
+	*n = Of(value)
+
+	return nil
+}
+
+//////
+// Helpers.
+//////
+
+// convert converts the value to T: directly, with a Go conversion between
+// kinds of the same class, e.g.: int64 to int, or []byte to string, or
+// through JSON, e.g.: float64 to int, as decoded from JSON into an any.
+func convert[T any](src any) (T, error) {
+	if value, ok := src.(T); ok {
+		return value, nil
+	}
+
+	var value T
+
+	from, to := reflect.ValueOf(src), reflect.TypeOf(&value).Elem()
+
+	if from.Type().ConvertibleTo(to) && class(from.Type()) == class(to) {
+		//nolint:forcetypeassert
+		return from.Convert(to).Interface().(T), nil
+	}
+
+	data, err := json.Marshal(src)
+	if err == nil {
+		err = json.Unmarshal(data, &value)
+	}
+
+	if err != nil {
+		return value, fmt.Errorf("cannot convert %T to %T: %w", src, value, err)
+	}
+
+	return value, nil
+}
+
+// class returns the class of the kind of the type, types of the same class
+// convert without changing the meaning of the value, e.g.: not an int to a
+// string, as a rune, nor a float to an int, truncating it.
+func class(t reflect.Type) reflect.Kind {
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return reflect.Int
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return reflect.Uint
+	case reflect.Float32, reflect.Float64:
+		return reflect.Float64
+	case reflect.Slice:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return reflect.String
+		}
+	}
+
+	return t.Kind()
+}
+
+//////
+// Factory.
+//////
+
+// Of creates a Null holding the value.
+func Of[T any](value T) Null[T] {
+	return Null[T]{value: value, state: present}
+}
+
+// Nil creates an explicitly null Null.
+func Nil[T any]() Null[T] {
+	return Null[T]{state: null}
+}
+
+// Absent creates an absent Null, the zero value.
+func Absent[T any]() Null[T] {
+	return Null[T]{}
+}
+
+// FromPtr creates a Null from a pointer: null if nil, otherwise holding the
+// pointed value.
+func FromPtr[T any](p *T) Null[T] {
+	if p == nil {
+		return Nil[T]()
+	}
+
+	return Of(*p)
+}
+
+// FromLookup creates a Null from a two-value lookup of an untyped value, e.g.:
+// null.FromLookup[int](m.Get("age")) for a SafeOrderedMap[any] decoded from a
+// PATCH request: absent if the key is missing, null if nil, otherwise the
+// value converted to T, as Scan does.
+func FromLookup[T any](value any, ok bool) (Null[T], error) {
+	switch {
+	case !ok:
+		return Absent[T](), nil
+	case value == nil:
+		return Nil[T](), nil
+	}
+
+	converted, err := convert[T](value)
+	if err != nil {
+		return Absent[T](), err
+	}
+
+	return Of(converted), nil
+}
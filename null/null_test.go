@@ -0,0 +1,155 @@
+package null
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/thalesfsp/go-common-types/safeorderedmap"
+)
+
+func TestNull(t *testing.T) {
+	var n Null[int]
+
+	assert.True(t, n.IsAbsent())
+	assert.True(t, n.IsZero())
+	assert.False(t, n.IsSet())
+	assert.Equal(t, "Absent", n.String())
+
+	n = Nil[int]()
+
+	assert.True(t, n.IsNull())
+	assert.True(t, n.IsSet())
+	assert.False(t, n.IsPresent())
+	assert.Nil(t, n.Ptr())
+	assert.Equal(t, 7, n.OrElse(7))
+	assert.Equal(t, "Null", n.String())
+
+	n = Of(1)
+
+	value, ok := n.Get()
+
+	assert.True(t, ok)
+	assert.Equal(t, 1, value)
+	assert.Equal(t, 1, *n.Ptr())
+	assert.Equal(t, "1", n.String())
+
+	assert.True(t, FromPtr[int](nil).IsNull())
+	assert.Equal(t, Of(2), FromPtr(&[]int{2}[0]))
+	assert.Equal(t, Absent[int](), Null[int]{})
+}
+
+func TestNullJSON(t *testing.T) {
+	type patch struct {
+		Name Null[string] `json:"name"`
+		Age  Null[int]    `json:"age"`
+		Tags Null[[]string]
+	}
+
+	var p patch
+
+	assert.NoError(t, json.Unmarshal([]byte(`{"name":null,"age":30}`), &p))
+
+	assert.True(t, p.Name.IsNull())
+	assert.Equal(t, Of(30), p.Age)
+	assert.True(t, p.Tags.IsAbsent())
+
+	data, err := json.Marshal(p)
+
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"name":null,"age":30,"Tags":null}`, string(data))
+
+	assert.Error(t, json.Unmarshal([]byte(`{"age":"x"}`), &p))
+}
+
+func TestNullText(t *testing.T) {
+	text, err := Of(time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)).MarshalText()
+
+	assert.NoError(t, err)
+	assert.Equal(t, "2024-01-02T00:00:00Z", string(text))
+
+	text, err = Nil[int]().MarshalText()
+
+	assert.NoError(t, err)
+	assert.Empty(t, text)
+
+	var n Null[int]
+
+	assert.NoError(t, n.UnmarshalText([]byte("42")))
+	assert.Equal(t, Of(42), n)
+
+	assert.NoError(t, n.UnmarshalText(nil))
+	assert.True(t, n.IsNull())
+
+	assert.Error(t, n.UnmarshalText([]byte("x")))
+}
+
+func TestNullSQL(t *testing.T) {
+	var (
+		_ driver.Valuer = Null[int]{}
+		_ sql.Scanner   = &Null[int]{}
+	)
+
+	value, err := Of(1).Value()
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), value)
+
+	value, err = Absent[int]().Value()
+
+	assert.NoError(t, err)
+	assert.Nil(t, value)
+
+	var n Null[int]
+
+	assert.NoError(t, n.Scan(int64(5)))
+	assert.Equal(t, Of(5), n)
+
+	assert.NoError(t, n.Scan(nil))
+	assert.True(t, n.IsNull())
+
+	var s Null[string]
+
+	assert.NoError(t, s.Scan([]byte("a")))
+	assert.Equal(t, Of("a"), s)
+
+	// Numbers aren't converted to strings, as runes.
+	assert.Error(t, s.Scan(int64(65)))
+
+	var ts Null[sql.NullString]
+
+	assert.NoError(t, ts.Scan("b"))
+	assert.Equal(t, "b", ts.OrElse(sql.NullString{}).String)
+
+	assert.Error(t, n.Scan("x"))
+}
+
+func TestFromLookup(t *testing.T) {
+	m := safeorderedmap.New[any]()
+
+	assert.NoError(t, json.Unmarshal([]byte(`{"name":null,"age":30,"ratio":1.5}`), m))
+
+	name, err := FromLookup[string](m.Get("name"))
+
+	assert.NoError(t, err)
+	assert.True(t, name.IsNull())
+
+	// JSON numbers are float64.
+	age, err := FromLookup[int](m.Get("age"))
+
+	assert.NoError(t, err)
+	assert.Equal(t, Of(30), age)
+
+	email, err := FromLookup[string](m.Get("email"))
+
+	assert.NoError(t, err)
+	assert.True(t, email.IsAbsent())
+
+	// Floats aren't truncated.
+	_, err = FromLookup[int](m.Get("ratio"))
+
+	assert.Error(t, err)
+}
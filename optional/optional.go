@@ -0,0 +1,169 @@
+// Package optional provides a generic Option type, a value which may, or may
+// not be present.
+package optional
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+//////
+// Const, vars, and types.
+//////
+
+// ErrNone is the panic value of MustGet when the option is None.
+var ErrNone = errors.New("optional: value not present")
+
+// null is the JSON representation of None.
+var null = []byte("null")
+
+// Option is a value which may, or may not be present. The zero value is None.
+type Option[T any] struct {
+	value T
+	ok    bool
+}
+
+//////
+// Methods.
+//////
+
+// String is the stringer implementation.
+func (o Option[T]) String() string {
+	if !o.ok {
+		return "None"
+	}
+
+	return fmt.Sprintf("Some(%v)", o.value)
+}
+
+// IsSome checks if the value is present.
+func (o Option[T]) IsSome() bool {
+	return o.ok
+}
+
+// IsNone checks if the value is absent.
+func (o Option[T]) IsNone() bool {
+	return !o.ok
+}
+
+// Get returns the value, and whether it's present, like a two-value lookup.
+func (o Option[T]) Get() (T, bool) {
+	return o.value, o.ok
+}
+
+// MustGet returns the value, panicking with ErrNone if it's absent.
+func (o Option[T]) MustGet() T {
+	if !o.ok {
+		panic(ErrNone)
+	}
+
+	return o.value
+}
+
+// OrElse returns the value if present, otherwise the given fallback.
+func (o Option[T]) OrElse(fallback T) T {
+	if !o.ok {
+		return fallback
+	}
+
+	return o.value
+}
+
+// OrElseGet returns the value if present, otherwise the result of f.
+func (o Option[T]) OrElseGet(f func() T) T {
+	if !o.ok {
+		return f()
+	}
+
+	return o.value
+}
+
+// Filter returns the option if the value is present, and satisfies the
+// predicate, otherwise None.
+func (o Option[T]) Filter(predicate func(T) bool) Option[T] {
+	if !o.ok || !predicate(o.value) {
+		return None[T]()
+	}
+
+	return o
+}
+
+// MarshalJSON implements the json.Marshaler interface. None is encoded as
+// null.
+func (o Option[T]) MarshalJSON() ([]byte, error) {
+	if !o.ok {
+		return null, nil
+	}
+
+	return json.Marshal(o.value)
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface. null is decoded as
+// None.
+func (o *Option[T]) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(bytes.TrimSpace(data), null) {
+		*o = None[T]()
+
+		return nil
+	}
+
+	var value T
+
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+
+	*o = Some(value)
+
+	return nil
+}
+
+//////
+// Factory.
+//////
+
+// Some creates an Option holding the value.
+func Some[T any](value T) Option[T] {
+	return Option[T]{value: value, ok: true}
+}
+
+// None creates an empty Option.
+func None[T any]() Option[T] {
+	return Option[T]{}
+}
+
+// From creates an Option from a two-value lookup, e.g.:
+// optional.From(m.Get("key")).
+func From[T any](value T, ok bool) Option[T] {
+	if !ok {
+		return None[T]()
+	}
+
+	return Some(value)
+}
+
+//////
+// Exported Functionalities.
+//////
+
+// Map returns an Option holding the result of f applied to the value, if
+// present, otherwise None.
+func Map[T, U any](o Option[T], f func(T) U) Option[U] {
+	if !o.ok {
+		return None[U]()
+	}
+
+	return Some(f(o.value))
+}
+
+// FlatMap returns the result of f applied to the value, if present, otherwise
+// None.
+func FlatMap[T, U any](o Option[T], f func(T) Option[U]) Option[U] {
+	if !o.ok {
+		return None[U]()
+	}
+
+	return f(o.value)
+}
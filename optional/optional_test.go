@@ -0,0 +1,84 @@
+package optional
+
+import (
+	"encoding/json"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/thalesfsp/go-common-types/safeorderedmap"
+)
+
+func TestOption(t *testing.T) {
+	some := Some(1)
+	none := None[int]()
+
+	assert.True(t, some.IsSome())
+	assert.False(t, some.IsNone())
+	assert.True(t, none.IsNone())
+	assert.Equal(t, none, Option[int]{})
+
+	v, ok := some.Get()
+	assert.True(t, ok)
+	assert.Equal(t, 1, v)
+
+	assert.Equal(t, 1, some.MustGet())
+	assert.PanicsWithValue(t, ErrNone, func() { none.MustGet() })
+
+	assert.Equal(t, 1, some.OrElse(2))
+	assert.Equal(t, 2, none.OrElse(2))
+	assert.Equal(t, 3, none.OrElseGet(func() int { return 3 }))
+
+	assert.Equal(t, "Some(1)", some.String())
+	assert.Equal(t, "None", none.String())
+
+	assert.True(t, some.Filter(func(v int) bool { return v > 0 }).IsSome())
+	assert.True(t, some.Filter(func(v int) bool { return v > 1 }).IsNone())
+}
+
+func TestOptionMap(t *testing.T) {
+	assert.Equal(t, Some("1"), Map(Some(1), strconv.Itoa))
+	assert.Equal(t, None[string](), Map(None[int](), strconv.Itoa))
+
+	parse := func(s string) Option[int] {
+		v, err := strconv.Atoi(s)
+
+		return From(v, err == nil)
+	}
+
+	assert.Equal(t, Some(42), FlatMap(Some("42"), parse))
+	assert.Equal(t, None[int](), FlatMap(Some("x"), parse))
+}
+
+func TestOptionFrom(t *testing.T) {
+	m := safeorderedmap.New[int]()
+	m.Add("a", 1)
+
+	assert.Equal(t, Some(1), From(m.Get("a")))
+	assert.Equal(t, None[int](), From(m.Get("b")))
+}
+
+func TestOptionJSON(t *testing.T) {
+	type user struct {
+		Name     string         `json:"name"`
+		Nickname Option[string] `json:"nickname"`
+	}
+
+	b, err := json.Marshal(user{Name: "a"})
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"name":"a","nickname":null}`, string(b))
+
+	b, err = json.Marshal(user{Name: "a", Nickname: Some("b")})
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"name":"a","nickname":"b"}`, string(b))
+
+	var u user
+
+	assert.NoError(t, json.Unmarshal([]byte(`{"nickname":"c"}`), &u))
+	assert.Equal(t, Some("c"), u.Nickname)
+
+	assert.NoError(t, json.Unmarshal([]byte(`{"nickname":null}`), &u))
+	assert.True(t, u.Nickname.IsNone())
+
+	assert.Error(t, json.Unmarshal([]byte(`{"nickname":1}`), &u))
+}
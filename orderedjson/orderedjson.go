@@ -0,0 +1,276 @@
+package orderedjson
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/thalesfsp/go-common-types/safeorderedmap"
+	"github.com/thalesfsp/go-common-types/safeslice"
+)
+
+//////
+// Const, vars, and types.
+//////
+
+// ErrTrailingData is returned when a document is followed by more data.
+var ErrTrailingData = errors.New("unexpected data after the JSON document")
+
+// Object is a decoded JSON object, keeping the order of its keys.
+type Object = safeorderedmap.SafeOrderedMap[any]
+
+// Array is a decoded JSON array.
+type Array = safeslice.SafeSlice[any]
+
+//////
+// Helpers.
+//////
+
+// decodeValue decodes the value starting with tok.
+func decodeValue(dec *json.Decoder, tok json.Token) (any, error) {
+	switch tok {
+	case json.Delim('{'):
+		return decodeObject(dec)
+	case json.Delim('['):
+		return decodeArray(dec)
+	default:
+		// Strings, json.Number, booleans, and null.
+		return tok, nil
+	}
+}
+
+// decodeObject decodes the members of an object, after its opening brace.
+func decodeObject(dec *json.Decoder) (*Object, error) {
+	keys := []string{}
+	values := []any{}
+
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+
+		//nolint:forcetypeassert
+		key := tok.(string)
+
+		if tok, err = dec.Token(); err != nil {
+			return nil, err
+		}
+
+		value, err := decodeValue(dec, tok)
+		if err != nil {
+			return nil, err
+		}
+
+		keys = append(keys, key)
+		values = append(values, value)
+	}
+
+	// Closing brace.
+	if _, err := dec.Token(); err != nil {
+		return nil, err
+	}
+
+	object := safeorderedmap.New[any]()
+
+	// Duplicate keys keep the position of the first, and the last value.
+	for i, key := range keys {
+		object.Add(key, values[i])
+	}
+
+	return object, nil
+}
+
+// decodeArray decodes the elements of an array, after its opening bracket.
+func decodeArray(dec *json.Decoder) (*Array, error) {
+	items := []any{}
+
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+
+		item, err := decodeValue(dec, tok)
+		if err != nil {
+			return nil, err
+		}
+
+		items = append(items, item)
+	}
+
+	// Closing bracket.
+	if _, err := dec.Token(); err != nil {
+		return nil, err
+	}
+
+	return safeslice.New(items...), nil
+}
+
+// encodeValue writes the value as compact JSON.
+func encodeValue(w *bufio.Writer, value any) error {
+	switch v := value.(type) {
+	case *Object:
+		if err := w.WriteByte('{'); err != nil {
+			return err
+		}
+
+		for i, entry := range v.Entries() {
+			if i > 0 {
+				if err := w.WriteByte(','); err != nil {
+					return err
+				}
+			}
+
+			if err := encodeScalar(w, entry.Key); err != nil {
+				return err
+			}
+
+			if err := w.WriteByte(':'); err != nil {
+				return err
+			}
+
+			if err := encodeValue(w, entry.Value); err != nil {
+				return err
+			}
+		}
+
+		return w.WriteByte('}')
+	case *Array:
+		if err := w.WriteByte('['); err != nil {
+			return err
+		}
+
+		for i, item := range v.Values() {
+			if i > 0 {
+				if err := w.WriteByte(','); err != nil {
+					return err
+				}
+			}
+
+			if err := encodeValue(w, item); err != nil {
+				return err
+			}
+		}
+
+		return w.WriteByte(']')
+	default:
+		return encodeScalar(w, value)
+	}
+}
+
+// encodeScalar writes any other value with encoding/json, without escaping
+// HTML characters, so strings are written as they were read.
+func encodeScalar(w *bufio.Writer, value any) error {
+	var buf bytes.Buffer
+
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+
+	if err := enc.Encode(value); err != nil {
+		return err
+	}
+
+	// Encode appends a newline.
+	_, err := w.Write(bytes.TrimSuffix(buf.Bytes(), []byte{'\n'}))
+
+	return err
+}
+
+//////
+// Decoding.
+//////
+
+// Decode decodes a JSON document from r. Objects are decoded as *Object, which
+// keeps the order of the keys, arrays as *Array, numbers as json.Number, which
+// keeps their exact text, and strings, booleans, and null as with
+// encoding/json. Trailing data, other than whitespace, is an error.
+func Decode(r io.Reader) (any, error) {
+	dec := json.NewDecoder(r)
+	dec.UseNumber()
+
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	value, err := decodeValue(dec, tok)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := dec.Token(); !errors.Is(err, io.EOF) {
+		return nil, ErrTrailingData
+	}
+
+	return value, nil
+}
+
+// Unmarshal is like Decode, from a byte slice.
+func Unmarshal(data []byte) (any, error) {
+	return Decode(bytes.NewReader(data))
+}
+
+// UnmarshalObject is like Unmarshal, for documents which must be objects,
+// e.g.: configuration files.
+func UnmarshalObject(data []byte) (*Object, error) {
+	value, err := Unmarshal(data)
+	if err != nil {
+		return nil, err
+	}
+
+	object, ok := value.(*Object)
+	if !ok {
+		return nil, fmt.Errorf("expected a JSON object, got %T", value)
+	}
+
+	return object, nil
+}
+
+//////
+// Encoding.
+//////
+
+// Encode writes the value to w as compact JSON. *Object values are written
+// in the order of their keys, so a decoded compact document is written back
+// byte-for-byte, as long as its strings don't use unnecessary escapes. Other
+// values, e.g.: nested built-in maps, are written with encoding/json.
+func Encode(w io.Writer, value any) error {
+	bw := bufio.NewWriter(w)
+
+	if err := encodeValue(bw, value); err != nil {
+		return err
+	}
+
+	return bw.Flush()
+}
+
+// Marshal is like Encode, returning a byte slice.
+func Marshal(value any) ([]byte, error) {
+	var buf bytes.Buffer
+
+	if err := Encode(&buf, value); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// MarshalIndent is like Marshal, indenting the output like json.MarshalIndent.
+func MarshalIndent(value any, prefix, indent string) ([]byte, error) {
+	data, err := Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+
+	if err := json.Indent(&buf, data, prefix, indent); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
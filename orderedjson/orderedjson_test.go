@@ -0,0 +1,68 @@
+package orderedjson
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/thalesfsp/go-common-types/safeorderedmap"
+)
+
+func TestRoundTrip(t *testing.T) {
+	for _, doc := range []string{
+		`{"z":1,"a":{"y":[1,2.50,{"c":null,"b":true}],"x":"<b>&é"},"m":[],"n":{}}`,
+		`[3,"a",{"b":1,"a":2}]`,
+		`"text"`,
+		`12345678901234567890`,
+		`null`,
+	} {
+		value, err := Unmarshal([]byte(doc))
+		assert.NoError(t, err)
+
+		data, err := Marshal(value)
+		assert.NoError(t, err)
+		assert.Equal(t, doc, string(data))
+	}
+}
+
+func TestDecode(t *testing.T) {
+	object, err := UnmarshalObject([]byte(`{"b": {"d": 1, "c": [true]}, "a": "x", "b2": 2, "a": "y"}`))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"b", "a", "b2"}, object.Keys())
+
+	a, _ := object.Get("a")
+	assert.Equal(t, "y", a)
+
+	b, _ := object.Get("b")
+	assert.Equal(t, []string{"d", "c"}, b.(*Object).Keys())
+
+	c, err := safeorderedmap.GetPath(b.(*Object), "d")
+	assert.NoError(t, err)
+	assert.Equal(t, json.Number("1"), c)
+
+	_, err = UnmarshalObject([]byte(`[1]`))
+	assert.Error(t, err)
+
+	_, err = Unmarshal([]byte(`{"a":1} {}`))
+	assert.ErrorIs(t, err, ErrTrailingData)
+
+	_, err = Unmarshal([]byte(`{"a":`))
+	assert.Error(t, err)
+}
+
+func TestEditAndIndent(t *testing.T) {
+	object, err := UnmarshalObject([]byte(`{"name":"api","port":80}`))
+	assert.NoError(t, err)
+
+	object.Add("port", 8080).Add("debug", map[string]any{"level": 1})
+
+	data, err := MarshalIndent(object, "", "  ")
+	assert.NoError(t, err)
+	assert.Equal(t, "{\n  \"name\": \"api\",\n  \"port\": 8080,\n  \"debug\": {\n    \"level\": 1\n  }\n}", string(data))
+
+	var sb strings.Builder
+
+	assert.NoError(t, Encode(&sb, object))
+	assert.Equal(t, `{"name":"api","port":8080,"debug":{"level":1}}`, sb.String())
+}
@@ -0,0 +1,403 @@
+// Package redisstore provides Redis-backed implementations of the collection
+// interfaces, so code can swap local for distributed storage without
+// call-site changes.
+//
+// Elements are stored as JSON. Collection interfaces don't return errors, so
+// the first error which happened talking to Redis is kept, and returned by
+// Err, like bufio.Scanner. Operations which fail return the zero value.
+package redisstore
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/thalesfsp/go-common-types/collection"
+)
+
+//////
+// Const, vars, and types.
+//////
+
+var (
+	_ collection.OrderedMap[any] = (*OrderedMap[any])(nil)
+	_ collection.Set[any]        = (*Set[any])(nil)
+	_ collection.List[string]    = (*List[string])(nil)
+)
+
+// Scripts keeping the hash, and the list of keys of an OrderedMap in sync.
+var (
+	setScript = redis.NewScript(`
+if redis.call('HSET', KEYS[1], ARGV[1], ARGV[2]) == 1 then
+	redis.call('RPUSH', KEYS[2], ARGV[1])
+end
+return 0
+`)
+
+	removeScript = redis.NewScript(`
+if redis.call('HDEL', KEYS[1], ARGV[1]) == 1 then
+	redis.call('LREM', KEYS[2], 1, ARGV[1])
+	return 1
+end
+return 0
+`)
+
+	// valuesScript skips keys missing from the hash, e.g.: removed by a client
+	// not using the scripts, as HGET returns false for them.
+	valuesScript = redis.NewScript(`
+local keys = redis.call('LRANGE', KEYS[2], 0, -1)
+local values = {}
+for _, key in ipairs(keys) do
+	local value = redis.call('HGET', KEYS[1], key)
+	if value then
+		values[#values + 1] = value
+	end
+end
+return values
+`)
+)
+
+// store is the common part of all Redis-backed collections.
+type store struct {
+	client redis.UniversalClient
+	key    string
+
+	errMu sync.Mutex
+	err   error
+}
+
+// OrderedMap is an ordered map backed by a Redis hash, holding the values, and
+// a Redis list, holding the insertion order of the keys. Mutations are atomic,
+// they run as Lua scripts. Both keys share the same hash tag, so it works with
+// Redis Cluster.
+type OrderedMap[T any] struct {
+	store
+}
+
+// Set is a set backed by a Redis set. Elements are compared by their JSON
+// representation.
+//
+// NOTE: Redis sets are unordered, so, unlike SafeSet, Values doesn't preserve
+// the insertion order.
+type Set[T any] struct {
+	store
+}
+
+// List is a list backed by a Redis list.
+type List[T comparable] struct {
+	store
+}
+
+//////
+// Methods.
+//////
+
+// Err returns the first error which happened talking to Redis, if any.
+func (s *store) Err() error {
+	s.errMu.Lock()
+	defer s.errMu.Unlock()
+
+	return s.err
+}
+
+// Key returns the Redis key of the collection.
+func (s *store) Key() string {
+	return s.key
+}
+
+// check keeps the first error, ignoring redis.Nil. It returns whether err is
+// nil.
+func (s *store) check(err error) bool {
+	if err == nil || errors.Is(err, redis.Nil) {
+		return err == nil
+	}
+
+	s.errMu.Lock()
+	defer s.errMu.Unlock()
+
+	if s.err == nil {
+		s.err = fmt.Errorf("redis %q: %w", s.key, err)
+	}
+
+	return false
+}
+
+// count returns the result of a command returning the number of elements.
+func (s *store) count(n int64, err error) int {
+	if !s.check(err) {
+		return 0
+	}
+
+	return int(n)
+}
+
+// ctx returns the context of the Redis commands.
+func (s *store) ctx() context.Context {
+	return context.Background()
+}
+
+// encode encodes the value as JSON.
+func encode[T any](s *store, value T) (string, bool) {
+	b, err := json.Marshal(value)
+	if !s.check(err) {
+		return "", false
+	}
+
+	return string(b), true
+}
+
+// decode decodes the JSON value.
+func decode[T any](s *store, data string) (T, bool) {
+	var value T
+
+	if !s.check(json.Unmarshal([]byte(data), &value)) {
+		return *new(T), false
+	}
+
+	return value, true
+}
+
+// decodeAll decodes the JSON values.
+func decodeAll[T any](s *store, data []string, err error) []T {
+	values := make([]T, 0, len(data))
+
+	if !s.check(err) {
+		return values
+	}
+
+	for _, d := range data {
+		value, ok := decode[T](s, d)
+		if !ok {
+			return values
+		}
+
+		values = append(values, value)
+	}
+
+	return values
+}
+
+//////
+// OrderedMap.
+
+// keys returns the Redis keys of the values, and of the order.
+func (m *OrderedMap[T]) keys() []string {
+	return []string{
+		"{" + m.key + "}:values",
+		"{" + m.key + "}:keys",
+	}
+}
+
+// Set a value, keeping the position of existing keys.
+func (m *OrderedMap[T]) Set(key string, value T) {
+	data, ok := encode(&m.store, value)
+	if !ok {
+		return
+	}
+
+	m.check(setScript.Run(m.ctx(), m.client, m.keys(), key, data).Err())
+}
+
+// Get a value.
+func (m *OrderedMap[T]) Get(key string) (T, bool) {
+	data, err := m.client.HGet(m.ctx(), m.keys()[0], key).Result()
+	if !m.check(err) {
+		return *new(T), false
+	}
+
+	return decode[T](&m.store, data)
+}
+
+// Remove a value, returning whether it was present.
+func (m *OrderedMap[T]) Remove(key string) bool {
+	n, err := removeScript.Run(m.ctx(), m.client, m.keys(), key).Int64()
+
+	return m.check(err) && n == 1
+}
+
+// Contains checks if the key is present.
+func (m *OrderedMap[T]) Contains(key string) bool {
+	ok, err := m.client.HExists(m.ctx(), m.keys()[0], key).Result()
+
+	return m.check(err) && ok
+}
+
+// Keys returns all keys, in insertion order.
+func (m *OrderedMap[T]) Keys() []string {
+	keys, err := m.client.LRange(m.ctx(), m.keys()[1], 0, -1).Result()
+	if !m.check(err) {
+		return []string{}
+	}
+
+	return keys
+}
+
+// Values returns all values, in insertion order.
+func (m *OrderedMap[T]) Values() []T {
+	data, err := valuesScript.Run(m.ctx(), m.client, m.keys()).StringSlice()
+
+	return decodeAll[T](&m.store, data, err)
+}
+
+// Size returns the number of values.
+func (m *OrderedMap[T]) Size() int {
+	return m.count(m.client.HLen(m.ctx(), m.keys()[0]).Result())
+}
+
+// Empty checks if there are no values.
+func (m *OrderedMap[T]) Empty() bool {
+	return m.Size() == 0
+}
+
+//////
+// Set.
+
+// Insert an element, returning whether it wasn't present.
+func (s *Set[T]) Insert(value T) bool {
+	data, ok := encode(&s.store, value)
+	if !ok {
+		return false
+	}
+
+	return s.count(s.client.SAdd(s.ctx(), s.key, data).Result()) == 1
+}
+
+// Remove an element, returning whether it was present.
+func (s *Set[T]) Remove(value T) bool {
+	data, ok := encode(&s.store, value)
+	if !ok {
+		return false
+	}
+
+	return s.count(s.client.SRem(s.ctx(), s.key, data).Result()) == 1
+}
+
+// Contains checks if the element is present.
+func (s *Set[T]) Contains(value T) bool {
+	data, ok := encode(&s.store, value)
+	if !ok {
+		return false
+	}
+
+	found, err := s.client.SIsMember(s.ctx(), s.key, data).Result()
+
+	return s.check(err) && found
+}
+
+// Values returns all elements, in no particular order.
+func (s *Set[T]) Values() []T {
+	data, err := s.client.SMembers(s.ctx(), s.key).Result()
+
+	return decodeAll[T](&s.store, data, err)
+}
+
+// Size returns the number of elements.
+func (s *Set[T]) Size() int {
+	return s.count(s.client.SCard(s.ctx(), s.key).Result())
+}
+
+// Empty checks if there are no elements.
+func (s *Set[T]) Empty() bool {
+	return s.Size() == 0
+}
+
+//////
+// List.
+
+// Append elements to the end of the list.
+func (l *List[T]) Append(items ...T) {
+	if len(items) == 0 {
+		return
+	}
+
+	data := make([]any, 0, len(items))
+
+	for _, item := range items {
+		d, ok := encode(&l.store, item)
+		if !ok {
+			return
+		}
+
+		data = append(data, d)
+	}
+
+	l.check(l.client.RPush(l.ctx(), l.key, data...).Err())
+}
+
+// Get the element at the given index, the zero value if out of range.
+func (l *List[T]) Get(index int) T {
+	if index < 0 {
+		return *new(T)
+	}
+
+	data, err := l.client.LIndex(l.ctx(), l.key, int64(index)).Result()
+	if !l.check(err) {
+		return *new(T)
+	}
+
+	value, _ := decode[T](&l.store, data)
+
+	return value
+}
+
+// Contains checks if the element is present.
+func (l *List[T]) Contains(item T) bool {
+	_, ok := l.Index(item)
+
+	return ok
+}
+
+// Index returns the index of the first occurrence of the element, -1 and
+// false if not found. It requires Redis 6.0.6 or later.
+func (l *List[T]) Index(item T) (int, bool) {
+	data, ok := encode(&l.store, item)
+	if !ok {
+		return -1, false
+	}
+
+	i, err := l.client.LPos(l.ctx(), l.key, data, redis.LPosArgs{}).Result()
+	if !l.check(err) {
+		return -1, false
+	}
+
+	return int(i), true
+}
+
+// Values returns all elements, in order.
+func (l *List[T]) Values() []T {
+	data, err := l.client.LRange(l.ctx(), l.key, 0, -1).Result()
+
+	return decodeAll[T](&l.store, data, err)
+}
+
+// Size returns the number of elements.
+func (l *List[T]) Size() int {
+	return l.count(l.client.LLen(l.ctx(), l.key).Result())
+}
+
+// Empty checks if there are no elements.
+func (l *List[T]) Empty() bool {
+	return l.Size() == 0
+}
+
+//////
+// Factory.
+//////
+
+// NewOrderedMap creates an OrderedMap stored under the given Redis key.
+func NewOrderedMap[T any](client redis.UniversalClient, key string) *OrderedMap[T] {
+	return &OrderedMap[T]{store: store{client: client, key: key}}
+}
+
+// NewSet creates a Set stored under the given Redis key.
+func NewSet[T any](client redis.UniversalClient, key string) *Set[T] {
+	return &Set[T]{store: store{client: client, key: key}}
+}
+
+// NewList creates a List stored under the given Redis key.
+func NewList[T comparable](client redis.UniversalClient, key string) *List[T] {
+	return &List[T]{store: store{client: client, key: key}}
+}
@@ -0,0 +1,179 @@
+package redisstore
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/thalesfsp/go-common-types/collection"
+	"github.com/thalesfsp/go-common-types/collection/collectiontest"
+)
+
+type point struct {
+	X int `json:"x"`
+	Y int `json:"y"`
+}
+
+// newClient returns a client of a new, in-memory, Redis server.
+func newClient(t *testing.T) (*miniredis.Miniredis, redis.UniversalClient) {
+	t.Helper()
+
+	server := miniredis.RunT(t)
+
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+
+	t.Cleanup(func() { client.Close() })
+
+	return server, client
+}
+
+func TestOrderedMap(t *testing.T) {
+	server, client := newClient(t)
+
+	m := NewOrderedMap[point](client, "points")
+
+	assert.Equal(t, "points", m.Key())
+	assert.True(t, m.Empty())
+
+	m.Set("b", point{1, 2})
+	m.Set("a", point{3, 4})
+	m.Set("c", point{5, 6})
+
+	// Updates keep the position.
+	m.Set("b", point{7, 8})
+
+	value, ok := m.Get("b")
+
+	assert.True(t, ok)
+	assert.Equal(t, point{7, 8}, value)
+
+	_, ok = m.Get("missing")
+
+	assert.False(t, ok)
+	assert.True(t, m.Contains("a"))
+	assert.False(t, m.Contains("missing"))
+	assert.Equal(t, 3, m.Size())
+	assert.Equal(t, []string{"b", "a", "c"}, m.Keys())
+	assert.Equal(t, []point{{7, 8}, {3, 4}, {5, 6}}, m.Values())
+
+	assert.True(t, m.Remove("a"))
+	assert.False(t, m.Remove("a"))
+	assert.Equal(t, []string{"b", "c"}, m.Keys())
+	assert.Equal(t, []point{{7, 8}, {5, 6}}, m.Values())
+
+	// Re-added keys go last.
+	m.Set("a", point{9, 9})
+
+	assert.Equal(t, []string{"b", "c", "a"}, m.Keys())
+
+	// Fields removed from the hash, bypassing the scripts, are skipped.
+	server.HDel("{points}:values", "c")
+
+	assert.Equal(t, []point{{7, 8}, {9, 9}}, m.Values())
+	assert.NoError(t, m.Err())
+
+	// Undecodable values are reported.
+	server.HSet("{points}:values", "b", "not json")
+
+	_, ok = m.Get("b")
+
+	assert.False(t, ok)
+	assert.Error(t, m.Err())
+}
+
+func TestSet(t *testing.T) {
+	_, client := newClient(t)
+
+	s := NewSet[point](client, "set")
+
+	assert.True(t, s.Insert(point{1, 2}))
+	assert.False(t, s.Insert(point{1, 2}))
+	assert.True(t, s.Insert(point{3, 4}))
+	assert.True(t, s.Contains(point{1, 2}))
+	assert.False(t, s.Contains(point{5, 6}))
+	assert.ElementsMatch(t, []point{{1, 2}, {3, 4}}, s.Values())
+	assert.Equal(t, 2, s.Size())
+	assert.True(t, s.Remove(point{1, 2}))
+	assert.False(t, s.Remove(point{1, 2}))
+	assert.Equal(t, 1, s.Size())
+	assert.NoError(t, s.Err())
+}
+
+func TestList(t *testing.T) {
+	_, client := newClient(t)
+
+	l := NewList[string](client, "list")
+
+	l.Append()
+	l.Append("a", "b", "a")
+
+	assert.Equal(t, []string{"a", "b", "a"}, l.Values())
+	assert.Equal(t, 3, l.Size())
+	assert.Equal(t, "b", l.Get(1))
+	assert.Equal(t, "", l.Get(3))
+	assert.Equal(t, "", l.Get(-1))
+
+	i, ok := l.Index("a")
+
+	assert.True(t, ok)
+	assert.Equal(t, 0, i)
+
+	i, ok = l.Index("c")
+
+	assert.False(t, ok)
+	assert.Equal(t, -1, i)
+	assert.True(t, l.Contains("b"))
+	assert.NoError(t, l.Err())
+}
+
+func TestErr(t *testing.T) {
+	server, client := newClient(t)
+
+	m := NewOrderedMap[int](client, "m")
+
+	server.Close()
+
+	m.Set("a", 1)
+
+	assert.Equal(t, 0, m.Size())
+	assert.Empty(t, m.Keys())
+	assert.Empty(t, m.Values())
+
+	// The first error is kept.
+	err := m.Err()
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), `redis "m"`)
+
+	m.Remove("a")
+
+	assert.Equal(t, err, m.Err())
+}
+
+func TestCollection(t *testing.T) {
+	_, client := newClient(t)
+
+	n := 0
+
+	name := func() string {
+		n++
+
+		return fmt.Sprintf("c%d", n)
+	}
+
+	gen := func(i int) string { return fmt.Sprintf("v%d", i) }
+
+	collectiontest.TestOrderedMap(t, func() collection.OrderedMap[string] {
+		return NewOrderedMap[string](client, name())
+	}, gen)
+
+	collectiontest.TestSet(t, func() collection.Set[string] {
+		return NewSet[string](client, name())
+	}, gen)
+
+	collectiontest.TestList(t, func() collection.List[string] {
+		return NewList[string](client, name())
+	}, gen)
+}
@@ -0,0 +1,128 @@
+// Package result provides a generic Result type, holding either a value, or
+// an error.
+package result
+
+import (
+	"fmt"
+)
+
+//////
+// Const, vars, and types.
+//////
+
+// Result holds either a value, or an error. The zero value is Ok with the zero
+// value of T.
+type Result[T any] struct {
+	value T
+	err   error
+}
+
+//////
+// Methods.
+//////
+
+// String is the stringer implementation.
+func (r Result[T]) String() string {
+	if r.err != nil {
+		return fmt.Sprintf("Err(%v)", r.err)
+	}
+
+	return fmt.Sprintf("Ok(%v)", r.value)
+}
+
+// IsOk checks if the result holds a value.
+func (r Result[T]) IsOk() bool {
+	return r.err == nil
+}
+
+// IsErr checks if the result holds an error.
+func (r Result[T]) IsErr() bool {
+	return r.err != nil
+}
+
+// Get returns the value, and the error, like a two-value call.
+func (r Result[T]) Get() (T, error) {
+	return r.value, r.err
+}
+
+// Err returns the error, nil if Ok.
+func (r Result[T]) Err() error {
+	return r.err
+}
+
+// Unwrap returns the value, panicking with the error if it's Err.
+func (r Result[T]) Unwrap() T {
+	if r.err != nil {
+		panic(r.err)
+	}
+
+	return r.value
+}
+
+// UnwrapOr returns the value if Ok, otherwise the given fallback.
+func (r Result[T]) UnwrapOr(fallback T) T {
+	if r.err != nil {
+		return fallback
+	}
+
+	return r.value
+}
+
+// UnwrapOrElse returns the value if Ok, otherwise the result of f applied to
+// the error.
+func (r Result[T]) UnwrapOrElse(f func(error) T) T {
+	if r.err != nil {
+		return f(r.err)
+	}
+
+	return r.value
+}
+
+//////
+// Factory.
+//////
+
+// Ok creates a Result holding the value.
+func Ok[T any](value T) Result[T] {
+	return Result[T]{value: value}
+}
+
+// Err creates a Result holding the error. A nil error results in Ok with the
+// zero value.
+func Err[T any](err error) Result[T] {
+	return Result[T]{err: err}
+}
+
+// From creates a Result from a two-value call, e.g.:
+// result.From(strconv.Atoi(s)).
+func From[T any](value T, err error) Result[T] {
+	if err != nil {
+		return Err[T](err)
+	}
+
+	return Ok(value)
+}
+
+//////
+// Exported Functionalities.
+//////
+
+// Map returns a Result holding the result of f applied to the value, if Ok,
+// otherwise the error.
+func Map[T, U any](r Result[T], f func(T) U) Result[U] {
+	if r.err != nil {
+		return Err[U](r.err)
+	}
+
+	return Ok(f(r.value))
+}
+
+// AndThen returns the result of f applied to the value, if Ok, otherwise the
+// error. It chains fallible operations.
+func AndThen[T, U any](r Result[T], f func(T) (U, error)) Result[U] {
+	if r.err != nil {
+		return Err[U](r.err)
+	}
+
+	return From(f(r.value))
+}
@@ -0,0 +1,48 @@
+package result
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResult(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	ok := Ok(1)
+	failed := Err[int](errBoom)
+
+	assert.True(t, ok.IsOk())
+	assert.False(t, ok.IsErr())
+	assert.True(t, failed.IsErr())
+	assert.ErrorIs(t, failed.Err(), errBoom)
+	assert.NoError(t, ok.Err())
+
+	v, err := ok.Get()
+	assert.NoError(t, err)
+	assert.Equal(t, 1, v)
+
+	assert.Equal(t, 1, ok.Unwrap())
+	assert.PanicsWithError(t, "boom", func() { failed.Unwrap() })
+
+	assert.Equal(t, 2, failed.UnwrapOr(2))
+	assert.Equal(t, 1, ok.UnwrapOr(2))
+	assert.Equal(t, 4, failed.UnwrapOrElse(func(err error) int { return len(err.Error()) }))
+
+	assert.Equal(t, "Ok(1)", ok.String())
+	assert.Equal(t, "Err(boom)", failed.String())
+}
+
+func TestResultFromMap(t *testing.T) {
+	assert.Equal(t, Ok(42), From(strconv.Atoi("42")))
+	assert.True(t, From(strconv.Atoi("x")).IsErr())
+
+	assert.Equal(t, Ok("42"), Map(Ok(42), strconv.Itoa))
+	assert.True(t, Map(Err[int](errors.New("boom")), strconv.Itoa).IsErr())
+
+	assert.Equal(t, Ok(42), AndThen(Ok("42"), strconv.Atoi))
+	assert.True(t, AndThen(Ok("x"), strconv.Atoi).IsErr())
+	assert.True(t, AndThen(Err[string](errors.New("boom")), strconv.Atoi).IsErr())
+}
@@ -0,0 +1,68 @@
+package safebloom
+
+//////
+// Const, vars, and types.
+//////
+
+// ReadOnly is a read-only view of a SafeBloom, see SafeBloom.ReadOnly.
+type ReadOnly[T any] interface {
+	// MayContain checks if the element may have been added.
+	MayContain(value T) bool
+
+	// Bits returns the number of bits of the filter.
+	Bits() uint64
+
+	// Hashes returns the number of hash functions of the filter.
+	Hashes() uint64
+
+	// FillRatio returns the ratio of bits set.
+	FillRatio() float64
+
+	// Clone returns a copy of the filter, which can be modified.
+	Clone() *SafeBloom[T]
+
+	// String returns the string representation of the filter.
+	String() string
+
+	// MarshalBinary marshals the filter.
+	MarshalBinary() ([]byte, error)
+}
+
+// readOnly implements ReadOnly. The filter isn't exported, so the view can't
+// be converted back.
+type readOnly[T any] struct {
+	b *SafeBloom[T]
+}
+
+//////
+// Methods.
+//////
+
+// MayContain implements ReadOnly.
+func (v readOnly[T]) MayContain(value T) bool { return v.b.MayContain(value) }
+
+// Bits implements ReadOnly.
+func (v readOnly[T]) Bits() uint64 { return v.b.Bits() }
+
+// Hashes implements ReadOnly.
+func (v readOnly[T]) Hashes() uint64 { return v.b.Hashes() }
+
+// FillRatio implements ReadOnly.
+func (v readOnly[T]) FillRatio() float64 { return v.b.FillRatio() }
+
+// Clone implements ReadOnly.
+func (v readOnly[T]) Clone() *SafeBloom[T] { return v.b.Clone() }
+
+// String implements ReadOnly.
+func (v readOnly[T]) String() string { return v.b.String() }
+
+// MarshalBinary implements ReadOnly.
+func (v readOnly[T]) MarshalBinary() ([]byte, error) { return v.b.MarshalBinary() }
+
+// ReadOnly returns a read-only view of the filter, e.g.: to hand it out
+// without copying it. The view isn't a snapshot: it reflects later additions
+// to the filter. It can't be converted back to the filter, use Clone to get a
+// copy which can be modified.
+func (b *SafeBloom[T]) ReadOnly() ReadOnly[T] {
+	return readOnly[T]{b: b}
+}
@@ -0,0 +1,33 @@
+package safebloom
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadOnly(t *testing.T) {
+	b := New[string](100, 0.01)
+
+	view := b.ReadOnly()
+
+	b.Add("a")
+
+	// The view reflects later changes.
+	assert.True(t, view.MayContain("a"))
+	assert.Equal(t, b.Bits(), view.Bits())
+
+	// The view can't be converted back.
+	_, ok := any(view).(*SafeBloom[string])
+	assert.False(t, ok)
+
+	_, ok = any(view).(interface {
+		Add(string) *SafeBloom[string]
+	})
+	assert.False(t, ok)
+
+	// A clone can be modified, without affecting the filter.
+	view.Clone().Add("b")
+
+	assert.False(t, b.MayContain("b"))
+}
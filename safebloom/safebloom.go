@@ -0,0 +1,379 @@
+package safebloom
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"math/bits"
+	"sync"
+	"time"
+	"unsafe"
+
+	"github.com/thalesfsp/go-common-types/metrics"
+	"github.com/thalesfsp/go-common-types/shared"
+)
+
+//////
+// Const, vars, and types.
+//////
+
+// DefaultFalsePositiveRate is used when the given false-positive rate isn't
+// in the (0, 1) range.
+const DefaultFalsePositiveRate = 0.01
+
+// binaryVersion is the version of the binary serialization format.
+const binaryVersion = 1
+
+// headerSize is the size of the binary header: version, bits, and hashes.
+const headerSize = 1 + 8 + 8
+
+var (
+	// ErrIncompatible is returned when merging filters with a different number
+	// of bits, or hashes.
+	ErrIncompatible = errors.New("incompatible bloom filters")
+
+	// ErrInvalidData is returned when unmarshalling invalid binary data.
+	ErrInvalidData = errors.New("invalid bloom filter data")
+)
+
+// Option allows to configure a SafeBloom.
+type Option[T any] func(b *SafeBloom[T])
+
+// SafeBloom is a Bloom filter, safe for concurrent use, powered by generics.
+// It answers membership tests with no false negatives, and a configurable
+// false-positive rate, using a fraction of the memory of a set.
+//
+// Elements are hashed with shared.GenerateHash, like SafeSet, and the k bit
+// positions are derived from the hash by double hashing.
+type SafeBloom[T any] struct {
+	sync.RWMutex
+
+	words  []uint64
+	bits   uint64
+	hashes uint64
+
+	metrics *metrics.Metrics
+}
+
+//////
+// Methods.
+//////
+
+// lock acquires the write lock, recording the wait time if metrics are
+// enabled.
+func (b *SafeBloom[T]) lock() {
+	if b.metrics == nil {
+		b.Lock()
+
+		return
+	}
+
+	start := time.Now()
+
+	b.Lock()
+
+	b.metrics.ObserveLockWait(time.Since(start))
+}
+
+// rlock acquires the read lock, recording the wait time if metrics are
+// enabled.
+func (b *SafeBloom[T]) rlock() {
+	if b.metrics == nil {
+		b.RLock()
+
+		return
+	}
+
+	start := time.Now()
+
+	b.RLock()
+
+	b.metrics.ObserveLockWait(time.Since(start))
+}
+
+// positions calls f with the k bit positions of the value.
+func (b *SafeBloom[T]) positions(value T, f func(word uint64, mask uint64) bool) {
+	sum := shared.GenerateHashSum(value)
+
+	h1 := binary.LittleEndian.Uint64(sum[0:8])
+	h2 := binary.LittleEndian.Uint64(sum[8:16]) | 1
+
+	for i := uint64(0); i < b.hashes; i++ {
+		pos := (h1 + i*h2) % b.bits
+
+		if !f(pos/64, 1<<(pos%64)) {
+			return
+		}
+	}
+}
+
+// Metrics returns the metrics of the filter, nil if not enabled.
+func (b *SafeBloom[T]) Metrics() *metrics.Metrics {
+	return b.metrics
+}
+
+// String is the stringer implementation.
+func (b *SafeBloom[T]) String() string {
+	fill := b.FillRatio()
+
+	b.rlock()
+	defer b.RUnlock()
+
+	return fmt.Sprintf("SafeBloom{bits: %d, hashes: %d, fill: %.4f}", b.bits, b.hashes, fill)
+}
+
+// Bits returns the number of bits of the filter.
+func (b *SafeBloom[T]) Bits() uint64 {
+	b.rlock()
+	defer b.RUnlock()
+
+	return b.bits
+}
+
+// EstimateBytes estimates the memory used by the filter, in bytes, which
+// depends only on its number of bits. The estimate is recorded into the
+// metrics, if enabled.
+func (b *SafeBloom[T]) EstimateBytes() int64 {
+	b.rlock()
+	defer b.RUnlock()
+
+	size := int64(unsafe.Sizeof(*b)) + int64(cap(b.words))*int64(unsafe.Sizeof(uint64(0)))
+
+	b.metrics.Operation("estimateBytes")
+	b.metrics.SetBytes(size)
+
+	return size
+}
+
+// Hashes returns the number of hashes, bits set per element.
+func (b *SafeBloom[T]) Hashes() uint64 {
+	b.rlock()
+	defer b.RUnlock()
+
+	return b.hashes
+}
+
+// FillRatio returns the ratio of bits set. The false-positive rate is roughly
+// FillRatio ^ Hashes.
+func (b *SafeBloom[T]) FillRatio() float64 {
+	b.rlock()
+	defer b.RUnlock()
+
+	set := 0
+
+	for _, w := range b.words {
+		set += bits.OnesCount64(w)
+	}
+
+	return float64(set) / float64(b.bits)
+}
+
+// Add an element to the filter.
+func (b *SafeBloom[T]) Add(value T) *SafeBloom[T] {
+	b.lock()
+	defer b.Unlock()
+
+	b.positions(value, func(word, mask uint64) bool {
+		b.words[word] |= mask
+
+		return true
+	})
+
+	b.metrics.Operation("add")
+
+	return b
+}
+
+// MayContain checks if the element may have been added. False means it
+// definitely wasn't, true means it probably was.
+func (b *SafeBloom[T]) MayContain(value T) bool {
+	b.rlock()
+	defer b.RUnlock()
+
+	found := true
+
+	b.positions(value, func(word, mask uint64) bool {
+		found = b.words[word]&mask != 0
+
+		return found
+	})
+
+	b.metrics.Operation("mayContain")
+	b.metrics.Lookup(found)
+
+	return found
+}
+
+// Clear removes all elements from the filter.
+func (b *SafeBloom[T]) Clear() *SafeBloom[T] {
+	b.lock()
+	defer b.Unlock()
+
+	for i := range b.words {
+		b.words[i] = 0
+	}
+
+	b.metrics.Operation("clear")
+
+	return b
+}
+
+// Merge adds all elements of the other filter, which must have the same
+// number of bits, and hashes, e.g.: created with the same parameters.
+func (b *SafeBloom[T]) Merge(other *SafeBloom[T]) error {
+	other.rlock()
+	words := make([]uint64, len(other.words))
+	copy(words, other.words)
+	nbits, hashes := other.bits, other.hashes
+	other.RUnlock()
+
+	b.lock()
+	defer b.Unlock()
+
+	if b.bits != nbits || b.hashes != hashes {
+		return fmt.Errorf("%w: %d bits, %d hashes, and %d bits, %d hashes",
+			ErrIncompatible, b.bits, b.hashes, nbits, hashes)
+	}
+
+	for i, w := range words {
+		b.words[i] |= w
+	}
+
+	b.metrics.Operation("merge")
+
+	return nil
+}
+
+// Clone returns a copy of the filter.
+func (b *SafeBloom[T]) Clone() *SafeBloom[T] {
+	b.rlock()
+	defer b.RUnlock()
+
+	words := make([]uint64, len(b.words))
+	copy(words, b.words)
+
+	return &SafeBloom[T]{
+		words:  words,
+		bits:   b.bits,
+		hashes: b.hashes,
+	}
+}
+
+//////
+// Conversion Operations.
+//////
+
+// MarshalBinary implements the encoding.BinaryMarshaler interface. The format
+// is a version byte, the number of bits, and hashes, and the bits, all little
+// endian.
+func (b *SafeBloom[T]) MarshalBinary() ([]byte, error) {
+	b.rlock()
+	defer b.RUnlock()
+
+	data := make([]byte, headerSize, headerSize+8*len(b.words))
+
+	data[0] = binaryVersion
+
+	binary.LittleEndian.PutUint64(data[1:9], b.bits)
+	binary.LittleEndian.PutUint64(data[9:17], b.hashes)
+
+	for _, w := range b.words {
+		data = binary.LittleEndian.AppendUint64(data, w)
+	}
+
+	return data, nil
+}
+
+// UnmarshalBinary implements the encoding.BinaryUnmarshaler interface, the
+// inverse of MarshalBinary. It replaces the filter, including its number of
+// bits, and hashes.
+func (b *SafeBloom[T]) UnmarshalBinary(data []byte) error {
+	if len(data) < headerSize || data[0] != binaryVersion {
+		return ErrInvalidData
+	}
+
+	nbits := binary.LittleEndian.Uint64(data[1:9])
+	hashes := binary.LittleEndian.Uint64(data[9:17])
+	body := data[headerSize:]
+
+	if nbits == 0 || hashes == 0 || uint64(len(body)) != 8*words(nbits) {
+		return ErrInvalidData
+	}
+
+	ws := make([]uint64, 0, len(body)/8)
+
+	for i := 0; i < len(body); i += 8 {
+		ws = append(ws, binary.LittleEndian.Uint64(body[i:i+8]))
+	}
+
+	b.lock()
+	defer b.Unlock()
+
+	b.words, b.bits, b.hashes = ws, nbits, hashes
+
+	b.metrics.Operation("unmarshal")
+
+	return nil
+}
+
+//////
+// Factory.
+//////
+
+// words returns the number of words holding the given number of bits.
+func words(nbits uint64) uint64 {
+	return (nbits + 63) / 64
+}
+
+// WithMetrics enables metrics instrumentation, tracking operation counts,
+// lock wait times, and hit/miss ratios into the given metrics.
+func WithMetrics[T any](mtrcs *metrics.Metrics) Option[T] {
+	return func(b *SafeBloom[T]) {
+		b.metrics = mtrcs
+	}
+}
+
+// New creates a Bloom filter sized for the expected number of elements, and
+// the desired false-positive rate, in the (0, 1) range, otherwise
+// DefaultFalsePositiveRate is used.
+func New[T any](expected uint, falsePositiveRate float64, opts ...Option[T]) *SafeBloom[T] {
+	if expected == 0 {
+		expected = 1
+	}
+
+	if falsePositiveRate <= 0 || falsePositiveRate >= 1 {
+		falsePositiveRate = DefaultFalsePositiveRate
+	}
+
+	n := float64(expected)
+
+	nbits := uint64(math.Ceil(-n * math.Log(falsePositiveRate) / (math.Ln2 * math.Ln2)))
+	hashes := uint64(math.Max(1, math.Round(float64(nbits)/n*math.Ln2)))
+
+	return NewWithSize[T](nbits, hashes, opts...)
+}
+
+// NewWithSize creates a Bloom filter with the given number of bits, and
+// hashes, at least 1.
+func NewWithSize[T any](nbits, hashes uint64, opts ...Option[T]) *SafeBloom[T] {
+	if nbits == 0 {
+		nbits = 1
+	}
+
+	if hashes == 0 {
+		hashes = 1
+	}
+
+	b := &SafeBloom[T]{
+		words:  make([]uint64, words(nbits)),
+		bits:   nbits,
+		hashes: hashes,
+	}
+
+	for _, opt := range opts {
+		opt(b)
+	}
+
+	return b
+}
@@ -0,0 +1,133 @@
+package safebloom
+
+import (
+	"encoding"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/thalesfsp/go-common-types/metrics"
+)
+
+var (
+	_ encoding.BinaryMarshaler   = (*SafeBloom[int])(nil)
+	_ encoding.BinaryUnmarshaler = (*SafeBloom[int])(nil)
+)
+
+func TestSafeBloomSizing(t *testing.T) {
+	b := New[int](1_000, 0.01)
+
+	assert.Equal(t, uint64(9586), b.Bits())
+	assert.Equal(t, uint64(7), b.Hashes())
+
+	d := New[int](0, 2)
+
+	assert.Equal(t, New[int](1, DefaultFalsePositiveRate).Bits(), d.Bits())
+}
+
+func TestSafeBloomEstimateBytes(t *testing.T) {
+	m := metrics.New("test")
+
+	b := New[int](1_000, 0.01, WithMetrics[int](m))
+
+	// 9586 bits take 150 words.
+	assert.GreaterOrEqual(t, b.EstimateBytes(), int64(150*8))
+	assert.Equal(t, b.EstimateBytes(), m.Snapshot().Bytes)
+}
+
+func TestSafeBloomAddMayContain(t *testing.T) {
+	b := New[string](1_000, 0.01)
+
+	for i := 0; i < 1_000; i++ {
+		b.Add(fmt.Sprintf("in-%d", i))
+	}
+
+	for i := 0; i < 1_000; i++ {
+		assert.True(t, b.MayContain(fmt.Sprintf("in-%d", i)))
+	}
+
+	falsePositives := 0
+
+	for i := 0; i < 10_000; i++ {
+		if b.MayContain(fmt.Sprintf("out-%d", i)) {
+			falsePositives++
+		}
+	}
+
+	assert.Less(t, falsePositives, 300)
+
+	b.Clear()
+
+	assert.False(t, b.MayContain("in-1"))
+	assert.Equal(t, float64(0), b.FillRatio())
+}
+
+func TestSafeBloomConcurrent(t *testing.T) {
+	b := New[int](10_000, 0.01)
+
+	var wg sync.WaitGroup
+
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+
+		go func(g int) {
+			defer wg.Done()
+
+			for i := 0; i < 1_000; i++ {
+				b.Add(g*1_000 + i)
+				b.MayContain(i)
+			}
+		}(g)
+	}
+
+	wg.Wait()
+
+	for i := 0; i < 8_000; i++ {
+		assert.True(t, b.MayContain(i))
+	}
+}
+
+func TestSafeBloomMerge(t *testing.T) {
+	b1 := New[int](100, 0.01)
+	b2 := New[int](100, 0.01)
+
+	b1.Add(1)
+	b2.Add(2)
+
+	assert.NoError(t, b1.Merge(b2))
+	assert.True(t, b1.MayContain(1))
+	assert.True(t, b1.MayContain(2))
+	assert.False(t, b2.MayContain(1))
+
+	assert.ErrorIs(t, b1.Merge(New[int](1_000, 0.01)), ErrIncompatible)
+}
+
+func TestSafeBloomMarshalUnmarshalBinary(t *testing.T) {
+	b := New[string](100, 0.01, WithMetrics[string](metrics.New("bloom")))
+	b.Add("a").Add("b")
+
+	data, err := b.MarshalBinary()
+	assert.NoError(t, err)
+
+	c := NewWithSize[string](1, 1)
+	assert.NoError(t, c.UnmarshalBinary(data))
+	assert.Equal(t, b.Bits(), c.Bits())
+	assert.Equal(t, b.Hashes(), c.Hashes())
+	assert.True(t, c.MayContain("a"))
+	assert.True(t, c.MayContain("b"))
+
+	assert.ErrorIs(t, c.UnmarshalBinary(data[:len(data)-1]), ErrInvalidData)
+	assert.ErrorIs(t, c.UnmarshalBinary(nil), ErrInvalidData)
+
+	assert.Equal(t, uint64(2), b.Metrics().Snapshot().Operations["add"])
+}
+
+func TestSafeBloomClone(t *testing.T) {
+	b := New[int](100, 0.01).Add(1)
+	c := b.Clone().Add(2)
+
+	assert.True(t, c.MayContain(1))
+	assert.False(t, b.MayContain(2))
+	assert.Contains(t, b.String(), "hashes: 7")
+}
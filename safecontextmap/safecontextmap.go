@@ -0,0 +1,212 @@
+// Package safecontextmap provides Map, an immutable ordered map of
+// request-scoped values, with typed getters, carried by a context.Context,
+// e.g.: set by HTTP, or gRPC, middlewares, and read by handlers, without
+// type assertions.
+package safecontextmap
+
+import (
+	"bytes"
+	"context"
+	"time"
+
+	"github.com/thalesfsp/go-common-types/safeorderedmap"
+)
+
+//////
+// Const, vars, and types.
+//////
+
+// contextKey is the key of the Map in a context.
+type contextKey struct{}
+
+// Map is an immutable ordered map of values keyed by string, e.g.: the
+// request ID, the user, or the tenant of a request. Methods adding, or
+// removing, keys return a new Map, leaving the original untouched, so a Map
+// can be shared between goroutines, and stored in a context, like the context
+// values themselves. The zero value, and nil, are empty maps.
+//
+// Copies cost O(n): a Map is meant for the few values of a request.
+type Map struct {
+	entries *safeorderedmap.SafeOrderedMap[any]
+}
+
+//////
+// Methods.
+//////
+
+// orderedMap returns the entries, never modified.
+func (m *Map) orderedMap() *safeorderedmap.SafeOrderedMap[any] {
+	if m == nil || m.entries == nil {
+		return safeorderedmap.New[any]()
+	}
+
+	return m.entries
+}
+
+// With returns a copy of the map with the key set to the value, added at the
+// end if new.
+func (m *Map) With(key string, value any) *Map {
+	entries := m.orderedMap().Clone()
+
+	entries.Add(key, value)
+
+	return &Map{entries: entries}
+}
+
+// WithEntries returns a copy of the map with the entries set, in order.
+func (m *Map) WithEntries(entries ...safeorderedmap.Entry[any]) *Map {
+	clone := m.orderedMap().Clone()
+
+	for _, entry := range entries {
+		clone.Add(entry.Key, entry.Value)
+	}
+
+	return &Map{entries: clone}
+}
+
+// Without returns a copy of the map without the keys.
+func (m *Map) Without(keys ...string) *Map {
+	entries := m.orderedMap().Clone()
+
+	entries.DeleteMany(keys...)
+
+	return &Map{entries: entries}
+}
+
+// Get returns the value of the key, and whether it's present.
+func (m *Map) Get(key string) (any, bool) {
+	return m.orderedMap().Get(key)
+}
+
+// Contains checks if the key is present.
+func (m *Map) Contains(key string) bool {
+	return m.orderedMap().Contains(key)
+}
+
+// Keys returns the keys, in order.
+func (m *Map) Keys() []string {
+	return m.orderedMap().Keys()
+}
+
+// Entries returns the key-value pairs, in order.
+func (m *Map) Entries() []safeorderedmap.Entry[any] {
+	return m.orderedMap().Entries()
+}
+
+// Size returns the number of keys.
+func (m *Map) Size() int {
+	return m.orderedMap().Size()
+}
+
+// GetString returns the value of the key as a string, see
+// safeorderedmap.GetString.
+func (m *Map) GetString(key string) (string, error) {
+	return safeorderedmap.GetString(m.orderedMap(), key)
+}
+
+// GetInt returns the value of the key as an int, see safeorderedmap.GetInt.
+func (m *Map) GetInt(key string) (int, error) {
+	return safeorderedmap.GetInt(m.orderedMap(), key)
+}
+
+// GetFloat64 returns the value of the key as a float64, see
+// safeorderedmap.GetFloat64.
+func (m *Map) GetFloat64(key string) (float64, error) {
+	return safeorderedmap.GetFloat64(m.orderedMap(), key)
+}
+
+// GetBool returns the value of the key as a bool.
+func (m *Map) GetBool(key string) (bool, error) {
+	return safeorderedmap.GetBool(m.orderedMap(), key)
+}
+
+// GetTime returns the value of the key as a time.Time, see
+// safeorderedmap.GetTime.
+func (m *Map) GetTime(key string) (time.Time, error) {
+	return safeorderedmap.GetTime(m.orderedMap(), key)
+}
+
+// GetStringSlice returns the value of the key as a []string, see
+// safeorderedmap.GetStringSlice.
+func (m *Map) GetStringSlice(key string) ([]string, error) {
+	return safeorderedmap.GetStringSlice(m.orderedMap(), key)
+}
+
+// ReadOnly returns a read-only view of the entries.
+func (m *Map) ReadOnly() safeorderedmap.ReadOnly[any] {
+	return m.orderedMap().ReadOnly()
+}
+
+// ToOrderedMap returns a copy of the entries, which can be modified.
+func (m *Map) ToOrderedMap() *safeorderedmap.SafeOrderedMap[any] {
+	return m.orderedMap().Clone()
+}
+
+// String is the stringer implementation, see SafeOrderedMap.String.
+func (m *Map) String() string {
+	return m.orderedMap().String()
+}
+
+// MarshalJSON implements the json.Marshaler interface, encoding the entries
+// as a JSON object, in order, e.g.: for structured logs.
+func (m *Map) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+
+	if err := m.orderedMap().Encode(&buf); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+//////
+// Exported functionalities.
+//////
+
+// As returns the value of the key as V, e.g.: As[int](m, "port"), see
+// safeorderedmap.As.
+func As[V any](m *Map, key string) (V, error) {
+	return safeorderedmap.As[V](m.orderedMap(), key)
+}
+
+// NewContext returns a copy of the context carrying the map.
+func NewContext(ctx context.Context, m *Map) context.Context {
+	return context.WithValue(ctx, contextKey{}, m)
+}
+
+// FromContext returns the map carried by the context, or an empty one.
+func FromContext(ctx context.Context) *Map {
+	if m, ok := ctx.Value(contextKey{}).(*Map); ok && m != nil {
+		return m
+	}
+
+	return New()
+}
+
+// WithValue returns a copy of the context carrying a copy of its map, with
+// the key set to the value, e.g.: in a middleware. The map of the parent
+// context is untouched.
+func WithValue(ctx context.Context, key string, value any) context.Context {
+	return NewContext(ctx, FromContext(ctx).With(key, value))
+}
+
+// Value returns the value of the key, in the map carried by the context, as
+// V, e.g.: Value[string](ctx, "requestID"), see As.
+func Value[V any](ctx context.Context, key string) (V, error) {
+	return As[V](FromContext(ctx), key)
+}
+
+//////
+// Factory.
+//////
+
+// New creates a Map with the entries, in order.
+func New(entries ...safeorderedmap.Entry[any]) *Map {
+	return &Map{entries: safeorderedmap.FromEntries(entries)}
+}
+
+// FromOrderedMap creates a Map with a copy of the entries of the ordered map,
+// so later changes to it don't affect the Map.
+func FromOrderedMap(m *safeorderedmap.SafeOrderedMap[any]) *Map {
+	return &Map{entries: m.Clone()}
+}
@@ -0,0 +1,137 @@
+package safecontextmap
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/thalesfsp/go-common-types/safeorderedmap"
+)
+
+func TestMapImmutable(t *testing.T) {
+	a := New(safeorderedmap.Entry[any]{Key: "requestID", Value: "r-1"})
+	b := a.With("user", "alice").With("attempt", 2)
+
+	assert.Equal(t, []string{"requestID"}, a.Keys())
+	assert.Equal(t, []string{"requestID", "user", "attempt"}, b.Keys())
+	assert.Equal(t, 3, b.Size())
+
+	c := b.Without("user")
+
+	assert.False(t, c.Contains("user"))
+	assert.True(t, b.Contains("user"))
+
+	d := c.WithEntries(safeorderedmap.Entry[any]{Key: "tenant", Value: "acme"})
+
+	v, ok := d.Get("tenant")
+	assert.True(t, ok)
+	assert.Equal(t, "acme", v)
+	assert.Equal(t, []safeorderedmap.Entry[any]{{Key: "requestID", Value: "r-1"}, {Key: "attempt", Value: 2}}, c.Entries())
+
+	// Copies don't leak changes.
+	copied := d.ToOrderedMap()
+	copied.Add("x", 1)
+
+	assert.False(t, d.Contains("x"))
+}
+
+func TestMapNil(t *testing.T) {
+	var m *Map
+
+	assert.Equal(t, 0, m.Size())
+	assert.Equal(t, []string{"a"}, m.With("a", 1).Keys())
+	assert.Equal(t, 0, (&Map{}).Size())
+}
+
+func TestMapTypedGetters(t *testing.T) {
+	m := New().With("name", "alice").With("port", 8080.0).With("admin", true).
+		With("since", "2023-10-01T00:00:00Z").With("roles", []any{"a", "b"}).With("ratio", 1)
+
+	name, err := m.GetString("name")
+	assert.NoError(t, err)
+	assert.Equal(t, "alice", name)
+
+	port, err := m.GetInt("port")
+	assert.NoError(t, err)
+	assert.Equal(t, 8080, port)
+
+	ratio, err := m.GetFloat64("ratio")
+	assert.NoError(t, err)
+	assert.Equal(t, 1.0, ratio)
+
+	admin, err := m.GetBool("admin")
+	assert.NoError(t, err)
+	assert.True(t, admin)
+
+	since, err := m.GetTime("since")
+	assert.NoError(t, err)
+	assert.Equal(t, 2023, since.Year())
+
+	roles, err := m.GetStringSlice("roles")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a", "b"}, roles)
+
+	_, err = m.GetInt("name")
+	assert.True(t, errors.Is(err, safeorderedmap.ErrType))
+
+	_, err = As[string](m, "missing")
+	assert.True(t, errors.Is(err, safeorderedmap.ErrKeyNotFound))
+}
+
+func TestContext(t *testing.T) {
+	ctx := context.Background()
+
+	assert.Equal(t, 0, FromContext(ctx).Size())
+
+	parent := WithValue(ctx, "requestID", "r-1")
+	child := WithValue(parent, "user", "alice")
+
+	assert.Equal(t, []string{"requestID"}, FromContext(parent).Keys())
+	assert.Equal(t, []string{"requestID", "user"}, FromContext(child).Keys())
+
+	user, err := Value[string](child, "user")
+	assert.NoError(t, err)
+	assert.Equal(t, "alice", user)
+
+	_, err = Value[string](parent, "user")
+	assert.Error(t, err)
+
+	replaced := NewContext(child, New())
+	assert.Equal(t, 0, FromContext(replaced).Size())
+}
+
+func TestMapJSON(t *testing.T) {
+	m := New().With("b", 1).With("a", "x")
+
+	data, err := json.Marshal(m)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"b":1,"a":"x"}`, string(data))
+	assert.NotEmpty(t, m.String())
+
+	m2 := FromOrderedMap(safeorderedmap.New[any]().Add("k", "v"))
+	assert.Equal(t, []string{"k"}, m2.ReadOnly().Keys())
+}
+
+func TestMapConcurrent(t *testing.T) {
+	m := New().With("a", 1)
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+
+		go func(i int) {
+			defer wg.Done()
+
+			derived := m.With("b", i)
+
+			assert.Equal(t, 2, derived.Size())
+			assert.Equal(t, 1, m.Size())
+		}(i)
+	}
+
+	wg.Wait()
+}
@@ -0,0 +1,56 @@
+package safeexpiringset
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/thalesfsp/go-common-types/metrics"
+	"github.com/thalesfsp/go-common-types/shared"
+)
+
+func TestWithCapacity(t *testing.T) {
+	m := metrics.New("capacity")
+
+	s, c := newTestSet(time.Minute, WithCapacity[string](2, nil), WithMetrics[string](m))
+
+	s.Add("a")
+	s.Add("b")
+	s.Add("c")
+
+	// Without a policy, the closest to expire is evicted.
+	assert.Equal(t, []string{"b", "c"}, s.Values())
+
+	c.Advance(time.Minute)
+
+	assert.True(t, s.Empty())
+	assert.Equal(t, map[string]uint64{
+		shared.EvictCapacity: 1,
+		shared.EvictExpired:  2,
+	}, m.Snapshot().Evictions)
+}
+
+func TestWithCapacityLFU(t *testing.T) {
+	s, _ := newTestSet(time.Minute, WithCapacity[string](2, shared.NewLFU[string]()))
+
+	s.Add("a")
+	s.Add("b")
+
+	assert.True(t, s.Contains("a"))
+
+	s.Add("c")
+
+	// "b" was never accessed.
+	assert.Equal(t, []string{"a", "c"}, s.Values())
+
+	s.Remove("a")
+	s.Add("d")
+	s.Add("e")
+
+	assert.Equal(t, 2, s.Size())
+
+	s.Clear()
+	s.Add("f")
+
+	assert.Equal(t, []string{"f"}, s.Values())
+}
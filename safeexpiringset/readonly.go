@@ -0,0 +1,72 @@
+package safeexpiringset
+
+import "time"
+
+//////
+// Const, vars, and types.
+//////
+
+// ReadOnly is a read-only view of a SafeExpiringSet, see
+// SafeExpiringSet.ReadOnly.
+type ReadOnly[T any] interface {
+	// Contains checks if the element is present, and not expired. In sliding
+	// mode, its expiration is still renewed.
+	Contains(value T) bool
+
+	// ExpiresIn returns the time left before the element expires.
+	ExpiresIn(value T) (time.Duration, bool)
+
+	// Values returns the non-expired elements, from the closest to expire to
+	// the farthest.
+	Values() []T
+
+	// Size returns the number of non-expired elements.
+	Size() int
+
+	// Empty checks if there are no non-expired elements.
+	Empty() bool
+
+	// TTL returns the time to live of the elements.
+	TTL() time.Duration
+
+	// String returns the string representation of the set.
+	String() string
+}
+
+// readOnly implements ReadOnly. The set isn't exported, so the view can't be
+// converted back.
+type readOnly[T any] struct {
+	s *SafeExpiringSet[T]
+}
+
+//////
+// Methods.
+//////
+
+// Contains implements ReadOnly.
+func (v readOnly[T]) Contains(value T) bool { return v.s.Contains(value) }
+
+// ExpiresIn implements ReadOnly.
+func (v readOnly[T]) ExpiresIn(value T) (time.Duration, bool) { return v.s.ExpiresIn(value) }
+
+// Values implements ReadOnly.
+func (v readOnly[T]) Values() []T { return v.s.Values() }
+
+// Size implements ReadOnly.
+func (v readOnly[T]) Size() int { return v.s.Size() }
+
+// Empty implements ReadOnly.
+func (v readOnly[T]) Empty() bool { return v.s.Empty() }
+
+// TTL implements ReadOnly.
+func (v readOnly[T]) TTL() time.Duration { return v.s.TTL() }
+
+// String implements ReadOnly.
+func (v readOnly[T]) String() string { return v.s.String() }
+
+// ReadOnly returns a read-only view of the set, e.g.: to hand it out without
+// copying it. The view isn't a snapshot: it reflects later changes to the set,
+// and expirations. It can't be converted back to the set.
+func (s *SafeExpiringSet[T]) ReadOnly() ReadOnly[T] {
+	return readOnly[T]{s: s}
+}
@@ -0,0 +1,30 @@
+package safeexpiringset
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadOnly(t *testing.T) {
+	s := New[string](time.Hour)
+
+	view := s.ReadOnly()
+
+	s.Add("a")
+
+	// The view reflects later changes.
+	assert.Equal(t, 1, view.Size())
+	assert.True(t, view.Contains("a"))
+	assert.Equal(t, time.Hour, view.TTL())
+
+	// The view can't be converted back.
+	_, ok := any(view).(*SafeExpiringSet[string])
+	assert.False(t, ok)
+
+	_, ok = any(view).(interface {
+		Add(string) *SafeExpiringSet[string]
+	})
+	assert.False(t, ok)
+}
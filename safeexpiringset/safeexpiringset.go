@@ -0,0 +1,420 @@
+package safeexpiringset
+
+import (
+	"container/list"
+	"fmt"
+	"hash"
+	"strings"
+	"sync"
+	"time"
+	"unsafe"
+
+	"github.com/thalesfsp/go-common-types/metrics"
+	"github.com/thalesfsp/go-common-types/shared"
+)
+
+//////
+// Const, vars, and types.
+//////
+
+// Option allows to configure a SafeExpiringSet.
+type Option[T any] func(s *SafeExpiringSet[T])
+
+// entry is an element of the set.
+type entry[T any] struct {
+	hash      string
+	value     T
+	expiresAt time.Time
+}
+
+// SafeExpiringSet is a set, safe for concurrent use, whose elements expire
+// after a TTL, e.g.: to deduplicate event IDs over a time window.
+//
+// Elements are kept ordered by expiration, so expired elements are removed
+// lazily, in amortized O(1), by every operation. There are no goroutines, nor
+// timers.
+//
+// The zero value doesn't panic, but, without a TTL, its elements expire
+// immediately: use New.
+type SafeExpiringSet[T any] struct {
+	sync.Mutex
+
+	ttl     time.Duration
+	sliding bool
+
+	data  map[string]*list.Element
+	order *list.List
+
+	now func() time.Time
+
+	// capacity is the maximum number of elements, 0 if unbounded. When full,
+	// the policy chooses the element to evict, or the closest to expire if
+	// nil.
+	capacity int
+	policy   shared.EvictionPolicy[string]
+
+	metrics *metrics.Metrics
+
+	sizer shared.Sizer[T]
+}
+
+//////
+// Methods.
+//////
+
+// lock acquires the lock, recording the wait time if metrics are enabled, and
+// removes expired elements, returning the current time.
+func (s *SafeExpiringSet[T]) lock() time.Time {
+	if s.metrics == nil {
+		s.Lock()
+	} else {
+		start := time.Now()
+
+		s.Lock()
+
+		s.metrics.ObserveLockWait(time.Since(start))
+	}
+
+	if s.data == nil {
+		s.data = make(map[string]*list.Element)
+		s.order = list.New()
+		s.now = time.Now
+	}
+
+	now := s.now()
+
+	for e := s.order.Front(); e != nil; e = s.order.Front() {
+		//nolint:forcetypeassert
+		if now.Before(e.Value.(*entry[T]).expiresAt) {
+			break
+		}
+
+		s.remove(e)
+
+		s.metrics.Operation("expire")
+		s.metrics.Evicted(shared.EvictExpired)
+	}
+
+	s.metrics.SetSize(len(s.data))
+
+	return now
+}
+
+// remove removes the element. Caller must hold the lock.
+func (s *SafeExpiringSet[T]) remove(e *list.Element) {
+	//nolint:forcetypeassert
+	hash := e.Value.(*entry[T]).hash
+
+	delete(s.data, hash)
+
+	s.order.Remove(e)
+
+	if s.policy != nil {
+		s.policy.Remove(hash)
+	}
+}
+
+// renew extends the expiration of the element. Caller must hold the lock.
+func (s *SafeExpiringSet[T]) renew(e *list.Element, now time.Time) {
+	//nolint:forcetypeassert
+	e.Value.(*entry[T]).expiresAt = now.Add(s.ttl)
+
+	s.order.MoveToBack(e)
+}
+
+// access notifies the eviction policy, if any, of a hit of the element.
+// Caller must hold the lock.
+func (s *SafeExpiringSet[T]) access(e *list.Element) {
+	if s.policy != nil {
+		//nolint:forcetypeassert
+		s.policy.Access(e.Value.(*entry[T]).hash)
+	}
+}
+
+// evict makes room for a new element, if the set is bounded, and full.
+// Caller must hold the lock.
+func (s *SafeExpiringSet[T]) evict() {
+	if s.capacity <= 0 || len(s.data) < s.capacity {
+		return
+	}
+
+	e := s.order.Front()
+
+	if s.policy != nil {
+		if hash, ok := s.policy.Victim(); ok && s.data[hash] != nil {
+			e = s.data[hash]
+		}
+	}
+
+	s.remove(e)
+
+	s.metrics.Operation("evict")
+	s.metrics.Evicted(shared.EvictCapacity)
+}
+
+// Metrics returns the metrics of the set, nil if not enabled.
+func (s *SafeExpiringSet[T]) Metrics() *metrics.Metrics {
+	return s.metrics
+}
+
+// String is the stringer implementation.
+func (s *SafeExpiringSet[T]) String() string {
+	values := s.Values()
+
+	items := make([]string, 0, len(values))
+
+	for _, value := range values {
+		items = append(items, fmt.Sprintf("%v", value))
+	}
+
+	return "[" + strings.Join(items, ", ") + "]"
+}
+
+// TTL returns the time to live of new, and renewed, elements.
+func (s *SafeExpiringSet[T]) TTL() time.Duration {
+	return s.ttl
+}
+
+//////
+// CRUD operations.
+
+// Add an element to the set. If already present, its expiration is renewed.
+func (s *SafeExpiringSet[T]) Add(value T) *SafeExpiringSet[T] {
+	s.Insert(value)
+
+	return s
+}
+
+// Insert adds an element to the set, returning whether it wasn't present. If
+// already present, its expiration is renewed. It's the building block of
+// deduplication: process the event only if Insert returns true.
+func (s *SafeExpiringSet[T]) Insert(value T) bool {
+	now := s.lock()
+	defer s.Unlock()
+
+	s.metrics.Operation("add")
+
+	hash := shared.GenerateHash(value)
+
+	if e, ok := s.data[hash]; ok {
+		s.renew(e, now)
+		s.access(e)
+
+		return false
+	}
+
+	s.evict()
+
+	s.data[hash] = s.order.PushBack(&entry[T]{
+		hash:      hash,
+		value:     value,
+		expiresAt: now.Add(s.ttl),
+	})
+
+	if s.policy != nil {
+		s.policy.Add(hash)
+	}
+
+	s.metrics.SetSize(len(s.data))
+
+	return true
+}
+
+// Remove an element from the set, returning whether it was present.
+func (s *SafeExpiringSet[T]) Remove(value T) bool {
+	s.lock()
+	defer s.Unlock()
+
+	s.metrics.Operation("delete")
+
+	e, ok := s.data[shared.GenerateHash(value)]
+	if ok {
+		s.remove(e)
+	}
+
+	s.metrics.SetSize(len(s.data))
+
+	return ok
+}
+
+// Clear removes all elements from the set.
+func (s *SafeExpiringSet[T]) Clear() *SafeExpiringSet[T] {
+	s.lock()
+	defer s.Unlock()
+
+	s.data = make(map[string]*list.Element)
+	s.order.Init()
+
+	if s.policy != nil {
+		s.policy.Reset()
+	}
+
+	s.metrics.Operation("clear")
+	s.metrics.SetSize(0)
+
+	return s
+}
+
+//////
+// Meta operations.
+
+// Contains checks if the set contains a given, non-expired, element. In
+// sliding mode, its expiration is renewed.
+func (s *SafeExpiringSet[T]) Contains(value T) bool {
+	now := s.lock()
+	defer s.Unlock()
+
+	s.metrics.Operation("contains")
+
+	e, ok := s.data[shared.GenerateHash(value)]
+
+	s.metrics.Lookup(ok)
+
+	if ok {
+		s.access(e)
+	}
+
+	if ok && s.sliding {
+		s.renew(e, now)
+	}
+
+	return ok
+}
+
+// ExpiresIn returns the time left before the element expires.
+func (s *SafeExpiringSet[T]) ExpiresIn(value T) (time.Duration, bool) {
+	now := s.lock()
+	defer s.Unlock()
+
+	e, ok := s.data[shared.GenerateHash(value)]
+	if !ok {
+		return 0, false
+	}
+
+	//nolint:forcetypeassert
+	return e.Value.(*entry[T]).expiresAt.Sub(now), true
+}
+
+// Digest returns a stable hash of the non-expired elements, regardless of
+// their order, by h, or sha256 if nil, e.g.: for cheap equality checks, see
+// shared.DigestUnordered.
+func (s *SafeExpiringSet[T]) Digest(h hash.Hash) (string, error) {
+	return shared.DigestUnordered(h, s.Values())
+}
+
+// Values returns all non-expired elements, from the closest to expire to the
+// farthest.
+func (s *SafeExpiringSet[T]) Values() []T {
+	s.lock()
+	defer s.Unlock()
+
+	values := make([]T, 0, len(s.data))
+
+	for e := s.order.Front(); e != nil; e = e.Next() {
+		//nolint:forcetypeassert
+		values = append(values, e.Value.(*entry[T]).value)
+	}
+
+	return values
+}
+
+// Size returns the number of non-expired elements.
+func (s *SafeExpiringSet[T]) Size() int {
+	s.lock()
+	defer s.Unlock()
+
+	return len(s.data)
+}
+
+// EstimateBytes estimates the memory used by the non-expired elements, in
+// bytes: per element, its list node, hash, and index slot, plus the bytes
+// referenced by the element, as given by the sizer, see WithSizer. The
+// estimate is recorded into the metrics, if enabled.
+func (s *SafeExpiringSet[T]) EstimateBytes() int64 {
+	s.lock()
+	defer s.Unlock()
+
+	var zero T
+
+	// Node, and entry, without the value, counted by EstimateSize, plus the
+	// hash, and node pointer of the index.
+	overhead := int64(unsafe.Sizeof(list.Element{})+unsafe.Sizeof(entry[T]{})-unsafe.Sizeof(zero)) +
+		int64(unsafe.Sizeof("")+unsafe.Sizeof(&list.Element{}))
+
+	size := int64(unsafe.Sizeof(*s)) + int64(unsafe.Sizeof(*s.order))
+
+	for e := s.order.Front(); e != nil; e = e.Next() {
+		//nolint:forcetypeassert
+		item := e.Value.(*entry[T])
+
+		size += overhead + int64(len(item.hash)) + shared.EstimateSize(item.value, s.sizer)
+	}
+
+	s.metrics.Operation("estimateBytes")
+	s.metrics.SetBytes(size)
+
+	return size
+}
+
+// Empty checks if there are no non-expired elements.
+func (s *SafeExpiringSet[T]) Empty() bool {
+	return s.Size() == 0
+}
+
+//////
+// Factory.
+//////
+
+// WithSliding enables sliding-window expiration: Contains renews the
+// expiration of the element, so elements only expire after a TTL without
+// being added, or checked.
+func WithSliding[T any]() Option[T] {
+	return func(s *SafeExpiringSet[T]) {
+		s.sliding = true
+	}
+}
+
+// WithCapacity bounds the set to the given number of elements. Adding to a
+// full set evicts the element chosen by the policy, e.g.: shared.NewLFU, or
+// the closest to expire if nil. Policies are notified of every element added,
+// renewed, and removed, by hash. Evictions are recorded into the metrics, by
+// reason. A capacity <= 0 means unbounded.
+func WithCapacity[T any](capacity int, policy shared.EvictionPolicy[string]) Option[T] {
+	return func(s *SafeExpiringSet[T]) {
+		s.capacity = capacity
+		s.policy = policy
+	}
+}
+
+// WithSizer sets the sizer of the elements, used by EstimateBytes to count the
+// bytes they reference. By default, only the contents of strings, and byte
+// slices are counted.
+func WithSizer[T any](sizer shared.Sizer[T]) Option[T] {
+	return func(s *SafeExpiringSet[T]) {
+		s.sizer = sizer
+	}
+}
+
+// WithMetrics enables metrics instrumentation, tracking operation counts,
+// size, lock wait times, and hit/miss ratios into the given metrics.
+func WithMetrics[T any](mtrcs *metrics.Metrics) Option[T] {
+	return func(s *SafeExpiringSet[T]) {
+		s.metrics = mtrcs
+	}
+}
+
+// New creates a new SafeExpiringSet whose elements expire after the given TTL.
+func New[T any](ttl time.Duration, opts ...Option[T]) *SafeExpiringSet[T] {
+	s := &SafeExpiringSet[T]{
+		ttl:   ttl,
+		data:  make(map[string]*list.Element),
+		order: list.New(),
+		now:   time.Now,
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
+}
@@ -0,0 +1,148 @@
+package safeexpiringset
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/thalesfsp/go-common-types/collection"
+	"github.com/thalesfsp/go-common-types/metrics"
+)
+
+var _ collection.Set[int] = (*SafeExpiringSet[int])(nil)
+
+// clock is a manually advanced clock.
+type clock struct {
+	now time.Time
+}
+
+func (c *clock) Now() time.Time { return c.now }
+
+func (c *clock) Advance(d time.Duration) { c.now = c.now.Add(d) }
+
+func newTestSet(ttl time.Duration, opts ...Option[string]) (*SafeExpiringSet[string], *clock) {
+	c := &clock{now: time.Unix(0, 0)}
+
+	s := New(ttl, opts...)
+	s.now = c.Now
+
+	return s, c
+}
+
+func TestSafeExpiringSetExpiration(t *testing.T) {
+	s, c := newTestSet(10 * time.Minute)
+
+	assert.True(t, s.Insert("a"))
+	c.Advance(5 * time.Minute)
+	assert.True(t, s.Insert("b"))
+	assert.False(t, s.Insert("b"))
+
+	assert.Equal(t, []string{"a", "b"}, s.Values())
+
+	left, ok := s.ExpiresIn("a")
+	assert.True(t, ok)
+	assert.Equal(t, 5*time.Minute, left)
+
+	c.Advance(5 * time.Minute)
+
+	assert.False(t, s.Contains("a"))
+	assert.True(t, s.Contains("b"))
+	assert.Equal(t, 1, s.Size())
+
+	c.Advance(5 * time.Minute)
+
+	assert.True(t, s.Empty())
+	assert.True(t, s.Insert("a"))
+}
+
+func TestSafeExpiringSetAddRenews(t *testing.T) {
+	s, c := newTestSet(time.Minute)
+
+	s.Add("a").Add("b")
+	c.Advance(30 * time.Second)
+	s.Add("a")
+
+	assert.Equal(t, []string{"b", "a"}, s.Values())
+
+	c.Advance(30 * time.Second)
+
+	assert.Equal(t, []string{"a"}, s.Values())
+}
+
+func TestSafeExpiringSetSliding(t *testing.T) {
+	s, c := newTestSet(time.Minute, WithSliding[string]())
+	s.Add("a")
+
+	for i := 0; i < 5; i++ {
+		c.Advance(50 * time.Second)
+		assert.True(t, s.Contains("a"))
+	}
+
+	c.Advance(time.Minute)
+	assert.False(t, s.Contains("a"))
+
+	f, c := newTestSet(time.Minute)
+	f.Add("a")
+
+	c.Advance(50 * time.Second)
+	assert.True(t, f.Contains("a"))
+	c.Advance(50 * time.Second)
+	assert.False(t, f.Contains("a"))
+}
+
+func TestSafeExpiringSetRemoveClear(t *testing.T) {
+	s, _ := newTestSet(time.Minute, WithMetrics[string](metrics.New("dedup")))
+	s.Add("a").Add("b").Add("c")
+
+	assert.True(t, s.Remove("b"))
+	assert.False(t, s.Remove("b"))
+	assert.Equal(t, "[a, c]", s.String())
+
+	s.Clear()
+
+	assert.True(t, s.Empty())
+	assert.Equal(t, uint64(3), s.Metrics().Snapshot().Operations["add"])
+	assert.Equal(t, time.Minute, s.TTL())
+}
+
+func TestEstimateBytes(t *testing.T) {
+	m := metrics.New("test")
+
+	s, c := newTestSet(time.Second, WithMetrics[string](m))
+	empty := s.EstimateBytes()
+
+	s.Add("abc")
+
+	one := s.EstimateBytes()
+
+	assert.Greater(t, one, empty+16+3)
+	assert.Equal(t, one, m.Snapshot().Bytes)
+
+	// Expired elements aren't counted.
+	c.Advance(time.Second)
+
+	assert.Equal(t, empty, s.EstimateBytes())
+}
+
+func TestDigest(t *testing.T) {
+	a := New[string](time.Minute).Add("a").Add("b")
+	b := New[string](time.Minute).Add("b").Add("a")
+
+	da, err := a.Digest(nil)
+	assert.NoError(t, err)
+
+	db, err := b.Digest(nil)
+	assert.NoError(t, err)
+	assert.Equal(t, da, db)
+}
+
+func TestSafeExpiringSetZeroValue(t *testing.T) {
+	var s SafeExpiringSet[int]
+
+	// Without a TTL, elements expire immediately.
+	s.Add(1)
+
+	assert.False(t, s.Contains(1))
+	assert.Equal(t, 0, s.Size())
+	assert.Empty(t, s.Values())
+}
@@ -0,0 +1,349 @@
+package safelimiter
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/thalesfsp/go-common-types/metrics"
+	"github.com/thalesfsp/go-common-types/safemap"
+)
+
+//////
+// Const, vars, and types.
+//////
+
+// ErrExceedsBurst is returned by Wait when the request can never be allowed:
+// the burst is lower than 1, and the bucket doesn't refill.
+var ErrExceedsBurst = errors.New("request exceeds the burst of the limit")
+
+// Option allows to configure a Limiter.
+type Option[K comparable] func(l *Limiter[K])
+
+// Limit is the configuration of a token bucket: it holds up to Burst tokens,
+// refilled at Rate tokens per second. Each allowed event takes a token.
+type Limit struct {
+	// Rate is the number of tokens added per second. If not positive, the
+	// bucket never refills.
+	Rate float64 `json:"rate"`
+
+	// Burst is the maximum number of tokens, i.e.: events allowed at once.
+	Burst int `json:"burst"`
+}
+
+// bucket is the token bucket of a key.
+type bucket struct {
+	sync.Mutex
+
+	limit Limit
+
+	tokens float64
+	last   time.Time
+
+	// dead is set when the bucket is removed from the limiter, callers
+	// holding it must look the key up again.
+	dead bool
+}
+
+// Limiter is a keyed rate limiter, safe for concurrent use, with a token
+// bucket per key, e.g.: per user, or per IP, stored in a safemap.Map, so
+// different keys rarely contend.
+type Limiter[K comparable] struct {
+	// lastSweep is first, to be 64-bit aligned for atomic operations.
+	lastSweep int64
+
+	buckets *safemap.Map[K, *bucket]
+
+	limit    Limit
+	limitFor func(key K) Limit
+
+	idle time.Duration
+
+	metrics *metrics.Metrics
+
+	now func() time.Time
+}
+
+//////
+// Bucket.
+//////
+
+// refill adds the tokens accumulated since the last refill. Caller must hold
+// the lock.
+func (b *bucket) refill(now time.Time) {
+	if elapsed := now.Sub(b.last); elapsed > 0 && b.limit.Rate > 0 {
+		b.tokens += elapsed.Seconds() * b.limit.Rate
+	}
+
+	if burst := float64(b.limit.Burst); b.tokens > burst {
+		b.tokens = burst
+	}
+
+	b.last = now
+}
+
+//////
+// Methods.
+//////
+
+// bucket returns the locked bucket of the key, creating it, full, if
+// missing.
+func (l *Limiter[K]) bucket(key K, now time.Time) *bucket {
+	l.maybeSweep(now)
+
+	for {
+		b, _ := l.buckets.GetOrAddFunc(key, func() *bucket {
+			limit := l.limit
+
+			if l.limitFor != nil {
+				limit = l.limitFor(key)
+			}
+
+			return &bucket{limit: limit, tokens: float64(limit.Burst), last: now}
+		})
+
+		b.Lock()
+
+		if !b.dead {
+			b.refill(now)
+
+			return b
+		}
+
+		b.Unlock()
+	}
+}
+
+// maybeSweep removes the idle buckets, if idle collection is enabled, and the
+// last sweep is older than the idle timeout. Only one caller sweeps at a time.
+func (l *Limiter[K]) maybeSweep(now time.Time) {
+	if l.idle <= 0 {
+		return
+	}
+
+	last := atomic.LoadInt64(&l.lastSweep)
+
+	if now.Sub(time.Unix(0, last)) < l.idle || !atomic.CompareAndSwapInt64(&l.lastSweep, last, now.UnixNano()) {
+		return
+	}
+
+	l.sweep(now, l.idle)
+}
+
+// sweep removes the buckets unused for at least idle, and full, so removing
+// them doesn't change what's allowed. It returns how many were removed.
+func (l *Limiter[K]) sweep(now time.Time, idle time.Duration) int {
+	removed := 0
+
+	l.buckets.Range(func(key K, b *bucket) bool {
+		b.Lock()
+		defer b.Unlock()
+
+		if b.dead || now.Sub(b.last) < idle {
+			return true
+		}
+
+		b.refill(now)
+
+		if b.tokens < float64(b.limit.Burst) {
+			return true
+		}
+
+		// Deleted holding the lock of the bucket, so callers which got it
+		// before the deletion retry right after.
+		b.dead = true
+
+		l.buckets.Delete(key)
+
+		removed++
+
+		return true
+	})
+
+	l.metrics.SetSize(l.buckets.Len())
+
+	return removed
+}
+
+// Allow reports whether an event for the key may happen now, taking a token
+// if so.
+func (l *Limiter[K]) Allow(key K) bool {
+	return l.AllowN(key, 1)
+}
+
+// AllowN reports whether n events for the key may happen now, taking n tokens
+// if so. Tokens are taken all or nothing.
+func (l *Limiter[K]) AllowN(key K, n int) bool {
+	b := l.bucket(key, l.now())
+	defer b.Unlock()
+
+	l.metrics.Operation("allow")
+
+	allowed := b.tokens >= float64(n)
+	if allowed {
+		b.tokens -= float64(n)
+	}
+
+	l.metrics.Lookup(allowed)
+
+	return allowed
+}
+
+// Wait blocks until an event for the key may happen, taking a token, or
+// until the context is done, returning its error. The token is reserved
+// upfront, so waiters are served in order, and given back if the context is
+// done first. If the context has a deadline too early for the token, Wait
+// returns context.DeadlineExceeded right away. If the token can never be
+// available, it returns ErrExceedsBurst.
+func (l *Limiter[K]) Wait(ctx context.Context, key K) error {
+	now := l.now()
+	b := l.bucket(key, now)
+
+	l.metrics.Operation("wait")
+
+	if b.tokens >= 1 {
+		b.tokens--
+
+		b.Unlock()
+
+		return nil
+	}
+
+	if b.limit.Rate <= 0 {
+		b.Unlock()
+
+		return ErrExceedsBurst
+	}
+
+	delay := time.Duration((1 - b.tokens) / b.limit.Rate * float64(time.Second))
+
+	if deadline, ok := ctx.Deadline(); ok && deadline.Before(now.Add(delay)) {
+		b.Unlock()
+
+		return context.DeadlineExceeded
+	}
+
+	b.tokens--
+
+	b.Unlock()
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		b.Lock()
+
+		if !b.dead {
+			b.tokens++
+		}
+
+		b.Unlock()
+
+		return ctx.Err()
+	}
+}
+
+// Tokens returns the number of tokens available for the key, the burst of its
+// limit if it isn't tracked. It's negative while there are waiters.
+func (l *Limiter[K]) Tokens(key K) float64 {
+	b := l.bucket(key, l.now())
+	defer b.Unlock()
+
+	return b.tokens
+}
+
+// SetLimit changes the limit of the key, keeping its tokens, up to the new
+// burst.
+func (l *Limiter[K]) SetLimit(key K, limit Limit) {
+	b := l.bucket(key, l.now())
+	defer b.Unlock()
+
+	b.limit = limit
+
+	b.refill(b.last)
+}
+
+// Reset forgets the key, so its bucket is full again.
+func (l *Limiter[K]) Reset(key K) {
+	b, ok := l.buckets.Get(key)
+	if !ok {
+		return
+	}
+
+	b.Lock()
+	defer b.Unlock()
+
+	if !b.dead {
+		b.dead = true
+
+		l.buckets.Delete(key)
+	}
+}
+
+// Collect removes the buckets unused for at least idle, which are full, so
+// removing them doesn't change what's allowed, returning how many were
+// removed. With WithIdleTimeout, it's called automatically.
+func (l *Limiter[K]) Collect(idle time.Duration) int {
+	return l.sweep(l.now(), idle)
+}
+
+// Size returns the number of tracked keys.
+func (l *Limiter[K]) Size() int {
+	return l.buckets.Len()
+}
+
+// Metrics returns the metrics of the limiter, nil if not enabled.
+func (l *Limiter[K]) Metrics() *metrics.Metrics {
+	return l.metrics
+}
+
+//////
+// Factory.
+//////
+
+// WithKeyLimit sets the function returning the limit of a key, called when
+// the key is first seen, or after it was collected, e.g.: to give premium
+// users a higher rate. By default, all keys have the limit given to New.
+func WithKeyLimit[K comparable](limitFor func(key K) Limit) Option[K] {
+	return func(l *Limiter[K]) {
+		l.limitFor = limitFor
+	}
+}
+
+// WithIdleTimeout enables the garbage collection of idle keys: at most once
+// per timeout, the call which notices it removes the buckets unused for the
+// timeout, and full, see Collect. There are no goroutines, nor timers.
+func WithIdleTimeout[K comparable](timeout time.Duration) Option[K] {
+	return func(l *Limiter[K]) {
+		l.idle = timeout
+	}
+}
+
+// WithMetrics enables metrics instrumentation, tracking allow, and wait
+// calls, allowed (hits), and denied (misses) events, and the number of
+// tracked keys.
+func WithMetrics[K comparable](mtrcs *metrics.Metrics) Option[K] {
+	return func(l *Limiter[K]) {
+		l.metrics = mtrcs
+	}
+}
+
+// New creates a new Limiter, with the given limit per key.
+func New[K comparable](limit Limit, opts ...Option[K]) *Limiter[K] {
+	l := &Limiter[K]{
+		buckets: safemap.New[K, *bucket](),
+		limit:   limit,
+		now:     time.Now,
+	}
+
+	for _, opt := range opts {
+		opt(l)
+	}
+
+	return l
+}
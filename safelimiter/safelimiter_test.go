@@ -0,0 +1,184 @@
+package safelimiter
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// clock is a manually advanced clock, safe for concurrent use.
+type clock struct {
+	sync.Mutex
+
+	now time.Time
+}
+
+func (c *clock) Now() time.Time {
+	c.Lock()
+	defer c.Unlock()
+
+	return c.now
+}
+
+func (c *clock) Advance(d time.Duration) {
+	c.Lock()
+	defer c.Unlock()
+
+	c.now = c.now.Add(d)
+}
+
+func newTestLimiter(limit Limit, opts ...Option[string]) (*Limiter[string], *clock) {
+	c := &clock{now: time.Unix(1_000, 0)}
+
+	l := New(limit, opts...)
+	l.now = c.Now
+
+	return l, c
+}
+
+func TestAllow(t *testing.T) {
+	l, c := newTestLimiter(Limit{Rate: 1, Burst: 2})
+
+	assert.True(t, l.Allow("a"))
+	assert.True(t, l.Allow("a"))
+	assert.False(t, l.Allow("a"))
+
+	// Keys have their own bucket.
+	assert.True(t, l.Allow("b"))
+
+	c.Advance(500 * time.Millisecond)
+
+	assert.False(t, l.Allow("a"))
+
+	c.Advance(500 * time.Millisecond)
+
+	assert.True(t, l.Allow("a"))
+
+	// Tokens don't accumulate over the burst.
+	c.Advance(time.Hour)
+
+	assert.False(t, l.AllowN("a", 3))
+	assert.True(t, l.AllowN("a", 2))
+	assert.Equal(t, 0.0, l.Tokens("a"))
+}
+
+func TestKeyLimit(t *testing.T) {
+	l, _ := newTestLimiter(Limit{Rate: 1, Burst: 1}, WithKeyLimit(func(key string) Limit {
+		if key == "premium" {
+			return Limit{Rate: 10, Burst: 10}
+		}
+
+		return Limit{Rate: 1, Burst: 1}
+	}))
+
+	assert.True(t, l.AllowN("premium", 10))
+	assert.False(t, l.AllowN("free", 2))
+
+	l.SetLimit("free", Limit{Rate: 1, Burst: 5})
+
+	assert.Equal(t, 1.0, l.Tokens("free"))
+
+	l.Reset("free")
+
+	assert.Equal(t, 1.0, l.Tokens("free"))
+}
+
+func TestIdleCollection(t *testing.T) {
+	l, c := newTestLimiter(Limit{Rate: 1, Burst: 1}, WithIdleTimeout[string](time.Minute))
+
+	l.Allow("a")
+	l.Allow("b")
+
+	c.Advance(30 * time.Second)
+
+	l.Allow("b")
+
+	assert.Equal(t, 2, l.Size())
+
+	c.Advance(31 * time.Second)
+
+	// The sweep is triggered by any call: "a" is idle, "b" isn't.
+	l.Allow("c")
+
+	assert.Equal(t, 2, l.Size())
+	assert.Equal(t, 1, l.Collect(0))
+
+	// A bucket which isn't full isn't collected.
+	l.Allow("c")
+
+	assert.Equal(t, 0, l.Collect(0))
+}
+
+func TestWait(t *testing.T) {
+	l := New[string](Limit{Rate: 100, Burst: 1})
+
+	start := time.Now()
+
+	assert.NoError(t, l.Wait(context.Background(), "a"))
+	assert.NoError(t, l.Wait(context.Background(), "a"))
+
+	assert.GreaterOrEqual(t, time.Since(start), 9*time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+
+	l.Allow("b")
+
+	assert.ErrorIs(t, l.Wait(ctx, "b"), context.DeadlineExceeded)
+
+	never := New[string](Limit{Burst: 0})
+
+	assert.ErrorIs(t, never.Wait(context.Background(), "a"), ErrExceedsBurst)
+}
+
+func TestWaitCanceled(t *testing.T) {
+	l := New[string](Limit{Rate: 1, Burst: 1})
+
+	l.Allow("a")
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	done := make(chan error)
+
+	go func() { done <- l.Wait(ctx, "a") }()
+
+	time.Sleep(10 * time.Millisecond)
+	cancel()
+
+	assert.ErrorIs(t, <-done, context.Canceled)
+
+	// The reserved token was given back.
+	assert.Greater(t, l.Tokens("a"), -0.5)
+}
+
+func TestConcurrentAllow(t *testing.T) {
+	l, _ := newTestLimiter(Limit{Burst: 100}, WithIdleTimeout[string](time.Nanosecond))
+
+	var allowed int64
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < 16; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for j := 0; j < 50; j++ {
+				if l.Allow("a") {
+					atomic.AddInt64(&allowed, 1)
+				}
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	// The bucket never refills, so exactly the burst is allowed, even while
+	// collecting.
+	assert.Equal(t, int64(100), allowed)
+}
@@ -0,0 +1,294 @@
+package safelog
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/thalesfsp/go-common-types/internal/watch"
+	"github.com/thalesfsp/go-common-types/metrics"
+)
+
+//////
+// Const, vars, and types.
+//////
+
+var (
+	// ErrTruncated is returned when reading from an offset which has been
+	// truncated.
+	ErrTruncated = errors.New("offset truncated")
+
+	// ErrOutOfRange is returned when reading from an offset which hasn't been
+	// appended yet.
+	ErrOutOfRange = errors.New("offset out of range")
+)
+
+// Option allows to configure a SafeLog.
+type Option[T any] func(l *SafeLog[T])
+
+// Record is a value of the log, with its offset.
+type Record[T any] struct {
+	Offset uint64 `json:"offset"`
+	Value  T      `json:"value"`
+}
+
+// SafeLog is an append-only log, safe for concurrent use, powered by
+// generics. Every appended value gets the next offset, starting at 0, which
+// never changes, nor is reused: values can't be deleted, or reordered, only
+// the oldest ones truncated, see TruncateBefore. It's suitable as an
+// in-memory event log, read by offset, see ReadFrom, Watch, and Consume.
+//
+// The zero value is an empty log, ready to use.
+type SafeLog[T any] struct {
+	sync.RWMutex
+
+	// start is the offset of the first value retained.
+	start  uint64
+	values []T
+
+	// appended is closed, and replaced, on every append, waking up the
+	// consumers. It's created by the first consumer, for the zero value.
+	appended chan struct{}
+
+	watchers watch.Hub[Record[T]]
+
+	metrics *metrics.Metrics
+}
+
+//////
+// Methods.
+//////
+
+// Append appends the values, returning the offset of the first one. The
+// following ones have the next offsets.
+func (l *SafeLog[T]) Append(values ...T) uint64 {
+	l.Lock()
+	defer l.Unlock()
+
+	offset := l.start + uint64(len(l.values))
+
+	if len(values) == 0 {
+		return offset
+	}
+
+	l.values = append(l.values, values...)
+
+	for i, v := range values {
+		l.watchers.Publish(Record[T]{Offset: offset + uint64(i), Value: v})
+	}
+
+	if l.appended != nil {
+		close(l.appended)
+	}
+
+	l.appended = make(chan struct{})
+
+	l.metrics.Operation("append")
+	l.metrics.SetSize(len(l.values))
+
+	return offset
+}
+
+// First returns the offset of the oldest value retained, which equals Next if
+// the log is empty.
+func (l *SafeLog[T]) First() uint64 {
+	l.RLock()
+	defer l.RUnlock()
+
+	return l.start
+}
+
+// Next returns the offset the next appended value will get.
+func (l *SafeLog[T]) Next() uint64 {
+	l.RLock()
+	defer l.RUnlock()
+
+	return l.start + uint64(len(l.values))
+}
+
+// Size returns the number of values retained.
+func (l *SafeLog[T]) Size() int {
+	l.RLock()
+	defer l.RUnlock()
+
+	return len(l.values)
+}
+
+// Get returns the value at the offset, false if truncated, or not appended
+// yet.
+func (l *SafeLog[T]) Get(offset uint64) (T, bool) {
+	l.RLock()
+	defer l.RUnlock()
+
+	if offset < l.start || offset-l.start >= uint64(len(l.values)) {
+		var zero T
+
+		return zero, false
+	}
+
+	return l.values[offset-l.start], true
+}
+
+// ReadFrom returns up to max records, all if max isn't positive, starting at
+// the offset. Reading from Next returns no records. It fails with
+// ErrTruncated if the offset has been truncated, and ErrOutOfRange if it's
+// after Next.
+func (l *SafeLog[T]) ReadFrom(offset uint64, max int) ([]Record[T], error) {
+	l.RLock()
+	defer l.RUnlock()
+
+	return l.read(offset, max)
+}
+
+// read is ReadFrom. Caller must hold the lock.
+func (l *SafeLog[T]) read(offset uint64, max int) ([]Record[T], error) {
+	next := l.start + uint64(len(l.values))
+
+	switch {
+	case offset < l.start:
+		return nil, fmt.Errorf("%w: %d, the first offset is %d", ErrTruncated, offset, l.start)
+	case offset > next:
+		return nil, fmt.Errorf("%w: %d, the next offset is %d", ErrOutOfRange, offset, next)
+	}
+
+	values := l.values[offset-l.start:]
+	if max > 0 && len(values) > max {
+		values = values[:max]
+	}
+
+	records := make([]Record[T], len(values))
+
+	for i, v := range values {
+		records[i] = Record[T]{Offset: offset + uint64(i), Value: v}
+	}
+
+	l.metrics.Operation("read")
+
+	return records, nil
+}
+
+// TruncateBefore removes the values before the offset, returning how many.
+// Offsets aren't affected: the remaining values keep theirs. Truncating after
+// Next removes all values.
+func (l *SafeLog[T]) TruncateBefore(offset uint64) int {
+	l.Lock()
+	defer l.Unlock()
+
+	if offset <= l.start {
+		return 0
+	}
+
+	n := len(l.values)
+	if d := offset - l.start; d < uint64(n) {
+		n = int(d)
+	}
+
+	// Copies the remaining values, so the truncated ones can be collected.
+	l.values = append([]T(nil), l.values[n:]...)
+	l.start += uint64(n)
+
+	l.metrics.Operation("truncate")
+	l.metrics.SetSize(len(l.values))
+
+	return n
+}
+
+// Watch returns a channel receiving, in order, every record appended after
+// the call, until the context is done, then the channel is closed.
+//
+// Appends never block on watchers: records are queued until received, so a
+// watcher which stops receiving should cancel its context.
+func (l *SafeLog[T]) Watch(ctx context.Context) <-chan Record[T] {
+	return l.watchers.Subscribe(ctx)
+}
+
+// Consume calls fn with every record, in order, starting at the offset,
+// including the ones appended later, waiting for them, until the context is
+// done, returning its error, or fn fails, returning its error. The records
+// are read in batches, without holding the lock while calling fn.
+//
+// Unlike Watch, a consumer can resume from the offset following the last
+// record it processed. It fails with ErrTruncated if the values it has to
+// process are truncated before it reads them.
+func (l *SafeLog[T]) Consume(ctx context.Context, offset uint64, fn func(Record[T]) error) error {
+	for {
+		l.RLock()
+
+		records, err := l.read(offset, 0)
+		appended := l.appended
+
+		l.RUnlock()
+
+		if err != nil {
+			return err
+		}
+
+		// The zero value has no channel yet: it's created, and the records
+		// read again with it, so an append in between isn't missed.
+		if appended == nil {
+			l.Lock()
+
+			if l.appended == nil {
+				l.appended = make(chan struct{})
+			}
+
+			l.Unlock()
+
+			continue
+		}
+
+		for _, r := range records {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+
+			if err := fn(r); err != nil {
+				return err
+			}
+
+			offset = r.Offset + 1
+		}
+
+		if len(records) > 0 {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-appended:
+		}
+	}
+}
+
+//////
+// Factory.
+//////
+
+// WithMetrics sets the metrics of the log.
+func WithMetrics[T any](mtrcs *metrics.Metrics) Option[T] {
+	return func(l *SafeLog[T]) {
+		l.metrics = mtrcs
+	}
+}
+
+// New creates a SafeLog, with the values appended, from offset 0.
+func New[T any](values ...T) *SafeLog[T] {
+	l := NewWithOptions[T]()
+
+	l.Append(values...)
+
+	return l
+}
+
+// NewWithOptions creates an empty SafeLog, with options.
+func NewWithOptions[T any](opts ...Option[T]) *SafeLog[T] {
+	l := &SafeLog[T]{appended: make(chan struct{})}
+
+	for _, opt := range opts {
+		opt(l)
+	}
+
+	return l
+}
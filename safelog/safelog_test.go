@@ -0,0 +1,207 @@
+package safelog
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/thalesfsp/go-common-types/metrics"
+)
+
+func TestSafeLogAppend(t *testing.T) {
+	l := New("a", "b")
+
+	assert.Equal(t, uint64(2), l.Append("c", "d"))
+	assert.Equal(t, uint64(4), l.Append())
+	assert.Equal(t, uint64(0), l.First())
+	assert.Equal(t, uint64(4), l.Next())
+	assert.Equal(t, 4, l.Size())
+
+	v, ok := l.Get(2)
+	assert.True(t, ok)
+	assert.Equal(t, "c", v)
+
+	_, ok = l.Get(4)
+	assert.False(t, ok)
+}
+
+func TestSafeLogReadFrom(t *testing.T) {
+	l := New(10, 20, 30, 40)
+
+	records, err := l.ReadFrom(1, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, []Record[int]{{Offset: 1, Value: 20}, {Offset: 2, Value: 30}}, records)
+
+	records, err = l.ReadFrom(2, 0)
+	assert.NoError(t, err)
+	assert.Len(t, records, 2)
+
+	records, err = l.ReadFrom(4, 10)
+	assert.NoError(t, err)
+	assert.Empty(t, records)
+
+	_, err = l.ReadFrom(5, 10)
+	assert.ErrorIs(t, err, ErrOutOfRange)
+}
+
+func TestSafeLogTruncateBefore(t *testing.T) {
+	l := New(10, 20, 30, 40)
+
+	assert.Equal(t, 2, l.TruncateBefore(2))
+	assert.Equal(t, 0, l.TruncateBefore(1))
+	assert.Equal(t, uint64(2), l.First())
+	assert.Equal(t, 2, l.Size())
+
+	_, err := l.ReadFrom(1, 0)
+	assert.ErrorIs(t, err, ErrTruncated)
+
+	_, ok := l.Get(1)
+	assert.False(t, ok)
+
+	// Offsets are never reused.
+	assert.Equal(t, uint64(4), l.Append(50))
+
+	records, err := l.ReadFrom(2, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, []Record[int]{{Offset: 2, Value: 30}, {Offset: 3, Value: 40}, {Offset: 4, Value: 50}}, records)
+
+	assert.Equal(t, 3, l.TruncateBefore(100))
+	assert.Equal(t, uint64(5), l.First())
+	assert.Equal(t, uint64(5), l.Next())
+}
+
+func TestSafeLogWatch(t *testing.T) {
+	l := New(1)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	records := l.Watch(ctx)
+
+	l.Append(2, 3)
+
+	assert.Equal(t, Record[int]{Offset: 1, Value: 2}, <-records)
+	assert.Equal(t, Record[int]{Offset: 2, Value: 3}, <-records)
+}
+
+func TestSafeLogConsume(t *testing.T) {
+	l := New(1, 2)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var (
+		mu   sync.Mutex
+		seen []Record[int]
+	)
+
+	done := make(chan error)
+
+	go func() {
+		done <- l.Consume(ctx, 1, func(r Record[int]) error {
+			mu.Lock()
+			defer mu.Unlock()
+
+			seen = append(seen, r)
+
+			return nil
+		})
+	}()
+
+	l.Append(3)
+
+	assert.Eventually(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+
+		return len(seen) == 2
+	}, time.Second, time.Millisecond)
+
+	cancel()
+
+	assert.ErrorIs(t, <-done, context.Canceled)
+	assert.Equal(t, []Record[int]{{Offset: 1, Value: 2}, {Offset: 2, Value: 3}}, seen)
+}
+
+func TestSafeLogConsumeErrors(t *testing.T) {
+	l := New(1, 2, 3)
+
+	errStop := errors.New("stop")
+
+	err := l.Consume(context.Background(), 0, func(r Record[int]) error {
+		if r.Offset == 1 {
+			return errStop
+		}
+
+		return nil
+	})
+	assert.ErrorIs(t, err, errStop)
+
+	l.TruncateBefore(2)
+
+	err = l.Consume(context.Background(), 0, func(r Record[int]) error { return nil })
+	assert.ErrorIs(t, err, ErrTruncated)
+}
+
+func TestSafeLogMetrics(t *testing.T) {
+	m := metrics.New("test")
+
+	l := NewWithOptions(WithMetrics[int](m))
+
+	l.Append(1, 2, 3)
+	l.TruncateBefore(1)
+
+	assert.Equal(t, int64(2), m.Snapshot().Size)
+}
+
+func TestSafeLogConcurrent(t *testing.T) {
+	l := New[int]()
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for j := 0; j < 100; j++ {
+				l.Append(j)
+
+				_, _ = l.ReadFrom(l.First(), 10)
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	assert.Equal(t, uint64(1000), l.Next())
+}
+
+func TestSafeLogZeroValue(t *testing.T) {
+	var l SafeLog[string]
+
+	assert.Equal(t, uint64(0), l.Append("a"))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var zero SafeLog[int]
+
+	got := make(chan Record[int], 1)
+
+	go func() {
+		_ = zero.Consume(ctx, 0, func(r Record[int]) error {
+			got <- r
+
+			return nil
+		})
+	}()
+
+	zero.Append(1)
+
+	assert.Equal(t, Record[int]{Offset: 0, Value: 1}, <-got)
+}
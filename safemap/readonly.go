@@ -0,0 +1,98 @@
+package safemap
+
+//////
+// Const, vars, and types.
+//////
+
+// ReadOnly is a read-only view of a Map, see Map.ReadOnly.
+type ReadOnly[K comparable, V any] interface {
+	// Get a value.
+	Get(key K) (V, bool)
+
+	// Contains checks if the key is present.
+	Contains(key K) bool
+
+	// Keys returns all keys, in no particular order.
+	Keys() []K
+
+	// Values returns all values, in no particular order.
+	Values() []V
+
+	// Len returns the number of entries.
+	Len() int
+
+	// Size returns the number of entries.
+	Size() int
+
+	// Empty checks if there are no entries.
+	Empty() bool
+
+	// Range calls f for each entry, until it returns false.
+	Range(f func(key K, value V) bool)
+
+	// ToMap returns a copy of the entries as a Go map.
+	ToMap() map[K]V
+
+	// Clone returns a copy of the map, which can be modified.
+	Clone() *Map[K, V]
+
+	// String returns the string representation of the map.
+	String() string
+
+	// MarshalJSON marshals the map to JSON.
+	MarshalJSON() ([]byte, error)
+}
+
+// readOnly implements ReadOnly. The map isn't exported, so the view can't be
+// converted back.
+type readOnly[K comparable, V any] struct {
+	m *Map[K, V]
+}
+
+//////
+// Methods.
+//////
+
+// Get implements ReadOnly.
+func (v readOnly[K, V]) Get(key K) (V, bool) { return v.m.Get(key) }
+
+// Contains implements ReadOnly.
+func (v readOnly[K, V]) Contains(key K) bool { return v.m.Contains(key) }
+
+// Keys implements ReadOnly.
+func (v readOnly[K, V]) Keys() []K { return v.m.Keys() }
+
+// Values implements ReadOnly.
+func (v readOnly[K, V]) Values() []V { return v.m.Values() }
+
+// Len implements ReadOnly.
+func (v readOnly[K, V]) Len() int { return v.m.Len() }
+
+// Size implements ReadOnly.
+func (v readOnly[K, V]) Size() int { return v.m.Size() }
+
+// Empty implements ReadOnly.
+func (v readOnly[K, V]) Empty() bool { return v.m.Empty() }
+
+// Range implements ReadOnly.
+func (v readOnly[K, V]) Range(f func(key K, value V) bool) { v.m.Range(f) }
+
+// ToMap implements ReadOnly.
+func (v readOnly[K, V]) ToMap() map[K]V { return v.m.ToMap() }
+
+// Clone implements ReadOnly.
+func (v readOnly[K, V]) Clone() *Map[K, V] { return v.m.Clone() }
+
+// String implements ReadOnly.
+func (v readOnly[K, V]) String() string { return v.m.String() }
+
+// MarshalJSON implements ReadOnly.
+func (v readOnly[K, V]) MarshalJSON() ([]byte, error) { return v.m.MarshalJSON() }
+
+// ReadOnly returns a read-only view of the map, e.g.: to hand it out without
+// copying it. The view isn't a snapshot: it reflects later changes to the
+// map. It can't be converted back to the map, use Clone to get a copy which
+// can be modified.
+func (m *Map[K, V]) ReadOnly() ReadOnly[K, V] {
+	return readOnly[K, V]{m: m}
+}
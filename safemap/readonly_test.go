@@ -0,0 +1,42 @@
+package safemap
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadOnly(t *testing.T) {
+	m := New[string, int]()
+	m.Set("a", 1)
+
+	view := m.ReadOnly()
+
+	m.Set("b", 2)
+
+	// The view reflects later changes.
+	assert.Equal(t, 2, view.Size())
+	assert.Equal(t, map[string]int{"a": 1, "b": 2}, view.ToMap())
+
+	sum := 0
+
+	view.Range(func(_ string, value int) bool {
+		sum += value
+
+		return true
+	})
+
+	assert.Equal(t, 3, sum)
+
+	// The view can't be converted back.
+	_, ok := any(view).(*Map[string, int])
+	assert.False(t, ok)
+
+	_, ok = any(view).(interface{ Set(string, int) })
+	assert.False(t, ok)
+
+	// A clone can be modified, without affecting the map.
+	view.Clone().Set("c", 3)
+
+	assert.Equal(t, 2, m.Size())
+}
@@ -0,0 +1,650 @@
+package safemap
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"hash"
+	"hash/maphash"
+	"io"
+	"math"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/thalesfsp/go-common-types/metrics"
+	"github.com/thalesfsp/go-common-types/shared"
+)
+
+//////
+// Const, vars, and types.
+//////
+
+// DefaultShards is the number of shards when the given one isn't positive.
+const DefaultShards = 32
+
+// Option allows to configure a Map.
+type Option[K comparable, V any] func(m *Map[K, V])
+
+// shard is a lock-protected part of the map.
+type shard[K comparable, V any] struct {
+	sync.RWMutex
+
+	data map[K]V
+}
+
+// Map is an unordered map, safe for concurrent use, powered by generics. Keys
+// are spread across shards, each one with its own lock, so operations on
+// different keys rarely contend. Use it instead of SafeOrderedMap when the
+// order of the keys doesn't matter.
+//
+// The zero value is an empty map, with DefaultShards shards, ready to use,
+// e.g.: as a struct field filled by UnmarshalJSON.
+type Map[K comparable, V any] struct {
+	// size is first, to be 64-bit aligned for atomic operations.
+	size int64
+
+	// shards, and seed, are created on first use, by table, for the zero
+	// value.
+	shards []*shard[K, V]
+	seed   maphash.Seed
+	once   sync.Once
+
+	metrics *metrics.Metrics
+
+	keySizer   shared.Sizer[K]
+	valueSizer shared.Sizer[V]
+}
+
+//////
+// Methods.
+//////
+
+// table returns the shards, creating them on first use, for the zero value.
+func (m *Map[K, V]) table() []*shard[K, V] {
+	m.once.Do(func() {
+		if m.shards == nil {
+			m.shards = newShards[K, V](DefaultShards)
+			m.seed = maphash.MakeSeed()
+		}
+	})
+
+	return m.shards
+}
+
+// shard returns the shard of the key.
+func (m *Map[K, V]) shard(key K) *shard[K, V] {
+	shards := m.table()
+
+	return shards[m.hash(key)%uint64(len(shards))]
+}
+
+// hash returns the hash of the key. Common key types are hashed directly,
+// others through their %v representation, which is equal for equal keys.
+func (m *Map[K, V]) hash(key K) uint64 {
+	switch k := any(key).(type) {
+	case string:
+		return maphash.String(m.seed, k)
+	case int:
+		return mix(uint64(k))
+	case int64:
+		return mix(uint64(k))
+	case int32:
+		return mix(uint64(k))
+	case uint:
+		return mix(uint64(k))
+	case uint64:
+		return mix(k)
+	case uint32:
+		return mix(uint64(k))
+	case float64:
+		// 0, and -0 are equal keys.
+		if k == 0 {
+			return 0
+		}
+
+		return mix(math.Float64bits(k))
+	default:
+		return maphash.String(m.seed, fmt.Sprintf("%v", key))
+	}
+}
+
+// mix scrambles the bits of an integer key, so sequential keys are spread
+// across shards (splitmix64 finalizer).
+func mix(x uint64) uint64 {
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	x ^= x >> 31
+
+	return x
+}
+
+// lock acquires the write lock of the shard, recording the wait time if
+// metrics are enabled.
+func (m *Map[K, V]) lock(s *shard[K, V]) {
+	if m.metrics == nil {
+		s.Lock()
+
+		return
+	}
+
+	start := time.Now()
+
+	s.Lock()
+
+	m.metrics.ObserveLockWait(time.Since(start))
+}
+
+// rlock acquires the read lock of the shard, recording the wait time if
+// metrics are enabled.
+func (m *Map[K, V]) rlock(s *shard[K, V]) {
+	if m.metrics == nil {
+		s.RLock()
+
+		return
+	}
+
+	start := time.Now()
+
+	s.RLock()
+
+	m.metrics.ObserveLockWait(time.Since(start))
+}
+
+// resize updates the number of elements by delta. Caller must hold the write
+// lock of the shard which changed.
+func (m *Map[K, V]) resize(delta int64) {
+	size := atomic.AddInt64(&m.size, delta)
+
+	m.metrics.SetSize(int(size))
+}
+
+// Metrics returns the metrics of the map, nil if not enabled.
+func (m *Map[K, V]) Metrics() *metrics.Metrics {
+	return m.metrics
+}
+
+// String is the stringer implementation.
+func (m *Map[K, V]) String() string {
+	var sb strings.Builder
+
+	sb.WriteString("map[")
+
+	first := true
+
+	m.Range(func(key K, value V) bool {
+		if !first {
+			sb.WriteString(" ")
+		}
+
+		first = false
+
+		sb.WriteString(fmt.Sprintf("%v:%v", key, value))
+
+		return true
+	})
+
+	sb.WriteString("]")
+
+	return sb.String()
+}
+
+//////
+// CRUD operations.
+
+// Add a value in the map.
+func (m *Map[K, V]) Add(key K, value V) *Map[K, V] {
+	s := m.shard(key)
+
+	m.lock(s)
+	defer s.Unlock()
+
+	if _, ok := s.data[key]; !ok {
+		m.resize(1)
+	}
+
+	s.data[key] = value
+
+	m.metrics.Operation("add")
+
+	return m
+}
+
+// Set a value in the map. It's the same as Add, without chaining.
+func (m *Map[K, V]) Set(key K, value V) {
+	m.Add(key, value)
+}
+
+// Get a value from the map.
+func (m *Map[K, V]) Get(key K) (V, bool) {
+	s := m.shard(key)
+
+	m.rlock(s)
+	defer s.RUnlock()
+
+	value, ok := s.data[key]
+
+	m.metrics.Operation("get")
+	m.metrics.Lookup(ok)
+
+	return value, ok
+}
+
+// GetOrAdd returns the value of the key if present, otherwise it adds the
+// given value, and returns it. The boolean is true if the value was present.
+func (m *Map[K, V]) GetOrAdd(key K, value V) (V, bool) {
+	s := m.shard(key)
+
+	m.lock(s)
+	defer s.Unlock()
+
+	m.metrics.Operation("getOrAdd")
+
+	if existing, ok := s.data[key]; ok {
+		m.metrics.Hit()
+
+		return existing, true
+	}
+
+	m.metrics.Miss()
+
+	s.data[key] = value
+
+	m.resize(1)
+
+	return value, false
+}
+
+// GetOrAddFunc is like GetOrAdd, calling f to create the value only if the key
+// is missing. f is called holding the lock of the key's shard, so it must not
+// use the map.
+func (m *Map[K, V]) GetOrAddFunc(key K, f func() V) (V, bool) {
+	s := m.shard(key)
+
+	m.lock(s)
+	defer s.Unlock()
+
+	m.metrics.Operation("getOrAdd")
+
+	if existing, ok := s.data[key]; ok {
+		m.metrics.Hit()
+
+		return existing, true
+	}
+
+	m.metrics.Miss()
+
+	value := f()
+
+	s.data[key] = value
+
+	m.resize(1)
+
+	return value, false
+}
+
+// Delete a value from the map.
+func (m *Map[K, V]) Delete(key K) *Map[K, V] {
+	m.GetAndDelete(key)
+
+	return m
+}
+
+// GetAndDelete deletes the key, returning its value if it was present.
+func (m *Map[K, V]) GetAndDelete(key K) (V, bool) {
+	s := m.shard(key)
+
+	m.lock(s)
+	defer s.Unlock()
+
+	m.metrics.Operation("delete")
+
+	value, ok := s.data[key]
+	if ok {
+		delete(s.data, key)
+
+		m.resize(-1)
+	}
+
+	return value, ok
+}
+
+// Contains checks if the key is present.
+func (m *Map[K, V]) Contains(key K) bool {
+	_, ok := m.Get(key)
+
+	return ok
+}
+
+// Clear removes all elements from the map. Shards are cleared one at a time,
+// so concurrent additions to already cleared shards are kept.
+func (m *Map[K, V]) Clear() *Map[K, V] {
+	for _, s := range m.table() {
+		m.lock(s)
+
+		m.resize(-int64(len(s.data)))
+
+		s.data = make(map[K]V)
+
+		s.Unlock()
+	}
+
+	m.metrics.Operation("clear")
+
+	return m
+}
+
+//////
+// Meta operations.
+
+// Len returns the number of elements in the map, in O(1).
+func (m *Map[K, V]) Len() int {
+	return int(atomic.LoadInt64(&m.size))
+}
+
+// Size is the same as Len.
+func (m *Map[K, V]) Size() int {
+	return m.Len()
+}
+
+// Empty checks if the map is empty.
+func (m *Map[K, V]) Empty() bool {
+	return m.Len() == 0
+}
+
+// Keys returns the keys of the map, in no particular order.
+func (m *Map[K, V]) Keys() []K {
+	keys := make([]K, 0, m.Len())
+
+	m.Range(func(key K, _ V) bool {
+		keys = append(keys, key)
+
+		return true
+	})
+
+	return keys
+}
+
+// Values returns the values of the map, in no particular order.
+func (m *Map[K, V]) Values() []V {
+	values := make([]V, 0, m.Len())
+
+	m.Range(func(_ K, value V) bool {
+		values = append(values, value)
+
+		return true
+	})
+
+	return values
+}
+
+// Range calls f for every element, in no particular order, until it returns
+// false. Shards are copied, one at a time, and f is called without holding
+// any lock, so it may use the map. Like sync.Map, it isn't a consistent
+// snapshot: changes made concurrently may, or may not, be observed.
+func (m *Map[K, V]) Range(f func(key K, value V) bool) {
+	type entry struct {
+		key   K
+		value V
+	}
+
+	entries := []entry{}
+
+	for _, s := range m.table() {
+		m.rlock(s)
+
+		entries = entries[:0]
+
+		for key, value := range s.data {
+			entries = append(entries, entry{key: key, value: value})
+		}
+
+		s.RUnlock()
+
+		for _, e := range entries {
+			if !f(e.key, e.value) {
+				return
+			}
+		}
+	}
+}
+
+// ToMap returns a copy of the map, as a built-in map.
+func (m *Map[K, V]) ToMap() map[K]V {
+	result := make(map[K]V, m.Len())
+
+	m.Range(func(key K, value V) bool {
+		result[key] = value
+
+		return true
+	})
+
+	return result
+}
+
+// Digest returns a stable hash of the entries, regardless of their order, by
+// h, or sha256 if nil, e.g.: for cheap equality checks, ETags, and cache keys,
+// see shared.DigestUnordered. Like Range, it isn't a consistent snapshot.
+func (m *Map[K, V]) Digest(h hash.Hash) (string, error) {
+	type entry struct {
+		Key   K `json:"key"`
+		Value V `json:"value"`
+	}
+
+	entries := make([]entry, 0, m.Len())
+
+	m.Range(func(key K, value V) bool {
+		entries = append(entries, entry{Key: key, Value: value})
+
+		return true
+	})
+
+	return shared.DigestUnordered(h, entries)
+}
+
+// Clone returns a copy of the map, with the same configuration.
+func (m *Map[K, V]) Clone() *Map[K, V] {
+	clone := New[K, V](WithShards[K, V](len(m.table())), WithMetrics[K, V](m.metrics))
+
+	m.Range(func(key K, value V) bool {
+		clone.Add(key, value)
+
+		return true
+	})
+
+	return clone
+}
+
+//////
+// Conversion Operations.
+//////
+
+// MarshalJSON implements json.Marshaler interface for Map. Keys must be
+// strings, integers, or implement encoding.TextMarshaler, like for built-in
+// maps.
+func (m *Map[K, V]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(m.ToMap())
+}
+
+// UnmarshalJSON implements json.Unmarshaler interface for Map. It adds the
+// decoded elements to the map.
+func (m *Map[K, V]) UnmarshalJSON(data []byte) error {
+	var temp map[K]V
+	if err := json.Unmarshal(data, &temp); err != nil {
+		return err
+	}
+
+	for key, value := range temp {
+		m.Add(key, value)
+	}
+
+	return nil
+}
+
+// Encode writes the map to w as JSON, like MarshalJSON, marshalling one
+// element at a time, without building the whole JSON in memory. Shards are
+// copied one at a time, like in Range.
+func (m *Map[K, V]) Encode(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+
+	if err := bw.WriteByte('{'); err != nil {
+		return err
+	}
+
+	var err error
+
+	first := true
+
+	m.Range(func(key K, value V) bool {
+		// Marshalling a single-element map reuses the key encoding rules of
+		// built-in maps.
+		var b []byte
+
+		b, err = json.Marshal(map[K]V{key: value})
+		if err != nil {
+			return false
+		}
+
+		b = bytes.TrimSuffix(bytes.TrimPrefix(b, []byte{'{'}), []byte{'}'})
+
+		if !first {
+			if err = bw.WriteByte(','); err != nil {
+				return false
+			}
+		}
+
+		first = false
+
+		_, err = bw.Write(b)
+
+		return err == nil
+	})
+
+	if err != nil {
+		return err
+	}
+
+	if err := bw.WriteByte('}'); err != nil {
+		return err
+	}
+
+	return bw.Flush()
+}
+
+// Decode reads a JSON object from r, like UnmarshalJSON, decoding one element
+// at a time, and adding it to the map.
+func (m *Map[K, V]) Decode(r io.Reader) error {
+	dec := json.NewDecoder(r)
+
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+
+	if tok == nil {
+		return nil
+	}
+
+	if tok != json.Delim('{') {
+		return fmt.Errorf("expected a JSON object, got %v", tok)
+	}
+
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+
+		var raw json.RawMessage
+
+		if err := dec.Decode(&raw); err != nil {
+			return err
+		}
+
+		key, err := json.Marshal(tok)
+		if err != nil {
+			return err
+		}
+
+		// Unmarshalling into a single-element map reuses the key decoding
+		// rules of built-in maps.
+		var temp map[K]V
+
+		if err := json.Unmarshal([]byte(fmt.Sprintf("{%s:%s}", key, raw)), &temp); err != nil {
+			return err
+		}
+
+		for key, value := range temp {
+			m.Add(key, value)
+		}
+	}
+
+	_, err = dec.Token()
+
+	return err
+}
+
+//////
+// Factory.
+//////
+
+// WithShards sets the number of shards. More shards reduce contention, using
+// more memory. A number <= 0 means DefaultShards.
+func WithShards[K comparable, V any](n int) Option[K, V] {
+	return func(m *Map[K, V]) {
+		if n <= 0 {
+			n = DefaultShards
+		}
+
+		m.shards = newShards[K, V](n)
+	}
+}
+
+// WithMetrics enables metrics instrumentation, tracking operation counts,
+// size, lock wait times, and hit/miss ratios into the given metrics.
+func WithMetrics[K comparable, V any](mtrcs *metrics.Metrics) Option[K, V] {
+	return func(m *Map[K, V]) {
+		m.metrics = mtrcs
+	}
+}
+
+// newShards creates n empty shards.
+func newShards[K comparable, V any](n int) []*shard[K, V] {
+	shards := make([]*shard[K, V], n)
+
+	for i := range shards {
+		shards[i] = &shard[K, V]{data: make(map[K]V)}
+	}
+
+	return shards
+}
+
+// New creates a new Map.
+func New[K comparable, V any](opts ...Option[K, V]) *Map[K, V] {
+	m := &Map[K, V]{
+		shards: newShards[K, V](DefaultShards),
+		seed:   maphash.MakeSeed(),
+	}
+
+	for _, opt := range opts {
+		opt(m)
+	}
+
+	return m
+}
+
+// NewFromJSON creates a Map configured with the given options, loaded from
+// JSON, as encoded by MarshalJSON.
+func NewFromJSON[K comparable, V any](data []byte, opts ...Option[K, V]) (*Map[K, V], error) {
+	m := New(opts...)
+
+	if err := m.UnmarshalJSON(data); err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
@@ -0,0 +1,290 @@
+package safemap
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/thalesfsp/go-common-types/metrics"
+)
+
+func TestMapCRUD(t *testing.T) {
+	m := New[string, int]()
+
+	m.Add("a", 1).Add("b", 2).Add("a", 3)
+	m.Set("c", 4)
+
+	value, ok := m.Get("a")
+	assert.True(t, ok)
+	assert.Equal(t, 3, value)
+
+	_, ok = m.Get("z")
+	assert.False(t, ok)
+
+	assert.Equal(t, 3, m.Len())
+	assert.True(t, m.Contains("b"))
+
+	value, ok = m.GetAndDelete("b")
+	assert.True(t, ok)
+	assert.Equal(t, 2, value)
+
+	_, ok = m.GetAndDelete("b")
+	assert.False(t, ok)
+
+	m.Delete("c").Delete("z")
+
+	assert.Equal(t, 1, m.Len())
+
+	m.Clear()
+
+	assert.True(t, m.Empty())
+}
+
+func TestMapGetOrAdd(t *testing.T) {
+	m := New[int, string]()
+
+	value, loaded := m.GetOrAdd(1, "a")
+	assert.False(t, loaded)
+	assert.Equal(t, "a", value)
+
+	value, loaded = m.GetOrAdd(1, "b")
+	assert.True(t, loaded)
+	assert.Equal(t, "a", value)
+
+	calls := 0
+	create := func() string {
+		calls++
+
+		return "c"
+	}
+
+	value, loaded = m.GetOrAddFunc(2, create)
+	assert.False(t, loaded)
+	assert.Equal(t, "c", value)
+
+	_, loaded = m.GetOrAddFunc(2, create)
+	assert.True(t, loaded)
+	assert.Equal(t, 1, calls)
+	assert.Equal(t, 2, m.Len())
+}
+
+func TestMapRange(t *testing.T) {
+	m := New[int, int](WithShards[int, int](4))
+
+	for i := 0; i < 100; i++ {
+		m.Add(i, i*i)
+	}
+
+	keys := m.Keys()
+	sort.Ints(keys)
+
+	assert.Len(t, keys, 100)
+	assert.Equal(t, 0, keys[0])
+	assert.Equal(t, 99, keys[99])
+	assert.Len(t, m.Values(), 100)
+
+	count := 0
+
+	m.Range(func(key, value int) bool {
+		assert.Equal(t, key*key, value)
+
+		// f may use the map.
+		m.Delete(key)
+
+		count++
+
+		return count < 10
+	})
+
+	assert.Equal(t, 10, count)
+	assert.Equal(t, 90, m.Len())
+}
+
+func TestMapKeys(t *testing.T) {
+	type point struct{ x, y int }
+
+	m := New[point, string]()
+	m.Add(point{1, 2}, "a")
+
+	value, ok := m.Get(point{1, 2})
+	assert.True(t, ok)
+	assert.Equal(t, "a", value)
+
+	f := New[float64, string]()
+	f.Add(0, "zero")
+
+	value, ok = f.Get(math.Copysign(0, -1))
+	assert.True(t, ok)
+	assert.Equal(t, "zero", value)
+}
+
+func TestMapJSON(t *testing.T) {
+	m := New[string, int]()
+	m.Add("a", 1).Add("b", 2)
+
+	b, err := m.MarshalJSON()
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"a":1,"b":2}`, string(b))
+
+	decoded := New[string, int]()
+	assert.NoError(t, decoded.UnmarshalJSON(b))
+	assert.Equal(t, map[string]int{"a": 1, "b": 2}, decoded.ToMap())
+
+	assert.Equal(t, "map[a:1]", New[string, int]().Add("a", 1).String())
+	assert.Equal(t, m.ToMap(), m.Clone().ToMap())
+}
+
+func TestMapEncodeDecode(t *testing.T) {
+	m := New[int, string]()
+	m.Add(1, "a").Add(2, "b").Add(3, "c")
+
+	var buf bytes.Buffer
+
+	assert.NoError(t, m.Encode(&buf))
+	assert.JSONEq(t, `{"1":"a","2":"b","3":"c"}`, buf.String())
+
+	decoded := New[int, string]()
+
+	assert.NoError(t, decoded.Decode(&buf))
+	assert.Equal(t, m.ToMap(), decoded.ToMap())
+
+	buf.Reset()
+
+	assert.NoError(t, New[int, string]().Encode(&buf))
+	assert.Equal(t, `{}`, buf.String())
+
+	assert.NoError(t, decoded.Decode(strings.NewReader(`null`)))
+	assert.Error(t, decoded.Decode(strings.NewReader(`[1]`)))
+	assert.Error(t, decoded.Decode(strings.NewReader(`{"x":"a"}`)))
+}
+
+func TestMapConcurrent(t *testing.T) {
+	mtrcs := metrics.New("")
+	m := New(WithMetrics[int, int](mtrcs))
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+
+		go func(i int) {
+			defer wg.Done()
+
+			for j := 0; j < 1000; j++ {
+				m.Add(i*1000+j, j)
+				m.Get(j)
+				m.GetOrAdd(j, j)
+			}
+		}(i)
+	}
+
+	wg.Wait()
+
+	assert.Equal(t, 8000, m.Len())
+	assert.Equal(t, int64(8000), mtrcs.Snapshot().Size)
+}
+
+func BenchmarkMapParallel(b *testing.B) {
+	m := New[string, int]()
+
+	keys := make([]string, 1024)
+	for i := range keys {
+		keys[i] = strconv.Itoa(i)
+	}
+
+	b.RunParallel(func(pb *testing.PB) {
+		i := 0
+
+		for pb.Next() {
+			key := keys[i%len(keys)]
+
+			if i%4 == 0 {
+				m.Add(key, i)
+			} else {
+				m.Get(key)
+			}
+
+			i++
+		}
+	})
+}
+
+func ExampleMap() {
+	m := New[string, int]()
+	m.Add("a", 1)
+
+	value, ok := m.Get("a")
+
+	fmt.Println(value, ok)
+	// Output: 1 true
+}
+
+func TestMapEstimateBytes(t *testing.T) {
+	mtrcs := metrics.New("test")
+
+	m := New[string, int64](WithShards[string, int64](2), WithMetrics[string, int64](mtrcs))
+	empty := m.EstimateBytes()
+
+	m.Add("ab", 1)
+	m.Add("cde", 2)
+
+	assert.Equal(t, empty+(16+2+8)+(16+3+8), m.EstimateBytes())
+	assert.Equal(t, m.EstimateBytes(), mtrcs.Snapshot().Bytes)
+
+	sized := New[string, []int64](WithSizers[string, []int64](
+		func(string) int { return 0 },
+		func(v []int64) int { return 8 * len(v) },
+	))
+	empty = sized.EstimateBytes()
+
+	sized.Add("ab", []int64{1, 2})
+
+	assert.Equal(t, empty+16+24+16, sized.EstimateBytes())
+}
+
+func TestMapDigest(t *testing.T) {
+	a := New[string, int]()
+	a.Add("a", 1).Add("b", 2)
+
+	b := New[string, int]()
+	b.Add("b", 2).Add("a", 1)
+
+	da, err := a.Digest(nil)
+	assert.NoError(t, err)
+
+	db, err := b.Digest(nil)
+	assert.NoError(t, err)
+	assert.Equal(t, da, db)
+
+	b.Add("a", 3)
+
+	db, err = b.Digest(nil)
+	assert.NoError(t, err)
+	assert.NotEqual(t, da, db)
+}
+
+func TestMapZeroValue(t *testing.T) {
+	var m Map[string, int]
+
+	assert.NoError(t, m.UnmarshalJSON([]byte(`{"a":1}`)))
+
+	m.Set("b", 2)
+
+	assert.Equal(t, map[string]int{"a": 1, "b": 2}, m.ToMap())
+	assert.Equal(t, 2, m.Size())
+}
+
+func TestNewFromJSON(t *testing.T) {
+	m, err := NewFromJSON[string, int]([]byte(`{"a":1}`), WithShards[string, int](4))
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]int{"a": 1}, m.ToMap())
+
+	_, err = NewFromJSON[string, int]([]byte(`[`))
+	assert.Error(t, err)
+}
@@ -0,0 +1,51 @@
+package safemap
+
+import (
+	"unsafe"
+
+	"github.com/thalesfsp/go-common-types/shared"
+)
+
+//////
+// Methods.
+//////
+
+// EstimateBytes estimates the memory used by the map, in bytes: per entry,
+// the key, and the value, plus the bytes they reference, as given by the
+// sizers, see WithSizers. The overhead of the buckets of the built-in maps
+// isn't counted. Shards are locked one at a time, so the estimate isn't a
+// snapshot. It's recorded into the metrics, if enabled.
+func (m *Map[K, V]) EstimateBytes() int64 {
+	size := int64(unsafe.Sizeof(*m))
+
+	for _, s := range m.table() {
+		m.rlock(s)
+
+		size += int64(unsafe.Sizeof(*s))
+
+		for key, value := range s.data {
+			size += shared.EstimateSize(key, m.keySizer) + shared.EstimateSize(value, m.valueSizer)
+		}
+
+		s.RUnlock()
+	}
+
+	m.metrics.Operation("estimateBytes")
+	m.metrics.SetBytes(size)
+
+	return size
+}
+
+//////
+// Factory.
+//////
+
+// WithSizers sets the sizers of the keys, and values, used by EstimateBytes
+// to count the bytes they reference, e.g.: the elements of a slice. A nil
+// sizer counts only the contents of strings, and byte slices, the default.
+func WithSizers[K comparable, V any](key shared.Sizer[K], value shared.Sizer[V]) Option[K, V] {
+	return func(m *Map[K, V]) {
+		m.keySizer = key
+		m.valueSizer = value
+	}
+}
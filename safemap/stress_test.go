@@ -0,0 +1,95 @@
+package safemap
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// stressGoroutines is the number of goroutines of the stress tests, fewer with
+// -short.
+func stressGoroutines() int {
+	if testing.Short() {
+		return 32
+	}
+
+	return 256
+}
+
+// TestStress runs GetOrAdd, and then GetAndDelete, of the same keys from
+// hundreds of goroutines. Run it with -race. Each one must succeed exactly
+// once, and every goroutine must observe the value which was added.
+func TestStress(t *testing.T) {
+	const keys = 100
+
+	n := stressGoroutines()
+	m := New[int, int](WithShards[int, int](4))
+
+	var (
+		wg      sync.WaitGroup
+		adds    = make([]int64, keys)
+		deletes = make([]int64, keys)
+		winners = make([]int64, keys)
+	)
+
+	for g := 0; g < n; g++ {
+		wg.Add(1)
+
+		go func(g int) {
+			defer wg.Done()
+
+			for i := 0; i < keys; i++ {
+				k := (g + i) % keys
+
+				if _, loaded := m.GetOrAdd(k, g); !loaded {
+					atomic.AddInt64(&adds[k], 1)
+					atomic.StoreInt64(&winners[k], int64(g))
+				}
+
+				_ = m.Size()
+
+				if i%10 == 0 {
+					m.Range(func(int, int) bool { return true })
+				}
+			}
+		}(g)
+	}
+
+	wg.Wait()
+
+	assert.Equal(t, keys, m.Size())
+
+	for k := 0; k < keys; k++ {
+		assert.Equal(t, int64(1), adds[k], "key %d", k)
+
+		v, _ := m.Get(k)
+
+		assert.Equal(t, winners[k], int64(v), "key %d", k)
+	}
+
+	for g := 0; g < n; g++ {
+		wg.Add(1)
+
+		go func(g int) {
+			defer wg.Done()
+
+			for i := 0; i < keys; i++ {
+				k := (g + i) % keys
+
+				if _, ok := m.GetAndDelete(k); ok {
+					atomic.AddInt64(&deletes[k], 1)
+				}
+			}
+		}(g)
+	}
+
+	wg.Wait()
+
+	for k := 0; k < keys; k++ {
+		assert.Equal(t, int64(1), deletes[k], "key %d", k)
+	}
+
+	assert.True(t, m.Empty())
+}
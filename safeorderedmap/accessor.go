@@ -0,0 +1,232 @@
+package safeorderedmap
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+)
+
+//////
+// Const, vars, and types.
+//////
+
+// ErrType is returned when a value can't be converted to the requested type.
+var ErrType = errors.New("unexpected type")
+
+//////
+// Helpers.
+//////
+
+// lookup gets the value of the key, or ErrKeyNotFound.
+func lookup(m *SafeOrderedMap[any], key string) (any, error) {
+	value, ok := m.get(key)
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrKeyNotFound, key)
+	}
+
+	return value, nil
+}
+
+// typeError wraps ErrType with the key, the wanted, and the actual types.
+func typeError(key string, want string, value any) error {
+	return fmt.Errorf("%w: %q is %T, not %s", ErrType, key, value, want)
+}
+
+// toFloat64 converts any number, including json.Number, to float64.
+func toFloat64(value any) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case float32:
+		return float64(v), true
+	case int:
+		return float64(v), true
+	case int8:
+		return float64(v), true
+	case int16:
+		return float64(v), true
+	case int32:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case uint:
+		return float64(v), true
+	case uint8:
+		return float64(v), true
+	case uint16:
+		return float64(v), true
+	case uint32:
+		return float64(v), true
+	case uint64:
+		return float64(v), true
+	case json.Number:
+		f, err := v.Float64()
+
+		return f, err == nil
+	default:
+		return 0, false
+	}
+}
+
+// toInt converts a number to int. Integer types are converted directly, so
+// large values don't lose precision, others only if integral, e.g.: 1.0, as
+// decoded from JSON.
+func toInt(value any) (int, bool) {
+	switch v := value.(type) {
+	case int:
+		return v, true
+	case int64:
+		return int(v), int64(int(v)) == v
+	case int32:
+		return int(v), true
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return toInt(i)
+		}
+	}
+
+	f, ok := toFloat64(value)
+	if !ok || f != math.Trunc(f) || f < math.MinInt64 || f >= math.MaxInt64 {
+		return 0, false
+	}
+
+	return toInt(int64(f))
+}
+
+// toTime converts a time.Time, or an RFC 3339 string, as encoded in JSON.
+func toTime(value any) (time.Time, bool) {
+	switch v := value.(type) {
+	case time.Time:
+		return v, true
+	case string:
+		t, err := time.Parse(time.RFC3339Nano, v)
+
+		return t, err == nil
+	default:
+		return time.Time{}, false
+	}
+}
+
+// toStringSlice converts a []string, or a []any of strings, as decoded from
+// JSON.
+func toStringSlice(value any) ([]string, bool) {
+	switch v := value.(type) {
+	case []string:
+		return v, true
+	case []any:
+		result := make([]string, 0, len(v))
+
+		for _, item := range v {
+			s, ok := item.(string)
+			if !ok {
+				return nil, false
+			}
+
+			result = append(result, s)
+		}
+
+		return result, true
+	default:
+		return nil, false
+	}
+}
+
+// convert converts the value to V, applying the coercion rules of the typed
+// accessors.
+func convert[V any](value any) (V, bool) {
+	if v, ok := value.(V); ok {
+		return v, true
+	}
+
+	var (
+		result any
+		ok     bool
+	)
+
+	switch any(*new(V)).(type) {
+	case int:
+		result, ok = toInt(value)
+	case int64:
+		var i int
+
+		i, ok = toInt(value)
+		result = int64(i)
+	case float64:
+		result, ok = toFloat64(value)
+	case time.Time:
+		result, ok = toTime(value)
+	case []string:
+		result, ok = toStringSlice(value)
+	}
+
+	if !ok {
+		return *new(V), false
+	}
+
+	//nolint:forcetypeassert
+	return result.(V), true
+}
+
+//////
+// Typed accessors.
+//////
+
+// As returns the value of the key as V, e.g.: As[int](m, "port"). Numbers are
+// converted between types if they fit, e.g.: float64 as decoded from JSON, to
+// int, RFC 3339 strings to time.Time, and []any of strings to []string. It
+// returns ErrKeyNotFound if the key is missing, and ErrType if the value can't
+// be converted.
+func As[V any](m *SafeOrderedMap[any], key string) (V, error) {
+	value, err := lookup(m, key)
+	if err != nil {
+		return *new(V), err
+	}
+
+	result, ok := convert[V](value)
+	if !ok {
+		return *new(V), typeError(key, fmt.Sprintf("%T", *new(V)), value)
+	}
+
+	return result, nil
+}
+
+// GetString returns the value of the key as a string.
+func GetString(m *SafeOrderedMap[any], key string) (string, error) {
+	return As[string](m, key)
+}
+
+// GetInt returns the value of the key as an int. Floats are converted only if
+// integral.
+func GetInt(m *SafeOrderedMap[any], key string) (int, error) {
+	return As[int](m, key)
+}
+
+// GetInt64 returns the value of the key as an int64, e.g.: an ID decoded as
+// json.Number, see WithUseNumber.
+func GetInt64(m *SafeOrderedMap[any], key string) (int64, error) {
+	return As[int64](m, key)
+}
+
+// GetFloat64 returns the value of the key as a float64, converting any number.
+func GetFloat64(m *SafeOrderedMap[any], key string) (float64, error) {
+	return As[float64](m, key)
+}
+
+// GetBool returns the value of the key as a bool.
+func GetBool(m *SafeOrderedMap[any], key string) (bool, error) {
+	return As[bool](m, key)
+}
+
+// GetTime returns the value of the key as a time.Time, parsing RFC 3339
+// strings.
+func GetTime(m *SafeOrderedMap[any], key string) (time.Time, error) {
+	return As[time.Time](m, key)
+}
+
+// GetStringSlice returns the value of the key as a []string, converting a []any
+// of strings.
+func GetStringSlice(m *SafeOrderedMap[any], key string) ([]string, error) {
+	return As[[]string](m, key)
+}
@@ -0,0 +1,84 @@
+package safeorderedmap
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTypedAccessors(t *testing.T) {
+	m := New[any]()
+
+	assert.NoError(t, m.UnmarshalJSON([]byte(`{
+		"name": "api",
+		"port": 8080,
+		"ratio": 0.5,
+		"debug": true,
+		"started": "2023-02-08T10:00:00Z",
+		"tags": ["a", "b"],
+		"mixed": ["a", 1]
+	}`)))
+
+	name, err := GetString(m, "name")
+	assert.NoError(t, err)
+	assert.Equal(t, "api", name)
+
+	port, err := GetInt(m, "port")
+	assert.NoError(t, err)
+	assert.Equal(t, 8080, port)
+
+	ratio, err := GetFloat64(m, "ratio")
+	assert.NoError(t, err)
+	assert.Equal(t, 0.5, ratio)
+
+	debug, err := GetBool(m, "debug")
+	assert.NoError(t, err)
+	assert.True(t, debug)
+
+	started, err := GetTime(m, "started")
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2023, 2, 8, 10, 0, 0, 0, time.UTC), started)
+
+	tags, err := GetStringSlice(m, "tags")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a", "b"}, tags)
+
+	port64, err := As[int64](m, "port")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(8080), port64)
+
+	_, err = GetInt(m, "ratio")
+	assert.ErrorIs(t, err, ErrType)
+
+	_, err = GetString(m, "port")
+	assert.ErrorIs(t, err, ErrType)
+
+	_, err = GetStringSlice(m, "mixed")
+	assert.ErrorIs(t, err, ErrType)
+
+	_, err = GetTime(m, "name")
+	assert.ErrorIs(t, err, ErrType)
+
+	_, err = GetBool(m, "missing")
+	assert.ErrorIs(t, err, ErrKeyNotFound)
+}
+
+func TestAsNumbers(t *testing.T) {
+	m := New[any]()
+	m.Add("int8", int8(3)).Add("uint", uint(4)).Add("number", json.Number("5")).Add("huge", 1e20)
+
+	for key, expected := range map[string]int{"int8": 3, "uint": 4, "number": 5} {
+		value, err := GetInt(m, key)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, value)
+	}
+
+	_, err := GetInt(m, "huge")
+	assert.ErrorIs(t, err, ErrType)
+
+	f, err := GetFloat64(m, "number")
+	assert.NoError(t, err)
+	assert.Equal(t, 5.0, f)
+}
@@ -0,0 +1,36 @@
+package safeorderedmap
+
+import (
+	"encoding/json"
+
+	"github.com/thalesfsp/go-common-types/shared"
+)
+
+//////
+// Methods.
+//////
+
+// MarshalJSONSorted returns the JSON representation of the map as an object
+// with the keys sorted lexicographically, regardless of the insertion order,
+// and of WithEntriesJSON, e.g.: as a canonical representation to hash, or to
+// sign. Values are encoded by encoding/json, which also sorts the keys of
+// nested maps.
+func (m *SafeOrderedMap[T]) MarshalJSONSorted() ([]byte, error) {
+	m.rlock()
+	defer m.runlock()
+
+	// encoding/json sorts the keys of maps.
+	return json.Marshal(m.toMap())
+}
+
+// CanonicalHash returns the hash of the canonical representation of the map,
+// see MarshalJSONSorted, by shared.GenerateHash, so maps with the same
+// entries have the same hash, regardless of their order.
+func (m *SafeOrderedMap[T]) CanonicalHash() (string, error) {
+	data, err := m.MarshalJSONSorted()
+	if err != nil {
+		return "", err
+	}
+
+	return shared.GenerateHash(string(data)), nil
+}
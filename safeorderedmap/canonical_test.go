@@ -0,0 +1,62 @@
+package safeorderedmap
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMarshalJSONSorted(t *testing.T) {
+	m := New(WithEntriesJSON[any]())
+
+	m.Add("b", 2).Add("a", map[string]int{"y": 1, "x": 2}).Add("c", nil)
+
+	data, err := m.MarshalJSONSorted()
+
+	assert.NoError(t, err)
+	assert.Equal(t, `{"a":{"x":2,"y":1},"b":2,"c":null}`, string(data))
+}
+
+func TestCanonicalHash(t *testing.T) {
+	a := New[int]().Add("x", 1).Add("y", 2)
+	b := New(WithEntriesJSON[int]()).Add("y", 2).Add("x", 1)
+
+	hashA, err := a.CanonicalHash()
+
+	assert.NoError(t, err)
+	assert.Len(t, hashA, 64)
+
+	hashB, err := b.CanonicalHash()
+
+	assert.NoError(t, err)
+	assert.Equal(t, hashA, hashB)
+
+	b.Add("x", 3)
+
+	hashB, _ = b.CanonicalHash()
+
+	assert.NotEqual(t, hashA, hashB)
+
+	_, err = New[any]().Add("f", func() {}).CanonicalHash()
+
+	assert.Error(t, err)
+}
+
+func TestDigest(t *testing.T) {
+	a := New[int]()
+	a.Add("a", 1).Add("b", 2)
+
+	b := New[int]()
+	b.Add("b", 2).Add("a", 1)
+
+	da, err := a.Digest(nil)
+	assert.NoError(t, err)
+
+	again, err := a.Clone().Digest(nil)
+	assert.NoError(t, err)
+	assert.Equal(t, da, again)
+
+	db, err := b.Digest(nil)
+	assert.NoError(t, err)
+	assert.NotEqual(t, da, db)
+}
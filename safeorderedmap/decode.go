@@ -0,0 +1,75 @@
+package safeorderedmap
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+)
+
+//////
+// Const, vars, and types.
+//////
+
+// KeyDecoder decodes the raw JSON value of a key, see WithKeyDecoder.
+type KeyDecoder[T any] func(data []byte) (T, error)
+
+//////
+// Methods.
+//////
+
+// decoder returns a JSON decoder reading from r, configured by the decoding
+// options of the root map, see WithUseNumber.
+func (m *SafeOrderedMap[T]) decoder(r io.Reader) *json.Decoder {
+	dec := json.NewDecoder(r)
+
+	if m.useNumber {
+		dec.UseNumber()
+	}
+
+	return dec
+}
+
+// decodeValue decodes the raw JSON value of the key, with its decoder, if
+// any, see WithKeyDecoder, or as JSON, see WithUseNumber.
+func (m *SafeOrderedMap[T]) decodeValue(key string, data []byte) (T, error) {
+	if decode, ok := m.keyDecoders[key]; ok {
+		return decode(data)
+	}
+
+	var value T
+
+	if err := m.decoder(bytes.NewReader(data)).Decode(&value); err != nil {
+		return value, err
+	}
+
+	return value, nil
+}
+
+//////
+// Factory.
+//////
+
+// WithUseNumber makes numbers, decoded into an interface, e.g.: a
+// SafeOrderedMap[any], json.Number instead of float64, so large integers,
+// e.g.: int64 IDs, survive round-trips without losing precision. It applies
+// to UnmarshalJSON, Decode, UnmarshalBSON, and Replay. Typed accessors, e.g.:
+// GetInt, and As, convert json.Number.
+func WithUseNumber[T any]() Option[T] {
+	return func(m *SafeOrderedMap[T]) {
+		m.useNumber = true
+	}
+}
+
+// WithKeyDecoder sets the decoder of the value of the key, e.g.: to parse a
+// timestamp, or an ID, as a specific type in a SafeOrderedMap[any]. It
+// applies to UnmarshalJSON, Decode, and UnmarshalBSON. Keys are matched as
+// is, without folding.
+func WithKeyDecoder[T any](key string, decode KeyDecoder[T]) Option[T] {
+	return func(m *SafeOrderedMap[T]) {
+		if m.keyDecoders == nil {
+			m.keyDecoders = map[string]KeyDecoder[T]{}
+		}
+
+		m.keyDecoders[key] = decode
+	}
+}
@@ -0,0 +1,134 @@
+package safeorderedmap
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithUseNumber(t *testing.T) {
+	const input = `{"id":9007199254740993,"ratio":0.5,"nested":{"id":9007199254740995}}`
+
+	// Without the option, the ID loses precision as a float64.
+	lossy := New[any]()
+
+	assert.NoError(t, json.Unmarshal([]byte(input), lossy))
+
+	id, err := GetInt64(lossy, "id")
+
+	assert.NoError(t, err)
+	assert.NotEqual(t, int64(9007199254740993), id)
+
+	m := New(WithUseNumber[any]())
+
+	assert.NoError(t, json.Unmarshal([]byte(input), m))
+
+	value, _ := m.Get("id")
+
+	assert.Equal(t, json.Number("9007199254740993"), value)
+
+	id, err = GetInt64(m, "id")
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(9007199254740993), id)
+
+	ratio, err := GetFloat64(m, "ratio")
+
+	assert.NoError(t, err)
+	assert.Equal(t, 0.5, ratio)
+
+	nested, _ := m.Get("nested")
+
+	assert.Equal(t, map[string]any{"id": json.Number("9007199254740995")}, nested)
+
+	data, err := json.Marshal(m)
+
+	assert.NoError(t, err)
+	assert.JSONEq(t, input, string(data))
+	assert.Contains(t, string(data), "9007199254740993")
+
+	// Entries, Decode, BSON, and the journal.
+	entries := New(WithUseNumber[any]())
+
+	assert.NoError(t, entries.UnmarshalJSON([]byte(`[{"key":"id","value":9007199254740993}]`)))
+	assert.Equal(t, []any{json.Number("9007199254740993")}, entries.Values())
+
+	decoded := New(WithUseNumber[any]())
+
+	assert.NoError(t, decoded.Decode(strings.NewReader(input)))
+	assert.Equal(t, []string{"id", "ratio", "nested"}, decoded.Keys())
+	assert.Equal(t, json.Number("9007199254740993"), decoded.Values()[0])
+
+	b, err := m.MarshalBSON()
+
+	assert.NoError(t, err)
+
+	fromBSON := New(WithUseNumber[any]())
+
+	assert.NoError(t, fromBSON.UnmarshalBSON(b))
+
+	id, err = GetInt64(fromBSON, "id")
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(9007199254740993), id)
+
+	var journal bytes.Buffer
+
+	New(WithJournal[any](&journal)).Add("id", int64(9007199254740993))
+
+	replayed := New(WithUseNumber[any]())
+
+	assert.NoError(t, replayed.Replay(&journal))
+
+	id, err = GetInt64(replayed, "id")
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(9007199254740993), id)
+}
+
+func TestWithKeyDecoder(t *testing.T) {
+	errBadID := errors.New("bad id")
+
+	m := New(
+		WithKeyDecoder[any]("id", func(data []byte) (any, error) {
+			id, err := strconv.ParseInt(string(data), 10, 64)
+			if err != nil {
+				return nil, errBadID
+			}
+
+			return id, nil
+		}),
+		WithKeyDecoder[any]("at", func(data []byte) (any, error) {
+			var at time.Time
+
+			err := json.Unmarshal(data, &at)
+
+			return at, err
+		}),
+	)
+
+	assert.NoError(t, m.Decode(strings.NewReader(`{"id":9007199254740993,"at":"2024-01-02T03:04:05Z","n":1}`)))
+
+	value, _ := m.Get("id")
+
+	assert.Equal(t, int64(9007199254740993), value)
+
+	value, _ = m.Get("at")
+
+	assert.Equal(t, time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC), value)
+
+	value, _ = m.Get("n")
+
+	assert.Equal(t, float64(1), value)
+
+	// Errors of the decoder are returned, and the map is left unchanged.
+	assert.ErrorIs(t, json.Unmarshal([]byte(`{"id":"x"}`), m), errBadID)
+	assert.ErrorIs(t, m.Decode(strings.NewReader(`[{"key":"id","value":1.5}]`)), errBadID)
+	assert.Equal(t, 3, m.Size())
+}
@@ -0,0 +1,57 @@
+package safeorderedmap
+
+import "reflect"
+
+//////
+// Methods.
+//////
+
+// dispose calls the disposer, if any, see WithDisposer. Caller must hold the
+// write lock.
+func (m *SafeOrderedMap[T]) dispose(key string, value T) {
+	if m.disposer != nil {
+		m.disposer(key, value)
+	}
+}
+
+// same checks if both values are the same, e.g.: the same pointer, so a value
+// added again isn't disposed of. Values which aren't comparable, e.g.: slices,
+// are never the same.
+func same[T any](a, b T) bool {
+	va, vb := reflect.ValueOf(&a).Elem(), reflect.ValueOf(&b).Elem()
+
+	return va.Comparable() && va.Equal(vb)
+}
+
+// discard removes all elements, like reset, disposing of them, see
+// WithDisposer. Caller must hold the write lock.
+func (m *SafeOrderedMap[T]) discard() {
+	if m.disposer != nil {
+		for e := m.head; e != nil; e = e.next {
+			m.dispose(e.key, e.value)
+		}
+	}
+
+	m.reset()
+}
+
+//////
+// Factory.
+//////
+
+// WithDisposer sets a function called with the entries removed from the map,
+// e.g.: to close the files, or connections, held by the values
+// deterministically. It's called when entries are deleted, e.g.: by Delete,
+// Remove, DeleteFunc, or through a namespace, when the map is cleared, by
+// Clear, or replaced, by the unmarshallers, and with the previous value of a
+// key when it's replaced, e.g.: by Add, Set, Replace, or Swap, unless the new
+// value is the same, e.g.: the same pointer. Renamed, and reordered entries
+// aren't disposed of.
+//
+// The disposer is called holding the write lock, so it must not call methods
+// of the map.
+func WithDisposer[T any](disposer func(key string, value T)) Option[T] {
+	return func(m *SafeOrderedMap[T]) {
+		m.disposer = disposer
+	}
+}
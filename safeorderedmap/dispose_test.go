@@ -0,0 +1,90 @@
+package safeorderedmap
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithDisposer(t *testing.T) {
+	disposed := []string{}
+
+	m := New(WithDisposer(func(key string, _ int) {
+		disposed = append(disposed, key)
+	}))
+
+	m.Add("a", 1).Add("b", 2).Add("c", 3).Add("d", 4).Add("e", 5)
+
+	// Replaced values are disposed of, unless they're the same.
+	m.Add("a", 10)
+	m.Set("a", 10)
+
+	_, _ = m.Swap("a", 11)
+
+	assert.Equal(t, []string{"a", "a"}, disposed)
+
+	disposed = disposed[:0]
+
+	// Renames, and reordering keep the entries.
+	assert.NoError(t, m.RenameKey("b", "x"))
+	m.SortFunc(func(a, b Entry[int]) bool { return a.Key > b.Key })
+
+	assert.Empty(t, disposed)
+
+	m.Delete("a")
+	assert.True(t, m.Remove("x"))
+	assert.Equal(t, 1, m.DeleteFunc(func(_ string, value int) bool { return value == 3 }))
+
+	assert.Equal(t, []string{"a", "x", "c"}, disposed)
+
+	disposed = disposed[:0]
+
+	assert.NoError(t, json.Unmarshal([]byte(`{"f":6}`), m))
+	assert.ElementsMatch(t, []string{"e", "d"}, disposed)
+
+	disposed = disposed[:0]
+
+	m.Namespace("n/")
+	m.Add("n/a", 1)
+	m.Namespace("n/").Delete("a")
+
+	assert.Equal(t, []string{"n/a"}, disposed)
+
+	disposed = disposed[:0]
+
+	m.Clear()
+
+	assert.Equal(t, []string{"f"}, disposed)
+}
+
+func TestWithDisposerValues(t *testing.T) {
+	type conn struct{ closed bool }
+
+	m := New(WithDisposer(func(_ string, c *conn) {
+		c.closed = true
+	}))
+
+	first, second := &conn{}, &conn{}
+
+	m.Add("db", first)
+	m.Add("db", first)
+
+	assert.False(t, first.closed)
+
+	m.Set("db", second)
+
+	assert.True(t, first.closed)
+	assert.False(t, second.closed)
+
+	// Values which aren't comparable are always disposed of.
+	disposed := 0
+
+	s := New(WithDisposer(func(_ string, _ []int) {
+		disposed++
+	}))
+
+	s.Add("a", []int{1}).Add("a", []int{1})
+
+	assert.Equal(t, 1, disposed)
+}
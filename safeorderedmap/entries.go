@@ -0,0 +1,178 @@
+package safeorderedmap
+
+import (
+	"bytes"
+	"encoding/json"
+	"sort"
+)
+
+//////
+// Const, vars, and types.
+//////
+
+// Entry is a key-value pair of the map.
+type Entry[T any] struct {
+	Key   string `json:"key"`
+	Value T      `json:"value"`
+}
+
+//////
+// Methods.
+//////
+
+// entries returns the entries of the map, in order. Caller must hold the lock.
+func (m *SafeOrderedMap[T]) entries() []Entry[T] {
+	entries := make([]Entry[T], 0, len(m.data))
+
+	for e := m.head; e != nil; e = e.next {
+		entries = append(entries, Entry[T]{Key: e.key, Value: e.value})
+	}
+
+	return entries
+}
+
+// elements returns the elements of the map, in order. Caller must hold the
+// lock.
+func (m *SafeOrderedMap[T]) elements() []*element[T] {
+	elements := make([]*element[T], 0, len(m.data))
+
+	for e := m.head; e != nil; e = e.next {
+		elements = append(elements, e)
+	}
+
+	return elements
+}
+
+// unmarshalEntries replaces the content of the map with a JSON array of
+// entries.
+func (m *SafeOrderedMap[T]) unmarshalEntries(data []byte) error {
+	var entries []Entry[json.RawMessage]
+
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return err
+	}
+
+	keys := make([]string, 0, len(entries))
+	values := make([]T, 0, len(entries))
+
+	for _, entry := range entries {
+		value, err := m.decodeValue(entry.Key, entry.Value)
+		if err != nil {
+			return err
+		}
+
+		keys = append(keys, entry.Key)
+		values = append(values, value)
+	}
+
+	if err := m.validateAll(keys, values); err != nil {
+		return err
+	}
+
+	m.lock()
+	defer m.unlock()
+
+	m.load(keys, values)
+
+	return nil
+}
+
+// Entries returns the key-value pairs of the map, in order.
+func (m *SafeOrderedMap[T]) Entries() []Entry[T] {
+	if m.snapshotted() {
+		return m.view().entries()
+	}
+
+	m.rlock()
+	defer m.runlock()
+
+	return m.entries()
+}
+
+// ReplaceEntries replaces the content of the map with the entries, in order,
+// atomically: readers see either the old, or the new content. Like the
+// unmarshallers, it returns the validator error, leaving the map untouched,
+// see WithValidator.
+func (m *SafeOrderedMap[T]) ReplaceEntries(entries []Entry[T]) error {
+	keys := make([]string, 0, len(entries))
+	values := make([]T, 0, len(entries))
+
+	for _, entry := range entries {
+		keys = append(keys, entry.Key)
+		values = append(values, entry.Value)
+	}
+
+	if err := m.validateAll(keys, values); err != nil {
+		return err
+	}
+
+	m.lock()
+	defer m.unlock()
+
+	m.load(keys, values)
+
+	return nil
+}
+
+// SortFunc reorders the entries of the map, stably, so that less(a, b) holds
+// for consecutive entries. Watchers, and the journal see it as a clear,
+// followed by the entries in their new order. The times of the additions are
+// kept, see WithTimestamps.
+func (m *SafeOrderedMap[T]) SortFunc(less func(a, b Entry[T]) bool) *SafeOrderedMap[T] {
+	m.lock()
+	defer m.unlock()
+
+	elements := m.elements()
+
+	sort.SliceStable(elements, func(i, j int) bool {
+		return less(Entry[T]{Key: elements[i].key, Value: elements[i].value}, Entry[T]{Key: elements[j].key, Value: elements[j].value})
+	})
+
+	m.reset()
+
+	for _, e := range elements {
+		m.put(e.key, e.value, e.added)
+	}
+
+	m.metrics.Operation("reorder")
+
+	return m
+}
+
+//////
+// Factory.
+//////
+
+// WithEntriesJSON makes MarshalJSON encode the map as an array of entries,
+// e.g.: [{"key":"b","value":2},{"key":"a","value":1}], which preserves the
+// order in any JSON parser, e.g.: JavaScript, where objects with integer-like
+// keys are reordered.
+func WithEntriesJSON[T any]() Option[T] {
+	return func(m *SafeOrderedMap[T]) {
+		m.entriesJSON = true
+	}
+}
+
+// FromEntries creates a new SafeOrderedMap with the given entries, in order.
+// If a key is repeated, the last value wins, and the key keeps its first
+// position.
+func FromEntries[T any](entries []Entry[T], opts ...Option[T]) *SafeOrderedMap[T] {
+	m := New[T](opts...)
+
+	for _, entry := range entries {
+		m.Add(entry.Key, entry.Value)
+	}
+
+	return m
+}
+
+//////
+// Exported Functionalities.
+//////
+
+// isJSONArray checks if the JSON value is an array.
+func isJSONArray(data []byte) bool {
+	data = bytes.TrimLeft(data, " \t\r\n")
+
+	return len(data) > 0 && data[0] == '['
+}
@@ -0,0 +1,83 @@
+package safeorderedmap
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSafeOrderedMapEntries(t *testing.T) {
+	m := New[int]()
+	m.Add("b", 2).Add("a", 1)
+
+	entries := m.Entries()
+
+	assert.Equal(t, []Entry[int]{{Key: "b", Value: 2}, {Key: "a", Value: 1}}, entries)
+
+	entries = append(entries, Entry[int]{Key: "b", Value: 3})
+
+	fromEntries := FromEntries(entries)
+
+	assert.Equal(t, []string{"b", "a"}, fromEntries.Keys())
+	assert.Equal(t, []int{3, 1}, fromEntries.Values())
+	assert.Empty(t, New[int]().Entries())
+}
+
+func TestSafeOrderedMapReplaceEntries(t *testing.T) {
+	m := New(WithValidator(func(key string, value int) error {
+		if value < 0 {
+			return errors.New("negative")
+		}
+
+		return nil
+	}))
+	m.Add("a", 1).Add("b", 2)
+
+	assert.NoError(t, m.ReplaceEntries([]Entry[int]{{Key: "c", Value: 3}, {Key: "a", Value: 4}}))
+	assert.Equal(t, []string{"c", "a"}, m.Keys())
+	assert.Equal(t, []int{3, 4}, m.Values())
+
+	// Invalid entries leave the map untouched.
+	assert.Error(t, m.ReplaceEntries([]Entry[int]{{Key: "d", Value: -1}}))
+	assert.Equal(t, []string{"c", "a"}, m.Keys())
+
+	assert.NoError(t, m.ReplaceEntries(nil))
+	assert.True(t, m.Empty())
+}
+
+func TestSafeOrderedMapEntriesJSON(t *testing.T) {
+	m := New[int](WithEntriesJSON[int]())
+	m.Add("2", 2).Add("1", 1)
+
+	b, err := json.Marshal(m)
+	assert.NoError(t, err)
+	assert.Equal(t, `[{"key":"2","value":2},{"key":"1","value":1}]`, string(b))
+
+	decoded := New[int]()
+	assert.NoError(t, json.Unmarshal(b, decoded))
+	assert.Equal(t, []string{"2", "1"}, decoded.Keys())
+
+	b, err = json.Marshal(decoded)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"1":1,"2":2}`, string(b))
+
+	assert.NoError(t, json.Unmarshal([]byte(` [{"key":"x","value":9}]`), m))
+	assert.Equal(t, []string{"x"}, m.Keys())
+
+	assert.Error(t, json.Unmarshal([]byte(`[{"key":"x","value":"nine"}]`), m))
+	assert.Equal(t, []string{"x"}, m.Keys())
+}
+
+func TestSafeOrderedMapSortFunc(t *testing.T) {
+	m := New[int]()
+	m.Add("c", 1).Add("a", 2).Add("b", 1)
+
+	m.SortFunc(func(a, b Entry[int]) bool { return a.Value < b.Value })
+	assert.Equal(t, []string{"c", "b", "a"}, m.Keys())
+
+	m.SortFunc(func(a, b Entry[int]) bool { return a.Key < b.Key })
+	assert.Equal(t, []string{"a", "b", "c"}, m.Keys())
+	assert.Equal(t, []int{2, 1, 1}, m.Values())
+}
@@ -0,0 +1,52 @@
+package safeorderedmap
+
+import "github.com/thalesfsp/go-common-types/shared"
+
+//////
+// Methods.
+//////
+
+// slot returns the key under which the key is indexed: the key itself, or its
+// folded form, see WithKeyFolding.
+func (m *SafeOrderedMap[T]) slot(key string) string {
+	if m.fold == nil {
+		return key
+	}
+
+	return m.fold(key)
+}
+
+// derive returns a new, empty, map with the same key folding, timestamps, and
+// snapshots, for the results of operations such as Clone, Filter, or Union.
+func (m *SafeOrderedMap[T]) derive() *SafeOrderedMap[T] {
+	derived := New[T]()
+
+	derived.fold = m.fold
+	derived.now = m.now
+	derived.snapshots = m.snapshots
+
+	return derived
+}
+
+//////
+// Factory.
+//////
+
+// WithKeyFolding sets the function folding keys before they're matched, so
+// keys with the same folded form are the same key, e.g.: on Add, Get,
+// Contains, or Delete. Keys keep the casing of their first addition for
+// output, e.g.: Keys, and JSON. Maps derived from the map, e.g.: by Clone,
+// Filter, or Union, keep it.
+//
+// NOTE: Namespace prefixes are matched as is, see Namespace.
+func WithKeyFolding[T any](fold func(key string) string) Option[T] {
+	return func(m *SafeOrderedMap[T]) {
+		m.fold = fold
+	}
+}
+
+// WithCaseInsensitiveKeys makes keys case-insensitive, e.g.: for HTTP
+// headers, see WithKeyFolding, and shared.FoldCase.
+func WithCaseInsensitiveKeys[T any]() Option[T] {
+	return WithKeyFolding[T](shared.FoldCase)
+}
@@ -0,0 +1,55 @@
+package safeorderedmap
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithCaseInsensitiveKeys(t *testing.T) {
+	m := New(WithCaseInsensitiveKeys[string]())
+
+	m.Add("Content-Type", "text/plain").Add("X-Request-ID", "1").Add("content-type", "application/json")
+
+	assert.Equal(t, 2, m.Size())
+	assert.Equal(t, []string{"Content-Type", "X-Request-ID"}, m.Keys())
+
+	value, ok := m.Get("CONTENT-TYPE")
+
+	assert.True(t, ok)
+	assert.Equal(t, "application/json", value)
+	assert.True(t, m.Contains("x-request-id"))
+	assert.False(t, m.SetIfAbsent("X-REQUEST-ID", "2"))
+
+	data, err := json.Marshal(m)
+
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"Content-Type":"application/json","X-Request-ID":"1"}`, string(data))
+
+	clone := m.Filter(func(_ string, _ string) bool { return true })
+
+	assert.True(t, clone.Contains("content-type"))
+
+	assert.NoError(t, m.RenameKey("x-request-id", "X-Request-Id"))
+	assert.Equal(t, []string{"Content-Type", "X-Request-Id"}, m.Keys())
+
+	assert.True(t, m.Remove("CONTENT-TYPE"))
+	assert.Equal(t, 1, m.Size())
+
+	ns := New(WithCaseInsensitiveKeys[string]())
+	tenant := ns.Namespace("a/")
+
+	ns.Add("a/Key", "v")
+
+	assert.True(t, tenant.Contains("KEY"))
+}
+
+func TestWithKeyFolding(t *testing.T) {
+	m := New(WithKeyFolding[int](func(key string) string { return key[:1] }))
+
+	m.Add("apple", 1).Add("avocado", 2).Add("banana", 3)
+
+	assert.Equal(t, []string{"apple", "banana"}, m.Keys())
+	assert.Equal(t, []int{2, 3}, m.Values())
+}
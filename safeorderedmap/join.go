@@ -0,0 +1,65 @@
+package safeorderedmap
+
+//////
+// Exported functionalities.
+//////
+
+// InnerJoin returns a map of the keys present in both maps, in the order of
+// left, with the values combined by combine, e.g.: to join users with their
+// accounts by ID. Keys are looked up in right with its key folding, and the
+// result has the one of left, see WithKeyFolding.
+func InnerJoin[L, R, V any](
+	left *SafeOrderedMap[L],
+	right *SafeOrderedMap[R],
+	combine func(key string, l L, r R) V,
+) *SafeOrderedMap[V] {
+	result := New[V]()
+
+	result.fold = left.fold
+
+	for _, entry := range left.Entries() {
+		if r, ok := right.Peek(entry.Key); ok {
+			result.Add(entry.Key, combine(entry.Key, entry.Value, r))
+		}
+	}
+
+	return result
+}
+
+// LeftJoin returns a map of the keys of left, in order, with the values
+// combined by combine, which is called with the zero R, and ok false, for the
+// keys missing from right. Key folding applies like with InnerJoin.
+func LeftJoin[L, R, V any](
+	left *SafeOrderedMap[L],
+	right *SafeOrderedMap[R],
+	combine func(key string, l L, r R, ok bool) V,
+) *SafeOrderedMap[V] {
+	result := New[V]()
+
+	result.fold = left.fold
+
+	for _, entry := range left.Entries() {
+		r, ok := right.Peek(entry.Key)
+
+		result.Add(entry.Key, combine(entry.Key, entry.Value, r, ok))
+	}
+
+	return result
+}
+
+//////
+// Factory.
+//////
+
+// KeyBy creates a new SafeOrderedMap with the items, in order, keyed by
+// keyFn, e.g.: a lookup table of API results by ID. If a key is repeated, the
+// last item wins, and the key keeps its first position.
+func KeyBy[T any](items []T, keyFn func(item T) string, opts ...Option[T]) *SafeOrderedMap[T] {
+	m := New[T](opts...)
+
+	for _, item := range items {
+		m.Add(keyFn(item), item)
+	}
+
+	return m
+}
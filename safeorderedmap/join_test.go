@@ -0,0 +1,54 @@
+package safeorderedmap
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type joinUser struct {
+	ID   string
+	Name string
+}
+
+func TestKeyBy(t *testing.T) {
+	m := KeyBy([]joinUser{{"1", "bob"}, {"2", "alice"}, {"1", "robert"}}, func(u joinUser) string {
+		return u.ID
+	})
+
+	assert.Equal(t, []string{"1", "2"}, m.Keys())
+	assert.Equal(t, []joinUser{{"1", "robert"}, {"2", "alice"}}, m.Values())
+}
+
+func TestJoins(t *testing.T) {
+	users := KeyBy([]joinUser{{"1", "bob"}, {"2", "alice"}, {"3", "carol"}}, func(u joinUser) string {
+		return u.ID
+	})
+
+	balances := New[int]().Add("3", 30).Add("1", 10).Add("4", 40)
+
+	inner := InnerJoin(users, balances, func(_ string, u joinUser, balance int) string {
+		return fmt.Sprint(u.Name, ":", balance)
+	})
+
+	assert.Equal(t, []string{"1", "3"}, inner.Keys())
+	assert.Equal(t, []string{"bob:10", "carol:30"}, inner.Values())
+
+	left := LeftJoin(users, balances, func(_ string, u joinUser, balance int, ok bool) string {
+		if !ok {
+			return u.Name + ":none"
+		}
+
+		return fmt.Sprint(u.Name, ":", balance)
+	})
+
+	assert.Equal(t, []string{"bob:10", "alice:none", "carol:30"}, left.Values())
+
+	// Keys are looked up in right with its folding.
+	folded := New(WithCaseInsensitiveKeys[int]()).Add("A", 2)
+
+	assert.Equal(t, []int{3}, InnerJoin(New[int]().Add("a", 1), folded, func(_ string, l, r int) int {
+		return l + r
+	}).Values())
+}
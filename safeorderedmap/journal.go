@@ -0,0 +1,168 @@
+package safeorderedmap
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+)
+
+//////
+// Const, vars, and types.
+//////
+
+// Journal operations.
+const (
+	journalOpAdd    = "add"
+	journalOpDelete = "delete"
+	journalOpClear  = "clear"
+	journalOpRename = "rename"
+)
+
+// journalRecord is a record of the journal, one JSON document per line.
+type journalRecord[T any] struct {
+	Op    string `json:"op"`
+	Key   string `json:"key,omitempty"`
+	Value *T     `json:"value,omitempty"`
+	To    string `json:"to,omitempty"`
+}
+
+// journal is an append-only log of the mutations of the map. A nil journal is
+// a no-op. It's only written while holding the write lock of the map, so
+// records are in the same order as the mutations.
+type journal[T any] struct {
+	encoder *json.Encoder
+
+	errMu sync.Mutex
+	err   error
+}
+
+//////
+// Methods.
+//////
+
+// write writes the record, keeping the first error.
+func (j *journal[T]) write(record journalRecord[T]) {
+	if j == nil {
+		return
+	}
+
+	if err := j.encoder.Encode(record); err != nil {
+		j.errMu.Lock()
+		defer j.errMu.Unlock()
+
+		if j.err == nil {
+			j.err = err
+		}
+	}
+}
+
+// add records an add operation.
+func (j *journal[T]) add(key string, value T) {
+	j.write(journalRecord[T]{Op: journalOpAdd, Key: key, Value: &value})
+}
+
+// delete records a delete operation.
+func (j *journal[T]) delete(key string) {
+	j.write(journalRecord[T]{Op: journalOpDelete, Key: key})
+}
+
+// rename records a rename operation.
+func (j *journal[T]) rename(key, to string) {
+	j.write(journalRecord[T]{Op: journalOpRename, Key: key, To: to})
+}
+
+// clear records a clear operation.
+func (j *journal[T]) clear() {
+	j.write(journalRecord[T]{Op: journalOpClear})
+}
+
+// JournalErr returns the first error which happened writing to the journal,
+// if any. Mutations are applied to the map even if they can't be journaled.
+func (m *SafeOrderedMap[T]) JournalErr() error {
+	if m.journal == nil {
+		return nil
+	}
+
+	m.journal.errMu.Lock()
+	defer m.journal.errMu.Unlock()
+
+	return m.journal.err
+}
+
+// Replay reconstructs the map by applying the operations of a journal, in
+// order, on top of the current content of the map. Replayed operations aren't
+// journaled again.
+//
+// If the journal ends with a partially written record, e.g.: after a crash,
+// all complete records are applied, and io.ErrUnexpectedEOF is returned.
+func (m *SafeOrderedMap[T]) Replay(r io.Reader) error {
+	m.lock()
+	defer m.unlock()
+
+	j := m.journal
+
+	m.journal = nil
+
+	defer func() {
+		m.journal = j
+
+		m.metrics.Operation("replay")
+		m.metrics.SetSize(len(m.data))
+	}()
+
+	decoder := m.decoder(r)
+
+	for {
+		var record journalRecord[T]
+
+		if err := decoder.Decode(&record); err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+
+			return err
+		}
+
+		switch record.Op {
+		case journalOpAdd:
+			if record.Value == nil {
+				record.Value = new(T)
+			}
+
+			m.set(record.Key, *record.Value)
+		case journalOpDelete:
+			if e, ok := m.data[m.slot(record.Key)]; ok {
+				m.unlink(e)
+			}
+		case journalOpClear:
+			m.discard()
+		case journalOpRename:
+			if e, ok := m.data[m.slot(record.Key)]; ok {
+				m.rename(e, record.To)
+			}
+		default:
+			return fmt.Errorf("invalid journal operation %q", record.Op)
+		}
+	}
+}
+
+//////
+// Factory.
+//////
+
+// WithJournal enables journaling: every mutation (add, delete, and clear) is
+// appended to w as a JSON record, one per line. The map can be reconstructed
+// from the journal with Replay, giving crash recovery semantics when w is a
+// file opened for appending.
+//
+// NOTE: Records are written synchronously, while holding the write lock. For
+// durability, w is responsible for syncing, e.g.: calling Sync on the file.
+func WithJournal[T any](w io.Writer) Option[T] {
+	return func(m *SafeOrderedMap[T]) {
+		m.journal = &journal[T]{
+			encoder: json.NewEncoder(w),
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package safeorderedmap
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSafeOrderedMapJournalReplay(t *testing.T) {
+	var buf bytes.Buffer
+
+	m := New[int](WithJournal[int](&buf))
+	m.Add("a", 1).Add("b", 2).Add("c", 3).Add("a", 10).Delete("b")
+
+	assert.NoError(t, m.JournalErr())
+	assert.Equal(t, 5, strings.Count(buf.String(), "\n"))
+
+	replayed := New[int]()
+	assert.NoError(t, replayed.Replay(bytes.NewReader(buf.Bytes())))
+	assert.Equal(t, []string{"a", "c"}, replayed.Keys())
+	assert.Equal(t, []int{10, 3}, replayed.Values())
+
+	m.Clear()
+	m.Add("z", 26)
+
+	replayed = New[int]()
+	assert.NoError(t, replayed.Replay(bytes.NewReader(buf.Bytes())))
+	assert.Equal(t, []string{"z"}, replayed.Keys())
+}
+
+func TestSafeOrderedMapReplayDoesNotJournal(t *testing.T) {
+	var src, dst bytes.Buffer
+
+	New[string](WithJournal[string](&src)).Add("a", "x")
+
+	m := New[string](WithJournal[string](&dst))
+	assert.NoError(t, m.Replay(&src))
+	assert.Equal(t, 0, dst.Len())
+
+	m.Add("b", "y")
+	assert.NotEqual(t, 0, dst.Len())
+}
+
+func TestSafeOrderedMapReplayTruncated(t *testing.T) {
+	var buf bytes.Buffer
+
+	New[int](WithJournal[int](&buf)).Add("a", 1).Add("b", 2)
+
+	truncated := buf.Bytes()[:buf.Len()-5]
+
+	m := New[int]()
+	assert.ErrorIs(t, m.Replay(bytes.NewReader(truncated)), io.ErrUnexpectedEOF)
+	assert.Equal(t, []string{"a"}, m.Keys())
+
+	assert.Error(t, m.Replay(strings.NewReader(`{"op":"noop"}`)))
+}
+
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) { return 0, errors.New("disk full") }
+
+func TestSafeOrderedMapJournalErr(t *testing.T) {
+	m := New[int](WithJournal[int](failingWriter{}))
+	m.Add("a", 1)
+
+	assert.EqualError(t, m.JournalErr(), "disk full")
+	assert.Equal(t, 1, m.Size())
+	assert.NoError(t, New[int]().JournalErr())
+}
+
+func TestSafeOrderedMapJournalRename(t *testing.T) {
+	var buf bytes.Buffer
+
+	m := New[int](WithJournal[int](&buf))
+	m.Add("a", 1).Add("b", 2)
+
+	assert.NoError(t, m.RenameKey("a", "z"))
+
+	replayed := New[int]()
+	assert.NoError(t, replayed.Replay(&buf))
+	assert.Equal(t, []string{"z", "b"}, replayed.Keys())
+}
@@ -0,0 +1,239 @@
+package safeorderedmap
+
+import "errors"
+
+//////
+// Const, vars, and types.
+//////
+
+// Loader loads the value of a key missing from the map from a backing store.
+// It should return ErrKeyNotFound if the key isn't in the store either.
+type Loader[T any] func(key string) (T, error)
+
+// Writer writes an entry added to the map to a backing store.
+type Writer[T any] func(key string, value T) error
+
+//////
+// Methods.
+//////
+
+// write writes the entry through, if the map has a writer.
+func (m *SafeOrderedMap[T]) write(key string, value T) error {
+	if m.writer == nil {
+		return nil
+	}
+
+	if err := m.writer(key, value); err != nil {
+		m.metrics.Operation("writeError")
+
+		return err
+	}
+
+	return nil
+}
+
+// writeThrough writes the entry with the writer, if the map has one, without
+// holding the lock, so a slow store doesn't block the readers, and the other
+// mutations of the map, and then calls apply holding the write lock. check,
+// if set, is called holding the lock before writing, and again before apply:
+// if it fails, the entry isn't written, nor applied. Mutations writing
+// through are serialized, so they reach the store in the same order as the
+// map.
+func (m *SafeOrderedMap[T]) writeThrough(key string, value T, check func() error, apply func()) error {
+	if check == nil {
+		check = func() error { return nil }
+	}
+
+	if m.writer != nil {
+		m.writing.Lock()
+		defer m.writing.Unlock()
+
+		m.rlock()
+		err := check()
+		m.runlock()
+
+		if err != nil {
+			return err
+		}
+
+		if err := m.write(key, value); err != nil {
+			return err
+		}
+	}
+
+	m.lock()
+	defer m.unlock()
+
+	if err := check(); err != nil {
+		return err
+	}
+
+	apply()
+
+	return nil
+}
+
+// dropped records the error which made a method without an error result drop
+// an entry, keeping the first one, see DropErr.
+func (m *SafeOrderedMap[T]) dropped(err error) {
+	if err == nil {
+		return
+	}
+
+	m.metrics.Operation("drop")
+
+	m.dropMu.Lock()
+	defer m.dropMu.Unlock()
+
+	if m.dropErr == nil {
+		m.dropErr = err
+	}
+}
+
+// DropErr returns the first error of the validators, or the writer, which
+// made Add, Set, SetIfAbsent, Replace, or Swap drop an entry, if any, see
+// WithValidator, and WithWriter. Use AddE, or ReplaceKey to handle the error
+// of each entry.
+func (m *SafeOrderedMap[T]) DropErr() error {
+	m.dropMu.Lock()
+	defer m.dropMu.Unlock()
+
+	return m.dropErr
+}
+
+// fetch loads a missing key with the loader, storing it.
+func (m *SafeOrderedMap[T]) fetch(key string) (T, error) {
+	return m.compute(key, func() (T, error) {
+		return m.loader(key)
+	}, false)
+}
+
+// compute computes the value of a missing key with fn, storing it, and writing
+// it through if write is set. Concurrent computations of the same key, or of
+// keys folded to it, are de-duplicated.
+func (m *SafeOrderedMap[T]) compute(key string, fn func() (T, error), write bool) (T, error) {
+	value, err, _ := m.flights.Do(m.slot(key), func() (T, error) {
+		// The key may have been stored while waiting for the flight.
+		if value, ok := m.Peek(key); ok {
+			return value, nil
+		}
+
+		value, err := fn()
+		if err != nil {
+			return *new(T), err
+		}
+
+		if err := m.validate(key, value); err != nil {
+			return *new(T), err
+		}
+
+		// Values added while computing win over computed ones.
+		absent := func() error {
+			if e, ok := m.data[m.slot(key)]; ok {
+				value = e.value
+
+				return ErrKeyExists
+			}
+
+			return nil
+		}
+
+		store := func() {
+			m.set(key, value)
+
+			m.metrics.Operation("load")
+			m.metrics.SetSize(len(m.data))
+		}
+
+		if write {
+			err = m.writeThrough(key, value, absent, store)
+		} else {
+			m.lock()
+			defer m.unlock()
+
+			if err = absent(); err == nil {
+				store()
+			}
+		}
+
+		if err != nil && !errors.Is(err, ErrKeyExists) {
+			return *new(T), err
+		}
+
+		return value, nil
+	})
+
+	return value, err
+}
+
+// Do returns the value of the key. If it's missing, it's computed by fn, and
+// stored, unless fn returns an error, which isn't cached. Only one goroutine
+// calls fn per key at a time, concurrent callers wait, and share its result,
+// including an in-flight load, see WithLoader. Computed values are validated,
+// and written through, like with AddE.
+func (m *SafeOrderedMap[T]) Do(key string, fn func() (T, error)) (T, error) {
+	if value, ok := m.get(key); ok {
+		return value, nil
+	}
+
+	return m.compute(key, fn, true)
+}
+
+// Peek is like Get, without loading the value if it's missing, nor recording
+// metrics, e.g.: for lookups internal to a wrapping collection.
+func (m *SafeOrderedMap[T]) Peek(key string) (T, bool) {
+	m.rlock()
+	defer m.runlock()
+
+	if e, ok := m.data[m.slot(key)]; ok {
+		return e.value, true
+	}
+
+	return *new(T), false
+}
+
+// GetE is like Get, returning the error of the loader, or ErrKeyNotFound if
+// the key is missing, and the map has no loader.
+func (m *SafeOrderedMap[T]) GetE(key string) (T, error) {
+	if value, ok := m.get(key); ok {
+		return value, nil
+	}
+
+	if m.loader == nil {
+		return *new(T), ErrKeyNotFound
+	}
+
+	return m.fetch(key)
+}
+
+//////
+// Factory.
+//////
+
+// WithLoader makes Get, and GetE read-through: a missing key is loaded with
+// the loader, and stored in the map. Concurrent loads of the same key are
+// de-duplicated, only one of them calls the loader, the others share its
+// result. Errors aren't cached.
+func WithLoader[T any](loader Loader[T]) Option[T] {
+	return func(m *SafeOrderedMap[T]) {
+		m.loader = loader
+	}
+}
+
+// WithWriter makes Add, AddE, Set, SetIfAbsent, Replace, Swap, ReplaceKey,
+// and Do write-through: the entry is written with the writer before it's
+// stored in the map. The writer is called without holding the lock, so a slow
+// store doesn't block readers, but these mutations are serialized, so writes
+// reach the store in the same order as the map, and one slow write delays
+// the next ones. ApplyPatch writes holding the lock, to apply the patch
+// atomically.
+//
+// If the writer fails, the map is left untouched: AddE, and ReplaceKey return
+// the error, which the other methods record, see DropErr. Prefer AddE, so
+// failed writes aren't lost silently. Loaded values, deletions, and
+// unmarshalling aren't written.
+func WithWriter[T any](writer Writer[T]) Option[T] {
+	return func(m *SafeOrderedMap[T]) {
+		m.writer = writer
+	}
+}
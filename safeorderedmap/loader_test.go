@@ -0,0 +1,237 @@
+package safeorderedmap
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithLoader(t *testing.T) {
+	store := map[string]int{"a": 1, "b": 2}
+
+	var loads int32
+
+	m := New(WithLoader(func(key string) (int, error) {
+		atomic.AddInt32(&loads, 1)
+
+		// Gives concurrent callers time to join the load.
+		time.Sleep(20 * time.Millisecond)
+
+		value, ok := store[key]
+		if !ok {
+			return 0, ErrKeyNotFound
+		}
+
+		return value, nil
+	}))
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			value, ok := m.Get("a")
+			assert.True(t, ok)
+			assert.Equal(t, 1, value)
+		}()
+	}
+
+	wg.Wait()
+
+	assert.Equal(t, int32(1), atomic.LoadInt32(&loads))
+	assert.Equal(t, []string{"a"}, m.Keys())
+
+	// Loaded keys are served from the map.
+	value, err := m.GetE("a")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, value)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&loads))
+
+	_, err = m.GetE("z")
+	assert.ErrorIs(t, err, ErrKeyNotFound)
+
+	_, ok := m.Get("z")
+	assert.False(t, ok)
+	assert.False(t, m.Contains("z"))
+
+	_, err = New[int]().GetE("a")
+	assert.ErrorIs(t, err, ErrKeyNotFound)
+}
+
+func TestWithLoaderCaseInsensitive(t *testing.T) {
+	var loads int32
+
+	m := New(WithCaseInsensitiveKeys[int](), WithLoader(func(key string) (int, error) {
+		n := atomic.AddInt32(&loads, 1)
+
+		// Gives concurrent callers time to join the load.
+		time.Sleep(20 * time.Millisecond)
+
+		return int(n), nil
+	}))
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+
+		go func(key string) {
+			defer wg.Done()
+
+			value, ok := m.Get(key)
+			assert.True(t, ok)
+			assert.Equal(t, 1, value)
+		}([]string{"Foo", "foo"}[i%2])
+	}
+
+	wg.Wait()
+
+	assert.Equal(t, int32(1), atomic.LoadInt32(&loads))
+	assert.Equal(t, 1, m.Size())
+}
+
+func TestWithWriter(t *testing.T) {
+	errReadOnly := errors.New("read-only")
+
+	store := map[string]int{}
+
+	m := New(WithWriter(func(key string, value int) error {
+		if key == "ro" {
+			return errReadOnly
+		}
+
+		store[key] = value
+
+		return nil
+	}))
+
+	m.Add("a", 1)
+	m.Set("b", 2)
+	m.Add("ro", 3)
+
+	assert.NoError(t, m.AddE("c", 3))
+	assert.ErrorIs(t, m.AddE("ro", 3), errReadOnly)
+
+	assert.True(t, m.SetIfAbsent("d", 4))
+	assert.False(t, m.SetIfAbsent("ro", 4))
+
+	assert.NoError(t, m.ReplaceKey("d", "e", 5))
+	assert.ErrorIs(t, m.ReplaceKey("e", "ro", 5), errReadOnly)
+
+	assert.Equal(t, []string{"a", "b", "c", "e"}, m.Keys())
+	assert.Equal(t, map[string]int{"a": 1, "b": 2, "c": 3, "d": 4, "e": 5}, store)
+
+	// Dropped entries are recorded.
+	assert.ErrorIs(t, m.DropErr(), errReadOnly)
+	assert.NoError(t, New[int]().Add("a", 1).DropErr())
+}
+
+func TestWithWriterUnlocked(t *testing.T) {
+	var (
+		started = make(chan struct{})
+		release = make(chan struct{})
+	)
+
+	m := New(WithWriter(func(key string, value int) error {
+		if key == "slow" {
+			close(started)
+			<-release
+		}
+
+		return nil
+	}))
+
+	m.Add("a", 1)
+
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		assert.NoError(t, m.AddE("slow", 2))
+	}()
+
+	<-started
+
+	// The slow write doesn't block readers, nor mutations not writing through.
+	value, ok := m.Get("a")
+	assert.True(t, ok)
+	assert.Equal(t, 1, value)
+
+	m.Delete("a")
+	assert.False(t, m.Contains("slow"))
+
+	close(release)
+	<-done
+
+	assert.Equal(t, []string{"slow"}, m.Keys())
+}
+
+func TestSafeOrderedMapDo(t *testing.T) {
+	m := New[int]()
+
+	var (
+		calls int32
+		wg    sync.WaitGroup
+	)
+
+	compute := func() (int, error) {
+		atomic.AddInt32(&calls, 1)
+
+		// Gives concurrent callers time to join the computation.
+		time.Sleep(20 * time.Millisecond)
+
+		return 42, nil
+	}
+
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			value, err := m.Do("a", compute)
+			assert.NoError(t, err)
+			assert.Equal(t, 42, value)
+		}()
+	}
+
+	wg.Wait()
+
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+
+	value, ok := m.Get("a")
+	assert.True(t, ok)
+	assert.Equal(t, 42, value)
+
+	// Present keys aren't computed.
+	value, err := m.Do("a", compute)
+	assert.NoError(t, err)
+	assert.Equal(t, 42, value)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+
+	// Errors aren't cached.
+	errBoom := errors.New("boom")
+
+	_, err = m.Do("b", func() (int, error) { return 0, errBoom })
+	assert.ErrorIs(t, err, errBoom)
+	assert.False(t, m.Contains("b"))
+
+	value, err = m.Do("b", func() (int, error) { return 2, nil })
+	assert.NoError(t, err)
+	assert.Equal(t, 2, value)
+
+	// Computed values are validated.
+	v := New(WithValidator(positive))
+
+	_, err = v.Do("c", func() (int, error) { return -1, nil })
+	assert.ErrorIs(t, err, errNegative)
+	assert.False(t, v.Contains("c"))
+}
@@ -0,0 +1,235 @@
+package safeorderedmap
+
+import (
+	"strings"
+	"time"
+)
+
+//////
+// Const, vars, and types.
+//////
+
+// Namespace is a live view of the entries of a map with keys having a
+// prefix, without it, see SafeOrderedMap.Namespace. It holds no entries: its
+// operations apply to the map, with the prefix added to the keys.
+type Namespace[T any] struct {
+	m      *SafeOrderedMap[T]
+	prefix string
+}
+
+//////
+// Methods.
+//////
+
+// scoped returns the entries with keys having the prefix, without it, in
+// order.
+func (m *SafeOrderedMap[T]) scoped(prefix string) []Entry[T] {
+	entries := []Entry[T]{}
+
+	if m.snapshotted() {
+		s := m.view()
+
+		for i, key := range s.keys {
+			if key, ok := strings.CutPrefix(key, prefix); ok {
+				entries = append(entries, Entry[T]{Key: key, Value: s.values[i]})
+			}
+		}
+
+		return entries
+	}
+
+	m.rlock()
+	defer m.runlock()
+
+	for e := m.head; e != nil; e = e.next {
+		if key, ok := strings.CutPrefix(e.key, prefix); ok {
+			entries = append(entries, Entry[T]{Key: key, Value: e.value})
+		}
+	}
+
+	return entries
+}
+
+// Prefix returns the prefix of the keys of the namespace, in the map.
+func (n *Namespace[T]) Prefix() string {
+	return n.prefix
+}
+
+// Add a value, keeping the position of existing keys.
+func (n *Namespace[T]) Add(key string, value T) *Namespace[T] {
+	n.m.Add(n.prefix+key, value)
+
+	return n
+}
+
+// AddE is like Add, returning the error of the validators, or the writer.
+func (n *Namespace[T]) AddE(key string, value T) error {
+	return n.m.AddE(n.prefix+key, value)
+}
+
+// Set a value, keeping the position of existing keys.
+func (n *Namespace[T]) Set(key string, value T) {
+	n.m.Set(n.prefix+key, value)
+}
+
+// SetIfAbsent adds the value if the key isn't present, returning whether it
+// was added.
+func (n *Namespace[T]) SetIfAbsent(key string, value T) bool {
+	return n.m.SetIfAbsent(n.prefix+key, value)
+}
+
+// Swap sets the value, returning the previous one, and whether it was present.
+func (n *Namespace[T]) Swap(key string, value T) (T, bool) {
+	return n.m.Swap(n.prefix+key, value)
+}
+
+// Get a value. If the map has a loader, it's called with the key of the map.
+func (n *Namespace[T]) Get(key string) (T, bool) {
+	return n.m.Get(n.prefix + key)
+}
+
+// GetE is like Get, returning the error of the loader, or ErrKeyNotFound.
+func (n *Namespace[T]) GetE(key string) (T, error) {
+	return n.m.GetE(n.prefix + key)
+}
+
+// Do returns the value of the key, computing it with fn if it's missing, see
+// SafeOrderedMap.Do.
+func (n *Namespace[T]) Do(key string, fn func() (T, error)) (T, error) {
+	return n.m.Do(n.prefix+key, fn)
+}
+
+// Contains checks if the key is present.
+func (n *Namespace[T]) Contains(key string) bool {
+	return n.m.Contains(n.prefix + key)
+}
+
+// AddedAt returns the time the key was added, see WithTimestamps.
+func (n *Namespace[T]) AddedAt(key string) (time.Time, bool) {
+	return n.m.AddedAt(n.prefix + key)
+}
+
+// Delete a value.
+func (n *Namespace[T]) Delete(key string) *Namespace[T] {
+	n.m.Delete(n.prefix + key)
+
+	return n
+}
+
+// Remove a value, returning whether it was present.
+func (n *Namespace[T]) Remove(key string) bool {
+	return n.m.Remove(n.prefix + key)
+}
+
+// RenameKey renames a key, keeping its position, see SafeOrderedMap.RenameKey.
+func (n *Namespace[T]) RenameKey(oldKey, newKey string) error {
+	return n.m.RenameKey(n.prefix+oldKey, n.prefix+newKey)
+}
+
+// Clear removes the entries of the namespace from the map, atomically.
+func (n *Namespace[T]) Clear() *Namespace[T] {
+	n.m.DeletePrefix(n.prefix)
+
+	return n
+}
+
+// Keys returns the keys of the namespace, in order.
+func (n *Namespace[T]) Keys() []string {
+	entries := n.m.scoped(n.prefix)
+
+	keys := make([]string, len(entries))
+
+	for i, e := range entries {
+		keys[i] = e.Key
+	}
+
+	return keys
+}
+
+// Values returns the values of the namespace, in order.
+func (n *Namespace[T]) Values() []T {
+	entries := n.m.scoped(n.prefix)
+
+	values := make([]T, len(entries))
+
+	for i, e := range entries {
+		values[i] = e.Value
+	}
+
+	return values
+}
+
+// Entries returns the entries of the namespace, in order.
+func (n *Namespace[T]) Entries() []Entry[T] {
+	return n.m.scoped(n.prefix)
+}
+
+// Each calls f for each entry of the namespace, in order. Unlike
+// SafeOrderedMap.Each, f is called on a copy of the entries, without holding
+// the lock.
+func (n *Namespace[T]) Each(f func(key string, value T)) *Namespace[T] {
+	for _, e := range n.m.scoped(n.prefix) {
+		f(e.Key, e.Value)
+	}
+
+	return n
+}
+
+// Size returns the number of entries of the namespace. It's O(n), n being
+// the size of the map.
+func (n *Namespace[T]) Size() int {
+	return len(n.m.scoped(n.prefix))
+}
+
+// Empty checks if the namespace has no entries.
+func (n *Namespace[T]) Empty() bool {
+	return n.Size() == 0
+}
+
+// Clone returns a new map with the entries of the namespace, without the
+// prefix, with the key folding, timestamps, and snapshots of the map.
+func (n *Namespace[T]) Clone() *SafeOrderedMap[T] {
+	n.m.rlock()
+	defer n.m.runlock()
+
+	clone := n.m.derive()
+
+	for e := n.m.head; e != nil; e = e.next {
+		if key, ok := strings.CutPrefix(e.key, n.prefix); ok {
+			clone.put(key, e.value, e.added)
+		}
+	}
+
+	return clone
+}
+
+// Namespace returns a nested namespace, e.g.: "b/" in the "a/" namespace has
+// the "a/b/" prefix in the map.
+func (n *Namespace[T]) Namespace(prefix string) *Namespace[T] {
+	return &Namespace[T]{m: n.m, prefix: n.prefix + prefix}
+}
+
+// String returns the JSON representation of the namespace.
+func (n *Namespace[T]) String() string {
+	return n.Clone().String()
+}
+
+// MarshalJSON implements json.Marshaler, preserving the order of the keys.
+func (n *Namespace[T]) MarshalJSON() ([]byte, error) {
+	return n.Clone().MarshalJSON()
+}
+
+// Namespace returns a live view of the entries with keys having the prefix,
+// without it, e.g.: "b" in the "tenant-a/" namespace is "tenant-a/b" in the
+// map, so multi-tenant services can isolate tenants while sharing a single
+// map. Namespaces can be nested.
+//
+// The view only holds the map, and the prefix: reads, and writes go to the
+// map, with the prefix added to the keys, so changes to the map are visible
+// in the view, and the validators, writer, loader, disposer, journal, and
+// watchers of the map see the keys of the map. Operations listing the
+// entries, e.g.: Keys, or Size, filter the entries of the map by the prefix,
+// in O(n).
+func (m *SafeOrderedMap[T]) Namespace(prefix string) *Namespace[T] {
+	return &Namespace[T]{m: m, prefix: prefix}
+}
@@ -0,0 +1,147 @@
+package safeorderedmap
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNamespace(t *testing.T) {
+	m := New[int]()
+	m.Add("a/x", 1)
+	m.Add("b/x", 2)
+
+	a := m.Namespace("a/")
+
+	assert.Equal(t, a, m.Namespace("a/"))
+	assert.Equal(t, []string{"x"}, a.Keys())
+
+	// Changes to the view apply to the map.
+	a.Add("y", 3)
+	a.Delete("x")
+
+	assert.Equal(t, []string{"b/x", "a/y"}, m.Keys())
+
+	// Changes to the map are visible in the view.
+	m.Add("a/z", 4)
+	m.Add("c/z", 5)
+
+	assert.Equal(t, []string{"y", "z"}, a.Keys())
+	assert.Equal(t, 2, a.Size())
+
+	// Renames across namespaces move the entry.
+	assert.NoError(t, m.RenameKey("a/y", "b/y"))
+	assert.Equal(t, []string{"z"}, a.Keys())
+
+	assert.NoError(t, a.RenameKey("z", "w"))
+	assert.Equal(t, []string{"b/x", "b/y", "a/w", "c/z"}, m.Keys())
+
+	// Clearing the view only clears its scope.
+	a.Add("v", 6)
+	a.Clear()
+
+	assert.True(t, a.Empty())
+	assert.Equal(t, []string{"b/x", "b/y", "c/z"}, m.Keys())
+
+	// Clearing the map clears the view.
+	a.Add("v", 6)
+	m.Clear()
+
+	assert.True(t, a.Empty())
+}
+
+func TestNamespaceView(t *testing.T) {
+	m := New(WithTimestamps[int]())
+	m.Add("a/x", 1)
+	m.Add("b/x", 2)
+	m.Add("a/y", 3)
+
+	a := m.Namespace("a/")
+
+	assert.Equal(t, []Entry[int]{{Key: "x", Value: 1}, {Key: "y", Value: 3}}, a.Entries())
+	assert.Equal(t, []int{1, 3}, a.Values())
+
+	data, err := a.MarshalJSON()
+	assert.NoError(t, err)
+	assert.Equal(t, `{"x":1,"y":3}`, string(data))
+
+	// Clones are copies, without the prefix.
+	clone := a.Clone()
+	clone.Add("z", 4)
+
+	assert.Equal(t, []string{"x", "y", "z"}, clone.Keys())
+	assert.Equal(t, []string{"x", "y"}, a.Keys())
+
+	_, ok := clone.AddedAt("x")
+	assert.True(t, ok)
+
+	keys := []string{}
+
+	a.Each(func(key string, _ int) {
+		// The lock isn't held.
+		a.Delete(key)
+
+		keys = append(keys, key)
+	})
+
+	assert.Equal(t, []string{"x", "y"}, keys)
+	assert.Equal(t, []string{"b/x"}, m.Keys())
+}
+
+func TestNamespaceNested(t *testing.T) {
+	m := New[int]()
+
+	a := m.Namespace("a/")
+	ab := a.Namespace("b/")
+	other := m.Namespace("a/b/")
+
+	ab.Add("x", 1)
+
+	assert.Equal(t, []string{"a/b/x"}, m.Keys())
+	assert.Equal(t, []string{"b/x"}, a.Keys())
+	assert.Equal(t, []string{"x"}, other.Keys())
+
+	other.Delete("x")
+
+	assert.True(t, m.Empty())
+	assert.True(t, a.Empty())
+	assert.True(t, ab.Empty())
+}
+
+func TestNamespaceInherits(t *testing.T) {
+	written := []string{}
+
+	m := New(
+		WithValidator(func(key string, value int) error {
+			if value < 0 {
+				return errors.New("negative")
+			}
+
+			return nil
+		}),
+		WithWriter(func(key string, value int) error {
+			written = append(written, key)
+
+			return nil
+		}),
+		WithLoader(func(key string) (int, error) {
+			if key == "a/loaded" {
+				return 7, nil
+			}
+
+			return 0, ErrKeyNotFound
+		}),
+	)
+
+	a := m.Namespace("a/")
+
+	assert.Error(t, a.AddE("x", -1))
+	assert.NoError(t, a.AddE("x", 1))
+	assert.Equal(t, []string{"a/x"}, written)
+
+	v, ok := a.Get("loaded")
+	assert.True(t, ok)
+	assert.Equal(t, 7, v)
+	assert.True(t, m.Contains("a/loaded"))
+}
@@ -0,0 +1,238 @@
+package safeorderedmap
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+//////
+// Const, vars, and types.
+//////
+
+// Patch operations, as in RFC 6902.
+const (
+	PatchAdd     = "add"
+	PatchReplace = "replace"
+	PatchRemove  = "remove"
+)
+
+// ErrInvalidPatch is returned when a patch can't be decoded, or applied, e.g.:
+// an unknown operation, or replacing a missing key.
+var ErrInvalidPatch = errors.New("invalid patch")
+
+// Operation is an operation of a Patch, encoded in JSON like RFC 6902, with
+// the key as a JSON pointer, e.g.: {"op":"add","path":"/a~1b","value":1} for
+// the key "a/b".
+type Operation[T any] struct {
+	Op    string
+	Key   string
+	Value T
+}
+
+// operationJSON is the JSON representation of an Operation.
+type operationJSON[T any] struct {
+	Op    string `json:"op"`
+	Path  string `json:"path"`
+	Value *T     `json:"value,omitempty"`
+}
+
+// Patch is a list of operations transforming a map into another, in order,
+// see Diff, and ApplyPatch. It's encoded in JSON as an array, like RFC 6902.
+type Patch[T any] []Operation[T]
+
+//////
+// Methods.
+//////
+
+// MarshalJSON implements the json.Marshaler interface. The value is only
+// present for add, and replace.
+func (o Operation[T]) MarshalJSON() ([]byte, error) {
+	op := operationJSON[T]{Op: o.Op, Path: "/" + escapePointer(o.Key)}
+
+	if o.Op != PatchRemove {
+		op.Value = &o.Value
+	}
+
+	return json.Marshal(op)
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface. The path must point
+// to a top-level key, e.g.: "/a", not "/a/b".
+func (o *Operation[T]) UnmarshalJSON(data []byte) error {
+	var op operationJSON[T]
+
+	if err := json.Unmarshal(data, &op); err != nil {
+		return err
+	}
+
+	switch op.Op {
+	case PatchAdd, PatchReplace, PatchRemove:
+	default:
+		return fmt.Errorf("%w: unknown operation %q", ErrInvalidPatch, op.Op)
+	}
+
+	pointer, ok := strings.CutPrefix(op.Path, "/")
+	if !ok || strings.Contains(pointer, "/") {
+		return fmt.Errorf("%w: path %q isn't a top-level key", ErrInvalidPatch, op.Path)
+	}
+
+	if op.Value == nil && op.Op != PatchRemove {
+		return fmt.Errorf("%w: %s %q without value", ErrInvalidPatch, op.Op, op.Path)
+	}
+
+	*o = Operation[T]{Op: op.Op, Key: unescapePointer(pointer)}
+
+	if op.Value != nil && op.Op != PatchRemove {
+		o.Value = *op.Value
+	}
+
+	return nil
+}
+
+// Diff returns the patch transforming the map into the other one, comparing
+// values like Equal. Applied to the map, it reproduces the other one, order
+// included: keys missing from the other map are removed, changed values are
+// replaced, and new keys added, at the end. Keys out of place are removed,
+// and added again in the right order.
+func (m *SafeOrderedMap[T]) Diff(other *SafeOrderedMap[T]) Patch[T] {
+	target := other.Entries()
+
+	m.rlock()
+	defer m.runlock()
+
+	patch := Patch[T]{}
+
+	// kept are the keys of the map kept in place: the longest prefix of the
+	// other map found in the same order.
+	kept := make(map[string]bool, len(target))
+
+	e := m.head
+
+	for _, entry := range target {
+		current, ok := m.data[m.slot(entry.Key)]
+		if !ok {
+			break
+		}
+
+		// Skip the keys which are removed, or come later in the other map.
+		for e != nil && e != current {
+			e = e.next
+		}
+
+		if e == nil {
+			break
+		}
+
+		kept[m.slot(entry.Key)] = true
+
+		e = e.next
+	}
+
+	for e := m.head; e != nil; e = e.next {
+		if !kept[m.slot(e.key)] {
+			patch = append(patch, Operation[T]{Op: PatchRemove, Key: e.key})
+		}
+	}
+
+	for _, entry := range target {
+		slot := m.slot(entry.Key)
+
+		if !kept[slot] {
+			patch = append(patch, Operation[T]{Op: PatchAdd, Key: entry.Key, Value: entry.Value})
+
+			continue
+		}
+
+		if current := m.data[slot]; !reflect.DeepEqual(current.value, entry.Value) {
+			patch = append(patch, Operation[T]{Op: PatchReplace, Key: entry.Key, Value: entry.Value})
+		}
+	}
+
+	return patch
+}
+
+// ApplyPatch applies the operations of the patch, in order, under a single
+// write lock. Add sets the key, appending it if new, replace requires the key
+// to be present, and remove too. The patch is checked, and validated, before
+// anything is applied, failing with ErrInvalidPatch, or the error of the
+// validator, leaving the map untouched. If the writer, if set, fails, the
+// operations before are kept.
+func (m *SafeOrderedMap[T]) ApplyPatch(patch Patch[T]) error {
+	for _, op := range patch {
+		if op.Op == PatchRemove {
+			continue
+		}
+
+		if err := m.validate(op.Key, op.Value); err != nil {
+			return err
+		}
+	}
+
+	m.lock()
+	defer m.unlock()
+
+	defer func() {
+		m.metrics.Operation("patch")
+		m.metrics.SetSize(len(m.data))
+	}()
+
+	present := make(map[string]bool, len(patch))
+
+	exists := func(key string) bool {
+		if ok, checked := present[m.slot(key)]; checked {
+			return ok
+		}
+
+		_, ok := m.data[m.slot(key)]
+
+		return ok
+	}
+
+	for i, op := range patch {
+		switch op.Op {
+		case PatchAdd:
+			present[m.slot(op.Key)] = true
+		case PatchReplace, PatchRemove:
+			if !exists(op.Key) {
+				return fmt.Errorf("%w: operation %d, %s %q: %w", ErrInvalidPatch, i, op.Op, op.Key, ErrKeyNotFound)
+			}
+
+			present[m.slot(op.Key)] = op.Op == PatchReplace
+		default:
+			return fmt.Errorf("%w: operation %d, unknown operation %q", ErrInvalidPatch, i, op.Op)
+		}
+	}
+
+	for _, op := range patch {
+		if op.Op == PatchRemove {
+			m.unlink(m.data[m.slot(op.Key)])
+
+			continue
+		}
+
+		if err := m.write(op.Key, op.Value); err != nil {
+			return err
+		}
+
+		m.set(op.Key, op.Value)
+	}
+
+	return nil
+}
+
+//////
+// Helpers.
+//////
+
+// escapePointer escapes a key as a JSON pointer token, see RFC 6901.
+func escapePointer(key string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(key)
+}
+
+// unescapePointer unescapes a JSON pointer token, see RFC 6901.
+func unescapePointer(token string) string {
+	return strings.NewReplacer("~1", "/", "~0", "~").Replace(token)
+}
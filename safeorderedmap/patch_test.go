@@ -0,0 +1,102 @@
+package safeorderedmap
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiff(t *testing.T) {
+	a := New[int]().Add("a", 1).Add("b", 2).Add("c", 3)
+	b := New[int]().Add("a", 1).Add("c", 30).Add("d", 4)
+
+	patch := a.Diff(b)
+
+	assert.Equal(t, Patch[int]{
+		{Op: PatchRemove, Key: "b"},
+		{Op: PatchReplace, Key: "c", Value: 30},
+		{Op: PatchAdd, Key: "d", Value: 4},
+	}, patch)
+
+	assert.NoError(t, a.ApplyPatch(patch))
+	assert.Equal(t, b.Entries(), a.Entries())
+
+	assert.Empty(t, a.Diff(b))
+}
+
+func TestDiffOrder(t *testing.T) {
+	a := New[int]().Add("a", 1).Add("b", 2).Add("c", 3)
+	b := New[int]().Add("b", 2).Add("a", 1).Add("c", 3)
+
+	patch := a.Diff(b)
+
+	assert.NoError(t, a.ApplyPatch(patch))
+	assert.Equal(t, []string{"b", "a", "c"}, a.Keys())
+
+	assert.Equal(t, Patch[int]{
+		{Op: PatchRemove, Key: "a"},
+		{Op: PatchRemove, Key: "c"},
+		{Op: PatchAdd, Key: "a", Value: 1},
+		{Op: PatchAdd, Key: "c", Value: 3},
+	}, patch)
+}
+
+func TestApplyPatchInvalid(t *testing.T) {
+	m := New[int]().Add("a", 1)
+
+	err := m.ApplyPatch(Patch[int]{
+		{Op: PatchAdd, Key: "b", Value: 2},
+		{Op: PatchReplace, Key: "c", Value: 3},
+	})
+
+	assert.True(t, errors.Is(err, ErrInvalidPatch))
+	assert.True(t, errors.Is(err, ErrKeyNotFound))
+	assert.Equal(t, []string{"a"}, m.Keys())
+
+	assert.Error(t, m.ApplyPatch(Patch[int]{{Op: PatchRemove, Key: "a"}, {Op: PatchRemove, Key: "a"}}))
+	assert.Error(t, m.ApplyPatch(Patch[int]{{Op: "copy", Key: "a"}}))
+	assert.Equal(t, 1, m.Size())
+
+	v := New(WithValidator(func(key string, value int) error {
+		if value < 0 {
+			return errors.New("negative")
+		}
+
+		return nil
+	}))
+
+	assert.Error(t, v.ApplyPatch(Patch[int]{{Op: PatchAdd, Key: "a", Value: 1}, {Op: PatchAdd, Key: "b", Value: -1}}))
+	assert.True(t, v.Empty())
+}
+
+func TestPatchJSON(t *testing.T) {
+	patch := Patch[int]{
+		{Op: PatchAdd, Key: "a/b~c", Value: 1},
+		{Op: PatchReplace, Key: "d", Value: 0},
+		{Op: PatchRemove, Key: "e"},
+	}
+
+	data, err := json.Marshal(patch)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `[
+		{"op":"add","path":"/a~1b~0c","value":1},
+		{"op":"replace","path":"/d","value":0},
+		{"op":"remove","path":"/e"}
+	]`, string(data))
+
+	var decoded Patch[int]
+
+	assert.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, patch, decoded)
+
+	for _, invalid := range []string{
+		`[{"op":"move","path":"/a"}]`,
+		`[{"op":"remove","path":"a"}]`,
+		`[{"op":"remove","path":"/a/b"}]`,
+		`[{"op":"add","path":"/a"}]`,
+	} {
+		assert.ErrorIs(t, json.Unmarshal([]byte(invalid), &decoded), ErrInvalidPatch, invalid)
+	}
+}
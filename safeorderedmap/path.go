@@ -0,0 +1,237 @@
+package safeorderedmap
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+//////
+// Const, vars, and types.
+//////
+
+// DefaultPathSeparator separates the segments of a path, e.g.: a.b.c.
+const DefaultPathSeparator = "."
+
+// ErrInvalidPath is returned when a path can't be walked, e.g.: it's empty, a
+// segment isn't a valid slice index, or it goes through a non-container value.
+var ErrInvalidPath = errors.New("invalid path")
+
+//////
+// Helpers.
+//////
+
+// split splits the path into segments with the separator of the map.
+func split[T any](m *SafeOrderedMap[T], path string) ([]string, error) {
+	if path == "" {
+		return nil, fmt.Errorf("%w: empty", ErrInvalidPath)
+	}
+
+	sep := m.pathSeparator
+	if sep == "" {
+		sep = DefaultPathSeparator
+	}
+
+	return strings.Split(path, sep), nil
+}
+
+// index parses a slice index, which must be in [0, n].
+func index(segment string, n int) (int, error) {
+	i, err := strconv.Atoi(segment)
+	if err != nil || i < 0 || i > n {
+		return 0, fmt.Errorf("%w: index %q out of range", ErrInvalidPath, segment)
+	}
+
+	return i, nil
+}
+
+// child returns the value of the segment in the container node.
+func child(node any, segment string) (any, error) {
+	switch n := node.(type) {
+	case *SafeOrderedMap[any]:
+		if value, ok := n.get(segment); ok {
+			return value, nil
+		}
+	case map[string]any:
+		if value, ok := n[segment]; ok {
+			return value, nil
+		}
+	case []any:
+		i, err := index(segment, len(n)-1)
+		if err != nil {
+			return nil, err
+		}
+
+		return n[i], nil
+	default:
+		return nil, fmt.Errorf("%w: %q isn't in a map, or slice", ErrInvalidPath, segment)
+	}
+
+	return nil, fmt.Errorf("%w: %q", ErrKeyNotFound, segment)
+}
+
+// setIn sets the value at the path below node, creating missing maps, and
+// returns the updated node. Slices may grow by appending at index len.
+func setIn(node any, segments []string, value any) (any, error) {
+	if len(segments) == 0 {
+		return value, nil
+	}
+
+	segment := segments[0]
+
+	switch n := node.(type) {
+	case nil:
+		return setIn(New[any](), segments, value)
+	case *SafeOrderedMap[any]:
+		current, _ := n.get(segment)
+
+		updated, err := setIn(current, segments[1:], value)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := n.AddE(segment, updated); err != nil {
+			return nil, err
+		}
+
+		return n, nil
+	case map[string]any:
+		updated, err := setIn(n[segment], segments[1:], value)
+		if err != nil {
+			return nil, err
+		}
+
+		n[segment] = updated
+
+		return n, nil
+	case []any:
+		i, err := index(segment, len(n))
+		if err != nil {
+			return nil, err
+		}
+
+		if i == len(n) {
+			n = append(n, nil)
+		}
+
+		updated, err := setIn(n[i], segments[1:], value)
+		if err != nil {
+			return nil, err
+		}
+
+		n[i] = updated
+
+		return n, nil
+	default:
+		return nil, fmt.Errorf("%w: %q isn't in a map, or slice", ErrInvalidPath, segment)
+	}
+}
+
+// deleteIn deletes the value at the path below node, and returns the updated
+// node.
+func deleteIn(node any, segments []string) (any, error) {
+	segment := segments[0]
+
+	if len(segments) > 1 {
+		current, err := child(node, segment)
+		if err != nil {
+			return nil, err
+		}
+
+		updated, err := deleteIn(current, segments[1:])
+		if err != nil {
+			return nil, err
+		}
+
+		return setIn(node, segments[:1], updated)
+	}
+
+	if _, err := child(node, segment); err != nil {
+		return nil, err
+	}
+
+	switch n := node.(type) {
+	case *SafeOrderedMap[any]:
+		n.Delete(segment)
+
+		return n, nil
+	case map[string]any:
+		delete(n, segment)
+
+		return n, nil
+	default:
+		//nolint:forcetypeassert
+		s := node.([]any)
+
+		// Already validated by child.
+		i, _ := strconv.Atoi(segment)
+
+		return append(s[:i:i], s[i+1:]...), nil
+	}
+}
+
+//////
+// Path operations.
+//////
+
+// GetPath returns the value at the path, walking nested SafeOrderedMap[any],
+// map[string]any, and []any values, e.g.: "items.0.name". It returns
+// ErrKeyNotFound if a key is missing, and ErrInvalidPath if the path can't be
+// walked. Each nested map is locked separately.
+func GetPath(m *SafeOrderedMap[any], path string) (any, error) {
+	segments, err := split(m, path)
+	if err != nil {
+		return nil, err
+	}
+
+	var node any = m
+
+	for _, segment := range segments {
+		if node, err = child(node, segment); err != nil {
+			return nil, err
+		}
+	}
+
+	return node, nil
+}
+
+// SetPath sets the value at the path, creating missing intermediate maps as
+// SafeOrderedMap[any]. An index equal to the length of a slice appends to it.
+// Values are stored with AddE, so validators, and writers apply. The walk
+// isn't atomic: each nested map is locked separately.
+func SetPath(m *SafeOrderedMap[any], path string, value any) error {
+	segments, err := split(m, path)
+	if err != nil {
+		return err
+	}
+
+	_, err = setIn(m, segments, value)
+
+	return err
+}
+
+// DeletePath deletes the value at the path, removing the element if it's in a
+// slice. It returns ErrKeyNotFound if a key is missing.
+func DeletePath(m *SafeOrderedMap[any], path string) error {
+	segments, err := split(m, path)
+	if err != nil {
+		return err
+	}
+
+	_, err = deleteIn(m, segments)
+
+	return err
+}
+
+//////
+// Factory.
+//////
+
+// WithPathSeparator sets the separator of the segments of paths, used by
+// GetPath, SetPath, and DeletePath. Default is DefaultPathSeparator.
+func WithPathSeparator[T any](sep string) Option[T] {
+	return func(m *SafeOrderedMap[T]) {
+		m.pathSeparator = sep
+	}
+}
@@ -0,0 +1,78 @@
+package safeorderedmap
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPath(t *testing.T) {
+	m := New[any]()
+
+	assert.NoError(t, SetPath(m, "a.b.c", 1))
+	assert.NoError(t, SetPath(m, "a.b.d", 2))
+	assert.NoError(t, SetPath(m, "items", []any{map[string]any{"name": "x"}}))
+
+	value, err := GetPath(m, "a.b.c")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, value)
+
+	value, err = GetPath(m, "items.0.name")
+	assert.NoError(t, err)
+	assert.Equal(t, "x", value)
+
+	nested, err := GetPath(m, "a.b")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"c", "d"}, nested.(*SafeOrderedMap[any]).Keys())
+
+	// Appending, and overwriting slice elements.
+	assert.NoError(t, SetPath(m, "items.1", "y"))
+	assert.NoError(t, SetPath(m, "items.0.name", "z"))
+
+	items, _ := GetPath(m, "items")
+	assert.Equal(t, []any{map[string]any{"name": "z"}, "y"}, items)
+
+	_, err = GetPath(m, "a.missing")
+	assert.ErrorIs(t, err, ErrKeyNotFound)
+
+	_, err = GetPath(m, "items.5")
+	assert.ErrorIs(t, err, ErrInvalidPath)
+
+	_, err = GetPath(m, "a.b.c.d")
+	assert.ErrorIs(t, err, ErrInvalidPath)
+
+	assert.ErrorIs(t, SetPath(m, "a.b.c.d", 1), ErrInvalidPath)
+	assert.ErrorIs(t, SetPath(m, "items.x", 1), ErrInvalidPath)
+	assert.ErrorIs(t, SetPath(m, "", 1), ErrInvalidPath)
+}
+
+func TestDeletePath(t *testing.T) {
+	m := New[any]()
+
+	assert.NoError(t, SetPath(m, "a.b", 1))
+	assert.NoError(t, SetPath(m, "a.c", 2))
+	assert.NoError(t, SetPath(m, "items", []any{"x", "y", "z"}))
+
+	assert.NoError(t, DeletePath(m, "a.b"))
+	assert.NoError(t, DeletePath(m, "items.1"))
+
+	a, _ := GetPath(m, "a")
+	assert.Equal(t, []string{"c"}, a.(*SafeOrderedMap[any]).Keys())
+
+	items, _ := GetPath(m, "items")
+	assert.Equal(t, []any{"x", "z"}, items)
+
+	assert.ErrorIs(t, DeletePath(m, "a.b"), ErrKeyNotFound)
+	assert.ErrorIs(t, DeletePath(m, "items.2"), ErrInvalidPath)
+}
+
+func TestPathSeparator(t *testing.T) {
+	m := New(WithPathSeparator[any]("/"))
+
+	assert.NoError(t, SetPath(m, "a.b/c", 1))
+
+	value, err := GetPath(m, "a.b/c")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, value)
+	assert.Equal(t, []string{"a.b"}, m.Keys())
+}
@@ -0,0 +1,185 @@
+package safeorderedmap
+
+import "time"
+
+//////
+// Const, vars, and types.
+//////
+
+// ReadOnly is a read-only view of a SafeOrderedMap, see
+// SafeOrderedMap.ReadOnly.
+type ReadOnly[T any] interface {
+	// Get a value. If the map has a loader, it still applies.
+	Get(key string) (T, bool)
+
+	// GetByIndex returns the value at the given index, in insertion order.
+	GetByIndex(i int) (T, bool)
+
+	// Contains checks if the key is present.
+	Contains(key string) bool
+
+	// Index returns the index, and value, of the key.
+	Index(key string) (int, T, bool)
+
+	// First returns the first entry, in insertion order.
+	First() (string, T, bool)
+
+	// Last returns the last entry, in insertion order.
+	Last() (string, T, bool)
+
+	// Keys returns all keys, in insertion order.
+	Keys() []string
+
+	// Values returns all values, in insertion order.
+	Values() []T
+
+	// Entries returns all entries, in insertion order.
+	Entries() []Entry[T]
+
+	// ScanPage returns a page of entries, and the cursor of the next one.
+	ScanPage(cursor string, limit int) ([]Entry[T], string)
+
+	// AddedAt returns the time the key was added, see WithTimestamps.
+	AddedAt(key string) (time.Time, bool)
+
+	// AddedSince returns the entries added at, or after, the time.
+	AddedSince(t time.Time) []Entry[T]
+
+	// OldestN returns the n entries added first, from the oldest.
+	OldestN(n int) []Entry[T]
+
+	// NewestN returns the n entries added last, from the newest.
+	NewestN(n int) []Entry[T]
+
+	// Size returns the number of entries.
+	Size() int
+
+	// Empty checks if there are no entries.
+	Empty() bool
+
+	// Each calls f for each entry, in insertion order.
+	Each(f func(key string, value T))
+
+	// Find returns the first entry satisfying the predicate.
+	Find(predicate func(key string, value T) bool) (string, T, bool)
+
+	// Any checks if at least one entry satisfies the predicate.
+	Any(predicate func(key string, value T) bool) bool
+
+	// All checks if all entries satisfy the predicate.
+	All(predicate func(key string, value T) bool) bool
+
+	// Count returns the number of entries satisfying the predicate.
+	Count(predicate func(key string, value T) bool) int
+
+	// Revision returns the revision of the last mutation.
+	Revision() uint64
+
+	// Clone returns a copy of the map, which can be modified.
+	Clone() *SafeOrderedMap[T]
+
+	// String returns the string representation of the map.
+	String() string
+
+	// MarshalJSON marshals the map to JSON.
+	MarshalJSON() ([]byte, error)
+}
+
+// readOnly implements ReadOnly. The map isn't exported, so the view can't be
+// converted back.
+type readOnly[T any] struct {
+	m *SafeOrderedMap[T]
+}
+
+//////
+// Methods.
+//////
+
+// Get implements ReadOnly.
+func (v readOnly[T]) Get(key string) (T, bool) { return v.m.Get(key) }
+
+// GetByIndex implements ReadOnly.
+func (v readOnly[T]) GetByIndex(i int) (T, bool) { return v.m.GetByIndex(i) }
+
+// Contains implements ReadOnly.
+func (v readOnly[T]) Contains(key string) bool { return v.m.Contains(key) }
+
+// Index implements ReadOnly.
+func (v readOnly[T]) Index(key string) (int, T, bool) { return v.m.Index(key) }
+
+// First implements ReadOnly.
+func (v readOnly[T]) First() (string, T, bool) { return v.m.First() }
+
+// Last implements ReadOnly.
+func (v readOnly[T]) Last() (string, T, bool) { return v.m.Last() }
+
+// Keys implements ReadOnly.
+func (v readOnly[T]) Keys() []string { return v.m.Keys() }
+
+// Values implements ReadOnly.
+func (v readOnly[T]) Values() []T { return v.m.Values() }
+
+// Entries implements ReadOnly.
+func (v readOnly[T]) Entries() []Entry[T] { return v.m.Entries() }
+
+// ScanPage implements ReadOnly.
+func (v readOnly[T]) ScanPage(cursor string, limit int) ([]Entry[T], string) {
+	return v.m.ScanPage(cursor, limit)
+}
+
+// AddedAt implements ReadOnly.
+func (v readOnly[T]) AddedAt(key string) (time.Time, bool) { return v.m.AddedAt(key) }
+
+// AddedSince implements ReadOnly.
+func (v readOnly[T]) AddedSince(t time.Time) []Entry[T] { return v.m.AddedSince(t) }
+
+// OldestN implements ReadOnly.
+func (v readOnly[T]) OldestN(n int) []Entry[T] { return v.m.OldestN(n) }
+
+// NewestN implements ReadOnly.
+func (v readOnly[T]) NewestN(n int) []Entry[T] { return v.m.NewestN(n) }
+
+// Size implements ReadOnly.
+func (v readOnly[T]) Size() int { return v.m.Size() }
+
+// Empty implements ReadOnly.
+func (v readOnly[T]) Empty() bool { return v.m.Empty() }
+
+// Each implements ReadOnly.
+func (v readOnly[T]) Each(f func(key string, value T)) { v.m.Each(f) }
+
+// Find implements ReadOnly.
+func (v readOnly[T]) Find(predicate func(key string, value T) bool) (string, T, bool) {
+	return v.m.Find(predicate)
+}
+
+// Any implements ReadOnly.
+func (v readOnly[T]) Any(predicate func(key string, value T) bool) bool { return v.m.Any(predicate) }
+
+// All implements ReadOnly.
+func (v readOnly[T]) All(predicate func(key string, value T) bool) bool { return v.m.All(predicate) }
+
+// Count implements ReadOnly.
+func (v readOnly[T]) Count(predicate func(key string, value T) bool) int {
+	return v.m.Count(predicate)
+}
+
+// Revision implements ReadOnly.
+func (v readOnly[T]) Revision() uint64 { return v.m.Revision() }
+
+// Clone implements ReadOnly.
+func (v readOnly[T]) Clone() *SafeOrderedMap[T] { return v.m.Clone() }
+
+// String implements ReadOnly.
+func (v readOnly[T]) String() string { return v.m.String() }
+
+// MarshalJSON implements ReadOnly.
+func (v readOnly[T]) MarshalJSON() ([]byte, error) { return v.m.MarshalJSON() }
+
+// ReadOnly returns a read-only view of the map, e.g.: to hand it out without
+// copying it. The view isn't a snapshot: it reflects later changes to the
+// map. It can't be converted back to the map, use Clone to get a copy which
+// can be modified.
+func (m *SafeOrderedMap[T]) ReadOnly() ReadOnly[T] {
+	return readOnly[T]{m: m}
+}
@@ -0,0 +1,44 @@
+package safeorderedmap
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadOnly(t *testing.T) {
+	m := New[int]()
+	m.Add("a", 1)
+
+	view := m.ReadOnly()
+
+	m.Add("b", 2)
+
+	// The view reflects later changes.
+	assert.Equal(t, 2, view.Size())
+	assert.Equal(t, []string{"a", "b"}, view.Keys())
+
+	v, ok := view.Get("b")
+	assert.True(t, ok)
+	assert.Equal(t, 2, v)
+
+	sum := 0
+
+	view.Each(func(_ string, value int) { sum += value })
+
+	assert.Equal(t, 3, sum)
+
+	// The view can't be converted back.
+	_, ok = any(view).(*SafeOrderedMap[int])
+	assert.False(t, ok)
+
+	_, ok = any(view).(interface {
+		Add(string, int) *SafeOrderedMap[int]
+	})
+	assert.False(t, ok)
+
+	// A clone can be modified, without affecting the map.
+	view.Clone().Add("c", 3)
+
+	assert.Equal(t, 2, m.Size())
+}
@@ -0,0 +1,113 @@
+package safeorderedmap
+
+import "sort"
+
+//////
+// Const, vars, and types.
+//////
+
+// tombstone is a deleted key, with the casing it had, see WithKeyFolding, and
+// the revision of its deletion.
+type tombstone struct {
+	key string
+	rev uint64
+}
+
+//////
+// Methods.
+//////
+
+// tombstone records the deletion of the key at the current revision, if the
+// map tracks deletions, see WithChangeTracking. Caller must hold the write
+// lock.
+func (m *SafeOrderedMap[T]) tombstone(key string) {
+	if !m.tracking {
+		return
+	}
+
+	if m.tombstones == nil {
+		m.tombstones = make(map[string]tombstone)
+	}
+
+	m.tombstones[m.slot(key)] = tombstone{key: key, rev: m.revision}
+}
+
+// Revision returns the current revision of the map. It starts at 0, and is
+// incremented on every mutation, so a different revision means the map
+// changed.
+func (m *SafeOrderedMap[T]) Revision() uint64 {
+	m.rlock()
+	defer m.runlock()
+
+	return m.revision
+}
+
+// ChangedSince returns the keys added, updated, or deleted after the given
+// revision, in the order of their last mutation. Use Contains, or Get to tell
+// deleted keys apart. It's O(n).
+//
+// Deleted keys are only reported if the map tracks them, see
+// WithChangeTracking, until they're added again, or until Compact is called.
+func (m *SafeOrderedMap[T]) ChangedSince(rev uint64) []string {
+	m.rlock()
+	defer m.runlock()
+
+	type change struct {
+		key string
+		rev uint64
+	}
+
+	changes := []change{}
+
+	for e := m.head; e != nil; e = e.next {
+		if e.rev > rev {
+			changes = append(changes, change{e.key, e.rev})
+		}
+	}
+
+	for _, t := range m.tombstones {
+		if t.rev > rev {
+			changes = append(changes, change{t.key, t.rev})
+		}
+	}
+
+	sort.SliceStable(changes, func(i, j int) bool {
+		return changes[i].rev < changes[j].rev
+	})
+
+	keys := make([]string, 0, len(changes))
+
+	for _, c := range changes {
+		keys = append(keys, c.key)
+	}
+
+	return keys
+}
+
+// Compact forgets deleted keys up to, and including, the given revision, which
+// should be the lowest revision every consumer of ChangedSince has already
+// seen. Deletions at, or before it, aren't reported by ChangedSince anymore.
+func (m *SafeOrderedMap[T]) Compact(rev uint64) {
+	m.lock()
+	defer m.unlock()
+
+	for slot, t := range m.tombstones {
+		if t.rev <= rev {
+			delete(m.tombstones, slot)
+		}
+	}
+}
+
+//////
+// Factory.
+//////
+
+// WithChangeTracking records deleted keys, so ChangedSince reports them. Each
+// deleted key is kept until it's added again, or until Compact forgets it, so
+// consumers must call Compact, e.g.: long-lived caches with churning keys.
+// Without it, ChangedSince only reports added, and updated keys.
+func WithChangeTracking[T any]() Option[T] {
+	return func(m *SafeOrderedMap[T]) {
+		m.tracking = true
+	}
+}
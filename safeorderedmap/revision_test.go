@@ -0,0 +1,83 @@
+package safeorderedmap
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSafeOrderedMapRevision(t *testing.T) {
+	m := New(WithChangeTracking[int]())
+	assert.Equal(t, uint64(0), m.Revision())
+
+	m.Add("a", 1).Add("b", 2).Add("c", 3)
+	assert.Equal(t, uint64(3), m.Revision())
+
+	rev := m.Revision()
+
+	m.Get("a")
+	m.Delete("missing")
+	assert.Equal(t, rev, m.Revision())
+	assert.Empty(t, m.ChangedSince(rev))
+
+	m.Add("c", 30).Delete("a").Add("a", 10).Delete("b")
+
+	assert.Equal(t, []string{"c", "a", "b"}, m.ChangedSince(rev))
+	assert.Equal(t, []string{"c", "a", "b"}, m.ChangedSince(0))
+	assert.Equal(t, []string{"b"}, m.ChangedSince(m.Revision()-1))
+}
+
+func TestSafeOrderedMapRevisionClear(t *testing.T) {
+	m := New(WithChangeTracking[int]())
+	m.Add("a", 1).Add("b", 2)
+
+	rev := m.Revision()
+
+	m.Clear()
+
+	assert.ElementsMatch(t, []string{"a", "b"}, m.ChangedSince(rev))
+
+	m.Compact(m.Revision())
+
+	assert.Empty(t, m.ChangedSince(rev))
+}
+
+func TestSafeOrderedMapRevisionRename(t *testing.T) {
+	m := New(WithChangeTracking[int]())
+	m.Add("a", 1).Add("b", 2)
+
+	rev := m.Revision()
+
+	assert.NoError(t, m.RenameKey("a", "z"))
+	assert.ElementsMatch(t, []string{"a", "z"}, m.ChangedSince(rev))
+}
+
+func TestSafeOrderedMapRevisionUntracked(t *testing.T) {
+	m := New[int]()
+	m.Add("a", 1).Add("b", 2)
+
+	rev := m.Revision()
+
+	m.Delete("a").Add("b", 20).Clear()
+
+	assert.Empty(t, m.tombstones)
+	assert.Empty(t, m.ChangedSince(rev))
+
+	m.Add("c", 3)
+
+	assert.Equal(t, []string{"c"}, m.ChangedSince(rev))
+}
+
+func TestSafeOrderedMapRevisionFolding(t *testing.T) {
+	m := New(WithChangeTracking[int](), WithCaseInsensitiveKeys[int]())
+	m.Add("Foo", 1)
+
+	rev := m.Revision()
+
+	m.Delete("foo")
+	assert.Equal(t, []string{"Foo"}, m.ChangedSince(rev))
+
+	// Re-adding the key, in another casing, clears its tombstone.
+	m.Add("foo", 2)
+	assert.Equal(t, []string{"foo"}, m.ChangedSince(rev))
+}
@@ -1,121 +1,889 @@
 package safeorderedmap
 
 import (
+	"bytes"
+	"database/sql/driver"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"reflect"
+	"strings"
 	"sync"
+	"sync/atomic"
+	"text/tabwriter"
+	"time"
+
+	"github.com/thalesfsp/go-common-types/internal/bsonjson"
+	"github.com/thalesfsp/go-common-types/internal/singleflight"
+	"github.com/thalesfsp/go-common-types/internal/watch"
+	"github.com/thalesfsp/go-common-types/metrics"
+	"github.com/thalesfsp/go-common-types/shared"
 )
 
 //////
 // Const, vars, and types.
 //////
 
+var (
+	// ErrKeyNotFound is returned when a key isn't present.
+	ErrKeyNotFound = errors.New("key not found")
+
+	// ErrKeyExists is returned when a key is already present.
+	ErrKeyExists = errors.New("key already exists")
+)
+
+// Option allows to configure a SafeOrderedMap.
+type Option[T any] func(m *SafeOrderedMap[T])
+
+// element is a node of the doubly linked list which keeps the insertion order
+// of the map, allowing O(1) deletions.
+type element[T any] struct {
+	key   string
+	value T
+
+	// rev is the revision of the last mutation of the element.
+	rev uint64
+
+	// seq is the insertion sequence of the element, increasing along the
+	// list, see ScanPage.
+	seq uint64
+
+	// added is the time the element was added, if the map records it, see
+	// WithTimestamps.
+	added time.Time
+
+	prev *element[T]
+	next *element[T]
+}
+
 // SafeOrderedMap is a map that preserves the order of keys powered by generics.
+//
+// The zero value is an empty map, ready to use, e.g.: as a struct field
+// filled by UnmarshalJSON.
 type SafeOrderedMap[T any] struct {
+	// size is first, to be 64-bit aligned for atomic operations. It's
+	// updated holding the write lock, and read without the lock by Size.
+	size int64
+
 	sync.RWMutex
 
-	data map[string]T
+	// loading defers the updates of size to the end of load, so Size doesn't
+	// see a partially loaded map.
+	loading bool
+
+	data map[string]*element[T]
+
+	head *element[T]
+	tail *element[T]
+
+	metrics *metrics.Metrics
+
+	journal *journal[T]
+
+	revision uint64
+
+	// tombstones are the deleted keys, by slot, if the map tracks them, see
+	// WithChangeTracking.
+	tracking   bool
+	tombstones map[string]tombstone
+
+	// sequence is the insertion sequence of the last element added.
+	sequence uint64
+
+	// now returns the time of the additions, if recorded, see WithTimestamps.
+	now func() time.Time
+
+	// snapshots serves reads from current, rebuilt by a single reader after
+	// changes, see WithSnapshots.
+	snapshots bool
+	current   atomic.Pointer[snapshot[T]]
+	rebuild   sync.Mutex
+
+	watchers watch.Hub[Event[T]]
+
+	entriesJSON bool
+
+	// useNumber, and keyDecoders, configure how values are decoded, see
+	// WithUseNumber, and WithKeyDecoder.
+	useNumber   bool
+	keyDecoders map[string]KeyDecoder[T]
 
-	order []string
+	pathSeparator string
+
+	fold func(key string) string
+
+	disposer func(key string, value T)
+
+	sizer shared.Sizer[T]
+
+	validators []Validator[T]
+
+	loader  Loader[T]
+	writer  Writer[T]
+	flights singleflight.Group[T]
+
+	// writing serializes the mutations writing through, see WithWriter.
+	writing sync.Mutex
+
+	// dropErr is the first error which made a method without an error result
+	// drop an entry, see DropErr.
+	dropMu  sync.Mutex
+	dropErr error
 }
 
 //////
 // Methods.
 //////
 
-// String is the stringer implementation.
-func (m *SafeOrderedMap[T]) String() string {
+// lock acquires the write lock, recording the wait time if metrics are
+// enabled.
+func (m *SafeOrderedMap[T]) lock() {
+	if m.metrics == nil {
+		m.Lock()
+
+		return
+	}
+
+	start := time.Now()
+
+	m.Lock()
+
+	m.metrics.ObserveLockWait(time.Since(start))
+}
+
+// unlock releases the write lock.
+func (m *SafeOrderedMap[T]) unlock() {
+	m.Unlock()
+}
+
+// rlock acquires the read lock, recording the wait time if metrics are
+// enabled.
+func (m *SafeOrderedMap[T]) rlock() {
+	if m.metrics == nil {
+		m.RLock()
+
+		return
+	}
+
+	start := time.Now()
+
 	m.RLock()
-	defer m.RUnlock()
 
-	json, err := json.Marshal(m.data)
+	m.metrics.ObserveLockWait(time.Since(start))
+}
+
+// runlock releases the read lock.
+func (m *SafeOrderedMap[T]) runlock() {
+	m.RUnlock()
+}
+
+// set adds or updates a key. Caller must hold the write lock.
+func (m *SafeOrderedMap[T]) set(key string, value T) {
+	m.put(key, value, m.stamp())
+}
+
+// put is set, with the time of the addition, if the key is new, see
+// WithTimestamps. Caller must hold the write lock.
+func (m *SafeOrderedMap[T]) put(key string, value T, added time.Time) {
+	m.invalidate()
+
+	m.journal.add(key, value)
+
+	m.revision++
+
+	delete(m.tombstones, m.slot(key))
+
+	if e, ok := m.data[m.slot(key)]; ok {
+		m.watchers.Publish(Event[T]{Op: shared.OpUpdate, Key: key, Old: e.value, New: value})
+
+		old := e.value
+
+		e.value = value
+		e.rev = m.revision
+
+		if !same(old, value) {
+			m.dispose(e.key, old)
+		}
+
+		return
+	}
+
+	m.watchers.Publish(Event[T]{Op: shared.OpAdd, Key: key, New: value})
+
+	if m.data == nil {
+		m.data = make(map[string]*element[T])
+	}
+
+	m.sequence++
+
+	e := &element[T]{key: key, value: value, rev: m.revision, seq: m.sequence, added: added, prev: m.tail}
+
+	if m.tail == nil {
+		m.head = e
+	} else {
+		m.tail.next = e
+	}
+
+	m.tail = e
+
+	m.data[m.slot(key)] = e
+
+	if !m.loading {
+		atomic.AddInt64(&m.size, 1)
+	}
+}
+
+// load replaces the content of the map with the given keys and values. Caller
+// must hold the write lock.
+func (m *SafeOrderedMap[T]) load(keys []string, values []T) {
+	m.loading = true
+
+	m.discard()
+
+	for i, key := range keys {
+		m.set(key, values[i])
+	}
+
+	m.loading = false
+
+	atomic.StoreInt64(&m.size, int64(len(m.data)))
+
+	m.metrics.Operation("unmarshal")
+	m.metrics.SetSize(len(m.data))
+}
+
+// reset removes all elements from the map. Caller must hold the write lock.
+func (m *SafeOrderedMap[T]) reset() {
+	m.invalidate()
+
+	m.journal.clear()
+
+	m.watchers.Publish(Event[T]{Op: shared.OpClear})
+
+	m.revision++
+
+	if m.tracking {
+		for e := m.head; e != nil; e = e.next {
+			m.tombstone(e.key)
+		}
+	}
+
+	m.data = make(map[string]*element[T])
+	m.head, m.tail = nil, nil
+
+	if !m.loading {
+		atomic.StoreInt64(&m.size, 0)
+	}
+}
+
+// at returns the element at the given index, which must be in range. It walks
+// the list from the closest end. Caller must hold the lock.
+func (m *SafeOrderedMap[T]) at(i int) *element[T] {
+	if i < len(m.data)/2 {
+		e := m.head
+
+		for ; i > 0; i-- {
+			e = e.next
+		}
+
+		return e
+	}
+
+	e := m.tail
+
+	for j := len(m.data) - 1; j > i; j-- {
+		e = e.prev
+	}
+
+	return e
+}
+
+// unlink removes the element from the map, disposing of it, see
+// WithDisposer. Caller must hold the write lock.
+func (m *SafeOrderedMap[T]) unlink(e *element[T]) {
+	m.invalidate()
+
+	m.journal.delete(e.key)
+
+	m.watchers.Publish(Event[T]{Op: shared.OpDelete, Key: e.key, Old: e.value})
+
+	m.revision++
+
+	m.tombstone(e.key)
+
+	if e.prev == nil {
+		m.head = e.next
+	} else {
+		e.prev.next = e.next
+	}
+
+	if e.next == nil {
+		m.tail = e.prev
+	} else {
+		e.next.prev = e.prev
+	}
+
+	e.prev, e.next = nil, nil
+
+	delete(m.data, m.slot(e.key))
+
+	atomic.AddInt64(&m.size, -1)
+
+	m.dispose(e.key, e.value)
+}
+
+// rename changes the key of the element, keeping its position. The new key
+// must not be present. Caller must hold the write lock.
+func (m *SafeOrderedMap[T]) rename(e *element[T], key string) {
+	m.invalidate()
+
+	m.journal.rename(e.key, key)
+
+	m.watchers.Publish(Event[T]{Op: shared.OpDelete, Key: e.key, Old: e.value})
+	m.watchers.Publish(Event[T]{Op: shared.OpAdd, Key: key, New: e.value})
+
+	m.revision++
+
+	m.tombstone(e.key)
+
+	delete(m.tombstones, m.slot(key))
+	delete(m.data, m.slot(e.key))
+
+	e.key = key
+	e.rev = m.revision
+
+	m.data[m.slot(key)] = e
+}
+
+// Metrics returns the metrics of the map, nil if not enabled.
+func (m *SafeOrderedMap[T]) Metrics() *metrics.Metrics {
+	return m.metrics
+}
+
+// toMap returns the content of the map as a regular Go map. Caller must hold
+// the lock.
+func (m *SafeOrderedMap[T]) toMap() map[string]T {
+	goMap := make(map[string]T, len(m.data))
+
+	for e := m.head; e != nil; e = e.next {
+		goMap[e.key] = e.value
+	}
+
+	return goMap
+}
+
+// marshalOrdered returns the JSON representation of the map, preserving the
+// insertion order of the keys. Caller must hold the lock.
+func (m *SafeOrderedMap[T]) marshalOrdered() ([]byte, error) {
+	var buf bytes.Buffer
+
+	buf.WriteByte('{')
+
+	for e := m.head; e != nil; e = e.next {
+		key, err := json.Marshal(e.key)
+		if err != nil {
+			return nil, err
+		}
+
+		value, err := json.Marshal(e.value)
+		if err != nil {
+			return nil, err
+		}
+
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(value)
+
+		if e.next != nil {
+			buf.WriteByte(',')
+		}
+	}
+
+	buf.WriteByte('}')
+
+	return buf.Bytes(), nil
+}
+
+// goString returns the Go-syntax representation of the map, in order. Caller
+// must hold the lock.
+func (m *SafeOrderedMap[T]) goString() string {
+	var sb strings.Builder
+
+	sb.WriteString("map[string]")
+	sb.WriteString(reflect.TypeOf((*T)(nil)).Elem().String())
+	sb.WriteString("{")
+
+	for e := m.head; e != nil; e = e.next {
+		sb.WriteString(fmt.Sprintf("%q:%#v", e.key, e.value))
+
+		if e.next != nil {
+			sb.WriteString(", ")
+		}
+	}
+
+	sb.WriteString("}")
+
+	return sb.String()
+}
+
+// String is the stringer implementation. It returns the JSON representation
+// of the map, falling back to the Go-syntax representation if the values
+// can't be marshalled.
+func (m *SafeOrderedMap[T]) String() string {
+	m.rlock()
+	defer m.runlock()
+
+	json, err := json.Marshal(m.toMap())
 	if err != nil {
-		return ""
+		return m.goString()
 	}
 
 	return string(json)
 }
 
+// StringIndent is like String but indents the JSON representation, see
+// json.MarshalIndent. Unlike String, it returns marshalling errors.
+func (m *SafeOrderedMap[T]) StringIndent(prefix, indent string) (string, error) {
+	m.rlock()
+	defer m.runlock()
+
+	json, err := json.MarshalIndent(m.toMap(), prefix, indent)
+	if err != nil {
+		return "", err
+	}
+
+	return string(json), nil
+}
+
+// PrettyString returns the indented JSON representation of the map, preserving
+// the insertion order of the keys. It falls back to the Go-syntax
+// representation if the values can't be marshalled.
+func (m *SafeOrderedMap[T]) PrettyString() string {
+	m.rlock()
+	defer m.runlock()
+
+	b, err := m.marshalOrdered()
+	if err != nil {
+		return m.goString()
+	}
+
+	var buf bytes.Buffer
+
+	if err := json.Indent(&buf, b, "", "  "); err != nil {
+		return m.goString()
+	}
+
+	return buf.String()
+}
+
+// Table writes the map to w as an aligned table, in order, one row per entry.
+// The first column is the key, followed by one column per column function. If
+// no column function is given, the value is printed with %v.
+func (m *SafeOrderedMap[T]) Table(w io.Writer, columns ...func(T) string) error {
+	m.rlock()
+	defer m.runlock()
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	for e := m.head; e != nil; e = e.next {
+		row := []string{e.key}
+
+		if len(columns) == 0 {
+			row = append(row, fmt.Sprintf("%v", e.value))
+		}
+
+		for _, column := range columns {
+			row = append(row, column(e.value))
+		}
+
+		if _, err := fmt.Fprintln(tw, strings.Join(row, "\t")); err != nil {
+			return err
+		}
+	}
+
+	return tw.Flush()
+}
+
+// GoString is the fmt.GoStringer implementation. It returns the Go-syntax
+// representation of the map, in order, e.g.: map[string]int{"a":1, "b":2}.
+func (m *SafeOrderedMap[T]) GoString() string {
+	m.rlock()
+	defer m.runlock()
+
+	return m.goString()
+}
+
+// Format is the fmt.Formatter implementation. It supports:
+//   - %v and %s: same as String.
+//   - %+v: key-value pairs, in order, e.g.: map[a:1 b:2].
+//   - %#v: same as GoString.
+func (m *SafeOrderedMap[T]) Format(f fmt.State, verb rune) {
+	switch {
+	case verb == 'v' && f.Flag('#'):
+		fmt.Fprint(f, m.GoString())
+	case verb == 'v' && f.Flag('+'):
+		m.rlock()
+		defer m.runlock()
+
+		fmt.Fprint(f, "map[")
+
+		for e := m.head; e != nil; e = e.next {
+			fmt.Fprintf(f, "%s:%+v", e.key, e.value)
+
+			if e.next != nil {
+				fmt.Fprint(f, " ")
+			}
+		}
+
+		fmt.Fprint(f, "]")
+	case verb == 'v' || verb == 's':
+		fmt.Fprint(f, m.String())
+	default:
+		fmt.Fprintf(f, "%%!%c(%s)", verb, m.String())
+	}
+}
+
 //////
 // CRUD operations.
 
-// Add a value in the map.
+// Add a value in the map. If a validator, or the writer rejects the entry,
+// it's dropped, and the error is recorded, see DropErr. Use AddE to handle
+// the error.
 func (m *SafeOrderedMap[T]) Add(key string, value T) *SafeOrderedMap[T] {
-	m.Lock()
-	defer m.Unlock()
+	m.dropped(m.AddE(key, value))
+
+	return m
+}
+
+// Set a value in the map. It's the same as Add, without chaining, satisfying
+// the collection.OrderedMap interface.
+func (m *SafeOrderedMap[T]) Set(key string, value T) {
+	m.Add(key, value)
+}
 
-	if _, ok := m.data[key]; !ok {
-		m.order = append(m.order, key)
+// SetIfAbsent sets a value in the map only if the key isn't present, and the
+// entry is valid, returning whether it was set. If a validator, or the writer
+// rejects the entry, the error is recorded, see DropErr.
+func (m *SafeOrderedMap[T]) SetIfAbsent(key string, value T) bool {
+	if err := m.validate(key, value); err != nil {
+		m.dropped(err)
+
+		return false
 	}
 
-	m.data[key] = value
+	m.metrics.Operation("add")
 
-	return m
+	absent := func() error {
+		if _, ok := m.data[m.slot(key)]; ok {
+			return ErrKeyExists
+		}
+
+		return nil
+	}
+
+	err := m.writeThrough(key, value, absent, func() {
+		m.set(key, value)
+
+		m.metrics.SetSize(len(m.data))
+	})
+	if err != nil {
+		if !errors.Is(err, ErrKeyExists) {
+			m.dropped(err)
+		}
+
+		return false
+	}
+
+	return true
+}
+
+// AddIfAbsent is the same as SetIfAbsent, named after Add.
+func (m *SafeOrderedMap[T]) AddIfAbsent(key string, value T) bool {
+	return m.SetIfAbsent(key, value)
 }
 
-// Get a value from the map.
+// Replace updates the value of the key only if it's present, returning the old
+// value, and whether it was replaced. Nothing happens if the key is missing,
+// or if the entry is invalid, or the writer fails, in which case the error is
+// recorded, see DropErr.
+func (m *SafeOrderedMap[T]) Replace(key string, value T) (T, bool) {
+	if err := m.validate(key, value); err != nil {
+		m.dropped(err)
+
+		return *new(T), false
+	}
+
+	m.metrics.Operation("replace")
+
+	present := func() error {
+		if _, ok := m.data[m.slot(key)]; !ok {
+			return ErrKeyNotFound
+		}
+
+		return nil
+	}
+
+	var old T
+
+	err := m.writeThrough(key, value, present, func() {
+		old = m.data[m.slot(key)].value
+
+		m.set(key, value)
+	})
+	if err != nil {
+		if !errors.Is(err, ErrKeyNotFound) {
+			m.dropped(err)
+		}
+
+		return *new(T), false
+	}
+
+	return old, true
+}
+
+// Swap is like Add, returning the previous value, and whether the key was
+// present, i.e.: if it was an update rather than an insert, like sync.Map. If
+// the entry is invalid, or the writer fails, the map is left untouched, the
+// current value is returned, and the error is recorded, see DropErr.
+func (m *SafeOrderedMap[T]) Swap(key string, value T) (T, bool) {
+	var (
+		previous T
+		loaded   bool
+	)
+
+	// current records the current value, even if the entry is dropped.
+	current := func() error {
+		var e *element[T]
+
+		if e, loaded = m.data[m.slot(key)]; loaded {
+			previous = e.value
+		}
+
+		return nil
+	}
+
+	if err := m.validate(key, value); err != nil {
+		m.dropped(err)
+
+		m.rlock()
+		defer m.runlock()
+
+		_ = current()
+
+		return previous, loaded
+	}
+
+	m.dropped(m.writeThrough(key, value, current, func() {
+		m.set(key, value)
+
+		m.metrics.Operation("add")
+		m.metrics.SetSize(len(m.data))
+	}))
+
+	return previous, loaded
+}
+
+// Get a value from the map. If the key is missing, and the map has a loader,
+// the value is loaded, see WithLoader.
 func (m *SafeOrderedMap[T]) Get(key string) (T, bool) {
-	m.RLock()
-	defer m.RUnlock()
+	value, ok := m.get(key)
+	if ok || m.loader == nil {
+		return value, ok
+	}
+
+	value, err := m.fetch(key)
+
+	return value, err == nil
+}
+
+// get a value from the map, without loading it.
+func (m *SafeOrderedMap[T]) get(key string) (T, bool) {
+	if m.snapshotted() {
+		value, ok := m.view().lookup(m.slot(key))
+
+		m.metrics.Operation("get")
+		m.metrics.Lookup(ok)
+
+		return value, ok
+	}
+
+	m.rlock()
+	defer m.runlock()
+
+	e, ok := m.data[m.slot(key)]
 
-	value, ok := m.data[key]
+	m.metrics.Operation("get")
+	m.metrics.Lookup(ok)
+
+	if !ok {
+		return *new(T), false
+	}
 
-	return value, ok
+	return e.value, true
 }
 
-// GetByIndex a value from the map based on the index.
+// GetByIndex a value from the map based on the index. It walks the map from
+// the closest end, so it's O(n).
 func (m *SafeOrderedMap[T]) GetByIndex(i int) (T, bool) {
-	m.RLock()
-	defer m.RUnlock()
+	m.rlock()
+	defer m.runlock()
+
+	m.metrics.Operation("getByIndex")
+
+	if i < 0 || i >= len(m.data) {
+		m.metrics.Miss()
 
-	if i < 0 || i >= len(m.order) {
 		return *new(T), false
 	}
 
-	return m.data[m.order[i]], true
+	m.metrics.Hit()
+
+	return m.at(i).value, true
 }
 
 // Delete a value from the map.
 func (m *SafeOrderedMap[T]) Delete(key string) *SafeOrderedMap[T] {
-	m.Lock()
-	defer m.Unlock()
+	m.lock()
+	defer m.unlock()
 
-	if _, ok := m.data[key]; ok {
-		delete(m.data, key)
+	if e, ok := m.data[m.slot(key)]; ok {
+		m.unlink(e)
+	}
 
-		for i, k := range m.order {
-			if k == key {
-				m.order = append(m.order[:i], m.order[i+1:]...)
+	m.metrics.Operation("delete")
+	m.metrics.SetSize(len(m.data))
 
-				break
-			}
+	return m
+}
+
+// Remove a value from the map, returning whether it was present.
+func (m *SafeOrderedMap[T]) Remove(key string) bool {
+	m.lock()
+	defer m.unlock()
+
+	e, ok := m.data[m.slot(key)]
+	if ok {
+		m.unlink(e)
+	}
+
+	m.metrics.Operation("delete")
+	m.metrics.SetSize(len(m.data))
+
+	return ok
+}
+
+// RenameKey changes the key of an element, keeping its position. It fails
+// with ErrKeyNotFound if the old key isn't present, and ErrKeyExists if the
+// new one is. Renaming a key to itself is a no-op.
+func (m *SafeOrderedMap[T]) RenameKey(oldKey, newKey string) error {
+	m.lock()
+	defer m.unlock()
+
+	e, err := m.renameable(oldKey, newKey)
+	if err != nil || oldKey == newKey {
+		return err
+	}
+
+	m.rename(e, newKey)
+
+	m.metrics.Operation("rename")
+
+	return nil
+}
+
+// ReplaceKey is like RenameKey, also setting the value of the element.
+func (m *SafeOrderedMap[T]) ReplaceKey(oldKey, newKey string, value T) error {
+	if err := m.validate(newKey, value); err != nil {
+		return err
+	}
+
+	renameable := func() error {
+		_, err := m.renameable(oldKey, newKey)
+
+		return err
+	}
+
+	return m.writeThrough(newKey, value, renameable, func() {
+		if oldKey != newKey {
+			m.rename(m.data[m.slot(oldKey)], newKey)
 		}
+
+		m.set(newKey, value)
+
+		m.metrics.Operation("rename")
+	})
+}
+
+// renameable returns the element of the old key, if it can be renamed to the
+// new key. Caller must hold the lock.
+func (m *SafeOrderedMap[T]) renameable(oldKey, newKey string) (*element[T], error) {
+	e, ok := m.data[m.slot(oldKey)]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrKeyNotFound, oldKey)
+	}
+
+	if _, ok := m.data[m.slot(newKey)]; ok && m.slot(oldKey) != m.slot(newKey) {
+		return nil, fmt.Errorf("%w: %q", ErrKeyExists, newKey)
+	}
+
+	return e, nil
+}
+
+// DeleteByIndex deletes the value at the given index from the map. It walks
+// the map from the closest end, so it's O(n).
+func (m *SafeOrderedMap[T]) DeleteByIndex(i int) *SafeOrderedMap[T] {
+	m.lock()
+	defer m.unlock()
+
+	if i < 0 || i >= len(m.data) {
+		return m
 	}
 
+	m.unlink(m.at(i))
+
+	m.metrics.Operation("delete")
+	m.metrics.SetSize(len(m.data))
+
+	return m
+}
+
+// Clear removes all elements from the map.
+func (m *SafeOrderedMap[T]) Clear() *SafeOrderedMap[T] {
+	m.lock()
+	defer m.unlock()
+
+	m.discard()
+
+	m.metrics.Operation("clear")
+	m.metrics.SetSize(0)
+
 	return m
 }
 
 // First return the first element of the map.
 func (m *SafeOrderedMap[T]) First() (string, T, bool) {
-	m.RLock()
-	defer m.RUnlock()
+	m.rlock()
+	defer m.runlock()
 
-	if len(m.order) == 0 {
+	if m.head == nil {
 		return "", *new(T), false
 	}
 
-	return m.order[0], m.data[m.order[0]], true
+	return m.head.key, m.head.value, true
 }
 
 // Last return the last element of the map.
 func (m *SafeOrderedMap[T]) Last() (string, T, bool) {
-	m.RLock()
-	defer m.RUnlock()
+	m.rlock()
+	defer m.runlock()
 
-	if len(m.order) == 0 {
+	if m.tail == nil {
 		return "", *new(T), false
 	}
 
-	return m.order[len(m.order)-1], m.data[m.order[len(m.order)-1]], true
+	return m.tail.key, m.tail.value, true
 }
 
 //////
@@ -123,85 +891,223 @@ func (m *SafeOrderedMap[T]) Last() (string, T, bool) {
 
 // Keys returns a list of all keys.
 func (m *SafeOrderedMap[T]) Keys() []string {
-	m.RLock()
-	defer m.RUnlock()
+	if m.snapshotted() {
+		return append([]string(nil), m.view().keys...)
+	}
+
+	m.rlock()
+	defer m.runlock()
 
-	keys := make([]string, len(m.order))
+	keys := make([]string, 0, len(m.data))
 
-	copy(keys, m.order)
+	for e := m.head; e != nil; e = e.next {
+		keys = append(keys, e.key)
+	}
 
 	return keys
 }
 
 // Values returns a list of all values.
 func (m *SafeOrderedMap[T]) Values() []T {
-	m.RLock()
-	defer m.RUnlock()
+	if m.snapshotted() {
+		return append([]T(nil), m.view().values...)
+	}
+
+	m.rlock()
+	defer m.runlock()
 
-	values := make([]T, len(m.order))
+	values := make([]T, 0, len(m.data))
 
-	for i, key := range m.order {
-		values[i] = m.data[key]
+	for e := m.head; e != nil; e = e.next {
+		values = append(values, e.value)
 	}
 
 	return values
 }
 
+// KeysWithPrefix returns the keys starting with the given prefix, in order.
+func (m *SafeOrderedMap[T]) KeysWithPrefix(prefix string) []string {
+	return m.KeysFunc(func(key string) bool {
+		return strings.HasPrefix(key, prefix)
+	})
+}
+
+// KeysFunc returns the keys satisfying the given predicate, in order.
+func (m *SafeOrderedMap[T]) KeysFunc(predicate func(key string) bool) []string {
+	m.rlock()
+	defer m.runlock()
+
+	keys := []string{}
+
+	for e := m.head; e != nil; e = e.next {
+		if predicate(e.key) {
+			keys = append(keys, e.key)
+		}
+	}
+
+	return keys
+}
+
+// FilterKeys returns a new map containing only the elements whose key
+// satisfies the given predicate, in order.
+func (m *SafeOrderedMap[T]) FilterKeys(predicate func(key string) bool) *SafeOrderedMap[T] {
+	return m.Filter(func(key string, _ T) bool {
+		return predicate(key)
+	})
+}
+
+// DeletePrefix deletes all elements whose key starts with the given prefix,
+// returning how many were deleted. It's O(n).
+func (m *SafeOrderedMap[T]) DeletePrefix(prefix string) int {
+	m.lock()
+	defer m.unlock()
+
+	deleted := 0
+
+	for e := m.head; e != nil; {
+		next := e.next
+
+		if strings.HasPrefix(e.key, prefix) {
+			m.unlink(e)
+
+			deleted++
+		}
+
+		e = next
+	}
+
+	m.metrics.Operation("delete")
+	m.metrics.SetSize(len(m.data))
+
+	return deleted
+}
+
+// DeleteMany deletes the keys, under a single write lock, returning how many
+// were present, e.g.: to clean up expired keys in bulk.
+func (m *SafeOrderedMap[T]) DeleteMany(keys ...string) int {
+	m.lock()
+	defer m.unlock()
+
+	deleted := 0
+
+	for _, key := range keys {
+		if e, ok := m.data[m.slot(key)]; ok {
+			m.unlink(e)
+
+			deleted++
+		}
+	}
+
+	m.metrics.Operation("delete")
+	m.metrics.SetSize(len(m.data))
+
+	return deleted
+}
+
+// DeleteFunc deletes the elements satisfying the predicate, under a single
+// write lock, returning how many. The predicate must not call methods of the
+// map.
+func (m *SafeOrderedMap[T]) DeleteFunc(predicate func(key string, value T) bool) int {
+	m.lock()
+	defer m.unlock()
+
+	deleted := 0
+
+	for e := m.head; e != nil; {
+		next := e.next
+
+		if predicate(e.key, e.value) {
+			m.unlink(e)
+
+			deleted++
+		}
+
+		e = next
+	}
+
+	m.metrics.Operation("delete")
+	m.metrics.SetSize(len(m.data))
+
+	return deleted
+}
+
 //////
 // Meta operations.
 
 // Contains checks if the set contains a given element.
 func (m *SafeOrderedMap[T]) Contains(key string) bool {
-	m.RLock()
-	defer m.RUnlock()
+	if m.snapshotted() {
+		_, ok := m.view().index[m.slot(key)]
+
+		m.metrics.Operation("contains")
+		m.metrics.Lookup(ok)
+
+		return ok
+	}
 
-	_, ok := m.data[key]
+	m.rlock()
+	defer m.runlock()
+
+	_, ok := m.data[m.slot(key)]
+
+	m.metrics.Operation("contains")
+	m.metrics.Lookup(ok)
 
 	return ok
 }
 
-// Size returns the number of elements in the map.
+// Size returns the number of elements in the map. It doesn't acquire the
+// lock, the size is read atomically.
 func (m *SafeOrderedMap[T]) Size() int {
-	m.RLock()
-	defer m.RUnlock()
-
-	return len(m.order)
+	return int(atomic.LoadInt64(&m.size))
 }
 
-// Empty checks if the map is empty and returns a boolean value.
+// Empty checks if the map is empty and returns a boolean value. Like Size, it
+// doesn't acquire the lock.
 func (m *SafeOrderedMap[T]) Empty() bool {
-	m.RLock()
-	defer m.RUnlock()
+	return m.Size() == 0
+}
 
-	return len(m.order) == 0
+// Digest returns a stable hash of the entries, in order, by h, or sha256 if
+// nil, e.g.: for cheap equality checks, ETags, and cache keys, see
+// shared.DigestOrdered. See CanonicalHash to ignore the order.
+func (m *SafeOrderedMap[T]) Digest(h hash.Hash) (string, error) {
+	return shared.DigestOrdered(h, m.Entries())
 }
 
-// Clone creates a deep copy of the map and returns it.
+// Clone creates a deep copy of the map and returns it. It keeps the times of
+// the additions, see WithTimestamps.
 func (m *SafeOrderedMap[T]) Clone() *SafeOrderedMap[T] {
-	m.RLock()
-	defer m.RUnlock()
+	m.rlock()
+	defer m.runlock()
 
-	clone := New[T]()
+	clone := m.derive()
 
-	for _, key := range m.order {
-		clone.Add(key, m.data[key])
+	for e := m.head; e != nil; e = e.next {
+		clone.put(e.key, e.value, e.added)
 	}
 
 	return clone
 }
 
-// Index returns the index and value of the given key.
+// Index returns the index and value of the given key. It walks the map from
+// the start, so it's O(n).
 func (m *SafeOrderedMap[T]) Index(key string) (int, T, bool) {
-	m.RLock()
-	defer m.RUnlock()
+	m.rlock()
+	defer m.runlock()
 
-	for i, k := range m.order {
-		if k == key {
-			return i, m.data[key], true
-		}
+	e, ok := m.data[m.slot(key)]
+	if !ok {
+		return -1, *new(T), false
+	}
+
+	i := 0
+
+	for cur := m.head; cur != e; cur = cur.next {
+		i++
 	}
 
-	return -1, *new(T), false
+	return i, e.value, true
 }
 
 //////
@@ -214,11 +1120,11 @@ func (m *SafeOrderedMap[T]) Index(key string) (int, T, bool) {
 // the condition, and false otherwise. The All method stops processing as soon
 // as it finds an element that does not satisfy the condition.
 func (m *SafeOrderedMap[T]) All(predicate func(key string, value T) bool) bool {
-	m.RLock()
-	defer m.RUnlock()
+	m.rlock()
+	defer m.runlock()
 
-	for _, key := range m.order {
-		if !predicate(key, m.data[key]) {
+	for e := m.head; e != nil; e = e.next {
+		if !predicate(e.key, e.value) {
 			return false
 		}
 	}
@@ -233,13 +1139,13 @@ func (m *SafeOrderedMap[T]) All(predicate func(key string, value T) bool) bool {
 // a new map containing the results. The original map remains unchanged. The new
 // map maintains the insertion order of the original map.
 func (m *SafeOrderedMap[T]) Map(f func(key string, value T) T) *SafeOrderedMap[T] {
-	m.RLock()
-	defer m.RUnlock()
+	m.rlock()
+	defer m.runlock()
 
-	newMap := New[T]()
+	newMap := m.derive()
 
-	for _, key := range m.order {
-		newMap.Add(key, f(key, m.data[key]))
+	for e := m.head; e != nil; e = e.next {
+		newMap.Add(e.key, f(e.key, e.value))
 	}
 
 	return newMap
@@ -252,14 +1158,14 @@ func (m *SafeOrderedMap[T]) Map(f func(key string, value T) T) *SafeOrderedMap[T
 // given condition (predicate). The original map remains unchanged. The new map
 // maintains the insertion order of the original map.
 func (m *SafeOrderedMap[T]) Filter(predicate func(key string, value T) bool) *SafeOrderedMap[T] {
-	m.RLock()
-	defer m.RUnlock()
+	m.rlock()
+	defer m.runlock()
 
-	filteredMap := New[T]()
+	filteredMap := m.derive()
 
-	for _, key := range m.order {
-		if predicate(key, m.data[key]) {
-			filteredMap.Add(key, m.data[key])
+	for e := m.head; e != nil; e = e.next {
+		if predicate(e.key, e.value) {
+			filteredMap.Add(e.key, e.value)
 		}
 	}
 
@@ -274,11 +1180,11 @@ func (m *SafeOrderedMap[T]) Filter(predicate func(key string, value T) bool) *Sa
 // printing or modifying the elements. However, the Each method itself does not
 // return any result.
 func (m *SafeOrderedMap[T]) Each(f func(key string, value T)) *SafeOrderedMap[T] {
-	m.RLock()
-	defer m.RUnlock()
+	m.rlock()
+	defer m.runlock()
 
-	for _, key := range m.order {
-		f(key, m.data[key])
+	for e := m.head; e != nil; e = e.next {
+		f(e.key, e.value)
 	}
 
 	return m
@@ -293,13 +1199,13 @@ func (m *SafeOrderedMap[T]) Each(f func(key string, value T)) *SafeOrderedMap[T]
 // on, until all elements in the map have been processed. The final result is a
 // single accumulated value.
 func (m *SafeOrderedMap[T]) Reduce(reducer func(accum T, key string, value T) T, initial T) T {
-	m.RLock()
-	defer m.RUnlock()
+	m.rlock()
+	defer m.runlock()
 
 	accum := initial
 
-	for _, key := range m.order {
-		accum = reducer(accum, key, m.data[key])
+	for e := m.head; e != nil; e = e.next {
+		accum = reducer(accum, e.key, e.value)
 	}
 
 	return accum
@@ -314,18 +1220,85 @@ func (m *SafeOrderedMap[T]) Reduce(reducer func(accum T, key string, value T) T,
 // satisfies the predicate, it returns a zero value for the type, an empty
 // string for the key, and false for the boolean value.
 func (m *SafeOrderedMap[T]) Find(predicate func(key string, value T) bool) (string, T, bool) {
-	m.RLock()
-	defer m.RUnlock()
+	m.rlock()
+	defer m.runlock()
+
+	for e := m.head; e != nil; e = e.next {
+		if predicate(e.key, e.value) {
+			return e.key, e.value, true
+		}
+	}
+
+	return "", *new(T), false
+}
 
-	for _, key := range m.order {
-		if predicate(key, m.data[key]) {
-			return key, m.data[key], true
+// FindLast returns the last element that satisfies the given predicate. It
+// walks the map backwards, from the most recently added element.
+func (m *SafeOrderedMap[T]) FindLast(predicate func(key string, value T) bool) (string, T, bool) {
+	m.rlock()
+	defer m.runlock()
+
+	for e := m.tail; e != nil; e = e.prev {
+		if predicate(e.key, e.value) {
+			return e.key, e.value, true
 		}
 	}
 
 	return "", *new(T), false
 }
 
+// FindIndex returns the index of the first element that satisfies the given
+// predicate, or -1.
+func (m *SafeOrderedMap[T]) FindIndex(predicate func(key string, value T) bool) int {
+	m.rlock()
+	defer m.runlock()
+
+	i := 0
+
+	for e := m.head; e != nil; e = e.next {
+		if predicate(e.key, e.value) {
+			return i
+		}
+
+		i++
+	}
+
+	return -1
+}
+
+// FindAll returns the keys of all elements that satisfy the given predicate,
+// in order. Use Filter to get the elements as a new map.
+func (m *SafeOrderedMap[T]) FindAll(predicate func(key string, value T) bool) []string {
+	m.rlock()
+	defer m.runlock()
+
+	keys := []string{}
+
+	for e := m.head; e != nil; e = e.next {
+		if predicate(e.key, e.value) {
+			keys = append(keys, e.key)
+		}
+	}
+
+	return keys
+}
+
+// Count returns the number of elements that satisfy the given predicate.
+func (m *SafeOrderedMap[T]) Count(predicate func(key string, value T) bool) int {
+	m.rlock()
+	defer m.runlock()
+
+	count := 0
+
+	for e := m.head; e != nil; e = e.next {
+		if predicate(e.key, e.value) {
+			count++
+		}
+	}
+
+	return count
+}
+
 // Any checks if any element in the map satisfies the given predicate.
 //
 // Iterates over the map and checks if any element satisfies a given predicate.
@@ -333,11 +1306,11 @@ func (m *SafeOrderedMap[T]) Find(predicate func(key string, value T) bool) (stri
 // element satisfies the predicate, it returns true. If no element satisfies the
 // predicate, it returns false.
 func (m *SafeOrderedMap[T]) Any(predicate func(key string, value T) bool) bool {
-	m.RLock()
-	defer m.RUnlock()
+	m.rlock()
+	defer m.runlock()
 
-	for _, key := range m.order {
-		if predicate(key, m.data[key]) {
+	for e := m.head; e != nil; e = e.next {
+		if predicate(e.key, e.value) {
 			return true
 		}
 	}
@@ -354,14 +1327,14 @@ func (m *SafeOrderedMap[T]) Any(predicate func(key string, value T) bool) bool {
 // predicate, it is added to the resulting map. The process stops once an
 // element that does not satisfy the predicate is encountered.
 func (m *SafeOrderedMap[T]) TakeWhile(predicate func(key string, value T) bool) *SafeOrderedMap[T] {
-	m.RLock()
-	defer m.RUnlock()
+	m.rlock()
+	defer m.runlock()
 
-	newMap := New[T]()
+	newMap := m.derive()
 
-	for _, key := range m.order {
-		if predicate(key, m.data[key]) {
-			newMap.Add(key, m.data[key])
+	for e := m.head; e != nil; e = e.next {
+		if predicate(e.key, e.value) {
+			newMap.Add(e.key, e.value)
 		} else {
 			break
 		}
@@ -380,19 +1353,19 @@ func (m *SafeOrderedMap[T]) TakeWhile(predicate func(key string, value T) bool)
 // to the resulting map once an element that does not satisfy the predicate is
 // encountered.
 func (m *SafeOrderedMap[T]) DropWhile(predicate func(key string, value T) bool) *SafeOrderedMap[T] {
-	m.RLock()
-	defer m.RUnlock()
+	m.rlock()
+	defer m.runlock()
 
-	newMap := New[T]()
+	newMap := m.derive()
 
 	dropping := true
-	for _, key := range m.order {
-		if dropping && !predicate(key, m.data[key]) {
+	for e := m.head; e != nil; e = e.next {
+		if dropping && !predicate(e.key, e.value) {
 			dropping = false
 		}
 
 		if !dropping {
-			newMap.Add(key, m.data[key])
+			newMap.Add(e.key, e.value)
 		}
 	}
 
@@ -400,40 +1373,122 @@ func (m *SafeOrderedMap[T]) DropWhile(predicate func(key string, value T) bool)
 }
 
 //////
-// Set operations
+// Erroring Higher-Order Functions. They stop at the first error, returning it.
 
-// Union returns a new ordered map containing all unique elements from both
-// maps. The order of elements in the resulting map will be based on the order
-// of elements in the original maps.
-func (m *SafeOrderedMap[T]) Union(other *SafeOrderedMap[T]) *SafeOrderedMap[T] {
-	m.RLock()
-	defer m.RUnlock()
+// TryMap is like Map, but f can fail.
+func (m *SafeOrderedMap[T]) TryMap(f func(key string, value T) (T, error)) (*SafeOrderedMap[T], error) {
+	m.rlock()
+	defer m.runlock()
+
+	newMap := m.derive()
+
+	for e := m.head; e != nil; e = e.next {
+		value, err := f(e.key, e.value)
+		if err != nil {
+			return nil, err
+		}
 
-	result := New[T]()
-	for _, key := range m.order {
-		result.Add(key, m.data[key])
+		newMap.Add(e.key, value)
 	}
 
-	for _, key := range other.order {
-		if _, ok := m.data[key]; !ok {
-			result.Add(key, other.data[key])
+	return newMap, nil
+}
+
+// TryFilter is like Filter, but the predicate can fail.
+func (m *SafeOrderedMap[T]) TryFilter(
+	predicate func(key string, value T) (bool, error),
+) (*SafeOrderedMap[T], error) {
+	m.rlock()
+	defer m.runlock()
+
+	filteredMap := m.derive()
+
+	for e := m.head; e != nil; e = e.next {
+		ok, err := predicate(e.key, e.value)
+		if err != nil {
+			return nil, err
+		}
+
+		if ok {
+			filteredMap.Add(e.key, e.value)
 		}
 	}
 
-	return result
+	return filteredMap, nil
 }
 
-// Difference returns a new ordered map containing elements present in the
-// original map but not in the other map.
-func (m *SafeOrderedMap[T]) Difference(other *SafeOrderedMap[T]) *SafeOrderedMap[T] {
-	m.RLock()
-	defer m.RUnlock()
+// TryEach is like Each, but f can fail.
+func (m *SafeOrderedMap[T]) TryEach(f func(key string, value T) error) error {
+	m.rlock()
+	defer m.runlock()
+
+	for e := m.head; e != nil; e = e.next {
+		if err := f(e.key, e.value); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// TryReduce is like Reduce, but the reducer can fail. On failure, the zero
+// value is returned.
+func (m *SafeOrderedMap[T]) TryReduce(
+	reducer func(accum T, key string, value T) (T, error),
+	initial T,
+) (T, error) {
+	m.rlock()
+	defer m.runlock()
+
+	accum := initial
+
+	for e := m.head; e != nil; e = e.next {
+		var err error
+
+		accum, err = reducer(accum, e.key, e.value)
+		if err != nil {
+			return *new(T), err
+		}
+	}
+
+	return accum, nil
+}
 
-	result := New[T]()
+//////
+// Set operations
+//
+// Results have a deterministic order: the elements of the original map come
+// first, in its order, followed by the ones of the other maps, in their order.
+
+// Union returns a new ordered map containing all unique elements from the
+// maps, in a single pass. The order of elements in the resulting map will be
+// based on the order of elements in the original maps, and a key keeps the
+// value of the first map having it.
+func (m *SafeOrderedMap[T]) Union(others ...*SafeOrderedMap[T]) *SafeOrderedMap[T] {
+	result := m.derive()
+
+	for _, entry := range m.Entries() {
+		result.Add(entry.Key, entry.Value)
+	}
+
+	for _, other := range others {
+		for _, entry := range other.Entries() {
+			result.SetIfAbsent(entry.Key, entry.Value)
+		}
+	}
+
+	return result
+}
 
-	for _, key := range m.order {
-		if _, ok := other.data[key]; !ok {
-			result.Add(key, m.data[key])
+// Difference returns a new ordered map containing elements present in the
+// original map but in none of the other maps, in the order of the original
+// map, in a single pass.
+func (m *SafeOrderedMap[T]) Difference(others ...*SafeOrderedMap[T]) *SafeOrderedMap[T] {
+	result := m.derive()
+
+	for _, entry := range m.Entries() {
+		if !anyHas(others, entry.Key) {
+			result.Add(entry.Key, entry.Value)
 		}
 	}
 
@@ -443,11 +1498,21 @@ func (m *SafeOrderedMap[T]) Difference(other *SafeOrderedMap[T]) *SafeOrderedMap
 // Subset checks if all elements of the original map are present in the other
 // map.
 func (m *SafeOrderedMap[T]) Subset(other *SafeOrderedMap[T]) bool {
-	m.RLock()
-	defer m.RUnlock()
+	// The keys of the other map are copied first, so its lock isn't held
+	// with the one of m, like IsDisjoint.
+	keys := other.Keys()
+
+	slots := make(map[string]struct{}, len(keys))
+
+	for _, key := range keys {
+		slots[other.slot(key)] = struct{}{}
+	}
+
+	m.rlock()
+	defer m.runlock()
 
-	for _, key := range m.order {
-		if _, ok := other.data[key]; !ok {
+	for e := m.head; e != nil; e = e.next {
+		if _, ok := slots[other.slot(e.key)]; !ok {
 			return false
 		}
 	}
@@ -460,17 +1525,91 @@ func (m *SafeOrderedMap[T]) Superset(other *SafeOrderedMap[T]) bool {
 	return other.Subset(m)
 }
 
-// Intersection returns a new ordered map containing elements present in both
-// maps.
-func (m *SafeOrderedMap[T]) Intersection(other *SafeOrderedMap[T]) *SafeOrderedMap[T] {
-	m.RLock()
-	defer m.RUnlock()
+// IsDisjoint checks if the maps have no keys in common. It's cheaper than
+// checking if the Intersection is empty, as nothing is copied, and it stops at
+// the first common key.
+func (m *SafeOrderedMap[T]) IsDisjoint(other *SafeOrderedMap[T]) bool {
+	keys := other.Keys()
+
+	m.rlock()
+	defer m.runlock()
+
+	for _, key := range keys {
+		if _, ok := m.data[m.slot(key)]; ok {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Equal checks if both maps have the same keys, with deeply equal values, like
+// reflect.DeepEqual, regardless of their order.
+func (m *SafeOrderedMap[T]) Equal(other *SafeOrderedMap[T]) bool {
+	entries := other.Entries()
+
+	m.rlock()
+	defer m.runlock()
+
+	if len(entries) != len(m.data) {
+		return false
+	}
+
+	for _, entry := range entries {
+		e, ok := m.data[m.slot(entry.Key)]
+		if !ok || !reflect.DeepEqual(e.value, entry.Value) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Intersection returns a new ordered map containing elements present in all
+// maps, in the order, and with the values of the original map, in a single
+// pass.
+func (m *SafeOrderedMap[T]) Intersection(others ...*SafeOrderedMap[T]) *SafeOrderedMap[T] {
+	result := m.derive()
+
+	for _, entry := range m.Entries() {
+		if allHave(others, entry.Key) {
+			result.Add(entry.Key, entry.Value)
+		}
+	}
+
+	return result
+}
+
+// SymmetricDifference returns a new ordered map containing elements present in
+// either map but not in both. Elements of the original map come first, in its
+// order, followed by the ones of the other map, in its order.
+func (m *SafeOrderedMap[T]) SymmetricDifference(other *SafeOrderedMap[T]) *SafeOrderedMap[T] {
+	others := other.Entries()
+
+	m.rlock()
+	defer m.runlock()
+
+	result := m.derive()
+
+	if other == m {
+		return result
+	}
 
-	result := New[T]()
+	keys := make(map[string]struct{}, len(others))
 
-	for _, key := range m.order {
-		if _, ok := other.data[key]; ok {
-			result.Add(key, m.data[key])
+	for _, entry := range others {
+		keys[m.slot(entry.Key)] = struct{}{}
+	}
+
+	for e := m.head; e != nil; e = e.next {
+		if _, ok := keys[m.slot(e.key)]; !ok {
+			result.Add(e.key, e.value)
+		}
+	}
+
+	for _, entry := range others {
+		if _, ok := m.data[m.slot(entry.Key)]; !ok {
+			result.Add(entry.Key, entry.Value)
 		}
 	}
 
@@ -481,71 +1620,314 @@ func (m *SafeOrderedMap[T]) Intersection(other *SafeOrderedMap[T]) *SafeOrderedM
 // Conversion Operations.
 //////
 
-// MarshalJSON implements json.Marshaler interface for SafeOrderedMap.
+// MarshalJSON implements json.Marshaler interface for SafeOrderedMap. If
+// WithEntriesJSON is enabled, the map is encoded as an array of entries.
 func (m *SafeOrderedMap[T]) MarshalJSON() ([]byte, error) {
-	m.RLock()
-	defer m.RUnlock()
+	m.rlock()
+	defer m.runlock()
 
-	jsonMap := make(map[string]T)
-
-	for _, key := range m.order {
-		jsonMap[key] = m.data[key]
+	if m.entriesJSON {
+		return json.Marshal(m.entries())
 	}
 
-	return json.Marshal(jsonMap)
+	return json.Marshal(m.toMap())
 }
 
-// UnmarshalJSON implements json.Unmarshaler interface for SafeOrderedMap.
+// UnmarshalJSON implements json.Unmarshaler interface for SafeOrderedMap. It
+// accepts both an object, and an array of entries, regardless of
+// WithEntriesJSON. Entries preserve their order.
 func (m *SafeOrderedMap[T]) UnmarshalJSON(data []byte) error {
-	m.Lock()
-	defer m.Unlock()
+	if isJSONArray(data) {
+		return m.unmarshalEntries(data)
+	}
 
-	var temp map[string]T
-	if err := json.Unmarshal(data, &temp); err != nil {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
 		return err
 	}
 
-	if m.data == nil {
-		m.data = make(map[string]T)
+	temp := make(map[string]T, len(raw))
+
+	for key, data := range raw {
+		value, err := m.decodeValue(key, data)
+		if err != nil {
+			return err
+		}
+
+		if err := m.validate(key, value); err != nil {
+			return err
+		}
+
+		temp[key] = value
+	}
+
+	keys := make([]string, 0, len(temp))
+	values := make([]T, 0, len(temp))
+
+	for key, value := range temp {
+		keys = append(keys, key)
+		values = append(values, value)
+	}
+
+	m.lock()
+	defer m.unlock()
+
+	m.load(keys, values)
+
+	return nil
+}
+
+// MarshalText implements the encoding.TextMarshaler interface. Entries are
+// formatted as comma-separated key=value pairs, in order, e.g.: a=1,b=2.
+// Commas, equal signs, and backslashes are escaped by a backslash.
+func (m *SafeOrderedMap[T]) MarshalText() ([]byte, error) {
+	m.rlock()
+	defer m.runlock()
+
+	pairs := make([]string, 0, len(m.data))
+
+	for e := m.head; e != nil; e = e.next {
+		value, err := shared.FormatText(e.value)
+		if err != nil {
+			return nil, err
+		}
+
+		pairs = append(pairs, shared.EscapeText(e.key, ",=")+"="+shared.EscapeText(value, ",="))
+	}
+
+	return []byte(strings.Join(pairs, ",")), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface, the inverse
+// of MarshalText. It replaces the content of the map.
+func (m *SafeOrderedMap[T]) UnmarshalText(text []byte) error {
+	keys := []string{}
+	values := []T{}
+
+	if len(text) > 0 {
+		for _, pair := range shared.SplitText(string(text), ',', -1) {
+			kv := shared.SplitText(pair, '=', 2)
+			if len(kv) != 2 {
+				return fmt.Errorf("invalid key=value pair %q", pair)
+			}
+
+			value, err := shared.ParseText[T](shared.UnescapeText(kv[1]))
+			if err != nil {
+				return err
+			}
+
+			keys = append(keys, shared.UnescapeText(kv[0]))
+			values = append(values, value)
+		}
+	}
+
+	if err := m.validateAll(keys, values); err != nil {
+		return err
+	}
+
+	m.lock()
+	defer m.unlock()
+
+	m.load(keys, values)
+
+	return nil
+}
+
+// EncodeWith encodes the map as a map, preserving the insertion order of the
+// keys. It's meant to be used with order preserving formats such as
+// MessagePack, and CBOR.
+func (m *SafeOrderedMap[T]) EncodeWith(enc shared.Encoder) error {
+	m.rlock()
+	defer m.runlock()
+
+	if err := enc.EncodeMapLen(len(m.data)); err != nil {
+		return err
+	}
+
+	for e := m.head; e != nil; e = e.next {
+		if err := enc.EncodeString(e.key); err != nil {
+			return err
+		}
+
+		if err := enc.Encode(e.value); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// DecodeWith decodes a map encoded by EncodeWith, preserving the order of the
+// keys. It replaces the content of the map.
+func (m *SafeOrderedMap[T]) DecodeWith(dec shared.Decoder) error {
+	n, err := dec.DecodeMapLen()
+	if err != nil {
+		return err
 	}
 
-	m.order = []string{}
+	keys := []string{}
+	values := []T{}
+
+	for i := 0; i < n; i++ {
+		key, err := dec.DecodeString()
+		if err != nil {
+			return err
+		}
+
+		var value T
+
+		if err := dec.Decode(&value); err != nil {
+			return err
+		}
 
-	for key := range temp {
-		m.order = append(m.order, key)
+		keys = append(keys, key)
+		values = append(values, value)
+	}
 
-		m.data[key] = temp[key]
+	if err := m.validateAll(keys, values); err != nil {
+		return err
 	}
 
+	m.lock()
+	defer m.unlock()
+
+	m.load(keys, values)
+
 	return nil
 }
 
-// MarshalBSON implements bson.Marshaler interface for SafeOrderedMap.
+// Value implements the driver.Valuer interface, storing the map as JSON.
+func (m *SafeOrderedMap[T]) Value() (driver.Value, error) {
+	if m == nil {
+		return nil, nil
+	}
+
+	b, err := m.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+
+	return string(b), nil
+}
+
+// Scan implements the sql.Scanner interface, loading the map from JSON.
+func (m *SafeOrderedMap[T]) Scan(src any) error {
+	return shared.ScanJSON(src, m.UnmarshalJSON)
+}
+
+// MarshalBSON implements bson.Marshaler interface for SafeOrderedMap. The map
+// is encoded as a BSON document preserving the insertion order of the keys,
+// like a bson.D. Values keep their BSON types, like with the MongoDB driver,
+// e.g.: int64, time.Time as a datetime, []byte as binary, or
+// primitive.ObjectID.
 func (m *SafeOrderedMap[T]) MarshalBSON() ([]byte, error) {
-	m.RLock()
-	defer m.RUnlock()
+	m.rlock()
+	defer m.runlock()
+
+	d := make(bsonjson.D, 0, len(m.data))
+
+	for e := m.head; e != nil; e = e.next {
+		d = append(d, bsonjson.E{Key: e.key, Value: e.value})
+	}
 
-	return m.MarshalJSON()
+	return d.MarshalBSON()
 }
 
-// UnmarshalBSON implements bson.Unmarshaler interface for SafeOrderedMap.
+// UnmarshalBSON implements bson.Unmarshaler interface for SafeOrderedMap,
+// preserving the order of the keys. It replaces the content of the map.
 func (m *SafeOrderedMap[T]) UnmarshalBSON(data []byte) error {
-	m.Lock()
-	defer m.Unlock()
+	elements, err := bsonjson.Elements(data)
+	if err != nil {
+		return err
+	}
+
+	keys := make([]string, 0, len(elements))
+	values := make([]T, 0, len(elements))
+
+	for _, element := range elements {
+		value, err := m.decodeValue(element.Key, element.Value)
+		if err != nil {
+			return err
+		}
+
+		keys = append(keys, element.Key)
+		values = append(values, value)
+	}
+
+	if err := m.validateAll(keys, values); err != nil {
+		return err
+	}
 
-	return m.UnmarshalJSON(data)
+	m.lock()
+	defer m.unlock()
+
+	m.load(keys, values)
+
+	return nil
+}
+
+//////
+// Helpers.
+//////
+
+// anyHas checks if any of the maps has the key.
+func anyHas[T any](maps []*SafeOrderedMap[T], key string) bool {
+	for _, m := range maps {
+		if _, ok := m.Peek(key); ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+// allHave checks if all the maps have the key.
+func allHave[T any](maps []*SafeOrderedMap[T], key string) bool {
+	for _, m := range maps {
+		if _, ok := m.Peek(key); !ok {
+			return false
+		}
+	}
+
+	return true
 }
 
 //////
 // Factory.
 //////
 
+// WithMetrics enables metrics instrumentation, tracking operation counts,
+// size, lock wait times, and hit/miss ratios into the given metrics.
+func WithMetrics[T any](mtrcs *metrics.Metrics) Option[T] {
+	return func(m *SafeOrderedMap[T]) {
+		m.metrics = mtrcs
+	}
+}
+
 // New creates a new Safe Ordered Map.
-func New[T any]() *SafeOrderedMap[T] {
-	return &SafeOrderedMap[T]{
-		data:  make(map[string]T),
-		order: []string{},
+func New[T any](opts ...Option[T]) *SafeOrderedMap[T] {
+	m := &SafeOrderedMap[T]{
+		data: make(map[string]*element[T]),
 
 		RWMutex: sync.RWMutex{},
 	}
+
+	for _, opt := range opts {
+		opt(m)
+	}
+
+	return m
+}
+
+// NewFromJSON creates a new Safe Ordered Map configured with the given
+// options, loaded from JSON, an object, or an array of entries, keeping the
+// order of the keys, see Decode.
+func NewFromJSON[T any](data []byte, opts ...Option[T]) (*SafeOrderedMap[T], error) {
+	m := New(opts...)
+
+	if err := m.Decode(bytes.NewReader(data)); err != nil {
+		return nil, err
+	}
+
+	return m, nil
 }
@@ -1,10 +1,21 @@
 package safeorderedmap
 
 import (
+	"database/sql"
+	"database/sql/driver"
 	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"strconv"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/thalesfsp/go-common-types/internal/bsonjson"
+	"github.com/thalesfsp/go-common-types/internal/codectest"
+	"github.com/thalesfsp/go-common-types/metrics"
 )
 
 func TestSafeOrderedMapString(t *testing.T) {
@@ -14,6 +25,65 @@ func TestSafeOrderedMapString(t *testing.T) {
 	assert.Equal(t, `{"1":1,"2":2,"3":3}`, s.String())
 }
 
+func TestSafeOrderedMapStringFallback(t *testing.T) {
+	s := New[any]()
+	s.Add("b", 1).Add("a", make(chan int))
+
+	assert.True(t, strings.HasPrefix(s.String(), `map[string]interface {}{"b":1, "a":(chan int)(0x`))
+
+	_, err := s.StringIndent("", "  ")
+	assert.Error(t, err)
+}
+
+func TestSafeOrderedMapStringIndent(t *testing.T) {
+	s := New[int]()
+	s.Add("1", 1).Add("2", 2)
+
+	str, err := s.StringIndent("", "  ")
+	assert.NoError(t, err)
+	assert.Equal(t, "{\n  \"1\": 1,\n  \"2\": 2\n}", str)
+}
+
+func TestSafeOrderedMapFormat(t *testing.T) {
+	s := New[int]()
+	s.Add("b", 2).Add("a", 1)
+
+	assert.Equal(t, `{"a":1,"b":2}`, fmt.Sprintf("%v", s))
+	assert.Equal(t, `{"a":1,"b":2}`, fmt.Sprintf("%s", s))
+	assert.Equal(t, `map[b:2 a:1]`, fmt.Sprintf("%+v", s))
+	assert.Equal(t, `map[string]int{"b":2, "a":1}`, fmt.Sprintf("%#v", s))
+	assert.Equal(t, `%!d({"a":1,"b":2})`, fmt.Sprintf("%d", s))
+}
+
+func TestSafeOrderedMapPrettyString(t *testing.T) {
+	s := New[int]()
+	s.Add("b", 2).Add("a", 1)
+
+	assert.Equal(t, "{\n  \"b\": 2,\n  \"a\": 1\n}", s.PrettyString())
+	assert.Equal(t, "{}", New[int]().PrettyString())
+}
+
+func TestSafeOrderedMapTable(t *testing.T) {
+	type config struct {
+		Name  string
+		Value int
+	}
+
+	s := New[config]()
+	s.Add("timeout", config{"Timeout", 30}).Add("retries", config{"Retries", 3})
+
+	var buf strings.Builder
+
+	err := s.Table(&buf, func(c config) string { return c.Name }, func(c config) string { return strconv.Itoa(c.Value) })
+	assert.NoError(t, err)
+	assert.Equal(t, "timeout  Timeout  30\nretries  Retries  3\n", buf.String())
+
+	buf.Reset()
+
+	assert.NoError(t, New[int]().Add("a", 1).Add("bb", 2).Table(&buf))
+	assert.Equal(t, "a   1\nbb  2\n", buf.String())
+}
+
 func TestSafeOrderedMapAdd(t *testing.T) {
 	s := New[int]()
 	s.Add("1", 1).Add("2", 2).Add("3", 3)
@@ -53,6 +123,15 @@ func TestSafeOrderedMapDelete(t *testing.T) {
 	assert.False(t, s.Contains("3"))
 }
 
+func TestSafeOrderedMapDeleteByIndex(t *testing.T) {
+	s := New[int]()
+	s.Add("1", 1).Add("2", 2).Add("3", 3)
+
+	s.DeleteByIndex(1).DeleteByIndex(5).DeleteByIndex(-1)
+
+	assert.Equal(t, []string{"1", "3"}, s.Keys())
+}
+
 func TestSafeOrderedMapKeys(t *testing.T) {
 	s := New[int]()
 	s.Add("1", 1).Add("2", 2).Add("3", 3)
@@ -294,6 +373,45 @@ func TestSafeOrderedMapIntersection(t *testing.T) {
 	assert.Equal(t, []int{2, 3}, s1.Intersection(s2).Values())
 }
 
+func TestSafeOrderedMapIsDisjointEqual(t *testing.T) {
+	s1 := New[int]()
+	s1.Add("1", 1).Add("2", 2)
+
+	s2 := New[int]()
+	s2.Add("3", 3)
+
+	assert.True(t, s1.IsDisjoint(s2))
+
+	s2.Add("2", 20)
+
+	assert.False(t, s1.IsDisjoint(s2))
+
+	s3 := New[int]()
+	s3.Add("2", 2).Add("1", 1)
+
+	assert.True(t, s1.Equal(s3))
+	assert.False(t, s1.Equal(s2))
+
+	s3.Add("2", 20)
+
+	assert.False(t, s1.Equal(s3))
+}
+
+func TestSafeOrderedMapSymmetricDifference(t *testing.T) {
+	s1 := New[int]()
+	s1.Add("1", 1).Add("2", 2).Add("3", 3)
+
+	s2 := New[int]()
+	s2.Add("4", 4).Add("3", 30).Add("2", 20)
+
+	diff := s1.SymmetricDifference(s2)
+
+	assert.Equal(t, []string{"1", "4"}, diff.Keys())
+	assert.Equal(t, []int{1, 4}, diff.Values())
+	assert.Equal(t, []string{"4", "1"}, s2.SymmetricDifference(s1).Keys())
+	assert.Equal(t, 0, s1.SymmetricDifference(s1).Size())
+}
+
 func TestSafeOrderedMapMarshalJSON(t *testing.T) {
 	s := New[int]()
 	s.Add("1", 1).Add("2", 2).Add("3", 3)
@@ -373,3 +491,544 @@ func TestSafeOrderedMapMarshalUnmarshal(t *testing.T) {
 	n, _ := s2.Get("1")
 	assert.Equal(t, 1, n)
 }
+
+func TestSafeOrderedMapWithMetrics(t *testing.T) {
+	mtrcs := metrics.New("som")
+
+	s := New[int](WithMetrics[int](mtrcs))
+	s.Add("1", 1).Add("2", 2).Add("3", 3).Delete("3")
+
+	s.Get("1")
+	s.Get("4")
+	s.Contains("2")
+
+	snapshot := s.Metrics().Snapshot()
+
+	assert.Equal(t, uint64(3), snapshot.Operations["add"])
+	assert.Equal(t, uint64(1), snapshot.Operations["delete"])
+	assert.Equal(t, int64(2), snapshot.Size)
+	assert.Equal(t, uint64(2), snapshot.Hits)
+	assert.Equal(t, uint64(1), snapshot.Misses)
+	assert.NotZero(t, snapshot.LockAcquisitions)
+}
+
+func TestSafeOrderedMapDeleteKeepsOrder(t *testing.T) {
+	s := New[int]()
+	s.Add("1", 1).Add("2", 2).Add("3", 3).Add("4", 4).Add("5", 5)
+
+	s.Delete("1").Delete("3").Delete("5").Delete("6")
+
+	assert.Equal(t, []string{"2", "4"}, s.Keys())
+	assert.Equal(t, []int{2, 4}, s.Values())
+
+	k, v, ok := s.First()
+	assert.Equal(t, "2", k)
+	assert.Equal(t, 2, v)
+	assert.True(t, ok)
+
+	k, v, ok = s.Last()
+	assert.Equal(t, "4", k)
+	assert.Equal(t, 4, v)
+	assert.True(t, ok)
+
+	i, v, ok := s.Index("4")
+	assert.Equal(t, 1, i)
+	assert.Equal(t, 4, v)
+	assert.True(t, ok)
+
+	r, ok := s.GetByIndex(1)
+	assert.Equal(t, 4, r)
+	assert.True(t, ok)
+
+	s.Add("1", 1)
+	assert.Equal(t, []string{"2", "4", "1"}, s.Keys())
+
+	s.Delete("2").Delete("4").Delete("1")
+	assert.True(t, s.Empty())
+
+	_, _, ok = s.First()
+	assert.False(t, ok)
+
+	_, _, ok = s.Last()
+	assert.False(t, ok)
+}
+
+func TestSafeOrderedMapGetByIndexFromBothEnds(t *testing.T) {
+	s := New[int]()
+
+	for i := 0; i < 10; i++ {
+		s.Add(strconv.Itoa(i), i)
+	}
+
+	for i := 0; i < 10; i++ {
+		v, ok := s.GetByIndex(i)
+		assert.True(t, ok)
+		assert.Equal(t, i, v)
+	}
+
+	_, ok := s.GetByIndex(10)
+	assert.False(t, ok)
+
+	_, ok = s.GetByIndex(-1)
+	assert.False(t, ok)
+}
+
+var benchmarkSizes = []int{1_000, 100_000, 1_000_000}
+
+func newBenchmarkMap(n int) (*SafeOrderedMap[int], []string) {
+	m := New[int]()
+
+	keys := make([]string, n)
+
+	for i := 0; i < n; i++ {
+		keys[i] = strconv.Itoa(i)
+
+		m.Add(keys[i], i)
+	}
+
+	return m, keys
+}
+
+func BenchmarkSafeOrderedMapAdd(b *testing.B) {
+	for _, n := range benchmarkSizes {
+		b.Run(fmt.Sprintf("%d", n), func(b *testing.B) {
+			m, _ := newBenchmarkMap(n)
+
+			b.ResetTimer()
+
+			for i := 0; i < b.N; i++ {
+				m.Add(strconv.Itoa(n+i), i)
+			}
+		})
+	}
+}
+
+func BenchmarkSafeOrderedMapGet(b *testing.B) {
+	for _, n := range benchmarkSizes {
+		b.Run(fmt.Sprintf("%d", n), func(b *testing.B) {
+			m, keys := newBenchmarkMap(n)
+
+			b.ResetTimer()
+
+			for i := 0; i < b.N; i++ {
+				m.Get(keys[i%n])
+			}
+		})
+	}
+}
+
+func BenchmarkSafeOrderedMapGetByIndex(b *testing.B) {
+	for _, n := range benchmarkSizes {
+		b.Run(fmt.Sprintf("%d", n), func(b *testing.B) {
+			m, _ := newBenchmarkMap(n)
+
+			b.ResetTimer()
+
+			for i := 0; i < b.N; i++ {
+				m.GetByIndex((i * 7919) % n)
+			}
+		})
+	}
+}
+
+func BenchmarkSafeOrderedMapIndex(b *testing.B) {
+	for _, n := range benchmarkSizes {
+		b.Run(fmt.Sprintf("%d", n), func(b *testing.B) {
+			m, keys := newBenchmarkMap(n)
+
+			b.ResetTimer()
+
+			for i := 0; i < b.N; i++ {
+				m.Index(keys[(i*7919)%n])
+			}
+		})
+	}
+}
+
+func BenchmarkSafeOrderedMapDelete(b *testing.B) {
+	for _, n := range benchmarkSizes {
+		b.Run(fmt.Sprintf("%d", n), func(b *testing.B) {
+			m, keys := newBenchmarkMap(n)
+
+			b.ResetTimer()
+
+			for i := 0; i < b.N; i++ {
+				key := keys[(i*7919)%n]
+
+				m.Delete(key)
+				m.Add(key, i)
+			}
+		})
+	}
+}
+
+func BenchmarkSafeOrderedMapValues(b *testing.B) {
+	for _, n := range benchmarkSizes {
+		b.Run(fmt.Sprintf("%d", n), func(b *testing.B) {
+			m, _ := newBenchmarkMap(n)
+
+			b.ResetTimer()
+
+			for i := 0; i < b.N; i++ {
+				m.Values()
+			}
+		})
+	}
+}
+
+func TestSafeOrderedMapValueScan(t *testing.T) {
+	var (
+		_ driver.Valuer = New[int]()
+		_ sql.Scanner   = New[int]()
+	)
+
+	s := New[int]()
+	s.Add("1", 1).Add("2", 2)
+
+	v, err := s.Value()
+	assert.NoError(t, err)
+	assert.Equal(t, `{"1":1,"2":2}`, v)
+
+	s2 := New[int]()
+	assert.NoError(t, s2.Scan(v))
+	assert.ElementsMatch(t, []string{"1", "2"}, s2.Keys())
+
+	assert.NoError(t, s2.Scan([]byte(`{"3":3}`)))
+	assert.Equal(t, []string{"3"}, s2.Keys())
+
+	assert.NoError(t, s2.Scan(nil))
+	assert.True(t, s2.Empty())
+
+	assert.Error(t, s2.Scan(1))
+
+	var nilMap *SafeOrderedMap[int]
+
+	v, err = nilMap.Value()
+	assert.NoError(t, err)
+	assert.Nil(t, v)
+}
+
+func TestSafeOrderedMapMarshalUnmarshalText(t *testing.T) {
+	s := New[string]()
+	s.Add("b", "2").Add("a=x", "1,2")
+
+	text, err := s.MarshalText()
+	assert.NoError(t, err)
+	assert.Equal(t, `b=2,a\=x=1\,2`, string(text))
+
+	s2 := New[string]()
+	assert.NoError(t, s2.UnmarshalText(text))
+	assert.Equal(t, []string{"b", "a=x"}, s2.Keys())
+	assert.Equal(t, []string{"2", "1,2"}, s2.Values())
+
+	assert.Error(t, s2.UnmarshalText([]byte("a")))
+
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	labels := New[int]()
+	fs.TextVar(labels, "labels", New[int](), "labels")
+
+	assert.NoError(t, fs.Parse([]string{"-labels", "x=1,y=2"}))
+	assert.Equal(t, []string{"x", "y"}, labels.Keys())
+	assert.Equal(t, []int{1, 2}, labels.Values())
+}
+
+func TestSafeOrderedMapEncodeDecodeWith(t *testing.T) {
+	s := New[int]()
+	s.Add("b", 2).Add("a", 1).Add("c", 3)
+
+	c := &codectest.TokenCodec{}
+
+	assert.NoError(t, s.EncodeWith(c))
+	assert.Equal(t, []any{3, "b", 2, "a", 1, "c", 3}, c.Tokens)
+
+	s2 := New[int]()
+	s2.Add("x", 1)
+
+	assert.NoError(t, s2.DecodeWith(c))
+	assert.Equal(t, []string{"b", "a", "c"}, s2.Keys())
+	assert.Equal(t, []int{2, 1, 3}, s2.Values())
+
+	assert.Error(t, s2.DecodeWith(&codectest.TokenCodec{Tokens: []any{1, "a"}}))
+	assert.Equal(t, []string{"b", "a", "c"}, s2.Keys())
+}
+
+func TestSafeOrderedMapMarshalUnmarshalBSON(t *testing.T) {
+	type point struct {
+		X int `json:"x"`
+		Y int `json:"y"`
+	}
+
+	s := New[point]()
+	s.Add("b", point{1, 2}).Add("a", point{3, 4})
+
+	b, err := s.MarshalBSON()
+	assert.NoError(t, err)
+
+	s2 := New[point]()
+	assert.NoError(t, s2.UnmarshalBSON(b))
+	assert.Equal(t, []string{"b", "a"}, s2.Keys())
+	assert.Equal(t, []point{{1, 2}, {3, 4}}, s2.Values())
+
+	assert.Error(t, s2.UnmarshalBSON([]byte{1, 2}))
+}
+
+func TestSafeOrderedMapMarshalBSONTypes(t *testing.T) {
+	at := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	m := New[any]()
+	m.Add("id", int64(9007199254740993)).Add("at", at).Add("raw", []byte{1})
+
+	b, err := m.MarshalBSON()
+	assert.NoError(t, err)
+
+	// Values keep their types, in order, like a bson.D.
+	expected, err := bsonjson.D{
+		{Key: "id", Value: int64(9007199254740993)},
+		{Key: "at", Value: at},
+		{Key: "raw", Value: []byte{1}},
+	}.MarshalBSON()
+	assert.NoError(t, err)
+	assert.Equal(t, expected, b)
+
+	times := New[time.Time]()
+	times.Add("at", at)
+
+	b, err = times.MarshalBSON()
+	assert.NoError(t, err)
+
+	decoded := New[time.Time]()
+	assert.NoError(t, decoded.UnmarshalBSON(b))
+
+	got, _ := decoded.Get("at")
+	assert.True(t, at.Equal(got))
+}
+
+func TestSafeOrderedMapSetRemove(t *testing.T) {
+	m := New[int]()
+
+	m.Set("a", 1)
+	assert.True(t, m.SetIfAbsent("b", 2))
+	assert.False(t, m.SetIfAbsent("a", 10))
+
+	assert.Equal(t, []int{1, 2}, m.Values())
+	assert.True(t, m.Remove("a"))
+	assert.False(t, m.Remove("a"))
+	assert.Equal(t, []string{"b"}, m.Keys())
+}
+
+func TestSafeOrderedMapReplaceSwap(t *testing.T) {
+	m := New(WithValidator(positive))
+	m.Add("a", 1)
+
+	assert.True(t, m.AddIfAbsent("b", 2))
+	assert.False(t, m.AddIfAbsent("b", 3))
+
+	old, ok := m.Replace("a", 10)
+	assert.True(t, ok)
+	assert.Equal(t, 1, old)
+
+	_, ok = m.Replace("missing", 1)
+	assert.False(t, ok)
+
+	_, ok = m.Replace("a", -1)
+	assert.False(t, ok)
+
+	previous, loaded := m.Swap("c", 3)
+	assert.False(t, loaded)
+	assert.Equal(t, 0, previous)
+
+	previous, loaded = m.Swap("c", 4)
+	assert.True(t, loaded)
+	assert.Equal(t, 3, previous)
+
+	previous, loaded = m.Swap("c", -4)
+	assert.True(t, loaded)
+	assert.Equal(t, 4, previous)
+
+	assert.Equal(t, []string{"a", "b", "c"}, m.Keys())
+	assert.Equal(t, []int{10, 2, 4}, m.Values())
+	assert.False(t, m.Contains("missing"))
+}
+
+func TestSafeOrderedMapTryFunctions(t *testing.T) {
+	errNegative := errors.New("negative")
+
+	m := New[int]()
+	m.Add("a", 1).Add("b", 2)
+
+	doubled, err := m.TryMap(func(_ string, v int) (int, error) { return v * 2, nil })
+	assert.NoError(t, err)
+	assert.Equal(t, []int{2, 4}, doubled.Values())
+
+	even, err := m.TryFilter(func(_ string, v int) (bool, error) { return v%2 == 0, nil })
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"b"}, even.Keys())
+
+	sum, err := m.TryReduce(func(acc int, _ string, v int) (int, error) { return acc + v, nil }, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, sum)
+
+	m.Add("c", -1).Add("d", 4)
+
+	failing := func(_ string, v int) (int, error) {
+		if v < 0 {
+			return 0, errNegative
+		}
+
+		return v, nil
+	}
+
+	_, err = m.TryMap(failing)
+	assert.ErrorIs(t, err, errNegative)
+
+	_, err = m.TryFilter(func(k string, v int) (bool, error) {
+		_, err := failing(k, v)
+
+		return true, err
+	})
+	assert.ErrorIs(t, err, errNegative)
+
+	seen := []string{}
+
+	err = m.TryEach(func(k string, v int) error {
+		seen = append(seen, k)
+
+		_, err := failing(k, v)
+
+		return err
+	})
+	assert.ErrorIs(t, err, errNegative)
+	assert.Equal(t, []string{"a", "b", "c"}, seen)
+
+	sum, err = m.TryReduce(func(acc int, k string, v int) (int, error) {
+		v, err := failing(k, v)
+
+		return acc + v, err
+	}, 0)
+	assert.ErrorIs(t, err, errNegative)
+	assert.Equal(t, 0, sum)
+}
+
+func TestSafeOrderedMapSearch(t *testing.T) {
+	m := New[int]()
+	m.Add("a", 1).Add("b", 2).Add("c", 3).Add("d", 4)
+
+	even := func(_ string, v int) bool { return v%2 == 0 }
+	none := func(_ string, v int) bool { return v > 10 }
+
+	key, value, ok := m.FindLast(even)
+	assert.True(t, ok)
+	assert.Equal(t, "d", key)
+	assert.Equal(t, 4, value)
+
+	_, _, ok = m.FindLast(none)
+	assert.False(t, ok)
+
+	assert.Equal(t, 1, m.FindIndex(even))
+	assert.Equal(t, -1, m.FindIndex(none))
+
+	assert.Equal(t, []string{"b", "d"}, m.FindAll(even))
+	assert.Empty(t, m.FindAll(none))
+
+	assert.Equal(t, 2, m.Count(even))
+	assert.Equal(t, 0, m.Count(none))
+}
+
+func TestSafeOrderedMapPrefix(t *testing.T) {
+	m := New[int]()
+	m.Add("tenantA:x", 1).Add("tenantB:x", 2).Add("tenantA:y", 3).Add("tenant", 4)
+
+	assert.Equal(t, []string{"tenantA:x", "tenantA:y"}, m.KeysWithPrefix("tenantA:"))
+	assert.Empty(t, m.KeysWithPrefix("tenantC:"))
+	assert.Equal(t, []string{"tenant"}, m.KeysFunc(func(key string) bool { return !strings.Contains(key, ":") }))
+
+	b := m.FilterKeys(func(key string) bool { return strings.HasPrefix(key, "tenantB:") })
+	assert.Equal(t, []int{2}, b.Values())
+
+	assert.Equal(t, 2, m.DeletePrefix("tenantA:"))
+	assert.Equal(t, 0, m.DeletePrefix("tenantA:"))
+	assert.Equal(t, []string{"tenantB:x", "tenant"}, m.Keys())
+}
+
+func TestSafeOrderedMapDeleteMany(t *testing.T) {
+	m := New[int]()
+	m.Add("a", 1).Add("b", 2).Add("c", 3).Add("d", 4).Add("e", 5)
+
+	assert.Equal(t, 2, m.DeleteMany("a", "c", "x"))
+	assert.Equal(t, 0, m.DeleteMany())
+	assert.Equal(t, []string{"b", "d", "e"}, m.Keys())
+
+	assert.Equal(t, 2, m.DeleteFunc(func(_ string, value int) bool { return value%2 == 0 }))
+	assert.Equal(t, 0, m.DeleteFunc(func(_ string, value int) bool { return value%2 == 0 }))
+	assert.Equal(t, []string{"e"}, m.Keys())
+	assert.Equal(t, 1, m.Size())
+}
+
+func TestSafeOrderedMapRenameKey(t *testing.T) {
+	m := New[int]()
+	m.Add("a", 1).Add("b", 2).Add("c", 3)
+
+	assert.NoError(t, m.RenameKey("b", "x"))
+	assert.Equal(t, []string{"a", "x", "c"}, m.Keys())
+	assert.Equal(t, []int{1, 2, 3}, m.Values())
+	assert.False(t, m.Contains("b"))
+
+	v, ok := m.Get("x")
+	assert.True(t, ok)
+	assert.Equal(t, 2, v)
+
+	assert.NoError(t, m.RenameKey("x", "x"))
+	assert.ErrorIs(t, m.RenameKey("missing", "y"), ErrKeyNotFound)
+	assert.ErrorIs(t, m.RenameKey("a", "c"), ErrKeyExists)
+
+	assert.NoError(t, m.ReplaceKey("a", "z", 26))
+	assert.Equal(t, []string{"z", "x", "c"}, m.Keys())
+	assert.Equal(t, []int{26, 2, 3}, m.Values())
+
+	assert.NoError(t, m.ReplaceKey("z", "z", 0))
+	assert.Equal(t, []int{0, 2, 3}, m.Values())
+	assert.ErrorIs(t, m.ReplaceKey("z", "c", 0), ErrKeyExists)
+}
+
+func TestSafeOrderedMapVariadicSetOperations(t *testing.T) {
+	a := FromEntries([]Entry[int]{{Key: "a", Value: 1}, {Key: "b", Value: 2}, {Key: "c", Value: 3}})
+	b := FromEntries([]Entry[int]{{Key: "b", Value: 20}, {Key: "d", Value: 40}})
+	c := FromEntries([]Entry[int]{{Key: "b", Value: 200}, {Key: "d", Value: 400}, {Key: "e", Value: 500}})
+
+	// Keys keep the value of the first map having them.
+	assert.Equal(t, []Entry[int]{
+		{Key: "a", Value: 1},
+		{Key: "b", Value: 2},
+		{Key: "c", Value: 3},
+		{Key: "d", Value: 40},
+		{Key: "e", Value: 500},
+	}, a.Union(b, c).Entries())
+	assert.Equal(t, []Entry[int]{{Key: "b", Value: 2}}, a.Intersection(b, c).Entries())
+	assert.Equal(t, []string{"a", "c"}, a.Difference(b, c).Keys())
+	assert.Equal(t, []string{"a"}, a.Difference(b, New[int]().Add("c", 0)).Keys())
+}
+
+func TestSafeOrderedMapZeroValue(t *testing.T) {
+	var config struct {
+		Limits SafeOrderedMap[int] `json:"limits"`
+	}
+
+	assert.NoError(t, json.Unmarshal([]byte(`{"limits":{"b":2,"a":1}}`), &config))
+	assert.ElementsMatch(t, []string{"b", "a"}, config.Limits.Keys())
+
+	var m SafeOrderedMap[int]
+
+	m.Add("a", 1).Delete("b")
+
+	assert.Equal(t, []int{1}, m.Values())
+}
+
+func TestNewFromJSON(t *testing.T) {
+	m, err := NewFromJSON[int]([]byte(`{"b":2,"a":1}`), WithCaseInsensitiveKeys[int]())
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"b", "a"}, m.Keys())
+	assert.True(t, m.Contains("B"))
+
+	_, err = NewFromJSON[int]([]byte(`[`))
+	assert.Error(t, err)
+}
@@ -0,0 +1,89 @@
+package safeorderedmap
+
+import (
+	"encoding/base64"
+	"strconv"
+	"strings"
+)
+
+//////
+// Methods.
+//////
+
+// after returns the element following the position of the cursor, nil if
+// there's none, and false if the cursor is invalid. Caller must hold the lock.
+func (m *SafeOrderedMap[T]) after(cursor string) (*element[T], bool) {
+	if cursor == "" {
+		return m.head, true
+	}
+
+	decoded, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, false
+	}
+
+	rawSeq, key, ok := strings.Cut(string(decoded), ":")
+	if !ok {
+		return nil, false
+	}
+
+	seq, err := strconv.ParseUint(rawSeq, 36, 64)
+	if err != nil {
+		return nil, false
+	}
+
+	// Fast path: the last element returned is still there.
+	if e, ok := m.data[m.slot(key)]; ok && e.seq == seq {
+		return e.next, true
+	}
+
+	// Otherwise, elements are in increasing sequence, so the next one is the
+	// first with a greater sequence.
+	var next *element[T]
+
+	for e := m.tail; e != nil && e.seq > seq; e = e.prev {
+		next = e
+	}
+
+	return next, true
+}
+
+// ScanPage returns up to limit entries, in order, starting after the cursor,
+// and the cursor of the next page, empty when there are no more entries. The
+// first page is requested with an empty cursor, and a limit <= 0 returns all
+// the remaining entries. Cursors are opaque, URL-safe strings, e.g.: for the
+// paginated listing of a large map by an API, each page holding the lock only
+// while it's read.
+//
+// The map can be modified between pages: deleted entries are skipped, and
+// entries added after the cursor are returned by the following pages, while
+// updated ones keep their position. SortFunc, or UnmarshalJSON, re-add all
+// the entries, so a scan spanning them sees the entries again. An invalid
+// cursor returns no entries.
+func (m *SafeOrderedMap[T]) ScanPage(cursor string, limit int) ([]Entry[T], string) {
+	m.rlock()
+	defer m.runlock()
+
+	m.metrics.Operation("scan")
+
+	e, ok := m.after(cursor)
+	if !ok {
+		return []Entry[T]{}, ""
+	}
+
+	entries := []Entry[T]{}
+
+	for ; e != nil; e = e.next {
+		if limit > 0 && len(entries) == limit {
+			last := e.prev
+
+			return entries, base64.RawURLEncoding.EncodeToString(
+				[]byte(strconv.FormatUint(last.seq, 36) + ":" + last.key),
+			)
+		}
+
+		entries = append(entries, Entry[T]{Key: e.key, Value: e.value})
+	}
+
+	return entries, ""
+}
@@ -0,0 +1,72 @@
+package safeorderedmap
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScanPage(t *testing.T) {
+	m := New[int]()
+
+	for i := 0; i < 5; i++ {
+		m.Add(fmt.Sprint("k", i), i)
+	}
+
+	page, cursor := m.ScanPage("", 2)
+
+	assert.Equal(t, []Entry[int]{{"k0", 0}, {"k1", 1}}, page)
+	assert.NotEmpty(t, cursor)
+
+	// Concurrent changes: the last returned, and a following entry are
+	// deleted, a previous one is updated, and a new one is added.
+	m.Delete("k1").Delete("k2").Add("k0", 10).Add("k5", 5)
+
+	page, cursor = m.ScanPage(cursor, 2)
+
+	assert.Equal(t, []Entry[int]{{"k3", 3}, {"k4", 4}}, page)
+
+	page, cursor = m.ScanPage(cursor, 2)
+
+	assert.Equal(t, []Entry[int]{{"k5", 5}}, page)
+	assert.Empty(t, cursor)
+
+	all, cursor := m.ScanPage("", 0)
+
+	assert.Len(t, all, 4)
+	assert.Empty(t, cursor)
+
+	page, cursor = m.ScanPage("invalid!", 2)
+
+	assert.Empty(t, page)
+	assert.Empty(t, cursor)
+}
+
+func TestScanPageAll(t *testing.T) {
+	m := New[int]()
+
+	for i := 0; i < 100; i++ {
+		m.Add(fmt.Sprint(i), i)
+	}
+
+	var (
+		seen   []int
+		page   []Entry[int]
+		cursor string
+	)
+
+	for {
+		page, cursor = m.ScanPage(cursor, 7)
+
+		for _, entry := range page {
+			seen = append(seen, entry.Value)
+		}
+
+		if cursor == "" {
+			break
+		}
+	}
+
+	assert.Equal(t, m.Values(), seen)
+}
@@ -0,0 +1,52 @@
+package safeorderedmap
+
+import (
+	"unsafe"
+
+	"github.com/thalesfsp/go-common-types/shared"
+)
+
+//////
+// Methods.
+//////
+
+// EstimateBytes estimates the memory used by the map, in bytes: per entry, the
+// node of the insertion order list, the key, and its slot in the index, plus
+// the bytes referenced by the value, as given by the sizer, see WithSizer.
+// The overhead of the buckets of the built-in map isn't counted. The
+// estimate is recorded into the metrics, if enabled.
+func (m *SafeOrderedMap[T]) EstimateBytes() int64 {
+	m.rlock()
+	defer m.runlock()
+
+	var zero T
+
+	// Node, without the value, counted by EstimateSize, plus the key, and
+	// node pointer of the index.
+	overhead := int64(unsafe.Sizeof(element[T]{})-unsafe.Sizeof(zero)) +
+		int64(unsafe.Sizeof("")+unsafe.Sizeof(m.head))
+
+	size := int64(unsafe.Sizeof(*m))
+
+	for e := m.head; e != nil; e = e.next {
+		size += overhead + int64(len(e.key)) + shared.EstimateSize(e.value, m.sizer)
+	}
+
+	m.metrics.Operation("estimateBytes")
+	m.metrics.SetBytes(size)
+
+	return size
+}
+
+//////
+// Factory.
+//////
+
+// WithSizer sets the sizer of the values, used by EstimateBytes to count the
+// bytes they reference, e.g.: the elements of a slice. By default, only the
+// contents of strings, and byte slices are counted.
+func WithSizer[T any](sizer shared.Sizer[T]) Option[T] {
+	return func(m *SafeOrderedMap[T]) {
+		m.sizer = sizer
+	}
+}
@@ -0,0 +1,40 @@
+package safeorderedmap
+
+import (
+	"testing"
+	"unsafe"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/thalesfsp/go-common-types/metrics"
+)
+
+func TestEstimateBytes(t *testing.T) {
+	m := metrics.New("test")
+
+	om := New[string](WithMetrics[string](m))
+	empty := om.EstimateBytes()
+
+	assert.Equal(t, int64(unsafe.Sizeof(*om)), empty)
+
+	om.Add("a", "bc")
+
+	one := om.EstimateBytes()
+
+	// Node, key, and value, plus the key, and pointer of the index.
+	perEntry := int64(unsafe.Sizeof(element[string]{})) + 1 + 2 + 16 + 8
+
+	assert.Equal(t, empty+perEntry, one)
+	assert.Equal(t, one, m.Snapshot().Bytes)
+
+	// Updates don't add entries.
+	om.Add("a", "bcd")
+
+	assert.Equal(t, one+1, om.EstimateBytes())
+
+	sized := New[[]int](WithSizer(func(v []int) int { return 8 * cap(v) }))
+	empty = sized.EstimateBytes()
+
+	sized.Add("a", make([]int, 2, 10))
+
+	assert.Equal(t, empty+int64(unsafe.Sizeof(element[[]int]{}))+1+16+8+80, sized.EstimateBytes())
+}
@@ -0,0 +1,108 @@
+package safeorderedmap
+
+//////
+// Const, vars, and types.
+//////
+
+// snapshot is an immutable copy of the entries of a map, read without the
+// lock, see WithSnapshots.
+type snapshot[T any] struct {
+	keys   []string
+	values []T
+
+	// index maps the slots of the keys to their position.
+	index map[string]int
+}
+
+//////
+// Methods.
+//////
+
+// snapshotted checks if reads are served from snapshots.
+func (m *SafeOrderedMap[T]) snapshotted() bool {
+	return m.snapshots
+}
+
+// invalidate discards the snapshot, after a change. Caller must hold the
+// write lock.
+func (m *SafeOrderedMap[T]) invalidate() {
+	if m.snapshotted() {
+		m.current.Store(nil)
+	}
+}
+
+// view returns the snapshot of the map, building it if a change discarded it.
+// Only one reader builds it, the others wait for it.
+func (m *SafeOrderedMap[T]) view() *snapshot[T] {
+	if s := m.current.Load(); s != nil {
+		return s
+	}
+
+	m.rebuild.Lock()
+	defer m.rebuild.Unlock()
+
+	if s := m.current.Load(); s != nil {
+		return s
+	}
+
+	m.rlock()
+	defer m.runlock()
+
+	s := &snapshot[T]{
+		keys:   make([]string, 0, len(m.data)),
+		values: make([]T, 0, len(m.data)),
+		index:  make(map[string]int, len(m.data)),
+	}
+
+	for e := m.head; e != nil; e = e.next {
+		s.index[m.slot(e.key)] = len(s.keys)
+		s.keys = append(s.keys, e.key)
+		s.values = append(s.values, e.value)
+	}
+
+	// Stored holding the lock, so a change can't discard the snapshot before
+	// it's stored, leaving a stale one.
+	m.current.Store(s)
+
+	return s
+}
+
+// lookup returns the value of the key, from the snapshot.
+func (s *snapshot[T]) lookup(slot string) (T, bool) {
+	i, ok := s.index[slot]
+	if !ok {
+		return *new(T), false
+	}
+
+	return s.values[i], true
+}
+
+// entries returns the key-value pairs of the snapshot, in order.
+func (s *snapshot[T]) entries() []Entry[T] {
+	entries := make([]Entry[T], len(s.keys))
+
+	for i, key := range s.keys {
+		entries[i] = Entry[T]{Key: key, Value: s.values[i]}
+	}
+
+	return entries
+}
+
+//////
+// Factory.
+//////
+
+// WithSnapshots serves Get, Contains, Keys, Values, and Entries from an
+// immutable snapshot of the entries, swapped atomically, without acquiring the
+// lock, removing the contention between readers, e.g.: for configuration, or
+// routing tables, read far more often than changed. A change discards the
+// snapshot, and the next read rebuilds it, in O(n), so it slows down
+// write-heavy workloads, see BenchmarkSnapshots. Maps derived from the map,
+// e.g.: by Clone, or Filter, use snapshots too.
+//
+// NOTE: Reads from a snapshot don't record lock wait times, see WithMetrics.
+func WithSnapshots[T any]() Option[T] {
+	return func(m *SafeOrderedMap[T]) {
+		m.snapshots = true
+	}
+}
@@ -0,0 +1,168 @@
+package safeorderedmap
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithSnapshots(t *testing.T) {
+	m := New(WithSnapshots[int]())
+
+	m.Add("a", 1).Add("b", 2)
+
+	assert.Equal(t, []string{"a", "b"}, m.Keys())
+	assert.Equal(t, []int{1, 2}, m.Values())
+
+	// Reads are served from the same snapshot, until a change.
+	s := m.current.Load()
+	assert.NotNil(t, s)
+
+	v, ok := m.Get("a")
+	assert.True(t, ok)
+	assert.Equal(t, 1, v)
+	assert.Same(t, s, m.current.Load())
+
+	// Returned slices are copies.
+	keys := m.Keys()
+	keys[0] = "z"
+	assert.Equal(t, []string{"a", "b"}, m.Keys())
+
+	m.Add("a", 10)
+	v, _ = m.Get("a")
+	assert.Equal(t, 10, v)
+
+	m.Add("c", 3).Delete("b")
+	assert.Equal(t, []Entry[int]{{Key: "a", Value: 10}, {Key: "c", Value: 3}}, m.Entries())
+	assert.False(t, m.Contains("b"))
+
+	assert.NoError(t, m.RenameKey("c", "d"))
+	assert.Equal(t, []string{"a", "d"}, m.Keys())
+	assert.True(t, m.Contains("d"))
+
+	m.Clear()
+	assert.Empty(t, m.Keys())
+
+	_, ok = m.Get("a")
+	assert.False(t, ok)
+}
+
+func TestWithSnapshotsInherits(t *testing.T) {
+	m := New(WithSnapshots[int](), WithCaseInsensitiveKeys[int]())
+
+	m.Add("Content-Type", 1)
+
+	assert.True(t, m.Contains("content-type"))
+
+	ns := m.Namespace("user:")
+	ns.Add("1", 1)
+	assert.Equal(t, []string{"1"}, ns.Keys())
+	assert.Equal(t, []string{"Content-Type", "user:1"}, m.Keys())
+
+	// Changes through the map discard the snapshot of the namespace.
+	m.Delete("user:1")
+	assert.Empty(t, ns.Keys())
+
+	clone := m.Clone()
+	assert.True(t, clone.snapshotted())
+	assert.Equal(t, []string{"Content-Type"}, clone.Keys())
+}
+
+func TestWithSnapshotsConcurrent(t *testing.T) {
+	m := New(WithSnapshots[int]())
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < 4; i++ {
+		wg.Add(2)
+
+		go func(i int) {
+			defer wg.Done()
+
+			for j := 0; j < 1000; j++ {
+				m.Add(strconv.Itoa(i*1000+j), j)
+			}
+		}(i)
+
+		go func() {
+			defer wg.Done()
+
+			for j := 0; j < 1000; j++ {
+				keys, values := m.Keys(), m.Values()
+
+				// Keys, and values, of a snapshot are consistent.
+				assert.LessOrEqual(t, len(keys), len(m.Keys()))
+				assert.GreaterOrEqual(t, len(m.Values()), len(values))
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	// Once the writers are done, reads see every change.
+	assert.Len(t, m.Keys(), 4000)
+	assert.Equal(t, 4000, m.Size())
+}
+
+// BenchmarkSnapshots compares the RWMutex reads, to the snapshot reads, see
+// WithSnapshots, for ratios of reads, to writes, e.g.:
+//
+//	go test ./safeorderedmap -run '^$' -bench BenchmarkSnapshots -cpu 1,8
+func BenchmarkSnapshots(b *testing.B) {
+	modes := []struct {
+		name string
+		opts []Option[int]
+	}{
+		{name: "rwmutex"},
+		{name: "snapshot", opts: []Option[int]{WithSnapshots[int]()}},
+	}
+
+	// Writes per 1000 operations.
+	ratios := []int{0, 1, 10, 100, 500}
+
+	for _, n := range []int{100, 10000} {
+		for _, writes := range ratios {
+			for _, mode := range modes {
+				name := fmt.Sprintf("%d/reads=%.1f%%/%s", n, float64(1000-writes)/10, mode.name)
+
+				b.Run(strings.ReplaceAll(name, ".0%", "%"), func(b *testing.B) {
+					m := New(mode.opts...)
+
+					keys := make([]string, n)
+
+					for i := range keys {
+						keys[i] = strconv.Itoa(i)
+
+						m.Add(keys[i], i)
+					}
+
+					var worker int64
+
+					b.ReportAllocs()
+					b.ResetTimer()
+
+					b.RunParallel(func(pb *testing.PB) {
+						i := int(atomic.AddInt64(&worker, 1)) * 7919
+
+						for pb.Next() {
+							i++
+
+							key := keys[i%n]
+
+							if i%1000 < writes {
+								m.Add(key, i)
+							} else {
+								m.Get(key)
+							}
+						}
+					})
+				})
+			}
+		}
+	}
+}
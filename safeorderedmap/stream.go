@@ -0,0 +1,147 @@
+package safeorderedmap
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/thalesfsp/go-common-types/shared"
+)
+
+//////
+// Methods.
+//////
+
+// Encode writes the map to w as JSON, like MarshalJSON, marshalling one entry
+// at a time, e.g.: to stream a large map to an HTTP response without building
+// the whole JSON in memory. Keys keep their order. Entries are copied first,
+// so the lock isn't held while writing.
+func (m *SafeOrderedMap[T]) Encode(w io.Writer) error {
+	entries := m.Entries()
+
+	if m.entriesJSON {
+		return shared.EncodeJSONList(w, entries)
+	}
+
+	bw := bufio.NewWriter(w)
+
+	if err := bw.WriteByte('{'); err != nil {
+		return err
+	}
+
+	for i, entry := range entries {
+		key, err := json.Marshal(entry.Key)
+		if err != nil {
+			return err
+		}
+
+		value, err := json.Marshal(entry.Value)
+		if err != nil {
+			return err
+		}
+
+		if i > 0 {
+			key = append([]byte{','}, key...)
+		}
+
+		key = append(key, ':')
+
+		if _, err := bw.Write(key); err != nil {
+			return err
+		}
+
+		if _, err := bw.Write(value); err != nil {
+			return err
+		}
+	}
+
+	if err := bw.WriteByte('}'); err != nil {
+		return err
+	}
+
+	return bw.Flush()
+}
+
+// Decode reads the map from r as JSON, like UnmarshalJSON, decoding one entry
+// at a time. It accepts both an object, and an array of entries, and, unlike
+// UnmarshalJSON, keys of an object keep their order. It replaces the content
+// of the map.
+func (m *SafeOrderedMap[T]) Decode(r io.Reader) error {
+	dec := json.NewDecoder(r)
+
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+
+	keys := []string{}
+	values := []T{}
+
+	switch tok {
+	case nil:
+	case json.Delim('['):
+		for dec.More() {
+			var entry Entry[json.RawMessage]
+
+			if err := dec.Decode(&entry); err != nil {
+				return err
+			}
+
+			value, err := m.decodeValue(entry.Key, entry.Value)
+			if err != nil {
+				return err
+			}
+
+			keys = append(keys, entry.Key)
+			values = append(values, value)
+		}
+	case json.Delim('{'):
+		for dec.More() {
+			tok, err := dec.Token()
+			if err != nil {
+				return err
+			}
+
+			var data json.RawMessage
+
+			if err := dec.Decode(&data); err != nil {
+				return err
+			}
+
+			//nolint:forcetypeassert
+			key := tok.(string)
+
+			value, err := m.decodeValue(key, data)
+			if err != nil {
+				return err
+			}
+
+			keys = append(keys, key)
+			values = append(values, value)
+		}
+	default:
+		return fmt.Errorf("expected a JSON object, or array, got %v", tok)
+	}
+
+	// Consumes the closing delimiter.
+	if tok != nil {
+		if _, err := dec.Token(); err != nil {
+			return err
+		}
+	}
+
+	if err := m.validateAll(keys, values); err != nil {
+		return err
+	}
+
+	m.lock()
+	defer m.unlock()
+
+	m.load(keys, values)
+
+	m.metrics.Operation("unmarshal")
+	m.metrics.SetSize(len(m.data))
+
+	return nil
+}
@@ -0,0 +1,56 @@
+package safeorderedmap
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEncodeDecode(t *testing.T) {
+	m := New[int]()
+	m.Add("b", 2).Add("a", 1).Add("c", 3)
+
+	var buf bytes.Buffer
+
+	assert.NoError(t, m.Encode(&buf))
+	assert.Equal(t, `{"b":2,"a":1,"c":3}`, buf.String())
+
+	decoded := New[int]()
+	decoded.Add("z", 26)
+
+	assert.NoError(t, decoded.Decode(&buf))
+	assert.Equal(t, []string{"b", "a", "c"}, decoded.Keys())
+	assert.Equal(t, []int{2, 1, 3}, decoded.Values())
+
+	assert.NoError(t, decoded.Decode(strings.NewReader(`null`)))
+	assert.True(t, decoded.Empty())
+
+	assert.Error(t, decoded.Decode(strings.NewReader(`1`)))
+	assert.Error(t, decoded.Decode(strings.NewReader(`{"a":"x"}`)))
+	assert.Error(t, decoded.Decode(strings.NewReader(`{"a":1`)))
+}
+
+func TestEncodeDecodeEntries(t *testing.T) {
+	m := New(WithEntriesJSON[int]())
+	m.Add("b", 2).Add("a", 1)
+
+	var buf bytes.Buffer
+
+	assert.NoError(t, m.Encode(&buf))
+	assert.JSONEq(t, `[{"key":"b","value":2},{"key":"a","value":1}]`, buf.String())
+
+	decoded := New[int]()
+
+	assert.NoError(t, decoded.Decode(&buf))
+	assert.Equal(t, []string{"b", "a"}, decoded.Keys())
+}
+
+func TestDecodeValidates(t *testing.T) {
+	m := New(WithValidator(positive))
+	m.Add("a", 1)
+
+	assert.ErrorIs(t, m.Decode(strings.NewReader(`{"b":2,"c":-3}`)), errNegative)
+	assert.Equal(t, []string{"a"}, m.Keys())
+}
@@ -0,0 +1,156 @@
+package safeorderedmap
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// stressGoroutines is the number of goroutines of the stress tests, fewer with
+// -short.
+func stressGoroutines() int {
+	if testing.Short() {
+		return 32
+	}
+
+	return 256
+}
+
+// TestStress runs mixed operations from hundreds of goroutines. Run it with
+// -race. Atomic operations must neither lose, nor duplicate, updates.
+func TestStress(t *testing.T) {
+	const (
+		slots = 16
+		ops   = 100
+	)
+
+	n := stressGoroutines()
+	m := New[int]()
+
+	// Tokens are exchanged by Swap, starting from negative ones.
+	for k := 0; k < slots; k++ {
+		m.Set(fmt.Sprintf("slot-%d", k), -1-k)
+	}
+
+	var (
+		wg     sync.WaitGroup
+		claims int64
+		taken  = make([][]int, n)
+	)
+
+	for g := 0; g < n; g++ {
+		wg.Add(1)
+
+		go func(g int) {
+			defer wg.Done()
+
+			own := fmt.Sprintf("own-%d", g)
+
+			for i := 0; i < ops; i++ {
+				if old, ok := m.Swap(fmt.Sprintf("slot-%d", (g+i)%slots), g*ops+i); ok {
+					taken[g] = append(taken[g], old)
+				}
+
+				if m.SetIfAbsent(fmt.Sprintf("claim-%d", i), g) {
+					atomic.AddInt64(&claims, 1)
+				}
+
+				m.Set(own, i)
+
+				if !m.Remove(own) {
+					t.Errorf("%s: not found after Set", own)
+				}
+
+				switch i % 5 {
+				case 0:
+					_ = m.Keys()
+				case 1:
+					_, _ = m.MarshalJSON()
+				case 2:
+					_ = m.Clone().Size()
+				case 3:
+					m.Each(func(string, int) {})
+				default:
+					_ = m.Empty()
+				}
+			}
+		}(g)
+	}
+
+	wg.Wait()
+
+	// Every token, initial, and swapped in, is either taken, or in a slot,
+	// exactly once.
+	seen := map[int]int{}
+
+	for _, tokens := range taken {
+		for _, token := range tokens {
+			seen[token]++
+		}
+	}
+
+	for k := 0; k < slots; k++ {
+		token, _ := m.Get(fmt.Sprintf("slot-%d", k))
+
+		seen[token]++
+	}
+
+	assert.Len(t, seen, n*ops+slots)
+
+	for token, count := range seen {
+		if count != 1 {
+			t.Errorf("token %d seen %d times", token, count)
+		}
+	}
+
+	// Each claim is won by exactly one goroutine.
+	assert.Equal(t, int64(ops), claims)
+
+	assert.Equal(t, slots+ops, m.Size())
+	assert.Len(t, m.Keys(), m.Size())
+}
+
+// TestStressSubset checks maps against others mutated concurrently, and
+// against themselves, while they're reordered. Run it with -race.
+func TestStressSubset(t *testing.T) {
+	n := stressGoroutines()
+
+	m, other := New[int](), New[int]()
+
+	for k := 0; k < 10; k++ {
+		m.Set(fmt.Sprintf("k-%d", k), k)
+		other.Set(fmt.Sprintf("k-%d", k), k)
+	}
+
+	var wg sync.WaitGroup
+
+	for g := 0; g < n; g++ {
+		wg.Add(1)
+
+		go func(g int) {
+			defer wg.Done()
+
+			for i := 0; i < 50; i++ {
+				switch g % 3 {
+				case 0:
+					key := fmt.Sprintf("extra-%d-%d", g, i)
+
+					other.Set(key, i)
+					other.Delete(key)
+					m.SortFunc(func(a, b Entry[int]) bool { return a.Key < b.Key })
+				case 1:
+					assert.True(t, m.Subset(other))
+					assert.True(t, other.Superset(m))
+				default:
+					assert.True(t, m.Subset(m))
+					assert.False(t, m.IsDisjoint(other))
+				}
+			}
+		}(g)
+	}
+
+	wg.Wait()
+}
@@ -0,0 +1,230 @@
+package safeorderedmap
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+//////
+// Const, vars, and types.
+//////
+
+// ErrNotStruct is returned when a value isn't a struct, or a pointer to one.
+var ErrNotStruct = errors.New("not a struct")
+
+// field is an exported field of a struct, as seen by encoding/json.
+type field struct {
+	name      string
+	index     []int
+	omitEmpty bool
+}
+
+//////
+// Helpers.
+//////
+
+// fields returns the fields of the struct type, in declaration order, named
+// after their json tag, if any. Fields tagged "-", and unexported ones, are
+// skipped. Fields of untagged embedded structs are promoted, in place.
+func fields(t reflect.Type) []field {
+	result := []field{}
+
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+
+		name, opts, _ := strings.Cut(tag, ",")
+
+		ft := f.Type
+		if ft.Kind() == reflect.Pointer {
+			ft = ft.Elem()
+		}
+
+		if f.Anonymous && name == "" && ft.Kind() == reflect.Struct {
+			for _, promoted := range fields(ft) {
+				promoted.index = append([]int{i}, promoted.index...)
+
+				result = append(result, promoted)
+			}
+
+			continue
+		}
+
+		if !f.IsExported() {
+			continue
+		}
+
+		if name == "" {
+			name = f.Name
+		}
+
+		result = append(result, field{
+			name:      name,
+			index:     []int{i},
+			omitEmpty: strings.Contains(","+opts+",", ",omitempty,"),
+		})
+	}
+
+	return result
+}
+
+// structValue returns the struct v points to, or is, or ErrNotStruct.
+func structValue(v any) (reflect.Value, error) {
+	rv := reflect.ValueOf(v)
+
+	for rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			return reflect.Value{}, fmt.Errorf("%w: nil %T", ErrNotStruct, v)
+		}
+
+		rv = rv.Elem()
+	}
+
+	if rv.Kind() != reflect.Struct {
+		return reflect.Value{}, fmt.Errorf("%w: %T", ErrNotStruct, v)
+	}
+
+	return rv, nil
+}
+
+// fieldOf returns the field at the index, and false if it's promoted from a
+// nil embedded pointer. If alloc is set, nil embedded pointers are allocated
+// instead, unless unexported.
+func fieldOf(v reflect.Value, index []int, alloc bool) (reflect.Value, bool) {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Pointer {
+			if v.IsNil() {
+				if !alloc || !v.CanSet() {
+					return reflect.Value{}, false
+				}
+
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+
+			v = v.Elem()
+		}
+
+		v = v.Field(x)
+	}
+
+	return v, true
+}
+
+// isEmpty checks if the value is empty, as defined by the omitempty option of
+// encoding/json.
+func isEmpty(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64,
+		reflect.Interface, reflect.Pointer:
+		return v.IsZero()
+	default:
+		return false
+	}
+}
+
+// assign sets the field to the value, directly if assignable, converting
+// numbers, e.g.: float64 as decoded from JSON, to int, or through JSON
+// otherwise, e.g.: a map[string]any to a struct.
+func assign(f reflect.Value, value any) error {
+	if value == nil {
+		f.Set(reflect.Zero(f.Type()))
+
+		return nil
+	}
+
+	rv := reflect.ValueOf(value)
+
+	if rv.Type().AssignableTo(f.Type()) {
+		f.Set(rv)
+
+		return nil
+	}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(data, f.Addr().Interface())
+}
+
+//////
+// Exported functionalities.
+//////
+
+// ToStruct sets the fields of the struct target points to from the values of
+// the map, matching keys as FromStruct names them. Values are assigned
+// directly if their type matches, and converted through JSON otherwise, e.g.:
+// float64 to int, or map[string]any to a struct. Keys without a field, and
+// fields without a key, are left alone. It returns ErrNotStruct if target
+// isn't a non-nil pointer to a struct.
+func ToStruct(m *SafeOrderedMap[any], target any) error {
+	rv := reflect.ValueOf(target)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("%w: %T, expected a pointer to a struct", ErrNotStruct, target)
+	}
+
+	rv = rv.Elem()
+
+	for _, f := range fields(rv.Type()) {
+		value, ok := m.Peek(f.name)
+		if !ok {
+			continue
+		}
+
+		fv, ok := fieldOf(rv, f.index, true)
+		if !ok {
+			continue
+		}
+
+		if err := assign(fv, value); err != nil {
+			return fmt.Errorf("field %q: %w", f.name, err)
+		}
+	}
+
+	return nil
+}
+
+//////
+// Factory.
+//////
+
+// FromStruct creates a new SafeOrderedMap with the exported fields of the
+// struct v is, or points to, in declaration order, e.g.: to build ordered API
+// payloads. Keys are named, and fields skipped, as by encoding/json: after
+// the json tag, if any, honoring "-", and omitempty, and promoting the fields
+// of untagged embedded structs. Values are kept as is, without conversion.
+// It returns ErrNotStruct if v isn't a struct.
+func FromStruct(v any, opts ...Option[any]) (*SafeOrderedMap[any], error) {
+	rv, err := structValue(v)
+	if err != nil {
+		return nil, err
+	}
+
+	m := New[any](opts...)
+
+	for _, f := range fields(rv.Type()) {
+		fv, ok := fieldOf(rv, f.index, false)
+		if !ok || (f.omitEmpty && isEmpty(fv)) {
+			continue
+		}
+
+		if err := m.AddE(f.name, fv.Interface()); err != nil {
+			return nil, err
+		}
+	}
+
+	return m, nil
+}
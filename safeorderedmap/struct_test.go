@@ -0,0 +1,122 @@
+package safeorderedmap
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type structBase struct {
+	ID int64 `json:"id"`
+}
+
+// StructAudit is exported, so ToStruct can allocate it, when embedded.
+type StructAudit struct {
+	By string `json:"by"`
+}
+
+type structAddress struct {
+	City string `json:"city"`
+}
+
+type structPayload struct {
+	structBase
+	*StructAudit
+
+	Name     string         `json:"name"`
+	Tags     []string       `json:"tags,omitempty"`
+	Note     string         `json:"note,omitempty"`
+	Secret   string         `json:"-"`
+	Address  structAddress  `json:"address"`
+	Extra    map[string]any `json:"extra,omitempty"`
+	Untagged bool
+	internal int
+}
+
+func TestFromStruct(t *testing.T) {
+	payload := structPayload{
+		structBase: structBase{ID: 9007199254740993},
+		Name:       "a",
+		Tags:       []string{"x"},
+		Secret:     "s",
+		Address:    structAddress{City: "c"},
+		Untagged:   true,
+		internal:   1,
+	}
+
+	m, err := FromStruct(&payload)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"id", "name", "tags", "address", "Untagged"}, m.Keys())
+	assert.Equal(t, []any{int64(9007199254740993), "a", []string{"x"}, structAddress{City: "c"}, true}, m.Values())
+
+	// Same keys as encoding/json.
+	buf, err := json.Marshal(payload)
+
+	assert.NoError(t, err)
+
+	fromJSON := New[any]()
+
+	assert.NoError(t, json.Unmarshal(buf, fromJSON))
+	assert.ElementsMatch(t, fromJSON.Keys(), m.Keys())
+
+	// Embedded pointers are promoted, if set.
+	payload.StructAudit = &StructAudit{By: "b"}
+
+	m, err = FromStruct(payload)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"id", "by", "name", "tags", "address", "Untagged"}, m.Keys())
+
+	_, err = FromStruct(1)
+
+	assert.ErrorIs(t, err, ErrNotStruct)
+
+	_, err = FromStruct((*structPayload)(nil))
+
+	assert.ErrorIs(t, err, ErrNotStruct)
+}
+
+func TestToStruct(t *testing.T) {
+	m := New[any]()
+
+	assert.NoError(t, json.Unmarshal([]byte(`{
+		"id": 42,
+		"by": "b",
+		"name": "a",
+		"tags": ["x", "y"],
+		"Secret": "s",
+		"address": {"city": "c"},
+		"Untagged": true,
+		"unknown": 1
+	}`), m))
+
+	var payload structPayload
+
+	assert.NoError(t, ToStruct(m, &payload))
+	assert.Equal(t, int64(42), payload.ID)
+	assert.Equal(t, &StructAudit{By: "b"}, payload.StructAudit)
+	assert.Equal(t, "a", payload.Name)
+	assert.Equal(t, []string{"x", "y"}, payload.Tags)
+	assert.Empty(t, payload.Secret)
+	assert.Equal(t, structAddress{City: "c"}, payload.Address)
+	assert.True(t, payload.Untagged)
+
+	// Round-trip, values of the right type are assigned as is.
+	from, err := FromStruct(payload)
+
+	assert.NoError(t, err)
+
+	var back structPayload
+
+	assert.NoError(t, ToStruct(from, &back))
+	assert.Equal(t, payload, back)
+
+	// Mismatched types are reported with the field.
+	m.Set("name", 1)
+
+	assert.ErrorContains(t, ToStruct(m, &payload), `"name"`)
+	assert.ErrorIs(t, ToStruct(m, payload), ErrNotStruct)
+	assert.ErrorIs(t, ToStruct(m, (*structPayload)(nil)), ErrNotStruct)
+}
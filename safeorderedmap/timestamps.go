@@ -0,0 +1,117 @@
+package safeorderedmap
+
+import (
+	"sort"
+	"time"
+)
+
+//////
+// Methods.
+//////
+
+// stamp returns the time of an addition, zero if the map doesn't record it.
+func (m *SafeOrderedMap[T]) stamp() time.Time {
+	if now := m.now; now != nil {
+		return now()
+	}
+
+	return time.Time{}
+}
+
+// AddedAt returns the time the key was added, false if it isn't present, or
+// the map doesn't record it, see WithTimestamps. Updates don't change it.
+func (m *SafeOrderedMap[T]) AddedAt(key string) (time.Time, bool) {
+	m.rlock()
+	defer m.runlock()
+
+	e, ok := m.data[m.slot(key)]
+	if !ok || e.added.IsZero() {
+		return time.Time{}, false
+	}
+
+	return e.added, true
+}
+
+// AddedSince returns the entries added at, or after, the time, in order,
+// e.g.: AddedSince(time.Now().Add(-5 * time.Minute)) for the last five
+// minutes. It returns no entries if the map doesn't record the times of the
+// additions, see WithTimestamps.
+func (m *SafeOrderedMap[T]) AddedSince(t time.Time) []Entry[T] {
+	m.rlock()
+	defer m.runlock()
+
+	m.metrics.Operation("addedSince")
+
+	entries := []Entry[T]{}
+
+	for e := m.head; e != nil; e = e.next {
+		if !e.added.IsZero() && !e.added.Before(t) {
+			entries = append(entries, Entry[T]{Key: e.key, Value: e.value})
+		}
+	}
+
+	return entries
+}
+
+// OldestN returns the n entries added first, from the oldest. Without
+// timestamps, see WithTimestamps, it's the first n entries, in order.
+func (m *SafeOrderedMap[T]) OldestN(n int) []Entry[T] {
+	return m.byAddition(n, false)
+}
+
+// NewestN returns the n entries added last, from the newest. Without
+// timestamps, see WithTimestamps, it's the last n entries, in reverse order.
+func (m *SafeOrderedMap[T]) NewestN(n int) []Entry[T] {
+	return m.byAddition(n, true)
+}
+
+// byAddition returns up to n entries, by time of addition, then position,
+// from the oldest, or the newest.
+func (m *SafeOrderedMap[T]) byAddition(n int, newest bool) []Entry[T] {
+	m.rlock()
+	defer m.runlock()
+
+	elements := m.elements()
+
+	// The list is in the order of addition, unless reordered by SortFunc.
+	sort.SliceStable(elements, func(i, j int) bool {
+		return elements[i].added.Before(elements[j].added)
+	})
+
+	if newest {
+		for i, j := 0, len(elements)-1; i < j; i, j = i+1, j-1 {
+			elements[i], elements[j] = elements[j], elements[i]
+		}
+	}
+
+	if n < 0 {
+		n = 0
+	}
+
+	if n > len(elements) {
+		n = len(elements)
+	}
+
+	entries := make([]Entry[T], n)
+
+	for i, e := range elements[:n] {
+		entries[i] = Entry[T]{Key: e.key, Value: e.value}
+	}
+
+	return entries
+}
+
+//////
+// Factory.
+//////
+
+// WithTimestamps records the time each key is added, so entries can be
+// queried by it, see AddedAt, AddedSince, OldestN, and NewestN, e.g.: for
+// session registries. Updates don't change it, and re-adding a deleted key
+// does. Maps derived from the map, e.g.: by Clone, or Filter, record them
+// too.
+func WithTimestamps[T any]() Option[T] {
+	return func(m *SafeOrderedMap[T]) {
+		m.now = time.Now
+	}
+}
@@ -0,0 +1,116 @@
+package safeorderedmap
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// withClock sets a clock advancing by one second on every call.
+func withClock[T any](m *SafeOrderedMap[T], start time.Time) *SafeOrderedMap[T] {
+	now := start
+
+	m.now = func() time.Time {
+		now = now.Add(time.Second)
+
+		return now
+	}
+
+	return m
+}
+
+func TestAddedSince(t *testing.T) {
+	start := time.Unix(1000, 0)
+
+	m := withClock(New(WithTimestamps[int]()), start)
+
+	m.Add("a", 1).Add("b", 2).Add("c", 3)
+
+	// Updates keep the time.
+	m.Add("a", 10)
+
+	at, ok := m.AddedAt("a")
+	assert.True(t, ok)
+	assert.Equal(t, start.Add(time.Second), at)
+
+	_, ok = m.AddedAt("z")
+	assert.False(t, ok)
+
+	assert.Equal(t, []Entry[int]{{Key: "b", Value: 2}, {Key: "c", Value: 3}}, m.AddedSince(start.Add(2*time.Second)))
+	assert.Empty(t, m.AddedSince(start.Add(time.Hour)))
+
+	// Re-adding a deleted key changes it.
+	m.Delete("a").Add("a", 1)
+
+	assert.Equal(t, []string{"b", "c", "a"}, entryKeys(m.AddedSince(start.Add(2*time.Second))))
+}
+
+func TestOldestNewestN(t *testing.T) {
+	m := withClock(New(WithTimestamps[int]()), time.Unix(1000, 0))
+
+	m.Add("a", 1).Add("b", 2).Add("c", 3)
+
+	assert.Equal(t, []string{"a", "b"}, entryKeys(m.OldestN(2)))
+	assert.Equal(t, []string{"c", "b"}, entryKeys(m.NewestN(2)))
+	assert.Len(t, m.OldestN(10), 3)
+	assert.Empty(t, m.NewestN(-1))
+
+	// Sorting keeps the times.
+	m.SortFunc(func(a, b Entry[int]) bool { return a.Value > b.Value })
+
+	assert.Equal(t, []string{"c", "b", "a"}, m.Keys())
+	assert.Equal(t, []string{"a", "b"}, entryKeys(m.OldestN(2)))
+	assert.Equal(t, []string{"c"}, entryKeys(m.NewestN(1)))
+}
+
+func TestTimestampsDisabled(t *testing.T) {
+	m := New[int]().Add("a", 1).Add("b", 2)
+
+	_, ok := m.AddedAt("a")
+	assert.False(t, ok)
+	assert.Empty(t, m.AddedSince(time.Time{}))
+	assert.Equal(t, []string{"a"}, entryKeys(m.OldestN(1)))
+	assert.Equal(t, []string{"b", "a"}, entryKeys(m.NewestN(2)))
+}
+
+func TestTimestampsCloneNamespace(t *testing.T) {
+	start := time.Unix(1000, 0)
+
+	m := withClock(New(WithTimestamps[int]()), start)
+
+	m.Add("t1/a", 1)
+
+	ns := m.Namespace("t1/")
+
+	m.Add("t1/b", 2)
+
+	at, ok := ns.AddedAt("a")
+	assert.True(t, ok)
+	assert.Equal(t, start.Add(time.Second), at)
+
+	nsAt, _ := ns.AddedAt("b")
+	mAt, _ := m.AddedAt("t1/b")
+	assert.Equal(t, mAt, nsAt)
+
+	clone := m.Clone()
+
+	cloneAt, ok := clone.AddedAt("t1/a")
+	assert.True(t, ok)
+	assert.Equal(t, at, cloneAt)
+
+	ro, ok := m.ReadOnly().AddedAt("t1/b")
+	assert.True(t, ok)
+	assert.Equal(t, mAt, ro)
+}
+
+// entryKeys returns the keys of the entries.
+func entryKeys[T any](entries []Entry[T]) []string {
+	keys := make([]string, len(entries))
+
+	for i, e := range entries {
+		keys[i] = e.Key
+	}
+
+	return keys
+}
@@ -0,0 +1,72 @@
+package safeorderedmap
+
+import "fmt"
+
+//////
+// Const, vars, and types.
+//////
+
+// Validator checks an entry before it's stored in the map, returning an error
+// if it's invalid.
+type Validator[T any] func(key string, value T) error
+
+//////
+// Methods.
+//////
+
+// validate runs the validators against the entry, stopping at the first
+// error, which is wrapped with the key.
+func (m *SafeOrderedMap[T]) validate(key string, value T) error {
+	for _, validator := range m.validators {
+		if err := validator(key, value); err != nil {
+			return fmt.Errorf("invalid %q: %w", key, err)
+		}
+	}
+
+	return nil
+}
+
+// validateAll validates all the entries, stopping at the first error.
+func (m *SafeOrderedMap[T]) validateAll(keys []string, values []T) error {
+	if len(m.validators) == 0 {
+		return nil
+	}
+
+	for i, key := range keys {
+		if err := m.validate(key, values[i]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// AddE is like Add, returning the error of the first validator rejecting the
+// entry, or of the writer, in which case the map is left untouched.
+func (m *SafeOrderedMap[T]) AddE(key string, value T) error {
+	if err := m.validate(key, value); err != nil {
+		return err
+	}
+
+	return m.writeThrough(key, value, nil, func() {
+		m.set(key, value)
+
+		m.metrics.Operation("add")
+		m.metrics.SetSize(len(m.data))
+	})
+}
+
+//////
+// Factory.
+//////
+
+// WithValidator adds a validator, checking every entry before it's stored.
+// AddE, ReplaceKey, and the unmarshallers return the error. Add, Set,
+// SetIfAbsent, Replace, and Swap drop invalid entries, recording the error,
+// see DropErr. Validators run in the order they were added, without holding
+// the lock.
+func WithValidator[T any](validator Validator[T]) Option[T] {
+	return func(m *SafeOrderedMap[T]) {
+		m.validators = append(m.validators, validator)
+	}
+}
@@ -0,0 +1,68 @@
+package safeorderedmap
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var errNegative = errors.New("negative")
+
+func positive(_ string, value int) error {
+	if value < 0 {
+		return errNegative
+	}
+
+	return nil
+}
+
+func lowercase(key string, _ int) error {
+	if strings.ToLower(key) != key {
+		return errors.New("key must be lowercase")
+	}
+
+	return nil
+}
+
+func TestWithValidator(t *testing.T) {
+	m := New(WithValidator(positive), WithValidator(lowercase))
+
+	assert.NoError(t, m.AddE("a", 1))
+
+	err := m.AddE("b", -1)
+	assert.ErrorIs(t, err, errNegative)
+	assert.Contains(t, err.Error(), `"b"`)
+
+	assert.Error(t, m.AddE("C", 1))
+	assert.NoError(t, m.DropErr())
+
+	m.Add("d", -1).Add("e", 2)
+	assert.ErrorIs(t, m.DropErr(), errNegative)
+
+	m.Set("f", -1)
+
+	assert.False(t, m.SetIfAbsent("g", -1))
+	assert.True(t, m.SetIfAbsent("g", 3))
+
+	assert.ErrorIs(t, m.ReplaceKey("a", "h", -1), errNegative)
+	assert.NoError(t, m.ReplaceKey("a", "h", 4))
+
+	assert.Equal(t, []string{"h", "e", "g"}, m.Keys())
+	assert.Equal(t, []int{4, 2, 3}, m.Values())
+}
+
+func TestWithValidatorUnmarshal(t *testing.T) {
+	m := New(WithValidator(positive))
+	m.Add("a", 1)
+
+	assert.ErrorIs(t, m.UnmarshalJSON([]byte(`{"b":2,"c":-3}`)), errNegative)
+	assert.ErrorIs(t, m.UnmarshalJSON([]byte(`[{"key":"b","value":-2}]`)), errNegative)
+	assert.ErrorIs(t, m.UnmarshalText([]byte(`b=-2`)), errNegative)
+
+	assert.Equal(t, []string{"a"}, m.Keys())
+
+	assert.NoError(t, m.UnmarshalJSON([]byte(`{"b":2}`)))
+	assert.Equal(t, []string{"b"}, m.Keys())
+}
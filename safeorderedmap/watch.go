@@ -0,0 +1,77 @@
+package safeorderedmap
+
+import (
+	"context"
+
+	"github.com/thalesfsp/go-common-types/shared"
+)
+
+//////
+// Const, vars, and types.
+//////
+
+// Event is a change of the map.
+type Event[T any] struct {
+	// Op is the type of the change.
+	Op shared.Op `json:"op"`
+
+	// Key is the changed key, empty for clear.
+	Key string `json:"key,omitempty"`
+
+	// Old is the previous value, for update, and delete.
+	Old T `json:"old"`
+
+	// New is the new value, for add, and update.
+	New T `json:"new"`
+}
+
+//////
+// Methods.
+//////
+
+// Watch returns a channel receiving, in order, every change of the map made
+// after the call, until the context is done, then the channel is closed.
+// Replacing the whole map, e.g.: UnmarshalJSON, is a clear followed by adds.
+//
+// Mutations never block on watchers: events are queued until received, so a
+// watcher which stops receiving should cancel its context.
+func (m *SafeOrderedMap[T]) Watch(ctx context.Context) <-chan Event[T] {
+	return m.watchers.Subscribe(ctx)
+}
+
+// WaitFor blocks until the map has an entry satisfying the predicate,
+// returning it, or until the context is done, returning its error. Entries
+// already in the map are checked first, then every added, or updated one,
+// without polling.
+func (m *SafeOrderedMap[T]) WaitFor(ctx context.Context, predicate func(key string, value T) bool) (string, T, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// Subscribing first, so entries added while checking aren't missed.
+	events := m.Watch(ctx)
+
+	if key, value, ok := m.Find(predicate); ok {
+		return key, value, nil
+	}
+
+	for e := range events {
+		if (e.Op == shared.OpAdd || e.Op == shared.OpUpdate) && predicate(e.Key, e.New) {
+			return e.Key, e.New, nil
+		}
+	}
+
+	return "", *new(T), ctx.Err()
+}
+
+// WaitForKey blocks until the key is in the map, returning its value, or until
+// the context is done, returning its error. Keys are matched after folding,
+// see WithKeyFolding.
+func (m *SafeOrderedMap[T]) WaitForKey(ctx context.Context, key string) (T, error) {
+	slot := m.slot(key)
+
+	_, value, err := m.WaitFor(ctx, func(k string, _ T) bool {
+		return m.slot(k) == slot
+	})
+
+	return value, err
+}
@@ -0,0 +1,92 @@
+package safeorderedmap
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/thalesfsp/go-common-types/shared"
+)
+
+func TestSafeOrderedMapWatch(t *testing.T) {
+	m := New[int]()
+	m.Add("a", 1)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	events := m.Watch(ctx)
+
+	m.Add("b", 2).Add("a", 10).Delete("b").Delete("missing").Clear()
+
+	assert.NoError(t, m.UnmarshalJSON([]byte(`{"c":3}`)))
+
+	expected := []Event[int]{
+		{Op: shared.OpAdd, Key: "b", New: 2},
+		{Op: shared.OpUpdate, Key: "a", Old: 1, New: 10},
+		{Op: shared.OpDelete, Key: "b", Old: 2},
+		{Op: shared.OpClear},
+		{Op: shared.OpClear},
+		{Op: shared.OpAdd, Key: "c", New: 3},
+	}
+
+	for _, e := range expected {
+		assert.Equal(t, e, <-events)
+	}
+
+	cancel()
+
+	for range events {
+	}
+}
+
+func TestSafeOrderedMapWaitFor(t *testing.T) {
+	m := New[int]()
+	m.Add("a", 1)
+
+	key, value, err := m.WaitFor(context.Background(), func(_ string, v int) bool { return v == 1 })
+	assert.NoError(t, err)
+	assert.Equal(t, "a", key)
+	assert.Equal(t, 1, value)
+
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+
+		m.Add("b", 2).Add("ready", 3)
+	}()
+
+	value, err = m.WaitForKey(context.Background(), "ready")
+	assert.NoError(t, err)
+	assert.Equal(t, 3, value)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	_, err = m.WaitForKey(ctx, "never")
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestSafeOrderedMapWaitForKeyFolding(t *testing.T) {
+	m := New(WithCaseInsensitiveKeys[int]())
+	m.Add("Content-Type", 1)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	// Entries already in the map.
+	value, err := m.WaitForKey(ctx, "content-type")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, value)
+
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+
+		m.Add("X-Ready", 2)
+	}()
+
+	// Entries added later.
+	value, err = m.WaitForKey(ctx, "x-ready")
+	assert.NoError(t, err)
+	assert.Equal(t, 2, value)
+}
@@ -0,0 +1,92 @@
+// Package safepool provides generic worker pools, running a function over
+// items concurrently, with a bounded number of workers, error aggregation, and
+// cancellation.
+package safepool
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"sync"
+	"sync/atomic"
+
+	"github.com/thalesfsp/go-common-types/errs"
+)
+
+//////
+// Exported Functionalities.
+//////
+
+// run calls f with every index in [0, n), using the given number of workers.
+// Errors are collected in an errs.Errors, in index order. Once the context is
+// done, no more indexes are scheduled, and the context error is included.
+func run(ctx context.Context, n, workers int, f func(i int) error) error {
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+
+	if workers > n {
+		workers = n
+	}
+
+	failures := make([]error, n)
+
+	var (
+		next int64
+		wg   sync.WaitGroup
+	)
+
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for {
+				i := int(atomic.AddInt64(&next, 1) - 1)
+				if i >= n || ctx.Err() != nil {
+					return
+				}
+
+				if err := f(i); err != nil {
+					failures[i] = fmt.Errorf("item %d: %w", i, err)
+				}
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	return errs.Join(append(failures, ctx.Err())...)
+}
+
+// Map calls f with every item, concurrently, using the given number of
+// workers, or GOMAXPROCS if not positive, returning the results in the order
+// of the items.
+//
+// All items are processed, even if some fail: errors are collected in an
+// errs.Errors, in the order of the items. Once the context is done, remaining
+// items are skipped, their results are the zero value, and the context error
+// is included.
+func Map[T, U any](ctx context.Context, items []T, workers int, f func(T) (U, error)) ([]U, error) {
+	results := make([]U, len(items))
+
+	err := run(ctx, len(items), workers, func(i int) error {
+		result, err := f(items[i])
+
+		results[i] = result
+
+		return err
+	})
+
+	return results, err
+}
+
+// ForEach calls f with every item, concurrently, using the given number of
+// workers, or GOMAXPROCS if not positive. Errors, and cancellation, are
+// handled like Map.
+func ForEach[T any](ctx context.Context, items []T, workers int, f func(T) error) error {
+	return run(ctx, len(items), workers, func(i int) error {
+		return f(items[i])
+	})
+}
@@ -0,0 +1,97 @@
+package safepool
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/thalesfsp/go-common-types/errs"
+)
+
+func TestMap(t *testing.T) {
+	items := []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}
+
+	results, err := Map(context.Background(), items, 3, func(i int) (string, error) {
+		return strconv.Itoa(i * 2), nil
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"2", "4", "6", "8", "10", "12", "14", "16", "18", "20"}, results)
+
+	results, err = Map(context.Background(), []int{}, 0, func(i int) (string, error) {
+		return "", nil
+	})
+
+	assert.NoError(t, err)
+	assert.Empty(t, results)
+}
+
+func TestMapErrors(t *testing.T) {
+	errOdd := errors.New("odd")
+
+	results, err := Map(context.Background(), []int{1, 2, 3, 4}, 0, func(i int) (int, error) {
+		if i%2 == 1 {
+			return 0, errOdd
+		}
+
+		return i, nil
+	})
+
+	assert.ErrorIs(t, err, errOdd)
+	assert.EqualError(t, err, "item 0: odd\nitem 2: odd")
+
+	var aggregate *errs.Errors
+
+	assert.ErrorAs(t, err, &aggregate)
+	assert.Equal(t, 2, aggregate.Len())
+	assert.Equal(t, []int{0, 2, 0, 4}, results)
+}
+
+func TestForEachBoundedWorkers(t *testing.T) {
+	var running, peak, calls int64
+
+	items := make([]int, 50)
+
+	err := ForEach(context.Background(), items, 4, func(int) error {
+		n := atomic.AddInt64(&running, 1)
+
+		for {
+			p := atomic.LoadInt64(&peak)
+			if n <= p || atomic.CompareAndSwapInt64(&peak, p, n) {
+				break
+			}
+		}
+
+		time.Sleep(time.Millisecond)
+
+		atomic.AddInt64(&running, -1)
+		atomic.AddInt64(&calls, 1)
+
+		return nil
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(50), calls)
+	assert.LessOrEqual(t, peak, int64(4))
+}
+
+func TestForEachCancellation(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	var calls int64
+
+	err := ForEach(ctx, make([]int, 100), 1, func(int) error {
+		if atomic.AddInt64(&calls, 1) == 10 {
+			cancel()
+		}
+
+		return nil
+	})
+
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, int64(10), calls)
+}
@@ -0,0 +1,110 @@
+package safeset
+
+//////
+// Const, vars, and types.
+//////
+
+// ReadOnly is a read-only view of a SafeSet, see SafeSet.ReadOnly.
+type ReadOnly[T any] interface {
+	// Contains checks if the element is present.
+	Contains(value T) bool
+
+	// Get returns the element at the given index, in insertion order.
+	Get(index int) (T, bool)
+
+	// First returns the first element, in insertion order.
+	First() (T, bool)
+
+	// Last returns the last element, in insertion order.
+	Last() (T, bool)
+
+	// Values returns the elements, in insertion order.
+	Values() []T
+
+	// Size returns the number of elements.
+	Size() int
+
+	// Empty checks if there are no elements.
+	Empty() bool
+
+	// Each calls f for each element, in insertion order.
+	Each(f func(value T))
+
+	// Find returns the first element satisfying the predicate.
+	Find(predicate func(value T) bool) (T, bool)
+
+	// Any checks if at least one element satisfies the predicate.
+	Any(predicate func(value T) bool) bool
+
+	// All checks if all elements satisfy the predicate.
+	All(predicate func(value T) bool) bool
+
+	// Clone returns a copy of the set, which can be modified.
+	Clone() *SafeSet[T]
+
+	// String returns the string representation of the set.
+	String() string
+
+	// MarshalJSON marshals the set to JSON.
+	MarshalJSON() ([]byte, error)
+}
+
+// readOnly implements ReadOnly. The set isn't exported, so the view can't be
+// converted back.
+type readOnly[T any] struct {
+	s *SafeSet[T]
+}
+
+//////
+// Methods.
+//////
+
+// Contains implements ReadOnly.
+func (v readOnly[T]) Contains(value T) bool { return v.s.Contains(value) }
+
+// Get implements ReadOnly.
+func (v readOnly[T]) Get(index int) (T, bool) { return v.s.Get(index) }
+
+// First implements ReadOnly.
+func (v readOnly[T]) First() (T, bool) { return v.s.First() }
+
+// Last implements ReadOnly.
+func (v readOnly[T]) Last() (T, bool) { return v.s.Last() }
+
+// Values implements ReadOnly.
+func (v readOnly[T]) Values() []T { return v.s.Values() }
+
+// Size implements ReadOnly.
+func (v readOnly[T]) Size() int { return v.s.Size() }
+
+// Empty implements ReadOnly.
+func (v readOnly[T]) Empty() bool { return v.s.Empty() }
+
+// Each implements ReadOnly.
+func (v readOnly[T]) Each(f func(value T)) { v.s.Each(f) }
+
+// Find implements ReadOnly.
+func (v readOnly[T]) Find(predicate func(value T) bool) (T, bool) { return v.s.Find(predicate) }
+
+// Any implements ReadOnly.
+func (v readOnly[T]) Any(predicate func(value T) bool) bool { return v.s.Any(predicate) }
+
+// All implements ReadOnly.
+func (v readOnly[T]) All(predicate func(value T) bool) bool { return v.s.All(predicate) }
+
+// Clone implements ReadOnly.
+func (v readOnly[T]) Clone() *SafeSet[T] { return v.s.Clone() }
+
+// String implements ReadOnly.
+func (v readOnly[T]) String() string { return v.s.String() }
+
+// MarshalJSON implements ReadOnly.
+func (v readOnly[T]) MarshalJSON() ([]byte, error) { return v.s.MarshalJSON() }
+
+// ReadOnly returns a read-only view of the set, e.g.: to hand it out without
+// copying it. The view isn't a snapshot: it reflects later changes to the set.
+// It can't be converted back to the set, use Clone to get a copy which can be
+// modified.
+func (s *SafeSet[T]) ReadOnly() ReadOnly[T] {
+	return readOnly[T]{s: s}
+}
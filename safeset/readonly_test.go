@@ -0,0 +1,38 @@
+package safeset
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadOnly(t *testing.T) {
+	s := New(1)
+
+	view := s.ReadOnly()
+
+	s.Add(2)
+
+	// The view reflects later changes.
+	assert.Equal(t, 2, view.Size())
+	assert.True(t, view.Contains(2))
+	assert.Equal(t, []int{1, 2}, view.Values())
+
+	sum := 0
+
+	view.Each(func(v int) { sum += v })
+
+	assert.Equal(t, 3, sum)
+
+	// The view can't be converted back.
+	_, ok := any(view).(*SafeSet[int])
+	assert.False(t, ok)
+
+	_, ok = any(view).(interface{ Add(int) *SafeSet[int] })
+	assert.False(t, ok)
+
+	// A clone can be modified, without affecting the set.
+	view.Clone().Add(3)
+
+	assert.Equal(t, 2, s.Size())
+}
@@ -1,40 +1,119 @@
 package safeset
 
 import (
+	"bytes"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"hash"
+	"io"
+	"reflect"
+	"sort"
+	"strconv"
 	"strings"
+	"sync"
 
+	"github.com/thalesfsp/go-common-types/internal/bsonjson"
+	"github.com/thalesfsp/go-common-types/metrics"
 	"github.com/thalesfsp/go-common-types/safeorderedmap"
 	"github.com/thalesfsp/go-common-types/shared"
+	"github.com/thalesfsp/go-common-types/statistical"
+	"github.com/thalesfsp/go-common-types/tuple"
 )
 
 //////
 // Const, vars, and types.
 //////
 
+const (
+	// MaxPowerSetSize is the largest set PowerSet accepts, which results in
+	// 2^MaxPowerSetSize subsets.
+	MaxPowerSetSize = 20
+
+	// chainSeparator separates the hash from the position of an element in
+	// its chain, see slot.
+	chainSeparator = "\x00"
+)
+
+// ErrPowerSetTooLarge is returned by PowerSet if the set has more than
+// MaxPowerSetSize elements.
+var ErrPowerSetTooLarge = errors.New("set too large for a power set")
+
+// Option allows to configure a SafeSet.
+type Option[T any] func(s *SafeSet[T])
+
 // SafeSet is a set that preserves the order of keys powered by generics.
+//
+// Elements are stored in an ordered map keyed by their hash, which is computed
+// once, when the element is added. Set operations reuse the stored hashes
+// instead of re-hashing the elements. Elements with the same hash, which
+// aren't equal, are chained, so a collision doesn't drop them, see
+// CollisionCount.
+//
+// The zero value is an empty set, ready to use, e.g.: as a struct field
+// filled by UnmarshalJSON.
 type SafeSet[T any] struct {
+	// mu serializes the changes to the chains of colliding elements, see
+	// find.
+	mu sync.RWMutex
+
+	// data is created on first use, by store, for the zero value.
 	data *safeorderedmap.SafeOrderedMap[T]
+	once sync.Once
+
+	equaler shared.Equaler[T]
+
+	// keepFirst keeps the first of equal elements, instead of the last added,
+	// see WithCaseInsensitive.
+	keepFirst bool
+}
+
+// elements are a copy of the elements of a set, by hash, so the elements of
+// another set are checked against them without holding the lock of both, see
+// copy.
+type elements[T any] struct {
+	set    *SafeSet[T]
+	chains map[string][]T
 }
 
 //////
 // Methods.
 //////
 
-// String is the stringer implementation.
-func (s SafeSet[T]) String() string {
-	s.data.RLock()
-	defer s.data.RUnlock()
+// contains checks if the copy contains the element stored under the key of
+// another set, reusing its hash.
+func (e elements[T]) contains(key string, value T) bool {
+	ok := false
+
+	for _, stored := range e.chains[chain(key)] {
+		if e.set.equal(stored, value) {
+			ok = true
+
+			break
+		}
+	}
+
+	e.set.store().Metrics().Operation("contains")
+	e.set.store().Metrics().Lookup(ok)
+
+	return ok
+}
 
+// String is the stringer implementation. It has a pointer receiver, as the
+// set holds its lock, so it must not be copied: format a *SafeSet.
+func (s *SafeSet[T]) String() string {
 	// Shoud print only the values. Should use string builder.
 	var sb strings.Builder
 
 	sb.WriteString("[")
 
-	for i, value := range s.data.Values() {
+	values := s.store().Values()
+
+	for i, value := range values {
 		sb.WriteString(fmt.Sprintf("%v", value))
 
-		if i < s.data.Size()-1 {
+		if i < len(values)-1 {
 			sb.WriteString(", ")
 		}
 	}
@@ -44,58 +123,226 @@ func (s SafeSet[T]) String() string {
 	return sb.String()
 }
 
+// store returns the elements, creating them on first use, for the zero value.
+func (s *SafeSet[T]) store() *safeorderedmap.SafeOrderedMap[T] {
+	s.once.Do(func() {
+		if s.data == nil {
+			s.data = safeorderedmap.New[T]()
+		}
+	})
+
+	return s.data
+}
+
+// hash returns the hash identifying the value, see WithEqualer.
+func (s *SafeSet[T]) hash(value T) string {
+	if s.equaler != nil {
+		return s.equaler.Hash(value)
+	}
+
+	return shared.GenerateHash(value)
+}
+
+// equal checks if both values are the same element, see WithEqualer. By
+// default, values are deeply equal, like reflect.DeepEqual.
+func (s *SafeSet[T]) equal(a, b T) bool {
+	if s.equaler != nil {
+		return s.equaler.Equal(a, b)
+	}
+
+	return reflect.DeepEqual(a, b)
+}
+
+// find returns the position, in the chain of elements with the hash, of the
+// element equal to the value, or the position following the chain, and false,
+// if it's missing. Caller must hold the lock.
+func (s *SafeSet[T]) find(hash string, value T) (int, bool) {
+	for i := 0; ; i++ {
+		stored, ok := s.store().Peek(slot(hash, i))
+		if !ok {
+			return i, false
+		}
+
+		if s.equal(stored, value) {
+			return i, true
+		}
+	}
+}
+
+// derive returns a new set with the given data, and the same configuration.
+func (s *SafeSet[T]) derive(data *safeorderedmap.SafeOrderedMap[T]) *SafeSet[T] {
+	set := &SafeSet[T]{data: data, equaler: s.equaler, keepFirst: s.keepFirst}
+
+	// Filtering may leave gaps in the chains, which end at the first missing
+	// position, see find, so they're rebuilt.
+	if set.CollisionCount() > 0 {
+		entries := set.store().Entries()
+
+		set.store().Clear()
+
+		for _, entry := range entries {
+			set.put(chain(entry.Key), entry.Value)
+		}
+	}
+
+	return set
+}
+
+// elements returns a copy of the elements of the set, as of a single point in
+// time, e.g.: to check the elements of another set against them, see Subset.
+func (s *SafeSet[T]) elements() elements[T] {
+	chains := map[string][]T{}
+
+	for _, entry := range s.store().Entries() {
+		hash := chain(entry.Key)
+
+		chains[hash] = append(chains[hash], entry.Value)
+	}
+
+	return elements[T]{set: s, chains: chains}
+}
+
+// empty returns a new, empty, set with the same configuration.
+func (s *SafeSet[T]) empty() *SafeSet[T] {
+	return s.derive(safeorderedmap.New[T]())
+}
+
+// put adds the element with the given hash, see Add. Caller must hold the
+// lock.
+func (s *SafeSet[T]) put(hash string, value T) {
+	i, ok := s.find(hash, value)
+	if ok && s.keepFirst {
+		return
+	}
+
+	s.store().Add(slot(hash, i), value)
+}
+
+// remove removes the element with the given hash, returning whether it was
+// present, see Remove. Caller must hold the lock.
+func (s *SafeSet[T]) remove(hash string, value T) bool {
+	i, ok := s.find(hash, value)
+	if !ok {
+		return false
+	}
+
+	s.store().Remove(slot(hash, i))
+
+	// Moves the last element of the chain to the freed position, keeping its
+	// order, so the chain has no gaps.
+	last := i
+
+	for {
+		if _, ok := s.store().Peek(slot(hash, last+1)); !ok {
+			break
+		}
+
+		last++
+	}
+
+	if last != i {
+		_ = s.store().RenameKey(slot(hash, last), slot(hash, i))
+	}
+
+	return true
+}
+
+// replace replaces the content of the set with the given values, atomically:
+// they're added to a new set, whose elements are swapped in at once.
+func (s *SafeSet[T]) replace(values []T) error {
+	loaded := s.empty()
+
+	for _, value := range values {
+		loaded.put(loaded.hash(value), value)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.store().ReplaceEntries(loaded.store().Entries())
+}
+
+// EstimateBytes estimates the memory used by the set, in bytes: per element,
+// its hash, and the bytes referenced by the element, as given by the sizer,
+// see WithSizer. The estimate is recorded into the metrics, if enabled.
+func (s *SafeSet[T]) EstimateBytes() int64 {
+	return s.store().EstimateBytes()
+}
+
+// Metrics returns the metrics of the set, nil if not enabled.
+func (s *SafeSet[T]) Metrics() *metrics.Metrics {
+	return s.store().Metrics()
+}
+
 //////
 // CRUD operations.
 
-// Add an element to the set.
+// Add an element to the set. An element equal to one already present
+// replaces it, unless the set keeps the first one, see WithCaseInsensitive.
 func (s *SafeSet[T]) Add(value T) *SafeSet[T] {
-	s.data.Add(shared.GenerateHash(value), value)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.put(s.hash(value), value)
 
 	return s
 }
 
-// Get retrieves an element from the slice at the specified index.
-func (s *SafeSet[T]) Get(index int) (T, bool) {
-	if index < 0 || index >= s.data.Size() {
-		return *new(T), false
+// Insert adds an element to the set, returning whether it wasn't present.
+func (s *SafeSet[T]) Insert(value T) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	hash := s.hash(value)
+
+	i, ok := s.find(hash, value)
+	if ok {
+		return false
 	}
 
-	return s.data.Values()[index], true
+	s.store().Add(slot(hash, i), value)
+
+	return true
 }
 
-// Delete removes an element from the slice at the specified index.
+// Remove removes an element from the set, returning whether it was present.
+func (s *SafeSet[T]) Remove(value T) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.remove(s.hash(value), value)
+}
+
+// Get retrieves an element from the slice at the specified index, in O(n),
+// see SafeOrderedMap.GetByIndex.
+func (s *SafeSet[T]) Get(index int) (T, bool) {
+	return s.store().GetByIndex(index)
+}
+
+// Delete removes an element from the slice at the specified index, in O(n).
 func (s *SafeSet[T]) Delete(index int) *SafeSet[T] {
-	if index < 0 || index >= s.data.Size() {
-		return s
-	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
 
-	s.data.Delete(shared.GenerateHash(s.data.Values()[index]))
+	if value, ok := s.store().GetByIndex(index); ok {
+		s.remove(s.hash(value), value)
+	}
 
 	return s
 }
 
 // First returns the first element in the set.
 func (s *SafeSet[T]) First() (T, bool) {
-	s.data.RLock()
-	defer s.data.RUnlock()
+	_, value, ok := s.store().First()
 
-	if s.data.Empty() {
-		return *new(T), false
-	}
-
-	return s.data.Values()[0], true
+	return value, ok
 }
 
 // Last returns the last element in the set.
 func (s *SafeSet[T]) Last() (T, bool) {
-	s.data.RLock()
-	defer s.data.RUnlock()
-
-	if s.data.Empty() {
-		return *new(T), false
-	}
+	_, value, ok := s.store().Last()
 
-	return s.data.Values()[s.data.Size()-1], true
+	return value, ok
 }
 
 //////
@@ -103,7 +350,29 @@ func (s *SafeSet[T]) Last() (T, bool) {
 
 // Values returns a list of all values in the set.
 func (s *SafeSet[T]) Values() []T {
-	return s.data.Values()
+	return s.store().Values()
+}
+
+// SortedValues returns the values of the set, sorted by less, e.g.: for
+// deterministic output. The set isn't modified.
+func (s *SafeSet[T]) SortedValues(less func(a, b T) bool) []T {
+	values := s.Values()
+
+	sort.SliceStable(values, func(i, j int) bool {
+		return less(values[i], values[j])
+	})
+
+	return values
+}
+
+// Sort reorders the elements of the set, stably, by less. Later additions are
+// still appended at the end.
+func (s *SafeSet[T]) Sort(less func(a, b T) bool) *SafeSet[T] {
+	s.store().SortFunc(func(a, b safeorderedmap.Entry[T]) bool {
+		return less(a.Value, b.Value)
+	})
+
+	return s
 }
 
 //////
@@ -111,33 +380,48 @@ func (s *SafeSet[T]) Values() []T {
 
 // Contains checks if the set contains a given element.
 func (s *SafeSet[T]) Contains(value T) bool {
-	_, ok := s.data.Get(shared.GenerateHash(value))
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	_, ok := s.find(s.hash(value), value)
+
+	s.store().Metrics().Operation("get")
+	s.store().Metrics().Lookup(ok)
 
 	return ok
 }
 
 // Size returns the number of elements in the set.
 func (s *SafeSet[T]) Size() int {
-	return s.data.Size()
+	return s.store().Size()
 }
 
 // Empty checks if the set is empty and returns a boolean value.
 func (s *SafeSet[T]) Empty() bool {
-	return s.data.Empty()
+	return s.store().Empty()
 }
 
-// Clone creates a deep copy of the set and returns it.
-func (s *SafeSet[T]) Clone() *SafeSet[T] {
-	s.data.RLock()
-	defer s.data.RUnlock()
-
-	clone := New[T]()
+// Digest returns a stable hash of the elements, regardless of their order,
+// like Equal, by h, or sha256 if nil, e.g.: for cheap equality checks, ETags,
+// and cache keys, see shared.DigestUnordered.
+func (s *SafeSet[T]) Digest(h hash.Hash) (string, error) {
+	return shared.DigestUnordered(h, s.Values())
+}
 
-	for _, value := range s.data.Values() {
-		clone.Add(value)
-	}
+// CollisionCount returns the number of elements having the same hash as an
+// element added before, which isn't equal, e.g.: 1, and "1", in a
+// SafeSet[any], by default. They're chained, so no element is dropped, but
+// lookups compare them one by one, so a high count, e.g.: with a custom hash,
+// see WithEqualer, slows the set down. It's O(n).
+func (s *SafeSet[T]) CollisionCount() int {
+	return len(s.store().KeysFunc(func(key string) bool {
+		return strings.Contains(key, chainSeparator)
+	}))
+}
 
-	return clone
+// Clone creates a deep copy of the set and returns it.
+func (s *SafeSet[T]) Clone() *SafeSet[T] {
+	return s.derive(s.store().Clone())
 }
 
 //////
@@ -157,10 +441,7 @@ func (s *SafeSet[T]) All(predicate func(value T) bool) bool {
 // Map returns a new set containing the results of applying the given function
 // to each element.
 func (s *SafeSet[T]) Map(f func(value T) T) *SafeSet[T] {
-	s.data.RLock()
-	defer s.data.RUnlock()
-
-	newSet := New[T]()
+	newSet := s.empty()
 
 	for _, value := range s.Values() {
 		newSet.Add(f(value))
@@ -172,15 +453,9 @@ func (s *SafeSet[T]) Map(f func(value T) T) *SafeSet[T] {
 // Filter returns a new set containing only the elements that satisfy the given
 // predicate.
 func (s *SafeSet[T]) Filter(predicate func(value T) bool) *SafeSet[T] {
-	result := New[T]()
-
-	for _, value := range s.Values() {
-		if predicate(value) {
-			result.Add(value)
-		}
-	}
-
-	return result
+	return s.derive(s.store().Filter(func(_ string, value T) bool {
+		return predicate(value)
+	}))
 }
 
 // Each iterates over the set and calls the given function for each element.
@@ -229,7 +504,7 @@ func (s *SafeSet[T]) Any(predicate func(value T) bool) bool {
 // TakeWhile returns a new set containing the first n elements that satisfy the
 // given predicate.
 func (s *SafeSet[T]) TakeWhile(predicate func(value T) bool) *SafeSet[T] {
-	result := New[T]()
+	result := s.empty()
 
 	for _, value := range s.Values() {
 		if predicate(value) {
@@ -245,7 +520,7 @@ func (s *SafeSet[T]) TakeWhile(predicate func(value T) bool) *SafeSet[T] {
 // DropWhile returns a new set containing all elements except the first n
 // elements that satisfy the given predicate.
 func (s *SafeSet[T]) DropWhile(predicate func(value T) bool) *SafeSet[T] {
-	result := New[T]()
+	result := s.empty()
 
 	for _, value := range s.Values() {
 		if predicate(value) {
@@ -259,41 +534,108 @@ func (s *SafeSet[T]) DropWhile(predicate func(value T) bool) *SafeSet[T] {
 }
 
 //////
-// Set operations.
+// Erroring Higher-Order Functions. They stop at the first error, returning it.
 
-// Union returns a new set containing all unique elements from both sets.
-func (s *SafeSet[T]) Union(other *SafeSet[T]) *SafeSet[T] {
-	result := s.Clone()
+// TryMap is like Map, but f can fail.
+func (s *SafeSet[T]) TryMap(f func(value T) (T, error)) (*SafeSet[T], error) {
+	newSet := s.empty()
 
-	for _, value := range other.Values() {
-		result.Add(value)
+	for _, value := range s.Values() {
+		mapped, err := f(value)
+		if err != nil {
+			return nil, err
+		}
+
+		newSet.Add(mapped)
 	}
 
-	return result
+	return newSet, nil
 }
 
-// Difference returns a new set containing elements present in the original set but not in the other set.
-func (s *SafeSet[T]) Difference(other *SafeSet[T]) *SafeSet[T] {
-	result := New[T]()
+// TryFilter is like Filter, but the predicate can fail.
+func (s *SafeSet[T]) TryFilter(predicate func(value T) (bool, error)) (*SafeSet[T], error) {
+	data, err := s.store().TryFilter(func(_ string, value T) (bool, error) {
+		return predicate(value)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.derive(data), nil
+}
 
+// TryEach is like Each, but f can fail.
+func (s *SafeSet[T]) TryEach(f func(value T) error) error {
 	for _, value := range s.Values() {
-		if !other.Contains(value) {
-			result.Add(value)
+		if err := f(value); err != nil {
+			return err
 		}
 	}
 
-	return result
+	return nil
 }
 
-// Subset checks if all elements of the original set are present in the other set.
-func (s *SafeSet[T]) Subset(other *SafeSet[T]) bool {
+// TryReduce is like Reduce, but the reducer can fail. On failure, the zero
+// value is returned.
+func (s *SafeSet[T]) TryReduce(reducer func(acc T, value T) (T, error), initialValue T) (T, error) {
+	acc := initialValue
+
 	for _, value := range s.Values() {
-		if !other.Contains(value) {
-			return false
+		var err error
+
+		acc, err = reducer(acc, value)
+		if err != nil {
+			return *new(T), err
 		}
 	}
 
-	return true
+	return acc, nil
+}
+
+//////
+// Set operations.
+//
+// Results have a deterministic order: the elements of the original set come
+// first, in its order, followed by the ones of the other sets, in their order.
+
+// Union returns a new set containing all unique elements from the sets, in a
+// single pass.
+func (s *SafeSet[T]) Union(others ...*SafeSet[T]) *SafeSet[T] {
+	result := s.Clone()
+
+	for _, other := range others {
+		other.store().Each(func(key string, value T) {
+			result.put(chain(key), value)
+		})
+	}
+
+	return result
+}
+
+// Difference returns a new set containing elements present in the original
+// set but in none of the other sets, in the order of the original set, in a
+// single pass.
+func (s *SafeSet[T]) Difference(others ...*SafeSet[T]) *SafeSet[T] {
+	copies := copyAll(others)
+
+	return s.derive(s.store().Filter(func(key string, value T) bool {
+		for _, other := range copies {
+			if other.contains(key, value) {
+				return false
+			}
+		}
+
+		return true
+	}))
+}
+
+// Subset checks if all elements of the original set are present in the other set.
+func (s *SafeSet[T]) Subset(other *SafeSet[T]) bool {
+	copied := other.elements()
+
+	return s.store().All(func(key string, value T) bool {
+		return copied.contains(key, value)
+	})
 }
 
 // Superset checks if all elements of the other set are present in the original set.
@@ -301,17 +643,75 @@ func (s *SafeSet[T]) Superset(other *SafeSet[T]) bool {
 	return other.Subset(s)
 }
 
-// Intersection returns a new set containing elements present in both sets.
-func (s *SafeSet[T]) Intersection(other *SafeSet[T]) *SafeSet[T] {
-	result := New[T]()
+// IsDisjoint checks if the sets have no elements in common. It's cheaper than
+// checking if the Intersection is empty, as no set is created, and it stops at
+// the first common element.
+func (s *SafeSet[T]) IsDisjoint(other *SafeSet[T]) bool {
+	copied := other.elements()
 
-	for _, value := range s.Values() {
-		if other.Contains(value) {
-			result.Add(value)
+	return s.store().All(func(key string, value T) bool {
+		return !copied.contains(key, value)
+	})
+}
+
+// Equal checks if both sets contain the same elements, regardless of their
+// order.
+func (s *SafeSet[T]) Equal(other *SafeSet[T]) bool {
+	return s.Size() == other.Size() && s.Subset(other)
+}
+
+// Intersection returns a new set containing elements present in all sets, in
+// the order of the original set, in a single pass.
+func (s *SafeSet[T]) Intersection(others ...*SafeSet[T]) *SafeSet[T] {
+	copies := copyAll(others)
+
+	return s.derive(s.store().Filter(func(key string, value T) bool {
+		for _, other := range copies {
+			if !other.contains(key, value) {
+				return false
+			}
 		}
+
+		return true
+	}))
+}
+
+// SymmetricDifference returns a new set containing elements present in either
+// set but not in both. Elements of the original set come first, in its order,
+// followed by the ones of the other set, in its order.
+func (s *SafeSet[T]) SymmetricDifference(other *SafeSet[T]) *SafeSet[T] {
+	return s.Difference(other).Union(other.Difference(s))
+}
+
+//////
+// Aggregations.
+
+// MinBy returns the smallest element according to less, the first one if
+// there are ties, or false if the set is empty.
+func (s *SafeSet[T]) MinBy(less func(a, b T) bool) (T, bool) {
+	values := s.Values()
+
+	if len(values) == 0 {
+		return *new(T), false
 	}
 
-	return result
+	result := values[0]
+
+	for _, value := range values[1:] {
+		if less(value, result) {
+			result = value
+		}
+	}
+
+	return result, true
+}
+
+// MaxBy returns the largest element according to less, the first one if
+// there are ties, or false if the set is empty.
+func (s *SafeSet[T]) MaxBy(less func(a, b T) bool) (T, bool) {
+	return s.MinBy(func(a, b T) bool {
+		return less(b, a)
+	})
 }
 
 //////
@@ -320,12 +720,173 @@ func (s *SafeSet[T]) Intersection(other *SafeSet[T]) *SafeSet[T] {
 
 // MarshalJSON implements json.Marshaler interface for SafeSet.
 func (s *SafeSet[T]) MarshalJSON() ([]byte, error) {
-	return s.data.MarshalJSON()
+	return s.store().MarshalJSON()
 }
 
-// UnmarshalJSON implements json.Unmarshaler interface for SafeSet.
+// UnmarshalJSON implements json.Unmarshaler interface for SafeSet. It accepts
+// both an object, as encoded by MarshalJSON, and an array of elements. The
+// hashes of the object are ignored: elements are hashed by the set, see
+// WithEqualer. It atomically replaces the content of the set, duplicates are
+// ignored.
 func (s *SafeSet[T]) UnmarshalJSON(data []byte) error {
-	return s.data.UnmarshalJSON(data)
+	return s.Decode(bytes.NewReader(data))
+}
+
+// Encode writes the set to w as JSON, like MarshalJSON, marshalling one
+// element at a time.
+func (s *SafeSet[T]) Encode(w io.Writer) error {
+	return s.store().Encode(w)
+}
+
+// Decode reads the set from r as JSON, like UnmarshalJSON, decoding one
+// element at a time. It replaces the content of the set.
+func (s *SafeSet[T]) Decode(r io.Reader) error {
+	dec := json.NewDecoder(r)
+
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+
+	values := []T{}
+
+	switch tok {
+	case nil:
+	case json.Delim('['), json.Delim('{'):
+		for dec.More() {
+			// Skips the hash of the element.
+			if tok == json.Delim('{') {
+				if _, err := dec.Token(); err != nil {
+					return err
+				}
+			}
+
+			var value T
+
+			if err := dec.Decode(&value); err != nil {
+				return err
+			}
+
+			values = append(values, value)
+		}
+
+		// Consumes the closing delimiter.
+		if _, err := dec.Token(); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("expected a JSON object, or array, got %v", tok)
+	}
+
+	return s.replace(values)
+}
+
+// MarshalText implements the encoding.TextMarshaler interface. Elements are
+// comma-separated, e.g.: a,b,c. Commas, and backslashes are escaped by a
+// backslash.
+func (s *SafeSet[T]) MarshalText() ([]byte, error) {
+	return shared.FormatTextList(s.Values())
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface, the inverse
+// of MarshalText. It atomically replaces the content of the set, duplicates
+// are ignored.
+func (s *SafeSet[T]) UnmarshalText(text []byte) error {
+	values, err := shared.ParseTextList[T](text)
+	if err != nil {
+		return err
+	}
+
+	return s.replace(values)
+}
+
+// EncodeWith encodes the set as an array of its elements. It's meant to be
+// used with formats such as MessagePack, and CBOR.
+func (s *SafeSet[T]) EncodeWith(enc shared.Encoder) error {
+	return shared.EncodeList(enc, s.Values())
+}
+
+// DecodeWith decodes an array encoded by EncodeWith. It atomically replaces
+// the content of the set, duplicates are ignored.
+func (s *SafeSet[T]) DecodeWith(dec shared.Decoder) error {
+	values, err := shared.DecodeList[T](dec)
+	if err != nil {
+		return err
+	}
+
+	return s.replace(values)
+}
+
+// MarshalBSON implements bson.Marshaler interface for SafeSet. BSON requires a
+// document, so the set is encoded as a document keyed by the index of the
+// elements, which is how BSON represents arrays. Elements keep their BSON
+// types, like with the MongoDB driver, e.g.: int64, or time.Time as a
+// datetime.
+func (s *SafeSet[T]) MarshalBSON() ([]byte, error) {
+	return bsonjson.MarshalList(s.Values())
+}
+
+// UnmarshalBSON implements bson.Unmarshaler interface for SafeSet. It
+// atomically replaces the content of the set, duplicates are ignored.
+func (s *SafeSet[T]) UnmarshalBSON(data []byte) error {
+	values, err := bsonjson.UnmarshalList[T](data)
+	if err != nil {
+		return err
+	}
+
+	return s.replace(values)
+}
+
+// Value implements the driver.Valuer interface, storing the set as JSON.
+func (s *SafeSet[T]) Value() (driver.Value, error) {
+	if s == nil {
+		return nil, nil
+	}
+
+	return s.store().Value()
+}
+
+// Scan implements the sql.Scanner interface, loading the set from JSON.
+func (s *SafeSet[T]) Scan(src any) error {
+	return s.store().Scan(src)
+}
+
+//////
+// Helpers.
+//////
+
+// slot returns the key of the element at position i of the chain of elements
+// with the hash: the hash itself for the first, which is the only one unless
+// hashes collide, see CollisionCount.
+func slot(hash string, i int) string {
+	if i == 0 {
+		return hash
+	}
+
+	return hash + chainSeparator + strconv.Itoa(i)
+}
+
+// copyAll copies the elements of the sets, once each, even if repeated.
+func copyAll[T any](sets []*SafeSet[T]) []elements[T] {
+	copies := make([]elements[T], 0, len(sets))
+	copied := make(map[*SafeSet[T]]struct{}, len(sets))
+
+	for _, set := range sets {
+		if _, ok := copied[set]; !ok {
+			copies = append(copies, set.elements())
+
+			copied[set] = struct{}{}
+		}
+	}
+
+	return copies
+}
+
+// chain returns the hash of the chain the key belongs to, see slot.
+func chain(key string) string {
+	hash, _, _ := strings.Cut(key, chainSeparator)
+
+	return hash
 }
 
 //////
@@ -345,6 +906,68 @@ func New[T any](v ...T) *SafeSet[T] {
 	return set
 }
 
+// WithMetrics enables metrics instrumentation, tracking operation counts,
+// size, lock wait times, and hit/miss ratios into the given metrics.
+func WithMetrics[T any](mtrcs *metrics.Metrics) Option[T] {
+	return func(s *SafeSet[T]) {
+		safeorderedmap.WithMetrics[T](mtrcs)(s.store())
+	}
+}
+
+// WithSizer sets the sizer of the elements, used by EstimateBytes to count the
+// bytes they reference. By default, only the contents of strings, and byte
+// slices are counted.
+func WithSizer[T any](sizer shared.Sizer[T]) Option[T] {
+	return func(s *SafeSet[T]) {
+		safeorderedmap.WithSizer[T](sizer)(s.store())
+	}
+}
+
+// WithEqualer sets the identity of the elements, e.g.: to deduplicate structs
+// by their ID, with shared.EqualBy. Sets derived from the set, e.g.: by Filter,
+// or Union, keep it. Set operations between sets with a different identity
+// are undefined. By default, elements are identified by shared.GenerateHash.
+func WithEqualer[T any](equaler shared.Equaler[T]) Option[T] {
+	return func(s *SafeSet[T]) {
+		s.equaler = equaler
+	}
+}
+
+// WithCaseInsensitive makes a set of strings case-insensitive, e.g.: for HTTP
+// header names: elements differing only by case are the same, see
+// shared.EqualFold, and the first added keeps its casing for output, e.g.:
+// Values, and JSON.
+func WithCaseInsensitive() Option[string] {
+	return func(s *SafeSet[string]) {
+		s.equaler = shared.EqualFold()
+		s.keepFirst = true
+	}
+}
+
+// NewWithOptions creates a new, empty, SafeSet configured with the given
+// options.
+func NewWithOptions[T any](opts ...Option[T]) *SafeSet[T] {
+	set := New[T]()
+
+	for _, opt := range opts {
+		opt(set)
+	}
+
+	return set
+}
+
+// NewFromJSON creates a new SafeSet configured with the given options,
+// loaded from JSON, as encoded by MarshalJSON.
+func NewFromJSON[T any](data []byte, opts ...Option[T]) (*SafeSet[T], error) {
+	set := NewWithOptions(opts...)
+
+	if err := set.UnmarshalJSON(data); err != nil {
+		return nil, err
+	}
+
+	return set, nil
+}
+
 //////
 // Exported Functionalities.
 //////
@@ -364,3 +987,62 @@ func Pluck[T, R comparable](s *SafeSet[T], predicate func(T) R) []R {
 
 	return result
 }
+
+// Min returns the smallest element, or false if the set is empty.
+func Min[T statistical.Numbers](s *SafeSet[T]) (T, bool) {
+	return s.MinBy(func(a, b T) bool { return a < b })
+}
+
+// Max returns the largest element, or false if the set is empty.
+func Max[T statistical.Numbers](s *SafeSet[T]) (T, bool) {
+	return s.MaxBy(func(a, b T) bool { return a < b })
+}
+
+// Sum returns the sum of the elements, 0 if the set is empty.
+func Sum[T statistical.Numbers](s *SafeSet[T]) T {
+	return s.Reduce(func(acc, value T) T { return acc + value }, 0)
+}
+
+// Product returns the cartesian product of both sets, as a set of pairs. Pairs
+// are ordered by the elements of a, then by the ones of b.
+func Product[A, B any](a *SafeSet[A], b *SafeSet[B]) *SafeSet[tuple.Pair[A, B]] {
+	result := New[tuple.Pair[A, B]]()
+
+	seconds := b.Values()
+
+	for _, first := range a.Values() {
+		for _, second := range seconds {
+			result.Add(tuple.NewPair(first, second))
+		}
+	}
+
+	return result
+}
+
+// PowerSet returns all the subsets of the set, starting with the empty one.
+// Subsets are ordered as binary counting over the elements, e.g.: [], [a],
+// [b], [a, b]. Sets with more than MaxPowerSetSize elements are rejected with
+// ErrPowerSetTooLarge.
+func PowerSet[T any](s *SafeSet[T]) ([]*SafeSet[T], error) {
+	values := s.Values()
+
+	if len(values) > MaxPowerSetSize {
+		return nil, fmt.Errorf("%w: %d elements, max %d", ErrPowerSetTooLarge, len(values), MaxPowerSetSize)
+	}
+
+	result := make([]*SafeSet[T], 0, 1<<len(values))
+
+	for mask := 0; mask < 1<<len(values); mask++ {
+		subset := New[T]()
+
+		for i, value := range values {
+			if mask&(1<<i) != 0 {
+				subset.Add(value)
+			}
+		}
+
+		result = append(result, subset)
+	}
+
+	return result, nil
+}
@@ -1,10 +1,21 @@
 package safeset
 
 import (
+	"bytes"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/thalesfsp/go-common-types/internal/codectest"
+	"github.com/thalesfsp/go-common-types/metrics"
+	"github.com/thalesfsp/go-common-types/shared"
+	"github.com/thalesfsp/go-common-types/tuple"
 )
 
 func TestSafeSetAdd(t *testing.T) {
@@ -107,6 +118,15 @@ func TestSafeSetIntersection(t *testing.T) {
 	assert.True(t, intersection.Contains(3))
 }
 
+func TestSafeSetSymmetricDifference(t *testing.T) {
+	s1 := New(3, 1, 2)
+	s2 := New(5, 3, 4)
+
+	assert.Equal(t, []int{1, 2, 5, 4}, s1.SymmetricDifference(s2).Values())
+	assert.Equal(t, []int{5, 4, 1, 2}, s2.SymmetricDifference(s1).Values())
+	assert.True(t, s1.SymmetricDifference(s1).Empty())
+}
+
 func TestSafeSetSubsetSuperset(t *testing.T) {
 	s1 := New(1, 2, 3)
 	s2 := New(1, 2)
@@ -119,6 +139,19 @@ func TestSafeSetSubsetSuperset(t *testing.T) {
 	assert.False(t, s1.Superset(s3))
 }
 
+func TestSafeSetIsDisjointEqual(t *testing.T) {
+	s1 := New(1, 2, 3)
+
+	assert.True(t, s1.IsDisjoint(New(4, 5)))
+	assert.False(t, s1.IsDisjoint(New(5, 3)))
+	assert.True(t, s1.IsDisjoint(New[int]()))
+
+	assert.True(t, s1.Equal(New(3, 1, 2)))
+	assert.False(t, s1.Equal(New(1, 2)))
+	assert.False(t, s1.Equal(New(1, 2, 4)))
+	assert.True(t, New[int]().Equal(New[int]()))
+}
+
 func TestSafeSetDifference(t *testing.T) {
 	s1 := New(1, 2, 3)
 	s2 := New(3, 4, 5)
@@ -146,6 +179,57 @@ func TestSafeSetMarshalUnmarshalJSON(t *testing.T) {
 	assert.True(t, unmarshaled.Contains(3))
 }
 
+func TestSafeSetUnmarshalJSONRehash(t *testing.T) {
+	marshaled, err := New("A", "a", "b").MarshalJSON()
+	assert.NoError(t, err)
+
+	// Elements are hashed by the set, not by the input.
+	folded := NewWithOptions(WithCaseInsensitive())
+
+	assert.NoError(t, folded.UnmarshalJSON(marshaled))
+	assert.Equal(t, 2, folded.Size())
+	assert.True(t, folded.Contains("a"))
+	assert.True(t, folded.Contains("B"))
+
+	folded, err = NewFromJSON([]byte(`["x","X","y"]`), WithCaseInsensitive())
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"x", "y"}, folded.Values())
+
+	assert.NoError(t, folded.Decode(strings.NewReader(`["z"]`)))
+	assert.Equal(t, []string{"z"}, folded.Values())
+
+	assert.NoError(t, folded.UnmarshalJSON([]byte(`null`)))
+	assert.True(t, folded.Empty())
+
+	assert.Error(t, folded.UnmarshalJSON([]byte(`"x"`)))
+	assert.Error(t, folded.UnmarshalJSON([]byte(`[1]`)))
+}
+
+func TestSafeSetEncodeDecode(t *testing.T) {
+	var buf bytes.Buffer
+
+	assert.NoError(t, New(1, 2, 3).Encode(&buf))
+
+	decoded := New(9)
+
+	assert.NoError(t, decoded.Decode(&buf))
+	assert.Equal(t, []int{1, 2, 3}, decoded.Values())
+}
+
+func TestSafeSetSort(t *testing.T) {
+	s := New("go", "api", "db")
+
+	less := func(a, b string) bool { return a < b }
+
+	assert.Equal(t, []string{"api", "db", "go"}, s.SortedValues(less))
+	assert.Equal(t, []string{"go", "api", "db"}, s.Values())
+
+	s.Sort(less).Add("cli")
+
+	assert.Equal(t, []string{"api", "db", "go", "cli"}, s.Values())
+	assert.True(t, s.Contains("go"))
+}
+
 func TestSafeSetString(t *testing.T) {
 	s := New(1, 2, 3)
 	expected := "[1, 2, 3]"
@@ -248,3 +332,434 @@ func TestSafeSet_Pluck(t *testing.T) {
 
 	assert.Equal(t, []string{"test1", "test2"}, actual)
 }
+
+func TestSafeSetWithMetrics(t *testing.T) {
+	s := NewWithOptions[int](WithMetrics[int](metrics.New("set")))
+	s.Add(1).Add(2).Add(2)
+
+	assert.True(t, s.Contains(1))
+	assert.False(t, s.Contains(3))
+
+	snapshot := s.Metrics().Snapshot()
+
+	assert.Equal(t, uint64(3), snapshot.Operations["add"])
+	assert.Equal(t, int64(2), snapshot.Size)
+	assert.Equal(t, uint64(1), snapshot.Hits)
+	assert.Equal(t, uint64(1), snapshot.Misses)
+}
+
+func TestSafeSetDeleteByIndex(t *testing.T) {
+	s := New(1, 2, 3)
+
+	s.Delete(1).Delete(5).Delete(-1)
+
+	assert.Equal(t, []int{1, 3}, s.Values())
+	assert.False(t, s.Contains(2))
+}
+
+func TestSafeSetCloneIsIndependent(t *testing.T) {
+	s := New(1, 2, 3)
+	c := s.Clone()
+
+	c.Add(4)
+
+	assert.Equal(t, 3, s.Size())
+	assert.Equal(t, []int{1, 2, 3, 4}, c.Values())
+	assert.True(t, c.Contains(4))
+}
+
+func newBenchmarkSet(n int) *SafeSet[int] {
+	s := New[int]()
+
+	for i := 0; i < n; i++ {
+		s.Add(i)
+	}
+
+	return s
+}
+
+func BenchmarkSafeSetContains(b *testing.B) {
+	for _, n := range []int{1_000, 100_000} {
+		b.Run(fmt.Sprintf("%d", n), func(b *testing.B) {
+			s := newBenchmarkSet(n)
+
+			b.ResetTimer()
+
+			for i := 0; i < b.N; i++ {
+				s.Contains(i % (2 * n))
+			}
+		})
+	}
+}
+
+func BenchmarkSafeSetGet(b *testing.B) {
+	s := newBenchmarkSet(100_000)
+
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		s.Get(i % 10)
+	}
+}
+
+func BenchmarkSafeSetIntersection(b *testing.B) {
+	s1 := newBenchmarkSet(100_000)
+	s2 := newBenchmarkSet(50_000)
+
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		s1.Intersection(s2)
+	}
+}
+
+func TestSafeSetValueScan(t *testing.T) {
+	var (
+		_ driver.Valuer = New[int]()
+		_ sql.Scanner   = New[int]()
+	)
+
+	s := New(1, 2, 3)
+
+	v, err := s.Value()
+	assert.NoError(t, err)
+
+	s2 := New[int]()
+	assert.NoError(t, s2.Scan(v))
+	assert.Equal(t, 3, s2.Size())
+	assert.True(t, s2.Contains(1))
+	assert.True(t, s2.Contains(3))
+
+	assert.Error(t, s2.Scan(true))
+}
+
+func TestSafeSetMarshalUnmarshalText(t *testing.T) {
+	s := New("a", "b")
+
+	text, err := s.MarshalText()
+	assert.NoError(t, err)
+	assert.Equal(t, "a,b", string(text))
+
+	s2 := New("c")
+	assert.NoError(t, s2.UnmarshalText([]byte("x,y,x")))
+	assert.Equal(t, []string{"x", "y"}, s2.Values())
+	assert.False(t, s2.Contains("c"))
+}
+
+func TestSafeSetEncodeDecodeWith(t *testing.T) {
+	c := &codectest.TokenCodec{}
+
+	assert.NoError(t, New(3, 1, 2).EncodeWith(c))
+	assert.Equal(t, []any{3, 3, 1, 2}, c.Tokens)
+
+	s := New(9)
+	assert.NoError(t, s.DecodeWith(c))
+	assert.Equal(t, []int{3, 1, 2}, s.Values())
+}
+
+func TestSafeSetMarshalUnmarshalBSON(t *testing.T) {
+	b, err := New(3, 1, 2).MarshalBSON()
+	assert.NoError(t, err)
+
+	s := New[int]()
+	assert.NoError(t, s.UnmarshalBSON(b))
+	assert.Equal(t, []int{3, 1, 2}, s.Values())
+}
+
+func TestSafeSetInsertRemove(t *testing.T) {
+	s := New(1)
+
+	assert.True(t, s.Insert(2))
+	assert.False(t, s.Insert(1))
+	assert.True(t, s.Remove(1))
+	assert.False(t, s.Remove(1))
+	assert.Equal(t, []int{2}, s.Values())
+}
+
+func TestSafeSetTryFunctions(t *testing.T) {
+	errNegative := errors.New("negative")
+
+	failing := func(v int) (int, error) {
+		if v < 0 {
+			return 0, errNegative
+		}
+
+		return v * 2, nil
+	}
+
+	s := New(1, 2, 3)
+
+	doubled, err := s.TryMap(failing)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{2, 4, 6}, doubled.Values())
+
+	odd, err := s.TryFilter(func(v int) (bool, error) { return v%2 == 1, nil })
+	assert.NoError(t, err)
+	assert.Equal(t, []int{1, 3}, odd.Values())
+
+	sum, err := s.TryReduce(func(acc, v int) (int, error) { return acc + v, nil }, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, 6, sum)
+
+	s.Add(-1)
+
+	_, err = s.TryMap(failing)
+	assert.ErrorIs(t, err, errNegative)
+
+	_, err = s.TryFilter(func(v int) (bool, error) {
+		_, err := failing(v)
+
+		return true, err
+	})
+	assert.ErrorIs(t, err, errNegative)
+
+	err = s.TryEach(func(v int) error {
+		_, err := failing(v)
+
+		return err
+	})
+	assert.ErrorIs(t, err, errNegative)
+
+	_, err = s.TryReduce(func(acc, v int) (int, error) { return failing(v) }, 0)
+	assert.ErrorIs(t, err, errNegative)
+}
+
+func TestSafeSetAggregations(t *testing.T) {
+	s := New(3, -1, 4, 5)
+
+	minimum, ok := Min(s)
+	assert.True(t, ok)
+	assert.Equal(t, -1, minimum)
+
+	maximum, ok := Max(s)
+	assert.True(t, ok)
+	assert.Equal(t, 5, maximum)
+
+	assert.Equal(t, 11, Sum(s))
+
+	words := New("bb", "a", "ccc", "dd")
+	byLen := func(a, b string) bool { return len(a) < len(b) }
+
+	longest, ok := words.MaxBy(byLen)
+	assert.True(t, ok)
+	assert.Equal(t, "ccc", longest)
+
+	_, ok = New[int]().MinBy(func(a, b int) bool { return a < b })
+	assert.False(t, ok)
+}
+
+func TestProduct(t *testing.T) {
+	p := Product(New("a", "b"), New(1, 2))
+
+	assert.Equal(t, []tuple.Pair[string, int]{
+		tuple.NewPair("a", 1),
+		tuple.NewPair("a", 2),
+		tuple.NewPair("b", 1),
+		tuple.NewPair("b", 2),
+	}, p.Values())
+
+	assert.True(t, Product(New[string](), New(1, 2)).Empty())
+}
+
+func TestPowerSet(t *testing.T) {
+	subsets, err := PowerSet(New("a", "b", "c"))
+	assert.NoError(t, err)
+
+	actual := []string{}
+
+	for _, subset := range subsets {
+		actual = append(actual, subset.String())
+	}
+
+	assert.Equal(t, []string{
+		"[]", "[a]", "[b]", "[a, b]", "[c]", "[a, c]", "[b, c]", "[a, b, c]",
+	}, actual)
+
+	values := make([]int, MaxPowerSetSize+1)
+	for i := range values {
+		values[i] = i
+	}
+
+	_, err = PowerSet(New(values...))
+	assert.ErrorIs(t, err, ErrPowerSetTooLarge)
+}
+
+func TestWithEqualer(t *testing.T) {
+	type user struct {
+		ID   string
+		Name string
+	}
+
+	s := NewWithOptions(WithEqualer(shared.EqualBy(func(u user) string { return u.ID })))
+
+	s.Add(user{"1", "bob"}).Add(user{"2", "alice"}).Add(user{"1", "robert"})
+
+	assert.Equal(t, 2, s.Size())
+	assert.True(t, s.Contains(user{ID: "1"}))
+	assert.False(t, s.Insert(user{ID: "2"}))
+
+	filtered := s.Filter(func(u user) bool { return u.ID == "1" })
+	assert.True(t, filtered.Contains(user{ID: "1"}))
+
+	mapped := s.Map(func(u user) user { return user{ID: "1", Name: u.Name} })
+	assert.Equal(t, 1, mapped.Size())
+
+	assert.True(t, s.Remove(user{ID: "2"}))
+	assert.Equal(t, []user{{"1", "robert"}}, s.Values())
+}
+
+func TestWithCaseInsensitive(t *testing.T) {
+	s := NewWithOptions(WithCaseInsensitive())
+
+	s.Add("Content-Type").Add("Accept").Add("content-type")
+
+	assert.Equal(t, []string{"Content-Type", "Accept"}, s.Values())
+	assert.True(t, s.Contains("ACCEPT"))
+	assert.False(t, s.Insert("accept"))
+
+	union := s.Union(NewWithOptions(WithCaseInsensitive()).Add("CONTENT-TYPE").Add("X-Request-ID"))
+
+	assert.Equal(t, []string{"Content-Type", "Accept", "X-Request-ID"}, union.Values())
+	assert.True(t, union.Contains("x-request-id"))
+
+	assert.True(t, s.Remove("accept"))
+	assert.Equal(t, []string{"Content-Type"}, s.Values())
+}
+
+func TestSafeSetEstimateBytes(t *testing.T) {
+	m := metrics.New("test")
+
+	s := NewWithOptions[[]int](WithMetrics[[]int](m), WithSizer(func(v []int) int { return 8 * len(v) }))
+	empty := s.EstimateBytes()
+
+	s.Add([]int{1, 2})
+
+	one := s.EstimateBytes()
+
+	assert.Greater(t, one, empty+24+16)
+	assert.Equal(t, one, m.Snapshot().Bytes)
+
+	s.Add([]int{1, 2})
+
+	assert.Equal(t, one, s.EstimateBytes())
+}
+
+func TestSafeSetDigest(t *testing.T) {
+	a, err := New(1, 2, 3).Digest(nil)
+	assert.NoError(t, err)
+
+	b, err := New(3, 1, 2).Digest(nil)
+	assert.NoError(t, err)
+	assert.Equal(t, a, b)
+
+	c, err := New(1, 2).Digest(nil)
+	assert.NoError(t, err)
+	assert.NotEqual(t, a, c)
+}
+
+// firstLetter is an Equaler with a weak hash, to force collisions.
+type firstLetter struct{}
+
+func (firstLetter) Equal(a, b string) bool { return a == b }
+
+func (firstLetter) Hash(v string) string { return v[:1] }
+
+func TestSafeSetCollisions(t *testing.T) {
+	// 1, and "1" have the same default hash.
+	s := New[any](1, "1", 1)
+
+	assert.Equal(t, []any{1, "1"}, s.Values())
+	assert.Equal(t, 1, s.CollisionCount())
+	assert.True(t, s.Contains("1"))
+	assert.False(t, s.Insert("1"))
+
+	assert.True(t, s.Remove(1))
+	assert.True(t, s.Contains("1"))
+	assert.False(t, s.Contains(1))
+	assert.Equal(t, 0, s.CollisionCount())
+
+	weak := NewWithOptions(WithEqualer[string](firstLetter{}))
+	weak.Add("apple").Add("avocado").Add("apricot").Add("banana")
+
+	assert.Equal(t, 2, weak.CollisionCount())
+
+	// Removing the middle of a chain keeps the others reachable, and ordered.
+	assert.True(t, weak.Remove("avocado"))
+	assert.True(t, weak.Contains("apricot"))
+	assert.Equal(t, []string{"apple", "apricot", "banana"}, weak.Values())
+
+	weak.Delete(0)
+	assert.True(t, weak.Contains("apricot"))
+	assert.False(t, weak.Contains("apple"))
+	assert.Equal(t, 0, weak.CollisionCount())
+}
+
+func TestSafeSetCollisionsSetOperations(t *testing.T) {
+	opt := WithEqualer[string](firstLetter{})
+
+	a := NewWithOptions(opt).Add("apple").Add("avocado").Add("banana")
+	b := NewWithOptions(opt).Add("avocado").Add("apricot")
+
+	assert.Equal(t, []string{"apple", "avocado", "banana", "apricot"}, a.Union(b).Values())
+	assert.Equal(t, []string{"avocado"}, a.Intersection(b).Values())
+	assert.Equal(t, []string{"apple", "banana"}, a.Difference(b).Values())
+	assert.Equal(t, []string{"apple", "banana", "apricot"}, a.SymmetricDifference(b).Values())
+	assert.False(t, a.IsDisjoint(b))
+	assert.True(t, NewWithOptions(opt).Add("avocado").Subset(a))
+	assert.False(t, b.Subset(a))
+
+	// Filtering out the head of a chain keeps the rest reachable.
+	filtered := a.Filter(func(v string) bool { return v != "apple" })
+	assert.True(t, filtered.Contains("avocado"))
+	assert.Equal(t, 0, filtered.CollisionCount())
+}
+
+func TestSafeSetVariadicSetOperations(t *testing.T) {
+	a := New(1, 2, 3, 4)
+	b := New(2, 3, 5)
+	c := New(3, 4, 6)
+
+	assert.Equal(t, []int{1, 2, 3, 4, 5, 6}, a.Union(b, c).Values())
+	assert.Equal(t, []int{3}, a.Intersection(b, c).Values())
+	assert.Equal(t, []int{1}, a.Difference(b, c).Values())
+
+	// Repeating a set, or passing the set itself, doesn't deadlock.
+	assert.Equal(t, []int{2, 3}, a.Intersection(b, b, a).Values())
+
+	// Without other sets, the results are copies, or empty.
+	assert.Equal(t, a.Values(), a.Union().Values())
+	assert.Equal(t, a.Values(), a.Intersection().Values())
+	assert.Equal(t, a.Values(), a.Difference().Values())
+}
+
+func TestSafeSetZeroValue(t *testing.T) {
+	var config struct {
+		Tags SafeSet[string] `json:"tags"`
+	}
+
+	tags, err := New("go").MarshalJSON()
+	assert.NoError(t, err)
+
+	assert.NoError(t, json.Unmarshal([]byte(`{"tags":`+string(tags)+`}`), &config))
+	assert.True(t, config.Tags.Contains("go"))
+
+	var s SafeSet[int]
+
+	s.Add(1).Add(1)
+
+	assert.Equal(t, []int{1}, s.Values())
+	assert.Equal(t, "[1]", s.String())
+}
+
+func TestNewFromJSON(t *testing.T) {
+	marshaled, err := New("a", "b").MarshalJSON()
+	assert.NoError(t, err)
+
+	s, err := NewFromJSON[string](marshaled)
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []string{"a", "b"}, s.Values())
+	assert.True(t, s.Contains("b"))
+
+	_, err = NewFromJSON[string]([]byte(`[`))
+	assert.Error(t, err)
+}
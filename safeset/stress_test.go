@@ -0,0 +1,167 @@
+package safeset
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// stressGoroutines is the number of goroutines of the stress tests, fewer with
+// -short.
+func stressGoroutines() int {
+	if testing.Short() {
+		return 32
+	}
+
+	return 256
+}
+
+// TestStress runs Insert, and then Remove, of the same elements from hundreds
+// of goroutines. Run it with -race. Each one must succeed exactly once.
+func TestStress(t *testing.T) {
+	const values = 100
+
+	n := stressGoroutines()
+	s := New[int]()
+
+	for _, op := range []func(int) bool{s.Insert, s.Remove} {
+		var (
+			wg        sync.WaitGroup
+			successes = make([]int64, values)
+		)
+
+		for g := 0; g < n; g++ {
+			wg.Add(1)
+
+			go func(g int) {
+				defer wg.Done()
+
+				for i := 0; i < values; i++ {
+					v := (g + i) % values
+
+					if op(v) {
+						atomic.AddInt64(&successes[v], 1)
+					}
+
+					_ = s.Contains(v)
+					_ = s.Size()
+
+					if i%10 == 0 {
+						_ = s.Values()
+					}
+				}
+			}(g)
+		}
+
+		wg.Wait()
+
+		for v, count := range successes {
+			assert.Equal(t, int64(1), count, "value %d", v)
+		}
+	}
+
+	assert.True(t, s.Empty())
+}
+
+// TestStressReplace replaces the content of the set, alternating between two
+// sets of elements, while readers check they only see one of them, whole.
+func TestStressReplace(t *testing.T) {
+	const values = 50
+
+	first, second := New[int](), New[int]()
+
+	for i := 0; i < values; i++ {
+		first.Add(i)
+		second.Add(values + i)
+	}
+
+	texts := make([][]byte, 0, 2)
+
+	for _, set := range []*SafeSet[int]{first, second} {
+		text, err := set.MarshalText()
+		assert.NoError(t, err)
+
+		texts = append(texts, text)
+	}
+
+	n := stressGoroutines()
+	s := New[int]()
+
+	assert.NoError(t, s.UnmarshalText(texts[0]))
+
+	var wg sync.WaitGroup
+
+	for g := 0; g < n; g++ {
+		wg.Add(1)
+
+		go func(g int) {
+			defer wg.Done()
+
+			for i := 0; i < 20; i++ {
+				if g%2 == 0 {
+					assert.NoError(t, s.UnmarshalText(texts[(g/2+i)%2]))
+
+					continue
+				}
+
+				assert.Equal(t, values, s.Size())
+
+				got := s.Values()
+				if assert.Len(t, got, values) {
+					assert.Equal(t, got[0], got[values-1]-values+1, "mixed elements")
+				}
+			}
+		}(g)
+	}
+
+	wg.Wait()
+
+	assert.True(t, s.Equal(first) || s.Equal(second))
+}
+
+// TestStressCrossSet checks sets against each other, in both directions, while
+// they change. Holding the lock of both sets would deadlock.
+func TestStressCrossSet(t *testing.T) {
+	const values = 50
+
+	a, b := New[int](), New[int]()
+
+	for i := 0; i < values; i++ {
+		a.Add(i)
+		b.Add(i)
+	}
+
+	n := stressGoroutines()
+
+	var wg sync.WaitGroup
+
+	for g := 0; g < n; g++ {
+		wg.Add(1)
+
+		go func(g int) {
+			defer wg.Done()
+
+			this, other := a, b
+			if g%2 == 0 {
+				this, other = b, a
+			}
+
+			for i := 0; i < 20; i++ {
+				v := values + (g+i)%values
+
+				this.Add(v)
+				_ = this.Subset(other)
+				_ = this.IsDisjoint(other)
+				_ = this.Intersection(other, this)
+				_ = this.Difference(other)
+				this.Remove(v)
+			}
+		}(g)
+	}
+
+	wg.Wait()
+
+	assert.True(t, a.Equal(b))
+}
@@ -0,0 +1,115 @@
+package safeslice
+
+import (
+	"errors"
+
+	"github.com/thalesfsp/go-common-types/shared"
+)
+
+//////
+// Const, vars, and types.
+//////
+
+// EvictionPolicy determines what happens when adding to a full bounded slice.
+type EvictionPolicy int
+
+// Eviction policies.
+const (
+	// DropOldest removes the first element to make room for the new one.
+	DropOldest EvictionPolicy = iota
+
+	// DropNewest silently discards the new element.
+	DropNewest
+
+	// Reject discards the new element, TryAdd returns ErrFull.
+	Reject
+)
+
+// ErrFull is returned by TryAdd when a bounded slice with the Reject policy is
+// full.
+var ErrFull = errors.New("slice is full")
+
+//////
+// Methods.
+//////
+
+// push appends the element, applying the eviction policy if the slice is
+// bounded, and full. Caller must hold the write lock.
+func (s *SafeSlice[T]) push(item T) error {
+	if s.max > 0 && s.data.len() >= s.max {
+		switch s.policy {
+		case DropOldest:
+			s.watchers.Publish(Event[T]{Op: shared.OpDelete, Index: 0, Old: s.data.remove(0)})
+
+			s.metrics.Operation("evict")
+			s.metrics.Evicted(shared.EvictCapacity)
+		case DropNewest:
+			s.metrics.Operation("drop")
+
+			return nil
+		default:
+			s.metrics.Operation("reject")
+
+			return ErrFull
+		}
+	}
+
+	s.data.push(item)
+
+	s.watchers.Publish(Event[T]{Op: shared.OpAdd, Index: s.data.len() - 1, New: item})
+
+	return nil
+}
+
+// bound trims the data to the maximum size, if the slice is bounded. With
+// DropOldest the last elements are kept, otherwise the first ones.
+func (s *SafeSlice[T]) bound(data []T) []T {
+	if s.max <= 0 || len(data) <= s.max {
+		return data
+	}
+
+	if s.policy == DropOldest {
+		return data[len(data)-s.max:]
+	}
+
+	return data[:s.max]
+}
+
+// TryAdd appends a new element to the end of the slice. It returns ErrFull if
+// the slice is bounded, full, and its policy is Reject.
+func (s *SafeSlice[T]) TryAdd(item T) error {
+	s.lock()
+	defer s.Unlock()
+
+	if err := s.push(item); err != nil {
+		return err
+	}
+
+	s.metrics.Operation("add")
+	s.metrics.SetSize(s.data.len())
+
+	return nil
+}
+
+// Bound returns the maximum number of elements, and the eviction policy of the
+// slice. The maximum is 0 if the slice is unbounded.
+func (s *SafeSlice[T]) Bound() (int, EvictionPolicy) {
+	return s.max, s.policy
+}
+
+//////
+// Factory.
+//////
+
+// NewBounded creates a new, empty, Safe Slice holding at most maxSize
+// elements. Adding to a full slice applies the policy. Replacing the whole
+// slice, e.g.: UnmarshalJSON, keeps the last maxSize elements with DropOldest,
+// otherwise the first ones. A maxSize <= 0 means unbounded.
+func NewBounded[T comparable](maxSize int, policy EvictionPolicy, opts ...Option[T]) *SafeSlice[T] {
+	s := NewWithOptions(opts...)
+
+	s.max = maxSize
+	s.policy = policy
+
+	return s
+}
@@ -0,0 +1,91 @@
+package safeslice
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/thalesfsp/go-common-types/metrics"
+	"github.com/thalesfsp/go-common-types/shared"
+)
+
+func TestNewBounded(t *testing.T) {
+	t.Run("DropOldest", func(t *testing.T) {
+		m := metrics.New("bounded")
+
+		s := NewBounded[int](3, DropOldest, WithMetrics[int](m))
+		s.Add(1).Add(2).Add(3).Add(4)
+		s.Append(5, 6)
+
+		assert.Equal(t, []int{4, 5, 6}, s.Values())
+		assert.NoError(t, s.TryAdd(7))
+		assert.Equal(t, []int{5, 6, 7}, s.Values())
+		assert.Equal(t, map[string]uint64{shared.EvictCapacity: 4}, m.Snapshot().Evictions)
+	})
+
+	t.Run("DropNewest", func(t *testing.T) {
+		s := NewBounded[int](3, DropNewest)
+		s.Append(1, 2, 3, 4)
+
+		assert.NoError(t, s.TryAdd(5))
+		assert.Equal(t, []int{1, 2, 3}, s.Values())
+	})
+
+	t.Run("Reject", func(t *testing.T) {
+		s := NewBounded[int](2, Reject)
+
+		assert.NoError(t, s.TryAdd(1))
+		assert.NoError(t, s.TryAdd(2))
+		assert.ErrorIs(t, s.TryAdd(3), ErrFull)
+
+		s.Add(4)
+
+		assert.Equal(t, []int{1, 2}, s.Values())
+
+		s.Delete(0)
+
+		assert.NoError(t, s.TryAdd(3))
+		assert.Equal(t, []int{2, 3}, s.Values())
+	})
+
+	t.Run("Unbounded", func(t *testing.T) {
+		s := NewBounded[int](0, Reject)
+		s.Append(1, 2, 3)
+
+		assert.NoError(t, s.TryAdd(4))
+		assert.Equal(t, 4, s.Size())
+
+		size, _ := s.Bound()
+		assert.Equal(t, 0, size)
+	})
+
+	t.Run("Replace", func(t *testing.T) {
+		oldest := NewBounded[int](2, DropOldest)
+		assert.NoError(t, oldest.UnmarshalJSON([]byte("[1,2,3]")))
+		assert.Equal(t, []int{2, 3}, oldest.Values())
+
+		rejecting := NewBounded[int](2, Reject)
+		assert.NoError(t, rejecting.UnmarshalJSON([]byte("[1,2,3]")))
+		assert.Equal(t, []int{1, 2}, rejecting.Values())
+	})
+
+	t.Run("Concurrent", func(t *testing.T) {
+		s := NewBounded[int](10, DropOldest)
+
+		var wg sync.WaitGroup
+
+		for i := 0; i < 100; i++ {
+			wg.Add(1)
+
+			go func(i int) {
+				defer wg.Done()
+
+				s.Add(i)
+			}(i)
+		}
+
+		wg.Wait()
+
+		assert.Equal(t, 10, s.Size())
+	})
+}
@@ -0,0 +1,66 @@
+package safeslice
+
+import (
+	"github.com/thalesfsp/go-common-types/safeorderedmap"
+	"github.com/thalesfsp/go-common-types/safeset"
+)
+
+// NOTE: The converters live here, as safeset depends on safeorderedmap, so
+// neither can depend on safeslice without an import cycle.
+
+//////
+// Exported functionalities.
+//////
+
+// ToSet returns a set of the elements of the slice, in order, without
+// duplicates, configured with the given options.
+func ToSet[T comparable](s *SafeSlice[T], opts ...safeset.Option[T]) *safeset.SafeSet[T] {
+	set := safeset.NewWithOptions(opts...)
+
+	for _, item := range s.Values() {
+		set.Add(item)
+	}
+
+	return set
+}
+
+// ToOrderedMap returns an ordered map of the elements of the slice, in order,
+// keyed by keyFn, configured with the given options. Elements with the same
+// key overwrite the previous one, keeping its position.
+func ToOrderedMap[T comparable](
+	s *SafeSlice[T],
+	keyFn func(item T) string,
+	opts ...safeorderedmap.Option[T],
+) *safeorderedmap.SafeOrderedMap[T] {
+	m := safeorderedmap.New(opts...)
+
+	for _, item := range s.Values() {
+		m.Add(keyFn(item), item)
+	}
+
+	return m
+}
+
+//////
+// Factory.
+//////
+
+// FromSet creates a slice with the elements of the set, in order, configured
+// with the given options.
+func FromSet[T comparable](set *safeset.SafeSet[T], opts ...Option[T]) *SafeSlice[T] {
+	s := NewWithOptions(opts...)
+
+	s.Append(set.Values()...)
+
+	return s
+}
+
+// FromOrderedMap creates a slice with the values of the ordered map, in
+// order, configured with the given options.
+func FromOrderedMap[T comparable](m *safeorderedmap.SafeOrderedMap[T], opts ...Option[T]) *SafeSlice[T] {
+	s := NewWithOptions(opts...)
+
+	s.Append(m.Values()...)
+
+	return s
+}
@@ -0,0 +1,45 @@
+package safeslice
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/thalesfsp/go-common-types/safeorderedmap"
+	"github.com/thalesfsp/go-common-types/safeset"
+)
+
+func TestToSet(t *testing.T) {
+	set := ToSet(New("b", "a", "b", "c"))
+
+	assert.Equal(t, []string{"b", "a", "c"}, set.Values())
+
+	folded := ToSet(New("Accept", "accept"), safeset.WithCaseInsensitive())
+
+	assert.Equal(t, []string{"Accept"}, folded.Values())
+}
+
+func TestToOrderedMap(t *testing.T) {
+	type user struct {
+		ID   string
+		Name string
+	}
+
+	m := ToOrderedMap(New(user{"1", "bob"}, user{"2", "alice"}, user{"1", "robert"}), func(u user) string {
+		return u.ID
+	})
+
+	assert.Equal(t, []string{"1", "2"}, m.Keys())
+	assert.Equal(t, []user{{"1", "robert"}, {"2", "alice"}}, m.Values())
+}
+
+func TestFromSet(t *testing.T) {
+	s := FromSet(safeset.New(3, 1, 2))
+
+	assert.Equal(t, []int{3, 1, 2}, s.Values())
+}
+
+func TestFromOrderedMap(t *testing.T) {
+	s := FromOrderedMap(safeorderedmap.New[int]().Add("b", 2).Add("a", 1))
+
+	assert.Equal(t, []int{2, 1}, s.Values())
+}
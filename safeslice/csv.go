@@ -0,0 +1,92 @@
+package safeslice
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+)
+
+//////
+// Const, vars, and types.
+//////
+
+// ErrSkipRow can be returned by the decode function of FromCSV to skip a row,
+// e.g.: the header.
+var ErrSkipRow = errors.New("skip row")
+
+//////
+// Methods.
+//////
+
+// ToCSV writes the slice to w as CSV, one row per element, formatted by row.
+// If header isn't nil, it's called with the first element, or the zero value
+// if the slice is empty, and written first. Elements are copied first, so the
+// lock isn't held while writing.
+func (s *SafeSlice[T]) ToCSV(w io.Writer, header func(T) []string, row func(T) []string) error {
+	items := s.Values()
+
+	cw := csv.NewWriter(w)
+
+	if header != nil {
+		var first T
+
+		if len(items) > 0 {
+			first = items[0]
+		}
+
+		if err := cw.Write(header(first)); err != nil {
+			return err
+		}
+	}
+
+	for _, item := range items {
+		if err := cw.Write(row(item)); err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+
+	return cw.Error()
+}
+
+//////
+// Factory.
+//////
+
+// FromCSV creates a slice from the CSV rows read from r, one at a time,
+// decoded by decode. Rows for which decode returns ErrSkipRow, e.g.: the
+// header, are skipped. Other errors are returned with the row number.
+func FromCSV[T comparable](r io.Reader, decode func(record []string) (T, error), opts ...Option[T]) (*SafeSlice[T], error) {
+	s := NewWithOptions(opts...)
+
+	cr := csv.NewReader(r)
+
+	// Rows may have a different number of fields, decode decides.
+	cr.FieldsPerRecord = -1
+
+	for n := 1; ; n++ {
+		record, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			return s, nil
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		item, err := decode(record)
+		if errors.Is(err, ErrSkipRow) {
+			continue
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("row %d: %w", n, err)
+		}
+
+		if err := s.TryAdd(item); err != nil {
+			return nil, fmt.Errorf("row %d: %w", n, err)
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package safeslice
+
+import (
+	"bytes"
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type person struct {
+	Name string
+	Age  int
+}
+
+func TestCSV(t *testing.T) {
+	s := New(person{"Ann", 30}, person{"Bob, Jr.", 25})
+
+	var buf bytes.Buffer
+
+	err := s.ToCSV(&buf, func(person) []string {
+		return []string{"name", "age"}
+	}, func(p person) []string {
+		return []string{p.Name, strconv.Itoa(p.Age)}
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "name,age\nAnn,30\n\"Bob, Jr.\",25\n", buf.String())
+
+	decoded, err := FromCSV(&buf, func(record []string) (person, error) {
+		if record[0] == "name" {
+			return person{}, ErrSkipRow
+		}
+
+		age, err := strconv.Atoi(record[1])
+
+		return person{Name: record[0], Age: age}, err
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, s.Values(), decoded.Values())
+}
+
+func TestCSVErrors(t *testing.T) {
+	_, err := FromCSV(strings.NewReader("a\nb\n"), func(record []string) (string, error) {
+		if record[0] == "b" {
+			return "", errors.New("bad")
+		}
+
+		return record[0], nil
+	})
+	assert.EqualError(t, err, "row 2: bad")
+
+	_, err = FromCSV(strings.NewReader("a\n\"b\n"), func(record []string) (string, error) {
+		return record[0], nil
+	})
+	assert.Error(t, err)
+
+	_, err = FromCSV(strings.NewReader("a\nb\n"), func(record []string) (string, error) {
+		return record[0], nil
+	}, func(s *SafeSlice[string]) {
+		s.max, s.policy = 1, Reject
+	})
+	assert.ErrorIs(t, err, ErrFull)
+
+	var buf bytes.Buffer
+
+	assert.NoError(t, New[int]().ToCSV(&buf, nil, func(i int) []string { return []string{strconv.Itoa(i)} }))
+	assert.Empty(t, buf.String())
+}
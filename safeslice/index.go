@@ -0,0 +1,33 @@
+package safeslice
+
+import "github.com/thalesfsp/go-common-types/safeorderedmap"
+
+//////
+// Factory.
+//////
+
+// IndexBy creates an ordered map of slices, grouping the items by the key
+// returned by keyFn, e.g.: a lookup table of API results by owner. Keys are
+// in the order of their first item, and items keep their order.
+func IndexBy[T comparable](
+	items []T,
+	keyFn func(item T) string,
+	opts ...safeorderedmap.Option[*SafeSlice[T]],
+) *safeorderedmap.SafeOrderedMap[*SafeSlice[T]] {
+	index := safeorderedmap.New(opts...)
+
+	for _, item := range items {
+		key := keyFn(item)
+
+		group, ok := index.Get(key)
+		if !ok {
+			group = New[T]()
+
+			index.Add(key, group)
+		}
+
+		group.Append(item)
+	}
+
+	return index
+}
@@ -0,0 +1,23 @@
+package safeslice
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIndexBy(t *testing.T) {
+	type repo struct {
+		Owner string
+		Name  string
+	}
+
+	index := IndexBy([]repo{{"a", "x"}, {"b", "y"}, {"a", "z"}}, func(r repo) string { return r.Owner })
+
+	assert.Equal(t, []string{"a", "b"}, index.Keys())
+
+	a, ok := index.Get("a")
+
+	assert.True(t, ok)
+	assert.Equal(t, []repo{{"a", "x"}, {"a", "z"}}, a.Values())
+}
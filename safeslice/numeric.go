@@ -0,0 +1,255 @@
+package safeslice
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+
+	"github.com/thalesfsp/go-common-types/statistical"
+)
+
+//////
+// Const, vars, and types.
+//////
+
+// ErrOverflow is returned by Numeric.Sum if the sum of integers doesn't fit
+// in their type.
+var ErrOverflow = errors.New("sum overflows")
+
+// Numeric is a SafeSlice of numbers maintaining its statistics as it
+// changes, for hot read paths: Sum, and Mean are updated incrementally by
+// every mutation, in O(1), and Min, and Max are cached, recomputed only when
+// the cached value is removed. Integers are summed as int64, whatever their
+// type, so small types don't wrap, and floats as float64.
+//
+// NOTE: With floats, the incremental sum accumulates rounding errors, which
+// a sum over a snapshot, e.g.: the package-level Sum, doesn't.
+type Numeric[T statistical.Numbers] struct {
+	*SafeSlice[T]
+
+	stats *numericStats[T]
+}
+
+// numericStats are the statistics of a Numeric slice. The sums are updated
+// holding the write lock of the slice, and read holding its read lock. The
+// cached bounds are guarded by mu, as they're computed by readers.
+type numericStats[T statistical.Numbers] struct {
+	// sum is the sum of integers, wrapping around, and wraps the number of
+	// times it did, upwards, minus downwards, so it's exact when 0.
+	sum   int64
+	wraps int64
+
+	// total is the sum as float64, of floats, and for Mean.
+	total float64
+
+	mu       sync.Mutex
+	min, max T
+	bounded  bool
+}
+
+//////
+// Tracker.
+//////
+
+// add implements the tracker interface.
+func (st *numericStats[T]) add(item T) {
+	st.accumulate(int64(item), 1)
+	st.total += float64(item)
+
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	if st.bounded {
+		if item < st.min {
+			st.min = item
+		}
+
+		if item > st.max {
+			st.max = item
+		}
+	}
+}
+
+// remove implements the tracker interface.
+func (st *numericStats[T]) remove(item T) {
+	st.accumulate(int64(item), -1)
+	st.total -= float64(item)
+
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	if item == st.min || item == st.max {
+		st.bounded = false
+	}
+}
+
+// reset implements the tracker interface.
+func (st *numericStats[T]) reset(items []T) {
+	st.sum, st.wraps, st.total = 0, 0, 0
+
+	for _, item := range items {
+		st.accumulate(int64(item), 1)
+		st.total += float64(item)
+	}
+
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	st.bounded = false
+}
+
+// accumulate adds the integer to the sum, or subtracts it if sign is -1,
+// counting the wraps. It's a no-op for floats, which are summed by total.
+func (st *numericStats[T]) accumulate(item int64, sign int64) {
+	if isFloat[T]() {
+		return
+	}
+
+	if sign < 0 {
+		// -math.MinInt64 wraps to itself, 2^64 below its value.
+		if item == math.MinInt64 {
+			st.wraps++
+		}
+
+		item = -item
+	}
+
+	sum := st.sum + item
+
+	switch {
+	case item > 0 && sum < st.sum:
+		st.wraps++
+	case item < 0 && sum > st.sum:
+		st.wraps--
+	}
+
+	st.sum = sum
+}
+
+//////
+// Methods.
+//////
+
+// bounds returns the smallest, and largest elements, recomputing them if the
+// cached ones were removed. Caller must hold the read lock of the slice.
+func (n *Numeric[T]) bounds() (T, T, bool) {
+	st := n.stats
+
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	if n.data.len() == 0 {
+		return *new(T), *new(T), false
+	}
+
+	if !st.bounded {
+		st.min, st.max = n.data.first(), n.data.first()
+
+		for c := n.data.cursor(); c.next(); {
+			if item := c.value(); item < st.min {
+				st.min = item
+			} else if item > st.max {
+				st.max = item
+			}
+		}
+
+		st.bounded = true
+	}
+
+	return st.min, st.max, true
+}
+
+// Sum returns the sum of the elements, 0 if the slice is empty. For integers,
+// it fails with ErrOverflow if the sum doesn't fit in their type, e.g.: 100 +
+// 100 in an int8 slice.
+func (n *Numeric[T]) Sum() (T, error) {
+	n.rlock()
+	defer n.RUnlock()
+
+	st := n.stats
+
+	if isFloat[T]() {
+		return T(st.total), nil
+	}
+
+	if st.wraps != 0 || int64(T(st.sum)) != st.sum {
+		return *new(T), ErrOverflow
+	}
+
+	return T(st.sum), nil
+}
+
+// Mean returns the mean of the elements, accumulated as float64, so large
+// integers don't overflow. It fails if the slice is empty.
+func (n *Numeric[T]) Mean() (float64, error) {
+	n.rlock()
+	defer n.RUnlock()
+
+	if n.data.len() == 0 {
+		return 0, fmt.Errorf("cannot calculate mean of empty slice")
+	}
+
+	return n.stats.total / float64(n.data.len()), nil
+}
+
+// Min returns the smallest element, or false if the slice is empty.
+func (n *Numeric[T]) Min() (T, bool) {
+	n.rlock()
+	defer n.RUnlock()
+
+	minimum, _, ok := n.bounds()
+
+	return minimum, ok
+}
+
+// Max returns the largest element, or false if the slice is empty.
+func (n *Numeric[T]) Max() (T, bool) {
+	n.rlock()
+	defer n.RUnlock()
+
+	_, maximum, ok := n.bounds()
+
+	return maximum, ok
+}
+
+//////
+// Helpers.
+//////
+
+// isFloat checks if T is a floating-point type.
+func isFloat[T statistical.Numbers]() bool {
+	half := T(1)
+
+	half /= 2
+
+	return half != 0
+}
+
+//////
+// Factory.
+//////
+
+// NewNumeric creates a new Numeric slice with the given elements.
+func NewNumeric[T statistical.Numbers](v ...T) *Numeric[T] {
+	n := NewNumericWithOptions[T]()
+
+	n.Append(v...)
+
+	return n
+}
+
+// NewNumericWithOptions creates a new, empty, Numeric slice configured with
+// the given options, e.g.: WithSlabs.
+func NewNumericWithOptions[T statistical.Numbers](opts ...Option[T]) *Numeric[T] {
+	n := &Numeric[T]{
+		SafeSlice: NewWithOptions(opts...),
+		stats:     &numericStats[T]{},
+	}
+
+	n.data.tracker = n.stats
+
+	n.stats.reset(n.data.values())
+
+	return n
+}
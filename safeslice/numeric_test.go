@@ -0,0 +1,126 @@
+package safeslice
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/thalesfsp/go-common-types/shared"
+	"github.com/thalesfsp/go-common-types/statistical"
+)
+
+// sum returns the sum of the slice, failing the test on error.
+func sum[T statistical.Numbers](t *testing.T, n *Numeric[T]) T {
+	t.Helper()
+
+	result, err := n.Sum()
+	assert.NoError(t, err)
+
+	return result
+}
+
+func TestNumeric(t *testing.T) {
+	n := NewNumeric(3, 1, 4)
+
+	assert.Equal(t, 8, sum(t, n))
+
+	minimum, ok := n.Min()
+	assert.True(t, ok)
+	assert.Equal(t, 1, minimum)
+
+	maximum, _ := n.Max()
+	assert.Equal(t, 4, maximum)
+
+	n.Append(10)
+	n.SortedInsert(-2, func(a, b int) int { return a - b })
+
+	assert.Equal(t, 16, sum(t, n))
+
+	maximum, _ = n.Max()
+	assert.Equal(t, 10, maximum)
+
+	// Removing the cached minimum recomputes it.
+	n.Delete(0)
+
+	minimum, _ = n.Min()
+	assert.Equal(t, 1, minimum)
+
+	assert.Equal(t, 1, n.RemoveFunc(func(item int) bool { return item == 10 }))
+
+	maximum, _ = n.Max()
+	assert.Equal(t, 4, maximum)
+
+	mean, err := n.Mean()
+	assert.NoError(t, err)
+	assert.Equal(t, 8.0/3, mean)
+
+	n.SortWith(shared.CompareOrdered[int]())
+	assert.Equal(t, 8, sum(t, n))
+
+	assert.NoError(t, n.UnmarshalJSON([]byte(`[5, 7]`)))
+	assert.Equal(t, 12, sum(t, n))
+
+	minimum, _ = n.Min()
+	assert.Equal(t, 5, minimum)
+
+	assert.NoError(t, n.UnmarshalJSON([]byte(`[]`)))
+
+	assert.Equal(t, 0, sum(t, n))
+
+	_, ok = n.Min()
+	assert.False(t, ok)
+
+	_, err = n.Mean()
+	assert.Error(t, err)
+}
+
+func TestNumericWithSlabs(t *testing.T) {
+	n := NewNumericWithOptions(WithSlabs[float64](2))
+
+	n.Append(1.5, 2.5, 3, 4)
+	n.Delete(1)
+
+	assert.Equal(t, 8.5, sum(t, n))
+
+	n.RotateLeft(1)
+
+	assert.Equal(t, 8.5, sum(t, n))
+
+	maximum, _ := n.Max()
+	assert.Equal(t, 4.0, maximum)
+}
+
+func TestNumericOverflow(t *testing.T) {
+	small := NewNumeric[int8](100, 100)
+
+	// The sum doesn't wrap, it fails.
+	_, err := small.Sum()
+	assert.ErrorIs(t, err, ErrOverflow)
+
+	mean, err := small.Mean()
+	assert.NoError(t, err)
+	assert.Equal(t, 100.0, mean)
+
+	// It's exact again once it fits.
+	small.Append(-100)
+	assert.Equal(t, int8(100), sum(t, small))
+
+	large := NewNumeric[int64](math.MaxInt64, 1)
+
+	_, err = large.Sum()
+	assert.ErrorIs(t, err, ErrOverflow)
+
+	large.Append(math.MinInt64)
+	assert.Equal(t, int64(0), sum(t, large))
+
+	// Removing the smallest int64 adds 2^63.
+	large.RemoveFunc(func(item int64) bool { return item == math.MinInt64 })
+
+	_, err = large.Sum()
+	assert.ErrorIs(t, err, ErrOverflow)
+
+	large.RemoveFunc(func(item int64) bool { return item == 1 })
+	assert.Equal(t, int64(math.MaxInt64), sum(t, large))
+
+	assert.Equal(t, float32(3.5), sum(t, NewNumeric[float32](1.25, 2.25)))
+}
@@ -0,0 +1,129 @@
+package safeslice
+
+//////
+// Const, vars, and types.
+//////
+
+// ReadOnly is a read-only view of a SafeSlice, see SafeSlice.ReadOnly.
+type ReadOnly[T comparable] interface {
+	// Get returns the element at the given index.
+	Get(index int) T
+
+	// First returns the first element.
+	First() (T, bool)
+
+	// Last returns the last element.
+	Last() (T, bool)
+
+	// Values returns a copy of the elements.
+	Values() []T
+
+	// Contains checks if the element is present.
+	Contains(item T) bool
+
+	// Index returns the index of the element.
+	Index(element T) (int, bool)
+
+	// Size returns the number of elements.
+	Size() int
+
+	// Empty checks if there are no elements.
+	Empty() bool
+
+	// Each calls f for each element, in order.
+	Each(f func(T))
+
+	// Find returns the first element satisfying the predicate.
+	Find(predicate func(T) bool) T
+
+	// FindIndex returns the index of the first element satisfying the
+	// predicate.
+	FindIndex(predicate func(T) bool) int
+
+	// Any checks if at least one element satisfies the predicate.
+	Any(predicate func(T) bool) bool
+
+	// All checks if all elements satisfy the predicate.
+	All(predicate func(T) bool) bool
+
+	// Count returns the number of elements satisfying the predicate.
+	Count(predicate func(T) bool) int
+
+	// Clone returns a copy of the slice, which can be modified.
+	Clone() *SafeSlice[T]
+
+	// String returns the string representation of the slice.
+	String() string
+
+	// MarshalJSON marshals the slice to JSON.
+	MarshalJSON() ([]byte, error)
+}
+
+// readOnly implements ReadOnly. The slice isn't exported, so the view can't be
+// converted back.
+type readOnly[T comparable] struct {
+	s *SafeSlice[T]
+}
+
+//////
+// Methods.
+//////
+
+// Get implements ReadOnly.
+func (v readOnly[T]) Get(index int) T { return v.s.Get(index) }
+
+// First implements ReadOnly.
+func (v readOnly[T]) First() (T, bool) { return v.s.First() }
+
+// Last implements ReadOnly.
+func (v readOnly[T]) Last() (T, bool) { return v.s.Last() }
+
+// Values implements ReadOnly.
+func (v readOnly[T]) Values() []T { return v.s.Values() }
+
+// Contains implements ReadOnly.
+func (v readOnly[T]) Contains(item T) bool { return v.s.Contains(item) }
+
+// Index implements ReadOnly.
+func (v readOnly[T]) Index(element T) (int, bool) { return v.s.Index(element) }
+
+// Size implements ReadOnly.
+func (v readOnly[T]) Size() int { return v.s.Size() }
+
+// Empty implements ReadOnly.
+func (v readOnly[T]) Empty() bool { return v.s.Empty() }
+
+// Each implements ReadOnly.
+func (v readOnly[T]) Each(f func(T)) { v.s.Each(f) }
+
+// Find implements ReadOnly.
+func (v readOnly[T]) Find(predicate func(T) bool) T { return v.s.Find(predicate) }
+
+// FindIndex implements ReadOnly.
+func (v readOnly[T]) FindIndex(predicate func(T) bool) int { return v.s.FindIndex(predicate) }
+
+// Any implements ReadOnly.
+func (v readOnly[T]) Any(predicate func(T) bool) bool { return v.s.Any(predicate) }
+
+// All implements ReadOnly.
+func (v readOnly[T]) All(predicate func(T) bool) bool { return v.s.All(predicate) }
+
+// Count implements ReadOnly.
+func (v readOnly[T]) Count(predicate func(T) bool) int { return v.s.Count(predicate) }
+
+// Clone implements ReadOnly.
+func (v readOnly[T]) Clone() *SafeSlice[T] { return v.s.Clone() }
+
+// String implements ReadOnly.
+func (v readOnly[T]) String() string { return v.s.String() }
+
+// MarshalJSON implements ReadOnly.
+func (v readOnly[T]) MarshalJSON() ([]byte, error) { return v.s.MarshalJSON() }
+
+// ReadOnly returns a read-only view of the slice, e.g.: to hand it out without
+// copying it. The view isn't a snapshot: it reflects later changes to the
+// slice. It can't be converted back to the slice, use Clone to get a copy
+// which can be modified.
+func (s *SafeSlice[T]) ReadOnly() ReadOnly[T] {
+	return readOnly[T]{s: s}
+}
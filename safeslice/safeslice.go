@@ -3,115 +3,525 @@
 package safeslice
 
 import (
+	"context"
+	"database/sql/driver"
 	"encoding/json"
 	"fmt"
+	"hash"
+	"io"
+	"math"
+	"math/rand"
+	"sort"
+	"strings"
 	"sync"
+	"text/tabwriter"
+	"time"
+
+	"github.com/thalesfsp/go-common-types/internal/bsonjson"
+	"github.com/thalesfsp/go-common-types/internal/watch"
+	"github.com/thalesfsp/go-common-types/metrics"
+	"github.com/thalesfsp/go-common-types/safepool"
+	"github.com/thalesfsp/go-common-types/shared"
+	"github.com/thalesfsp/go-common-types/statistical"
+	"github.com/thalesfsp/go-common-types/tuple"
 )
 
 //////
 // Const, vars, and types.
 //////
 
+// Option allows to configure a SafeSlice.
+type Option[T comparable] func(s *SafeSlice[T])
+
 // SafeSlice is a slice that is safe for concurrent use powered by generics.
+//
+// The zero value is an empty slice, ready to use, e.g.: as a struct field
+// filled by UnmarshalJSON.
 type SafeSlice[T comparable] struct {
+	// data is first, so its length is 64-bit aligned for atomic operations.
+	data store[T]
+
 	sync.RWMutex
 
-	data []T
+	metrics *metrics.Metrics
+
+	watchers watch.Hub[Event[T]]
+
+	max    int
+	policy EvictionPolicy
+
+	sizer shared.Sizer[T]
 }
 
 //////
 // Methods.
 //////
 
+// lock acquires the write lock, recording the wait time if metrics are
+// enabled.
+func (s *SafeSlice[T]) lock() {
+	if s.metrics == nil {
+		s.Lock()
+
+		return
+	}
+
+	start := time.Now()
+
+	s.Lock()
+
+	s.metrics.ObserveLockWait(time.Since(start))
+}
+
+// rlock acquires the read lock, recording the wait time if metrics are
+// enabled.
+func (s *SafeSlice[T]) rlock() {
+	if s.metrics == nil {
+		s.RLock()
+
+		return
+	}
+
+	start := time.Now()
+
+	s.RLock()
+
+	s.metrics.ObserveLockWait(time.Since(start))
+}
+
+// replace replaces the content of the slice. Caller must hold the write lock.
+func (s *SafeSlice[T]) replace(data []T) {
+	data = s.bound(data)
+
+	s.data.reset(data)
+
+	if !s.watchers.Active() {
+		return
+	}
+
+	s.watchers.Publish(Event[T]{Op: shared.OpClear})
+
+	for i, item := range data {
+		s.watchers.Publish(Event[T]{Op: shared.OpAdd, Index: i, New: item})
+	}
+}
+
+// Metrics returns the metrics of the slice, nil if not enabled.
+func (s *SafeSlice[T]) Metrics() *metrics.Metrics {
+	return s.metrics
+}
+
 // String is the stringer implementation.
 func (s *SafeSlice[T]) String() string {
-	s.RLock()
+	s.rlock()
 	defer s.RUnlock()
 
-	return fmt.Sprintf("%v", s.data)
+	return fmt.Sprintf("%v", s.data.slice())
+}
+
+// PrettyString returns the indented JSON representation of the slice. It
+// falls back to String if the elements can't be marshalled.
+func (s *SafeSlice[T]) PrettyString() string {
+	s.rlock()
+	defer s.RUnlock()
+
+	data := s.data.slice()
+
+	b, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		return fmt.Sprintf("%v", data)
+	}
+
+	return string(b)
+}
+
+// Table writes the slice to w as an aligned table, one row per element, and
+// one column per column function. If no column function is given, the element
+// is printed with %v.
+func (s *SafeSlice[T]) Table(w io.Writer, columns ...func(T) string) error {
+	s.rlock()
+	defer s.RUnlock()
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	for c := s.data.cursor(); c.next(); {
+		item := c.value()
+		row := []string{}
+
+		if len(columns) == 0 {
+			row = append(row, fmt.Sprintf("%v", item))
+		}
+
+		for _, column := range columns {
+			row = append(row, column(item))
+		}
+
+		if _, err := fmt.Fprintln(tw, strings.Join(row, "\t")); err != nil {
+			return err
+		}
+	}
+
+	return tw.Flush()
 }
 
 //////
 // CRUD operations.
 
-// Add appends a new element to the end of the slice.
+// Add appends a new element to the end of the slice. If the slice is bounded,
+// and full, the eviction policy applies, see NewBounded.
 func (s *SafeSlice[T]) Add(item T) *SafeSlice[T] {
-	s.Lock()
+	s.lock()
 	defer s.Unlock()
 
-	s.data = append(s.data, item)
+	_ = s.push(item)
+
+	s.metrics.Operation("add")
+	s.metrics.SetSize(s.data.len())
 
 	return s
 }
 
+// Append appends the given elements to the end of the slice. If the slice is
+// bounded, the eviction policy applies to each element, see NewBounded.
+func (s *SafeSlice[T]) Append(items ...T) {
+	s.lock()
+	defer s.Unlock()
+
+	for _, item := range items {
+		_ = s.push(item)
+	}
+
+	s.metrics.Operation("add")
+	s.metrics.SetSize(s.data.len())
+}
+
 // Get retrieves an element from the slice at the specified index.
 func (s *SafeSlice[T]) Get(index int) T {
-	s.RLock()
+	s.rlock()
 	defer s.RUnlock()
 
-	if index < 0 || index >= len(s.data) {
+	s.metrics.Operation("get")
+
+	if index < 0 || index >= s.data.len() {
+		s.metrics.Miss()
+
 		return *new(T)
 	}
 
-	return s.data[index]
+	s.metrics.Hit()
+
+	return s.data.at(index)
 }
 
 // Delete removes an element from the slice at the specified index.
 func (s *SafeSlice[T]) Delete(index int) *SafeSlice[T] {
-	s.Lock()
+	s.lock()
+	defer s.Unlock()
+
+	if index < 0 || index >= s.data.len() {
+		return s
+	}
+
+	old := s.data.remove(index)
+
+	s.watchers.Publish(Event[T]{Op: shared.OpDelete, Index: index, Old: old})
+
+	s.metrics.Operation("delete")
+	s.metrics.SetSize(s.data.len())
+
+	return s
+}
+
+// removeFunc removes up to limit elements satisfying the predicate, all if
+// limit is negative, returning how many were removed. Delete events carry the
+// index at the time of each removal. Caller must hold the write lock.
+func (s *SafeSlice[T]) removeFunc(predicate func(T) bool, limit int) int {
+	removed, kept := 0, 0
+
+	s.data.filter(func(item T) bool {
+		if (limit < 0 || removed < limit) && predicate(item) {
+			s.watchers.Publish(Event[T]{Op: shared.OpDelete, Index: kept, Old: item})
+
+			removed++
+
+			return false
+		}
+
+		kept++
+
+		return true
+	})
+
+	if removed > 0 {
+		s.metrics.Operation("delete")
+		s.metrics.SetSize(s.data.len())
+	}
+
+	return removed
+}
+
+// Remove removes the first occurrence of the item, returning whether it was
+// present. Unlike Index, and Delete, it's atomic.
+func (s *SafeSlice[T]) Remove(item T) bool {
+	s.lock()
+	defer s.Unlock()
+
+	return s.removeFunc(func(v T) bool { return v == item }, 1) == 1
+}
+
+// RemoveAll removes all occurrences of the item, returning how many were
+// removed.
+func (s *SafeSlice[T]) RemoveAll(item T) int {
+	s.lock()
+	defer s.Unlock()
+
+	return s.removeFunc(func(v T) bool { return v == item }, -1)
+}
+
+// RemoveFunc removes all elements satisfying the predicate, in place, returning
+// how many were removed. The predicate is called holding the lock, so it must
+// not use the slice.
+func (s *SafeSlice[T]) RemoveFunc(predicate func(T) bool) int {
+	s.lock()
+	defer s.Unlock()
+
+	return s.removeFunc(predicate, -1)
+}
+
+// reorder calls f, which reorders the elements in place, publishing an update
+// event for every index whose element changed. Caller must hold the write
+// lock.
+func (s *SafeSlice[T]) reorder(f func(data []T)) {
+	if !s.watchers.Active() {
+		s.data.reorder(f)
+
+		return
+	}
+
+	before := s.data.values()
+
+	s.data.reorder(f)
+
+	for c := s.data.cursor(); c.next(); {
+		i, item := c.index, c.value()
+		if item != before[i] {
+			s.watchers.Publish(Event[T]{Op: shared.OpUpdate, Index: i, Old: before[i], New: item})
+		}
+	}
+}
+
+// Swap swaps the elements at the given indexes. Out of range indexes are
+// ignored.
+func (s *SafeSlice[T]) Swap(i, j int) *SafeSlice[T] {
+	s.lock()
+	defer s.Unlock()
+
+	if i < 0 || i >= s.data.len() || j < 0 || j >= s.data.len() {
+		return s
+	}
+
+	s.reorder(func(data []T) {
+		data[i], data[j] = data[j], data[i]
+	})
+
+	s.metrics.Operation("reorder")
+
+	return s
+}
+
+// Move moves the element at index from to index to, shifting the elements in
+// between. Out of range indexes are ignored.
+func (s *SafeSlice[T]) Move(from, to int) *SafeSlice[T] {
+	s.lock()
+	defer s.Unlock()
+
+	if from < 0 || from >= s.data.len() || to < 0 || to >= s.data.len() {
+		return s
+	}
+
+	s.reorder(func(data []T) {
+		item := data[from]
+
+		if from < to {
+			copy(data[from:to], data[from+1:to+1])
+		} else {
+			copy(data[to+1:from+1], data[to:from])
+		}
+
+		data[to] = item
+	})
+
+	s.metrics.Operation("reorder")
+
+	return s
+}
+
+// RotateLeft rotates the elements n positions to the left, e.g.: rotating
+// [1, 2, 3] by 1 results in [2, 3, 1]. A negative n rotates to the right.
+func (s *SafeSlice[T]) RotateLeft(n int) *SafeSlice[T] {
+	s.lock()
 	defer s.Unlock()
 
-	if index < 0 || index >= len(s.data) {
+	if s.data.len() == 0 {
 		return s
 	}
 
-	s.data = append(s.data[:index], s.data[index+1:]...)
+	n %= s.data.len()
+	if n < 0 {
+		n += s.data.len()
+	}
+
+	s.reorder(func(data []T) {
+		reverse(data[:n])
+		reverse(data[n:])
+		reverse(data)
+	})
+
+	s.metrics.Operation("reorder")
+
+	return s
+}
+
+// RotateRight rotates the elements n positions to the right, e.g.: rotating
+// [1, 2, 3] by 1 results in [3, 1, 2]. A negative n rotates to the left.
+func (s *SafeSlice[T]) RotateRight(n int) *SafeSlice[T] {
+	return s.RotateLeft(-n)
+}
+
+// SortWith sorts the elements in place, in the order defined by the comparer,
+// e.g.: shared.CompareOrdered, or shared.CompareBy. The sort is stable.
+func (s *SafeSlice[T]) SortWith(c shared.Comparer[T]) *SafeSlice[T] {
+	s.lock()
+	defer s.Unlock()
+
+	s.reorder(func(data []T) {
+		sort.SliceStable(data, func(i, j int) bool {
+			return c.Compare(data[i], data[j]) < 0
+		})
+	})
+
+	s.metrics.Operation("reorder")
 
 	return s
 }
 
+// BinarySearch searches the target in the slice, which must be sorted by cmp,
+// in O(log n). It returns the index of the first element equal to the
+// target, or where it would be inserted, and whether it was found. cmp
+// returns a negative number if a < b, 0 if equal, and a positive one if
+// a > b, like shared.Comparer.
+func (s *SafeSlice[T]) BinarySearch(target T, cmp func(a, b T) int) (int, bool) {
+	s.rlock()
+	defer s.RUnlock()
+
+	s.metrics.Operation("search")
+
+	i := sort.Search(s.data.len(), func(i int) bool {
+		return cmp(s.data.at(i), target) >= 0
+	})
+
+	found := i < s.data.len() && cmp(s.data.at(i), target) == 0
+
+	s.metrics.Lookup(found)
+
+	return i, found
+}
+
+// SortedInsert inserts the item after any equal element, in O(log n)
+// comparisons, keeping sorted the slice, which must be sorted by cmp. It
+// returns the index of the item, or -1 if it isn't inserted.
+//
+// A sorted slice doesn't keep track of the insertion order, so, if bounded,
+// and full, DropOldest keeps the largest elements, e.g.: a top N: the first,
+// smallest, element is evicted unless the item is less than it, in which case
+// the item is discarded. With the other policies, the item is discarded.
+func (s *SafeSlice[T]) SortedInsert(item T, cmp func(a, b T) int) int {
+	s.lock()
+	defer s.Unlock()
+
+	if s.max > 0 && s.data.len() >= s.max {
+		if s.policy != DropOldest {
+			s.metrics.Operation("reject")
+
+			return -1
+		}
+
+		if cmp(item, s.data.first()) < 0 {
+			s.metrics.Operation("drop")
+
+			return -1
+		}
+
+		s.watchers.Publish(Event[T]{Op: shared.OpDelete, Index: 0, Old: s.data.remove(0)})
+
+		s.metrics.Operation("evict")
+		s.metrics.Evicted(shared.EvictCapacity)
+	}
+
+	i := sort.Search(s.data.len(), func(i int) bool {
+		return cmp(s.data.at(i), item) > 0
+	})
+
+	s.data.insert(i, item)
+
+	s.watchers.Publish(Event[T]{Op: shared.OpAdd, Index: i, New: item})
+
+	s.metrics.Operation("add")
+	s.metrics.SetSize(s.data.len())
+
+	return i
+}
+
 // First return the first element.
 func (s *SafeSlice[T]) First() (T, bool) {
-	s.RLock()
+	s.rlock()
 	defer s.RUnlock()
 
-	if len(s.data) == 0 {
+	if s.data.len() == 0 {
 		return *new(T), false
 	}
 
-	return s.data[0], true
+	return s.data.first(), true
 }
 
 // Last return the last element.
 func (s *SafeSlice[T]) Last() (T, bool) {
-	s.RLock()
+	s.rlock()
 	defer s.RUnlock()
 
-	if len(s.data) == 0 {
+	if s.data.len() == 0 {
 		return *new(T), false
 	}
 
-	return s.data[len(s.data)-1], true
+	return s.data.last(), true
 }
 
-// ToSlice returns the underlying slice.
+// ToSlice returns the underlying slice. In slab mode, a copy.
 func (s *SafeSlice[T]) ToSlice() []T {
-	s.RLock()
+	s.rlock()
+	defer s.RUnlock()
+
+	return s.data.slice()
+}
+
+// Values returns a copy of the elements of the slice.
+func (s *SafeSlice[T]) Values() []T {
+	s.rlock()
 	defer s.RUnlock()
 
-	return s.data
+	return s.data.values()
 }
 
 // LastN return the last N elements as a new slice.
 func (s *SafeSlice[T]) LastN(n int) *SafeSlice[T] {
-	s.RLock()
+	s.rlock()
 	defer s.RUnlock()
 
-	if len(s.data) == 0 {
+	if s.data.len() == 0 {
 		return nil
 	}
 
-	sl := s.ToSlice()
+	sl := s.data.slice()
 	if n > len(sl) {
 		n = len(sl) // Return all elements if n is greater than the length of the slice
 	}
@@ -121,47 +531,96 @@ func (s *SafeSlice[T]) LastN(n int) *SafeSlice[T] {
 	return New[T](x...)
 }
 
+// Concat returns a new slice with the elements of the slice, followed by the
+// elements of the others, in order.
+func (s *SafeSlice[T]) Concat(others ...*SafeSlice[T]) *SafeSlice[T] {
+	result := s.Values()
+
+	for _, other := range others {
+		result = append(result, other.Values()...)
+	}
+
+	return New(result...)
+}
+
+// Interleave returns a new slice alternating the elements of the slice, and
+// the others, e.g.: interleaving [1, 2, 3] with [a, b] results in
+// [1, a, 2, b, 3]. Remaining elements of longer slices are appended in order.
+func (s *SafeSlice[T]) Interleave(others ...*SafeSlice[T]) *SafeSlice[T] {
+	sources := [][]T{s.Values()}
+	total := len(sources[0])
+
+	for _, other := range others {
+		values := other.Values()
+
+		sources = append(sources, values)
+		total += len(values)
+	}
+
+	result := make([]T, 0, total)
+
+	for i := 0; len(result) < total; i++ {
+		for _, source := range sources {
+			if i < len(source) {
+				result = append(result, source[i])
+			}
+		}
+	}
+
+	return New(result...)
+}
+
 //////
 // Meta operations.
 
 // Contains checks if the given element is present in the slice.
 func (s *SafeSlice[T]) Contains(item T) bool {
-	s.RLock()
+	s.rlock()
 	defer s.RUnlock()
 
-	for _, value := range s.data {
+	s.metrics.Operation("contains")
+
+	for c := s.data.cursor(); c.next(); {
+		value := c.value()
 		if value == item {
+			s.metrics.Hit()
+
 			return true
 		}
 	}
 
+	s.metrics.Miss()
+
 	return false
 }
 
-// Size returns the number of elements in the slice.
+// Size returns the number of elements in the slice. It doesn't acquire the
+// lock, the size is read atomically.
 func (s *SafeSlice[T]) Size() int {
-	s.RLock()
-	defer s.RUnlock()
-
-	return len(s.data)
+	return s.data.loadLen()
 }
 
-// Empty checks if the slice is empty.
+// Empty checks if the slice is empty. Like Size, it doesn't acquire the lock.
 func (s *SafeSlice[T]) Empty() bool {
-	s.RLock()
-	defer s.RUnlock()
+	return s.Size() == 0
+}
 
-	return len(s.data) == 0
+// Digest returns a stable hash of the elements, in order, by h, or sha256 if
+// nil, e.g.: for cheap equality checks, ETags, and cache keys, see
+// shared.DigestOrdered.
+func (s *SafeSlice[T]) Digest(h hash.Hash) (string, error) {
+	return shared.DigestOrdered(h, s.Values())
 }
 
 // Clone returns a new copy of the slice.
 func (s *SafeSlice[T]) Clone() *SafeSlice[T] {
-	s.RLock()
+	s.rlock()
 	defer s.RUnlock()
 
 	clone := New[T]()
 
-	for _, item := range s.data {
+	for c := s.data.cursor(); c.next(); {
+		item := c.value()
 		clone.Add(item)
 	}
 
@@ -171,28 +630,36 @@ func (s *SafeSlice[T]) Clone() *SafeSlice[T] {
 // Index returns the index of the first occurrence of the given element in the slice.
 // If the element is not found, it returns -1 and false.
 func (s *SafeSlice[T]) Index(element T) (int, bool) {
-	s.RLock()
+	s.rlock()
 	defer s.RUnlock()
 
-	for i, item := range s.data {
+	s.metrics.Operation("index")
+
+	for c := s.data.cursor(); c.next(); {
+		i, item := c.index, c.value()
 		if item == element {
+			s.metrics.Hit()
+
 			return i, true
 		}
 	}
 
+	s.metrics.Miss()
+
 	return -1, false
 }
 
 // Unique returns a new SafeSlice with all duplicates removed.
 func (s *SafeSlice[T]) Unique() *SafeSlice[T] {
-	s.RLock()
+	s.rlock()
 	defer s.RUnlock()
 
 	uniqueMap := make(map[T]bool)
 
 	uniqueSlice := New[T]()
 
-	for _, item := range s.data {
+	for c := s.data.cursor(); c.next(); {
+		item := c.value()
 		if _, ok := uniqueMap[item]; !ok {
 			uniqueMap[item] = true
 
@@ -203,15 +670,63 @@ func (s *SafeSlice[T]) Unique() *SafeSlice[T] {
 	return uniqueSlice
 }
 
+// UniqueBy returns a new SafeSlice with the elements having the same key
+// removed, keeping the first one, e.g.: to dedupe structs by ID.
+func (s *SafeSlice[T]) UniqueBy(key func(T) string) *SafeSlice[T] {
+	s.rlock()
+	defer s.RUnlock()
+
+	seen := make(map[string]bool)
+
+	uniqueSlice := New[T]()
+
+	for c := s.data.cursor(); c.next(); {
+		item := c.value()
+		k := key(item)
+
+		if !seen[k] {
+			seen[k] = true
+
+			uniqueSlice.Add(item)
+		}
+	}
+
+	return uniqueSlice
+}
+
+// CompactBy returns a new SafeSlice with the consecutive elements equal to the
+// previous one, according to eq, removed, like the uniq command. Sort the
+// slice first to remove all duplicates.
+func (s *SafeSlice[T]) CompactBy(eq func(a, b T) bool) *SafeSlice[T] {
+	s.rlock()
+	defer s.RUnlock()
+
+	compactSlice := New[T]()
+
+	var previous T
+
+	for c := s.data.cursor(); c.next(); {
+		item := c.value()
+		if c.index == 0 || !eq(previous, item) {
+			compactSlice.Add(item)
+		}
+
+		previous = item
+	}
+
+	return compactSlice
+}
+
 //////
 // Collection Operations (Higher-Order Functions).
 
 // All checks if all elements in the slice satisfy a given condition (predicate) and returns a boolean value.
 func (s *SafeSlice[T]) All(predicate func(T) bool) bool {
-	s.RLock()
+	s.rlock()
 	defer s.RUnlock()
 
-	for _, item := range s.data {
+	for c := s.data.cursor(); c.next(); {
+		item := c.value()
 		if !predicate(item) {
 			return false
 		}
@@ -222,26 +737,49 @@ func (s *SafeSlice[T]) All(predicate func(T) bool) bool {
 
 // Map applies a given function to all elements in the slice and creates a new slice containing the results.
 func (s *SafeSlice[T]) Map(mapper func(T) T) *SafeSlice[T] {
-	s.RLock()
+	s.rlock()
 	defer s.RUnlock()
 
 	result := New[T]()
 
-	for _, item := range s.data {
+	for c := s.data.cursor(); c.next(); {
+		item := c.value()
 		result.Add(mapper(item))
 	}
 
 	return result
 }
 
+// ParallelMap is like Map, but runs the mapper concurrently, with the given
+// number of workers, or GOMAXPROCS if not positive, keeping the order of the
+// elements. It operates on a snapshot of the slice, so the lock isn't held
+// while mapping. Errors are collected in an errs.Errors, see safepool.Map.
+func (s *SafeSlice[T]) ParallelMap(
+	ctx context.Context,
+	workers int,
+	mapper func(T) (T, error),
+) (*SafeSlice[T], error) {
+	results, err := safepool.Map(ctx, s.Values(), workers, mapper)
+
+	return New(results...), err
+}
+
+// ParallelEach is like Each, but runs f concurrently, with the given number
+// of workers, or GOMAXPROCS if not positive. It operates on a snapshot of the
+// slice. Errors are collected in an errs.Errors, see safepool.ForEach.
+func (s *SafeSlice[T]) ParallelEach(ctx context.Context, workers int, f func(T) error) error {
+	return safepool.ForEach(ctx, s.Values(), workers, f)
+}
+
 // Filter creates a new slice containing only the elements that satisfy a given condition (predicate).
 func (s *SafeSlice[T]) Filter(predicate func(T) bool) *SafeSlice[T] {
-	s.RLock()
+	s.rlock()
 	defer s.RUnlock()
 
 	result := New[T]()
 
-	for _, item := range s.data {
+	for c := s.data.cursor(); c.next(); {
+		item := c.value()
 		if predicate(item) {
 			result.Add(item)
 		}
@@ -252,10 +790,11 @@ func (s *SafeSlice[T]) Filter(predicate func(T) bool) *SafeSlice[T] {
 
 // Each iterates over the slice and calls the given function for each element.
 func (s *SafeSlice[T]) Each(f func(T)) *SafeSlice[T] {
-	s.RLock()
+	s.rlock()
 	defer s.RUnlock()
 
-	for _, item := range s.data {
+	for c := s.data.cursor(); c.next(); {
+		item := c.value()
 		f(item)
 	}
 
@@ -264,12 +803,13 @@ func (s *SafeSlice[T]) Each(f func(T)) *SafeSlice[T] {
 
 // Reduce applies a given function to all elements in the slice and returns a single result.
 func (s *SafeSlice[T]) Reduce(reducer func(T, T) T, initialValue T) T {
-	s.RLock()
+	s.rlock()
 	defer s.RUnlock()
 
 	result := initialValue
 
-	for _, item := range s.data {
+	for c := s.data.cursor(); c.next(); {
+		item := c.value()
 		result = reducer(result, item)
 	}
 
@@ -279,10 +819,11 @@ func (s *SafeSlice[T]) Reduce(reducer func(T, T) T, initialValue T) T {
 // Find returns the first element in the slice that satisfies the given predicate.
 // If no element satisfies the predicate, it returns the zero value of the type.
 func (s *SafeSlice[T]) Find(predicate func(T) bool) T {
-	s.RLock()
+	s.rlock()
 	defer s.RUnlock()
 
-	for _, item := range s.data {
+	for c := s.data.cursor(); c.next(); {
+		item := c.value()
 		if predicate(item) {
 			return item
 		}
@@ -291,29 +832,103 @@ func (s *SafeSlice[T]) Find(predicate func(T) bool) T {
 	return *new(T)
 }
 
-// Any checks if at least one element in the slice satisfies a given condition (predicate).
-func (s *SafeSlice[T]) Any(predicate func(T) bool) bool {
-	s.RLock()
+// FindLast returns the last element in the slice that satisfies the given
+// predicate. If no element satisfies the predicate, it returns the zero value
+// of the type.
+func (s *SafeSlice[T]) FindLast(predicate func(T) bool) T {
+	s.rlock()
 	defer s.RUnlock()
 
-	for _, item := range s.data {
-		if predicate(item) {
-			return true
+	for b := len(s.data.blocks) - 1; b >= 0; b-- {
+		block := s.data.blocks[b]
+
+		for i := len(block) - 1; i >= 0; i-- {
+			if predicate(block[i]) {
+				return block[i]
+			}
 		}
 	}
 
-	return false
+	return *new(T)
 }
 
-// TakeWhile creates a new slice containing elements from the original slice
-// until the predicate function returns false.
-func (s *SafeSlice[T]) TakeWhile(predicate func(T) bool) *SafeSlice[T] {
-	s.RLock()
+// FindIndex returns the index of the first element in the slice that
+// satisfies the given predicate, or -1.
+func (s *SafeSlice[T]) FindIndex(predicate func(T) bool) int {
+	s.rlock()
 	defer s.RUnlock()
 
-	result := New[T]()
+	for c := s.data.cursor(); c.next(); {
+		i, item := c.index, c.value()
+		if predicate(item) {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// FindAll returns all elements in the slice that satisfy the given predicate,
+// in order.
+func (s *SafeSlice[T]) FindAll(predicate func(T) bool) []T {
+	s.rlock()
+	defer s.RUnlock()
+
+	result := []T{}
+
+	for c := s.data.cursor(); c.next(); {
+		item := c.value()
+		if predicate(item) {
+			result = append(result, item)
+		}
+	}
+
+	return result
+}
+
+// Count returns the number of elements in the slice that satisfy the given
+// predicate.
+func (s *SafeSlice[T]) Count(predicate func(T) bool) int {
+	s.rlock()
+	defer s.RUnlock()
+
+	count := 0
+
+	for c := s.data.cursor(); c.next(); {
+		item := c.value()
+		if predicate(item) {
+			count++
+		}
+	}
+
+	return count
+}
+
+// Any checks if at least one element in the slice satisfies a given condition (predicate).
+func (s *SafeSlice[T]) Any(predicate func(T) bool) bool {
+	s.rlock()
+	defer s.RUnlock()
+
+	for c := s.data.cursor(); c.next(); {
+		item := c.value()
+		if predicate(item) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// TakeWhile creates a new slice containing elements from the original slice
+// until the predicate function returns false.
+func (s *SafeSlice[T]) TakeWhile(predicate func(T) bool) *SafeSlice[T] {
+	s.rlock()
+	defer s.RUnlock()
 
-	for _, item := range s.data {
+	result := New[T]()
+
+	for c := s.data.cursor(); c.next(); {
+		item := c.value()
 		if predicate(item) {
 			result.Add(item)
 		} else {
@@ -327,14 +942,15 @@ func (s *SafeSlice[T]) TakeWhile(predicate func(T) bool) *SafeSlice[T] {
 // DropWhile creates a new slice without the elements from the original slice
 // until the predicate function returns false.
 func (s *SafeSlice[T]) DropWhile(predicate func(T) bool) *SafeSlice[T] {
-	s.RLock()
+	s.rlock()
 	defer s.RUnlock()
 
 	result := New[T]()
 
 	dropping := true
 
-	for _, item := range s.data {
+	for c := s.data.cursor(); c.next(); {
+		item := c.value()
 		if dropping && predicate(item) {
 			continue
 		} else {
@@ -346,53 +962,166 @@ func (s *SafeSlice[T]) DropWhile(predicate func(T) bool) *SafeSlice[T] {
 	return result
 }
 
+//////
+// Erroring Higher-Order Functions. They stop at the first error, returning it.
+
+// TryMap is like Map, but the mapper can fail.
+func (s *SafeSlice[T]) TryMap(mapper func(T) (T, error)) (*SafeSlice[T], error) {
+	s.rlock()
+	defer s.RUnlock()
+
+	result := make([]T, 0, s.data.len())
+
+	for c := s.data.cursor(); c.next(); {
+		item := c.value()
+		mapped, err := mapper(item)
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, mapped)
+	}
+
+	return New(result...), nil
+}
+
+// TryFilter is like Filter, but the predicate can fail.
+func (s *SafeSlice[T]) TryFilter(predicate func(T) (bool, error)) (*SafeSlice[T], error) {
+	s.rlock()
+	defer s.RUnlock()
+
+	result := []T{}
+
+	for c := s.data.cursor(); c.next(); {
+		item := c.value()
+		ok, err := predicate(item)
+		if err != nil {
+			return nil, err
+		}
+
+		if ok {
+			result = append(result, item)
+		}
+	}
+
+	return New(result...), nil
+}
+
+// TryEach is like Each, but f can fail.
+func (s *SafeSlice[T]) TryEach(f func(T) error) error {
+	s.rlock()
+	defer s.RUnlock()
+
+	for c := s.data.cursor(); c.next(); {
+		item := c.value()
+		if err := f(item); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// TryReduce is like Reduce, but the reducer can fail. On failure, the zero
+// value is returned.
+func (s *SafeSlice[T]) TryReduce(reducer func(T, T) (T, error), initialValue T) (T, error) {
+	s.rlock()
+	defer s.RUnlock()
+
+	result := initialValue
+
+	for c := s.data.cursor(); c.next(); {
+		item := c.value()
+		var err error
+
+		result, err = reducer(result, item)
+		if err != nil {
+			return *new(T), err
+		}
+	}
+
+	return result, nil
+}
+
 //////
 // Set operations.
+//
+// Results have a deterministic order, following the order of the slices the
+// elements come from.
 
-// Union returns a new slice containing all unique elements from both slices.
-func (s *SafeSlice[T]) Union(other *SafeSlice[T]) *SafeSlice[T] {
-	s.RLock()
+// Union returns a new slice containing the elements of the slice, in its
+// order, followed by the elements of the other slices not yet present, in
+// their order, in a single pass.
+func (s *SafeSlice[T]) Union(others ...*SafeSlice[T]) *SafeSlice[T] {
+	values := valuesOf(others)
+
+	s.rlock()
 	defer s.RUnlock()
 
-	result := New[T]()
+	items := make([]T, 0, s.data.len())
+	seen := make(map[T]struct{}, s.data.len())
 
-	for _, item := range s.data {
-		result.Add(item)
+	for c := s.data.cursor(); c.next(); {
+		item := c.value()
+		items = append(items, item)
+		seen[item] = struct{}{}
 	}
 
-	for _, item := range other.data {
-		if !result.Contains(item) {
-			result.Add(item)
+	for _, others := range values {
+		for _, item := range others {
+			if _, ok := seen[item]; !ok {
+				items = append(items, item)
+				seen[item] = struct{}{}
+			}
 		}
 	}
 
-	return result
+	return New(items...)
 }
 
-// Difference returns a new slice containing elements present in the
-// original slice but not in the other slice.
-func (s *SafeSlice[T]) Difference(other *SafeSlice[T]) *SafeSlice[T] {
-	other.RLock()
-	defer other.RUnlock()
+// Difference returns a new slice containing elements present in the other
+// slices but not in the original slice, in the order of the other slices, in
+// a single pass.
+func (s *SafeSlice[T]) Difference(others ...*SafeSlice[T]) *SafeSlice[T] {
+	values := valuesOf(others)
 
-	result := New[T]()
+	s.rlock()
+	defer s.RUnlock()
 
-	for _, item := range other.data {
-		if !s.Contains(item) {
-			result.Add(item)
+	present := make(map[T]struct{}, s.data.len())
+
+	for c := s.data.cursor(); c.next(); {
+		present[c.value()] = struct{}{}
+	}
+
+	items := []T{}
+
+	for _, others := range values {
+		for _, item := range others {
+			if _, ok := present[item]; !ok {
+				items = append(items, item)
+			}
 		}
 	}
 
-	return result
+	return New(items...)
 }
 
 // Subset checks if all elements in the slice are present in the other slice.
 func (s *SafeSlice[T]) Subset(other *SafeSlice[T]) bool {
-	s.RLock()
+	others := other.Values()
+
+	s.rlock()
 	defer s.RUnlock()
 
-	for _, item := range s.data {
-		if !other.Contains(item) {
+	items := make(map[T]struct{}, len(others))
+
+	for _, item := range others {
+		items[item] = struct{}{}
+	}
+
+	for c := s.data.cursor(); c.next(); {
+		if _, ok := items[c.value()]; !ok {
 			return false
 		}
 	}
@@ -405,17 +1134,135 @@ func (s *SafeSlice[T]) Superset(other *SafeSlice[T]) bool {
 	return other.Subset(s)
 }
 
-// Intersection returns a new slice containing elements present in both slices.
-func (s *SafeSlice[T]) Intersection(other *SafeSlice[T]) *SafeSlice[T] {
-	s.RLock()
+// IsDisjoint checks if the slices have no elements in common. It's cheaper
+// than checking if the Intersection is empty, as it stops at the first common
+// element.
+func (s *SafeSlice[T]) IsDisjoint(other *SafeSlice[T]) bool {
+	others := other.Values()
+
+	s.rlock()
+	defer s.RUnlock()
+
+	items := make(map[T]struct{}, s.data.len())
+
+	for c := s.data.cursor(); c.next(); {
+		item := c.value()
+		items[item] = struct{}{}
+	}
+
+	for _, item := range others {
+		if _, ok := items[item]; ok {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Equal checks if both slices contain the same elements, regardless of their
+// order, and duplicates, i.e.: each one is a subset of the other.
+func (s *SafeSlice[T]) Equal(other *SafeSlice[T]) bool {
+	others := other.Values()
+
+	s.rlock()
+	defer s.RUnlock()
+
+	items := make(map[T]bool, s.data.len())
+
+	for c := s.data.cursor(); c.next(); {
+		item := c.value()
+		items[item] = false
+	}
+
+	for _, item := range others {
+		if _, ok := items[item]; !ok {
+			return false
+		}
+
+		items[item] = true
+	}
+
+	for _, found := range items {
+		if !found {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Intersection returns a new slice containing elements present in all
+// slices, in the order of the original slice, in a single pass.
+func (s *SafeSlice[T]) Intersection(others ...*SafeSlice[T]) *SafeSlice[T] {
+	// counts is the number of other slices having each element.
+	counts := map[T]int{}
+
+	for i, other := range valuesOf(others) {
+		for _, item := range other {
+			if counts[item] == i {
+				counts[item] = i + 1
+			}
+		}
+	}
+
+	s.rlock()
+	defer s.RUnlock()
+
+	items := []T{}
+
+	for c := s.data.cursor(); c.next(); {
+		if item := c.value(); counts[item] == len(others) {
+			items = append(items, item)
+		}
+	}
+
+	return New(items...)
+}
+
+// SymmetricDifference returns a new slice containing the unique elements
+// present in either slice but not in both. Elements of the original slice come
+// first, in its order, followed by the ones of the other slice, in its order.
+func (s *SafeSlice[T]) SymmetricDifference(other *SafeSlice[T]) *SafeSlice[T] {
+	others := other.Values()
+
+	s.rlock()
 	defer s.RUnlock()
 
 	result := New[T]()
 
-	for _, item := range s.data {
-		if other.Contains(item) {
-			result.Add(item)
+	if other == s {
+		return result
+	}
+
+	seen := make(map[T]struct{}, s.data.len()+len(others))
+	inOther := make(map[T]struct{}, len(others))
+
+	for _, item := range others {
+		inOther[item] = struct{}{}
+	}
+
+	for c := s.data.cursor(); c.next(); {
+		item := c.value()
+		seen[item] = struct{}{}
+	}
+
+	add := func(item T, exclude map[T]struct{}) {
+		if _, ok := exclude[item]; ok {
+			return
 		}
+
+		exclude[item] = struct{}{}
+
+		result.Add(item)
+	}
+
+	for c := s.data.cursor(); c.next(); {
+		item := c.value()
+		add(item, inOther)
+	}
+
+	for _, item := range others {
+		add(item, seen)
 	}
 
 	return result
@@ -426,12 +1273,13 @@ func (s *SafeSlice[T]) Intersection(other *SafeSlice[T]) *SafeSlice[T] {
 
 // Frequency returns a map with the frequency of each element in the slice.
 func (s *SafeSlice[T]) Frequency() map[T]int {
-	s.RLock()
+	s.rlock()
 	defer s.RUnlock()
 
 	freq := make(map[T]int)
 
-	for _, item := range s.data {
+	for c := s.data.cursor(); c.next(); {
+		item := c.value()
 		freq[item]++
 	}
 
@@ -442,7 +1290,7 @@ func (s *SafeSlice[T]) Frequency() map[T]int {
 // element(s) that appears the most frequently. If all elements appear with the
 // same frequency, it returns a slice with all elements.
 func (s *SafeSlice[T]) Mode() []T {
-	s.RLock()
+	s.rlock()
 	defer s.RUnlock()
 
 	if s.Empty() {
@@ -450,7 +1298,8 @@ func (s *SafeSlice[T]) Mode() []T {
 	}
 
 	freqMap := make(map[T]int)
-	for _, item := range s.data {
+	for c := s.data.cursor(); c.next(); {
+		item := c.value()
 		freqMap[item]++
 	}
 
@@ -461,10 +1310,9 @@ func (s *SafeSlice[T]) Mode() []T {
 		}
 	}
 
+	// All the elements are distinct.
 	if maxFreq == 1 {
-		uniqueSlice := s.Unique()
-
-		return uniqueSlice.data
+		return s.data.values()
 	}
 
 	modes := make([]T, 0)
@@ -478,21 +1326,53 @@ func (s *SafeSlice[T]) Mode() []T {
 	return modes
 }
 
+//////
+// Aggregations.
+
+// MinBy returns the smallest element according to less, the first one if
+// there are ties, or false if the slice is empty.
+func (s *SafeSlice[T]) MinBy(less func(a, b T) bool) (T, bool) {
+	s.rlock()
+	defer s.RUnlock()
+
+	if s.data.len() == 0 {
+		return *new(T), false
+	}
+
+	result := s.data.first()
+
+	for c := s.data.cursor(); c.next(); {
+		if item := c.value(); less(item, result) {
+			result = item
+		}
+	}
+
+	return result, true
+}
+
+// MaxBy returns the largest element according to less, the first one if
+// there are ties, or false if the slice is empty.
+func (s *SafeSlice[T]) MaxBy(less func(a, b T) bool) (T, bool) {
+	return s.MinBy(func(a, b T) bool {
+		return less(b, a)
+	})
+}
+
 //////
 // Conversion Operations.
 //////
 
 // MarshalJSON marshals the slice to JSON.
 func (s *SafeSlice[T]) MarshalJSON() ([]byte, error) {
-	s.RLock()
+	s.rlock()
 	defer s.RUnlock()
 
-	return json.Marshal(s.data)
+	return json.Marshal(s.data.slice())
 }
 
 // UnmarshalJSON unmarshals the slice from JSON.
 func (s *SafeSlice[T]) UnmarshalJSON(data []byte) error {
-	s.Lock()
+	s.lock()
 	defer s.Unlock()
 
 	var temp []T
@@ -500,20 +1380,207 @@ func (s *SafeSlice[T]) UnmarshalJSON(data []byte) error {
 		return err
 	}
 
-	s.data = temp
+	s.replace(temp)
+
+	s.metrics.Operation("unmarshal")
+	s.metrics.SetSize(s.data.len())
+
+	return nil
+}
+
+// Encode writes the slice to w as JSON, like MarshalJSON, marshalling one
+// element at a time, e.g.: to stream a large slice to an HTTP response without
+// building the whole JSON in memory. Elements are copied first, so the lock
+// isn't held while writing.
+func (s *SafeSlice[T]) Encode(w io.Writer) error {
+	return shared.EncodeJSONList(w, s.Values())
+}
+
+// Decode reads the slice from r as JSON, like UnmarshalJSON, decoding one
+// element at a time. It replaces the content of the slice.
+func (s *SafeSlice[T]) Decode(r io.Reader) error {
+	items, err := shared.DecodeJSONList[T](r)
+	if err != nil {
+		return err
+	}
+
+	s.lock()
+	defer s.Unlock()
+
+	s.replace(items)
+
+	s.metrics.Operation("unmarshal")
+	s.metrics.SetSize(s.data.len())
+
+	return nil
+}
+
+// MarshalText implements the encoding.TextMarshaler interface. Elements are
+// comma-separated, e.g.: a,b,c. Commas, and backslashes are escaped by a
+// backslash.
+func (s *SafeSlice[T]) MarshalText() ([]byte, error) {
+	s.rlock()
+	defer s.RUnlock()
+
+	return shared.FormatTextList(s.data.slice())
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface, the inverse
+// of MarshalText. It replaces the content of the slice. An empty text results
+// in an empty slice.
+func (s *SafeSlice[T]) UnmarshalText(text []byte) error {
+	data, err := shared.ParseTextList[T](text)
+	if err != nil {
+		return err
+	}
+
+	s.lock()
+	defer s.Unlock()
+
+	s.replace(data)
+
+	s.metrics.Operation("unmarshal")
+	s.metrics.SetSize(s.data.len())
 
 	return nil
 }
 
+// EncodeWith encodes the slice as an array. It's meant to be used with
+// formats such as MessagePack, and CBOR.
+func (s *SafeSlice[T]) EncodeWith(enc shared.Encoder) error {
+	s.rlock()
+	defer s.RUnlock()
+
+	return shared.EncodeList(enc, s.data.slice())
+}
+
+// DecodeWith decodes an array encoded by EncodeWith. It replaces the content
+// of the slice.
+func (s *SafeSlice[T]) DecodeWith(dec shared.Decoder) error {
+	data, err := shared.DecodeList[T](dec)
+	if err != nil {
+		return err
+	}
+
+	s.lock()
+	defer s.Unlock()
+
+	s.replace(data)
+
+	s.metrics.Operation("unmarshal")
+	s.metrics.SetSize(s.data.len())
+
+	return nil
+}
+
+// MarshalBSON implements bson.Marshaler interface for SafeSlice. BSON requires
+// a document, so the slice is encoded as a document keyed by the index of the
+// elements, which is how BSON represents arrays. Elements keep their BSON
+// types, like with the MongoDB driver, e.g.: int64, or time.Time as a
+// datetime.
+func (s *SafeSlice[T]) MarshalBSON() ([]byte, error) {
+	s.rlock()
+	defer s.RUnlock()
+
+	return bsonjson.MarshalList(s.data.slice())
+}
+
+// UnmarshalBSON implements bson.Unmarshaler interface for SafeSlice. It
+// replaces the content of the slice.
+func (s *SafeSlice[T]) UnmarshalBSON(data []byte) error {
+	items, err := bsonjson.UnmarshalList[T](data)
+	if err != nil {
+		return err
+	}
+
+	s.lock()
+	defer s.Unlock()
+
+	s.replace(items)
+
+	s.metrics.Operation("unmarshal")
+	s.metrics.SetSize(s.data.len())
+
+	return nil
+}
+
+// Value implements the driver.Valuer interface, storing the slice as JSON.
+func (s *SafeSlice[T]) Value() (driver.Value, error) {
+	if s == nil {
+		return nil, nil
+	}
+
+	b, err := s.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+
+	return string(b), nil
+}
+
+// Scan implements the sql.Scanner interface, loading the slice from JSON.
+func (s *SafeSlice[T]) Scan(src any) error {
+	return shared.ScanJSON(src, s.UnmarshalJSON)
+}
+
+//////
+// Helpers.
+//////
+
+// valuesOf returns the values of the slices, each read holding its lock.
+func valuesOf[T comparable](slices []*SafeSlice[T]) [][]T {
+	values := make([][]T, len(slices))
+
+	for i, s := range slices {
+		values[i] = s.Values()
+	}
+
+	return values
+}
+
 //////
 // Factory.
 //////
 
+// WithMetrics enables metrics instrumentation, tracking operation counts,
+// size, lock wait times, and hit/miss ratios into the given metrics.
+func WithMetrics[T comparable](mtrcs *metrics.Metrics) Option[T] {
+	return func(s *SafeSlice[T]) {
+		s.metrics = mtrcs
+	}
+}
+
 // New creates a new Safe Slice.
 func New[T comparable](v ...T) *SafeSlice[T] {
-	return &SafeSlice[T]{
-		data: v,
+	s := &SafeSlice[T]{}
+
+	s.data.reset(v)
+
+	return s
+}
+
+// NewWithOptions creates a new, empty, Safe Slice configured with the given
+// options.
+func NewWithOptions[T comparable](opts ...Option[T]) *SafeSlice[T] {
+	s := New[T]()
+
+	for _, opt := range opts {
+		opt(s)
 	}
+
+	return s
+}
+
+// NewFromJSON creates a new Safe Slice configured with the given options,
+// loaded from JSON, as encoded by MarshalJSON.
+func NewFromJSON[T comparable](data []byte, opts ...Option[T]) (*SafeSlice[T], error) {
+	s := NewWithOptions(opts...)
+
+	if err := s.UnmarshalJSON(data); err != nil {
+		return nil, err
+	}
+
+	return s, nil
 }
 
 //////
@@ -523,12 +1590,13 @@ func New[T comparable](v ...T) *SafeSlice[T] {
 // Pluck returns a new slice with the result of applying the given predicate
 // to each element of the slice.
 func Pluck[T, R comparable](s *SafeSlice[T], predicate func(T) R) []R {
-	s.RLock()
+	s.rlock()
 	defer s.RUnlock()
 
 	result := []R{}
 
-	for _, item := range s.data {
+	for c := s.data.cursor(); c.next(); {
+		item := c.value()
 		r := predicate(item)
 
 		if r != *new(R) {
@@ -538,3 +1606,156 @@ func Pluck[T, R comparable](s *SafeSlice[T], predicate func(T) R) []R {
 
 	return result
 }
+
+// Min returns the smallest element, or false if the slice is empty.
+func Min[T statistical.Numbers](s *SafeSlice[T]) (T, bool) {
+	return s.MinBy(func(a, b T) bool { return a < b })
+}
+
+// Max returns the largest element, or false if the slice is empty.
+func Max[T statistical.Numbers](s *SafeSlice[T]) (T, bool) {
+	return s.MaxBy(func(a, b T) bool { return a < b })
+}
+
+// Sum returns the sum of the elements, 0 if the slice is empty.
+func Sum[T statistical.Numbers](s *SafeSlice[T]) T {
+	return s.Reduce(func(acc, item T) T { return acc + item }, 0)
+}
+
+// Zip returns a slice of pairs, combining the elements of both slices at the
+// same index. Its length is the shortest of both.
+func Zip[A, B comparable](a *SafeSlice[A], b *SafeSlice[B]) *SafeSlice[tuple.Pair[A, B]] {
+	return ZipWith(a, b, tuple.NewPair[A, B])
+}
+
+// ZipWith returns a slice with the result of calling f with the elements of
+// both slices at the same index. Its length is the shortest of both.
+func ZipWith[A, B, R comparable](a *SafeSlice[A], b *SafeSlice[B], f func(A, B) R) *SafeSlice[R] {
+	as, bs := a.Values(), b.Values()
+
+	n := len(as)
+	if len(bs) < n {
+		n = len(bs)
+	}
+
+	result := make([]R, 0, n)
+
+	for i := 0; i < n; i++ {
+		result = append(result, f(as[i], bs[i]))
+	}
+
+	return New(result...)
+}
+
+// Unzip is the inverse of Zip, splitting a slice of pairs into two slices.
+func Unzip[A, B comparable](s *SafeSlice[tuple.Pair[A, B]]) (*SafeSlice[A], *SafeSlice[B]) {
+	pairs := s.Values()
+
+	as := make([]A, 0, len(pairs))
+	bs := make([]B, 0, len(pairs))
+
+	for _, p := range pairs {
+		as = append(as, p.First)
+		bs = append(bs, p.Second)
+	}
+
+	return New(as...), New(bs...)
+}
+
+// Pairwise returns a slice of the pairs of consecutive elements, e.g.:
+// [1, 2, 3] results in [(1, 2), (2, 3)].
+func Pairwise[T comparable](s *SafeSlice[T]) *SafeSlice[tuple.Pair[T, T]] {
+	values := s.Values()
+
+	if len(values) < 2 {
+		return New[tuple.Pair[T, T]]()
+	}
+
+	return ZipWith(New(values[:len(values)-1]...), New(values[1:]...), tuple.NewPair[T, T])
+}
+
+// WeightedSample returns up to n distinct elements, picked at random, without
+// replacement, with a probability proportional to their weight. Elements with
+// a weight <= 0 are never picked. Elements are ordered as picked, so the first
+// one is a weighted random choice among all. It returns an empty slice if n
+// <= 0.
+func WeightedSample[T comparable](s *SafeSlice[T], weight func(T) float64, n int) *SafeSlice[T] {
+	if n <= 0 {
+		return New[T]()
+	}
+
+	type candidate struct {
+		item T
+		key  float64
+	}
+
+	candidates := []candidate{}
+
+	// Efraimidis-Spirakis: pick the n largest log(u)/w keys.
+	for _, item := range s.Values() {
+		w := weight(item)
+		if w <= 0 || math.IsNaN(w) {
+			continue
+		}
+
+		candidates = append(candidates, candidate{
+			item: item,
+			key:  math.Log(1-rand.Float64()) / w, //nolint:gosec
+		})
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		return candidates[i].key > candidates[j].key
+	})
+
+	if n > len(candidates) {
+		n = len(candidates)
+	}
+
+	result := make([]T, 0, n)
+
+	for _, c := range candidates[:n] {
+		result = append(result, c.item)
+	}
+
+	return New(result...)
+}
+
+// ReservoirSample returns up to n elements picked uniformly at random from the
+// stream, using a single pass, and O(n) memory. It consumes items until the
+// channel is closed, or the context is done, e.g.: a Watch channel.
+func ReservoirSample[T comparable](ctx context.Context, items <-chan T, n int) *SafeSlice[T] {
+	reservoir := []T{}
+
+	if n <= 0 {
+		return New(reservoir...)
+	}
+
+	for seen := 0; ; seen++ {
+		select {
+		case <-ctx.Done():
+			return New(reservoir...)
+		case item, ok := <-items:
+			if !ok {
+				return New(reservoir...)
+			}
+
+			if seen < n {
+				reservoir = append(reservoir, item)
+
+				continue
+			}
+
+			if i := rand.Intn(seen + 1); i < n { //nolint:gosec
+				reservoir[i] = item
+			}
+		}
+	}
+}
+
+// reverse reverses the elements in place.
+func reverse[T any](data []T) {
+	for i, j := 0, len(data)-1; i < j; i, j = i+1, j-1 {
+		data[i], data[j] = data[j], data[i]
+	}
+}
@@ -1,11 +1,23 @@
 package safeslice
 
 import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"os"
 	"reflect"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/thalesfsp/go-common-types/internal/codectest"
+	"github.com/thalesfsp/go-common-types/metrics"
+	"github.com/thalesfsp/go-common-types/shared"
+	"github.com/thalesfsp/go-common-types/tuple"
 )
 
 //nolint:goconst
@@ -71,6 +83,23 @@ func TestSafeSliceDelete(t *testing.T) {
 	}
 }
 
+func TestSafeSliceRemove(t *testing.T) {
+	s := New(1, 2, 1, 3, 1)
+
+	assert.True(t, s.Remove(1))
+	assert.Equal(t, []int{2, 1, 3, 1}, s.Values())
+
+	assert.False(t, s.Remove(9))
+
+	assert.Equal(t, 2, s.RemoveAll(1))
+	assert.Equal(t, []int{2, 3}, s.Values())
+
+	assert.Equal(t, 1, s.RemoveFunc(func(v int) bool { return v%2 == 0 }))
+	assert.Equal(t, []int{3}, s.Values())
+
+	assert.Equal(t, 0, s.RemoveAll(1))
+}
+
 func TestSafeSliceContains(t *testing.T) {
 	s := New[int]()
 
@@ -158,6 +187,25 @@ func TestSafeSliceUnique(t *testing.T) {
 	}
 }
 
+func TestSafeSliceUniqueBy(t *testing.T) {
+	type item struct {
+		ID   string
+		Name string
+	}
+
+	s := New(item{"1", "a"}, item{"2", "b"}, item{"1", "c"})
+
+	assert.Equal(t, []item{{"1", "a"}, {"2", "b"}}, s.UniqueBy(func(i item) string { return i.ID }).Values())
+	assert.Equal(t, 3, s.Size())
+}
+
+func TestSafeSliceCompactBy(t *testing.T) {
+	s := New("a", "A", "b", "a", "a")
+
+	assert.Equal(t, []string{"a", "b", "a"}, s.CompactBy(strings.EqualFold).Values())
+	assert.Empty(t, New[string]().CompactBy(strings.EqualFold).Values())
+}
+
 func TestSafeSliceAll(t *testing.T) {
 	s := New[int]()
 
@@ -398,6 +446,44 @@ func TestSafeSliceIntersection(t *testing.T) {
 	}
 }
 
+func TestSafeSliceIsDisjointEqual(t *testing.T) {
+	s := New[int]()
+	s.Add(1).Add(2).Add(2).Add(3)
+
+	o := New[int]()
+	o.Add(4).Add(5)
+
+	assert.True(t, s.IsDisjoint(o))
+
+	o.Add(2)
+
+	assert.False(t, s.IsDisjoint(o))
+
+	e := New[int]()
+	e.Add(3).Add(1).Add(2)
+
+	assert.True(t, s.Equal(e))
+	assert.True(t, e.Equal(s))
+	assert.False(t, s.Equal(o))
+
+	e.Add(4)
+
+	assert.False(t, s.Equal(e))
+	assert.False(t, e.Equal(s))
+}
+
+func TestSafeSliceSymmetricDifference(t *testing.T) {
+	s := New[int]()
+	s.Add(3).Add(1).Add(2).Add(1)
+
+	o := New[int]()
+	o.Add(5).Add(3).Add(4).Add(5)
+
+	assert.Equal(t, []int{1, 2, 5, 4}, s.SymmetricDifference(o).Values())
+	assert.Equal(t, []int{5, 4, 1, 2}, o.SymmetricDifference(s).Values())
+	assert.Equal(t, 0, s.SymmetricDifference(s).Size())
+}
+
 func TestSafeSliceFrequency(t *testing.T) {
 	s := New[int]()
 
@@ -453,6 +539,20 @@ func TestSafeSliceMarshalJSON(t *testing.T) {
 	}
 }
 
+func TestSafeSliceEncodeDecode(t *testing.T) {
+	var buf bytes.Buffer
+
+	assert.NoError(t, New(1, 2, 3).Encode(&buf))
+	assert.Equal(t, "[1,2,3]", buf.String())
+
+	decoded := New(9)
+
+	assert.NoError(t, decoded.Decode(&buf))
+	assert.Equal(t, []int{1, 2, 3}, decoded.Values())
+
+	assert.Error(t, decoded.Decode(strings.NewReader(`{}`)))
+}
+
 func TestSafeSliceUnmarshalJSON(t *testing.T) {
 	s := New[int]()
 
@@ -554,3 +654,498 @@ func TestSafeSlice_Pluck(t *testing.T) {
 
 	assert.Equal(t, []string{"test1", "test2"}, actual)
 }
+
+func TestSafeSliceWithMetrics(t *testing.T) {
+	mtrcs := metrics.New("ss")
+
+	s := NewWithOptions[int](WithMetrics[int](mtrcs))
+	s.Add(1).Add(2).Add(3).Delete(0)
+
+	s.Contains(2)
+	s.Contains(4)
+
+	snapshot := s.Metrics().Snapshot()
+
+	assert.Equal(t, uint64(3), snapshot.Operations["add"])
+	assert.Equal(t, int64(2), snapshot.Size)
+	assert.Equal(t, uint64(1), snapshot.Hits)
+	assert.Equal(t, uint64(1), snapshot.Misses)
+}
+
+func TestSafeSlicePrettyString(t *testing.T) {
+	s := New(1, 2)
+
+	assert.Equal(t, "[\n  1,\n  2\n]", s.PrettyString())
+}
+
+func TestSafeSliceTable(t *testing.T) {
+	type user struct {
+		Name string
+		Role string
+	}
+
+	s := New(user{"john", "admin"}, user{"ann", "viewer"})
+
+	var buf strings.Builder
+
+	err := s.Table(&buf, func(u user) string { return u.Name }, func(u user) string { return u.Role })
+	assert.NoError(t, err)
+	assert.Equal(t, "john  admin\nann   viewer\n", buf.String())
+}
+
+func TestSafeSliceValueScan(t *testing.T) {
+	var (
+		_ driver.Valuer = New[int]()
+		_ sql.Scanner   = New[int]()
+	)
+
+	s := New(1, 2, 3)
+
+	v, err := s.Value()
+	assert.NoError(t, err)
+	assert.Equal(t, "[1,2,3]", v)
+
+	s2 := New[int]()
+	assert.NoError(t, s2.Scan([]byte("[1,2,3]")))
+	assert.Equal(t, []int{1, 2, 3}, s2.ToSlice())
+
+	assert.NoError(t, s2.Scan("[4]"))
+	assert.Equal(t, []int{4}, s2.ToSlice())
+
+	assert.NoError(t, s2.Scan(nil))
+	assert.True(t, s2.Empty())
+
+	assert.Error(t, s2.Scan(1.5))
+}
+
+func TestSafeSliceMarshalUnmarshalText(t *testing.T) {
+	s := New("a", "b,c")
+
+	text, err := s.MarshalText()
+	assert.NoError(t, err)
+	assert.Equal(t, `a,b\,c`, string(text))
+
+	s2 := New[string]()
+	assert.NoError(t, s2.UnmarshalText(text))
+	assert.Equal(t, []string{"a", "b,c"}, s2.ToSlice())
+
+	ints := New[int]()
+	assert.NoError(t, ints.UnmarshalText([]byte("1,2,3")))
+	assert.Equal(t, []int{1, 2, 3}, ints.ToSlice())
+	assert.Error(t, ints.UnmarshalText([]byte("1,x")))
+	assert.Equal(t, []int{1, 2, 3}, ints.ToSlice())
+
+	t.Setenv("SAFESLICE_TEST", "4,5")
+
+	assert.NoError(t, ints.UnmarshalText([]byte(os.Getenv("SAFESLICE_TEST"))))
+	assert.Equal(t, []int{4, 5}, ints.ToSlice())
+}
+
+func TestSafeSliceEncodeDecodeWith(t *testing.T) {
+	c := &codectest.TokenCodec{}
+
+	assert.NoError(t, New(3, 1, 2).EncodeWith(c))
+
+	s := New[int]()
+	assert.NoError(t, s.DecodeWith(c))
+	assert.Equal(t, []int{3, 1, 2}, s.ToSlice())
+}
+
+func TestSafeSliceMarshalUnmarshalBSON(t *testing.T) {
+	b, err := New("x", "y").MarshalBSON()
+	assert.NoError(t, err)
+
+	s := New[string]()
+	assert.NoError(t, s.UnmarshalBSON(b))
+	assert.Equal(t, []string{"x", "y"}, s.ToSlice())
+}
+
+func TestSafeSliceAppendValues(t *testing.T) {
+	s := New(1)
+
+	s.Append(2, 3)
+
+	values := s.Values()
+	values[0] = 10
+
+	assert.Equal(t, []int{1, 2, 3}, s.ToSlice())
+}
+
+func TestSafeSliceParallelMap(t *testing.T) {
+	s := New(1, 2, 3, 4)
+
+	doubled, err := s.ParallelMap(context.Background(), 2, func(i int) (int, error) {
+		return i * 2, nil
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []int{2, 4, 6, 8}, doubled.ToSlice())
+
+	errBig := errors.New("too big")
+
+	err = s.ParallelEach(context.Background(), 0, func(i int) error {
+		if i > 3 {
+			return errBig
+		}
+
+		return nil
+	})
+
+	assert.ErrorIs(t, err, errBig)
+}
+
+func TestSafeSliceTryFunctions(t *testing.T) {
+	errNegative := errors.New("negative")
+
+	failing := func(v int) (int, error) {
+		if v < 0 {
+			return 0, errNegative
+		}
+
+		return v * 2, nil
+	}
+
+	s := New(1, 2, 3)
+
+	doubled, err := s.TryMap(failing)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{2, 4, 6}, doubled.ToSlice())
+
+	odd, err := s.TryFilter(func(v int) (bool, error) { return v%2 == 1, nil })
+	assert.NoError(t, err)
+	assert.Equal(t, []int{1, 3}, odd.ToSlice())
+
+	sum, err := s.TryReduce(func(acc, v int) (int, error) { return acc + v, nil }, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, 6, sum)
+
+	s.Add(-1).Add(5)
+
+	_, err = s.TryMap(failing)
+	assert.ErrorIs(t, err, errNegative)
+
+	_, err = s.TryFilter(func(v int) (bool, error) {
+		_, err := failing(v)
+
+		return true, err
+	})
+	assert.ErrorIs(t, err, errNegative)
+
+	count := 0
+
+	err = s.TryEach(func(v int) error {
+		count++
+
+		_, err := failing(v)
+
+		return err
+	})
+	assert.ErrorIs(t, err, errNegative)
+	assert.Equal(t, 4, count)
+
+	_, err = s.TryReduce(func(acc, v int) (int, error) { return failing(v) }, 0)
+	assert.ErrorIs(t, err, errNegative)
+}
+
+func TestSafeSliceSearch(t *testing.T) {
+	s := New(1, 2, 3, 4, 5)
+
+	even := func(v int) bool { return v%2 == 0 }
+	none := func(v int) bool { return v > 10 }
+
+	assert.Equal(t, 4, s.FindLast(even))
+	assert.Equal(t, 0, s.FindLast(none))
+
+	assert.Equal(t, 1, s.FindIndex(even))
+	assert.Equal(t, -1, s.FindIndex(none))
+
+	assert.Equal(t, []int{2, 4}, s.FindAll(even))
+	assert.Empty(t, s.FindAll(none))
+
+	assert.Equal(t, 2, s.Count(even))
+	assert.Equal(t, 0, s.Count(none))
+}
+
+func TestSafeSliceAggregations(t *testing.T) {
+	s := New(3, -1, 4, -1, 5)
+
+	minimum, ok := Min(s)
+	assert.True(t, ok)
+	assert.Equal(t, -1, minimum)
+
+	maximum, ok := Max(s)
+	assert.True(t, ok)
+	assert.Equal(t, 5, maximum)
+
+	assert.Equal(t, 10, Sum(s))
+
+	words := New("bb", "a", "ccc", "dd")
+	byLen := func(a, b string) bool { return len(a) < len(b) }
+
+	shortest, ok := words.MinBy(byLen)
+	assert.True(t, ok)
+	assert.Equal(t, "a", shortest)
+
+	longest, ok := words.MaxBy(byLen)
+	assert.True(t, ok)
+	assert.Equal(t, "ccc", longest)
+
+	_, ok = Min(New[float64]())
+	assert.False(t, ok)
+	assert.Equal(t, 0.0, Sum(New[float64]()))
+}
+
+func TestSafeSliceZipUnzip(t *testing.T) {
+	names := New("a", "b", "c")
+	ages := New(1, 2)
+
+	zipped := Zip(names, ages)
+
+	assert.Equal(t, []tuple.Pair[string, int]{
+		tuple.NewPair("a", 1),
+		tuple.NewPair("b", 2),
+	}, zipped.ToSlice())
+
+	n, a := Unzip(zipped)
+	assert.Equal(t, []string{"a", "b"}, n.ToSlice())
+	assert.Equal(t, []int{1, 2}, a.ToSlice())
+
+	sums := ZipWith(New(1, 2, 3), New(10, 20, 30), func(x, y int) int { return x + y })
+	assert.Equal(t, []int{11, 22, 33}, sums.ToSlice())
+
+	assert.Equal(t, []tuple.Pair[int, int]{
+		tuple.NewPair(1, 2),
+		tuple.NewPair(2, 3),
+	}, Pairwise(New(1, 2, 3)).ToSlice())
+	assert.True(t, Pairwise(New(1)).Empty())
+}
+
+func TestSafeSliceReorder(t *testing.T) {
+	s := New(1, 2, 3, 4, 5)
+
+	assert.Equal(t, []int{5, 2, 3, 4, 1}, s.Swap(0, 4).Values())
+	assert.Equal(t, []int{5, 2, 3, 4, 1}, s.Swap(0, 5).Swap(-1, 0).Values())
+
+	s = New(1, 2, 3, 4, 5)
+
+	assert.Equal(t, []int{2, 3, 4, 1, 5}, s.Move(0, 3).Values())
+	assert.Equal(t, []int{1, 2, 3, 4, 5}, s.Move(3, 0).Values())
+	assert.Equal(t, []int{1, 2, 3, 4, 5}, s.Move(2, 2).Move(0, 9).Values())
+
+	assert.Equal(t, []int{3, 4, 5, 1, 2}, s.RotateLeft(2).Values())
+	assert.Equal(t, []int{1, 2, 3, 4, 5}, s.RotateRight(2).Values())
+	assert.Equal(t, []int{5, 1, 2, 3, 4}, s.RotateLeft(9).Values())
+	assert.Equal(t, []int{1, 2, 3, 4, 5}, s.RotateRight(-1).Values())
+	assert.Equal(t, []int{1, 2, 3, 4, 5}, s.RotateLeft(0).RotateLeft(5).Values())
+
+	assert.True(t, New[int]().RotateLeft(3).Empty())
+}
+
+func TestSafeSliceConcatInterleave(t *testing.T) {
+	a := New(1, 2, 3)
+	b := New(10, 20)
+	c := New(100)
+
+	assert.Equal(t, []int{1, 2, 3, 10, 20, 100}, a.Concat(b, c).Values())
+	assert.Equal(t, []int{1, 2, 3, 1, 2, 3}, a.Concat(a).Values())
+	assert.Equal(t, []int{1, 2, 3}, a.Concat().Values())
+
+	assert.Equal(t, []int{1, 10, 2, 20, 3}, a.Interleave(b).Values())
+	assert.Equal(t, []int{10, 1, 20, 2, 3}, b.Interleave(a).Values())
+	assert.Equal(t, []int{1, 10, 100, 2, 20, 3}, a.Interleave(b, c).Values())
+	assert.Equal(t, []int{1}, New[int]().Interleave(New(1)).Values())
+	assert.True(t, New[int]().Interleave().Empty())
+}
+
+func TestWeightedSample(t *testing.T) {
+	s := New("a", "b", "c", "zero")
+
+	weights := map[string]float64{"a": 9, "b": 1, "c": 1, "zero": 0}
+	weight := func(item string) float64 { return weights[item] }
+
+	sample := WeightedSample(s, weight, 10)
+
+	assert.Equal(t, 3, sample.Size())
+	assert.False(t, sample.Contains("zero"))
+
+	picks := map[string]int{}
+
+	for i := 0; i < 2000; i++ {
+		picks[WeightedSample(s, weight, 1).Get(0)]++
+	}
+
+	assert.Greater(t, picks["a"], 1500)
+	assert.Zero(t, picks["zero"])
+
+	assert.Equal(t, 0, WeightedSample(s, weight, 0).Size())
+	assert.Equal(t, 0, WeightedSample(s, weight, -1).Size())
+}
+
+func TestReservoirSample(t *testing.T) {
+	items := make(chan int)
+
+	go func() {
+		defer close(items)
+
+		for i := 0; i < 100; i++ {
+			items <- i
+		}
+	}()
+
+	sample := ReservoirSample(context.Background(), items, 10)
+
+	assert.Equal(t, 10, sample.Size())
+	assert.Equal(t, 10, len(sample.Unique().Values()))
+	assert.True(t, sample.All(func(item int) bool { return item >= 0 && item < 100 }))
+
+	few := make(chan int, 2)
+	few <- 1
+	few <- 2
+	close(few)
+
+	assert.Equal(t, []int{1, 2}, ReservoirSample(context.Background(), few, 10).Values())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	assert.Equal(t, 0, ReservoirSample(ctx, make(chan int), 10).Size())
+}
+
+func TestSafeSliceSortWith(t *testing.T) {
+	type user struct {
+		Name string
+		Age  int
+	}
+
+	s := New(user{"bob", 30}, user{"alice", 25}, user{"carol", 25})
+
+	s.SortWith(shared.CompareBy(func(u user) int { return u.Age }))
+
+	assert.Equal(t, []user{{"alice", 25}, {"carol", 25}, {"bob", 30}}, s.Values())
+
+	s.SortWith(shared.Reverse(shared.CompareBy(func(u user) string { return u.Name })))
+
+	assert.Equal(t, "carol", s.Get(0).Name)
+
+	oldest, ok := s.MaxBy(shared.Less(shared.CompareBy(func(u user) int { return u.Age })))
+	assert.True(t, ok)
+	assert.Equal(t, "bob", oldest.Name)
+}
+
+func TestSafeSliceBinarySearch(t *testing.T) {
+	cmp := shared.CompareOrdered[int]().Compare
+
+	s := New[int]()
+
+	for _, v := range []int{5, 1, 3, 3, 9} {
+		s.SortedInsert(v, cmp)
+	}
+
+	assert.Equal(t, []int{1, 3, 3, 5, 9}, s.Values())
+
+	i, ok := s.BinarySearch(3, cmp)
+	assert.True(t, ok)
+	assert.Equal(t, 1, i)
+
+	i, ok = s.BinarySearch(4, cmp)
+	assert.False(t, ok)
+	assert.Equal(t, 3, i)
+
+	i, ok = s.BinarySearch(10, cmp)
+	assert.False(t, ok)
+	assert.Equal(t, 5, i)
+
+	assert.Equal(t, 4, s.SortedInsert(7, cmp))
+
+	bounded := NewBounded[int](2, DropOldest)
+	bounded.SortedInsert(2, cmp)
+	bounded.SortedInsert(1, cmp)
+
+	assert.Equal(t, 1, bounded.SortedInsert(3, cmp))
+	assert.Equal(t, []int{2, 3}, bounded.Values())
+
+	rejecting := NewBounded[int](1, Reject)
+	rejecting.SortedInsert(1, cmp)
+
+	assert.Equal(t, -1, rejecting.SortedInsert(0, cmp))
+	assert.Equal(t, []int{1}, rejecting.Values())
+}
+
+func TestSafeSliceSortedInsertBounded(t *testing.T) {
+	cmp := shared.CompareOrdered[int]().Compare
+
+	// DropOldest keeps the largest elements, whatever the insertion order.
+	top := NewBounded[int](3, DropOldest)
+
+	for _, v := range []int{5, 1, 9, 3, 7, 2} {
+		top.SortedInsert(v, cmp)
+	}
+
+	assert.Equal(t, []int{5, 7, 9}, top.Values())
+
+	// Items less than the smallest element are discarded.
+	assert.Equal(t, -1, top.SortedInsert(4, cmp))
+	assert.Equal(t, []int{5, 7, 9}, top.Values())
+
+	// Otherwise, the smallest element is evicted, e.g.: an equal item goes
+	// after it.
+	assert.Equal(t, 0, top.SortedInsert(5, cmp))
+	assert.Equal(t, []int{5, 7, 9}, top.Values())
+
+	assert.Equal(t, 1, top.SortedInsert(8, cmp))
+	assert.Equal(t, []int{7, 8, 9}, top.Values())
+
+	dropping := NewBounded[int](1, DropNewest)
+	dropping.SortedInsert(1, cmp)
+
+	assert.Equal(t, -1, dropping.SortedInsert(2, cmp))
+	assert.Equal(t, []int{1}, dropping.Values())
+}
+
+func TestSafeSliceDigest(t *testing.T) {
+	a, err := New(1, 2, 3).Digest(nil)
+	assert.NoError(t, err)
+
+	b, err := New(1, 2, 3).Digest(sha256.New())
+	assert.NoError(t, err)
+	assert.Equal(t, a, b)
+
+	c, err := New(3, 2, 1).Digest(nil)
+	assert.NoError(t, err)
+	assert.NotEqual(t, a, c)
+}
+
+func TestSafeSliceVariadicSetOperations(t *testing.T) {
+	a := New(1, 2, 3, 4, 3)
+	b := New(2, 3, 5, 5)
+	c := New(3, 4, 6)
+
+	assert.Equal(t, []int{1, 2, 3, 4, 3, 5, 6}, a.Union(b, c).Values())
+	assert.Equal(t, []int{3, 3}, a.Intersection(b, c).Values())
+	assert.Equal(t, []int{5, 5, 6}, a.Difference(b, c).Values())
+
+	// Duplicates in a slice count once.
+	assert.Empty(t, New(1).Intersection(New(2), New(2, 2)).Values())
+
+	assert.Equal(t, a.Values(), a.Intersection().Values())
+	assert.Empty(t, a.Difference().Values())
+}
+
+func TestSafeSliceZeroValue(t *testing.T) {
+	var s SafeSlice[int]
+
+	assert.NoError(t, s.UnmarshalJSON([]byte(`[1,2]`)))
+
+	s.Add(3)
+
+	assert.Equal(t, []int{1, 2, 3}, s.Values())
+}
+
+func TestNewFromJSON(t *testing.T) {
+	s, err := NewFromJSON[int]([]byte(`[3,1,2]`))
+	assert.NoError(t, err)
+	assert.Equal(t, []int{3, 1, 2}, s.Values())
+
+	_, err = NewFromJSON[int]([]byte(`{}`))
+	assert.Error(t, err)
+}
@@ -0,0 +1,50 @@
+package safeslice
+
+import (
+	"unsafe"
+
+	"github.com/thalesfsp/go-common-types/shared"
+)
+
+//////
+// Methods.
+//////
+
+// EstimateBytes estimates the memory used by the slice, in bytes: the
+// backing arrays, including unused capacity, and, in slab mode, the headers
+// of the blocks, plus the bytes referenced by each element, as given by the
+// sizer, see WithSizer. The estimate is recorded into the metrics, if enabled.
+func (s *SafeSlice[T]) EstimateBytes() int64 {
+	s.rlock()
+	defer s.RUnlock()
+
+	var zero T
+
+	size := int64(unsafe.Sizeof(*s)) + int64(s.data.capacity()-s.data.len())*int64(unsafe.Sizeof(zero))
+
+	if s.data.size > 0 {
+		size += int64(len(s.data.blocks)) * int64(unsafe.Sizeof(s.data.blocks))
+	}
+
+	for c := s.data.cursor(); c.next(); {
+		size += shared.EstimateSize(c.value(), s.sizer)
+	}
+
+	s.metrics.Operation("estimateBytes")
+	s.metrics.SetBytes(size)
+
+	return size
+}
+
+//////
+// Factory.
+//////
+
+// WithSizer sets the sizer of the elements, used by EstimateBytes to count
+// the bytes they reference, e.g.: the elements of a slice. By default, only
+// the contents of strings, and byte slices are counted.
+func WithSizer[T comparable](sizer shared.Sizer[T]) Option[T] {
+	return func(s *SafeSlice[T]) {
+		s.sizer = sizer
+	}
+}
@@ -0,0 +1,50 @@
+package safeslice
+
+import (
+	"testing"
+	"unsafe"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/thalesfsp/go-common-types/metrics"
+)
+
+func TestSafeSliceEstimateBytes(t *testing.T) {
+	m := metrics.New("test")
+
+	s := NewWithOptions[string](WithMetrics[string](m))
+	empty := s.EstimateBytes()
+
+	assert.Equal(t, int64(unsafe.Sizeof(*s)), empty)
+
+	s.Add("a")
+	s.Add("bcd")
+
+	assert.Equal(t, empty+16+1+16+3, s.EstimateBytes())
+	assert.Equal(t, s.EstimateBytes(), m.Snapshot().Bytes)
+	assert.Equal(t, uint64(3), m.Snapshot().Operations["estimateBytes"])
+
+	type user struct {
+		Name string
+		Tags []string
+	}
+
+	users := NewWithOptions[*user](WithSizer(func(u *user) int {
+		return int(unsafe.Sizeof(*u)) + len(u.Name) + 16*len(u.Tags)
+	}))
+	empty = users.EstimateBytes()
+
+	users.Add(&user{Name: "ab", Tags: []string{"x"}})
+
+	assert.Equal(t, empty+8+40+2+16, users.EstimateBytes())
+
+	// In slab mode, the headers of the blocks are counted too.
+	slabs := NewWithOptions[string](WithSlabs[string](2))
+	empty = slabs.EstimateBytes()
+
+	slabs.Add("a")
+	slabs.Add("b")
+	slabs.Add("c")
+
+	// Two blocks of two elements, one unused.
+	assert.Equal(t, empty+2*24+4*16+3, slabs.EstimateBytes())
+}
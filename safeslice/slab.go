@@ -0,0 +1,421 @@
+package safeslice
+
+import "sync/atomic"
+
+//////
+// Const, vars, and types.
+//////
+
+// DefaultSlabSize is the number of elements per slab when the given one isn't
+// positive.
+const DefaultSlabSize = 4096
+
+// store holds the elements of a slice. By default, in a single contiguous
+// block. In slab mode, see WithSlabs, in blocks of up to size elements, so
+// growing never reallocates, nor copies, the existing elements, and inserting,
+// or deleting, only shifts the elements of one block.
+type store[T any] struct {
+	// n is first, to be 64-bit aligned for atomic operations. It's updated
+	// holding the write lock of the slice, and read without the lock by
+	// loadLen.
+	n int64
+
+	blocks [][]T
+
+	// size is the maximum number of elements per block, 0 if contiguous.
+	size int
+
+	// tracker, if set, is notified of the elements added, and removed.
+	tracker tracker[T]
+}
+
+// tracker is notified of the changes of a store, e.g.: to maintain
+// statistics incrementally, see Numeric.
+type tracker[T any] interface {
+	// add is called with an element added.
+	add(item T)
+
+	// remove is called with an element removed.
+	remove(item T)
+
+	// reset is called with the elements replacing all the previous ones.
+	reset(items []T)
+}
+
+// cursor iterates over the elements of a store, in order.
+type cursor[T any] struct {
+	blocks [][]T
+
+	block  int
+	offset int
+	index  int
+}
+
+//////
+// Cursor.
+//////
+
+// next advances to the next element, returning false after the last one.
+func (c *cursor[T]) next() bool {
+	c.offset++
+	c.index++
+
+	for c.block < len(c.blocks) && c.offset >= len(c.blocks[c.block]) {
+		c.block++
+		c.offset = 0
+	}
+
+	return c.block < len(c.blocks)
+}
+
+// value returns the current element.
+func (c *cursor[T]) value() T {
+	return c.blocks[c.block][c.offset]
+}
+
+//////
+// Store.
+//////
+
+// cursor returns a cursor positioned before the first element.
+func (st *store[T]) cursor() *cursor[T] {
+	return &cursor[T]{blocks: st.blocks, offset: -1, index: -1}
+}
+
+// len returns the number of elements. Caller must hold the lock.
+func (st *store[T]) len() int {
+	return int(st.n)
+}
+
+// loadLen returns the number of elements, atomically, without the lock.
+func (st *store[T]) loadLen() int {
+	return int(atomic.LoadInt64(&st.n))
+}
+
+// locate returns the block, and the offset in it, of the element at index i,
+// which must be in range.
+func (st *store[T]) locate(i int) (int, int) {
+	if st.size == 0 {
+		return 0, i
+	}
+
+	// Blocks before the last one are usually full, scanning from the closest
+	// end halves the walk.
+	if i < st.len()/2 {
+		for b, block := range st.blocks {
+			if i < len(block) {
+				return b, i
+			}
+
+			i -= len(block)
+		}
+	}
+
+	fromEnd := st.len() - i
+
+	for b := len(st.blocks) - 1; ; b-- {
+		if fromEnd <= len(st.blocks[b]) {
+			return b, len(st.blocks[b]) - fromEnd
+		}
+
+		fromEnd -= len(st.blocks[b])
+	}
+}
+
+// at returns the element at index i, which must be in range.
+func (st *store[T]) at(i int) T {
+	b, j := st.locate(i)
+
+	return st.blocks[b][j]
+}
+
+// first returns the first element, which must exist.
+func (st *store[T]) first() T {
+	return st.at(0)
+}
+
+// last returns the last element, which must exist.
+func (st *store[T]) last() T {
+	return st.at(st.len() - 1)
+}
+
+// push appends the element. In slab mode, a new block is allocated when the
+// last one is full.
+func (st *store[T]) push(item T) {
+	last := len(st.blocks) - 1
+
+	switch {
+	case last < 0:
+		st.blocks = append(st.blocks, st.newBlock())
+		last = 0
+	case st.size > 0 && len(st.blocks[last]) >= st.size:
+		st.blocks = append(st.blocks, st.newBlock())
+		last++
+	}
+
+	st.blocks[last] = append(st.blocks[last], item)
+
+	atomic.AddInt64(&st.n, 1)
+
+	if st.tracker != nil {
+		st.tracker.add(item)
+	}
+}
+
+// insert inserts the element at index i, in [0, len]. In slab mode, a full
+// block is split in half first.
+func (st *store[T]) insert(i int, item T) {
+	if i == st.len() {
+		st.push(item)
+
+		return
+	}
+
+	b, j := st.locate(i)
+
+	if st.size > 0 && len(st.blocks[b]) >= st.size {
+		st.split(b)
+
+		if half := len(st.blocks[b]); j > half {
+			b, j = b+1, j-half
+		}
+	}
+
+	block := append(st.blocks[b], *new(T))
+
+	copy(block[j+1:], block[j:])
+
+	block[j] = item
+
+	st.blocks[b] = block
+
+	atomic.AddInt64(&st.n, 1)
+
+	if st.tracker != nil {
+		st.tracker.add(item)
+	}
+}
+
+// split moves the second half of the block b into a new block after it.
+func (st *store[T]) split(b int) {
+	block := st.blocks[b]
+	half := len(block) / 2
+
+	right := append(st.newBlock(), block[half:]...)
+
+	zero(block[half:])
+
+	st.blocks[b] = block[:half]
+
+	st.blocks = append(st.blocks, nil)
+
+	copy(st.blocks[b+2:], st.blocks[b+1:])
+
+	st.blocks[b+1] = right
+}
+
+// remove removes, and returns, the element at index i, which must be in range.
+// The first element of a block is removed by advancing its start, in O(1),
+// the space is reclaimed when appending reallocates the block. In slab mode,
+// an emptied block is released.
+func (st *store[T]) remove(i int) T {
+	b, j := st.locate(i)
+	block := st.blocks[b]
+	item := block[j]
+
+	if j == 0 {
+		zero(block[:1])
+
+		st.blocks[b] = block[1:]
+	} else {
+		copy(block[j:], block[j+1:])
+
+		zero(block[len(block)-1:])
+
+		st.blocks[b] = block[:len(block)-1]
+	}
+
+	atomic.AddInt64(&st.n, -1)
+
+	if st.size > 0 && len(st.blocks[b]) == 0 {
+		copy(st.blocks[b:], st.blocks[b+1:])
+
+		zero(st.blocks[len(st.blocks)-1:])
+
+		st.blocks = st.blocks[:len(st.blocks)-1]
+	}
+
+	if st.tracker != nil {
+		st.tracker.remove(item)
+	}
+
+	return item
+}
+
+// filter keeps, in order, the elements for which keep returns true, returning
+// how many were removed. Removed elements are cleared, so they can be garbage
+// collected.
+func (st *store[T]) filter(keep func(item T) bool) int {
+	removed := 0
+	blocks := st.blocks[:0]
+
+	for _, block := range st.blocks {
+		kept := block[:0]
+
+		for _, item := range block {
+			if keep(item) {
+				kept = append(kept, item)
+			} else if st.tracker != nil {
+				st.tracker.remove(item)
+			}
+		}
+
+		zero(block[len(kept):])
+
+		removed += len(block) - len(kept)
+
+		if len(kept) > 0 || st.size == 0 {
+			blocks = append(blocks, kept)
+		}
+	}
+
+	zero(st.blocks[len(blocks):])
+
+	st.blocks = blocks
+
+	atomic.AddInt64(&st.n, -int64(removed))
+
+	return removed
+}
+
+// reset replaces the elements. If contiguous, the given slice is used as is.
+func (st *store[T]) reset(items []T) {
+	atomic.StoreInt64(&st.n, int64(len(items)))
+
+	if st.tracker != nil {
+		st.tracker.reset(items)
+	}
+
+	if st.size == 0 {
+		st.blocks = nil
+
+		if items != nil {
+			st.blocks = [][]T{items}
+		}
+
+		return
+	}
+
+	st.blocks = make([][]T, 0, (len(items)+st.size-1)/st.size)
+
+	for len(items) > 0 {
+		n := st.size
+		if len(items) < n {
+			n = len(items)
+		}
+
+		st.blocks = append(st.blocks, append(st.newBlock(), items[:n]...))
+
+		items = items[n:]
+	}
+}
+
+// values returns a copy of the elements.
+func (st *store[T]) values() []T {
+	values := make([]T, 0, st.len())
+
+	for _, block := range st.blocks {
+		values = append(values, block...)
+	}
+
+	return values
+}
+
+// slice returns the elements as a slice: the block itself if contiguous,
+// otherwise a copy.
+func (st *store[T]) slice() []T {
+	if st.size == 0 {
+		if len(st.blocks) == 0 {
+			return nil
+		}
+
+		return st.blocks[0]
+	}
+
+	return st.values()
+}
+
+// reorder calls f, which reorders the elements in place. In slab mode, the
+// elements are copied into a contiguous slice, and back.
+func (st *store[T]) reorder(f func(data []T)) {
+	if st.size == 0 {
+		f(st.slice())
+
+		return
+	}
+
+	values := st.values()
+
+	f(values)
+
+	st.reset(values)
+}
+
+// capacity returns the number of elements which fit without allocating.
+func (st *store[T]) capacity() int {
+	total := 0
+
+	for _, block := range st.blocks {
+		total += cap(block)
+	}
+
+	return total
+}
+
+// newBlock allocates an empty block, with room for a whole slab in slab mode.
+func (st *store[T]) newBlock() []T {
+	if st.size == 0 {
+		return nil
+	}
+
+	return make([]T, 0, st.size)
+}
+
+//////
+// Helpers.
+//////
+
+// zero sets the elements to their zero value, so what they reference can be
+// garbage collected.
+func zero[E any](s []E) {
+	var z E
+
+	for i := range s {
+		s[i] = z
+	}
+}
+
+//////
+// Factory.
+//////
+
+// WithSlabs enables slab mode: elements are stored in blocks of up to size
+// elements, or DefaultSlabSize if not positive, instead of a single
+// contiguous array. Growing never reallocates, nor copies, the existing
+// elements, and Delete, Remove, SortedInsert, and evictions only shift the
+// elements of one block, at the cost of O(blocks) access by index. Meant for
+// slices of millions of elements. Reordering, e.g.: SortWith, and ToSlice,
+// copy the elements into a contiguous slice.
+func WithSlabs[T comparable](size int) Option[T] {
+	return func(s *SafeSlice[T]) {
+		if size <= 0 {
+			size = DefaultSlabSize
+		}
+
+		values := s.data.values()
+
+		s.data.size = size
+
+		s.data.reset(values)
+	}
+}
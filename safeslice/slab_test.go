@@ -0,0 +1,191 @@
+package safeslice
+
+import (
+	"encoding/json"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/thalesfsp/go-common-types/shared"
+)
+
+func TestStoreMatchesSlice(t *testing.T) {
+	for _, size := range []int{0, 1, 4} {
+		st := store[int]{size: size}
+		model := []int{}
+
+		rng := rand.New(rand.NewSource(1))
+
+		for op := 0; op < 5_000; op++ {
+			switch r := rng.Intn(10); {
+			case r < 4:
+				st.push(op)
+				model = append(model, op)
+			case r < 6:
+				i := rng.Intn(len(model) + 1)
+
+				st.insert(i, op)
+				model = append(model[:i], append([]int{op}, model[i:]...)...)
+			case r < 9 && len(model) > 0:
+				i := rng.Intn(len(model))
+
+				assert.Equal(t, model[i], st.remove(i))
+
+				model = append(model[:i], model[i+1:]...)
+			case r == 9:
+				st.filter(func(item int) bool { return item%7 != 0 })
+
+				kept := []int{}
+
+				for _, item := range model {
+					if item%7 != 0 {
+						kept = append(kept, item)
+					}
+				}
+
+				model = kept
+			}
+
+			for _, block := range st.blocks {
+				if size > 0 && (len(block) == 0 || len(block) > size) {
+					t.Fatalf("size %d: block of %d elements", size, len(block))
+				}
+			}
+		}
+
+		assert.Equal(t, len(model), st.len())
+		assert.Equal(t, model, st.values())
+
+		for i, item := range model {
+			assert.Equal(t, item, st.at(i))
+		}
+
+		for c := st.cursor(); c.next(); {
+			assert.Equal(t, model[c.index], c.value())
+		}
+	}
+}
+
+func TestWithSlabs(t *testing.T) {
+	s := NewWithOptions[int](WithSlabs[int](3))
+
+	for i := 0; i < 10; i++ {
+		s.Add(i)
+	}
+
+	assert.Equal(t, 4, len(s.data.blocks))
+	assert.Equal(t, 10, s.Size())
+	assert.Equal(t, 7, s.Get(7))
+	assert.Equal(t, []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}, s.ToSlice())
+
+	s.Delete(0)
+	s.Delete(0)
+	s.Delete(0)
+
+	// The emptied slab is released.
+	assert.Equal(t, 3, len(s.data.blocks))
+
+	assert.Equal(t, 1, s.RemoveFunc(func(v int) bool { return v == 5 }))
+	assert.Equal(t, 2, s.SortedInsert(5, shared.CompareOrdered[int]().Compare))
+	assert.Equal(t, []int{3, 4, 5, 6, 7, 8, 9}, s.Values())
+
+	i, found := s.BinarySearch(8, shared.CompareOrdered[int]().Compare)
+	assert.True(t, found)
+	assert.Equal(t, 5, i)
+
+	s.SortWith(shared.CompareBy(func(v int) int { return -v }))
+
+	assert.Equal(t, []int{9, 8, 7, 6, 5, 4, 3}, s.Values())
+	assert.Equal(t, 3, s.FindLast(func(v int) bool { return v < 5 }))
+
+	b, err := json.Marshal(s)
+	assert.NoError(t, err)
+	assert.Equal(t, "[9,8,7,6,5,4,3]", string(b))
+
+	assert.NoError(t, json.Unmarshal([]byte("[1,2,3,4]"), s))
+	assert.Equal(t, 2, len(s.data.blocks))
+	assert.Equal(t, []int{1, 2, 3, 4}, s.Values())
+
+	// Options applied to a non-empty slice keep its elements.
+	existing := New(1, 2, 3)
+
+	WithSlabs[int](0)(existing)
+
+	assert.Equal(t, DefaultSlabSize, existing.data.size)
+	assert.Equal(t, []int{1, 2, 3}, existing.Values())
+}
+
+func TestWithSlabsBounded(t *testing.T) {
+	s := NewBounded(5, DropOldest, WithSlabs[int](2))
+
+	for i := 0; i < 12; i++ {
+		s.Add(i)
+	}
+
+	assert.Equal(t, []int{7, 8, 9, 10, 11}, s.Values())
+	assert.LessOrEqual(t, len(s.data.blocks), 4)
+}
+
+func TestBoundedDropOldestCapacity(t *testing.T) {
+	s := NewBounded[int](1000, DropOldest)
+
+	for i := 0; i < 100_000; i++ {
+		s.Add(i)
+	}
+
+	assert.Equal(t, 1000, s.Size())
+	assert.Equal(t, 99_000, s.data.first())
+	assert.Equal(t, 99_999, s.data.last())
+
+	// Evicted elements are reclaimed when the block is reallocated.
+	assert.LessOrEqual(t, s.data.capacity(), 3000)
+}
+
+func BenchmarkBoundedDropOldest(b *testing.B) {
+	for _, bench := range []struct {
+		name string
+		opts []Option[int]
+	}{
+		{"contiguous", nil},
+		{"slabs", []Option[int]{WithSlabs[int](DefaultSlabSize)}},
+	} {
+		b.Run(bench.name, func(b *testing.B) {
+			s := NewBounded(1_000_000, DropOldest, bench.opts...)
+
+			for i := 0; i < 1_000_000; i++ {
+				s.Add(i)
+			}
+
+			b.ResetTimer()
+
+			for i := 0; i < b.N; i++ {
+				s.Add(i)
+			}
+		})
+	}
+}
+
+func BenchmarkDeleteFront(b *testing.B) {
+	for _, bench := range []struct {
+		name string
+		opts []Option[int]
+	}{
+		{"contiguous", nil},
+		{"slabs", []Option[int]{WithSlabs[int](DefaultSlabSize)}},
+	} {
+		b.Run(bench.name, func(b *testing.B) {
+			s := NewWithOptions(bench.opts...)
+
+			for i := 0; i < 1_000_000; i++ {
+				s.Add(i)
+			}
+
+			b.ResetTimer()
+
+			for i := 0; i < b.N; i++ {
+				s.Delete(0)
+				s.Add(i)
+			}
+		})
+	}
+}
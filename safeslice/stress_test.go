@@ -0,0 +1,181 @@
+package safeslice
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// stressGoroutines is the number of goroutines of the stress tests, fewer with
+// -short.
+func stressGoroutines() int {
+	if testing.Short() {
+		return 32
+	}
+
+	return 256
+}
+
+// TestStress runs mixed operations from hundreds of goroutines. Run it with
+// -race. Each element must be removed at most once, and none lost.
+func TestStress(t *testing.T) {
+	const (
+		initial = 500
+		ops     = 20
+	)
+
+	for _, opts := range [][]Option[int]{nil, {WithSlabs[int](64)}} {
+		n := stressGoroutines()
+		s := NewWithOptions(opts...)
+
+		for i := 0; i < initial; i++ {
+			s.Add(i)
+		}
+
+		var (
+			wg      sync.WaitGroup
+			removed = make([]int64, initial+n*ops)
+		)
+
+		for g := 0; g < n; g++ {
+			wg.Add(1)
+
+			go func(g int) {
+				defer wg.Done()
+
+				for i := 0; i < ops; i++ {
+					s.Append(initial + g*ops + i)
+
+					// Goroutines race to remove the same elements.
+					if item := (g*7 + i*13) % initial; s.Remove(item) {
+						atomic.AddInt64(&removed[item], 1)
+					}
+
+					switch i % 5 {
+					case 0:
+						_ = s.Values()
+					case 1:
+						_, _ = s.MarshalJSON()
+					case 2:
+						s.Swap(i, g%initial)
+					case 3:
+						_ = s.Contains(g)
+					default:
+						_ = s.Get(s.Size() - 1)
+					}
+				}
+			}(g)
+		}
+
+		wg.Wait()
+
+		total := int64(0)
+
+		for item, count := range removed {
+			if count > 1 {
+				t.Errorf("%d removed %d times", item, count)
+			}
+
+			total += count
+		}
+
+		values := s.Values()
+
+		assert.Equal(t, initial+n*ops-int(total), s.Size())
+		assert.Len(t, values, s.Size())
+
+		for _, item := range values {
+			if removed[item] != 0 {
+				t.Errorf("%d present after being removed", item)
+			}
+		}
+	}
+}
+
+// TestStressCrossSubset checks slices against each other, in both directions,
+// while they change. Holding the lock of both slices would deadlock.
+func TestStressCrossSubset(t *testing.T) {
+	const values = 50
+
+	a, b := New[int](), New[int]()
+
+	for i := 0; i < values; i++ {
+		a.Add(i)
+		b.Add(i)
+	}
+
+	n := stressGoroutines()
+
+	var wg sync.WaitGroup
+
+	for g := 0; g < n; g++ {
+		wg.Add(1)
+
+		go func(g int) {
+			defer wg.Done()
+
+			this, other := a, b
+			if g%2 == 0 {
+				this, other = b, a
+			}
+
+			for i := 0; i < 20; i++ {
+				v := values + (g+i)%values
+
+				this.Append(v)
+				_ = this.Subset(other)
+				_ = other.Superset(this)
+				this.Remove(v)
+			}
+		}(g)
+	}
+
+	wg.Wait()
+
+	assert.True(t, a.Subset(b))
+	assert.True(t, b.Subset(a))
+}
+
+// TestStressReadersWithWriter runs readers while a writer waits for the lock.
+// A reader acquiring the read lock twice would deadlock with the writer.
+func TestStressReadersWithWriter(t *testing.T) {
+	const values = 1000
+
+	// Distinct elements, for Mode to return all of them.
+	s := New[int]()
+
+	for i := 0; i < values; i++ {
+		s.Add(i)
+	}
+
+	n := stressGoroutines()
+
+	var wg sync.WaitGroup
+
+	for g := 0; g < n; g++ {
+		wg.Add(1)
+
+		go func(g int) {
+			defer wg.Done()
+
+			for i := 0; i < 50; i++ {
+				if g%2 == 0 {
+					s.Append(values + i)
+					s.Remove(values + i)
+
+					continue
+				}
+
+				_ = s.LastN(2)
+				_ = s.Mode()
+			}
+		}(g)
+	}
+
+	wg.Wait()
+
+	assert.Len(t, s.Mode(), values)
+	assert.Equal(t, []int{values - 2, values - 1}, s.LastN(2).Values())
+}
@@ -0,0 +1,66 @@
+package safeslice
+
+import (
+	"context"
+
+	"github.com/thalesfsp/go-common-types/shared"
+)
+
+//////
+// Const, vars, and types.
+//////
+
+// Event is a change of the slice.
+type Event[T comparable] struct {
+	// Op is the type of the change.
+	Op shared.Op `json:"op"`
+
+	// Index of the changed element, at the time of the change. 0 for clear.
+	Index int `json:"index"`
+
+	// Old is the previous value, for update, and delete.
+	Old T `json:"old"`
+
+	// New is the new value, for add, and update.
+	New T `json:"new"`
+}
+
+//////
+// Methods.
+//////
+
+// Watch returns a channel receiving, in order, every change of the slice made
+// after the call, until the context is done, then the channel is closed.
+// Replacing the whole slice, e.g.: UnmarshalJSON, is a clear followed by adds.
+//
+// Mutations never block on watchers: events are queued until received, so a
+// watcher which stops receiving should cancel its context.
+func (s *SafeSlice[T]) Watch(ctx context.Context) <-chan Event[T] {
+	return s.watchers.Subscribe(ctx)
+}
+
+// WaitFor blocks until the slice has an element satisfying the predicate,
+// returning it, or until the context is done, returning its error. Elements
+// already in the slice are checked first, then every added, or updated one,
+// without polling.
+func (s *SafeSlice[T]) WaitFor(ctx context.Context, predicate func(T) bool) (T, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// Subscribing first, so elements added while checking aren't missed.
+	events := s.Watch(ctx)
+
+	for _, item := range s.Values() {
+		if predicate(item) {
+			return item, nil
+		}
+	}
+
+	for e := range events {
+		if (e.Op == shared.OpAdd || e.Op == shared.OpUpdate) && predicate(e.New) {
+			return e.New, nil
+		}
+	}
+
+	return *new(T), ctx.Err()
+}
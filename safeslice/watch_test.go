@@ -0,0 +1,94 @@
+package safeslice
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/thalesfsp/go-common-types/shared"
+)
+
+func TestSafeSliceWatch(t *testing.T) {
+	s := New(1)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	events := s.Watch(ctx)
+
+	s.Add(2).Delete(0).Delete(5)
+	s.Append(3, 4)
+
+	assert.NoError(t, s.UnmarshalJSON([]byte(`[5]`)))
+
+	expected := []Event[int]{
+		{Op: shared.OpAdd, Index: 1, New: 2},
+		{Op: shared.OpDelete, Index: 0, Old: 1},
+		{Op: shared.OpAdd, Index: 1, New: 3},
+		{Op: shared.OpAdd, Index: 2, New: 4},
+		{Op: shared.OpClear},
+		{Op: shared.OpAdd, Index: 0, New: 5},
+	}
+
+	for _, e := range expected {
+		assert.Equal(t, e, <-events)
+	}
+
+	cancel()
+
+	for range events {
+	}
+}
+
+func TestSafeSliceWatchReorder(t *testing.T) {
+	s := New(1, 2, 3)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	events := s.Watch(ctx)
+
+	s.Swap(0, 2)
+
+	assert.Equal(t, Event[int]{Op: shared.OpUpdate, Index: 0, Old: 1, New: 3}, <-events)
+	assert.Equal(t, Event[int]{Op: shared.OpUpdate, Index: 2, Old: 3, New: 1}, <-events)
+}
+
+func TestSafeSliceWatchRemove(t *testing.T) {
+	s := New(1, 2, 1, 3)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	events := s.Watch(ctx)
+
+	s.RemoveAll(1)
+
+	assert.Equal(t, Event[int]{Op: shared.OpDelete, Index: 0, Old: 1}, <-events)
+	assert.Equal(t, Event[int]{Op: shared.OpDelete, Index: 1, Old: 1}, <-events)
+}
+
+func TestSafeSliceWaitFor(t *testing.T) {
+	s := New(1)
+
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+
+		s.Add(2).Add(10)
+	}()
+
+	value, err := s.WaitFor(context.Background(), func(v int) bool { return v > 5 })
+	assert.NoError(t, err)
+	assert.Equal(t, 10, value)
+
+	value, err = s.WaitFor(context.Background(), func(v int) bool { return v == 1 })
+	assert.NoError(t, err)
+	assert.Equal(t, 1, value)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err = s.WaitFor(ctx, func(v int) bool { return v > 100 })
+	assert.ErrorIs(t, err, context.Canceled)
+}
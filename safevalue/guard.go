@@ -0,0 +1,101 @@
+package safevalue
+
+import (
+	"encoding/json"
+	"sync"
+)
+
+//////
+// Const, vars, and types.
+//////
+
+// Guard is a value protected by a RWMutex, accessed only through closures, so
+// the lock can't be forgotten, e.g.: a struct updated field by field. The
+// zero value holds the zero value of T, and is ready to use.
+type Guard[T any] struct {
+	mu sync.RWMutex
+
+	value T
+}
+
+//////
+// Methods.
+//////
+
+// With calls f with a pointer to the value, holding the write lock. The
+// pointer must not be retained after f returns.
+func (g *Guard[T]) With(f func(value *T)) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	f(&g.value)
+}
+
+// RWith calls f with the value, holding the read lock. f must not modify what
+// the value references, e.g.: the elements of a slice, or a map.
+func (g *Guard[T]) RWith(f func(value T)) {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	f(g.value)
+}
+
+// Load returns a copy of the value. What the value references, e.g.: the
+// elements of a slice, isn't copied.
+func (g *Guard[T]) Load() T {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	return g.value
+}
+
+// Store sets the value.
+func (g *Guard[T]) Store(value T) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.value = value
+}
+
+// MarshalJSON implements json.Marshaler.
+func (g *Guard[T]) MarshalJSON() ([]byte, error) {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	return json.Marshal(g.value)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (g *Guard[T]) UnmarshalJSON(data []byte) error {
+	var value T
+
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+
+	g.Store(value)
+
+	return nil
+}
+
+//////
+// Exported functionalities.
+//////
+
+// Read calls f with the value of the guard, holding the read lock, returning
+// its result, e.g.: to compute a derived value without copying the value.
+func Read[T, R any](g *Guard[T], f func(value T) R) R {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	return f(g.value)
+}
+
+//////
+// Factory.
+//////
+
+// NewGuard creates a new Guard holding the given value.
+func NewGuard[T any](value T) *Guard[T] {
+	return &Guard[T]{value: value}
+}
@@ -0,0 +1,57 @@
+package safevalue
+
+import (
+	"encoding/json"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type account struct {
+	Balance int `json:"balance"`
+	Ops     int `json:"ops"`
+}
+
+func TestGuard(t *testing.T) {
+	g := NewGuard(account{})
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			g.With(func(a *account) {
+				a.Balance += 10
+				a.Ops++
+			})
+		}()
+	}
+
+	wg.Wait()
+
+	assert.Equal(t, account{Balance: 500, Ops: 50}, g.Load())
+
+	g.RWith(func(a account) {
+		assert.Equal(t, 50, a.Ops)
+	})
+
+	assert.Equal(t, 10, Read(g, func(a account) int { return a.Balance / a.Ops }))
+
+	g.Store(account{})
+
+	assert.Equal(t, account{}, g.Load())
+}
+
+func TestGuardJSON(t *testing.T) {
+	var g Guard[account]
+
+	assert.NoError(t, json.Unmarshal([]byte(`{"balance":5,"ops":1}`), &g))
+
+	b, err := json.Marshal(&g)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"balance":5,"ops":1}`, string(b))
+}
@@ -0,0 +1,95 @@
+package safevalue
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+//////
+// Const, vars, and types.
+//////
+
+// Lazy is a value computed once, on first use, safe for concurrent use, e.g.:
+// an expensive client, or a parsed configuration. Unlike sync.Once, a failed
+// computation is retried by the next Get.
+type Lazy[T any] struct {
+	// done is first, to be 64-bit aligned for atomic operations.
+	done uint32
+
+	mu sync.Mutex
+
+	f     func() (T, error)
+	value T
+}
+
+//////
+// Methods.
+//////
+
+// Get returns the value, computing it if it's the first call, or the previous
+// ones failed. Concurrent calls wait for the computation.
+func (l *Lazy[T]) Get() (T, error) {
+	if atomic.LoadUint32(&l.done) == 1 {
+		return l.value, nil
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.done == 1 {
+		return l.value, nil
+	}
+
+	value, err := l.f()
+	if err != nil {
+		return *new(T), err
+	}
+
+	l.value = value
+
+	atomic.StoreUint32(&l.done, 1)
+
+	return value, nil
+}
+
+// MustGet is like Get, panicking if the computation fails.
+func (l *Lazy[T]) MustGet() T {
+	value, err := l.Get()
+	if err != nil {
+		panic(err)
+	}
+
+	return value
+}
+
+// Loaded checks if the value was computed.
+func (l *Lazy[T]) Loaded() bool {
+	return atomic.LoadUint32(&l.done) == 1
+}
+
+// Reset forgets the value, so the next Get computes it again.
+func (l *Lazy[T]) Reset() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	atomic.StoreUint32(&l.done, 0)
+
+	l.value = *new(T)
+}
+
+//////
+// Factory.
+//////
+
+// NewLazy creates a new Lazy computing its value with f.
+func NewLazy[T any](f func() (T, error)) *Lazy[T] {
+	return &Lazy[T]{f: f}
+}
+
+// NewLazyValue creates a new Lazy computing its value with f, which can't
+// fail.
+func NewLazyValue[T any](f func() T) *Lazy[T] {
+	return NewLazy(func() (T, error) {
+		return f(), nil
+	})
+}
@@ -0,0 +1,67 @@
+package safevalue
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLazy(t *testing.T) {
+	var calls int64
+
+	l := NewLazyValue(func() int {
+		atomic.AddInt64(&calls, 1)
+
+		return 42
+	})
+
+	assert.False(t, l.Loaded())
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			assert.Equal(t, 42, l.MustGet())
+		}()
+	}
+
+	wg.Wait()
+
+	assert.True(t, l.Loaded())
+	assert.Equal(t, int64(1), calls)
+
+	l.Reset()
+
+	assert.Equal(t, 42, l.MustGet())
+	assert.Equal(t, int64(2), calls)
+}
+
+func TestLazyRetries(t *testing.T) {
+	fail := true
+
+	l := NewLazy(func() (string, error) {
+		if fail {
+			return "", errors.New("unavailable")
+		}
+
+		return "ok", nil
+	})
+
+	_, err := l.Get()
+	assert.Error(t, err)
+	assert.False(t, l.Loaded())
+	assert.Panics(t, func() { l.MustGet() })
+
+	fail = false
+
+	value, err := l.Get()
+	assert.NoError(t, err)
+	assert.Equal(t, "ok", value)
+}
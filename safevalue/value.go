@@ -0,0 +1,113 @@
+package safevalue
+
+import (
+	"encoding/json"
+	"sync/atomic"
+)
+
+//////
+// Const, vars, and types.
+//////
+
+// Value is a value of type T, loaded, and stored, atomically, i.e.: a generic
+// atomic.Value. Unlike atomic.Value, it can store any value of T, including
+// nil, and interfaces with different dynamic types. The zero value holds the
+// zero value of T, and is ready to use.
+type Value[T any] struct {
+	p atomic.Pointer[T]
+}
+
+//////
+// Methods.
+//////
+
+// Load returns the value.
+func (v *Value[T]) Load() T {
+	return deref(v.p.Load())
+}
+
+// Store sets the value.
+func (v *Value[T]) Store(value T) {
+	v.p.Store(&value)
+}
+
+// Swap sets the value, returning the previous one.
+func (v *Value[T]) Swap(value T) T {
+	return deref(v.p.Swap(&value))
+}
+
+// CompareAndSwap sets the value to new only if it's equal to old, as with ==,
+// returning whether it was set. Like atomic.Value, it panics if the values
+// aren't comparable.
+func (v *Value[T]) CompareAndSwap(old, new T) bool {
+	for {
+		p := v.p.Load()
+
+		if any(deref(p)) != any(old) {
+			return false
+		}
+
+		if v.p.CompareAndSwap(p, &new) {
+			return true
+		}
+	}
+}
+
+// Update sets the value to the result of f, called with the current one,
+// retrying if it changed concurrently, and returns the new value. f may be
+// called more than once, so it must not have side effects.
+func (v *Value[T]) Update(f func(current T) T) T {
+	for {
+		p := v.p.Load()
+
+		value := f(deref(p))
+
+		if v.p.CompareAndSwap(p, &value) {
+			return value
+		}
+	}
+}
+
+// MarshalJSON implements json.Marshaler.
+func (v *Value[T]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(v.Load())
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (v *Value[T]) UnmarshalJSON(data []byte) error {
+	var value T
+
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+
+	v.Store(value)
+
+	return nil
+}
+
+//////
+// Helpers.
+//////
+
+// deref returns the value p points to, the zero value of T if nil.
+func deref[T any](p *T) T {
+	if p == nil {
+		return *new(T)
+	}
+
+	return *p
+}
+
+//////
+// Factory.
+//////
+
+// NewValue creates a new Value holding the given value.
+func NewValue[T any](value T) *Value[T] {
+	v := &Value[T]{}
+
+	v.Store(value)
+
+	return v
+}
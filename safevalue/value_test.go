@@ -0,0 +1,72 @@
+package safevalue
+
+import (
+	"encoding/json"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValue(t *testing.T) {
+	var v Value[int]
+
+	assert.Equal(t, 0, v.Load())
+
+	v.Store(1)
+
+	assert.Equal(t, 1, v.Swap(2))
+	assert.False(t, v.CompareAndSwap(1, 3))
+	assert.True(t, v.CompareAndSwap(2, 3))
+	assert.Equal(t, 3, v.Load())
+
+	// The zero value compares equal to an empty Value.
+	var empty Value[string]
+
+	assert.True(t, empty.CompareAndSwap("", "a"))
+	assert.Equal(t, "a", empty.Load())
+}
+
+func TestValueInterfaces(t *testing.T) {
+	// Unlike atomic.Value, different dynamic types, and nil, can be stored.
+	v := NewValue[error](errors.New("a"))
+
+	v.Store(&json.SyntaxError{})
+	v.Store(nil)
+
+	assert.Nil(t, v.Load())
+}
+
+func TestValueUpdate(t *testing.T) {
+	v := NewValue(0)
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for j := 0; j < 100; j++ {
+				v.Update(func(n int) int { return n + 1 })
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	assert.Equal(t, 5_000, v.Load())
+}
+
+func TestValueJSON(t *testing.T) {
+	v := NewValue([]string{"a"})
+
+	b, err := json.Marshal(v)
+	assert.NoError(t, err)
+	assert.Equal(t, `["a"]`, string(b))
+
+	assert.NoError(t, json.Unmarshal([]byte(`["b"]`), v))
+	assert.Equal(t, []string{"b"}, v.Load())
+}
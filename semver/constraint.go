@@ -0,0 +1,369 @@
+package semver
+
+import (
+	"fmt"
+	"strings"
+)
+
+//////
+// Const, vars, and types.
+//////
+
+// comparator is a version compared with an operator, e.g.: ">=1.2.0".
+type comparator struct {
+	op      string
+	version Version
+}
+
+// Constraint is a set of version ranges, e.g.: "^1.2.0", ">=1.2 <2", or
+// "~1.2 || ^2". Comparators separated by spaces, or commas, must all match,
+// ranges separated by "||" are alternatives.
+//
+// The operators are =, !=, >, >=, <, <=, ~ (patch updates, e.g.: ~1.2.3 is
+// >=1.2.3 <1.3.0), ^ (updates not changing the leftmost non-zero number,
+// e.g.: ^1.2.3 is >=1.2.3 <2.0.0, and ^0.2.3 is >=0.2.3 <0.3.0), wildcards,
+// e.g.: 1.2.x, or *, and hyphen ranges, e.g.: 1.2 - 1.4. Versions can be
+// partial, e.g.: 1.2 is 1.2.x.
+//
+// Like npm, a pre-release version only matches a range with a comparator
+// having a pre-release of the same major, minor, and patch, e.g.: 1.3.0-beta
+// matches >=1.3.0-alpha, not >=1.2.0.
+type Constraint struct {
+	text   string
+	ranges [][]comparator
+}
+
+// partial is a version with some numbers missing, e.g.: "1.2", or "1.x".
+type partial struct {
+	version Version
+
+	// n is the number of numbers present, from 0 for "*", to 3.
+	n int
+}
+
+//////
+// Methods.
+//////
+
+// String is the stringer implementation, returning the constraint as parsed.
+func (c Constraint) String() string {
+	return c.text
+}
+
+// Check checks if the version satisfies the constraint.
+func (c Constraint) Check(v Version) bool {
+	for _, r := range c.ranges {
+		if matches(r, v) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Filter returns the versions satisfying the constraint, in order.
+func (c Constraint) Filter(versions []Version) []Version {
+	matching := []Version{}
+
+	for _, v := range versions {
+		if c.Check(v) {
+			matching = append(matching, v)
+		}
+	}
+
+	return matching
+}
+
+// Latest returns the highest version satisfying the constraint, false if
+// none does.
+func (c Constraint) Latest(versions []Version) (Version, bool) {
+	var (
+		latest Version
+		found  bool
+	)
+
+	for _, v := range versions {
+		if c.Check(v) && (!found || v.GreaterThan(latest)) {
+			latest, found = v, true
+		}
+	}
+
+	return latest, found
+}
+
+// MarshalText implements the encoding.TextMarshaler interface.
+func (c Constraint) MarshalText() ([]byte, error) {
+	return []byte(c.text), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface, see
+// ParseConstraint.
+func (c *Constraint) UnmarshalText(text []byte) error {
+	parsed, err := ParseConstraint(string(text))
+	if err != nil {
+		return err
+	}
+
+	*c = parsed
+
+	return nil
+}
+
+// next returns the lowest version above the partial version, e.g.: 1.3.0 for
+// 1.2, or 2.0.0 for 1.
+func (p partial) next() Version {
+	switch p.n {
+	case 1:
+		return p.version.IncMajor()
+	case 2:
+		return p.version.IncMinor()
+	default:
+		return p.version.IncPatch()
+	}
+}
+
+// check checks if the version satisfies the comparator.
+func (c comparator) check(v Version) bool {
+	cmp := v.Compare(c.version)
+
+	switch c.op {
+	case "!=":
+		return cmp != 0
+	case ">":
+		return cmp > 0
+	case ">=":
+		return cmp >= 0
+	case "<":
+		return cmp < 0
+	case "<=":
+		return cmp <= 0
+	default:
+		return cmp == 0
+	}
+}
+
+//////
+// Helpers.
+//////
+
+// matches checks if the version satisfies all the comparators of a range.
+func matches(r []comparator, v Version) bool {
+	allowed := !v.IsPrerelease()
+
+	for _, c := range r {
+		if !c.check(v) {
+			return false
+		}
+
+		if c.version.IsPrerelease() && c.version.Major == v.Major && c.version.Minor == v.Minor && c.version.Patch == v.Patch {
+			allowed = true
+		}
+	}
+
+	return allowed
+}
+
+// parsePartial parses a partial version, e.g.: "1.2", "1.x", or "*". A
+// pre-release, or build metadata, requires all numbers.
+func parsePartial(s string) (partial, error) {
+	s = strings.TrimPrefix(s, "v")
+
+	parts := strings.SplitN(s, ".", 3)
+
+	p := partial{}
+
+	for i, part := range parts {
+		if part == "x" || part == "X" || part == "*" {
+			break
+		}
+
+		if i == 2 {
+			v, err := Parse(s)
+			if err != nil {
+				return partial{}, err
+			}
+
+			return partial{version: v, n: 3}, nil
+		}
+
+		n, err := parseNumber(part)
+		if err != nil {
+			return partial{}, fmt.Errorf("%w %q: %w", ErrInvalid, s, err)
+		}
+
+		if i == 0 {
+			p.version.Major = n
+		} else {
+			p.version.Minor = n
+		}
+
+		p.n++
+	}
+
+	return p, nil
+}
+
+// expand returns the comparators of an operator, and a partial version.
+func expand(op string, p partial) ([]comparator, error) {
+	v := p.version
+
+	if p.n == 0 {
+		switch op {
+		case "", "=", ">=", "<=", "~", "^":
+			return nil, nil
+		default:
+			return []comparator{{op: "<", version: Version{}}}, nil
+		}
+	}
+
+	switch op {
+	case "", "=":
+		if p.n == 3 {
+			return []comparator{{op: "=", version: v}}, nil
+		}
+
+		return []comparator{{op: ">=", version: v}, {op: "<", version: p.next()}}, nil
+	case "!=":
+		if p.n != 3 {
+			return nil, fmt.Errorf("%w: != requires a full version", ErrInvalid)
+		}
+
+		return []comparator{{op: "!=", version: v}}, nil
+	case ">":
+		if p.n == 3 {
+			return []comparator{{op: ">", version: v}}, nil
+		}
+
+		return []comparator{{op: ">=", version: p.next()}}, nil
+	case ">=", "<":
+		return []comparator{{op: op, version: v}}, nil
+	case "<=":
+		if p.n == 3 {
+			return []comparator{{op: "<=", version: v}}, nil
+		}
+
+		return []comparator{{op: "<", version: p.next()}}, nil
+	case "~":
+		upper := v.IncMinor()
+		if p.n == 1 {
+			upper = v.IncMajor()
+		}
+
+		return []comparator{{op: ">=", version: v}, {op: "<", version: upper}}, nil
+	case "^":
+		var upper Version
+
+		switch {
+		case v.Major > 0 || p.n == 1:
+			upper = v.IncMajor()
+		case v.Minor > 0 || p.n == 2:
+			upper = v.IncMinor()
+		default:
+			upper = Version{Patch: v.Patch + 1}
+		}
+
+		return []comparator{{op: ">=", version: v}, {op: "<", version: upper}}, nil
+	default:
+		return nil, fmt.Errorf("%w: unknown operator %q", ErrInvalid, op)
+	}
+}
+
+// parseRange parses the comparators of a range, e.g.: ">=1.2 <2", or
+// "1.2 - 1.4".
+func parseRange(s string) ([]comparator, error) {
+	fields := strings.Fields(strings.ReplaceAll(s, ",", " "))
+
+	// Hyphen range.
+	if len(fields) == 3 && fields[1] == "-" {
+		from, err := parsePartial(fields[0])
+		if err != nil {
+			return nil, err
+		}
+
+		to, err := parsePartial(fields[2])
+		if err != nil {
+			return nil, err
+		}
+
+		lower, _ := expand(">=", from)
+		upper, _ := expand("<=", to)
+
+		return append(lower, upper...), nil
+	}
+
+	r := []comparator{}
+
+	for i := 0; i < len(fields); i++ {
+		field := fields[i]
+
+		op := field[:len(field)-len(strings.TrimLeft(field, "=!<>~^"))]
+
+		// Operators can be separated from the version, e.g.: ">= 1.2".
+		if op == field && i+1 < len(fields) {
+			i++
+
+			field += fields[i]
+		}
+
+		p, err := parsePartial(field[len(op):])
+		if err != nil {
+			return nil, err
+		}
+
+		comparators, err := expand(op, p)
+		if err != nil {
+			return nil, err
+		}
+
+		r = append(r, comparators...)
+	}
+
+	return r, nil
+}
+
+//////
+// Factory.
+//////
+
+// ParseConstraint parses a constraint, see Constraint.
+func ParseConstraint(s string) (Constraint, error) {
+	c := Constraint{text: s}
+
+	for _, alternative := range strings.Split(s, "||") {
+		r, err := parseRange(alternative)
+		if err != nil {
+			return Constraint{}, fmt.Errorf("constraint %q: %w", s, err)
+		}
+
+		c.ranges = append(c.ranges, r)
+	}
+
+	return c, nil
+}
+
+// MustParseConstraint is like ParseConstraint, panicking on error, e.g.: for
+// constants.
+func MustParseConstraint(s string) Constraint {
+	c, err := ParseConstraint(s)
+	if err != nil {
+		panic(err)
+	}
+
+	return c
+}
+
+// Check checks if the version satisfies the constraint, e.g.:
+// semver.Check("^1.2.0", "1.4.1"). It fails if either is invalid.
+func Check(constraint, version string) (bool, error) {
+	c, err := ParseConstraint(constraint)
+	if err != nil {
+		return false, err
+	}
+
+	v, err := Parse(version)
+	if err != nil {
+		return false, err
+	}
+
+	return c.Check(v), nil
+}
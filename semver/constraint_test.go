@@ -0,0 +1,98 @@
+package semver
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConstraint(t *testing.T) {
+	tests := []struct {
+		constraint string
+		matching   []string
+		others     []string
+	}{
+		{"^1.2.0", []string{"1.2.0", "1.9.9"}, []string{"1.1.9", "2.0.0", "1.3.0-beta"}},
+		{"^0.2.3", []string{"0.2.3", "0.2.9"}, []string{"0.3.0", "0.2.2"}},
+		{"^0.0.3", []string{"0.0.3"}, []string{"0.0.4"}},
+		{"^1.2", []string{"1.2.0", "1.9.0"}, []string{"2.0.0"}},
+		{"^0", []string{"0.0.1", "0.9.0"}, []string{"1.0.0"}},
+		{"~1.2.3", []string{"1.2.3", "1.2.9"}, []string{"1.3.0", "1.2.2"}},
+		{"~1", []string{"1.0.0", "1.9.0"}, []string{"2.0.0"}},
+		{"1.2.x", []string{"1.2.0", "1.2.9"}, []string{"1.3.0"}},
+		{"1.2", []string{"1.2.5"}, []string{"1.3.0"}},
+		{"*", []string{"0.0.1", "9.9.9"}, []string{"1.0.0-rc.1"}},
+		{"", []string{"1.0.0"}, nil},
+		{"=1.2.3", []string{"1.2.3", "1.2.3+build"}, []string{"1.2.4"}},
+		{">1.2", []string{"1.3.0"}, []string{"1.2.9"}},
+		{">1.2.3", []string{"1.2.4"}, []string{"1.2.3"}},
+		{"<=1.2", []string{"1.2.9"}, []string{"1.3.0"}},
+		{">= 1.2, < 2", []string{"1.2.0", "1.9.0"}, []string{"1.1.0", "2.0.0"}},
+		{">=1.2 <2 !=1.5.0", []string{"1.4.0"}, []string{"1.5.0"}},
+		{"1.2 - 1.4", []string{"1.2.0", "1.4.9"}, []string{"1.5.0", "1.1.9"}},
+		{"1.2.3 - 1.4.0", []string{"1.4.0"}, []string{"1.4.1"}},
+		{"~1.2 || ^3", []string{"1.2.5", "3.1.0"}, []string{"2.0.0", "1.3.0"}},
+		{">=1.3.0-alpha", []string{"1.3.0-beta", "1.3.0", "2.0.0"}, []string{"1.4.0-beta", "1.3.0-0"}},
+	}
+
+	for _, tt := range tests {
+		c, err := ParseConstraint(tt.constraint)
+
+		assert.NoError(t, err, tt.constraint)
+
+		for _, v := range tt.matching {
+			assert.True(t, c.Check(MustParse(v)), "%s should match %s", v, tt.constraint)
+		}
+
+		for _, v := range tt.others {
+			assert.False(t, c.Check(MustParse(v)), "%s shouldn't match %s", v, tt.constraint)
+		}
+	}
+
+	for _, s := range []string{"^1.2.3.4", "=>1.2", "!=1.2", ">=", "~a", "1 - "} {
+		_, err := ParseConstraint(s)
+
+		assert.ErrorIs(t, err, ErrInvalid, s)
+	}
+}
+
+func TestConstraintFilter(t *testing.T) {
+	versions := []Version{MustParse("1.0.0"), MustParse("1.4.0"), MustParse("1.2.0"), MustParse("2.0.0")}
+
+	c := MustParseConstraint("^1.1")
+
+	assert.Equal(t, []Version{MustParse("1.4.0"), MustParse("1.2.0")}, c.Filter(versions))
+
+	latest, ok := c.Latest(versions)
+
+	assert.True(t, ok)
+	assert.Equal(t, MustParse("1.4.0"), latest)
+
+	_, ok = MustParseConstraint(">3").Latest(versions)
+
+	assert.False(t, ok)
+
+	ok, err := Check("^1.2.0", "1.4.1")
+
+	assert.NoError(t, err)
+	assert.True(t, ok)
+
+	_, err = Check("^1.2.0", "x")
+
+	assert.Error(t, err)
+}
+
+func TestConstraintJSON(t *testing.T) {
+	var config struct {
+		Requires Constraint `json:"requires"`
+	}
+
+	assert.NoError(t, json.Unmarshal([]byte(`{"requires":"^1.2"}`), &config))
+	assert.True(t, config.Requires.Check(MustParse("1.3.0")))
+
+	data, err := json.Marshal(config)
+
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"requires":"^1.2"}`, string(data))
+}
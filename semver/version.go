@@ -0,0 +1,288 @@
+// Package semver provides Version, a semantic version, see
+// https://semver.org, with parsing, ordering, and constraints, e.g.: "^1.2.0".
+package semver
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/thalesfsp/go-common-types/shared"
+)
+
+//////
+// Const, vars, and types.
+//////
+
+// ErrInvalid is returned when parsing an invalid version, or constraint.
+var ErrInvalid = errors.New("invalid semantic version")
+
+// Version is a semantic version, e.g.: "1.2.3-beta.1+build.5". It's
+// comparable, so it can be an element of a SafeSlice, or a map key, but ==
+// also compares the build metadata, which Compare ignores.
+type Version struct {
+	Major, Minor, Patch uint64
+
+	// Prerelease is the dot-separated pre-release, e.g.: "beta.1", without
+	// the leading "-". Versions with a pre-release precede the release.
+	Prerelease string
+
+	// Build is the dot-separated build metadata, e.g.: "build.5", without the
+	// leading "+". It's ignored when comparing versions.
+	Build string
+}
+
+// Versions is a list of versions, implementing sort.Interface, in ascending
+// order.
+type Versions []Version
+
+//////
+// Methods.
+//////
+
+// String is the stringer implementation, e.g.: "1.2.3-beta.1+build.5".
+func (v Version) String() string {
+	s := fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+
+	if v.Prerelease != "" {
+		s += "-" + v.Prerelease
+	}
+
+	if v.Build != "" {
+		s += "+" + v.Build
+	}
+
+	return s
+}
+
+// Compare returns -1, 0, or 1, comparing the versions by precedence: major,
+// minor, and patch numerically, then the pre-release, identifier by
+// identifier. The build metadata is ignored.
+func (v Version) Compare(other Version) int {
+	for _, c := range [][2]uint64{{v.Major, other.Major}, {v.Minor, other.Minor}, {v.Patch, other.Patch}} {
+		if c := compareUint(c[0], c[1]); c != 0 {
+			return c
+		}
+	}
+
+	return comparePrerelease(v.Prerelease, other.Prerelease)
+}
+
+// LessThan checks if the version precedes the other.
+func (v Version) LessThan(other Version) bool {
+	return v.Compare(other) < 0
+}
+
+// GreaterThan checks if the version follows the other.
+func (v Version) GreaterThan(other Version) bool {
+	return v.Compare(other) > 0
+}
+
+// Equal checks if the versions have the same precedence, i.e.: regardless of
+// the build metadata.
+func (v Version) Equal(other Version) bool {
+	return v.Compare(other) == 0
+}
+
+// IsPrerelease checks if the version has a pre-release.
+func (v Version) IsPrerelease() bool {
+	return v.Prerelease != ""
+}
+
+// IncMajor returns the next major release, e.g.: 2.0.0 for 1.2.3.
+func (v Version) IncMajor() Version {
+	return Version{Major: v.Major + 1}
+}
+
+// IncMinor returns the next minor release, e.g.: 1.3.0 for 1.2.3.
+func (v Version) IncMinor() Version {
+	return Version{Major: v.Major, Minor: v.Minor + 1}
+}
+
+// IncPatch returns the next patch release, e.g.: 1.2.4 for 1.2.3, or 1.2.3 for
+// 1.2.3-beta.
+func (v Version) IncPatch() Version {
+	if v.Prerelease != "" {
+		return Version{Major: v.Major, Minor: v.Minor, Patch: v.Patch}
+	}
+
+	return Version{Major: v.Major, Minor: v.Minor, Patch: v.Patch + 1}
+}
+
+// MarshalText implements the encoding.TextMarshaler interface, so versions
+// are encoded as strings in JSON.
+func (v Version) MarshalText() ([]byte, error) {
+	return []byte(v.String()), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface, see Parse.
+func (v *Version) UnmarshalText(text []byte) error {
+	parsed, err := Parse(string(text))
+	if err != nil {
+		return err
+	}
+
+	*v = parsed
+
+	return nil
+}
+
+// Len implements sort.Interface.
+func (vs Versions) Len() int { return len(vs) }
+
+// Less implements sort.Interface.
+func (vs Versions) Less(i, j int) bool { return vs[i].LessThan(vs[j]) }
+
+// Swap implements sort.Interface.
+func (vs Versions) Swap(i, j int) { vs[i], vs[j] = vs[j], vs[i] }
+
+//////
+// Exported functionalities.
+//////
+
+// Comparer returns the shared.Comparer of versions, by precedence, e.g.: for
+// SafeSlice.SortWith, or SortedInsert.
+func Comparer() shared.Comparer[Version] {
+	return shared.CompareFunc[Version](func(a, b Version) int {
+		return a.Compare(b)
+	})
+}
+
+//////
+// Helpers.
+//////
+
+// compareUint returns -1, 0, or 1, comparing the numbers.
+func compareUint(a, b uint64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// comparePrerelease compares pre-releases: no pre-release has a higher
+// precedence, numeric identifiers are compared numerically, and have a lower
+// precedence than alphanumeric ones, which are compared in ASCII order, and a
+// larger set of identifiers has a higher precedence if all the preceding ones
+// are equal.
+func comparePrerelease(a, b string) int {
+	switch {
+	case a == b:
+		return 0
+	case a == "":
+		return 1
+	case b == "":
+		return -1
+	}
+
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+
+	for i := 0; i < len(as) && i < len(bs); i++ {
+		an, aErr := strconv.ParseUint(as[i], 10, 64)
+		bn, bErr := strconv.ParseUint(bs[i], 10, 64)
+
+		var c int
+
+		switch {
+		case aErr == nil && bErr == nil:
+			c = compareUint(an, bn)
+		case aErr == nil:
+			c = -1
+		case bErr == nil:
+			c = 1
+		default:
+			c = strings.Compare(as[i], bs[i])
+		}
+
+		if c != 0 {
+			return c
+		}
+	}
+
+	return compareUint(uint64(len(as)), uint64(len(bs)))
+}
+
+// parseNumber parses a version number, without leading zeros.
+func parseNumber(s string) (uint64, error) {
+	if len(s) > 1 && s[0] == '0' {
+		return 0, errors.New("leading zero")
+	}
+
+	return strconv.ParseUint(s, 10, 64)
+}
+
+// validIdentifiers checks the dot-separated identifiers of a pre-release, or
+// build metadata: non-empty, of alphanumerics, and hyphens. With numbers,
+// numeric identifiers must not have leading zeros.
+func validIdentifiers(s string, numbers bool) bool {
+	for _, id := range strings.Split(s, ".") {
+		if id == "" || strings.Trim(id, "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz-") != "" {
+			return false
+		}
+
+		if numbers && strings.Trim(id, "0123456789") == "" && len(id) > 1 && id[0] == '0' {
+			return false
+		}
+	}
+
+	return true
+}
+
+//////
+// Factory.
+//////
+
+// Parse parses a semantic version, e.g.: "1.2.3", "v1.2.3", or
+// "1.2.3-beta.1+build.5". The leading "v" is optional.
+func Parse(s string) (Version, error) {
+	rest := strings.TrimPrefix(s, "v")
+
+	var v Version
+
+	if i := strings.IndexByte(rest, '+'); i >= 0 {
+		v.Build, rest = rest[i+1:], rest[:i]
+
+		if !validIdentifiers(v.Build, false) {
+			return Version{}, fmt.Errorf("%w %q: build metadata", ErrInvalid, s)
+		}
+	}
+
+	if i := strings.IndexByte(rest, '-'); i >= 0 {
+		v.Prerelease, rest = rest[i+1:], rest[:i]
+
+		if !validIdentifiers(v.Prerelease, true) {
+			return Version{}, fmt.Errorf("%w %q: pre-release", ErrInvalid, s)
+		}
+	}
+
+	parts := strings.Split(rest, ".")
+	if len(parts) != 3 {
+		return Version{}, fmt.Errorf("%w %q: expected major.minor.patch", ErrInvalid, s)
+	}
+
+	for i, p := range []*uint64{&v.Major, &v.Minor, &v.Patch} {
+		n, err := parseNumber(parts[i])
+		if err != nil {
+			return Version{}, fmt.Errorf("%w %q: %w", ErrInvalid, s, err)
+		}
+
+		*p = n
+	}
+
+	return v, nil
+}
+
+// MustParse is like Parse, panicking on error, e.g.: for constants.
+func MustParse(s string) Version {
+	v, err := Parse(s)
+	if err != nil {
+		panic(err)
+	}
+
+	return v
+}
@@ -0,0 +1,84 @@
+package semver
+
+import (
+	"encoding/json"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/thalesfsp/go-common-types/safeslice"
+)
+
+func TestParse(t *testing.T) {
+	v, err := Parse("v1.2.3-beta.1+build.5")
+
+	assert.NoError(t, err)
+	assert.Equal(t, Version{Major: 1, Minor: 2, Patch: 3, Prerelease: "beta.1", Build: "build.5"}, v)
+	assert.Equal(t, "1.2.3-beta.1+build.5", v.String())
+	assert.True(t, v.IsPrerelease())
+
+	for _, s := range []string{"", "1", "1.2", "1.2.3.4", "01.2.3", "1.2.x", "1.2.3-", "1.2.3-01", "1.2.3+", "1.2.3-a..b", "1.2.3-a_b"} {
+		_, err := Parse(s)
+
+		assert.ErrorIs(t, err, ErrInvalid, s)
+	}
+
+	// Numeric build identifiers can have leading zeros.
+	assert.Equal(t, "001", MustParse("1.0.0+001").Build)
+}
+
+func TestCompare(t *testing.T) {
+	// The precedence example of the specification.
+	ordered := []string{
+		"1.0.0-alpha", "1.0.0-alpha.1", "1.0.0-alpha.beta", "1.0.0-beta",
+		"1.0.0-beta.2", "1.0.0-beta.11", "1.0.0-rc.1", "1.0.0", "1.0.1", "1.1.0", "2.0.0", "10.0.0",
+	}
+
+	for i := 0; i < len(ordered)-1; i++ {
+		a, b := MustParse(ordered[i]), MustParse(ordered[i+1])
+
+		assert.True(t, a.LessThan(b), "%s < %s", a, b)
+		assert.True(t, b.GreaterThan(a), "%s > %s", b, a)
+	}
+
+	assert.True(t, MustParse("1.0.0+a").Equal(MustParse("1.0.0+b")))
+	assert.NotEqual(t, MustParse("1.0.0+a"), MustParse("1.0.0+b"))
+
+	assert.Equal(t, "2.0.0", MustParse("1.2.3").IncMajor().String())
+	assert.Equal(t, "1.3.0", MustParse("1.2.3").IncMinor().String())
+	assert.Equal(t, "1.2.4", MustParse("1.2.3").IncPatch().String())
+	assert.Equal(t, "1.2.3", MustParse("1.2.3-rc.1").IncPatch().String())
+}
+
+func TestSort(t *testing.T) {
+	versions := Versions{MustParse("1.10.0"), MustParse("1.2.0"), MustParse("1.2.0-rc.1"), MustParse("0.9.9")}
+
+	sort.Sort(versions)
+
+	assert.Equal(t, "0.9.9", versions[0].String())
+	assert.Equal(t, "1.2.0-rc.1", versions[1].String())
+	assert.Equal(t, "1.10.0", versions[3].String())
+
+	// Stored in a SafeSlice.
+	s := safeslice.New(MustParse("2.0.0"), MustParse("1.0.0"), MustParse("1.5.0"))
+	s.SortWith(Comparer())
+
+	first, _ := s.First()
+
+	assert.Equal(t, "1.0.0", first.String())
+	assert.Equal(t, 2, s.SortedInsert(MustParse("1.6.0"), Comparer().Compare))
+}
+
+func TestVersionJSON(t *testing.T) {
+	data, err := json.Marshal([]Version{MustParse("1.2.3-rc.1")})
+
+	assert.NoError(t, err)
+	assert.Equal(t, `["1.2.3-rc.1"]`, string(data))
+
+	var versions []Version
+
+	assert.NoError(t, json.Unmarshal(data, &versions))
+	assert.Equal(t, MustParse("1.2.3-rc.1"), versions[0])
+
+	assert.Error(t, json.Unmarshal([]byte(`["1.2"]`), &versions))
+}
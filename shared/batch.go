@@ -0,0 +1,173 @@
+package shared
+
+import (
+	"context"
+	"time"
+)
+
+//////
+// Helpers.
+//////
+
+// batcher groups the events of a channel into batches, flushed when a timer
+// fires, or when the input is closed.
+type batcher[E any] struct {
+	ctx context.Context
+	out chan []E
+
+	batch []E
+	timer *time.Timer
+}
+
+// send sends the pending batch, if any, returning false if the context is
+// done.
+func (b *batcher[E]) send() bool {
+	if len(b.batch) == 0 {
+		return true
+	}
+
+	select {
+	case b.out <- b.batch:
+		b.batch = nil
+
+		return true
+	case <-b.ctx.Done():
+		return false
+	}
+}
+
+// arm (re)starts the timer to fire after d, returning its channel.
+func (b *batcher[E]) arm(d time.Duration) <-chan time.Time {
+	if b.timer == nil {
+		b.timer = time.NewTimer(d)
+
+		return b.timer.C
+	}
+
+	if !b.timer.Stop() {
+		select {
+		case <-b.timer.C:
+		default:
+		}
+	}
+
+	b.timer.Reset(d)
+
+	return b.timer.C
+}
+
+// stop releases the timer.
+func (b *batcher[E]) stop() {
+	if b.timer != nil {
+		b.timer.Stop()
+	}
+}
+
+//////
+// Exported functionalities.
+//////
+
+// Debounce groups the events into batches, sending a batch once no event was
+// received for the window, e.g.: to flush to storage once after a bulk load,
+// instead of once per change. A steady stream of events is never flushed, so
+// combine it with RateLimit if that's a concern. The returned channel is
+// closed when the context is done, or after the last batch, when events is
+// closed.
+func Debounce[E any](ctx context.Context, events <-chan E, window time.Duration) <-chan []E {
+	b := &batcher[E]{ctx: ctx, out: make(chan []E)}
+
+	go func() {
+		defer close(b.out)
+		defer b.stop()
+
+		var fire <-chan time.Time
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case e, ok := <-events:
+				if !ok {
+					b.send()
+
+					return
+				}
+
+				b.batch = append(b.batch, e)
+
+				fire = b.arm(window)
+			case <-fire:
+				fire = nil
+
+				if !b.send() {
+					return
+				}
+			}
+		}
+	}()
+
+	return b.out
+}
+
+// RateLimit groups the events into batches, sending at most perSecond batches
+// per second. An event is sent right away if the limit allows it, otherwise
+// it's batched with the following ones until it does. The returned channel is
+// closed when the context is done, or after the last batch, when events is
+// closed.
+func RateLimit[E any](ctx context.Context, events <-chan E, perSecond float64) <-chan []E {
+	b := &batcher[E]{ctx: ctx, out: make(chan []E)}
+
+	interval := time.Duration(float64(time.Second) / perSecond)
+
+	go func() {
+		defer close(b.out)
+		defer b.stop()
+
+		var (
+			fire <-chan time.Time
+			last time.Time
+		)
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case e, ok := <-events:
+				if !ok {
+					b.send()
+
+					return
+				}
+
+				b.batch = append(b.batch, e)
+
+				// A flush is already scheduled.
+				if fire != nil {
+					continue
+				}
+
+				if wait := interval - time.Since(last); wait > 0 {
+					fire = b.arm(wait)
+
+					continue
+				}
+
+				if !b.send() {
+					return
+				}
+
+				last = time.Now()
+			case <-fire:
+				fire = nil
+
+				if !b.send() {
+					return
+				}
+
+				last = time.Now()
+			}
+		}
+	}()
+
+	return b.out
+}
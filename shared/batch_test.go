@@ -0,0 +1,80 @@
+package shared
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDebounce(t *testing.T) {
+	events := make(chan int)
+
+	batches := Debounce(context.Background(), events, 20*time.Millisecond)
+
+	go func() {
+		for i := 1; i <= 5; i++ {
+			events <- i
+		}
+
+		time.Sleep(50 * time.Millisecond)
+
+		events <- 6
+
+		close(events)
+	}()
+
+	assert.Equal(t, []int{1, 2, 3, 4, 5}, <-batches)
+	assert.Equal(t, []int{6}, <-batches)
+
+	_, ok := <-batches
+	assert.False(t, ok)
+}
+
+func TestRateLimit(t *testing.T) {
+	events := make(chan int)
+
+	batches := RateLimit(context.Background(), events, 10)
+
+	done := make(chan struct{})
+
+	go func() {
+		for i := 1; i <= 5; i++ {
+			events <- i
+		}
+
+		<-done
+
+		close(events)
+	}()
+
+	start := time.Now()
+
+	// The first event passes right away, the others wait for the next slot.
+	assert.Equal(t, []int{1}, <-batches)
+	assert.Equal(t, []int{2, 3, 4, 5}, <-batches)
+	assert.GreaterOrEqual(t, time.Since(start), 90*time.Millisecond)
+
+	close(done)
+
+	_, ok := <-batches
+	assert.False(t, ok)
+}
+
+func TestBatchCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	events := make(chan int)
+
+	debounced := Debounce(ctx, events, time.Hour)
+	limited := RateLimit(ctx, events, 1)
+
+	cancel()
+
+	_, ok := <-debounced
+	assert.False(t, ok)
+
+	_, ok = <-limited
+	assert.False(t, ok)
+}
@@ -0,0 +1,139 @@
+package shared
+
+import (
+	"strings"
+
+	"golang.org/x/exp/constraints"
+)
+
+//////
+// Const, vars, and types.
+//////
+
+// Comparer defines the order of values of a type, e.g.: to sort them, or to
+// find the smallest one.
+type Comparer[T any] interface {
+	// Compare returns a negative number if a < b, a positive number if a > b,
+	// and 0 if they're equivalent.
+	Compare(a, b T) int
+}
+
+// Equaler defines the identity of values of a type, e.g.: to deduplicate
+// them in a set.
+type Equaler[T any] interface {
+	// Equal checks if both values are the same.
+	Equal(a, b T) bool
+
+	// Hash returns the hash of the value. Equal values must have the same
+	// hash.
+	Hash(v T) string
+}
+
+// CompareFunc adapts a function to the Comparer interface.
+type CompareFunc[T any] func(a, b T) int
+
+// comparableEqualer is the Equaler of comparable types.
+type comparableEqualer[T comparable] struct{}
+
+// funcEqualer adapts functions to the Equaler interface.
+type funcEqualer[T any] struct {
+	equal func(a, b T) bool
+	hash  func(v T) string
+}
+
+//////
+// Methods.
+//////
+
+// Compare implements the Comparer interface.
+func (f CompareFunc[T]) Compare(a, b T) int {
+	return f(a, b)
+}
+
+// Equal implements the Equaler interface.
+func (comparableEqualer[T]) Equal(a, b T) bool {
+	return a == b
+}
+
+// Hash implements the Equaler interface.
+func (comparableEqualer[T]) Hash(v T) string {
+	return GenerateHash(v)
+}
+
+// Equal implements the Equaler interface.
+func (e funcEqualer[T]) Equal(a, b T) bool {
+	return e.equal(a, b)
+}
+
+// Hash implements the Equaler interface.
+func (e funcEqualer[T]) Hash(v T) string {
+	return e.hash(v)
+}
+
+//////
+// Exported functionalities.
+//////
+
+// CompareOrdered returns the Comparer of ordered types, using < and >.
+func CompareOrdered[T constraints.Ordered]() Comparer[T] {
+	return CompareFunc[T](func(a, b T) int {
+		switch {
+		case a < b:
+			return -1
+		case a > b:
+			return 1
+		default:
+			return 0
+		}
+	})
+}
+
+// CompareBy returns a Comparer ordering values by the ordered key returned by
+// f, e.g.: CompareBy(func(u User) string { return u.Name }).
+func CompareBy[T any, K constraints.Ordered](f func(v T) K) Comparer[T] {
+	keys := CompareOrdered[K]()
+
+	return CompareFunc[T](func(a, b T) int {
+		return keys.Compare(f(a), f(b))
+	})
+}
+
+// Reverse returns a Comparer with the inverse order of c.
+func Reverse[T any](c Comparer[T]) Comparer[T] {
+	return CompareFunc[T](func(a, b T) int {
+		return c.Compare(b, a)
+	})
+}
+
+// Less returns a less function for c, e.g.: for sort.Slice, or MinBy.
+func Less[T any](c Comparer[T]) func(a, b T) bool {
+	return func(a, b T) bool {
+		return c.Compare(a, b) < 0
+	}
+}
+
+// EqualComparable returns the Equaler of comparable types, using ==, and
+// hashing like GenerateHash.
+func EqualComparable[T comparable]() Equaler[T] {
+	return comparableEqualer[T]{}
+}
+
+// EqualBy returns an Equaler identifying values by the comparable key returned
+// by f, e.g.: EqualBy(func(u User) string { return u.ID }).
+func EqualBy[T any, K comparable](f func(v T) K) Equaler[T] {
+	return funcEqualer[T]{
+		equal: func(a, b T) bool { return f(a) == f(b) },
+		hash:  func(v T) string { return GenerateHash(f(v)) },
+	}
+}
+
+// FoldCase returns the case-folded form of s, under which strings differing
+// only by case are the same, e.g.: "Content-Type", and "content-type".
+func FoldCase(s string) string {
+	return strings.ToLower(s)
+}
+
+// EqualFold returns the Equaler of strings, ignoring case, see FoldCase.
+func EqualFold() Equaler[string] {
+	return EqualBy(FoldCase)
+}
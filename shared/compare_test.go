@@ -0,0 +1,64 @@
+package shared
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type user struct {
+	ID   string
+	Name string
+	Age  int
+}
+
+func TestCompareOrdered(t *testing.T) {
+	c := CompareOrdered[int]()
+
+	assert.Equal(t, -1, c.Compare(1, 2))
+	assert.Equal(t, 1, c.Compare(2, 1))
+	assert.Equal(t, 0, c.Compare(2, 2))
+
+	assert.Equal(t, 1, Reverse(c).Compare(1, 2))
+	assert.True(t, Less(c)(1, 2))
+	assert.False(t, Less(c)(2, 2))
+}
+
+func TestCompareBy(t *testing.T) {
+	users := []user{{"1", "bob", 30}, {"2", "alice", 25}, {"3", "carol", 25}}
+
+	sort.SliceStable(users, func(i, j int) bool {
+		return Less(CompareBy(func(u user) int { return u.Age }))(users[i], users[j])
+	})
+
+	assert.Equal(t, []string{"alice", "carol", "bob"}, []string{users[0].Name, users[1].Name, users[2].Name})
+
+	byName := CompareFunc[user](func(a, b user) int { return CompareOrdered[string]().Compare(a.Name, b.Name) })
+	assert.Equal(t, -1, byName.Compare(users[0], users[2]))
+}
+
+func TestEqualers(t *testing.T) {
+	e := EqualComparable[string]()
+
+	assert.True(t, e.Equal("a", "a"))
+	assert.False(t, e.Equal("a", "b"))
+	assert.Equal(t, GenerateHash("a"), e.Hash("a"))
+
+	byID := EqualBy(func(u user) string { return u.ID })
+
+	a, b := user{"1", "bob", 30}, user{"1", "robert", 31}
+
+	assert.True(t, byID.Equal(a, b))
+	assert.False(t, byID.Equal(a, user{ID: "2"}))
+	assert.Equal(t, byID.Hash(a), byID.Hash(b))
+}
+
+func TestEqualFold(t *testing.T) {
+	fold := EqualFold()
+
+	assert.True(t, fold.Equal("Content-Type", "content-TYPE"))
+	assert.False(t, fold.Equal("Content-Type", "Content-Length"))
+	assert.Equal(t, fold.Hash("ACCEPT"), fold.Hash("accept"))
+	assert.Equal(t, "x-request-id", FoldCase("X-Request-ID"))
+}
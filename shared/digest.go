@@ -0,0 +1,86 @@
+package shared
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"hash"
+	"sort"
+)
+
+//////
+// Exported functionalities.
+//////
+
+// DigestOrdered returns the digest of the values, in order, hex encoded: the
+// hash, by h, or sha256 if nil, of their JSON representations, each prefixed
+// by its length. Unlike GenerateHash, it doesn't depend on memory addresses,
+// so it's stable across processes, e.g.: for ETags, and cache keys. It fails
+// if a value can't be marshalled to JSON.
+func DigestOrdered[T any](h hash.Hash, values []T) (string, error) {
+	if h == nil {
+		h = sha256.New()
+	}
+
+	h.Reset()
+
+	for _, value := range values {
+		data, err := json.Marshal(value)
+		if err != nil {
+			return "", err
+		}
+
+		writeFramed(h, data)
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// DigestUnordered is like DigestOrdered, regardless of the order of the
+// values: the hashes of the values are sorted before being hashed together.
+func DigestUnordered[T any](h hash.Hash, values []T) (string, error) {
+	if h == nil {
+		h = sha256.New()
+	}
+
+	sums := make([][]byte, 0, len(values))
+
+	for _, value := range values {
+		data, err := json.Marshal(value)
+		if err != nil {
+			return "", err
+		}
+
+		h.Reset()
+		h.Write(data)
+
+		sums = append(sums, h.Sum(nil))
+	}
+
+	sort.Slice(sums, func(i, j int) bool {
+		return bytes.Compare(sums[i], sums[j]) < 0
+	})
+
+	h.Reset()
+
+	for _, sum := range sums {
+		writeFramed(h, sum)
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+//////
+// Helpers.
+//////
+
+// writeFramed writes the data to the hash, prefixed by its length, so the
+// boundaries between consecutive values are unambiguous.
+func writeFramed(h hash.Hash, data []byte) {
+	var length [binary.MaxVarintLen64]byte
+
+	h.Write(length[:binary.PutUvarint(length[:], uint64(len(data)))])
+	h.Write(data)
+}
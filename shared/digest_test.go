@@ -0,0 +1,54 @@
+package shared
+
+import (
+	"crypto/md5"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDigestOrdered(t *testing.T) {
+	type user struct {
+		Name *string
+	}
+
+	bob, bob2 := "bob", "bob"
+
+	a, err := DigestOrdered(nil, []user{{&bob}, {nil}})
+
+	assert.NoError(t, err)
+	assert.Len(t, a, 64)
+
+	// Pointers are followed, not compared by address.
+	b, _ := DigestOrdered(nil, []user{{&bob2}, {nil}})
+	assert.Equal(t, a, b)
+
+	c, _ := DigestOrdered(nil, []user{{nil}, {&bob}})
+	assert.NotEqual(t, a, c)
+
+	// Boundaries are unambiguous.
+	d, _ := DigestOrdered(nil, []string{"ab", "c"})
+	e, _ := DigestOrdered(nil, []string{"a", "bc"})
+	assert.NotEqual(t, d, e)
+
+	f, _ := DigestOrdered(md5.New(), []string{"ab", "c"})
+	assert.Len(t, f, 32)
+
+	_, err = DigestOrdered(nil, []any{func() {}})
+	assert.Error(t, err)
+}
+
+func TestDigestUnordered(t *testing.T) {
+	a, err := DigestUnordered(nil, []int{1, 2, 3})
+
+	assert.NoError(t, err)
+
+	b, _ := DigestUnordered(nil, []int{3, 1, 2})
+	assert.Equal(t, a, b)
+
+	c, _ := DigestUnordered(nil, []int{1, 2})
+	assert.NotEqual(t, a, c)
+
+	ordered, _ := DigestOrdered(nil, []int{1, 2, 3})
+	assert.NotEqual(t, a, ordered)
+}
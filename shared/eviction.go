@@ -0,0 +1,316 @@
+package shared
+
+import (
+	"container/heap"
+	"container/list"
+	"math/rand"
+)
+
+//////
+// Const, vars, and types.
+//////
+
+// Eviction reasons, as recorded by metrics.Metrics.Evicted.
+const (
+	// EvictCapacity is the reason of elements evicted to make room for new
+	// ones.
+	EvictCapacity = "capacity"
+
+	// EvictExpired is the reason of elements removed after their TTL.
+	EvictExpired = "expired"
+)
+
+// EvictionPolicy chooses which key to evict from a full bounded collection.
+// The collection notifies it of every key added, accessed, and removed, and
+// asks it for a victim when full.
+//
+// Collections call it holding their lock, so implementations don't need to be
+// safe for concurrent use, but an instance must not be shared between
+// collections.
+type EvictionPolicy[K comparable] interface {
+	// Add records a new key.
+	Add(key K)
+
+	// Access records a hit of a key, e.g.: a read, or an update.
+	Access(key K)
+
+	// Remove forgets a key, e.g.: deleted, evicted, or expired.
+	Remove(key K)
+
+	// Victim returns the key to evict next, without forgetting it, false if
+	// there are no keys.
+	Victim() (K, bool)
+
+	// Reset forgets all keys.
+	Reset()
+}
+
+// listPolicy keeps the keys in a list, evicting the front one. With
+// moveOnAccess, accessed keys move to the back, i.e.: LRU, otherwise keys
+// keep their insertion order, i.e.: FIFO.
+type listPolicy[K comparable] struct {
+	moveOnAccess bool
+
+	order *list.List
+	keys  map[K]*list.Element
+}
+
+// lfuEntry is a key tracked by the LFU policy.
+type lfuEntry[K comparable] struct {
+	key K
+
+	hits uint64
+
+	// tick is the time of the last access, breaking ties between keys with
+	// the same number of hits, in favor of the least recently used.
+	tick uint64
+
+	index int
+}
+
+// lfuHeap is a min-heap of keys by number of hits, then last access.
+type lfuHeap[K comparable] []*lfuEntry[K]
+
+// lfuPolicy evicts the least frequently used key.
+type lfuPolicy[K comparable] struct {
+	heap lfuHeap[K]
+	keys map[K]*lfuEntry[K]
+
+	tick uint64
+}
+
+// randomPolicy evicts a random key.
+type randomPolicy[K comparable] struct {
+	keys  []K
+	index map[K]int
+
+	rand *rand.Rand
+}
+
+//////
+// List policy.
+//////
+
+// Add implements EvictionPolicy.
+func (p *listPolicy[K]) Add(key K) {
+	if _, ok := p.keys[key]; ok {
+		p.Access(key)
+
+		return
+	}
+
+	p.keys[key] = p.order.PushBack(key)
+}
+
+// Access implements EvictionPolicy.
+func (p *listPolicy[K]) Access(key K) {
+	if e, ok := p.keys[key]; ok && p.moveOnAccess {
+		p.order.MoveToBack(e)
+	}
+}
+
+// Remove implements EvictionPolicy.
+func (p *listPolicy[K]) Remove(key K) {
+	if e, ok := p.keys[key]; ok {
+		p.order.Remove(e)
+
+		delete(p.keys, key)
+	}
+}
+
+// Victim implements EvictionPolicy.
+func (p *listPolicy[K]) Victim() (K, bool) {
+	e := p.order.Front()
+	if e == nil {
+		return *new(K), false
+	}
+
+	//nolint:forcetypeassert
+	return e.Value.(K), true
+}
+
+// Reset implements EvictionPolicy.
+func (p *listPolicy[K]) Reset() {
+	p.order.Init()
+	p.keys = make(map[K]*list.Element)
+}
+
+//////
+// LFU policy.
+//////
+
+// Len implements heap.Interface.
+func (h lfuHeap[K]) Len() int { return len(h) }
+
+// Less implements heap.Interface.
+func (h lfuHeap[K]) Less(i, j int) bool {
+	if h[i].hits != h[j].hits {
+		return h[i].hits < h[j].hits
+	}
+
+	return h[i].tick < h[j].tick
+}
+
+// Swap implements heap.Interface.
+func (h lfuHeap[K]) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index = i
+	h[j].index = j
+}
+
+// Push implements heap.Interface.
+func (h *lfuHeap[K]) Push(x any) {
+	//nolint:forcetypeassert
+	e := x.(*lfuEntry[K])
+	e.index = len(*h)
+
+	*h = append(*h, e)
+}
+
+// Pop implements heap.Interface.
+func (h *lfuHeap[K]) Pop() any {
+	old := *h
+	e := old[len(old)-1]
+
+	old[len(old)-1] = nil
+
+	*h = old[:len(old)-1]
+
+	return e
+}
+
+// Add implements EvictionPolicy.
+func (p *lfuPolicy[K]) Add(key K) {
+	if _, ok := p.keys[key]; ok {
+		p.Access(key)
+
+		return
+	}
+
+	p.tick++
+
+	e := &lfuEntry[K]{key: key, tick: p.tick}
+
+	p.keys[key] = e
+
+	heap.Push(&p.heap, e)
+}
+
+// Access implements EvictionPolicy.
+func (p *lfuPolicy[K]) Access(key K) {
+	e, ok := p.keys[key]
+	if !ok {
+		return
+	}
+
+	p.tick++
+
+	e.hits++
+	e.tick = p.tick
+
+	heap.Fix(&p.heap, e.index)
+}
+
+// Remove implements EvictionPolicy.
+func (p *lfuPolicy[K]) Remove(key K) {
+	if e, ok := p.keys[key]; ok {
+		heap.Remove(&p.heap, e.index)
+
+		delete(p.keys, key)
+	}
+}
+
+// Victim implements EvictionPolicy.
+func (p *lfuPolicy[K]) Victim() (K, bool) {
+	if len(p.heap) == 0 {
+		return *new(K), false
+	}
+
+	return p.heap[0].key, true
+}
+
+// Reset implements EvictionPolicy.
+func (p *lfuPolicy[K]) Reset() {
+	p.heap = nil
+	p.keys = make(map[K]*lfuEntry[K])
+}
+
+//////
+// Random policy.
+//////
+
+// Add implements EvictionPolicy.
+func (p *randomPolicy[K]) Add(key K) {
+	if _, ok := p.index[key]; ok {
+		return
+	}
+
+	p.index[key] = len(p.keys)
+	p.keys = append(p.keys, key)
+}
+
+// Access implements EvictionPolicy.
+func (p *randomPolicy[K]) Access(K) {}
+
+// Remove implements EvictionPolicy. The last key takes the place of the
+// removed one, in O(1).
+func (p *randomPolicy[K]) Remove(key K) {
+	i, ok := p.index[key]
+	if !ok {
+		return
+	}
+
+	last := len(p.keys) - 1
+
+	p.keys[i] = p.keys[last]
+	p.index[p.keys[i]] = i
+
+	p.keys[last] = *new(K)
+	p.keys = p.keys[:last]
+
+	delete(p.index, key)
+}
+
+// Victim implements EvictionPolicy.
+func (p *randomPolicy[K]) Victim() (K, bool) {
+	if len(p.keys) == 0 {
+		return *new(K), false
+	}
+
+	return p.keys[p.rand.Intn(len(p.keys))], true
+}
+
+// Reset implements EvictionPolicy.
+func (p *randomPolicy[K]) Reset() {
+	p.keys = nil
+	p.index = make(map[K]int)
+}
+
+//////
+// Factory.
+//////
+
+// NewLRU returns a policy evicting the least recently used key.
+func NewLRU[K comparable]() EvictionPolicy[K] {
+	return &listPolicy[K]{moveOnAccess: true, order: list.New(), keys: make(map[K]*list.Element)}
+}
+
+// NewFIFO returns a policy evicting the oldest key, regardless of accesses.
+func NewFIFO[K comparable]() EvictionPolicy[K] {
+	return &listPolicy[K]{order: list.New(), keys: make(map[K]*list.Element)}
+}
+
+// NewLFU returns a policy evicting the least frequently used key, and the
+// least recently used one among keys with the same number of hits. Added
+// keys start with no hits, so a new key is evicted before older keys which
+// were accessed.
+func NewLFU[K comparable]() EvictionPolicy[K] {
+	return &lfuPolicy[K]{keys: make(map[K]*lfuEntry[K])}
+}
+
+// NewRandom returns a policy evicting a random key, with the given seed, so
+// evictions are reproducible.
+func NewRandom[K comparable](seed int64) EvictionPolicy[K] {
+	//nolint:gosec
+	return &randomPolicy[K]{index: make(map[K]int), rand: rand.New(rand.NewSource(seed))}
+}
@@ -0,0 +1,88 @@
+package shared
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// victims evicts all the keys of the policy, returning them in order.
+func victims(p EvictionPolicy[string]) []string {
+	keys := []string{}
+
+	for {
+		key, ok := p.Victim()
+		if !ok {
+			return keys
+		}
+
+		p.Remove(key)
+
+		keys = append(keys, key)
+	}
+}
+
+func TestEvictionPolicies(t *testing.T) {
+	for name, test := range map[string]struct {
+		policy   EvictionPolicy[string]
+		expected []string
+	}{
+		"lru":  {NewLRU[string](), []string{"c", "d", "b", "a"}},
+		"fifo": {NewFIFO[string](), []string{"b", "c", "d"}},
+		"lfu":  {NewLFU[string](), []string{"c", "d", "b", "a"}},
+	} {
+		t.Run(name, func(t *testing.T) {
+			p := test.policy
+
+			for _, key := range []string{"a", "b", "c", "d"} {
+				p.Add(key)
+			}
+
+			p.Access("a")
+			p.Access("b")
+			p.Access("a")
+
+			if name == "fifo" {
+				p.Remove("a")
+			}
+
+			assert.Equal(t, test.expected, victims(p))
+
+			p.Add("x")
+			p.Reset()
+
+			_, ok := p.Victim()
+			assert.False(t, ok)
+		})
+	}
+}
+
+func TestEvictionPolicyRandom(t *testing.T) {
+	p := NewRandom[string](1)
+
+	for _, key := range []string{"a", "b", "c", "d"} {
+		p.Add(key)
+	}
+
+	p.Remove("b")
+
+	assert.ElementsMatch(t, []string{"a", "c", "d"}, victims(p))
+
+	// The same seed gives the same evictions.
+	q, r := NewRandom[int](7), NewRandom[int](7)
+
+	for i := 0; i < 100; i++ {
+		q.Add(i)
+		r.Add(i)
+	}
+
+	for i := 0; i < 100; i++ {
+		a, _ := q.Victim()
+		b, _ := r.Victim()
+
+		assert.Equal(t, a, b)
+
+		q.Remove(a)
+		r.Remove(b)
+	}
+}
@@ -2,13 +2,279 @@ package shared
 
 import (
 	"crypto/sha256"
+	"encoding"
 	"encoding/hex"
+	"encoding/json"
 	"fmt"
+	"strings"
 )
 
-// GenerateHash returns a sha256 hash of the value.
+//////
+// Const, vars, and types.
+//////
+
+// Encoder is a streaming encoder for formats which can preserve the order of
+// map entries, e.g.: MessagePack, and CBOR. Its method set matches the
+// MessagePack encoder of github.com/vmihailenco/msgpack/v5.
+type Encoder interface {
+	// EncodeArrayLen writes the header of an array with n elements.
+	EncodeArrayLen(n int) error
+
+	// EncodeMapLen writes the header of a map with n entries.
+	EncodeMapLen(n int) error
+
+	// EncodeString writes a string.
+	EncodeString(s string) error
+
+	// Encode writes any value.
+	Encode(v any) error
+}
+
+// Decoder is the streaming decoder counterpart of Encoder. Its method set
+// matches the MessagePack decoder of github.com/vmihailenco/msgpack/v5.
+type Decoder interface {
+	// DecodeArrayLen reads the header of an array, returning the number of
+	// elements, or -1 for nil.
+	DecodeArrayLen() (int, error)
+
+	// DecodeMapLen reads the header of a map, returning the number of entries,
+	// or -1 for nil.
+	DecodeMapLen() (int, error)
+
+	// DecodeString reads a string.
+	DecodeString() (string, error)
+
+	// Decode reads any value into v.
+	Decode(v any) error
+}
+
+// Op is the type of a mutation of a collection, carried by change events.
+type Op string
+
+// Mutation types.
+const (
+	OpAdd    Op = "add"
+	OpUpdate Op = "update"
+	OpDelete Op = "delete"
+	OpClear  Op = "clear"
+)
+
+//////
+// Exported functionalities.
+//////
+
+// GenerateHash returns a sha256 hash of the value, hex encoded.
 func GenerateHash[T any](value T) string {
-	hash := sha256.Sum256([]byte(fmt.Sprintf("%v", value)))
+	hash := GenerateHashSum(value)
 
 	return hex.EncodeToString(hash[:])
 }
+
+// GenerateHashSum returns the raw sha256 hash of the value, the same hash as
+// GenerateHash, without encoding.
+func GenerateHashSum[T any](value T) [sha256.Size]byte {
+	return sha256.Sum256(fmt.Appendf(nil, "%v", value))
+}
+
+// ScanJSON is a helper to implement the sql.Scanner interface for types
+// stored as JSON. It accepts []byte, string, and nil (as JSON null) sources.
+func ScanJSON(src any, unmarshal func(data []byte) error) error {
+	switch v := src.(type) {
+	case nil:
+		return unmarshal([]byte("null"))
+	case []byte:
+		return unmarshal(v)
+	case string:
+		return unmarshal([]byte(v))
+	default:
+		return fmt.Errorf("cannot scan %T, expected []byte or string", src)
+	}
+}
+
+// FormatText returns the text representation of the value. Strings are
+// returned as-is, encoding.TextMarshaler is honored, and anything else is
+// JSON encoded (e.g.: numbers, and booleans).
+func FormatText[T any](value T) (string, error) {
+	if s, ok := any(value).(string); ok {
+		return s, nil
+	}
+
+	marshaler, ok := any(value).(encoding.TextMarshaler)
+	if !ok {
+		marshaler, ok = any(&value).(encoding.TextMarshaler)
+	}
+
+	if ok {
+		b, err := marshaler.MarshalText()
+		if err != nil {
+			return "", err
+		}
+
+		return string(b), nil
+	}
+
+	b, err := json.Marshal(value)
+	if err != nil {
+		return "", err
+	}
+
+	return string(b), nil
+}
+
+// ParseText parses the text representation of a value, the inverse of
+// FormatText.
+func ParseText[T any](text string) (T, error) {
+	var value T
+
+	switch v := any(&value).(type) {
+	case *string:
+		*v = text
+	case encoding.TextUnmarshaler:
+		if err := v.UnmarshalText([]byte(text)); err != nil {
+			return value, err
+		}
+	default:
+		if err := json.Unmarshal([]byte(text), v); err != nil {
+			return value, fmt.Errorf("cannot parse %q: %w", text, err)
+		}
+	}
+
+	return value, nil
+}
+
+// EscapeText escapes the backslash, and any of the special characters in s
+// with a backslash.
+func EscapeText(s, special string) string {
+	var sb strings.Builder
+
+	for _, r := range s {
+		if r == '\\' || strings.ContainsRune(special, r) {
+			sb.WriteRune('\\')
+		}
+
+		sb.WriteRune(r)
+	}
+
+	return sb.String()
+}
+
+// UnescapeText removes the escaping added by EscapeText.
+func UnescapeText(s string) string {
+	var sb strings.Builder
+
+	escaped := false
+
+	for _, r := range s {
+		if !escaped && r == '\\' {
+			escaped = true
+
+			continue
+		}
+
+		escaped = false
+
+		sb.WriteRune(r)
+	}
+
+	return sb.String()
+}
+
+// SplitText splits s on the unescaped occurrences of sep, up to n parts (n < 0
+// means no limit). Parts are returned still escaped.
+func SplitText(s string, sep rune, n int) []string {
+	parts := []string{}
+
+	start := 0
+	escaped := false
+
+	for i, r := range s {
+		switch {
+		case escaped:
+			escaped = false
+		case r == '\\':
+			escaped = true
+		case r == sep && (n < 0 || len(parts) < n-1):
+			parts = append(parts, s[start:i])
+
+			start = i + len(string(sep))
+		}
+	}
+
+	return append(parts, s[start:])
+}
+
+// FormatTextList formats the items as comma-separated text, escaping commas,
+// and backslashes with a backslash, e.g.: a,b\,c.
+func FormatTextList[T any](items []T) ([]byte, error) {
+	parts := make([]string, 0, len(items))
+
+	for _, item := range items {
+		text, err := FormatText(item)
+		if err != nil {
+			return nil, err
+		}
+
+		parts = append(parts, EscapeText(text, ","))
+	}
+
+	return []byte(strings.Join(parts, ",")), nil
+}
+
+// ParseTextList parses comma-separated text into items, the inverse of
+// FormatTextList. An empty text results in no items.
+func ParseTextList[T any](text []byte) ([]T, error) {
+	items := []T{}
+
+	if len(text) == 0 {
+		return items, nil
+	}
+
+	for _, part := range SplitText(string(text), ',', -1) {
+		item, err := ParseText[T](UnescapeText(part))
+		if err != nil {
+			return nil, err
+		}
+
+		items = append(items, item)
+	}
+
+	return items, nil
+}
+
+// EncodeList encodes the items as an array.
+func EncodeList[T any](enc Encoder, items []T) error {
+	if err := enc.EncodeArrayLen(len(items)); err != nil {
+		return err
+	}
+
+	for _, item := range items {
+		if err := enc.Encode(item); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// DecodeList decodes an array encoded by EncodeList. A nil array results in
+// no items.
+func DecodeList[T any](dec Decoder) ([]T, error) {
+	n, err := dec.DecodeArrayLen()
+	if err != nil {
+		return nil, err
+	}
+
+	items := []T{}
+
+	for i := 0; i < n; i++ {
+		var item T
+
+		if err := dec.Decode(&item); err != nil {
+			return nil, err
+		}
+
+		items = append(items, item)
+	}
+
+	return items, nil
+}
@@ -0,0 +1,153 @@
+package shared
+
+import (
+	"encoding/hex"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/thalesfsp/go-common-types/internal/codectest"
+)
+
+func TestGenerateHash(t *testing.T) {
+	assert.Equal(t, GenerateHash(1), GenerateHash(1))
+	assert.NotEqual(t, GenerateHash(1), GenerateHash(2))
+}
+
+func TestGenerateHashSum(t *testing.T) {
+	sum := GenerateHashSum("a")
+
+	assert.Equal(t, GenerateHash("a"), hex.EncodeToString(sum[:]))
+}
+
+func TestScanJSON(t *testing.T) {
+	var got string
+
+	unmarshal := func(data []byte) error {
+		got = string(data)
+
+		return nil
+	}
+
+	assert.NoError(t, ScanJSON([]byte("[1]"), unmarshal))
+	assert.Equal(t, "[1]", got)
+
+	assert.NoError(t, ScanJSON("[2]", unmarshal))
+	assert.Equal(t, "[2]", got)
+
+	assert.NoError(t, ScanJSON(nil, unmarshal))
+	assert.Equal(t, "null", got)
+
+	assert.Error(t, ScanJSON(1, unmarshal))
+}
+
+func TestFormatParseText(t *testing.T) {
+	text, err := FormatText("a,b")
+	assert.NoError(t, err)
+	assert.Equal(t, "a,b", text)
+
+	text, err = FormatText(1.5)
+	assert.NoError(t, err)
+	assert.Equal(t, "1.5", text)
+
+	date := time.Date(2023, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	text, err = FormatText(date)
+	assert.NoError(t, err)
+	assert.Equal(t, "2023-01-02T03:04:05Z", text)
+
+	parsedDate, err := ParseText[time.Time](text)
+	assert.NoError(t, err)
+	assert.True(t, date.Equal(parsedDate))
+
+	n, err := ParseText[int]("42")
+	assert.NoError(t, err)
+	assert.Equal(t, 42, n)
+
+	_, err = ParseText[int]("abc")
+	assert.Error(t, err)
+}
+
+func TestEscapeText(t *testing.T) {
+	escaped := EscapeText(`a,b=c\d`, ",=")
+
+	assert.Equal(t, `a\,b\=c\\d`, escaped)
+	assert.Equal(t, `a,b=c\d`, UnescapeText(escaped))
+}
+
+func TestSplitText(t *testing.T) {
+	assert.Equal(t, []string{"a", `b\,c`, ""}, SplitText(`a,b\,c,`, ',', -1))
+	assert.Equal(t, []string{"k", "v=w"}, SplitText("k=v=w", '=', 2))
+	assert.Equal(t, []string{""}, SplitText("", ',', -1))
+}
+
+func TestFormatParseTextList(t *testing.T) {
+	text, err := FormatTextList([]string{"a", "b,c", `d\`})
+	assert.NoError(t, err)
+	assert.Equal(t, `a,b\,c,d\\`, string(text))
+
+	items, err := ParseTextList[string](text)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a", "b,c", `d\`}, items)
+
+	items, err = ParseTextList[string](nil)
+	assert.NoError(t, err)
+	assert.Empty(t, items)
+}
+
+func TestEncodeDecodeList(t *testing.T) {
+	c := &codectest.TokenCodec{}
+
+	assert.NoError(t, EncodeList(c, []int{1, 2, 3}))
+	assert.Equal(t, []any{3, 1, 2, 3}, c.Tokens)
+
+	items, err := DecodeList[int](c)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{1, 2, 3}, items)
+
+	c = &codectest.TokenCodec{Tokens: []any{-1}}
+
+	items, err = DecodeList[int](c)
+	assert.NoError(t, err)
+	assert.Empty(t, items)
+
+	_, err = DecodeList[int](&codectest.TokenCodec{Tokens: []any{1}})
+	assert.Error(t, err)
+}
+
+// failingWriter is an io.Writer which always fails.
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) {
+	return 0, io.ErrClosedPipe
+}
+
+func TestJSONList(t *testing.T) {
+	var sb strings.Builder
+
+	assert.NoError(t, EncodeJSONList(&sb, []string{"a", "<b>", "c"}))
+	assert.Equal(t, `["a","\u003cb\u003e","c"]`, sb.String())
+
+	items, err := DecodeJSONList[string](strings.NewReader(sb.String()))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a", "<b>", "c"}, items)
+
+	sb.Reset()
+
+	assert.NoError(t, EncodeJSONList[int](&sb, nil))
+	assert.Equal(t, `[]`, sb.String())
+
+	items, err = DecodeJSONList[string](strings.NewReader("null"))
+	assert.NoError(t, err)
+	assert.Empty(t, items)
+
+	_, err = DecodeJSONList[string](strings.NewReader(`{"a":1}`))
+	assert.Error(t, err)
+
+	_, err = DecodeJSONList[string](strings.NewReader(`["a",`))
+	assert.Error(t, err)
+
+	assert.Error(t, EncodeJSONList(failingWriter{}, []int{1}))
+}
@@ -0,0 +1,36 @@
+package shared
+
+import "unsafe"
+
+//////
+// Const, vars, and types.
+//////
+
+// Sizer returns the number of bytes referenced by a value, beyond its fixed
+// size, e.g.: the contents of a string, or the elements of a slice.
+type Sizer[T any] func(T) int
+
+//////
+// Exported functionalities.
+//////
+
+// EstimateSize estimates the number of bytes used by the value: its fixed
+// size, as given by unsafe.Sizeof, plus the bytes it references, as given by
+// the sizer. Without a sizer, the contents of strings, and byte slices are
+// counted, other references, e.g.: pointers, or maps, aren't followed.
+func EstimateSize[T any](value T, sizer Sizer[T]) int64 {
+	size := int64(unsafe.Sizeof(value))
+
+	if sizer != nil {
+		return size + int64(sizer(value))
+	}
+
+	switch v := any(value).(type) {
+	case string:
+		size += int64(len(v))
+	case []byte:
+		size += int64(cap(v))
+	}
+
+	return size
+}
@@ -0,0 +1,20 @@
+package shared
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEstimateSize(t *testing.T) {
+	assert.Equal(t, int64(8), EstimateSize[int64](1, nil))
+	assert.Equal(t, int64(16+5), EstimateSize("hello", nil))
+	assert.Equal(t, int64(24+4), EstimateSize(make([]byte, 2, 4), nil))
+
+	// Without a sizer, other references aren't followed.
+	assert.Equal(t, int64(24), EstimateSize([]int64{1, 2}, nil))
+
+	sizer := func(v []int64) int { return 8 * len(v) }
+
+	assert.Equal(t, int64(24+16), EstimateSize([]int64{1, 2}, sizer))
+}
@@ -0,0 +1,84 @@
+package shared
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+//////
+// Exported functionalities.
+//////
+
+// EncodeJSONList writes the items to w as a JSON array, marshalling one item
+// at a time, so the encoded array is never entirely in memory.
+func EncodeJSONList[T any](w io.Writer, items []T) error {
+	bw := bufio.NewWriter(w)
+
+	if err := bw.WriteByte('['); err != nil {
+		return err
+	}
+
+	for i, item := range items {
+		if i > 0 {
+			if err := bw.WriteByte(','); err != nil {
+				return err
+			}
+		}
+
+		b, err := json.Marshal(item)
+		if err != nil {
+			return err
+		}
+
+		if _, err := bw.Write(b); err != nil {
+			return err
+		}
+	}
+
+	if err := bw.WriteByte(']'); err != nil {
+		return err
+	}
+
+	return bw.Flush()
+}
+
+// DecodeJSONList reads a JSON array from r, decoding one item at a time, so
+// the encoded array is never entirely in memory. A JSON null results in no
+// items.
+func DecodeJSONList[T any](r io.Reader) ([]T, error) {
+	dec := json.NewDecoder(r)
+
+	items := []T{}
+
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	if tok == nil {
+		return items, nil
+	}
+
+	if tok != json.Delim('[') {
+		return nil, fmt.Errorf("expected a JSON array, got %v", tok)
+	}
+
+	for dec.More() {
+		var item T
+
+		if err := dec.Decode(&item); err != nil {
+			return nil, err
+		}
+
+		items = append(items, item)
+	}
+
+	// Consumes the closing bracket.
+	if _, err := dec.Token(); err != nil {
+		return nil, err
+	}
+
+	return items, nil
+}
@@ -0,0 +1,49 @@
+package statistical
+
+import (
+	"math"
+	"math/rand"
+)
+
+//////
+// Exported functionalities.
+//////
+
+// Bootstrap estimates the statistic, e.g.: Median, of the population the
+// sample s comes from, by computing it over iterations resamples of s, drawn
+// with replacement using rng, or the default source if nil. It returns the
+// mean of the resampled statistics, and the bounds of their 95% confidence
+// interval, by the percentile method. All are NaN if s is empty, or
+// iterations isn't positive.
+func Bootstrap(
+	s []float64,
+	iterations int,
+	statistic func([]float64) float64,
+	rng *rand.Rand,
+) (mean, ciLow, ciHigh float64) {
+	if len(s) == 0 || iterations <= 0 {
+		return math.NaN(), math.NaN(), math.NaN()
+	}
+
+	intn := rand.Intn
+	if rng != nil {
+		intn = rng.Intn
+	}
+
+	stats := make([]float64, iterations)
+	resample := make([]float64, len(s))
+
+	for i := range stats {
+		for j := range resample {
+			resample[j] = s[intn(len(s))]
+		}
+
+		stats[i] = statistic(resample)
+	}
+
+	mean, _ = Mean(stats)
+	ciLow, _ = Percentile(stats, 0.025)
+	ciHigh, _ = Percentile(stats, 0.975)
+
+	return mean, ciLow, ciHigh
+}
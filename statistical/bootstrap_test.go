@@ -0,0 +1,41 @@
+package statistical
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBootstrap(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+
+	s := make([]float64, 200)
+	for i := range s {
+		s[i] = 10 + rng.NormFloat64()
+	}
+
+	mean := func(s []float64) float64 {
+		m, _ := Mean(s)
+
+		return m
+	}
+
+	estimate, low, high := Bootstrap(s, 1000, mean, rand.New(rand.NewSource(2)))
+
+	assert.InDelta(t, 10, estimate, 0.3)
+	assert.Less(t, low, estimate)
+	assert.Greater(t, high, estimate)
+	assert.InDelta(t, 0.28, high-low, 0.1)
+
+	// Same seed, same result.
+	again, _, _ := Bootstrap(s, 1000, mean, rand.New(rand.NewSource(2)))
+	assert.Equal(t, estimate, again)
+
+	estimate, _, _ = Bootstrap(s, 10, mean, nil)
+	assert.InDelta(t, 10, estimate, 0.5)
+
+	estimate, low, high = Bootstrap(nil, 10, mean, nil)
+	assert.True(t, math.IsNaN(estimate) && math.IsNaN(low) && math.IsNaN(high))
+}
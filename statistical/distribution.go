@@ -0,0 +1,129 @@
+package statistical
+
+import (
+	"fmt"
+	"math"
+)
+
+//////
+// Exported functionalities.
+//////
+
+// NormalPDF returns the probability density of the normal distribution with
+// the given mean, and standard deviation at x. It returns NaN if the standard
+// deviation isn't positive.
+func NormalPDF(x, mean, stddev float64) float64 {
+	if !(stddev > 0) {
+		return math.NaN()
+	}
+
+	z := (x - mean) / stddev
+
+	return math.Exp(-z*z/2) / (stddev * math.Sqrt(2*math.Pi))
+}
+
+// NormalCDF returns the probability of the normal distribution with the given
+// mean, and standard deviation to be less than or equal to x. It returns NaN
+// if the standard deviation isn't positive.
+func NormalCDF(x, mean, stddev float64) float64 {
+	if !(stddev > 0) {
+		return math.NaN()
+	}
+
+	return (1 + math.Erf((x-mean)/(stddev*math.Sqrt2))) / 2
+}
+
+// NormalQuantile is the inverse of NormalCDF, returning the value x for which
+// the probability to be less than or equal to it is p. It returns NaN if p
+// isn't in the [0, 1] range, or the standard deviation isn't positive.
+func NormalQuantile(p, mean, stddev float64) float64 {
+	if !(stddev > 0) || !(p >= 0 && p <= 1) {
+		return math.NaN()
+	}
+
+	return mean + stddev*math.Sqrt2*math.Erfinv(2*p-1)
+}
+
+// BinomialPMF returns the probability of exactly k successes in n independent
+// trials, each one with a probability p of success. It returns 0 if k isn't in
+// the [0, n] range, and NaN if p isn't in the [0, 1] range.
+func BinomialPMF(k, n int, p float64) float64 {
+	if !(p >= 0 && p <= 1) {
+		return math.NaN()
+	}
+
+	if k < 0 || k > n {
+		return 0
+	}
+
+	// Edge cases, where the logarithms below are undefined.
+	switch {
+	case p == 0 && k == 0, p == 1 && k == n:
+		return 1
+	case p == 0, p == 1:
+		return 0
+	}
+
+	return math.Exp(logChoose(n, k) + float64(k)*math.Log(p) + float64(n-k)*math.Log1p(-p))
+}
+
+// PoissonPMF returns the probability of exactly k events in an interval, given
+// the average number of events per interval, lambda. It returns 0 if k is
+// negative, and NaN if lambda is negative.
+func PoissonPMF(k int, lambda float64) float64 {
+	if !(lambda >= 0) {
+		return math.NaN()
+	}
+
+	if k < 0 {
+		return 0
+	}
+
+	if lambda == 0 {
+		if k == 0 {
+			return 1
+		}
+
+		return 0
+	}
+
+	lgamma, _ := math.Lgamma(float64(k + 1))
+
+	return math.Exp(float64(k)*math.Log(lambda) - lambda - lgamma)
+}
+
+// ConfidenceInterval returns the lower, and upper bounds of the confidence
+// interval of a mean, estimated from n samples with the given standard
+// deviation, at the given confidence level (between 0 and 1, e.g.: 0.95). It
+// uses the normal approximation, suitable for large samples (n >= 30).
+func ConfidenceInterval(mean, stddev float64, n int, level float64) (float64, float64, error) {
+	if n < 1 {
+		return 0, 0, fmt.Errorf("confidence interval requires at least one sample")
+	}
+
+	if !(stddev >= 0) {
+		return 0, 0, fmt.Errorf("standard deviation must not be negative, got %v", stddev)
+	}
+
+	if !(level > 0 && level < 1) {
+		return 0, 0, fmt.Errorf("confidence level must be between 0 and 1, got %v", level)
+	}
+
+	margin := NormalQuantile((1+level)/2, 0, 1) * stddev / math.Sqrt(float64(n))
+
+	return mean - margin, mean + margin, nil
+}
+
+//////
+// Helpers.
+//////
+
+// logChoose returns the natural logarithm of the binomial coefficient n choose
+// k.
+func logChoose(n, k int) float64 {
+	a, _ := math.Lgamma(float64(n + 1))
+	b, _ := math.Lgamma(float64(k + 1))
+	c, _ := math.Lgamma(float64(n - k + 1))
+
+	return a - b - c
+}
@@ -0,0 +1,92 @@
+package statistical
+
+import (
+	"math"
+	"testing"
+)
+
+func TestNormal(t *testing.T) {
+	if pdf := NormalPDF(0, 0, 1); !approxEqual(t, pdf, 0.398942, 1e-6) {
+		t.Errorf("Expected NormalPDF(0) to be 0.398942, got %v", pdf)
+	}
+
+	if cdf := NormalCDF(1.96, 0, 1); !approxEqual(t, cdf, 0.975002, 1e-6) {
+		t.Errorf("Expected NormalCDF(1.96) to be 0.975002, got %v", cdf)
+	}
+
+	if cdf := NormalCDF(110, 100, 10); !approxEqual(t, cdf, 0.841345, 1e-6) {
+		t.Errorf("Expected NormalCDF(110, 100, 10) to be 0.841345, got %v", cdf)
+	}
+
+	if x := NormalQuantile(0.975, 0, 1); !approxEqual(t, x, 1.959964, 1e-6) {
+		t.Errorf("Expected NormalQuantile(0.975) to be 1.959964, got %v", x)
+	}
+
+	if !math.IsNaN(NormalPDF(0, 0, 0)) || !math.IsNaN(NormalCDF(0, 0, -1)) || !math.IsNaN(NormalQuantile(2, 0, 1)) {
+		t.Errorf("Expected NaN for invalid parameters")
+	}
+}
+
+func TestBinomialPMF(t *testing.T) {
+	if pmf := BinomialPMF(3, 10, 0.5); !approxEqual(t, pmf, 0.117188, 1e-6) {
+		t.Errorf("Expected BinomialPMF(3, 10, 0.5) to be 0.117188, got %v", pmf)
+	}
+
+	sum := 0.0
+
+	for k := 0; k <= 20; k++ {
+		sum += BinomialPMF(k, 20, 0.3)
+	}
+
+	if !approxEqual(t, sum, 1, 1e-9) {
+		t.Errorf("Expected the probabilities to sum to 1, got %v", sum)
+	}
+
+	if BinomialPMF(0, 5, 0) != 1 || BinomialPMF(5, 5, 1) != 1 || BinomialPMF(1, 5, 0) != 0 || BinomialPMF(6, 5, 0.5) != 0 {
+		t.Errorf("Unexpected edge cases")
+	}
+
+	if !math.IsNaN(BinomialPMF(1, 5, 1.5)) {
+		t.Errorf("Expected NaN for an invalid probability")
+	}
+}
+
+func TestPoissonPMF(t *testing.T) {
+	if pmf := PoissonPMF(2, 3); !approxEqual(t, pmf, 0.224042, 1e-6) {
+		t.Errorf("Expected PoissonPMF(2, 3) to be 0.224042, got %v", pmf)
+	}
+
+	if PoissonPMF(0, 0) != 1 || PoissonPMF(1, 0) != 0 || PoissonPMF(-1, 3) != 0 {
+		t.Errorf("Unexpected edge cases")
+	}
+
+	if !math.IsNaN(PoissonPMF(1, -1)) {
+		t.Errorf("Expected NaN for a negative lambda")
+	}
+}
+
+func TestConfidenceInterval(t *testing.T) {
+	lower, upper, err := ConfidenceInterval(100, 15, 36, 0.95)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !approxEqual(t, lower, 95.100090, 1e-5) || !approxEqual(t, upper, 104.899910, 1e-5) {
+		t.Errorf("Expected (95.100090, 104.899910), got (%v, %v)", lower, upper)
+	}
+
+	for _, tt := range []struct {
+		stddev float64
+		n      int
+		level  float64
+	}{
+		{15, 0, 0.95},
+		{-1, 36, 0.95},
+		{15, 36, 1},
+		{15, 36, 0},
+	} {
+		if _, _, err := ConfidenceInterval(100, tt.stddev, tt.n, tt.level); err == nil {
+			t.Errorf("Expected an error for %+v", tt)
+		}
+	}
+}
@@ -0,0 +1,261 @@
+package statistical
+
+import (
+	"fmt"
+	"math"
+)
+
+//////
+// Const, vars, and types.
+//////
+
+const (
+	// maxIterations bounds the evaluation of the series, and continued
+	// fractions of the incomplete beta, and gamma functions.
+	maxIterations = 300
+
+	// epsilon is the relative accuracy of the incomplete beta, and gamma
+	// functions.
+	epsilon = 3e-14
+
+	// tiny replaces zero denominators of the continued fractions.
+	tiny = 1e-300
+)
+
+//////
+// Exported functionalities.
+//////
+
+// TTestTwoSample runs Welch's t-test, checking if two independent samples,
+// e.g.: the cohorts of an experiment, have different means, without assuming
+// equal variances. It returns the t statistic, and the two-sided p-value,
+// the probability of a difference at least as large if the means were equal.
+// Each sample needs at least two elements, and both can't be constant.
+func TTestTwoSample(a, b []float64) (float64, float64, error) {
+	if len(a) < 2 || len(b) < 2 {
+		return 0, 0, fmt.Errorf("t-test requires at least two elements per sample")
+	}
+
+	meanA, _ := Mean(a)
+	meanB, _ := Mean(b)
+	varianceA, _ := Variance(a)
+	varianceB, _ := Variance(b)
+
+	na, nb := float64(len(a)), float64(len(b))
+
+	errA, errB := varianceA/na, varianceB/nb
+
+	if errA+errB == 0 {
+		return 0, 0, fmt.Errorf("t-test requires non-constant samples")
+	}
+
+	t := (meanA - meanB) / math.Sqrt(errA+errB)
+
+	// Welch-Satterthwaite degrees of freedom.
+	df := (errA + errB) * (errA + errB) / (errA*errA/(na-1) + errB*errB/(nb-1))
+
+	return t, studentTwoSided(t, df), nil
+}
+
+// PairedTTest runs the paired t-test, checking if the mean difference of
+// paired observations, e.g.: before, and after a change, is different from 0.
+// It returns the t statistic, and the two-sided p-value. The samples must
+// have the same length, of at least two, and the differences can't be
+// constant.
+func PairedTTest(a, b []float64) (float64, float64, error) {
+	if len(a) != len(b) {
+		return 0, 0, fmt.Errorf("paired t-test requires samples of the same length, got %d, and %d", len(a), len(b))
+	}
+
+	if len(a) < 2 {
+		return 0, 0, fmt.Errorf("paired t-test requires at least two pairs")
+	}
+
+	diffs := make([]float64, len(a))
+
+	for i := range a {
+		diffs[i] = a[i] - b[i]
+	}
+
+	mean, _ := Mean(diffs)
+	stddev, _ := StandardDeviation(diffs)
+
+	if stddev == 0 {
+		return 0, 0, fmt.Errorf("paired t-test requires non-constant differences")
+	}
+
+	n := float64(len(diffs))
+
+	t := mean / (stddev / math.Sqrt(n))
+
+	return t, studentTwoSided(t, n-1), nil
+}
+
+// ChiSquare runs Pearson's chi-square goodness of fit test, checking if the
+// observed frequencies differ from the expected ones. It returns the
+// chi-square statistic, and the p-value, with one degree of freedom less than
+// the number of categories. There must be at least two categories, with
+// positive expected frequencies.
+func ChiSquare(observed, expected []float64) (float64, float64, error) {
+	if len(observed) != len(expected) {
+		return 0, 0, fmt.Errorf("chi-square requires the same number of categories, got %d, and %d", len(observed), len(expected))
+	}
+
+	if len(observed) < 2 {
+		return 0, 0, fmt.Errorf("chi-square requires at least two categories")
+	}
+
+	stat := 0.0
+
+	for i, o := range observed {
+		e := expected[i]
+
+		if !(e > 0) {
+			return 0, 0, fmt.Errorf("chi-square requires positive expected frequencies, got %v", e)
+		}
+
+		stat += (o - e) * (o - e) / e
+	}
+
+	df := float64(len(observed) - 1)
+
+	return stat, upperIncompleteGamma(df/2, stat/2), nil
+}
+
+//////
+// Helpers.
+//////
+
+// studentTwoSided returns the probability of the Student's t distribution,
+// with df degrees of freedom, to be at least |t| away from 0.
+func studentTwoSided(t, df float64) float64 {
+	return incompleteBeta(df/2, 0.5, df/(df+t*t))
+}
+
+// incompleteBeta returns the regularized incomplete beta function I_x(a, b).
+func incompleteBeta(a, b, x float64) float64 {
+	if x <= 0 {
+		return 0
+	}
+
+	if x >= 1 {
+		return 1
+	}
+
+	lab, _ := math.Lgamma(a + b)
+	la, _ := math.Lgamma(a)
+	lb, _ := math.Lgamma(b)
+
+	front := math.Exp(lab - la - lb + a*math.Log(x) + b*math.Log(1-x))
+
+	// The continued fraction converges quickly on this side, use the
+	// symmetry I_x(a, b) = 1 - I_(1-x)(b, a) on the other one.
+	if x < (a+1)/(a+b+2) {
+		return front * betaFraction(a, b, x) / a
+	}
+
+	return 1 - front*betaFraction(b, a, 1-x)/b
+}
+
+// betaFraction evaluates the continued fraction of the incomplete beta
+// function, by the modified Lentz's method.
+func betaFraction(a, b, x float64) float64 {
+	c, d := 1.0, 1-(a+b)*x/(a+1)
+
+	if math.Abs(d) < tiny {
+		d = tiny
+	}
+
+	d = 1 / d
+	h := d
+
+	// step applies a term of the fraction, returning its factor.
+	step := func(numerator float64) float64 {
+		d = 1 + numerator*d
+		if math.Abs(d) < tiny {
+			d = tiny
+		}
+
+		c = 1 + numerator/c
+		if math.Abs(c) < tiny {
+			c = tiny
+		}
+
+		d = 1 / d
+
+		return d * c
+	}
+
+	for m := 1.0; m <= maxIterations; m++ {
+		h *= step(m * (b - m) * x / ((a + 2*m - 1) * (a + 2*m)))
+
+		factor := step(-(a + m) * (a + b + m) * x / ((a + 2*m) * (a + 2*m + 1)))
+
+		h *= factor
+
+		if math.Abs(factor-1) < epsilon {
+			break
+		}
+	}
+
+	return h
+}
+
+// upperIncompleteGamma returns the regularized upper incomplete gamma
+// function Q(a, x), by its series below a+1, and its continued fraction
+// above.
+func upperIncompleteGamma(a, x float64) float64 {
+	if x <= 0 {
+		return 1
+	}
+
+	lga, _ := math.Lgamma(a)
+
+	front := math.Exp(-x + a*math.Log(x) - lga)
+
+	if x < a+1 {
+		sum, term := 1/a, 1/a
+
+		for n := 1.0; n <= maxIterations; n++ {
+			term *= x / (a + n)
+			sum += term
+
+			if math.Abs(term) < math.Abs(sum)*epsilon {
+				break
+			}
+		}
+
+		return 1 - front*sum
+	}
+
+	b := x + 1 - a
+	c, d := 1/tiny, 1/b
+	h := d
+
+	for i := 1.0; i <= maxIterations; i++ {
+		an := -i * (i - a)
+		b += 2
+
+		d = an*d + b
+		if math.Abs(d) < tiny {
+			d = tiny
+		}
+
+		c = b + an/c
+		if math.Abs(c) < tiny {
+			c = tiny
+		}
+
+		d = 1 / d
+
+		factor := d * c
+
+		h *= factor
+
+		if math.Abs(factor-1) < epsilon {
+			break
+		}
+	}
+
+	return front * h
+}
@@ -0,0 +1,61 @@
+package statistical
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTTestTwoSample(t *testing.T) {
+	tstat, p, err := TTestTwoSample([]float64{1, 2, 3, 4, 5}, []float64{2, 4, 6, 8, 10})
+
+	assert.NoError(t, err)
+	assert.InDelta(t, -1.8974, tstat, 1e-4)
+	assert.InDelta(t, 0.1075, p, 1e-3)
+
+	_, p, err = TTestTwoSample([]float64{1, 2, 3}, []float64{1, 2, 3})
+
+	assert.NoError(t, err)
+	assert.InDelta(t, 1, p, 1e-9)
+
+	_, _, err = TTestTwoSample([]float64{1}, []float64{1, 2})
+	assert.Error(t, err)
+
+	_, _, err = TTestTwoSample([]float64{1, 1}, []float64{2, 2})
+	assert.Error(t, err)
+}
+
+func TestPairedTTest(t *testing.T) {
+	tstat, p, err := PairedTTest([]float64{1, 2, 3, 4, 5}, []float64{2, 3, 5, 5, 7})
+
+	assert.NoError(t, err)
+	assert.InDelta(t, -5.7155, tstat, 1e-4)
+	assert.InDelta(t, 0.004636, p, 1e-5)
+
+	_, _, err = PairedTTest([]float64{1, 2}, []float64{1})
+	assert.Error(t, err)
+
+	_, _, err = PairedTTest([]float64{1, 2}, []float64{2, 3})
+	assert.Error(t, err)
+}
+
+func TestChiSquare(t *testing.T) {
+	stat, p, err := ChiSquare([]float64{10, 20, 30}, []float64{20, 20, 20})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 10.0, stat)
+	assert.InDelta(t, math.Exp(-5), p, 1e-9)
+
+	// Small statistic, computed by the series.
+	_, p, err = ChiSquare([]float64{19, 21}, []float64{20, 20})
+
+	assert.NoError(t, err)
+	assert.InDelta(t, math.Erfc(math.Sqrt(0.05)), p, 1e-9)
+
+	_, _, err = ChiSquare([]float64{1, 2}, []float64{1, 0})
+	assert.Error(t, err)
+
+	_, _, err = ChiSquare([]float64{1}, []float64{1})
+	assert.Error(t, err)
+}
@@ -0,0 +1,104 @@
+package statistical
+
+import (
+	"sort"
+
+	"golang.org/x/exp/constraints"
+)
+
+//////
+// Exported functionalities.
+//////
+
+// Rank returns the rank of each element, like a leaderboard: the largest is
+// 1, and equal elements share the rank, with a gap after them, e.g.: [10, 30,
+// 20, 30] are ranked [4, 1, 3, 1]. The slice isn't modified.
+func Rank[T constraints.Ordered](s []T) []int {
+	order := sortedIndexes(s, func(a, b T) bool { return a > b })
+
+	ranks := make([]int, len(s))
+
+	for i, idx := range order {
+		if i > 0 && s[idx] == s[order[i-1]] {
+			ranks[idx] = ranks[order[i-1]]
+
+			continue
+		}
+
+		ranks[idx] = i + 1
+	}
+
+	return ranks
+}
+
+// PercentileRank returns the percentage, between 0 and 100, of the elements
+// less than the value, counting the equal ones as half, e.g.: 50 for the
+// median. It's 0 if the slice is empty.
+func PercentileRank[T constraints.Ordered](s []T, value T) float64 {
+	if len(s) == 0 {
+		return 0
+	}
+
+	below := 0.0
+
+	for _, x := range s {
+		switch {
+		case x < value:
+			below++
+		case x == value:
+			below += 0.5
+		}
+	}
+
+	return below / float64(len(s)) * 100
+}
+
+// TopN returns the n largest elements, from the largest. Equal elements keep
+// their order. The slice isn't modified.
+func TopN[T constraints.Ordered](s []T, n int) []T {
+	return firstN(s, n, func(a, b T) bool { return a > b })
+}
+
+// BottomN returns the n smallest elements, from the smallest. Equal elements
+// keep their order. The slice isn't modified.
+func BottomN[T constraints.Ordered](s []T, n int) []T {
+	return firstN(s, n, func(a, b T) bool { return a < b })
+}
+
+//////
+// Helpers.
+//////
+
+// sortedIndexes returns the indexes of the elements, sorted stably by less.
+func sortedIndexes[T any](s []T, less func(a, b T) bool) []int {
+	indexes := make([]int, len(s))
+
+	for i := range indexes {
+		indexes[i] = i
+	}
+
+	sort.SliceStable(indexes, func(i, j int) bool {
+		return less(s[indexes[i]], s[indexes[j]])
+	})
+
+	return indexes
+}
+
+// firstN returns the first n elements, sorted stably by less.
+func firstN[T any](s []T, n int, less func(a, b T) bool) []T {
+	if n > len(s) {
+		n = len(s)
+	}
+
+	if n < 0 {
+		n = 0
+	}
+
+	result := make([]T, 0, n)
+
+	for _, idx := range sortedIndexes(s, less)[:n] {
+		result = append(result, s[idx])
+	}
+
+	return result
+}
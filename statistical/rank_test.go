@@ -0,0 +1,36 @@
+package statistical
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRank(t *testing.T) {
+	s := []int{10, 30, 20, 30}
+
+	assert.Equal(t, []int{4, 1, 3, 1}, Rank(s))
+	assert.Equal(t, []int{10, 30, 20, 30}, s)
+	assert.Empty(t, Rank([]float64{}))
+	assert.Equal(t, []int{2, 1}, Rank([]string{"a", "b"}))
+}
+
+func TestPercentileRank(t *testing.T) {
+	s := []int{1, 2, 3, 4, 5}
+
+	assert.Equal(t, 50.0, PercentileRank(s, 3))
+	assert.Equal(t, 10.0, PercentileRank(s, 1))
+	assert.Equal(t, 100.0, PercentileRank(s, 6))
+	assert.Equal(t, 0.0, PercentileRank(s, 0))
+	assert.Equal(t, 0.0, PercentileRank([]int{}, 1))
+}
+
+func TestTopN(t *testing.T) {
+	s := []int{5, 1, 4, 2, 3}
+
+	assert.Equal(t, []int{5, 4, 3}, TopN(s, 3))
+	assert.Equal(t, []int{1, 2}, BottomN(s, 2))
+	assert.Equal(t, []int{1, 2, 3, 4, 5}, BottomN(s, 10))
+	assert.Empty(t, TopN(s, -1))
+	assert.Equal(t, []int{5, 1, 4, 2, 3}, s)
+}
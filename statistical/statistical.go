@@ -68,31 +68,40 @@ func Range[T Numbers](s []T) (T, T, error) {
 	return s[0], s[n-1], nil
 }
 
-// Mean calculates the mean (average) value of a slice of numbers.
-func Mean(s []float64) float64 {
+// Mean calculates the mean (average) value of a slice of numbers. Numbers are
+// accumulated as float64, so large integers don't overflow.
+func Mean[T Numbers](s []T) (float64, error) {
+	if len(s) == 0 {
+		return 0, fmt.Errorf("cannot calculate mean of empty slice")
+	}
+
 	sum := 0.0
 
 	for _, x := range s {
-		sum += x
+		sum += float64(x)
 	}
 
-	return sum / float64(len(s))
+	return sum / float64(len(s)), nil
 }
 
-// Variance calculates the variance of a slice of numbers.
-func Variance(s []float64) (float64, error) {
+// Variance calculates the sample variance of a slice of numbers. Numbers are
+// accumulated as float64, so large integers don't overflow.
+func Variance[T Numbers](s []T) (float64, error) {
 	n := len(s)
 
 	if n < 2 {
 		return 0, fmt.Errorf("variance requires at least two elements")
 	}
 
-	mean := Mean(s)
+	mean, err := Mean(s)
+	if err != nil {
+		return 0, err
+	}
 
 	variance := 0.0
 
 	for _, x := range s {
-		variance += (x - mean) * (x - mean)
+		variance += (float64(x) - mean) * (float64(x) - mean)
 	}
 
 	variance /= float64(n - 1)
@@ -100,8 +109,9 @@ func Variance(s []float64) (float64, error) {
 	return variance, nil
 }
 
-// StandardDeviation calculates the standard deviation of a slice of numbers.
-func StandardDeviation(s []float64) (float64, error) {
+// StandardDeviation calculates the sample standard deviation of a slice of
+// numbers.
+func StandardDeviation[T Numbers](s []T) (float64, error) {
 	variance, err := Variance(s)
 	if err != nil {
 		return 0, err
@@ -135,3 +145,126 @@ func Percentile[T Numbers](s []T, p float64) (T, error) {
 
 	return T(result), nil
 }
+
+// Skewness calculates the sample skewness (adjusted Fisher-Pearson) of a slice
+// of numbers, the asymmetry of their distribution: positive if the right tail
+// is longer, negative if the left one is.
+func Skewness[T Numbers](s []T) (float64, error) {
+	n := float64(len(s))
+
+	if n < 3 {
+		return 0, fmt.Errorf("skewness requires at least three elements")
+	}
+
+	mean, stddev, err := meanStandardDeviation(s)
+	if err != nil {
+		return 0, err
+	}
+
+	sum := 0.0
+
+	for _, x := range s {
+		sum += math.Pow((float64(x)-mean)/stddev, 3)
+	}
+
+	return n / ((n - 1) * (n - 2)) * sum, nil
+}
+
+// Kurtosis calculates the sample excess kurtosis of a slice of numbers, how
+// heavy the tails of their distribution are compared to a normal one, which
+// is 0.
+func Kurtosis[T Numbers](s []T) (float64, error) {
+	n := float64(len(s))
+
+	if n < 4 {
+		return 0, fmt.Errorf("kurtosis requires at least four elements")
+	}
+
+	mean, stddev, err := meanStandardDeviation(s)
+	if err != nil {
+		return 0, err
+	}
+
+	sum := 0.0
+
+	for _, x := range s {
+		sum += math.Pow((float64(x)-mean)/stddev, 4)
+	}
+
+	return n*(n+1)/((n-1)*(n-2)*(n-3))*sum - 3*(n-1)*(n-1)/((n-2)*(n-3)), nil
+}
+
+// ZScores returns, for each number, how many standard deviations it's away
+// from the mean. If there are less than two elements, or all of them are
+// equal, there's no deviation, and all the scores are 0.
+func ZScores[T Numbers](s []T) []float64 {
+	scores := make([]float64, len(s))
+
+	mean, stddev, err := meanStandardDeviation(s)
+	if err != nil {
+		return scores
+	}
+
+	for i, x := range s {
+		scores[i] = (float64(x) - mean) / stddev
+	}
+
+	return scores
+}
+
+// MinMaxScale returns the numbers linearly scaled to the [lower, upper] range,
+// the minimum becoming lower, and the maximum upper. If all of them are equal,
+// they all become lower.
+func MinMaxScale[T Numbers](s []T, lower, upper float64) []float64 {
+	scaled := make([]float64, len(s))
+
+	if len(s) == 0 {
+		return scaled
+	}
+
+	minimum, maximum := float64(s[0]), float64(s[0])
+
+	for _, x := range s[1:] {
+		minimum = math.Min(minimum, float64(x))
+		maximum = math.Max(maximum, float64(x))
+	}
+
+	for i, x := range s {
+		scaled[i] = lower
+
+		if maximum > minimum {
+			scaled[i] += (float64(x) - minimum) / (maximum - minimum) * (upper - lower)
+		}
+	}
+
+	return scaled
+}
+
+// Normalize returns the numbers scaled to the [0, 1] range, see MinMaxScale.
+func Normalize[T Numbers](s []T) []float64 {
+	return MinMaxScale(s, 0, 1)
+}
+
+//////
+// Helpers.
+//////
+
+// meanStandardDeviation returns the mean, and the standard deviation of the
+// numbers, which must not be 0.
+func meanStandardDeviation[T Numbers](s []T) (float64, float64, error) {
+	stddev, err := StandardDeviation(s)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	if stddev == 0 {
+		return 0, 0, fmt.Errorf("standard deviation is zero, all elements are equal")
+	}
+
+	mean, err := Mean(s)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return mean, stddev, nil
+}
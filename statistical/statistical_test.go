@@ -103,26 +103,49 @@ func TestRange(t *testing.T) {
 func TestMean(t *testing.T) {
 	s := []float64{1, 2, 3, 4}
 
-	mean := Mean(s)
+	mean, err := Mean(s)
+	if err != nil {
+		t.Fatal(err)
+	}
 
 	if mean != 2.5 {
 		t.Errorf("Expected mean to be 2.5, got %v", mean)
 	}
 
-	s = []float64{1, 2}
-
-	mean = Mean(s)
+	mean, _ = Mean([]int{1, 2})
 
 	if mean != 1.5 {
 		t.Errorf("Expected mean to be 1.5, got %v", mean)
 	}
 
-	s = []float64{}
+	mean, _ = Mean([]int64{math.MaxInt64, math.MaxInt64})
 
-	mean = Mean(s)
+	if mean != math.MaxInt64 {
+		t.Errorf("Expected mean not to overflow, got %v", mean)
+	}
 
-	if !math.IsNaN(mean) {
-		t.Errorf("Expected mean of empty slice to be NaN, got %v", mean)
+	if _, err = Mean([]float64{}); err == nil {
+		t.Errorf("Expected error calculating mean of empty slice")
+	}
+}
+
+func TestGenericVariance(t *testing.T) {
+	variance, err := Variance([]int8{100, 120, 127})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !approxEqual(t, variance, 196.333333, 1e-6) {
+		t.Errorf("Expected variance to be 196.333333, got %v", variance)
+	}
+
+	stddev, err := StandardDeviation([]float32{1, 2, 3, 4, 5})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !approxEqual(t, stddev, 1.5811388, 1e-6) {
+		t.Errorf("Expected standard deviation to be 1.5811388, got %v", stddev)
 	}
 }
 
@@ -164,6 +187,8 @@ func TestVariance(t *testing.T) {
 	}
 
 	for _, tt := range tests {
+		tt := tt
+
 		t.Run(tt.name, func(t *testing.T) {
 			// Add the t.Parallel() call here
 			t.Parallel()
@@ -235,7 +260,7 @@ func ExampleRange() {
 
 func ExampleMean() {
 	s := []float64{1, 2, 3, 4, 5}
-	mean := Mean(s)
+	mean, _ := Mean(s)
 	fmt.Printf("Mean: %v\n", mean)
 	// Output: Mean: 3
 }
@@ -260,3 +285,79 @@ func ExamplePercentile() {
 	fmt.Printf("Percentile: %v\n", percentile)
 	// Output: Percentile: 3
 }
+
+func TestSkewnessKurtosis(t *testing.T) {
+	s := []float64{2, 8, 0, 4, 1, 9, 9, 0}
+
+	skewness, err := Skewness(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !approxEqual(t, skewness, 0.330582, 1e-6) {
+		t.Errorf("Expected skewness to be 0.330582, got %v", skewness)
+	}
+
+	kurtosis, err := Kurtosis(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !approxEqual(t, kurtosis, -2.098602, 1e-6) {
+		t.Errorf("Expected kurtosis to be -2.098602, got %v", kurtosis)
+	}
+
+	if _, err := Skewness([]float64{1, 2}); err == nil {
+		t.Errorf("Expected an error for less than three elements")
+	}
+
+	if _, err := Kurtosis([]float64{1, 1, 1, 1}); err == nil {
+		t.Errorf("Expected an error for equal elements")
+	}
+}
+
+func TestZScores(t *testing.T) {
+	scores := ZScores([]float64{2, 8, 0, 4, 1, 9, 9, 0})
+
+	if !approxEqual(t, scores[0], -0.533938, 1e-6) || !approxEqual(t, scores[1], 0.973652, 1e-6) {
+		t.Errorf("Unexpected z-scores %v", scores)
+	}
+
+	for _, score := range ZScores([]float64{3, 3, 3}) {
+		if score != 0 {
+			t.Errorf("Expected z-scores of equal elements to be 0, got %v", score)
+		}
+	}
+
+	if len(ZScores([]float64(nil))) != 0 {
+		t.Errorf("Expected no z-scores for an empty slice")
+	}
+}
+
+func TestMinMaxScale(t *testing.T) {
+	s := []float64{10, 20, 15, 30}
+
+	expected := []float64{0, 0.5, 0.25, 1}
+
+	for i, x := range Normalize(s) {
+		if !approxEqual(t, x, expected[i], 1e-9) {
+			t.Errorf("Expected %v, got %v", expected, Normalize(s))
+		}
+	}
+
+	expected = []float64{-1, 0, -0.5, 1}
+
+	for i, x := range MinMaxScale(s, -1, 1) {
+		if !approxEqual(t, x, expected[i], 1e-9) {
+			t.Errorf("Expected %v, got %v", expected, MinMaxScale(s, -1, 1))
+		}
+	}
+
+	if s[0] != 10 {
+		t.Errorf("Expected the input to be left untouched")
+	}
+
+	if fmt.Sprint(Normalize([]float64{5, 5})) != "[0 0]" {
+		t.Errorf("Expected equal elements to be scaled to the lower bound")
+	}
+}
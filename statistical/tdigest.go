@@ -0,0 +1,348 @@
+package statistical
+
+import (
+	"encoding/binary"
+	"errors"
+	"math"
+	"sort"
+	"sync"
+)
+
+//////
+// Const, vars, and types.
+//////
+
+// DefaultCompression is the compression of a TDigest when the given one isn't
+// positive. It bounds the number of centroids to about 2x its value.
+const DefaultCompression = 100
+
+// tdigestVersion is the version of the binary serialization format.
+const tdigestVersion = 1
+
+// ErrInvalidTDigest is returned when unmarshalling invalid binary data.
+var ErrInvalidTDigest = errors.New("invalid t-digest data")
+
+// centroid is a cluster of samples, summarized by their mean, and count.
+type centroid struct {
+	mean   float64
+	weight float64
+}
+
+// TDigest is a quantile sketch, safe for concurrent use. It estimates
+// quantiles of millions of samples using a small, bounded, amount of memory,
+// with a better accuracy at the extremes (e.g.: p99), and digests can be
+// merged, e.g.: to combine the latencies reported by many instances.
+//
+// It's the merging variant of the t-digest, by Ted Dunning: samples are
+// buffered, and periodically merged into the centroids. The zero value uses
+// DefaultCompression.
+type TDigest struct {
+	sync.Mutex
+
+	compression float64
+	centroids   []centroid
+	buffer      []centroid
+	count       float64
+	min         float64
+	max         float64
+}
+
+//////
+// Methods.
+//////
+
+// scale is the k1 scale function, mapping a quantile to a centroid index.
+func (t *TDigest) scale(q float64) float64 {
+	return t.compression / (2 * math.Pi) * math.Asin(2*q-1)
+}
+
+// add buffers a sample. Caller must hold the lock.
+func (t *TDigest) add(mean, weight float64) {
+	if t.compression <= 0 {
+		t.compression = DefaultCompression
+	}
+
+	if t.count == 0 || mean < t.min {
+		t.min = mean
+	}
+
+	if t.count == 0 || mean > t.max {
+		t.max = mean
+	}
+
+	t.count += weight
+
+	t.buffer = append(t.buffer, centroid{mean: mean, weight: weight})
+
+	if len(t.buffer) >= 5*int(t.compression) {
+		t.flush()
+	}
+}
+
+// flush merges the buffered samples into the centroids. Caller must hold the
+// lock.
+func (t *TDigest) flush() {
+	if len(t.buffer) == 0 {
+		return
+	}
+
+	all := append(t.centroids, t.buffer...)
+
+	t.buffer = t.buffer[:0]
+
+	sort.Slice(all, func(i, j int) bool { return all[i].mean < all[j].mean })
+
+	merged := make([]centroid, 0, len(t.centroids)+1)
+
+	current := all[0]
+	before := 0.0
+	limit := t.scale(0) + 1
+
+	for _, next := range all[1:] {
+		if t.scale((before+current.weight+next.weight)/t.count) <= limit {
+			current.weight += next.weight
+			current.mean += (next.mean - current.mean) * next.weight / current.weight
+
+			continue
+		}
+
+		merged = append(merged, current)
+
+		before += current.weight
+		limit = t.scale(before/t.count) + 1
+		current = next
+	}
+
+	t.centroids = append(merged, current)
+}
+
+// Add adds a sample. NaN is ignored.
+func (t *TDigest) Add(x float64) *TDigest {
+	t.Lock()
+	defer t.Unlock()
+
+	if !math.IsNaN(x) {
+		t.add(x, 1)
+	}
+
+	return t
+}
+
+// Count returns the number of samples.
+func (t *TDigest) Count() int {
+	t.Lock()
+	defer t.Unlock()
+
+	return int(t.count)
+}
+
+// Min returns the smallest sample, NaN if there are none.
+func (t *TDigest) Min() float64 {
+	t.Lock()
+	defer t.Unlock()
+
+	if t.count == 0 {
+		return math.NaN()
+	}
+
+	return t.min
+}
+
+// Max returns the largest sample, NaN if there are none.
+func (t *TDigest) Max() float64 {
+	t.Lock()
+	defer t.Unlock()
+
+	if t.count == 0 {
+		return math.NaN()
+	}
+
+	return t.max
+}
+
+// Quantile returns the estimated value at the quantile q (between 0 and 1,
+// e.g.: 0.99 for p99), interpolating between centroids. It returns NaN if there
+// are no samples.
+func (t *TDigest) Quantile(q float64) float64 {
+	t.Lock()
+	defer t.Unlock()
+
+	t.flush()
+
+	if t.count == 0 || math.IsNaN(q) {
+		return math.NaN()
+	}
+
+	switch {
+	case q <= 0:
+		return t.min
+	case q >= 1:
+		return t.max
+	case len(t.centroids) == 1:
+		return t.centroids[0].mean
+	}
+
+	target := q * t.count
+
+	// Between the smallest sample, and the center of the first centroid.
+	first := t.centroids[0]
+	if target < first.weight/2 {
+		return t.min + (first.mean-t.min)*target/(first.weight/2)
+	}
+
+	// Between the centers of consecutive centroids.
+	center := first.weight / 2
+
+	for i := 1; i < len(t.centroids); i++ {
+		prev, next := t.centroids[i-1], t.centroids[i]
+
+		nextCenter := center + (prev.weight+next.weight)/2
+
+		if target < nextCenter {
+			return prev.mean + (next.mean-prev.mean)*(target-center)/(nextCenter-center)
+		}
+
+		center = nextCenter
+	}
+
+	// Between the center of the last centroid, and the largest sample.
+	last := t.centroids[len(t.centroids)-1]
+
+	return last.mean + (t.max-last.mean)*(target-center)/(t.count-center)
+}
+
+// Merge adds the samples summarized by the other digest. Merging a digest with
+// itself is a no-op.
+func (t *TDigest) Merge(other *TDigest) *TDigest {
+	if other == t {
+		return t
+	}
+
+	other.Lock()
+
+	other.flush()
+
+	centroids := append([]centroid{}, other.centroids...)
+	minimum, maximum := other.min, other.max
+
+	other.Unlock()
+
+	t.Lock()
+	defer t.Unlock()
+
+	if len(centroids) == 0 {
+		return t
+	}
+
+	for _, c := range centroids {
+		t.add(c.mean, c.weight)
+	}
+
+	// Centroids means are within the samples range, not the extremes.
+	t.min = math.Min(t.min, minimum)
+	t.max = math.Max(t.max, maximum)
+
+	return t
+}
+
+// Reset removes all the samples.
+func (t *TDigest) Reset() *TDigest {
+	t.Lock()
+	defer t.Unlock()
+
+	t.centroids = nil
+	t.buffer = nil
+	t.count = 0
+
+	return t
+}
+
+// MarshalBinary implements the encoding.BinaryMarshaler interface. The format
+// is a version byte, followed by the compression, min, max, and the mean, and
+// weight of every centroid, as big-endian float64s.
+func (t *TDigest) MarshalBinary() ([]byte, error) {
+	t.Lock()
+	defer t.Unlock()
+
+	t.flush()
+
+	compression := t.compression
+	if compression <= 0 {
+		compression = DefaultCompression
+	}
+
+	floats := []float64{compression, t.min, t.max}
+
+	for _, c := range t.centroids {
+		floats = append(floats, c.mean, c.weight)
+	}
+
+	data := make([]byte, 1, 1+8*len(floats))
+	data[0] = tdigestVersion
+
+	for _, f := range floats {
+		data = binary.BigEndian.AppendUint64(data, math.Float64bits(f))
+	}
+
+	return data, nil
+}
+
+// UnmarshalBinary implements the encoding.BinaryUnmarshaler interface, the
+// inverse of MarshalBinary. It replaces the content of the digest.
+func (t *TDigest) UnmarshalBinary(data []byte) error {
+	if len(data) < 1+3*8 || data[0] != tdigestVersion || (len(data)-1)%16 != 8 {
+		return ErrInvalidTDigest
+	}
+
+	floats := make([]float64, 0, (len(data)-1)/8)
+
+	for i := 1; i < len(data); i += 8 {
+		floats = append(floats, math.Float64frombits(binary.BigEndian.Uint64(data[i:])))
+	}
+
+	if !(floats[0] > 0) {
+		return ErrInvalidTDigest
+	}
+
+	centroids := make([]centroid, 0, (len(floats)-3)/2)
+	count := 0.0
+
+	for i := 3; i < len(floats); i += 2 {
+		c := centroid{mean: floats[i], weight: floats[i+1]}
+
+		if !(c.weight > 0) || math.IsNaN(c.mean) {
+			return ErrInvalidTDigest
+		}
+
+		centroids = append(centroids, c)
+		count += c.weight
+	}
+
+	t.Lock()
+	defer t.Unlock()
+
+	t.compression = floats[0]
+	t.min = floats[1]
+	t.max = floats[2]
+	t.centroids = centroids
+	t.buffer = nil
+	t.count = count
+
+	return nil
+}
+
+//////
+// Factory.
+//////
+
+// NewTDigest creates a new TDigest. A higher compression is more accurate,
+// using more memory. A compression <= 0 means DefaultCompression.
+func NewTDigest(compression float64) *TDigest {
+	if !(compression > 0) {
+		compression = DefaultCompression
+	}
+
+	return &TDigest{
+		compression: compression,
+	}
+}
@@ -0,0 +1,142 @@
+package statistical
+
+import (
+	"math"
+	"math/rand"
+	"sync"
+	"testing"
+)
+
+func TestTDigestQuantile(t *testing.T) {
+	td := NewTDigest(100)
+
+	const n = 100000
+
+	for _, i := range rand.Perm(n) {
+		td.Add(float64(i))
+	}
+
+	if td.Count() != n {
+		t.Errorf("Expected count %d, got %d", n, td.Count())
+	}
+
+	if td.Min() != 0 || td.Max() != n-1 {
+		t.Errorf("Expected min 0, and max %d, got %v, and %v", n-1, td.Min(), td.Max())
+	}
+
+	for _, q := range []float64{0.01, 0.1, 0.5, 0.9, 0.99, 0.999} {
+		expected := q * n
+		actual := td.Quantile(q)
+
+		if !approxEqual(t, actual, expected, n*0.01) {
+			t.Errorf("Expected quantile %v to be about %v, got %v", q, expected, actual)
+		}
+	}
+
+	if td.Quantile(0) != 0 || td.Quantile(1) != n-1 {
+		t.Errorf("Expected extremes to be the min, and max")
+	}
+}
+
+func TestTDigestEmpty(t *testing.T) {
+	var td TDigest
+
+	if !math.IsNaN(td.Quantile(0.5)) || !math.IsNaN(td.Min()) || !math.IsNaN(td.Max()) {
+		t.Errorf("Expected NaN for an empty digest")
+	}
+
+	td.Add(42)
+
+	if td.Quantile(0.5) != 42 {
+		t.Errorf("Expected 42, got %v", td.Quantile(0.5))
+	}
+
+	td.Reset()
+
+	if td.Count() != 0 {
+		t.Errorf("Expected an empty digest after reset")
+	}
+}
+
+func TestTDigestMerge(t *testing.T) {
+	a, b := NewTDigest(0), NewTDigest(0)
+
+	for i := 0; i < 50000; i++ {
+		a.Add(float64(i))
+		b.Add(float64(i + 50000))
+	}
+
+	a.Merge(b).Merge(a)
+
+	if a.Count() != 100000 {
+		t.Errorf("Expected count 100000, got %d", a.Count())
+	}
+
+	if a.Min() != 0 || a.Max() != 99999 {
+		t.Errorf("Expected min 0, and max 99999, got %v, and %v", a.Min(), a.Max())
+	}
+
+	if median := a.Quantile(0.5); !approxEqual(t, median, 50000, 1000) {
+		t.Errorf("Expected median to be about 50000, got %v", median)
+	}
+}
+
+func TestTDigestBinary(t *testing.T) {
+	td := NewTDigest(50)
+
+	for i := 0; i < 10000; i++ {
+		td.Add(rand.NormFloat64())
+	}
+
+	data, err := td.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	decoded := NewTDigest(0)
+
+	if err := decoded.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, q := range []float64{0.01, 0.5, 0.99} {
+		if td.Quantile(q) != decoded.Quantile(q) {
+			t.Errorf("Expected quantile %v to be %v, got %v", q, td.Quantile(q), decoded.Quantile(q))
+		}
+	}
+
+	if decoded.Count() != 10000 {
+		t.Errorf("Expected count 10000, got %d", decoded.Count())
+	}
+
+	for _, invalid := range [][]byte{nil, {2}, data[:len(data)-1]} {
+		if err := decoded.UnmarshalBinary(invalid); err != ErrInvalidTDigest {
+			t.Errorf("Expected ErrInvalidTDigest, got %v", err)
+		}
+	}
+}
+
+func TestTDigestConcurrent(t *testing.T) {
+	td := NewTDigest(0)
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for j := 0; j < 1000; j++ {
+				td.Add(float64(j))
+				td.Quantile(0.5)
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	if td.Count() != 8000 {
+		t.Errorf("Expected count 8000, got %d", td.Count())
+	}
+}
@@ -0,0 +1,302 @@
+// Package stream provides Stream, a lazy sequence of values, e.g.: to chain
+// Filter, Map, and Take over a collection, without building an intermediate
+// collection at every step. Values are pulled one at a time, by the terminal
+// operation, e.g.: Collect, through all the steps, so Take stops the pipeline
+// once it has enough values.
+package stream
+
+import "github.com/thalesfsp/go-common-types/collection"
+
+//////
+// Const, vars, and types.
+//////
+
+// Stream is a lazy sequence of values. Intermediate operations, e.g.: Filter,
+// Map, and Take, return a new Stream, without evaluating anything. Terminal
+// operations, e.g.: Collect, Each, and Reduce, evaluate it.
+//
+// A Stream is consumed once, by its terminal operation, or by the Stream
+// derived from it. It isn't safe for concurrent use.
+type Stream[T any] struct {
+	next func() (T, bool)
+}
+
+//////
+// Methods.
+//////
+
+// Next returns the next value, false when the stream is exhausted.
+func (s *Stream[T]) Next() (T, bool) {
+	return s.next()
+}
+
+// Filter returns a stream of the values satisfying the predicate.
+func (s *Stream[T]) Filter(predicate func(value T) bool) *Stream[T] {
+	return FromFunc(func() (T, bool) {
+		for {
+			value, ok := s.next()
+			if !ok || predicate(value) {
+				return value, ok
+			}
+		}
+	})
+}
+
+// Map returns a stream of the results of f, see the Map function to map to
+// another type.
+func (s *Stream[T]) Map(f func(value T) T) *Stream[T] {
+	return Map(s, f)
+}
+
+// Peek returns the same stream, calling f with every value as it's pulled,
+// e.g.: for logging.
+func (s *Stream[T]) Peek(f func(value T)) *Stream[T] {
+	return FromFunc(func() (T, bool) {
+		value, ok := s.next()
+		if ok {
+			f(value)
+		}
+
+		return value, ok
+	})
+}
+
+// Take returns a stream of the first n values. Values after them aren't
+// pulled, so it can limit an infinite stream, see Iterate.
+func (s *Stream[T]) Take(n int) *Stream[T] {
+	return FromFunc(func() (T, bool) {
+		if n <= 0 {
+			return *new(T), false
+		}
+
+		n--
+
+		return s.next()
+	})
+}
+
+// Skip returns a stream without the first n values.
+func (s *Stream[T]) Skip(n int) *Stream[T] {
+	return FromFunc(func() (T, bool) {
+		for ; n > 0; n-- {
+			if _, ok := s.next(); !ok {
+				return *new(T), false
+			}
+		}
+
+		return s.next()
+	})
+}
+
+// TakeWhile returns a stream of the values until the first one not
+// satisfying the predicate.
+func (s *Stream[T]) TakeWhile(predicate func(value T) bool) *Stream[T] {
+	done := false
+
+	return FromFunc(func() (T, bool) {
+		if done {
+			return *new(T), false
+		}
+
+		value, ok := s.next()
+		if !ok || !predicate(value) {
+			done = true
+
+			return *new(T), false
+		}
+
+		return value, true
+	})
+}
+
+// DropWhile returns a stream of the values from the first one not satisfying
+// the predicate.
+func (s *Stream[T]) DropWhile(predicate func(value T) bool) *Stream[T] {
+	dropping := true
+
+	return FromFunc(func() (T, bool) {
+		for {
+			value, ok := s.next()
+			if !ok || !dropping || !predicate(value) {
+				dropping = false
+
+				return value, ok
+			}
+		}
+	})
+}
+
+// Collect evaluates the stream, returning its values.
+func (s *Stream[T]) Collect() []T {
+	values := []T{}
+
+	for value, ok := s.next(); ok; value, ok = s.next() {
+		values = append(values, value)
+	}
+
+	return values
+}
+
+// Each evaluates the stream, calling f with every value.
+func (s *Stream[T]) Each(f func(value T)) {
+	for value, ok := s.next(); ok; value, ok = s.next() {
+		f(value)
+	}
+}
+
+// Reduce evaluates the stream, accumulating its values, see the Reduce
+// function to accumulate into another type.
+func (s *Stream[T]) Reduce(reducer func(acc, value T) T, initial T) T {
+	return Reduce(s, reducer, initial)
+}
+
+// Count evaluates the stream, returning the number of values.
+func (s *Stream[T]) Count() int {
+	n := 0
+
+	for _, ok := s.next(); ok; _, ok = s.next() {
+		n++
+	}
+
+	return n
+}
+
+// First returns the first value, false if the stream is empty. Values after
+// it aren't pulled.
+func (s *Stream[T]) First() (T, bool) {
+	return s.next()
+}
+
+// Find returns the first value satisfying the predicate, false if none does.
+// Values after it aren't pulled.
+func (s *Stream[T]) Find(predicate func(value T) bool) (T, bool) {
+	return s.Filter(predicate).First()
+}
+
+// Any checks if any value satisfies the predicate, stopping at the first one.
+func (s *Stream[T]) Any(predicate func(value T) bool) bool {
+	_, ok := s.Find(predicate)
+
+	return ok
+}
+
+// All checks if all values satisfy the predicate, stopping at the first one
+// which doesn't.
+func (s *Stream[T]) All(predicate func(value T) bool) bool {
+	return !s.Any(func(value T) bool {
+		return !predicate(value)
+	})
+}
+
+//////
+// Exported functionalities.
+//////
+
+// Map returns a stream of the results of f, e.g.: to map to another type.
+func Map[T, R any](s *Stream[T], f func(value T) R) *Stream[R] {
+	return FromFunc(func() (R, bool) {
+		value, ok := s.next()
+		if !ok {
+			return *new(R), false
+		}
+
+		return f(value), true
+	})
+}
+
+// FlatMap returns a stream of the values of the slices returned by f.
+func FlatMap[T, R any](s *Stream[T], f func(value T) []R) *Stream[R] {
+	var pending []R
+
+	return FromFunc(func() (R, bool) {
+		for len(pending) == 0 {
+			value, ok := s.next()
+			if !ok {
+				return *new(R), false
+			}
+
+			pending = f(value)
+		}
+
+		value := pending[0]
+		pending = pending[1:]
+
+		return value, true
+	})
+}
+
+// Distinct returns a stream without the repeated values, keeping the first
+// occurrence.
+func Distinct[T comparable](s *Stream[T]) *Stream[T] {
+	seen := map[T]struct{}{}
+
+	return s.Filter(func(value T) bool {
+		if _, ok := seen[value]; ok {
+			return false
+		}
+
+		seen[value] = struct{}{}
+
+		return true
+	})
+}
+
+// Reduce evaluates the stream, accumulating its values, e.g.: to sum them
+// into another type.
+func Reduce[T, A any](s *Stream[T], reducer func(acc A, value T) A, initial A) A {
+	acc := initial
+
+	for value, ok := s.next(); ok; value, ok = s.next() {
+		acc = reducer(acc, value)
+	}
+
+	return acc
+}
+
+//////
+// Factory.
+//////
+
+// From returns a stream of the values of the collection, in its order, e.g.:
+// a SafeSlice, a SafeSet, or a SafeOrderedMap. The values are read once, when
+// the stream is created, so later changes to the collection aren't seen.
+func From[T any](c collection.Collection[T]) *Stream[T] {
+	return Of(c.Values()...)
+}
+
+// Of returns a stream of the values.
+func Of[T any](values ...T) *Stream[T] {
+	i := 0
+
+	return FromFunc(func() (T, bool) {
+		if i >= len(values) {
+			return *new(T), false
+		}
+
+		i++
+
+		return values[i-1], true
+	})
+}
+
+// FromFunc returns a stream of the values returned by next, until it returns
+// false, e.g.: to read from a channel, or a paginated API.
+func FromFunc[T any](next func() (T, bool)) *Stream[T] {
+	return &Stream[T]{next: next}
+}
+
+// Iterate returns an infinite stream of seed, f(seed), f(f(seed)), and so on,
+// to be limited, e.g.: by Take, or TakeWhile.
+func Iterate[T any](seed T, f func(value T) T) *Stream[T] {
+	value, started := seed, false
+
+	return FromFunc(func() (T, bool) {
+		if started {
+			value = f(value)
+		}
+
+		started = true
+
+		return value, true
+	})
+}
@@ -0,0 +1,119 @@
+package stream
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/thalesfsp/go-common-types/safeorderedmap"
+	"github.com/thalesfsp/go-common-types/safeset"
+	"github.com/thalesfsp/go-common-types/safeslice"
+)
+
+func isEven(v int) bool { return v%2 == 0 }
+
+func double(v int) int { return v * 2 }
+
+func TestFrom(t *testing.T) {
+	s := safeslice.New(1, 2, 3, 4, 5, 6)
+
+	assert.Equal(t, []int{4, 8}, From[int](s).Filter(isEven).Map(double).Take(2).Collect())
+
+	// Changes after creating the stream aren't seen.
+	stream := From[int](safeset.New(1, 2))
+	assert.Equal(t, []int{1, 2}, stream.Collect())
+
+	m := safeorderedmap.New[int]().Add("a", 1).Add("b", 2)
+	assert.Equal(t, 3, From[int](m).Reduce(func(acc, v int) int { return acc + v }, 0))
+}
+
+func TestLazy(t *testing.T) {
+	pulled := 0
+
+	values := Iterate(1, func(v int) int { return v + 1 }).
+		Peek(func(int) { pulled++ }).
+		Filter(isEven).
+		Take(3)
+
+	assert.Equal(t, 0, pulled)
+	assert.Equal(t, []int{2, 4, 6}, values.Collect())
+	assert.Equal(t, 6, pulled)
+
+	// The stream is consumed.
+	assert.Empty(t, values.Collect())
+
+	pulled = 0
+
+	found, ok := Of(1, 2, 3, 4).Peek(func(int) { pulled++ }).Find(isEven)
+	assert.True(t, ok)
+	assert.Equal(t, 2, found)
+	assert.Equal(t, 2, pulled)
+}
+
+func TestOperations(t *testing.T) {
+	assert.Equal(t, []int{3, 4}, Of(1, 2, 3, 4).Skip(2).Collect())
+	assert.Empty(t, Of(1, 2).Skip(5).Collect())
+	assert.Equal(t, []int{1, 2}, Of(1, 2, 5, 1).TakeWhile(func(v int) bool { return v < 3 }).Collect())
+	assert.Equal(t, []int{5, 1}, Of(1, 2, 5, 1).DropWhile(func(v int) bool { return v < 3 }).Collect())
+	assert.Equal(t, []int{1, 2, 3}, Distinct(Of(1, 2, 1, 3, 2)).Collect())
+	assert.Equal(t, []string{"1", "2"}, Map(Of(1, 2), strconv.Itoa).Collect())
+	assert.Equal(t, []int{1, 1, 3, 3}, FlatMap(Of(1, 2, 3), func(v int) []int {
+		if v == 2 {
+			return nil
+		}
+
+		return []int{v, v}
+	}).Collect())
+	assert.Equal(t, "123", Reduce(Of(1, 2, 3), func(acc string, v int) string { return acc + strconv.Itoa(v) }, ""))
+	assert.Equal(t, 3, Of(1, 2, 3).Count())
+	assert.True(t, Of(1, 2).Any(isEven))
+	assert.False(t, Of(1, 2).All(isEven))
+	assert.True(t, Of[int]().All(isEven))
+
+	_, ok := Of[int]().First()
+	assert.False(t, ok)
+
+	var each []int
+
+	Of(1, 2).Each(func(v int) { each = append(each, v) })
+	assert.Equal(t, []int{1, 2}, each)
+
+	ch := make(chan int, 2)
+	ch <- 1
+	ch <- 2
+	close(ch)
+
+	assert.Equal(t, []int{1, 2}, FromFunc(func() (int, bool) {
+		v, ok := <-ch
+
+		return v, ok
+	}).Collect())
+}
+
+// BenchmarkPipeline compares a Filter, Map, Filter chain of SafeSlice, which
+// builds a slice at every step, to a stream.
+func BenchmarkPipeline(b *testing.B) {
+	values := make([]int, 10000)
+
+	for i := range values {
+		values[i] = i
+	}
+
+	s := safeslice.New(values...)
+
+	b.Run("safeslice", func(b *testing.B) {
+		b.ReportAllocs()
+
+		for i := 0; i < b.N; i++ {
+			s.Filter(isEven).Map(double).Filter(func(v int) bool { return v%3 == 0 }).Values()
+		}
+	})
+
+	b.Run("stream", func(b *testing.B) {
+		b.ReportAllocs()
+
+		for i := 0; i < b.N; i++ {
+			From[int](s).Filter(isEven).Map(double).Filter(func(v int) bool { return v%3 == 0 }).Collect()
+		}
+	})
+}
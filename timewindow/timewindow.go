@@ -0,0 +1,316 @@
+package timewindow
+
+import (
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/thalesfsp/go-common-types/metrics"
+	"github.com/thalesfsp/go-common-types/safeslice"
+	"github.com/thalesfsp/go-common-types/statistical"
+)
+
+//////
+// Const, vars, and types.
+//////
+
+// Option allows to configure a TimeWindow.
+type Option[T comparable] func(w *TimeWindow[T])
+
+// Point is a value observed at a given time.
+type Point[T comparable] struct {
+	Time  time.Time `json:"time"`
+	Value T         `json:"value"`
+}
+
+// TimeWindow is a time series, safe for concurrent use, built on a SafeSlice
+// of points kept ordered by time, e.g.: to compute sliding-window rates, and
+// means. With WithMaxAge, points older than the window are pruned on every
+// add, otherwise PruneOlderThan must be called.
+//
+// The zero value is an empty, unbounded, window, using time.Now, ready to
+// use.
+type TimeWindow[T comparable] struct {
+	// data is created on first use, by store, for the zero value.
+	data *safeslice.SafeSlice[Point[T]]
+	once sync.Once
+
+	maxAge  time.Duration
+	maxSize int
+
+	metrics *metrics.Metrics
+
+	now func() time.Time
+}
+
+//////
+// Methods.
+//////
+
+// store returns the points, creating them on first use, for the zero value.
+func (w *TimeWindow[T]) store() *safeslice.SafeSlice[Point[T]] {
+	w.once.Do(func() {
+		if w.data == nil {
+			w.data = safeslice.New[Point[T]]()
+			w.now = time.Now
+		}
+	})
+
+	return w.data
+}
+
+// clock returns the current time.
+func (w *TimeWindow[T]) clock() time.Time {
+	w.store()
+
+	return w.now()
+}
+
+// cutoff returns the time before which points are older than d.
+func (w *TimeWindow[T]) cutoff(d time.Duration) time.Time {
+	return w.clock().Add(-d)
+}
+
+// prune removes the points older than the maximum age, if any. Points are
+// ordered, so the oldest one tells if there's anything to remove, without
+// taking the write lock.
+func (w *TimeWindow[T]) prune() {
+	if w.maxAge <= 0 {
+		return
+	}
+
+	if oldest, ok := w.store().First(); ok && oldest.Time.Before(w.cutoff(w.maxAge)) {
+		w.PruneOlderThan(w.maxAge)
+	}
+}
+
+// Add a value observed at the given time. Points are kept ordered by time, so
+// values can be added out of order, e.g.: late events.
+func (w *TimeWindow[T]) Add(at time.Time, value T) *TimeWindow[T] {
+	w.store().SortedInsert(Point[T]{Time: at, Value: value}, compareTime[T])
+
+	w.prune()
+
+	return w
+}
+
+// AddNow adds a value observed now.
+func (w *TimeWindow[T]) AddNow(value T) *TimeWindow[T] {
+	return w.Add(w.clock(), value)
+}
+
+// Since returns the points observed in the last d, in time order.
+func (w *TimeWindow[T]) Since(d time.Duration) []Point[T] {
+	cutoff := w.cutoff(d)
+
+	return w.store().DropWhile(func(p Point[T]) bool {
+		return p.Time.Before(cutoff)
+	}).Values()
+}
+
+// ValuesSince returns the values observed in the last d, in time order.
+func (w *TimeWindow[T]) ValuesSince(d time.Duration) []T {
+	return values(w.Since(d))
+}
+
+// Between returns the points observed in [from, to), in time order.
+func (w *TimeWindow[T]) Between(from, to time.Time) []Point[T] {
+	return w.store().DropWhile(func(p Point[T]) bool {
+		return p.Time.Before(from)
+	}).TakeWhile(func(p Point[T]) bool {
+		return p.Time.Before(to)
+	}).Values()
+}
+
+// PruneOlderThan removes the points observed before the last d, returning how
+// many were removed.
+func (w *TimeWindow[T]) PruneOlderThan(d time.Duration) int {
+	cutoff := w.cutoff(d)
+
+	n := w.store().RemoveFunc(func(p Point[T]) bool {
+		return p.Time.Before(cutoff)
+	})
+
+	w.metrics.Operation("prune")
+
+	return n
+}
+
+// Rate returns the number of points observed in the last d, per second.
+func (w *TimeWindow[T]) Rate(d time.Duration) float64 {
+	if d <= 0 {
+		return 0
+	}
+
+	return float64(len(w.Since(d))) / d.Seconds()
+}
+
+// Points returns all points, in time order.
+func (w *TimeWindow[T]) Points() []Point[T] {
+	return w.store().Values()
+}
+
+// Values returns all values, in time order.
+func (w *TimeWindow[T]) Values() []T {
+	return values(w.store().Values())
+}
+
+// Size returns the number of points.
+func (w *TimeWindow[T]) Size() int {
+	return w.store().Size()
+}
+
+// Empty checks if there are no points.
+func (w *TimeWindow[T]) Empty() bool {
+	return w.store().Empty()
+}
+
+// Clear removes all points.
+func (w *TimeWindow[T]) Clear() *TimeWindow[T] {
+	w.store().RemoveFunc(func(Point[T]) bool { return true })
+
+	return w
+}
+
+// Metrics returns the metrics of the window, nil if not enabled.
+func (w *TimeWindow[T]) Metrics() *metrics.Metrics {
+	return w.metrics
+}
+
+// MarshalJSON implements json.Marshaler, encoding the points as an array of
+// {"time", "value"} objects, in time order.
+func (w *TimeWindow[T]) MarshalJSON() ([]byte, error) {
+	return w.store().MarshalJSON()
+}
+
+// UnmarshalJSON implements json.Unmarshaler, replacing the points. Points
+// don't need to be ordered.
+func (w *TimeWindow[T]) UnmarshalJSON(data []byte) error {
+	var points []Point[T]
+
+	if err := json.Unmarshal(data, &points); err != nil {
+		return err
+	}
+
+	w.Clear()
+
+	for _, p := range points {
+		w.store().SortedInsert(p, compareTime[T])
+	}
+
+	w.prune()
+
+	return nil
+}
+
+//////
+// Statistics.
+//////
+
+// Sum returns the sum of the values observed in the last d.
+func Sum[T statistical.Numbers](w *TimeWindow[T], d time.Duration) T {
+	var sum T
+
+	for _, p := range w.Since(d) {
+		sum += p.Value
+	}
+
+	return sum
+}
+
+// SumRate returns the sum of the values observed in the last d, per second,
+// e.g.: bytes per second.
+func SumRate[T statistical.Numbers](w *TimeWindow[T], d time.Duration) float64 {
+	if d <= 0 {
+		return 0
+	}
+
+	return float64(Sum(w, d)) / d.Seconds()
+}
+
+// Mean returns the mean of the values observed in the last d. It fails if
+// there are none.
+func Mean[T statistical.Numbers](w *TimeWindow[T], d time.Duration) (float64, error) {
+	return statistical.Mean(w.ValuesSince(d))
+}
+
+// StandardDeviation returns the sample standard deviation of the values
+// observed in the last d. It fails if there are less than two.
+func StandardDeviation[T statistical.Numbers](w *TimeWindow[T], d time.Duration) (float64, error) {
+	return statistical.StandardDeviation(w.ValuesSince(d))
+}
+
+// Percentile returns the percentile, between 0 and 1, of the values observed
+// in the last d, see statistical.Percentile. It fails if there are none.
+func Percentile[T statistical.Numbers](w *TimeWindow[T], d time.Duration, p float64) (T, error) {
+	return statistical.Percentile(w.ValuesSince(d), p)
+}
+
+//////
+// Helpers.
+//////
+
+// compareTime compares points by time.
+func compareTime[T comparable](a, b Point[T]) int {
+	return a.Time.Compare(b.Time)
+}
+
+// values returns the values of the points.
+func values[T comparable](points []Point[T]) []T {
+	vs := make([]T, 0, len(points))
+
+	for _, p := range points {
+		vs = append(vs, p.Value)
+	}
+
+	return vs
+}
+
+//////
+// Factory.
+//////
+
+// WithMaxAge prunes the points older than d on every add.
+func WithMaxAge[T comparable](d time.Duration) Option[T] {
+	return func(w *TimeWindow[T]) {
+		w.maxAge = d
+	}
+}
+
+// WithMaxSize keeps at most n points, evicting the oldest ones.
+func WithMaxSize[T comparable](n int) Option[T] {
+	return func(w *TimeWindow[T]) {
+		w.maxSize = n
+	}
+}
+
+// WithClock sets the function returning the current time, time.Now by
+// default, e.g.: for tests.
+func WithClock[T comparable](now func() time.Time) Option[T] {
+	return func(w *TimeWindow[T]) {
+		w.now = now
+	}
+}
+
+// WithMetrics enables metrics instrumentation of the underlying slice, plus
+// the prune operation.
+func WithMetrics[T comparable](mtrcs *metrics.Metrics) Option[T] {
+	return func(w *TimeWindow[T]) {
+		w.metrics = mtrcs
+	}
+}
+
+// New creates a new, empty, TimeWindow.
+func New[T comparable](opts ...Option[T]) *TimeWindow[T] {
+	w := &TimeWindow[T]{
+		now: time.Now,
+	}
+
+	for _, opt := range opts {
+		opt(w)
+	}
+
+	w.data = safeslice.NewBounded(w.maxSize, safeslice.DropOldest, safeslice.WithMetrics[Point[T]](w.metrics))
+
+	return w
+}
@@ -0,0 +1,128 @@
+package timewindow
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/thalesfsp/go-common-types/metrics"
+)
+
+// clock is a manually advanced clock.
+type clock struct {
+	now time.Time
+}
+
+func (c *clock) Now() time.Time { return c.now }
+
+func (c *clock) Advance(d time.Duration) { c.now = c.now.Add(d) }
+
+func newTestWindow(opts ...Option[int]) (*TimeWindow[int], *clock) {
+	c := &clock{now: time.Unix(1_000, 0)}
+
+	return New(append([]Option[int]{WithClock[int](c.Now)}, opts...)...), c
+}
+
+func TestTimeWindow(t *testing.T) {
+	w, c := newTestWindow()
+
+	for i := 1; i <= 5; i++ {
+		w.AddNow(i)
+		c.Advance(time.Second)
+	}
+
+	// Late values are kept in time order.
+	w.Add(c.now.Add(-10*time.Second), 0)
+
+	assert.Equal(t, []int{0, 1, 2, 3, 4, 5}, w.Values())
+	assert.Equal(t, []int{3, 4, 5}, w.ValuesSince(3*time.Second))
+	assert.Equal(t, 1.0, w.Rate(2*time.Second))
+
+	points := w.Between(time.Unix(1_001, 0), time.Unix(1_003, 0))
+	assert.Len(t, points, 2)
+	assert.Equal(t, 2, points[0].Value)
+
+	assert.Equal(t, 3, w.PruneOlderThan(3*time.Second))
+	assert.Equal(t, 3, w.Size())
+
+	w.Clear()
+
+	assert.True(t, w.Empty())
+}
+
+func TestTimeWindowMaxAge(t *testing.T) {
+	m := metrics.New("window")
+
+	w, c := newTestWindow(WithMaxAge[int](time.Minute), WithMaxSize[int](3), WithMetrics[int](m))
+
+	w.AddNow(1)
+	c.Advance(2 * time.Minute)
+	w.AddNow(2)
+
+	// The first value is older than the window.
+	assert.Equal(t, []int{2}, w.Values())
+
+	w.AddNow(3).AddNow(4).AddNow(5)
+
+	// The oldest value is evicted.
+	assert.Equal(t, []int{3, 4, 5}, w.Values())
+	assert.Equal(t, uint64(1), m.Snapshot().Operations["prune"])
+}
+
+func TestTimeWindowStatistics(t *testing.T) {
+	w, c := newTestWindow()
+
+	for _, v := range []int{10, 20, 30, 40} {
+		w.AddNow(v)
+		c.Advance(time.Second)
+	}
+
+	assert.Equal(t, 70, Sum(w, 2*time.Second))
+	assert.Equal(t, 35.0, SumRate(w, 2*time.Second))
+
+	mean, err := Mean(w, time.Minute)
+	assert.NoError(t, err)
+	assert.Equal(t, 25.0, mean)
+
+	p, err := Percentile(w, time.Minute, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, 40, p)
+
+	_, err = StandardDeviation(w, time.Minute)
+	assert.NoError(t, err)
+
+	_, err = Mean(w, 0)
+	assert.Error(t, err)
+}
+
+func TestTimeWindowJSON(t *testing.T) {
+	w, _ := newTestWindow()
+
+	w.Add(time.Unix(2, 0).UTC(), 2)
+	w.Add(time.Unix(1, 0).UTC(), 1)
+
+	b, err := json.Marshal(w)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `[
+		{"time": "1970-01-01T00:00:01Z", "value": 1},
+		{"time": "1970-01-01T00:00:02Z", "value": 2}
+	]`, string(b))
+
+	other, _ := newTestWindow()
+
+	assert.NoError(t, json.Unmarshal([]byte(`[
+		{"time": "1970-01-01T00:00:02Z", "value": 2},
+		{"time": "1970-01-01T00:00:01Z", "value": 1}
+	]`), other))
+	assert.Equal(t, []int{1, 2}, other.Values())
+}
+
+func TestTimeWindowZeroValue(t *testing.T) {
+	var w TimeWindow[int]
+
+	w.AddNow(1)
+
+	assert.Equal(t, []int{1}, w.Values())
+	assert.NoError(t, w.UnmarshalJSON([]byte(`[]`)))
+}
@@ -0,0 +1,202 @@
+// Package tuple provides generic tuple types.
+package tuple
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	"golang.org/x/exp/constraints"
+)
+
+//////
+// Const, vars, and types.
+//////
+
+// Pair is a tuple of two values. If both values are comparable, so is the
+// pair, so it can be used as a map key, or an element of a SafeSlice.
+type Pair[A, B any] struct {
+	First  A
+	Second B
+}
+
+// Triple is a tuple of three values. If all values are comparable, so is the
+// triple.
+type Triple[A, B, C any] struct {
+	First  A
+	Second B
+	Third  C
+}
+
+//////
+// Methods.
+//////
+
+// String is the stringer implementation.
+func (p Pair[A, B]) String() string {
+	return fmt.Sprintf("(%v, %v)", p.First, p.Second)
+}
+
+// Values returns both values.
+func (p Pair[A, B]) Values() (A, B) {
+	return p.First, p.Second
+}
+
+// Swap returns a new pair with the values swapped.
+func (p Pair[A, B]) Swap() Pair[B, A] {
+	return Pair[B, A]{First: p.Second, Second: p.First}
+}
+
+// Equal checks if both pairs hold deeply equal values, like reflect.DeepEqual.
+// For comparable values, == is equivalent, and faster.
+func (p Pair[A, B]) Equal(other Pair[A, B]) bool {
+	return reflect.DeepEqual(p, other)
+}
+
+// MarshalJSON implements the json.Marshaler interface. The pair is encoded as
+// an array of two elements, e.g.: ["a", 1].
+func (p Pair[A, B]) MarshalJSON() ([]byte, error) {
+	return json.Marshal([]any{p.First, p.Second})
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface, the inverse of
+// MarshalJSON.
+func (p *Pair[A, B]) UnmarshalJSON(data []byte) error {
+	var raw []json.RawMessage
+
+	if err := unmarshalArray(data, 2, &raw); err != nil {
+		return err
+	}
+
+	var pair Pair[A, B]
+
+	if err := unmarshalElements(raw, &pair.First, &pair.Second); err != nil {
+		return err
+	}
+
+	*p = pair
+
+	return nil
+}
+
+// String is the stringer implementation.
+func (t Triple[A, B, C]) String() string {
+	return fmt.Sprintf("(%v, %v, %v)", t.First, t.Second, t.Third)
+}
+
+// Values returns all values.
+func (t Triple[A, B, C]) Values() (A, B, C) {
+	return t.First, t.Second, t.Third
+}
+
+// Equal checks if both triples hold deeply equal values, like
+// reflect.DeepEqual. For comparable values, == is equivalent, and faster.
+func (t Triple[A, B, C]) Equal(other Triple[A, B, C]) bool {
+	return reflect.DeepEqual(t, other)
+}
+
+// MarshalJSON implements the json.Marshaler interface. The triple is encoded
+// as an array of three elements, e.g.: ["a", 1, true].
+func (t Triple[A, B, C]) MarshalJSON() ([]byte, error) {
+	return json.Marshal([]any{t.First, t.Second, t.Third})
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface, the inverse of
+// MarshalJSON.
+func (t *Triple[A, B, C]) UnmarshalJSON(data []byte) error {
+	var raw []json.RawMessage
+
+	if err := unmarshalArray(data, 3, &raw); err != nil {
+		return err
+	}
+
+	var triple Triple[A, B, C]
+
+	if err := unmarshalElements(raw, &triple.First, &triple.Second, &triple.Third); err != nil {
+		return err
+	}
+
+	*t = triple
+
+	return nil
+}
+
+//////
+// Factory.
+//////
+
+// NewPair creates a new Pair.
+func NewPair[A, B any](first A, second B) Pair[A, B] {
+	return Pair[A, B]{First: first, Second: second}
+}
+
+// NewTriple creates a new Triple.
+func NewTriple[A, B, C any](first A, second B, third C) Triple[A, B, C] {
+	return Triple[A, B, C]{First: first, Second: second, Third: third}
+}
+
+//////
+// Exported Functionalities.
+//////
+
+// unmarshalArray unmarshals a JSON array with exactly n elements.
+func unmarshalArray(data []byte, n int, raw *[]json.RawMessage) error {
+	if err := json.Unmarshal(data, raw); err != nil {
+		return err
+	}
+
+	if len(*raw) != n {
+		return fmt.Errorf("tuple: expected an array of %d elements, got %d", n, len(*raw))
+	}
+
+	return nil
+}
+
+// unmarshalElements unmarshals each raw element into the destination at the
+// same index.
+func unmarshalElements(raw []json.RawMessage, dst ...any) error {
+	for i, d := range dst {
+		if err := json.Unmarshal(raw[i], d); err != nil {
+			return fmt.Errorf("tuple: element %d: %w", i, err)
+		}
+	}
+
+	return nil
+}
+
+// compare returns -1, 0, or +1 depending on whether a is less than, equal
+// to, or greater than b.
+func compare[T constraints.Ordered](a, b T) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// ComparePair compares pairs lexicographically, returning -1, 0, or +1. It's
+// meant for sorting.
+func ComparePair[A, B constraints.Ordered](p, q Pair[A, B]) int {
+	if c := compare(p.First, q.First); c != 0 {
+		return c
+	}
+
+	return compare(p.Second, q.Second)
+}
+
+// CompareTriple compares triples lexicographically, returning -1, 0, or +1.
+// It's meant for sorting.
+func CompareTriple[A, B, C constraints.Ordered](t, u Triple[A, B, C]) int {
+	if c := compare(t.First, u.First); c != 0 {
+		return c
+	}
+
+	if c := compare(t.Second, u.Second); c != 0 {
+		return c
+	}
+
+	return compare(t.Third, u.Third)
+}
@@ -0,0 +1,84 @@
+package tuple
+
+import (
+	"encoding/json"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPair(t *testing.T) {
+	p := NewPair("a", 1)
+
+	first, second := p.Values()
+
+	assert.Equal(t, "a", first)
+	assert.Equal(t, 1, second)
+	assert.Equal(t, "(a, 1)", p.String())
+	assert.Equal(t, NewPair(1, "a"), p.Swap())
+	assert.True(t, p == NewPair("a", 1))
+
+	counts := map[Pair[string, int]]int{p: 1}
+
+	assert.Equal(t, 1, counts[NewPair("a", 1)])
+}
+
+func TestPairEqual(t *testing.T) {
+	p := NewPair("a", []int{1, 2})
+
+	assert.True(t, p.Equal(NewPair("a", []int{1, 2})))
+	assert.False(t, p.Equal(NewPair("a", []int{1})))
+}
+
+func TestPairJSON(t *testing.T) {
+	b, err := json.Marshal(NewPair("a", 1))
+	assert.NoError(t, err)
+	assert.Equal(t, `["a",1]`, string(b))
+
+	var p Pair[string, int]
+
+	assert.NoError(t, json.Unmarshal([]byte(`["b", 2]`), &p))
+	assert.Equal(t, NewPair("b", 2), p)
+
+	assert.EqualError(t, json.Unmarshal([]byte(`["b"]`), &p), "tuple: expected an array of 2 elements, got 1")
+	assert.Error(t, json.Unmarshal([]byte(`[1, 2]`), &p))
+	assert.Error(t, json.Unmarshal([]byte(`{}`), &p))
+	assert.Equal(t, NewPair("b", 2), p)
+}
+
+func TestTriple(t *testing.T) {
+	tr := NewTriple("a", 1, true)
+
+	a, b, c := tr.Values()
+
+	assert.Equal(t, "a", a)
+	assert.Equal(t, 1, b)
+	assert.True(t, c)
+	assert.Equal(t, "(a, 1, true)", tr.String())
+	assert.True(t, tr.Equal(NewTriple("a", 1, true)))
+	assert.False(t, tr.Equal(NewTriple("a", 1, false)))
+
+	data, err := json.Marshal(tr)
+	assert.NoError(t, err)
+	assert.Equal(t, `["a",1,true]`, string(data))
+
+	var decoded Triple[string, int, bool]
+
+	assert.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, tr, decoded)
+	assert.Error(t, json.Unmarshal([]byte(`["a", 1]`), &decoded))
+}
+
+func TestCompare(t *testing.T) {
+	pairs := []Pair[string, int]{NewPair("b", 1), NewPair("a", 2), NewPair("a", 1)}
+
+	sort.Slice(pairs, func(i, j int) bool { return ComparePair(pairs[i], pairs[j]) < 0 })
+
+	assert.Equal(t, []Pair[string, int]{NewPair("a", 1), NewPair("a", 2), NewPair("b", 1)}, pairs)
+	assert.Equal(t, 0, ComparePair(NewPair("a", 1), NewPair("a", 1)))
+
+	assert.Equal(t, -1, CompareTriple(NewTriple(1, 2, 3), NewTriple(1, 2, 4)))
+	assert.Equal(t, 1, CompareTriple(NewTriple(1, 3, 0), NewTriple(1, 2, 4)))
+	assert.Equal(t, 0, CompareTriple(NewTriple(1, 2, 3), NewTriple(1, 2, 3)))
+}
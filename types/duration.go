@@ -0,0 +1,120 @@
+// Package types provides Duration, and Time, wrappers of the time package
+// with human-friendly JSON, text, and SQL representations.
+package types
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+//////
+// Const, vars, and types.
+//////
+
+// Duration is a time.Duration encoded as a string, e.g.: "1h30m0s", instead of
+// nanoseconds. It decodes strings accepted by time.ParseDuration, and numbers,
+// as nanoseconds, for compatibility with time.Duration.
+type Duration time.Duration
+
+//////
+// Methods.
+//////
+
+// Std returns the time.Duration.
+func (d Duration) Std() time.Duration {
+	return time.Duration(d)
+}
+
+// String is the stringer implementation, e.g.: "1h30m0s".
+func (d Duration) String() string {
+	return time.Duration(d).String()
+}
+
+// MarshalText implements the encoding.TextMarshaler interface, also used by
+// YAML encoders, and the text marshaling of the collections.
+func (d Duration) MarshalText() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface, accepting
+// any string accepted by time.ParseDuration, e.g.: "1h30m", or "250ms".
+func (d *Duration) UnmarshalText(text []byte) error {
+	parsed, err := ParseDuration(string(text))
+	if err != nil {
+		return err
+	}
+
+	*d = parsed
+
+	return nil
+}
+
+// MarshalJSON implements the json.Marshaler interface, encoding the duration
+// as a string.
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.String())
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface. It accepts strings,
+// see UnmarshalText, and numbers, as nanoseconds.
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	if !bytes.HasPrefix(bytes.TrimSpace(data), []byte(`"`)) {
+		var nanoseconds int64
+
+		if err := json.Unmarshal(data, &nanoseconds); err != nil {
+			return err
+		}
+
+		*d = Duration(nanoseconds)
+
+		return nil
+	}
+
+	var s string
+
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+
+	return d.UnmarshalText([]byte(s))
+}
+
+// Value implements the driver.Valuer interface, storing the duration as a
+// string.
+func (d Duration) Value() (driver.Value, error) {
+	return d.String(), nil
+}
+
+// Scan implements the sql.Scanner interface. It accepts strings, as []byte,
+// or string, and integers, as nanoseconds.
+func (d *Duration) Scan(src any) error {
+	switch v := src.(type) {
+	case []byte:
+		return d.UnmarshalText(v)
+	case string:
+		return d.UnmarshalText([]byte(v))
+	case int64:
+		*d = Duration(v)
+
+		return nil
+	default:
+		return fmt.Errorf("cannot scan %T, expected []byte, string, or int64", src)
+	}
+}
+
+//////
+// Factory.
+//////
+
+// ParseDuration parses a duration, see time.ParseDuration.
+func ParseDuration(s string) (Duration, error) {
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, err
+	}
+
+	return Duration(d), nil
+}
@@ -0,0 +1,55 @@
+package types
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDuration(t *testing.T) {
+	var d Duration
+
+	assert.NoError(t, json.Unmarshal([]byte(`"1h30m"`), &d))
+	assert.Equal(t, 90*time.Minute, d.Std())
+
+	data, err := json.Marshal(d)
+
+	assert.NoError(t, err)
+	assert.Equal(t, `"1h30m0s"`, string(data))
+
+	// Numbers are nanoseconds, like time.Duration.
+	assert.NoError(t, json.Unmarshal([]byte(`1000000`), &d))
+	assert.Equal(t, time.Millisecond, d.Std())
+
+	assert.Error(t, json.Unmarshal([]byte(`"1 hour"`), &d))
+	assert.Error(t, json.Unmarshal([]byte(`true`), &d))
+
+	assert.NoError(t, d.UnmarshalText([]byte("250ms")))
+	assert.Equal(t, "250ms", d.String())
+}
+
+func TestDurationSQL(t *testing.T) {
+	var (
+		_ driver.Valuer = Duration(0)
+		_ sql.Scanner   = new(Duration)
+	)
+
+	value, err := Duration(time.Second).Value()
+
+	assert.NoError(t, err)
+	assert.Equal(t, "1s", value)
+
+	var d Duration
+
+	assert.NoError(t, d.Scan([]byte("2s")))
+	assert.Equal(t, 2*time.Second, d.Std())
+
+	assert.NoError(t, d.Scan(int64(time.Minute)))
+	assert.Equal(t, time.Minute, d.Std())
+
+	assert.Error(t, d.Scan(1.5))
+}
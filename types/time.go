@@ -0,0 +1,175 @@
+package types
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+//////
+// Const, vars, and types.
+//////
+
+// layouts are the layouts accepted when parsing a Time, in order. Layouts
+// without a zone are in UTC.
+var layouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02 15:04:05.999999999Z07:00",
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02",
+	time.RFC1123Z,
+	time.RFC1123,
+	time.RFC850,
+	time.ANSIC,
+}
+
+// null is the JSON representation of the zero Time.
+var null = []byte("null")
+
+// Time is a time.Time which accepts multiple layouts, e.g.: RFC 3339, with,
+// or without zone, a space instead of the "T", dates, RFC 1123, and Unix
+// seconds in JSON, and always outputs RFC 3339, with nanoseconds if any. The
+// zero Time is encoded as null in JSON.
+type Time struct {
+	time.Time
+}
+
+//////
+// Methods.
+//////
+
+// Std returns the time.Time.
+func (t Time) Std() time.Time {
+	return t.Time
+}
+
+// String is the stringer implementation, returning RFC 3339.
+func (t Time) String() string {
+	return t.Format(time.RFC3339Nano)
+}
+
+// MarshalText implements the encoding.TextMarshaler interface, also used by
+// YAML encoders, and the text marshaling of the collections.
+func (t Time) MarshalText() ([]byte, error) {
+	return []byte(t.String()), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface, accepting
+// any of the layouts, see ParseTime.
+func (t *Time) UnmarshalText(text []byte) error {
+	parsed, err := ParseTime(string(text))
+	if err != nil {
+		return err
+	}
+
+	*t = parsed
+
+	return nil
+}
+
+// MarshalJSON implements the json.Marshaler interface, encoding the time as a
+// RFC 3339 string, or null if zero.
+func (t Time) MarshalJSON() ([]byte, error) {
+	if t.IsZero() {
+		return null, nil
+	}
+
+	return json.Marshal(t.String())
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface. It accepts
+// strings, see UnmarshalText, numbers, as Unix seconds, and null, as the zero
+// Time.
+func (t *Time) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+
+	if bytes.Equal(data, null) {
+		*t = Time{}
+
+		return nil
+	}
+
+	if !bytes.HasPrefix(data, []byte(`"`)) {
+		seconds, err := strconv.ParseFloat(string(data), 64)
+		if err != nil {
+			return fmt.Errorf("cannot parse %s as a time: %w", data, err)
+		}
+
+		*t = Unix(seconds)
+
+		return nil
+	}
+
+	var s string
+
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+
+	return t.UnmarshalText([]byte(s))
+}
+
+// Value implements the driver.Valuer interface, storing the time.Time, or
+// NULL if zero.
+func (t Time) Value() (driver.Value, error) {
+	if t.IsZero() {
+		return nil, nil
+	}
+
+	return t.Time, nil
+}
+
+// Scan implements the sql.Scanner interface. It accepts time.Time, strings,
+// as []byte, or string, and NULL, as the zero Time.
+func (t *Time) Scan(src any) error {
+	switch v := src.(type) {
+	case nil:
+		*t = Time{}
+
+		return nil
+	case time.Time:
+		*t = Time{v}
+
+		return nil
+	case []byte:
+		return t.UnmarshalText(v)
+	case string:
+		return t.UnmarshalText([]byte(v))
+	default:
+		return fmt.Errorf("cannot scan %T, expected time.Time, []byte, or string", src)
+	}
+}
+
+//////
+// Factory.
+//////
+
+// ParseTime parses a time with the first matching layout: RFC 3339, RFC 3339
+// without zone, or with a space instead of the "T", a date, RFC 1123, RFC
+// 850, or ANSI C. Times without zone are in UTC.
+func ParseTime(s string) (Time, error) {
+	for _, layout := range layouts {
+		if parsed, err := time.Parse(layout, s); err == nil {
+			return Time{parsed}, nil
+		}
+	}
+
+	return Time{}, fmt.Errorf("cannot parse %q as a time", s)
+}
+
+// Now returns the current time.
+func Now() Time {
+	return Time{time.Now()}
+}
+
+// Unix returns the time of the Unix timestamp, in seconds, with a fraction,
+// in UTC.
+func Unix(seconds float64) Time {
+	whole := int64(seconds)
+
+	return Time{time.Unix(whole, int64((seconds-float64(whole))*1e9)).UTC()}
+}
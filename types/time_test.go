@@ -0,0 +1,124 @@
+package types
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/thalesfsp/go-common-types/safeorderedmap"
+)
+
+func TestTime(t *testing.T) {
+	want := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	for _, s := range []string{
+		"2024-01-02T03:04:05Z",
+		"2024-01-02T03:04:05",
+		"2024-01-02 03:04:05",
+		"2024-01-02 03:04:05Z",
+		"2024-01-02T05:04:05+02:00",
+		"Tue, 02 Jan 2024 03:04:05 UTC",
+	} {
+		parsed, err := ParseTime(s)
+
+		assert.NoError(t, err, s)
+		assert.True(t, want.Equal(parsed.Time), s)
+	}
+
+	date, err := ParseTime("2024-01-02")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "2024-01-02T00:00:00Z", date.String())
+
+	_, err = ParseTime("yesterday")
+
+	assert.Error(t, err)
+}
+
+func TestTimeJSON(t *testing.T) {
+	var ts Time
+
+	assert.NoError(t, json.Unmarshal([]byte(`"2024-01-02 03:04:05.5"`), &ts))
+
+	data, err := json.Marshal(ts)
+
+	assert.NoError(t, err)
+	assert.Equal(t, `"2024-01-02T03:04:05.5Z"`, string(data))
+
+	assert.NoError(t, json.Unmarshal([]byte(`1704164645.25`), &ts))
+	assert.Equal(t, "2024-01-02T03:04:05.25Z", ts.String())
+
+	assert.NoError(t, json.Unmarshal([]byte(`null`), &ts))
+	assert.True(t, ts.IsZero())
+
+	data, err = json.Marshal(ts)
+
+	assert.NoError(t, err)
+	assert.Equal(t, `null`, string(data))
+
+	assert.Error(t, json.Unmarshal([]byte(`"soon"`), &ts))
+	assert.Error(t, json.Unmarshal([]byte(`true`), &ts))
+}
+
+func TestTimeSQL(t *testing.T) {
+	var (
+		_ driver.Valuer = Time{}
+		_ sql.Scanner   = &Time{}
+	)
+
+	now := Now()
+
+	value, err := now.Value()
+
+	assert.NoError(t, err)
+	assert.Equal(t, now.Time, value)
+
+	value, err = Time{}.Value()
+
+	assert.NoError(t, err)
+	assert.Nil(t, value)
+
+	var ts Time
+
+	assert.NoError(t, ts.Scan(now.Time))
+	assert.Equal(t, now, ts)
+
+	assert.NoError(t, ts.Scan("2024-01-02"))
+	assert.Equal(t, 2024, ts.Year())
+
+	assert.NoError(t, ts.Scan(nil))
+	assert.True(t, ts.IsZero())
+
+	assert.Error(t, ts.Scan(1))
+}
+
+func TestCollections(t *testing.T) {
+	type config struct {
+		Timeout Duration `json:"timeout"`
+		Since   Time     `json:"since"`
+	}
+
+	m := safeorderedmap.New[config]()
+
+	assert.NoError(t, json.Unmarshal([]byte(`{"a":{"timeout":"5s","since":"2024-01-02"}}`), m))
+
+	a, _ := m.Get("a")
+
+	assert.Equal(t, 5*time.Second, a.Timeout.Std())
+
+	timeouts := safeorderedmap.New[Duration]()
+
+	assert.NoError(t, timeouts.UnmarshalText([]byte("read=1s,write=2m")))
+
+	write, _ := timeouts.Get("write")
+
+	assert.Equal(t, 2*time.Minute, write.Std())
+
+	text, err := timeouts.MarshalText()
+
+	assert.NoError(t, err)
+	assert.Equal(t, "read=1s,write=2m0s", string(text))
+}